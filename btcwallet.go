@@ -5,16 +5,23 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	_ "net/http/pprof" // nolint:gosec
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/rpc/legacyrpc"
+	"github.com/btcsuite/btcwallet/rpc/macaroons"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/walletdb"
 	"github.com/lightninglabs/neutrino"
@@ -68,14 +75,25 @@ func walletMain() error {
 	}
 
 	dbDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
+	loaderOpts := []wallet.LoaderOption{
+		wallet.WithScanWorkers(cfg.RescanWorkers),
+	}
+	if cfg.EncryptDB {
+		loaderOpts = append(loaderOpts, wallet.WithEncryptedDB())
+	}
 	loader := wallet.NewLoader(
 		activeNet.Params, dbDir, true, cfg.DBTimeout, 250,
+		loaderOpts...,
 	)
 
 	// Create and start HTTP server to serve wallet client connections.
 	// This will be updated with the wallet and chain server RPC client
 	// created below after each is created.
-	rpcs, legacyRPCServer, err := startRPCServers(loader)
+	var macaroonService *macaroons.Service
+	if !cfg.NoMacaroons {
+		macaroonService = macaroons.NewService("btcwallet")
+	}
+	rpcs, legacyRPCServer, err := startRPCServers(loader, macaroonService)
 	if err != nil {
 		log.Errorf("Unable to create RPC servers: %v", err)
 		return err
@@ -88,18 +106,17 @@ func walletMain() error {
 	}
 
 	loader.RunAfterLoad(func(w *wallet.Wallet) {
-		startWalletRPCServices(w, rpcs, legacyRPCServer)
-	})
-
-	if !cfg.NoInitialLoad {
-		// Load the wallet database.  It must have been created already
-		// or this will return an appropriate error.
-		_, err = loader.OpenExistingWallet([]byte(cfg.WalletPass), true)
+		err := startWalletRPCServices(
+			w, rpcs, macaroonService, legacyRPCServer,
+		)
 		if err != nil {
-			log.Error(err)
-			return err
+			// Shut down rather than serve a wallet whose calls
+			// can't be authorized.
+			log.Errorf("Unable to start wallet RPC services: %v",
+				err)
+			simulateInterrupt()
 		}
-	}
+	})
 
 	// Add interrupt handlers to shutdown the various process components
 	// before exiting.  Interrupt handlers run in LIFO order, so the wallet
@@ -131,6 +148,18 @@ func walletMain() error {
 		}()
 	}
 
+	// The wallet is loaded after the interrupt handlers are added, so a
+	// shutdown requested while loading it runs them.
+	if !cfg.NoInitialLoad {
+		// Load the wallet database.  It must have been created already
+		// or this will return an appropriate error.
+		_, err = loader.OpenExistingWallet([]byte(cfg.WalletPass), true)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
 	<-interruptHandlersDone
 	log.Info("Shutdown complete")
 	return nil
@@ -145,17 +174,34 @@ func walletMain() error {
 // methods.
 func rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server, loader *wallet.Loader) {
 	var certs []byte
-	if !cfg.UseSPV {
+	switch {
+	case len(cfg.Failover) > 0:
+		if slices.Contains(cfg.Failover, "btcd") {
+			certs = readCAFile()
+		}
+
+	case !cfg.UseSPV && !cfg.UseBitcoind && cfg.EsploraURL == "" &&
+		cfg.Electrum == "":
+
 		certs = readCAFile()
 	}
 
 	for {
 		var (
-			chainClient chain.Interface
-			err         error
+			chainClient  chain.Interface
+			bitcoindConn *chain.BitcoindConn
+			err          error
 		)
 
-		if cfg.UseSPV {
+		if len(cfg.Failover) > 0 {
+			chainClient, err = startFailover(certs)
+			if err != nil {
+				log.Errorf("Unable to start any chain backend: %v",
+					err)
+				time.Sleep(failoverReconnectDelay)
+				continue
+			}
+		} else if cfg.UseSPV {
 			var (
 				chainService *neutrino.ChainService
 				spvdb        walletdb.DB
@@ -187,8 +233,32 @@ func rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server, loader *wallet.Load
 			if err != nil {
 				log.Errorf("Couldn't start Neutrino client: %s", err)
 			}
+		} else if cfg.UseBitcoind {
+			bitcoindConn, chainClient, err = startBitcoind()
+			if err != nil {
+				log.Errorf("Unable to open connection to bitcoind: "+
+					"%v", err)
+				time.Sleep(bitcoindReconnectDelay)
+				continue
+			}
+		} else if cfg.EsploraURL != "" {
+			chainClient, err = startEsplora()
+			if err != nil {
+				log.Errorf("Unable to connect to the Esplora API: %v",
+					err)
+				time.Sleep(esploraReconnectDelay)
+				continue
+			}
+		} else if cfg.Electrum != "" {
+			chainClient, err = startElectrum()
+			if err != nil {
+				log.Errorf("Unable to connect to the Electrum "+
+					"server: %v", err)
+				time.Sleep(electrumReconnectDelay)
+				continue
+			}
 		} else {
-			chainClient, err = startChainRPC(certs)
+			chainClient, err = startChainRPC(certs, 0)
 			if err != nil {
 				log.Errorf("Unable to open connection to consensus RPC server: %v", err)
 				continue
@@ -218,6 +288,9 @@ func rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server, loader *wallet.Load
 		})
 
 		chainClient.WaitForShutdown()
+		if bitcoindConn != nil {
+			bitcoindConn.Stop()
+		}
 
 		mu.Lock()
 		associateRPCClient = nil
@@ -265,13 +338,231 @@ func readCAFile() []byte {
 // services.  This function uses the RPC options from the global config and
 // there is no recovery in case the server is not available or if there is an
 // authentication error.  Instead, all requests to the client will simply error.
-func startChainRPC(certs []byte) (*chain.RPCClient, error) {
+// Connecting is retried the given number of times, or forever if zero.
+func startChainRPC(certs []byte, reconnectAttempts int) (*chain.RPCClient,
+	error) {
+
 	log.Infof("Attempting RPC client connection to %v", cfg.RPCConnect)
 	rpcc, err := chain.NewRPCClient(activeNet.Params, cfg.RPCConnect,
-		cfg.BtcdUsername, cfg.BtcdPassword, certs, cfg.DisableClientTLS, 0)
+		cfg.BtcdUsername, cfg.BtcdPassword, certs, cfg.DisableClientTLS,
+		reconnectAttempts)
 	if err != nil {
 		return nil, err
 	}
 	err = rpcc.Start()
 	return rpcc, err
 }
+
+// esploraReconnectDelay is the time waited before retrying the Esplora API
+// after starting the client failed.
+const esploraReconnectDelay = 10 * time.Second
+
+// startEsplora creates and starts a client of the Esplora HTTP API configured
+// with the esplora option.
+func startEsplora() (*chain.EsploraClient, error) {
+	log.Infof("Using the Esplora API at %v", cfg.EsploraURL)
+
+	client, err := chain.NewEsploraClient(&chain.EsploraConfig{
+		URL:          cfg.EsploraURL,
+		ChainParams:  activeNet.Params,
+		PollInterval: cfg.EsploraPollInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Start(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// electrumReconnectDelay is the time waited before reconnecting to the
+// Electrum server after a connection attempt failed.
+const electrumReconnectDelay = 10 * time.Second
+
+// startElectrum connects to the Electrum server configured with the electrum
+// options and returns a started client using the connection.
+func startElectrum() (*chain.ElectrumClient, error) {
+	log.Infof("Attempting connection to Electrum server %v", cfg.Electrum)
+
+	electrumCfg := &chain.ElectrumConfig{
+		Server:      cfg.Electrum,
+		ChainParams: activeNet.Params,
+		TLS:         cfg.ElectrumTLS,
+	}
+	if cfg.ElectrumCAFile != "" {
+		pem, err := os.ReadFile(cfg.ElectrumCAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s",
+				cfg.ElectrumCAFile)
+		}
+		electrumCfg.TLSConfig = &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	client, err := chain.NewElectrumClient(electrumCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Start(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+const (
+	// failoverReconnectDelay is the time waited before retrying to start
+	// the chain backends listed by the failover option after none of them
+	// started.
+	failoverReconnectDelay = 10 * time.Second
+
+	// failoverBtcdConnectAttempts is the number of attempts made to
+	// connect to btcd when it is started as a failover backend.
+	failoverBtcdConnectAttempts = 1
+)
+
+// startFailover creates and starts a client failing over between the chain
+// backends listed by the failover option, in order of preference.  Each
+// backend is started with its own options.
+func startFailover(certs []byte) (*chain.FailoverClient, error) {
+	log.Infof("Using chain backends %v with failover",
+		strings.Join(cfg.Failover, ", "))
+
+	backends := make([]chain.FailoverBackend, 0, len(cfg.Failover))
+	for _, name := range cfg.Failover {
+		backend := chain.FailoverBackend{Name: name}
+		switch name {
+		case "btcd":
+			backend.Start = func() (chain.Interface, error) {
+				// Connecting is only retried by the next health
+				// check, so another backend can be used meanwhile.
+				client, err := startChainRPC(
+					certs, failoverBtcdConnectAttempts,
+				)
+				if err != nil {
+					if client != nil {
+						client.Stop()
+					}
+					return nil, err
+				}
+				return client, nil
+			}
+		case "bitcoind":
+			backend.Start = func() (chain.Interface, error) {
+				conn, client, err := startBitcoind()
+				if err != nil {
+					return nil, err
+				}
+
+				// The connection is only used by the client.
+				go func() {
+					client.WaitForShutdown()
+					conn.Stop()
+				}()
+				return client, nil
+			}
+		case "esplora":
+			backend.Start = func() (chain.Interface, error) {
+				client, err := startEsplora()
+				if err != nil {
+					return nil, err
+				}
+				return client, nil
+			}
+		case "electrum":
+			backend.Start = func() (chain.Interface, error) {
+				client, err := startElectrum()
+				if err != nil {
+					return nil, err
+				}
+				return client, nil
+			}
+		}
+		backends = append(backends, backend)
+	}
+
+	client, err := chain.NewFailoverClient(&chain.FailoverConfig{
+		Backends:            backends,
+		ChainParams:         activeNet.Params,
+		HealthCheckInterval: cfg.FailoverHealthCheckInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Start(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+const (
+	// bitcoindReconnectDelay is the time waited before reconnecting to
+	// bitcoind after a connection attempt failed.
+	bitcoindReconnectDelay = 10 * time.Second
+
+	// bitcoindDialTimeout is the timeout for connecting to the peers of a
+	// pruned bitcoind node.
+	bitcoindDialTimeout = 30 * time.Second
+)
+
+// startBitcoind opens a connection to a bitcoind node for blockchain services
+// and returns it together with a started client using the connection.  Block
+// and transaction notifications are received over ZMQ or by RPC polling as
+// configured.  If the node is pruned, blocks it no longer has are fetched
+// from its peers.
+func startBitcoind() (*chain.BitcoindConn, chain.Interface, error) {
+	log.Infof("Attempting RPC client connection to bitcoind at %v",
+		cfg.BitcoindRPCHost)
+
+	bitcoindCfg := &chain.BitcoindConfig{
+		ChainParams: activeNet.Params,
+		Host:        cfg.BitcoindRPCHost,
+		User:        cfg.BitcoindRPCUser,
+		Pass:        cfg.BitcoindRPCPass,
+		CookiePath:  cfg.BitcoindRPCCookie,
+		Dialer: func(addr string) (net.Conn, error) {
+			return net.DialTimeout("tcp", addr, bitcoindDialTimeout)
+		},
+		PrunedModeMaxPeers: cfg.BitcoindPrunedMaxPeers,
+	}
+	if cfg.BitcoindRPCPolling {
+		bitcoindCfg.PollingConfig = &chain.PollingConfig{
+			BlockPollingInterval: cfg.BitcoindBlockPollingInterval,
+			TxPollingInterval:    cfg.BitcoindTxPollingInterval,
+		}
+	} else {
+		bitcoindCfg.ZMQConfig = &chain.ZMQConfig{
+			ZMQBlockHost:           cfg.BitcoindZMQPubRawBlock,
+			ZMQTxHost:              cfg.BitcoindZMQPubRawTx,
+			ZMQSequenceHost:        cfg.BitcoindZMQPubSequence,
+			ZMQReadDeadline:        cfg.BitcoindZMQReadDeadline,
+			MempoolPollingInterval: cfg.BitcoindTxPollingInterval,
+		}
+	}
+
+	conn, err := chain.NewBitcoindConn(bitcoindCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := conn.Start(); err != nil {
+		conn.Stop()
+		return nil, nil, err
+	}
+
+	client := conn.NewBitcoindClient()
+	if err := client.Start(); err != nil {
+		conn.Stop()
+		return nil, nil, err
+	}
+
+	return conn, client, nil
+}
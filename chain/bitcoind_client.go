@@ -72,6 +72,13 @@ type BitcoindClient struct {
 	// NOTE: This requires the watchMtx to be held.
 	mempool map[chainhash.Hash]struct{}
 
+	// unminedInputs keeps track of the inputs of the relevant transactions
+	// in the mempool that have yet to be confirmed. It is used to find out
+	// whether such a transaction was replaced once it leaves the mempool.
+	//
+	// NOTE: This requires the watchMtx to be held.
+	unminedInputs map[chainhash.Hash][]wire.OutPoint
+
 	// expiredMempool keeps track of a set of confirmed transactions along
 	// with the height at which they were included in a block. These
 	// transactions will then be removed from the mempool after a period of
@@ -100,6 +107,11 @@ type BitcoindClient struct {
 	// the backing bitcoind connection, either via ZMQ or polling RPC.
 	blockNtfns chan *wire.MsgBlock
 
+	// removedTxNtfns is a channel through which the hashes of transactions
+	// removed from the mempool without being included in a block will be
+	// retrieved from the backing bitcoind connection.
+	removedTxNtfns chan chainhash.Hash
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -692,6 +704,14 @@ func (c *BitcoindClient) ntfnHandler() {
 			c.bestBlockMtx.RLock()
 			bestBlock := c.bestBlock
 			c.bestBlockMtx.RUnlock()
+
+			// A block missed by the ZMQ subscriptions may be
+			// delivered again when the connection resyncs, in
+			// which case there's nothing left to do.
+			if newBlock.BlockHash() == bestBlock.Hash {
+				continue
+			}
+
 			if newBlock.Header.PrevBlock == bestBlock.Hash {
 				newBlockHeight := bestBlock.Height + 1
 				_ = c.filterBlock(newBlock, newBlockHeight, true)
@@ -714,6 +734,10 @@ func (c *BitcoindClient) ntfnHandler() {
 				log.Errorf("Unable to process chain reorg: %v",
 					err)
 			}
+
+		case hash := <-c.removedTxNtfns:
+			c.onTxRemoved(hash)
+
 		case <-c.quit:
 			return
 		}
@@ -825,6 +849,48 @@ func (c *BitcoindClient) onRelevantTx(tx *wtxmgr.TxRecord,
 	}
 }
 
+// onTxRemoved is executed whenever a transaction leaves the mempool without
+// being included in a block. If the transaction is relevant to us and still
+// unconfirmed, we stop tracking it and queue either a TxReplaced notification,
+// if one of its inputs is now spent by another transaction in the mempool, or
+// a TxEvicted notification otherwise.
+func (c *BitcoindClient) onTxRemoved(hash chainhash.Hash) {
+	c.watchMtx.Lock()
+	inputs, ok := c.unminedInputs[hash]
+	if ok {
+		delete(c.unminedInputs, hash)
+		delete(c.mempool, hash)
+	}
+	c.watchMtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	var ntfn interface{} = TxEvicted{Hash: hash}
+	for _, op := range inputs {
+		spender, found := c.chainConn.events.LookupInputSpend(op)
+		if found && spender != hash {
+			ntfn = TxReplaced{Old: hash, New: spender}
+			break
+		}
+	}
+
+	switch n := ntfn.(type) {
+	case TxReplaced:
+		log.Debugf("Relevant transaction %v replaced in the mempool "+
+			"by %v", hash, n.New)
+	default:
+		log.Debugf("Relevant transaction %v evicted from the mempool",
+			hash)
+	}
+
+	select {
+	case c.notificationQueue.ChanIn() <- ntfn:
+	case <-c.quit:
+	}
+}
+
 // onRescanProgress is a callback that's executed whenever a rescan is in
 // progress. This will queue a RescanProgress notification to the caller with
 // the current rescan progress details.
@@ -1283,6 +1349,9 @@ func (c *BitcoindClient) filterBlock(block *wire.MsgBlock, height int32,
 	// transactions and deleting any in the mempool that were confirmed
 	// over 288 blocks ago.
 	c.watchMtx.Lock()
+	for txHash := range confirmedTxs {
+		delete(c.unminedInputs, txHash)
+	}
 	c.expiredMempool[height] = confirmedTxs
 	if oldBlock, ok := c.expiredMempool[height-288]; ok {
 		for txHash := range oldBlock {
@@ -1414,6 +1483,13 @@ func (c *BitcoindClient) filterTx(txDetails *btcutil.Tx,
 	// FilteredBlockConnected once it confirms.
 	if blockDetails == nil {
 		c.mempool[*txDetails.Hash()] = struct{}{}
+
+		txIns := txDetails.MsgTx().TxIn
+		inputs := make([]wire.OutPoint, 0, len(txIns))
+		for _, txIn := range txIns {
+			inputs = append(inputs, txIn.PreviousOutPoint)
+		}
+		c.unminedInputs[*txDetails.Hash()] = inputs
 	}
 
 	c.onRelevantTx(rec, blockDetails)
@@ -0,0 +1,110 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBitcoindEvents is a BitcoindEvents serving the mempool spends of a
+// fixed set of outpoints.
+type fakeBitcoindEvents struct {
+	spends map[wire.OutPoint]chainhash.Hash
+}
+
+func (e *fakeBitcoindEvents) TxNotifications() <-chan *wire.MsgTx {
+	return nil
+}
+
+func (e *fakeBitcoindEvents) BlockNotifications() <-chan *wire.MsgBlock {
+	return nil
+}
+
+func (e *fakeBitcoindEvents) RemovedTxNotifications() <-chan chainhash.Hash {
+	return nil
+}
+
+func (e *fakeBitcoindEvents) LookupInputSpend(
+	op wire.OutPoint) (chainhash.Hash, bool) {
+
+	hash, ok := e.spends[op]
+	return hash, ok
+}
+
+func (e *fakeBitcoindEvents) Start() error {
+	return nil
+}
+
+func (e *fakeBitcoindEvents) Stop() error {
+	return nil
+}
+
+// TestBitcoindClientTxRemoved tests that the removal of a relevant unmined
+// transaction from the mempool is notified as a replacement if one of its
+// inputs is spent by another transaction, and as an eviction otherwise.
+func TestBitcoindClientTxRemoved(t *testing.T) {
+	t.Parallel()
+
+	var (
+		replaced    = chainhash.Hash{1}
+		evicted     = chainhash.Hash{2}
+		irrelevant  = chainhash.Hash{3}
+		replacement = chainhash.Hash{4}
+		spentOp     = wire.OutPoint{Hash: chainhash.Hash{5}}
+		unspentOp   = wire.OutPoint{Hash: chainhash.Hash{6}}
+	)
+
+	events := &fakeBitcoindEvents{
+		spends: map[wire.OutPoint]chainhash.Hash{
+			spentOp: replacement,
+		},
+	}
+	c := &BitcoindClient{
+		chainConn: &BitcoindConn{events: events},
+		mempool: map[chainhash.Hash]struct{}{
+			replaced: {},
+			evicted:  {},
+		},
+		unminedInputs: map[chainhash.Hash][]wire.OutPoint{
+			replaced: {unspentOp, spentOp},
+			evicted:  {unspentOp},
+		},
+		notificationQueue: NewConcurrentQueue(10),
+		quit:              make(chan struct{}),
+	}
+	c.notificationQueue.Start()
+	t.Cleanup(c.notificationQueue.Stop)
+
+	requireNtfn := func(expected interface{}) {
+		t.Helper()
+
+		select {
+		case ntfn := <-c.notificationQueue.ChanOut():
+			require.Equal(t, expected, ntfn)
+		case <-time.After(time.Second):
+			t.Fatalf("expected notification %v", expected)
+		}
+	}
+
+	c.onTxRemoved(replaced)
+	requireNtfn(TxReplaced{Old: replaced, New: replacement})
+
+	// Transactions we don't track aren't notified.
+	c.onTxRemoved(irrelevant)
+	c.onTxRemoved(evicted)
+	requireNtfn(TxEvicted{Hash: evicted})
+
+	require.Empty(t, c.mempool)
+	require.Empty(t, c.unminedInputs)
+
+	// A transaction removed again is only notified once.
+	c.onTxRemoved(evicted)
+	select {
+	case ntfn := <-c.notificationQueue.ChanOut():
+		t.Fatalf("unexpected notification %v", ntfn)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/netparams"
 	"github.com/lightningnetwork/lnd/ticker"
 )
 
@@ -26,6 +27,10 @@ const (
 	// notifications from bitcoind through ZMQ.
 	rawTxZMQCommand = "rawtx"
 
+	// sequenceZMQCommand is the command used to receive the ordered block
+	// and mempool events from bitcoind through ZMQ.
+	sequenceZMQCommand = "sequence"
+
 	// maxRawBlockSize is the maximum size in bytes for a raw block received
 	// from bitcoind through ZMQ.
 	maxRawBlockSize = 4e6
@@ -38,6 +43,11 @@ const (
 	// bitcoind through ZMQ.
 	seqNumLen = 4
 
+	// sequenceMsgLen is the maximum size in bytes for a message of the
+	// sequence topic received from bitcoind through ZMQ. It consists of a
+	// hash, a label and, for mempool events, the mempool sequence number.
+	sequenceMsgLen = chainhash.HashSize + 1 + 8
+
 	// errBlockPrunedStr is the error message returned by bitcoind upon
 	// calling GetBlock on a pruned block.
 	errBlockPrunedStr = "Block not available (pruned data)"
@@ -73,6 +83,11 @@ type BitcoindConfig struct {
 	// server.
 	Pass string
 
+	// CookiePath is the path to bitcoind's RPC cookie file.  If set, the
+	// credentials are read from the cookie file, which is re-read when it
+	// changes, and User and Pass are ignored.
+	CookiePath string
+
 	// ZMQConfig holds the configuration settings required for setting up
 	// zmq connections to bitcoind.
 	ZMQConfig *ZMQConfig
@@ -141,6 +156,7 @@ func NewBitcoindConn(cfg *BitcoindConfig) (*BitcoindConn, error) {
 		Host:                 cfg.Host,
 		User:                 cfg.User,
 		Pass:                 cfg.Pass,
+		CookiePath:           cfg.CookiePath,
 		DisableAutoReconnect: false,
 		DisableConnectOnNew:  true,
 		DisableTLS:           true,
@@ -230,9 +246,10 @@ func (c *BitcoindConn) Start() error {
 		}
 	}
 
-	c.wg.Add(2)
+	c.wg.Add(3)
 	go c.sendBlockToClients()
 	go c.sendTxToClients()
+	go c.sendRemovedTxToClients()
 
 	return c.events.Start()
 }
@@ -327,6 +344,38 @@ func (c *BitcoindConn) sendTxToClients() {
 	}
 }
 
+// sendRemovedTxToClients is used to notify all rescan clients of a
+// transaction removed from the mempool without being included in a block. It
+// MUST be run as a goroutine.
+func (c *BitcoindConn) sendRemovedTxToClients() {
+	defer c.wg.Done()
+
+	sendRemovedTx := func(hash chainhash.Hash) {
+		c.rescanClientsMtx.Lock()
+		defer c.rescanClientsMtx.Unlock()
+
+		for _, client := range c.rescanClients {
+			select {
+			case client.removedTxNtfns <- hash:
+			case <-client.quit:
+			case <-c.quit:
+				return
+			}
+		}
+	}
+
+	var hash chainhash.Hash
+	for {
+		select {
+		case hash = <-c.events.RemovedTxNotifications():
+		case <-c.quit:
+			return
+		}
+
+		sendRemovedTx(hash)
+	}
+}
+
 // getBlockHashDuringStartup is used to call the getblockhash RPC during
 // startup. It catches the case where bitcoind is still in the process of
 // loading blocks, which returns the following error,
@@ -385,6 +434,8 @@ func getCurrentNet(client *rpcclient.Client) (wire.BitcoinNet, error) {
 	switch *hash {
 	case *chaincfg.TestNet3Params.GenesisHash:
 		return chaincfg.TestNet3Params.Net, nil
+	case *netparams.TestNet4Params.GenesisHash:
+		return netparams.TestNet4Params.Net, nil
 	case *chaincfg.RegressionNetParams.GenesisHash:
 		return chaincfg.RegressionNetParams.Net, nil
 	case *chaincfg.SigNetParams.GenesisHash:
@@ -415,8 +466,10 @@ func (c *BitcoindConn) NewBitcoindClient() *BitcoindClient {
 		notificationQueue: NewConcurrentQueue(20),
 		txNtfns:           make(chan *wire.MsgTx, 1000),
 		blockNtfns:        make(chan *wire.MsgBlock, 100),
+		removedTxNtfns:    make(chan chainhash.Hash, 1000),
 
 		mempool:        make(map[chainhash.Hash]struct{}),
+		unminedInputs:  make(map[chainhash.Hash][]wire.OutPoint),
 		expiredMempool: make(map[int32]map[chainhash.Hash]struct{}),
 	}
 }
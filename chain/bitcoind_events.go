@@ -20,6 +20,12 @@ type BitcoindEvents interface {
 	// blocks.
 	BlockNotifications() <-chan *wire.MsgBlock
 
+	// RemovedTxNotifications will return a channel which will deliver the
+	// hashes of transactions removed from the mempool without being
+	// included in a block, because they were evicted, expired, replaced
+	// or conflicted with a block.
+	RemovedTxNotifications() <-chan chainhash.Hash
+
 	// LookupInputSpend will return the transaction found in mempool that
 	// spends the given input.
 	LookupInputSpend(op wire.OutPoint) (chainhash.Hash, bool)
@@ -66,6 +66,11 @@ type bitcoindRPCPollingEvents struct {
 	// txNtfns is a channel to which any new transactions will be sent.
 	txNtfns chan *wire.MsgTx
 
+	// removedTxNtfns is the channel returned by RemovedTxNotifications.
+	// Nothing is sent to it, as polling can't tell transactions removed
+	// from the mempool apart from the ones included in a block.
+	removedTxNtfns chan chainhash.Hash
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -112,12 +117,13 @@ func newBitcoindRPCPollingEvents(cfg *PollingConfig, client *rpcclient.Client,
 	}
 
 	return &bitcoindRPCPollingEvents{
-		cfg:        cfg,
-		client:     client,
-		txNtfns:    make(chan *wire.MsgTx),
-		blockNtfns: make(chan *wire.MsgBlock),
-		mempool:    newMempool(mCfg),
-		quit:       make(chan struct{}),
+		cfg:            cfg,
+		client:         client,
+		txNtfns:        make(chan *wire.MsgTx),
+		blockNtfns:     make(chan *wire.MsgBlock),
+		removedTxNtfns: make(chan chainhash.Hash),
+		mempool:        newMempool(mCfg),
+		quit:           make(chan struct{}),
 	}
 }
 
@@ -158,6 +164,14 @@ func (b *bitcoindRPCPollingEvents) BlockNotifications() <-chan *wire.MsgBlock {
 	return b.blockNtfns
 }
 
+// RemovedTxNotifications returns a channel which will deliver the hashes of
+// transactions removed from the mempool without being included in a block.
+//
+// NOTE: No transactions are delivered when polling.
+func (b *bitcoindRPCPollingEvents) RemovedTxNotifications() <-chan chainhash.Hash {
+	return b.removedTxNtfns
+}
+
 // LookupInputSpend returns the transaction that spends the given outpoint
 // found in the mempool.
 func (b *bitcoindRPCPollingEvents) LookupInputSpend(
@@ -26,6 +26,13 @@ type ZMQConfig struct {
 	// listener.
 	ZMQTxHost string
 
+	// ZMQSequenceHost is the optional IP address and port of the
+	// bitcoind's sequence listener. When set, the ordered block and
+	// mempool events of the sequence topic are used to keep the local
+	// mempool up to date and to resync after dropped messages, instead of
+	// polling the mempool.
+	ZMQSequenceHost string
+
 	// ZMQReadDeadline represents the read deadline we'll apply when reading
 	// ZMQ messages from either subscription.
 	ZMQReadDeadline time.Duration
@@ -37,14 +44,12 @@ type ZMQConfig struct {
 	// - max: MempoolPollingInterval * (1 + PollingIntervalJitter)
 	// - min: MempoolPollingInterval * (1 - PollingIntervalJitter)
 	//
-	// TODO(yy): replace this temp config with SEQUENCE check.
+	// NOTE: The mempool isn't polled when ZMQSequenceHost is set.
 	MempoolPollingInterval time.Duration
 
 	// PollingIntervalJitter a factor that's used to simulates jitter by
 	// scaling MempoolPollingInterval with it. This value must be no less
 	// than 0. Default to 0, meaning no jitter will be applied.
-	//
-	// TODO(yy): replace this temp config with SEQUENCE check.
 	PollingIntervalJitter float64
 
 	// RPCBatchSize defines the number of RPC requests to be batches before
@@ -68,16 +73,26 @@ type bitcoindZMQEvents struct {
 	// events.
 	txConn *gozmq.Conn
 
+	// seqConn is the ZMQ connection we'll use to read sequence events. It
+	// is nil if no sequence listener is configured.
+	seqConn *gozmq.Conn
+
 	// blockNtfns is a channel to which any new blocks will be sent.
 	blockNtfns chan *wire.MsgBlock
 
 	// txNtfns is a channel to which any new transactions will be sent.
 	txNtfns chan *wire.MsgTx
 
+	// removedTxNtfns is a channel to which the hashes of transactions
+	// removed from the mempool without being included in a block will be
+	// sent.
+	removedTxNtfns chan chainhash.Hash
+
 	// mempool holds all the transactions that we currently see as being in
 	// the mempool. This is used so that we know which transactions we have
-	// already sent notifications for. This will be nil if we are using the
-	// gettxspendingprevout endpoint.
+	// already sent notifications for. This will be empty if we are using
+	// the gettxspendingprevout endpoint and the sequence topic isn't
+	// subscribed to.
 	mempool *mempool
 
 	// client is an rpc client to the bitcoind backend.
@@ -87,6 +102,39 @@ type bitcoindZMQEvents struct {
 	// doesn't need to maintain its own mempool.
 	hasPrevoutRPC bool
 
+	// trackMempool is set when the local mempool is maintained, which is
+	// the case when either the gettxspendingprevout endpoint is missing
+	// or the sequence topic is subscribed to.
+	trackMempool bool
+
+	// seqClient is the rpc client used to resync after messages were
+	// dropped from the ZMQ subscriptions.
+	seqClient sequenceClient
+
+	// resyncReq is signalled to request the sequence event handler to
+	// resync with bitcoind.
+	resyncReq chan struct{}
+
+	// mempoolSeq is the mempool sequence number of bitcoind at the last
+	// resync. Sequence events that are already accounted for by the resync
+	// are skipped.
+	//
+	// NOTE: This must only be used by the sequence event handler.
+	mempoolSeq uint64
+
+	// lastBlock is the hash of the last block sent to blockNtfns. It's
+	// used to skip blocks already sent after a resync, and is only
+	// maintained when the sequence topic is subscribed to.
+	lastBlockMtx sync.Mutex
+	lastBlock    chainhash.Hash
+
+	// removed is the set of transactions removed from the mempool since
+	// the last block was connected. It ensures that a late rawtx message
+	// doesn't add a transaction back to the local mempool after its
+	// removal was seen on the sequence topic.
+	removedMtx sync.Mutex
+	removed    map[chainhash.Hash]struct{}
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -140,12 +188,36 @@ func newBitcoindZMQEvents(cfg *ZMQConfig, client *rpcclient.Client,
 			"events: %w", err)
 	}
 
+	// The sequence topic uses a connection of its own as well, so its
+	// events are received in order regardless of the other ones.
+	var zmqSeqConn *gozmq.Conn
+	if cfg.ZMQSequenceHost != "" {
+		zmqSeqConn, err = gozmq.Subscribe(
+			cfg.ZMQSequenceHost, []string{sequenceZMQCommand},
+			cfg.ZMQReadDeadline,
+		)
+		if err != nil {
+			if err := zmqBlockConn.Close(); err != nil {
+				log.Errorf("could not close zmq block conn: "+
+					"%v", err)
+			}
+			if err := zmqTxConn.Close(); err != nil {
+				log.Errorf("could not close zmq tx conn: %v",
+					err)
+			}
+
+			return nil, fmt.Errorf("unable to subscribe for zmq "+
+				"sequence events: %w", err)
+		}
+	}
+
 	// Create the config for mempool and attach default values if not
 	// configed.
 	mCfg := &mempoolConfig{
 		client:            bClient,
 		getRawTxBatchSize: cfg.RPCBatchSize,
 		batchWaitInterval: cfg.RPCBatchInterval,
+		hasPrevoutRPC:     hasRPC,
 	}
 
 	if cfg.RPCBatchSize == 0 {
@@ -157,26 +229,31 @@ func newBitcoindZMQEvents(cfg *ZMQConfig, client *rpcclient.Client,
 	}
 
 	zmqEvents := &bitcoindZMQEvents{
-		cfg:           cfg,
-		client:        client,
-		blockConn:     zmqBlockConn,
-		txConn:        zmqTxConn,
-		hasPrevoutRPC: hasRPC,
-		blockNtfns:    make(chan *wire.MsgBlock),
-		txNtfns:       make(chan *wire.MsgTx),
-		mempool:       newMempool(mCfg),
-		quit:          make(chan struct{}),
+		cfg:            cfg,
+		client:         client,
+		blockConn:      zmqBlockConn,
+		txConn:         zmqTxConn,
+		seqConn:        zmqSeqConn,
+		hasPrevoutRPC:  hasRPC,
+		trackMempool:   !hasRPC || zmqSeqConn != nil,
+		seqClient:      client,
+		blockNtfns:     make(chan *wire.MsgBlock),
+		txNtfns:        make(chan *wire.MsgTx),
+		removedTxNtfns: make(chan chainhash.Hash),
+		mempool:        newMempool(mCfg),
+		resyncReq:      make(chan struct{}, 1),
+		removed:        make(map[chainhash.Hash]struct{}),
+		quit:           make(chan struct{}),
 	}
 
 	return zmqEvents, nil
-
 }
 
 // Start spins off the bitcoindZMQEvent goroutines.
 func (b *bitcoindZMQEvents) Start() error {
 	// Load the mempool so we don't miss transactions, but only if we need
 	// one.
-	if !b.hasPrevoutRPC {
+	if b.trackMempool {
 		if err := b.mempool.LoadMempool(); err != nil {
 			return err
 		}
@@ -185,7 +262,23 @@ func (b *bitcoindZMQEvents) Start() error {
 	b.wg.Add(3)
 	go b.blockEventHandler()
 	go b.txEventHandler()
-	go b.mempoolPoller()
+
+	// The sequence events keep the mempool up to date once it's loaded,
+	// otherwise we'll have to poll it.
+	if b.seqConn == nil {
+		go b.mempoolPoller()
+		return nil
+	}
+
+	// Blocks missed from now on are resynced starting from the current
+	// best block.
+	bestHash, err := b.seqClient.GetBestBlockHash()
+	if err != nil {
+		return err
+	}
+	b.setLastBlock(*bestHash)
+
+	go b.sequenceEventHandler()
 
 	return nil
 }
@@ -203,6 +296,12 @@ func (b *bitcoindZMQEvents) Stop() error {
 		returnErr = err
 	}
 
+	if b.seqConn != nil {
+		if err := b.seqConn.Close(); err != nil {
+			returnErr = err
+		}
+	}
+
 	close(b.quit)
 	b.wg.Wait()
 	return returnErr
@@ -218,6 +317,15 @@ func (b *bitcoindZMQEvents) BlockNotifications() <-chan *wire.MsgBlock {
 	return b.blockNtfns
 }
 
+// RemovedTxNotifications returns a channel which will deliver the hashes of
+// transactions removed from the mempool without being included in a block.
+//
+// NOTE: Transactions are only delivered when the sequence topic is subscribed
+// to.
+func (b *bitcoindZMQEvents) RemovedTxNotifications() <-chan chainhash.Hash {
+	return b.removedTxNtfns
+}
+
 // LookupInputSpend returns the transaction that spends the given outpoint
 // found in the mempool.
 func (b *bitcoindZMQEvents) LookupInputSpend(
@@ -258,6 +366,7 @@ func (b *bitcoindZMQEvents) blockEventHandler() {
 		command [len(rawBlockZMQCommand)]byte
 		seqNum  [seqNumLen]byte
 		data    = make([]byte, maxRawBlockSize)
+		msgSeq  zmqMsgSeq
 	)
 
 	for {
@@ -303,6 +412,8 @@ func (b *bitcoindZMQEvents) blockEventHandler() {
 		eventType := string(bufs[0])
 		switch eventType {
 		case rawBlockZMQCommand:
+			b.checkMsgSeq(&msgSeq, rawBlockZMQCommand, bufs)
+
 			block := &wire.MsgBlock{}
 			r := bytes.NewReader(bufs[1])
 			if err := block.Deserialize(r); err != nil {
@@ -311,9 +422,7 @@ func (b *bitcoindZMQEvents) blockEventHandler() {
 				continue
 			}
 
-			select {
-			case b.blockNtfns <- block:
-			case <-b.quit:
+			if !b.sendBlock(block) {
 				return
 			}
 
@@ -355,6 +464,7 @@ func (b *bitcoindZMQEvents) txEventHandler() {
 		command [len(rawTxZMQCommand)]byte
 		seqNum  [seqNumLen]byte
 		data    = make([]byte, maxRawTxSize)
+		msgSeq  zmqMsgSeq
 	)
 
 	for {
@@ -400,6 +510,8 @@ func (b *bitcoindZMQEvents) txEventHandler() {
 		eventType := string(bufs[0])
 		switch eventType {
 		case rawTxZMQCommand:
+			b.checkMsgSeq(&msgSeq, rawTxZMQCommand, bufs)
+
 			tx := &wire.MsgTx{}
 			r := bytes.NewReader(bufs[1])
 			if err := tx.Deserialize(r); err != nil {
@@ -408,8 +520,20 @@ func (b *bitcoindZMQEvents) txEventHandler() {
 				continue
 			}
 
+			// With the sequence topic, the transaction may have
+			// already been removed from the mempool, or added to
+			// it during a resync, by the time we receive it.
+			if b.seqConn != nil {
+				txHash := tx.TxHash()
+				if b.isRemoved(txHash) ||
+					b.mempool.ContainsTx(txHash) {
+
+					continue
+				}
+			}
+
 			// Add the tx to mempool if we're using one.
-			if !b.hasPrevoutRPC {
+			if b.trackMempool {
 				b.mempool.Add(tx)
 			}
 
@@ -434,11 +558,14 @@ func (b *bitcoindZMQEvents) txEventHandler() {
 	}
 }
 
+// mempoolPoller polls the mempool of bitcoind to catch transactions missed by
+// the rawtx subscription and remove the ones no longer in the mempool.
+//
 // NOTE: This must be run as a goroutine.
 func (b *bitcoindZMQEvents) mempoolPoller() {
 	defer b.wg.Done()
 
-	if b.hasPrevoutRPC {
+	if !b.trackMempool {
 		// Exit if we're not using a mempool.
 		return
 	}
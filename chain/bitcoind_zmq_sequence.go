@@ -0,0 +1,555 @@
+package chain
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// sequenceBlockConnected is the label of the sequence events of a block
+	// being connected.
+	sequenceBlockConnected = 'C'
+
+	// sequenceBlockDisconnected is the label of the sequence events of a
+	// block being disconnected.
+	sequenceBlockDisconnected = 'D'
+
+	// sequenceTxAdded is the label of the sequence events of a transaction
+	// being added to the mempool.
+	sequenceTxAdded = 'A'
+
+	// sequenceTxRemoved is the label of the sequence events of a
+	// transaction being removed from the mempool for any reason other than
+	// being included in a block.
+	sequenceTxRemoved = 'R'
+
+	// maxResyncBlocks is the maximum number of blocks sent during a resync.
+	// Rescan clients catch up on any earlier blocks themselves.
+	maxResyncBlocks = 10
+)
+
+// sequenceClient is the subset of the bitcoind rpc client used to resync after
+// messages were dropped from the ZMQ subscriptions.
+type sequenceClient interface {
+	// GetBestBlockHash returns the hash of the best block in the longest
+	// block chain.
+	GetBestBlockHash() (*chainhash.Hash, error)
+
+	// GetBlock returns a raw block from the server given its hash.
+	GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error)
+
+	// GetRawTransaction returns a transaction given its hash.
+	GetRawTransaction(txHash *chainhash.Hash) (*btcutil.Tx, error)
+
+	// RawRequest allows the caller to send a raw or custom request to the
+	// server.
+	RawRequest(method string,
+		params []json.RawMessage) (json.RawMessage, error)
+}
+
+// sequenceMsg is an event received from bitcoind's ZMQ sequence topic.
+type sequenceMsg struct {
+	// hash is the hash of the block or transaction of the event.
+	hash chainhash.Hash
+
+	// label identifies the kind of event.
+	label byte
+
+	// mempoolSeq is the mempool sequence number of bitcoind after a
+	// transaction was added or removed. It's zero for block events.
+	mempoolSeq uint64
+}
+
+// parseSequenceMsg parses the body of a message of the sequence topic.
+func parseSequenceMsg(data []byte) (*sequenceMsg, error) {
+	if len(data) < chainhash.HashSize+1 {
+		return nil, fmt.Errorf("sequence message of %d bytes is too "+
+			"short", len(data))
+	}
+
+	// bitcoind serializes the hash in the byte order it's displayed in,
+	// which is the reverse of ours.
+	msg := &sequenceMsg{label: data[chainhash.HashSize]}
+	for i := 0; i < chainhash.HashSize; i++ {
+		msg.hash[i] = data[chainhash.HashSize-1-i]
+	}
+
+	switch msg.label {
+	case sequenceBlockConnected, sequenceBlockDisconnected:
+		if len(data) != chainhash.HashSize+1 {
+			return nil, fmt.Errorf("block sequence message has "+
+				"invalid length %d", len(data))
+		}
+
+	case sequenceTxAdded, sequenceTxRemoved:
+		if len(data) != sequenceMsgLen {
+			return nil, fmt.Errorf("mempool sequence message has "+
+				"invalid length %d", len(data))
+		}
+
+		msg.mempoolSeq = binary.LittleEndian.Uint64(
+			data[chainhash.HashSize+1:],
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown sequence message label %q",
+			msg.label)
+	}
+
+	return msg, nil
+}
+
+// zmqMsgSeq tracks the sequence numbers of the messages received on a ZMQ
+// subscription. bitcoind numbers the messages of each topic consecutively, so
+// a skipped number means messages were dropped, e.g. because the high water
+// mark of the socket was reached, and a lower one that bitcoind restarted.
+type zmqMsgSeq struct {
+	// expected is the sequence number of the next message.
+	expected uint32
+
+	// started is set once a message has been received.
+	started bool
+}
+
+// update records the little-endian sequence number of a received message. It
+// returns false if the message doesn't directly follow the previous one.
+func (s *zmqMsgSeq) update(seqNum []byte) bool {
+	// A malformed sequence number can't tell us anything, so it's ignored.
+	if len(seqNum) != seqNumLen {
+		return true
+	}
+
+	seq := binary.LittleEndian.Uint32(seqNum)
+	inOrder := !s.started || seq == s.expected
+
+	s.started = true
+	s.expected = seq + 1
+
+	return inOrder
+}
+
+// checkMsgSeq checks the sequence number of a message received on the ZMQ
+// subscription of the given topic, and requests a resync if messages were
+// dropped before it.
+func (b *bitcoindZMQEvents) checkMsgSeq(msgSeq *zmqMsgSeq, topic string,
+	bufs [][]byte) {
+
+	if len(bufs) < 3 || msgSeq.update(bufs[2]) {
+		return
+	}
+
+	log.Warnf("Missed ZMQ %v messages from bitcoind", topic)
+
+	b.requestResync()
+}
+
+// requestResync requests the sequence event handler to resync with bitcoind.
+// Without the sequence topic, missed transactions are caught by polling the
+// mempool, and missed blocks by the rescan clients once the next block is
+// received.
+func (b *bitcoindZMQEvents) requestResync() {
+	if b.seqConn == nil {
+		return
+	}
+
+	select {
+	case b.resyncReq <- struct{}{}:
+	default:
+	}
+}
+
+// sequenceEventHandler reads the events of the sequence topic from the ZMQ
+// sequence socket, keeps the local mempool up to date with them, and resyncs
+// with bitcoind whenever messages were dropped from any subscription.
+//
+// NOTE: This must be run as a goroutine.
+func (b *bitcoindZMQEvents) sequenceEventHandler() {
+	defer b.wg.Done()
+
+	// We'll wait to apply the events until the initial mempool load is
+	// done.
+	b.mempool.WaitForInit()
+
+	log.Info("Started listening for bitcoind sequence notifications via "+
+		"ZMQ on", b.seqConn.RemoteAddr())
+
+	var (
+		command [len(sequenceZMQCommand)]byte
+		seqNum  [seqNumLen]byte
+		data    [sequenceMsgLen]byte
+		msgSeq  zmqMsgSeq
+	)
+
+	for {
+		// Before attempting to read from the ZMQ socket, we'll make
+		// sure to check if we've been requested to shut down or to
+		// resync.
+		select {
+		case <-b.quit:
+			return
+
+		case <-b.resyncReq:
+			b.resync()
+
+		default:
+		}
+
+		// Poll an event from the ZMQ socket.
+		var (
+			bufs = [][]byte{command[:], data[:], seqNum[:]}
+			err  error
+		)
+		bufs, err = b.seqConn.Receive(bufs)
+		if err != nil {
+			// EOF should only be returned if the connection was
+			// explicitly closed, so we can exit at this point.
+			if err == io.EOF {
+				return
+			}
+
+			// It's possible that the connection to the socket
+			// continuously times out, so we'll prevent logging this
+			// error to prevent spamming the logs.
+			netErr, ok := err.(net.Error)
+			if ok && netErr.Timeout() {
+				log.Trace("Re-establishing timed out ZMQ " +
+					"sequence connection")
+				continue
+			}
+
+			log.Errorf("Unable to receive ZMQ %v message: %v",
+				sequenceZMQCommand, err)
+			continue
+		}
+
+		eventType := string(bufs[0])
+		if eventType != sequenceZMQCommand {
+			// It's possible that the message wasn't fully read if
+			// bitcoind shuts down, which will produce an unreadable
+			// event type. To prevent from logging it, we'll make
+			// sure it conforms to the ASCII standard.
+			if eventType == "" || !isASCII(eventType) {
+				continue
+			}
+
+			log.Warnf("Received unexpected event type from %v "+
+				"subscription: %v", sequenceZMQCommand,
+				eventType)
+			continue
+		}
+
+		// If messages were dropped, we resync before applying this
+		// one. Its event is then already accounted for, which
+		// handleSequenceMsg can tell.
+		if len(bufs) == 3 && !msgSeq.update(bufs[2]) {
+			log.Warnf("Missed ZMQ %v messages from bitcoind",
+				sequenceZMQCommand)
+
+			b.resync()
+		}
+
+		msg, err := parseSequenceMsg(bufs[1])
+		if err != nil {
+			log.Errorf("Unable to parse sequence message: %v", err)
+			continue
+		}
+
+		b.handleSequenceMsg(msg)
+	}
+}
+
+// handleSequenceMsg applies an event of the sequence topic.
+func (b *bitcoindZMQEvents) handleSequenceMsg(msg *sequenceMsg) {
+	switch msg.label {
+	case sequenceBlockConnected:
+		log.Tracef("Received sequence event of block %v connected",
+			msg.hash)
+
+		// The rawtx messages of the transactions removed before the
+		// block have been received by now, so there's no need to
+		// remember them anymore.
+		b.clearRemoved()
+
+	case sequenceBlockDisconnected:
+		log.Debugf("Received sequence event of block %v disconnected",
+			msg.hash)
+
+		// The block will be sent again if it's connected again.
+		b.lastBlockMtx.Lock()
+		if b.lastBlock == msg.hash {
+			b.lastBlock = chainhash.Hash{}
+		}
+		b.lastBlockMtx.Unlock()
+
+	case sequenceTxAdded:
+		// Skip events already accounted for by the last resync.
+		if msg.mempoolSeq <= b.mempoolSeq {
+			return
+		}
+
+		// The transaction is normally added to the mempool when its
+		// rawtx message is received. But if it was removed before, that
+		// message may have been ignored, so we fetch it instead.
+		if !b.forgetRemoved(msg.hash) || b.mempool.ContainsTx(msg.hash) {
+			return
+		}
+
+		tx, err := b.seqClient.GetRawTransaction(&msg.hash)
+		if err != nil {
+			log.Debugf("Unable to fetch transaction %v added to "+
+				"the mempool: %v", msg.hash, err)
+			return
+		}
+
+		b.mempool.Add(tx.MsgTx())
+		b.sendTx(tx.MsgTx())
+
+	case sequenceTxRemoved:
+		// Skip events already accounted for by the last resync.
+		if msg.mempoolSeq <= b.mempoolSeq {
+			return
+		}
+
+		log.Tracef("Received sequence event of transaction %v "+
+			"removed from the mempool", msg.hash)
+
+		b.markRemoved(msg.hash)
+		if b.mempool.Remove(msg.hash) {
+			b.sendRemovedTx(msg.hash)
+		}
+	}
+}
+
+// resync brings the blocks and the local mempool up to date with bitcoind
+// after messages were dropped from the ZMQ subscriptions. Only the blocks and
+// transactions that were missed are fetched.
+func (b *bitcoindZMQEvents) resync() {
+	log.Infof("Resyncing blocks and mempool with bitcoind")
+
+	if err := b.resyncBlocks(); err != nil {
+		log.Errorf("Unable to resync blocks: %v", err)
+	}
+
+	if err := b.resyncMempool(); err != nil {
+		log.Errorf("Unable to resync mempool: %v", err)
+	}
+}
+
+// resyncBlocks sends the blocks connected since the last block sent, up to
+// maxResyncBlocks of them.
+func (b *bitcoindZMQEvents) resyncBlocks() error {
+	bestHash, err := b.seqClient.GetBestBlockHash()
+	if err != nil {
+		return err
+	}
+
+	lastBlock := b.getLastBlock()
+	if *bestHash == lastBlock {
+		return nil
+	}
+
+	// Walk back from the best block to the last one sent. If it isn't
+	// found, because it was reorged out or is unknown, the rescan clients
+	// find the fork themselves once the blocks are received.
+	var blocks []*wire.MsgBlock
+	hash := bestHash
+	for len(blocks) < maxResyncBlocks {
+		block, err := b.seqClient.GetBlock(hash)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+
+		if block.Header.PrevBlock == lastBlock ||
+			lastBlock == (chainhash.Hash{}) {
+
+			break
+		}
+		hash = &block.Header.PrevBlock
+	}
+
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if !b.sendBlock(blocks[i]) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// resyncMempool reconciles the local mempool with the one of bitcoind. The
+// transactions missed are fetched and sent, and the ones removed meanwhile
+// are reported.
+func (b *bitcoindZMQEvents) resyncMempool() error {
+	txids, mempoolSeq, err := b.getRawMempoolSequence()
+	if err != nil {
+		return err
+	}
+
+	newTxs, removed := b.mempool.reconcile(txids)
+	b.mempoolSeq = mempoolSeq
+
+	for _, tx := range newTxs {
+		b.forgetRemoved(tx.TxHash())
+		if !b.sendTx(tx) {
+			return nil
+		}
+	}
+
+	for _, hash := range removed {
+		b.markRemoved(hash)
+		if !b.sendRemovedTx(hash) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// getRawMempoolSequence returns the transactions in the mempool of bitcoind
+// together with its current mempool sequence number.
+func (b *bitcoindZMQEvents) getRawMempoolSequence() ([]*chainhash.Hash,
+	uint64, error) {
+
+	params := []json.RawMessage{
+		json.RawMessage("false"), json.RawMessage("true"),
+	}
+	resp, err := b.seqClient.RawRequest("getrawmempool", params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result struct {
+		TxIDs           []string `json:"txids"`
+		MempoolSequence uint64   `json:"mempool_sequence"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, 0, err
+	}
+
+	txids := make([]*chainhash.Hash, 0, len(result.TxIDs))
+	for _, txid := range result.TxIDs {
+		hash, err := chainhash.NewHashFromStr(txid)
+		if err != nil {
+			return nil, 0, err
+		}
+		txids = append(txids, hash)
+	}
+
+	return txids, result.MempoolSequence, nil
+}
+
+// sendBlock sends the block to blockNtfns, unless it was the last one sent,
+// and removes its transactions from the local mempool. It returns false if we
+// are shutting down.
+func (b *bitcoindZMQEvents) sendBlock(block *wire.MsgBlock) bool {
+	if b.seqConn != nil {
+		hash := block.BlockHash()
+
+		b.lastBlockMtx.Lock()
+		sent := b.lastBlock == hash
+		b.lastBlock = hash
+		b.lastBlockMtx.Unlock()
+
+		if sent {
+			return true
+		}
+	}
+
+	if b.trackMempool {
+		b.mempool.Clean(block.Transactions)
+	}
+
+	select {
+	case b.blockNtfns <- block:
+		return true
+
+	case <-b.quit:
+		return false
+	}
+}
+
+// sendTx sends the transaction to txNtfns. It returns false if we are shutting
+// down.
+func (b *bitcoindZMQEvents) sendTx(tx *wire.MsgTx) bool {
+	select {
+	case b.txNtfns <- tx:
+		return true
+
+	case <-b.quit:
+		return false
+	}
+}
+
+// sendRemovedTx sends the hash of the removed transaction to removedTxNtfns.
+// It returns false if we are shutting down.
+func (b *bitcoindZMQEvents) sendRemovedTx(hash chainhash.Hash) bool {
+	select {
+	case b.removedTxNtfns <- hash:
+		return true
+
+	case <-b.quit:
+		return false
+	}
+}
+
+// getLastBlock returns the hash of the last block sent.
+func (b *bitcoindZMQEvents) getLastBlock() chainhash.Hash {
+	b.lastBlockMtx.Lock()
+	defer b.lastBlockMtx.Unlock()
+
+	return b.lastBlock
+}
+
+// setLastBlock sets the hash of the last block sent.
+func (b *bitcoindZMQEvents) setLastBlock(hash chainhash.Hash) {
+	b.lastBlockMtx.Lock()
+	defer b.lastBlockMtx.Unlock()
+
+	b.lastBlock = hash
+}
+
+// markRemoved remembers that the transaction was removed from the mempool.
+func (b *bitcoindZMQEvents) markRemoved(hash chainhash.Hash) {
+	b.removedMtx.Lock()
+	defer b.removedMtx.Unlock()
+
+	b.removed[hash] = struct{}{}
+}
+
+// isRemoved returns true if the transaction was removed from the mempool since
+// the last block was connected.
+func (b *bitcoindZMQEvents) isRemoved(hash chainhash.Hash) bool {
+	b.removedMtx.Lock()
+	defer b.removedMtx.Unlock()
+
+	_, ok := b.removed[hash]
+	return ok
+}
+
+// forgetRemoved forgets that the transaction was removed from the mempool. It
+// returns true if it was.
+func (b *bitcoindZMQEvents) forgetRemoved(hash chainhash.Hash) bool {
+	b.removedMtx.Lock()
+	defer b.removedMtx.Unlock()
+
+	_, ok := b.removed[hash]
+	delete(b.removed, hash)
+
+	return ok
+}
+
+// clearRemoved forgets all the transactions removed from the mempool.
+func (b *bitcoindZMQEvents) clearRemoved() {
+	b.removedMtx.Lock()
+	defer b.removedMtx.Unlock()
+
+	b.removed = make(map[chainhash.Hash]struct{})
+}
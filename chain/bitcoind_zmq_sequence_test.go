@@ -0,0 +1,354 @@
+package chain
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/gozmq"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSequenceClient is a sequenceClient serving a fixed chain and mempool.
+type fakeSequenceClient struct {
+	best       chainhash.Hash
+	blocks     map[chainhash.Hash]*wire.MsgBlock
+	txs        map[chainhash.Hash]*wire.MsgTx
+	mempool    []chainhash.Hash
+	mempoolSeq uint64
+}
+
+func (c *fakeSequenceClient) GetBestBlockHash() (*chainhash.Hash, error) {
+	return &c.best, nil
+}
+
+func (c *fakeSequenceClient) GetBlock(
+	hash *chainhash.Hash) (*wire.MsgBlock, error) {
+
+	block, ok := c.blocks[*hash]
+	if !ok {
+		return nil, errors.New("block not found")
+	}
+
+	return block, nil
+}
+
+func (c *fakeSequenceClient) GetRawTransaction(
+	hash *chainhash.Hash) (*btcutil.Tx, error) {
+
+	tx, ok := c.txs[*hash]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+
+	return btcutil.NewTx(tx), nil
+}
+
+func (c *fakeSequenceClient) RawRequest(method string,
+	params []json.RawMessage) (json.RawMessage, error) {
+
+	if method != "getrawmempool" || len(params) != 2 ||
+		string(params[1]) != "true" {
+
+		return nil, errors.New("unexpected request")
+	}
+
+	txids := make([]string, 0, len(c.mempool))
+	for _, hash := range c.mempool {
+		txids = append(txids, hash.String())
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"txids":            txids,
+		"mempool_sequence": c.mempoolSeq,
+	})
+}
+
+// newTestSequenceEvents returns bitcoindZMQEvents subscribed to the sequence
+// topic, using the given clients to resync.
+func newTestSequenceEvents(client sequenceClient,
+	batch batchClient) *bitcoindZMQEvents {
+
+	return &bitcoindZMQEvents{
+		cfg:          &ZMQConfig{},
+		seqConn:      &gozmq.Conn{},
+		trackMempool: true,
+		seqClient:    client,
+		mempool: newMempool(&mempoolConfig{
+			client:            batch,
+			getRawTxBatchSize: 1,
+		}),
+		blockNtfns:     make(chan *wire.MsgBlock, 10),
+		txNtfns:        make(chan *wire.MsgTx, 10),
+		removedTxNtfns: make(chan chainhash.Hash, 10),
+		resyncReq:      make(chan struct{}, 1),
+		removed:        make(map[chainhash.Hash]struct{}),
+		quit:           make(chan struct{}),
+	}
+}
+
+// sequenceMsgBytes serializes a message of the sequence topic like bitcoind.
+func sequenceMsgBytes(hash chainhash.Hash, label byte,
+	mempoolSeq uint64) []byte {
+
+	data := make([]byte, 0, sequenceMsgLen)
+	for i := chainhash.HashSize - 1; i >= 0; i-- {
+		data = append(data, hash[i])
+	}
+	data = append(data, label)
+
+	if label == sequenceTxAdded || label == sequenceTxRemoved {
+		data = binary.LittleEndian.AppendUint64(data, mempoolSeq)
+	}
+
+	return data
+}
+
+// TestParseSequenceMsg checks that the messages of the sequence topic are
+// parsed as serialized by bitcoind.
+func TestParseSequenceMsg(t *testing.T) {
+	t.Parallel()
+
+	hash := chainhash.Hash{1, 2, 3}
+
+	for _, label := range []byte{
+		sequenceBlockConnected, sequenceBlockDisconnected,
+	} {
+		msg, err := parseSequenceMsg(sequenceMsgBytes(hash, label, 0))
+		require.NoError(t, err)
+		require.Equal(t, &sequenceMsg{hash: hash, label: label}, msg)
+	}
+
+	for _, label := range []byte{sequenceTxAdded, sequenceTxRemoved} {
+		msg, err := parseSequenceMsg(sequenceMsgBytes(hash, label, 42))
+		require.NoError(t, err)
+		require.Equal(t, &sequenceMsg{
+			hash: hash, label: label, mempoolSeq: 42,
+		}, msg)
+	}
+
+	// Messages of the wrong length or with an unknown label are
+	// rejected.
+	data := sequenceMsgBytes(hash, sequenceTxAdded, 42)
+	_, err := parseSequenceMsg(data[:chainhash.HashSize+1])
+	require.Error(t, err)
+
+	data = sequenceMsgBytes(hash, sequenceBlockConnected, 0)
+	_, err = parseSequenceMsg(append(data, 0))
+	require.Error(t, err)
+
+	_, err = parseSequenceMsg(sequenceMsgBytes(hash, 'X', 0))
+	require.Error(t, err)
+
+	_, err = parseSequenceMsg(data[:chainhash.HashSize])
+	require.Error(t, err)
+}
+
+// TestZMQMsgSeq checks that gaps in the sequence numbers of ZMQ messages are
+// detected.
+func TestZMQMsgSeq(t *testing.T) {
+	t.Parallel()
+
+	seqNum := func(seq uint32) []byte {
+		return binary.LittleEndian.AppendUint32(nil, seq)
+	}
+
+	var msgSeq zmqMsgSeq
+
+	// Any first message is in order, and so are the following ones until
+	// a sequence number is skipped.
+	require.True(t, msgSeq.update(seqNum(7)))
+	require.True(t, msgSeq.update(seqNum(8)))
+	require.False(t, msgSeq.update(seqNum(10)))
+	require.True(t, msgSeq.update(seqNum(11)))
+
+	// A restart of bitcoind resets the sequence numbers.
+	require.False(t, msgSeq.update(seqNum(0)))
+	require.True(t, msgSeq.update(seqNum(1)))
+
+	// The sequence numbers wrap around.
+	msgSeq = zmqMsgSeq{}
+	require.True(t, msgSeq.update(seqNum(^uint32(0))))
+	require.True(t, msgSeq.update(seqNum(0)))
+
+	// Malformed sequence numbers are ignored.
+	require.True(t, msgSeq.update([]byte{1}))
+}
+
+// TestSequenceEvents checks that the events of the sequence topic keep the
+// mempool up to date and report the transactions removed from it.
+func TestSequenceEvents(t *testing.T) {
+	t.Parallel()
+
+	tx1 := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+		}},
+	}
+	tx2 := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{2}},
+		}},
+	}
+
+	client := &fakeSequenceClient{
+		txs: map[chainhash.Hash]*wire.MsgTx{tx1.TxHash(): tx1},
+	}
+	b := newTestSequenceEvents(client, &mockRPCClient{})
+
+	// The transaction received on the rawtx subscription is removed from
+	// the mempool, which is reported.
+	b.mempool.Add(tx1)
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: tx1.TxHash(), label: sequenceTxRemoved, mempoolSeq: 2,
+	})
+	require.False(t, b.mempool.ContainsTx(tx1.TxHash()))
+	require.Equal(t, tx1.TxHash(), <-b.removedTxNtfns)
+	require.True(t, b.isRemoved(tx1.TxHash()))
+
+	// Transactions we didn't know about aren't reported.
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: tx2.TxHash(), label: sequenceTxRemoved, mempoolSeq: 3,
+	})
+	require.Empty(t, b.removedTxNtfns)
+
+	// When the removed transaction is added back, it's fetched as its
+	// rawtx message may have been ignored.
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: tx1.TxHash(), label: sequenceTxAdded, mempoolSeq: 4,
+	})
+	require.True(t, b.mempool.ContainsTx(tx1.TxHash()))
+	require.Equal(t, tx1.TxHash(), (<-b.txNtfns).TxHash())
+	require.False(t, b.isRemoved(tx1.TxHash()))
+
+	// Otherwise the transactions added are received on the rawtx
+	// subscription.
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: tx2.TxHash(), label: sequenceTxAdded, mempoolSeq: 5,
+	})
+	require.False(t, b.mempool.ContainsTx(tx2.TxHash()))
+	require.Empty(t, b.txNtfns)
+
+	// The removed transactions are forgotten once a block is connected.
+	b.markRemoved(tx2.TxHash())
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: chainhash.Hash{9}, label: sequenceBlockConnected,
+	})
+	require.False(t, b.isRemoved(tx2.TxHash()))
+}
+
+// TestSequenceResync checks that after messages were dropped only the missing
+// blocks and mempool transactions are fetched, and that the events accounted
+// for by the resync are skipped.
+func TestSequenceResync(t *testing.T) {
+	t.Parallel()
+
+	// Create a chain of blocks, where the last one includes a transaction.
+	tx1 := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+		}},
+	}
+	tx2 := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{2}},
+		}},
+	}
+	tx3 := &wire.MsgTx{
+		Version: 1,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{3}},
+		}},
+	}
+
+	client := &fakeSequenceClient{
+		blocks: make(map[chainhash.Hash]*wire.MsgBlock),
+	}
+	var blocks []*wire.MsgBlock
+	for i := 0; i < 4; i++ {
+		block := &wire.MsgBlock{Header: wire.BlockHeader{Nonce: uint32(i)}}
+		if i > 0 {
+			block.Header.PrevBlock = blocks[i-1].BlockHash()
+		}
+		if i == 3 {
+			block.Transactions = []*wire.MsgTx{tx3}
+		}
+		blocks = append(blocks, block)
+		client.blocks[block.BlockHash()] = block
+	}
+	client.best = blocks[3].BlockHash()
+
+	// The mempool now contains tx2 only, while we still know about tx1 and
+	// tx3.
+	client.mempool = []chainhash.Hash{tx2.TxHash()}
+	client.mempoolSeq = 10
+
+	mockRPC := &mockRPCClient{}
+	b := newTestSequenceEvents(client, mockRPC)
+	b.mempool.Add(tx1)
+	b.mempool.Add(tx3)
+	b.setLastBlock(blocks[1].BlockHash())
+
+	tx2Hash := tx2.TxHash()
+	tx2Receiver := make(rpcclient.FutureGetRawTransactionResult)
+	mockRPC.On("GetRawTransactionAsync", &tx2Hash).Return(
+		tx2Receiver).Once()
+	mockRPC.On("Send").Return(nil).Once()
+	b.mempool.cfg.rawTxReceiver = func(txid chainhash.Hash,
+		receiver getRawTxReceiver) *btcutil.Tx {
+
+		require.Equal(t, tx2Hash, txid)
+		return btcutil.NewTx(tx2)
+	}
+
+	b.resync()
+	mockRPC.AssertExpectations(t)
+
+	// The blocks after the last one sent are sent in order, and the
+	// transaction they include is no longer in the mempool.
+	require.Equal(t, blocks[2], <-b.blockNtfns)
+	require.Equal(t, blocks[3], <-b.blockNtfns)
+	require.Empty(t, b.blockNtfns)
+	require.Equal(t, blocks[3].BlockHash(), b.getLastBlock())
+	require.False(t, b.mempool.ContainsTx(tx3.TxHash()))
+
+	// The transaction missed is sent, and the one removed is reported.
+	require.Equal(t, tx2Hash, (<-b.txNtfns).TxHash())
+	require.Empty(t, b.txNtfns)
+	require.Equal(t, tx1.TxHash(), <-b.removedTxNtfns)
+	require.Empty(t, b.removedTxNtfns)
+	require.True(t, b.mempool.ContainsTx(tx2Hash))
+	require.False(t, b.mempool.ContainsTx(tx1.TxHash()))
+
+	// Blocks already sent aren't sent again.
+	require.True(t, b.sendBlock(blocks[3]))
+	require.Empty(t, b.blockNtfns)
+
+	// Events accounted for by the resync are skipped.
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: tx2Hash, label: sequenceTxRemoved, mempoolSeq: 10,
+	})
+	require.True(t, b.mempool.ContainsTx(tx2Hash))
+
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: tx2Hash, label: sequenceTxRemoved, mempoolSeq: 11,
+	})
+	require.False(t, b.mempool.ContainsTx(tx2Hash))
+	require.Equal(t, tx2Hash, <-b.removedTxNtfns)
+
+	// A block disconnected is sent again when it's connected again.
+	b.handleSequenceMsg(&sequenceMsg{
+		hash: blocks[3].BlockHash(), label: sequenceBlockDisconnected,
+	})
+	require.True(t, b.sendBlock(blocks[3]))
+	require.Equal(t, blocks[3], <-b.blockNtfns)
+}
@@ -34,6 +34,15 @@ type RPCClient struct {
 	dequeueNotification chan interface{}
 	currentBlock        chan *waddrmgr.BlockStamp
 
+	// unminedTxs keeps track of the relevant transactions notified to us
+	// that have yet to be mined, so that we can find out when they leave
+	// the mempool.
+	unminedMtx sync.Mutex
+	unminedTxs map[chainhash.Hash]*wire.MsgTx
+
+	// checkUnmined is used to request a check of the unmined transactions.
+	checkUnmined chan struct{}
+
 	quit    chan struct{}
 	wg      sync.WaitGroup
 	started bool
@@ -75,6 +84,8 @@ func NewRPCClient(chainParams *chaincfg.Params, connect, user, pass string, cert
 		enqueueNotification: make(chan interface{}),
 		dequeueNotification: make(chan interface{}),
 		currentBlock:        make(chan *waddrmgr.BlockStamp),
+		unminedTxs:          make(map[chainhash.Hash]*wire.MsgTx),
+		checkUnmined:        make(chan struct{}, 1),
 		quit:                make(chan struct{}),
 	}
 	ntfnCallbacks := &rpcclient.NotificationHandlers{
@@ -169,6 +180,8 @@ func NewRPCClientWithConfig(cfg *RPCClientConfig) (*RPCClient, error) {
 		enqueueNotification: make(chan interface{}),
 		dequeueNotification: make(chan interface{}),
 		currentBlock:        make(chan *waddrmgr.BlockStamp),
+		unminedTxs:          make(map[chainhash.Hash]*wire.MsgTx),
+		checkUnmined:        make(chan struct{}, 1),
 		quit:                make(chan struct{}),
 	}
 
@@ -228,8 +241,9 @@ func (c *RPCClient) Start() error {
 	c.started = true
 	c.quitMtx.Unlock()
 
-	c.wg.Add(1)
+	c.wg.Add(2)
 	go c.handler()
+	go c.unminedTxHandler()
 	return nil
 }
 
@@ -423,6 +437,10 @@ func (c *RPCClient) onClientConnect() {
 }
 
 func (c *RPCClient) onBlockConnected(hash *chainhash.Hash, height int32, time time.Time) {
+	// The new block may have confirmed, or conflicted with, some of the
+	// unmined transactions.
+	c.requestUnminedTxCheck()
+
 	select {
 	case c.enqueueNotification <- BlockConnected{
 		Block: wtxmgr.Block{
@@ -462,6 +480,16 @@ func (c *RPCClient) onRecvTx(tx *btcutil.Tx, block *btcjson.BlockDetails) {
 			"tx: %v", err)
 		return
 	}
+
+	// Keep track of the transaction while it's unmined. A new transaction
+	// may also have replaced one of the others, so we'll check them.
+	if blk == nil {
+		c.trackUnminedTx(tx.MsgTx())
+		c.requestUnminedTxCheck()
+	} else {
+		c.untrackUnminedTx(*tx.Hash())
+	}
+
 	select {
 	case c.enqueueNotification <- RelevantTx{rec, blk}:
 	case <-c.quit:
@@ -3,8 +3,11 @@ package chain
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,3 +59,145 @@ func TestValidateConfig(t *testing.T) {
 	_, err := NewRPCClientWithConfig(nil)
 	rt.ErrorContains(err, "missing rpc config")
 }
+
+// fakeUnminedTxChecker is an unminedTxChecker serving a fixed mempool and set
+// of unspent outputs.
+type fakeUnminedTxChecker struct {
+	mempool []*chainhash.Hash
+	spends  map[wire.OutPoint]chainhash.Hash
+	utxos   map[wire.OutPoint]struct{}
+
+	// mempoolUtxos are the unspent outputs of the transactions in the
+	// mempool, which are only returned when the mempool is included.
+	mempoolUtxos map[wire.OutPoint]struct{}
+}
+
+func (c *fakeUnminedTxChecker) GetRawMempool() ([]*chainhash.Hash, error) {
+	return c.mempool, nil
+}
+
+func (c *fakeUnminedTxChecker) GetTxOut(txHash *chainhash.Hash, index uint32,
+	mempool bool) (*btcjson.GetTxOutResult, error) {
+
+	op := wire.OutPoint{Hash: *txHash, Index: index}
+	if _, ok := c.utxos[op]; ok {
+		return &btcjson.GetTxOutResult{Confirmations: 1}, nil
+	}
+	if _, ok := c.mempoolUtxos[op]; ok && mempool {
+		return &btcjson.GetTxOutResult{}, nil
+	}
+
+	return nil, nil
+}
+
+func (c *fakeUnminedTxChecker) LookupInputMempoolSpend(
+	op wire.OutPoint) (chainhash.Hash, bool) {
+
+	hash, ok := c.spends[op]
+	return hash, ok
+}
+
+// TestCheckUnminedTxs tests that the unmined transactions which left btcd's
+// mempool are notified as replaced or evicted, unless they were mined.
+func TestCheckUnminedTxs(t *testing.T) {
+	t.Parallel()
+
+	newTx := func(ops ...wire.OutPoint) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		for _, op := range ops {
+			op := op
+			tx.AddTxIn(wire.NewTxIn(&op, nil, nil))
+		}
+		tx.AddTxOut(wire.NewTxOut(1000, nil))
+
+		return tx
+	}
+	outPoint := func(tx *wire.MsgTx) wire.OutPoint {
+		return wire.OutPoint{Hash: tx.TxHash()}
+	}
+
+	var (
+		op1 = wire.OutPoint{Hash: chainhash.Hash{1}}
+		op2 = wire.OutPoint{Hash: chainhash.Hash{2}}
+		op3 = wire.OutPoint{Hash: chainhash.Hash{3}}
+		op4 = wire.OutPoint{Hash: chainhash.Hash{4}}
+
+		inMempool   = newTx(op1)
+		replaced    = newTx(op2, op3)
+		replacement = newTx(op3)
+		evicted     = newTx(op4)
+		mined       = newTx(wire.OutPoint{Hash: chainhash.Hash{5}})
+
+		// The parent of unconfirmedParent is still in the mempool,
+		// while those of the other two left it, evicted or mined.
+		unconfirmedParent = newTx(outPoint(inMempool))
+		evictedParent     = newTx(outPoint(evicted))
+		minedParent       = newTx(outPoint(mined))
+	)
+
+	replacementHash := replacement.TxHash()
+	inMempoolHash := inMempool.TxHash()
+	client := &fakeUnminedTxChecker{
+		mempool: []*chainhash.Hash{&inMempoolHash, &replacementHash},
+		spends: map[wire.OutPoint]chainhash.Hash{
+			op1: inMempool.TxHash(),
+			op3: replacement.TxHash(),
+		},
+		utxos: map[wire.OutPoint]struct{}{
+			op2: {},
+			op4: {},
+		},
+		mempoolUtxos: map[wire.OutPoint]struct{}{
+			outPoint(inMempool): {},
+		},
+	}
+
+	// The children are checked before their parents.
+	ntfns, done, err := checkUnminedTxs(client, []*wire.MsgTx{
+		inMempool, unconfirmedParent, evictedParent, minedParent,
+		replaced, evicted, mined,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []interface{}{
+		TxEvicted{Hash: unconfirmedParent.TxHash()},
+		TxEvicted{Hash: evictedParent.TxHash()},
+		TxReplaced{Old: replaced.TxHash(), New: replacement.TxHash()},
+		TxEvicted{Hash: evicted.TxHash()},
+	}, ntfns)
+	require.Equal(t, []chainhash.Hash{
+		unconfirmedParent.TxHash(), evictedParent.TxHash(),
+		minedParent.TxHash(), replaced.TxHash(), evicted.TxHash(),
+		mined.TxHash(),
+	}, done)
+}
+
+// TestTrackUnminedTxs tests that the unmined transactions the wallet seeds the
+// client with are tracked and checked right away.
+func TestTrackUnminedTxs(t *testing.T) {
+	t.Parallel()
+
+	c := &RPCClient{
+		unminedTxs:   make(map[chainhash.Hash]*wire.MsgTx),
+		checkUnmined: make(chan struct{}, 1),
+	}
+
+	tx1 := wire.NewMsgTx(2)
+	tx1.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, nil,
+		nil))
+	tx2 := wire.NewMsgTx(2)
+	tx2.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{2}}, nil,
+		nil))
+
+	c.TrackUnminedTxs([]*wire.MsgTx{tx1, tx2})
+	require.Equal(t, map[chainhash.Hash]*wire.MsgTx{
+		tx1.TxHash(): tx1,
+		tx2.TxHash(): tx2,
+	}, c.unminedTxs)
+
+	select {
+	case <-c.checkUnmined:
+	default:
+		t.Fatal("no check of the unmined transactions requested")
+	}
+}
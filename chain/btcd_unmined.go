@@ -0,0 +1,254 @@
+package chain
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// unminedTxCheckInterval is the interval at which the relevant unmined
+// transactions are checked against btcd's mempool, in addition to the checks
+// triggered by new blocks and transactions.
+const unminedTxCheckInterval = time.Minute
+
+// unminedTxChecker is the set of RPC calls needed to find out what happened
+// to the relevant unmined transactions that left btcd's mempool.
+type unminedTxChecker interface {
+	// GetRawMempool returns the hashes of all transactions in the mempool.
+	GetRawMempool() ([]*chainhash.Hash, error)
+
+	// GetTxOut returns the details of an unspent transaction output, or
+	// nil if the output is spent or doesn't exist. The outputs created and
+	// spent by the transactions in the mempool are taken into account when
+	// mempool is true.
+	GetTxOut(txHash *chainhash.Hash, index uint32,
+		mempool bool) (*btcjson.GetTxOutResult, error)
+
+	// LookupInputMempoolSpend returns the hash of the mempool transaction
+	// spending the given outpoint, if any.
+	LookupInputMempoolSpend(op wire.OutPoint) (chainhash.Hash, bool)
+}
+
+// trackUnminedTx starts tracking a relevant transaction that has yet to be
+// mined, so that the wallet can be told once it leaves the mempool.
+func (c *RPCClient) trackUnminedTx(tx *wire.MsgTx) {
+	c.unminedMtx.Lock()
+	c.unminedTxs[tx.TxHash()] = tx
+	c.unminedMtx.Unlock()
+}
+
+// TrackUnminedTxs starts tracking the given transactions, which are known to
+// be unmined, and checks them right away. The wallet calls it with the unmined
+// transactions of its store, which btcd only notifies us of when they first
+// enter its mempool, so that the ones which left the mempool while the wallet
+// wasn't running are found too.
+func (c *RPCClient) TrackUnminedTxs(txs []*wire.MsgTx) {
+	for _, tx := range txs {
+		c.trackUnminedTx(tx)
+	}
+	c.requestUnminedTxCheck()
+}
+
+// untrackUnminedTx stops tracking the given transaction.
+func (c *RPCClient) untrackUnminedTx(hash chainhash.Hash) {
+	c.unminedMtx.Lock()
+	delete(c.unminedTxs, hash)
+	c.unminedMtx.Unlock()
+}
+
+// requestUnminedTxCheck asks unminedTxHandler to check the relevant unmined
+// transactions without blocking the caller.
+func (c *RPCClient) requestUnminedTxCheck() {
+	select {
+	case c.checkUnmined <- struct{}{}:
+	default:
+	}
+}
+
+// unminedTxHandler checks whether the relevant unmined transactions are still
+// in btcd's mempool whenever it's requested to, or periodically. btcd doesn't
+// notify us of the transactions leaving its mempool, so this is how we find out
+// about the ones that were replaced or evicted.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *RPCClient) unminedTxHandler() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(unminedTxCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.checkUnmined:
+		case <-ticker.C:
+		case <-c.quit:
+			return
+		}
+
+		c.unminedMtx.Lock()
+		if len(c.unminedTxs) == 0 {
+			c.unminedMtx.Unlock()
+			continue
+		}
+		txs := make([]*wire.MsgTx, 0, len(c.unminedTxs))
+		for _, tx := range c.unminedTxs {
+			txs = append(txs, tx)
+		}
+		c.unminedMtx.Unlock()
+
+		ntfns, done, err := checkUnminedTxs(c, txs)
+		if err != nil {
+			log.Errorf("Unable to check unmined transactions: %v",
+				err)
+			continue
+		}
+
+		for _, hash := range done {
+			c.untrackUnminedTx(hash)
+		}
+
+		for _, ntfn := range ntfns {
+			select {
+			case c.enqueueNotification <- ntfn:
+			case <-c.quit:
+				return
+			}
+		}
+	}
+}
+
+// checkUnminedTxs checks which of the given unmined transactions left the
+// mempool. For each of them, a TxReplaced notification is returned if one of
+// its inputs is now spent by another transaction in the mempool, or a
+// TxEvicted notification if all of its inputs are still unspent, whether their
+// parents are confirmed or still in the mempool, or if one of its parents left
+// the mempool without being mined as well. The transactions whose inputs are
+// spent by a mined transaction were either mined or conflict with a mined
+// transaction, which the block notifications take care of. The hashes of all
+// the transactions that left the mempool are returned too, as they don't need
+// to be tracked anymore.
+func checkUnminedTxs(client unminedTxChecker, txs []*wire.MsgTx) (
+	[]interface{}, []chainhash.Hash, error) {
+
+	mempool, err := client.GetRawMempool()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inMempool := make(map[chainhash.Hash]struct{}, len(mempool))
+	for _, hash := range mempool {
+		inMempool[*hash] = struct{}{}
+	}
+
+	check := &unminedTxCheck{
+		client:  client,
+		left:    make(map[chainhash.Hash]*wire.MsgTx),
+		results: make(map[chainhash.Hash]interface{}),
+	}
+	for _, tx := range txs {
+		hash := tx.TxHash()
+		if _, ok := inMempool[hash]; !ok {
+			check.left[hash] = tx
+		}
+	}
+
+	var (
+		ntfns []interface{}
+		done  []chainhash.Hash
+	)
+	for _, tx := range txs {
+		hash := tx.TxHash()
+		if _, ok := check.left[hash]; !ok {
+			continue
+		}
+
+		ntfn, err := check.leftMempool(tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ntfn != nil {
+			ntfns = append(ntfns, ntfn)
+		}
+		done = append(done, hash)
+	}
+
+	return ntfns, done, nil
+}
+
+// unminedTxCheck holds the state of a check of the unmined transactions.
+type unminedTxCheck struct {
+	client unminedTxChecker
+
+	// left holds the checked transactions that left the mempool.
+	left map[chainhash.Hash]*wire.MsgTx
+
+	// results holds the notification of each transaction that left the
+	// mempool, or nil if it was mined, once it's known.
+	results map[chainhash.Hash]interface{}
+}
+
+// leftMempool returns the notification for a transaction that left the
+// mempool, or nil if one of its inputs is spent by a mined transaction.
+func (c *unminedTxCheck) leftMempool(tx *wire.MsgTx) (interface{}, error) {
+	hash := tx.TxHash()
+	if ntfn, ok := c.results[hash]; ok {
+		return ntfn, nil
+	}
+
+	ntfn, err := c.classify(tx)
+	if err != nil {
+		return nil, err
+	}
+	c.results[hash] = ntfn
+
+	return ntfn, nil
+}
+
+// classify finds out why a transaction left the mempool.
+func (c *unminedTxCheck) classify(tx *wire.MsgTx) (interface{}, error) {
+	hash := tx.TxHash()
+
+	// The transaction was replaced if one of its inputs is now spent by
+	// another transaction in the mempool.
+	for _, txIn := range tx.TxIn {
+		spender, ok := c.client.LookupInputMempoolSpend(
+			txIn.PreviousOutPoint,
+		)
+		if ok && spender != hash {
+			return TxReplaced{Old: hash, New: spender}, nil
+		}
+	}
+
+	// Otherwise it was evicted, unless one of its inputs was spent by a
+	// mined transaction. The outputs of the parents still in the mempool
+	// are unspent too.
+	for _, txIn := range tx.TxIn {
+		op := txIn.PreviousOutPoint
+		txOut, err := c.client.GetTxOut(&op.Hash, op.Index, true)
+		if err != nil {
+			return nil, err
+		}
+		if txOut != nil {
+			continue
+		}
+
+		// The output is gone as well when its parent left the mempool
+		// without being mined, in which case this transaction was
+		// evicted along with it.
+		if parent, ok := c.left[op.Hash]; ok {
+			parentNtfn, err := c.leftMempool(parent)
+			if err != nil {
+				return nil, err
+			}
+			if parentNtfn != nil {
+				return TxEvicted{Hash: hash}, nil
+			}
+		}
+
+		return nil, nil
+	}
+
+	return TxEvicted{Hash: hash}, nil
+}
@@ -0,0 +1,867 @@
+package chaintest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/mining"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+const (
+	// BlockInterval is the difference between the timestamps of
+	// consecutive blocks.
+	BlockInterval = 10 * time.Minute
+
+	// maxStandardTxWeight is the maximum weight of a transaction accepted
+	// into the mempool.
+	maxStandardTxWeight = 400000
+
+	// maxStandardTxVersion is the maximum version of a transaction
+	// accepted into the mempool.
+	maxStandardTxVersion = 3
+)
+
+var (
+	// anyoneCanSpendScript is the witness script of the outputs paid to by
+	// the coinbase transactions.  It is spent by a witness only containing
+	// the script.
+	anyoneCanSpendScript = []byte{txscript.OP_TRUE}
+
+	// AnyoneCanSpendPkScript is the P2WSH output script the coinbase
+	// transactions and the change outputs of SendOutputs pay to.
+	AnyoneCanSpendPkScript = func() []byte {
+		hash := chainhash.HashB(anyoneCanSpendScript)
+		pkScript, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).AddData(hash).Script()
+		if err != nil {
+			panic(err)
+		}
+		return pkScript
+	}()
+
+	// ErrPrematureSpend is returned when a transaction spends a coinbase
+	// output before it matured.
+	ErrPrematureSpend = errors.New("bad-txns-premature-spend-of-coinbase")
+
+	// ErrInsufficientFunds is returned by SendOutputs when the mature
+	// coinbase outputs don't cover the outputs and fee.
+	ErrInsufficientFunds = errors.New("insufficient mature coinbase " +
+		"outputs")
+)
+
+// Config contains the configuration of a Chain.
+type Config struct {
+	// ChainParams are the parameters of the network the chain belongs to.
+	// Its genesis block is the first block of the chain.
+	ChainParams *chaincfg.Params
+
+	// StartTime is the earliest timestamp of the mined blocks.  Blocks are
+	// timestamped BlockInterval after their parent, or at the start time
+	// if that is later.
+	StartTime time.Time
+
+	// MinRelayFee is the minimum fee rate, in satoshis per kvB, of
+	// transactions accepted into the mempool.  It also determines which
+	// outputs are dust.  txrules.DefaultRelayFeePerKb is used if zero.
+	MinRelayFee btcutil.Amount
+}
+
+// utxo is an unspent output of the best chain.
+type utxo struct {
+	out      *wire.TxOut
+	height   int32
+	coinbase bool
+}
+
+// utxoView is a set of unspent outputs.
+type utxoView map[wire.OutPoint]*utxo
+
+// connectTx spends the inputs of the transaction and adds its outputs to the
+// view.
+func (v utxoView) connectTx(tx *wire.MsgTx, height int32) {
+	coinbase := blockchain.IsCoinBaseTx(tx)
+	if !coinbase {
+		for _, in := range tx.TxIn {
+			delete(v, in.PreviousOutPoint)
+		}
+	}
+
+	txHash := tx.TxHash()
+	for i, out := range tx.TxOut {
+		if txscript.IsUnspendable(out.PkScript) {
+			continue
+		}
+		v[wire.OutPoint{Hash: txHash, Index: uint32(i)}] = &utxo{
+			out:      out,
+			height:   height,
+			coinbase: coinbase,
+		}
+	}
+}
+
+// Chain is a deterministic in-memory block chain with a mempool.  Clients
+// created by NewClient are notified of its changes.  It is safe for concurrent
+// use.
+type Chain struct {
+	cfg Config
+
+	mtx sync.Mutex
+
+	// best is the best chain, indexed by height.
+	best []*wire.MsgBlock
+
+	// blocks contains all blocks ever mined, including those that were
+	// reorganized out of the best chain.
+	blocks  map[chainhash.Hash]*wire.MsgBlock
+	heights map[chainhash.Hash]int32
+
+	// txHeights indexes the heights of the transactions of the best chain.
+	txHeights map[chainhash.Hash]int32
+
+	utxos utxoView
+
+	// mempool contains the unconfirmed transactions in the order they
+	// were accepted.  spentByMempool maps the outpoints they spend to the
+	// spending transaction.
+	mempool        []*wire.MsgTx
+	mempoolTxs     map[chainhash.Hash]*wire.MsgTx
+	spentByMempool map[wire.OutPoint]chainhash.Hash
+
+	current bool
+	clients map[*Client]struct{}
+}
+
+// NewChain creates a chain only containing the genesis block of the network.
+func NewChain(cfg *Config) (*Chain, error) {
+	if cfg.ChainParams == nil {
+		return nil, errors.New("missing chain params config")
+	}
+
+	c := &Chain{
+		cfg:            *cfg,
+		blocks:         make(map[chainhash.Hash]*wire.MsgBlock),
+		heights:        make(map[chainhash.Hash]int32),
+		txHeights:      make(map[chainhash.Hash]int32),
+		utxos:          make(utxoView),
+		mempoolTxs:     make(map[chainhash.Hash]*wire.MsgTx),
+		spentByMempool: make(map[wire.OutPoint]chainhash.Hash),
+		current:        true,
+		clients:        make(map[*Client]struct{}),
+	}
+	if c.cfg.MinRelayFee == 0 {
+		c.cfg.MinRelayFee = txrules.DefaultRelayFeePerKb
+	}
+
+	// The outputs of the genesis block can't be spent.
+	genesis := cfg.ChainParams.GenesisBlock
+	hash := genesis.BlockHash()
+	c.best = append(c.best, genesis)
+	c.blocks[hash] = genesis
+	c.heights[hash] = 0
+
+	return c, nil
+}
+
+// ChainParams returns the parameters of the network of the chain.
+func (c *Chain) ChainParams() *chaincfg.Params {
+	return c.cfg.ChainParams
+}
+
+// SetCurrent sets whether the clients of the chain report to be current.
+// Clients are current by default.
+func (c *Chain) SetCurrent(current bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.current = current
+}
+
+// BestBlock returns the hash and height of the tip of the best chain.
+func (c *Chain) BestBlock() (*chainhash.Hash, int32) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	tip := c.best[len(c.best)-1]
+	hash := tip.BlockHash()
+	return &hash, int32(len(c.best) - 1)
+}
+
+// BlockAt returns the block of the best chain at the given height.
+func (c *Chain) BlockAt(height int32) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height < 0 || int(height) >= len(c.best) {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	return copyBlock(c.best[height]), nil
+}
+
+// Mempool returns the transactions of the mempool in the order they were
+// accepted.
+func (c *Chain) Mempool() []*wire.MsgTx {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	txs := make([]*wire.MsgTx, len(c.mempool))
+	for i, tx := range c.mempool {
+		txs[i] = tx.Copy()
+	}
+	return txs
+}
+
+// Confirmations returns the number of confirmations of the transaction in the
+// best chain.  Zero is returned for transactions of the mempool, and an error
+// for unknown transactions.
+func (c *Chain) Confirmations(txHash *chainhash.Hash) (int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height, ok := c.txHeights[*txHash]; ok {
+		return int32(len(c.best)) - height, nil
+	}
+	if _, ok := c.mempoolTxs[*txHash]; ok {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown transaction %v", txHash)
+}
+
+// GenerateBlocks mines the given number of blocks on top of the best chain.
+// The first block contains all transactions of the mempool.
+func (c *Chain) GenerateBlocks(n int) ([]*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	blocks := make([]*wire.MsgBlock, 0, n)
+	for i := 0; i < n; i++ {
+		var txs []*wire.MsgTx
+		if i == 0 {
+			txs = c.mempool
+		}
+		block, err := c.mineBlock(txs)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, copyBlock(block))
+	}
+
+	return blocks, nil
+}
+
+// GenerateBlock mines a block containing the given transactions, which don't
+// need to be part of the mempool, on top of the best chain.  Transactions of
+// the mempool conflicting with them are removed from the mempool.
+func (c *Chain) GenerateBlock(txs ...*wire.MsgTx) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	block, err := c.mineBlock(txs)
+	if err != nil {
+		return nil, err
+	}
+	return copyBlock(block), nil
+}
+
+// Reorg replaces the given number of blocks at the tip of the best chain with
+// new blocks containing the given transactions.  As the chain with the most
+// work is the best chain, more blocks than are replaced must be given.  The
+// transactions of the replaced blocks that are not part of the new blocks are
+// returned to the mempool if they are still valid.
+func (c *Chain) Reorg(depth int,
+	blocks ...[]*wire.MsgTx) ([]*wire.MsgBlock, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if depth < 1 || depth >= len(c.best) {
+		return nil, fmt.Errorf("invalid reorg depth %d", depth)
+	}
+	if len(blocks) <= depth {
+		return nil, fmt.Errorf("a reorg of depth %d requires more than "+
+			"%d new blocks", depth, depth)
+	}
+
+	// Validate the new blocks against the fork point before changing the
+	// best chain.
+	forkHeight := int32(len(c.best) - 1 - depth)
+	view := c.utxoViewAt(forkHeight)
+	newBlocks := make([]*wire.MsgBlock, 0, len(blocks))
+	prev := c.best[forkHeight]
+	for i, txs := range blocks {
+		height := forkHeight + 1 + int32(i)
+		block, err := c.newBlock(prev, height, view, txs)
+		if err != nil {
+			return nil, err
+		}
+		newBlocks = append(newBlocks, block)
+		prev = block
+	}
+
+	disconnected := make([]*wire.MsgBlock, depth)
+	copy(disconnected, c.best[forkHeight+1:])
+	for height := int32(len(c.best) - 1); height > forkHeight; height-- {
+		block := c.best[height]
+		for _, tx := range block.Transactions {
+			delete(c.txHeights, tx.TxHash())
+		}
+		c.best = c.best[:height]
+		c.notifyClients(func(client *Client) {
+			client.disconnectBlock(block, height)
+		})
+	}
+	c.utxos = c.utxoViewAt(forkHeight)
+
+	// The transactions of the mempool are accepted again after the new
+	// blocks are connected, following those of the disconnected blocks.
+	mempoolTxs := c.mempool
+	c.mempool = nil
+	c.mempoolTxs = make(map[chainhash.Hash]*wire.MsgTx)
+	c.spentByMempool = make(map[wire.OutPoint]chainhash.Hash)
+
+	for _, block := range newBlocks {
+		c.connectBlock(block)
+	}
+
+	var readd []*wire.MsgTx
+	for _, block := range disconnected {
+		readd = append(readd, block.Transactions[1:]...)
+	}
+	readd = append(readd, mempoolTxs...)
+	for _, tx := range readd {
+		if _, err := c.checkTx(tx); err != nil {
+			continue
+		}
+		c.addMempoolTx(tx, false)
+	}
+
+	result := make([]*wire.MsgBlock, len(newBlocks))
+	for i, block := range newBlocks {
+		result[i] = copyBlock(block)
+	}
+	return result, nil
+}
+
+// AcceptTx accepts the transaction into the mempool if it passes the policy
+// checks, notifying the clients watching it.
+func (c *Chain) AcceptTx(tx *wire.MsgTx) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, err := c.checkTx(tx); err != nil {
+		return err
+	}
+	c.addMempoolTx(tx.Copy(), true)
+
+	return nil
+}
+
+// SendOutputs creates a transaction paying to the outputs from mature
+// coinbase outputs, with change paid to AnyoneCanSpendPkScript, and accepts
+// it into the mempool.  The fee rate is the minimum relay fee rate.
+func (c *Chain) SendOutputs(outputs ...*wire.TxOut) (*wire.MsgTx, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var target btcutil.Amount
+	for _, out := range outputs {
+		target += btcutil.Amount(out.Value)
+	}
+
+	// Spend the oldest mature coinbase outputs first, so the funding
+	// transactions are deterministic.
+	bestHeight := int32(len(c.best) - 1)
+	maturity := int32(c.cfg.ChainParams.CoinbaseMaturity)
+	var coins []wire.OutPoint
+	for op, u := range c.utxos {
+		if !u.coinbase || bestHeight+1-u.height < maturity ||
+			!bytes.Equal(u.out.PkScript, AnyoneCanSpendPkScript) {
+
+			continue
+		}
+		if _, ok := c.spentByMempool[op]; ok {
+			continue
+		}
+		coins = append(coins, op)
+	}
+	sort.Slice(coins, func(i, j int) bool {
+		hi, hj := c.utxos[coins[i]].height, c.utxos[coins[j]].height
+		if hi != hj {
+			return hi < hj
+		}
+		return bytes.Compare(coins[i].Hash[:], coins[j].Hash[:]) < 0
+	})
+
+	tx := wire.NewMsgTx(2)
+	for _, out := range outputs {
+		tx.AddTxOut(wire.NewTxOut(out.Value, out.PkScript))
+	}
+	change := wire.NewTxOut(0, AnyoneCanSpendPkScript)
+	tx.AddTxOut(change)
+
+	var input btcutil.Amount
+	for _, op := range coins {
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: op,
+			Witness:          wire.TxWitness{anyoneCanSpendScript},
+			Sequence:         wire.MaxTxInSequenceNum,
+		})
+		input += btcutil.Amount(c.utxos[op].out.Value)
+
+		fee := txrules.FeeForSerializeSize(
+			c.cfg.MinRelayFee, int(txVirtualSize(tx)),
+		)
+		if input < target+fee {
+			continue
+		}
+
+		change.Value = int64(input - target - fee)
+		if txrules.IsDustOutput(change, c.cfg.MinRelayFee) {
+			tx.TxOut = tx.TxOut[:len(tx.TxOut)-1]
+		}
+		if _, err := c.checkTx(tx); err != nil {
+			return nil, err
+		}
+		c.addMempoolTx(tx, true)
+
+		return tx.Copy(), nil
+	}
+
+	return nil, ErrInsufficientFunds
+}
+
+// NewClient creates a client of the chain.  It must be started before it is
+// used.
+func (c *Chain) NewClient() *Client {
+	return &Client{
+		chain:             c,
+		notificationQueue: chain.NewConcurrentQueue(20),
+		watchedScripts:    make(map[string]struct{}),
+		watchedOutPoints:  make(map[wire.OutPoint]struct{}),
+		quit:              make(chan struct{}),
+	}
+}
+
+// mineBlock mines a block containing the transactions on top of the best
+// chain.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) mineBlock(txs []*wire.MsgTx) (*wire.MsgBlock, error) {
+	height := int32(len(c.best))
+	view := make(utxoView, len(c.utxos))
+	for op, u := range c.utxos {
+		view[op] = u
+	}
+
+	block, err := c.newBlock(c.best[height-1], height, view, txs)
+	if err != nil {
+		return nil, err
+	}
+	c.connectBlock(block)
+
+	return block, nil
+}
+
+// newBlock creates a block on top of prev containing the transactions, after
+// validating them against the view of the unspent outputs at the height of
+// prev.  The view is updated with the transactions of the block.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) newBlock(prev *wire.MsgBlock, height int32, view utxoView,
+	txs []*wire.MsgTx) (*wire.MsgBlock, error) {
+
+	timestamp := prev.Header.Timestamp.Add(BlockInterval)
+	if timestamp.Before(c.cfg.StartTime) {
+		timestamp = time.Unix(c.cfg.StartTime.Unix(), 0)
+	}
+
+	var fees int64
+	for _, tx := range txs {
+		fee, err := checkInputs(tx, view, height, c.cfg.ChainParams)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction %v: %w",
+				tx.TxHash(), err)
+		}
+		if !blockchain.IsFinalizedTransaction(
+			btcutil.NewTx(tx), height, prev.Header.Timestamp,
+		) {
+
+			return nil, fmt.Errorf("invalid transaction %v: %w",
+				tx.TxHash(), chain.ErrNonFinal)
+		}
+		fees += fee
+		view.connectTx(tx, height)
+	}
+
+	// The coinbase commits to the height of the block and to the number
+	// of blocks mined before, so blocks at the same height of competing
+	// chains differ.
+	coinbaseScript, err := txscript.NewScriptBuilder().
+		AddInt64(int64(height)).AddInt64(int64(len(c.blocks))).
+		Script()
+	if err != nil {
+		return nil, err
+	}
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  coinbaseScript,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(
+		blockchain.CalcBlockSubsidy(height, c.cfg.ChainParams)+fees,
+		AnyoneCanSpendPkScript,
+	))
+
+	blockTxs := make([]*btcutil.Tx, 0, len(txs)+1)
+	blockTxs = append(blockTxs, btcutil.NewTx(coinbase))
+	for _, tx := range txs {
+		blockTxs = append(blockTxs, btcutil.NewTx(tx.Copy()))
+	}
+	mining.AddWitnessCommitment(blockTxs[0], blockTxs)
+	view.connectTx(coinbase, height)
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   4,
+			PrevBlock: prev.BlockHash(),
+			MerkleRoot: blockchain.CalcMerkleRoot(
+				blockTxs, false,
+			),
+			Timestamp: timestamp,
+			Bits:      prev.Header.Bits,
+		},
+	}
+	for _, tx := range blockTxs {
+		block.Transactions = append(block.Transactions, tx.MsgTx())
+	}
+
+	return block, nil
+}
+
+// connectBlock appends the validated block to the best chain, removes its
+// transactions and those conflicting with them from the mempool, and notifies
+// the clients.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) connectBlock(block *wire.MsgBlock) {
+	height := int32(len(c.best))
+	hash := block.BlockHash()
+	c.best = append(c.best, block)
+	c.blocks[hash] = block
+	c.heights[hash] = height
+
+	for _, tx := range block.Transactions {
+		c.txHeights[tx.TxHash()] = height
+		c.utxos.connectTx(tx, height)
+	}
+
+	// Remove the mined transactions and the transactions spending the
+	// same outputs, together with their descendants.
+	mined := make(map[chainhash.Hash]struct{}, len(block.Transactions))
+	for _, tx := range block.Transactions[1:] {
+		mined[tx.TxHash()] = struct{}{}
+	}
+	removed := make(map[chainhash.Hash]struct{})
+	for _, tx := range block.Transactions[1:] {
+		for _, in := range tx.TxIn {
+			spender, ok := c.spentByMempool[in.PreviousOutPoint]
+			if ok {
+				if _, ok := mined[spender]; !ok {
+					removed[spender] = struct{}{}
+				}
+			}
+		}
+	}
+	mempool := c.mempool[:0:0]
+	for _, tx := range c.mempool {
+		txHash := tx.TxHash()
+		_, isMined := mined[txHash]
+		_, isRemoved := removed[txHash]
+		if !isRemoved && !isMined {
+			for _, in := range tx.TxIn {
+				_, ok := removed[in.PreviousOutPoint.Hash]
+				if ok {
+					isRemoved = true
+					removed[txHash] = struct{}{}
+					break
+				}
+			}
+		}
+		if isMined || isRemoved {
+			delete(c.mempoolTxs, txHash)
+			for _, in := range tx.TxIn {
+				delete(c.spentByMempool, in.PreviousOutPoint)
+			}
+			continue
+		}
+		mempool = append(mempool, tx)
+	}
+	c.mempool = mempool
+
+	c.notifyClients(func(client *Client) {
+		client.connectBlock(block, height)
+	})
+}
+
+// utxoViewAt returns the unspent outputs of the best chain up to the given
+// height.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) utxoViewAt(height int32) utxoView {
+	view := make(utxoView)
+	for h := int32(1); h <= height; h++ {
+		for _, tx := range c.best[h].Transactions {
+			view.connectTx(tx, h)
+		}
+	}
+	return view
+}
+
+// checkTx checks whether the transaction can be accepted into the mempool,
+// returning its fee.  Like bitcoind, transactions conflicting with the mempool
+// are rejected, as are non-standard transactions and transactions not paying
+// the minimum relay fee.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) checkTx(tx *wire.MsgTx) (btcutil.Amount, error) {
+	txHash := tx.TxHash()
+	switch {
+	case blockchain.IsCoinBaseTx(tx):
+		return 0, chain.ErrCoinbaseTx
+
+	case c.mempoolTxs[txHash] != nil:
+		return 0, chain.ErrTxAlreadyInMempool
+	}
+	if _, ok := c.txHeights[txHash]; ok {
+		return 0, chain.ErrTxAlreadyConfirmed
+	}
+
+	if tx.Version < 1 || tx.Version > maxStandardTxVersion {
+		return 0, chain.ErrNonStandardVersion
+	}
+	if txWeight(tx) > maxStandardTxWeight {
+		return 0, chain.ErrTxTooLarge
+	}
+	var numNullData int
+	for _, out := range tx.TxOut {
+		switch txscript.GetScriptClass(out.PkScript) {
+		case txscript.NonStandardTy:
+			return 0, chain.ErrNonStandardScript
+		case txscript.MultiSigTy:
+			return 0, chain.ErrBareMultiSig
+		case txscript.NullDataTy:
+			numNullData++
+			continue
+		}
+		if txrules.IsDustOutput(out, c.cfg.MinRelayFee) {
+			return 0, chain.ErrDust
+		}
+	}
+	if numNullData > 1 {
+		return 0, chain.ErrMultiOpReturn
+	}
+	for _, in := range tx.TxIn {
+		if !txscript.IsPushOnlyScript(in.SignatureScript) {
+			return 0, chain.ErrScriptSigNotPushOnly
+		}
+	}
+
+	nextHeight := int32(len(c.best))
+	tip := c.best[nextHeight-1]
+	if !blockchain.IsFinalizedTransaction(
+		btcutil.NewTx(tx), nextHeight, tip.Header.Timestamp,
+	) {
+
+		return 0, chain.ErrNonFinal
+	}
+
+	// Outputs of the mempool may be spent, unless another transaction of
+	// the mempool spends them.
+	view := make(utxoView, len(tx.TxIn))
+	for _, in := range tx.TxIn {
+		if _, ok := c.spentByMempool[in.PreviousOutPoint]; ok {
+			return 0, chain.ErrMempoolConflict
+		}
+		if u, ok := c.utxos[in.PreviousOutPoint]; ok {
+			view[in.PreviousOutPoint] = u
+			continue
+		}
+		parent, ok := c.mempoolTxs[in.PreviousOutPoint.Hash]
+		if !ok || int(in.PreviousOutPoint.Index) >= len(parent.TxOut) {
+			return 0, chain.ErrMissingInputs
+		}
+		view[in.PreviousOutPoint] = &utxo{
+			out:    parent.TxOut[in.PreviousOutPoint.Index],
+			height: nextHeight,
+		}
+	}
+
+	fee, err := checkInputs(tx, view, nextHeight, c.cfg.ChainParams)
+	if err != nil {
+		return 0, err
+	}
+
+	minFee := txrules.FeeForSerializeSize(
+		c.cfg.MinRelayFee, int(txVirtualSize(tx)),
+	)
+	if btcutil.Amount(fee) < minFee {
+		return 0, fmt.Errorf("%w: fee %v below %v",
+			chain.ErrMempoolMinFeeNotMet, btcutil.Amount(fee), minFee)
+	}
+
+	return btcutil.Amount(fee), nil
+}
+
+// addMempoolTx adds the checked transaction to the mempool, notifying the
+// clients watching it if requested.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) addMempoolTx(tx *wire.MsgTx, notify bool) {
+	txHash := tx.TxHash()
+	c.mempool = append(c.mempool, tx)
+	c.mempoolTxs[txHash] = tx
+	for _, in := range tx.TxIn {
+		c.spentByMempool[in.PreviousOutPoint] = txHash
+	}
+
+	if notify {
+		c.notifyClients(func(client *Client) {
+			client.notifyTx(tx, nil)
+		})
+	}
+}
+
+// notifyClients calls f for each started client.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Chain) notifyClients(f func(*Client)) {
+	clients := make([]*Client, 0, len(c.clients))
+	for client := range c.clients {
+		clients = append(clients, client)
+	}
+	for _, client := range clients {
+		f(client)
+	}
+}
+
+// checkInputs checks that the transaction's inputs are unspent outputs of the
+// view that may be spent at the given height, and that its scripts are valid.
+// The fee of the transaction is returned.
+func checkInputs(tx *wire.MsgTx, view utxoView, height int32,
+	params *chaincfg.Params) (int64, error) {
+
+	if len(tx.TxIn) == 0 {
+		return 0, chain.ErrEmptyInput
+	}
+	if len(tx.TxOut) == 0 {
+		return 0, chain.ErrEmptyOutput
+	}
+
+	var outputValue int64
+	for _, out := range tx.TxOut {
+		switch {
+		case out.Value < 0:
+			return 0, chain.ErrNegativeOutput
+		case out.Value > btcutil.MaxSatoshi:
+			return 0, chain.ErrLargeOutput
+		}
+		outputValue += out.Value
+		if outputValue > btcutil.MaxSatoshi {
+			return 0, chain.ErrLargeTotalOutput
+		}
+	}
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	spent := make(map[wire.OutPoint]struct{}, len(tx.TxIn))
+	var inputValue int64
+	for _, in := range tx.TxIn {
+		op := in.PreviousOutPoint
+		if _, ok := spent[op]; ok {
+			return 0, chain.ErrDuplicateInput
+		}
+		spent[op] = struct{}{}
+
+		u, ok := view[op]
+		if !ok {
+			return 0, chain.ErrMissingInputsOrSpent
+		}
+		if u.coinbase &&
+			height-u.height < int32(params.CoinbaseMaturity) {
+
+			return 0, ErrPrematureSpend
+		}
+		prevOuts.AddPrevOut(op, u.out)
+		inputValue += u.out.Value
+	}
+	if inputValue < outputValue {
+		return 0, chain.ErrBelowOutValue
+	}
+
+	sigHashes := txscript.NewTxSigHashes(tx, prevOuts)
+	for i, in := range tx.TxIn {
+		prevOut := prevOuts.FetchPrevOutput(in.PreviousOutPoint)
+		engine, err := txscript.NewEngine(
+			prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+			nil, sigHashes, prevOut.Value, prevOuts,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", chain.ErrScriptVerifyFlag,
+				err)
+		}
+		if err := engine.Execute(); err != nil {
+			return 0, fmt.Errorf("%w: input %d: %v",
+				chain.ErrScriptVerifyFlag, i, err)
+		}
+	}
+
+	return inputValue - outputValue, nil
+}
+
+// blockMeta returns the metadata of a block at the given height.
+func blockMeta(block *wire.MsgBlock, height int32) *wtxmgr.BlockMeta {
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   block.BlockHash(),
+			Height: height,
+		},
+		Time: block.Header.Timestamp,
+	}
+}
+
+// txWeight returns the weight of the transaction.
+func txWeight(tx *wire.MsgTx) int64 {
+	return blockchain.GetTransactionWeight(btcutil.NewTx(tx))
+}
+
+// txVirtualSize returns the virtual size of the transaction.
+func txVirtualSize(tx *wire.MsgTx) int64 {
+	return mempool.GetTxVirtualSize(btcutil.NewTx(tx))
+}
+
+// copyBlock returns a deep copy of the block, so callers can't modify the
+// blocks of the chain.
+func copyBlock(block *wire.MsgBlock) *wire.MsgBlock {
+	var buf bytes.Buffer
+	if err := block.Serialize(&buf); err != nil {
+		panic(err)
+	}
+	var blockCopy wire.MsgBlock
+	if err := blockCopy.Deserialize(&buf); err != nil {
+		panic(err)
+	}
+	return &blockCopy
+}
@@ -0,0 +1,499 @@
+package chaintest
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChain(t *testing.T) *Chain {
+	t.Helper()
+
+	c, err := NewChain(&Config{ChainParams: &chaincfg.RegressionNetParams})
+	require.NoError(t, err)
+
+	// Mature the coinbase of the first block.
+	_, err = c.GenerateBlocks(
+		int(chaincfg.RegressionNetParams.CoinbaseMaturity) + 1,
+	)
+	require.NoError(t, err)
+
+	return c
+}
+
+func startClient(t *testing.T, c *Chain) *Client {
+	t.Helper()
+
+	client := c.NewClient()
+	require.NoError(t, client.Start())
+	t.Cleanup(client.Stop)
+
+	require.IsType(t, chain.ClientConnected{}, nextNotification(t, client))
+	return client
+}
+
+// testScript returns a witness script anyone can spend with a witness only
+// containing the script, which differs for each b from 1 to 15.
+func testScript(b byte) []byte {
+	return []byte{txscript.OP_1 + b}
+}
+
+// testAddr returns the P2WSH address of testScript(b) and its output script.
+func testAddr(t *testing.T, b byte) (btcutil.Address, []byte) {
+	t.Helper()
+
+	scriptHash := sha256.Sum256(testScript(b))
+	addr, err := btcutil.NewAddressWitnessScriptHash(
+		scriptHash[:], &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	return addr, pkScript
+}
+
+func nextNotification(t *testing.T, client *Client) interface{} {
+	t.Helper()
+
+	select {
+	case n := <-client.Notifications():
+		return n
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+		return nil
+	}
+}
+
+func requireRelevantTx(t *testing.T, n interface{}, tx *wire.MsgTx,
+	block *wire.MsgBlock) {
+
+	t.Helper()
+
+	relevant, ok := n.(chain.RelevantTx)
+	require.True(t, ok, "expected RelevantTx, got %T", n)
+	require.Equal(t, tx.TxHash(), relevant.TxRecord.Hash)
+	if block == nil {
+		require.Nil(t, relevant.Block)
+		return
+	}
+	require.NotNil(t, relevant.Block)
+	require.Equal(t, block.BlockHash(), relevant.Block.Hash)
+}
+
+func requireBlock(t *testing.T, n interface{}, connected bool,
+	block *wire.MsgBlock, height int32) {
+
+	t.Helper()
+
+	var meta wtxmgr.BlockMeta
+	if connected {
+		ntfn, ok := n.(chain.BlockConnected)
+		require.True(t, ok, "expected BlockConnected, got %T", n)
+		meta = wtxmgr.BlockMeta(ntfn)
+	} else {
+		ntfn, ok := n.(chain.BlockDisconnected)
+		require.True(t, ok, "expected BlockDisconnected, got %T", n)
+		meta = wtxmgr.BlockMeta(ntfn)
+	}
+	require.Equal(t, block.BlockHash(), meta.Hash)
+	require.Equal(t, height, meta.Height)
+	require.Equal(t, block.Header.Timestamp, meta.Time)
+}
+
+// spendTx returns a transaction spending the anyone-can-spend output to the
+// output script.
+func spendTx(prev *wire.MsgTx, index uint32, pkScript []byte,
+	value int64) *wire.MsgTx {
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  prev.TxHash(),
+			Index: index,
+		},
+		Witness: wire.TxWitness{anyoneCanSpendScript},
+	})
+	tx.AddTxOut(wire.NewTxOut(value, pkScript))
+	return tx
+}
+
+// TestChainDeterministic tests that chains mining the same transactions
+// consist of the same blocks.
+func TestChainDeterministic(t *testing.T) {
+	t.Parallel()
+
+	_, pkScript := testAddr(t, 1)
+	build := func() *Chain {
+		c := newTestChain(t)
+		_, err := c.SendOutputs(wire.NewTxOut(1e8, pkScript))
+		require.NoError(t, err)
+		_, err = c.GenerateBlocks(2)
+		require.NoError(t, err)
+		return c
+	}
+
+	c1, c2 := build(), build()
+	hash1, height1 := c1.BestBlock()
+	hash2, height2 := c2.BestBlock()
+	require.Equal(t, *hash1, *hash2)
+	require.Equal(t, height1, height2)
+	require.EqualValues(t, 103, height1)
+
+	block, err := c1.BlockAt(1)
+	require.NoError(t, err)
+	require.Equal(t,
+		chaincfg.RegressionNetParams.GenesisBlock.Header.Timestamp.Add(
+			BlockInterval,
+		), block.Header.Timestamp,
+	)
+}
+
+// TestChainNotifications tests that clients are notified of relevant
+// transactions when they are accepted into the mempool and mined, and of the
+// blocks connected to the best chain.
+func TestChainNotifications(t *testing.T) {
+	t.Parallel()
+
+	c := newTestChain(t)
+	addr, pkScript := testAddr(t, 1)
+	_, otherPkScript := testAddr(t, 2)
+
+	client := startClient(t, c)
+	require.Equal(t, "chaintest", client.BackEnd())
+	require.NoError(t, client.NotifyBlocks())
+	require.NoError(t, client.NotifyReceived([]btcutil.Address{addr}))
+
+	pay, err := c.SendOutputs(wire.NewTxOut(1e8, pkScript))
+	require.NoError(t, err)
+	requireRelevantTx(t, nextNotification(t, client), pay, nil)
+
+	// Unrelated transactions are not notified.
+	_, err = c.SendOutputs(wire.NewTxOut(1e8, otherPkScript))
+	require.NoError(t, err)
+	require.Len(t, c.Mempool(), 2)
+
+	blocks, err := c.GenerateBlocks(1)
+	require.NoError(t, err)
+	require.Len(t, blocks[0].Transactions, 3)
+	require.Empty(t, c.Mempool())
+	requireRelevantTx(t, nextNotification(t, client), pay, blocks[0])
+	requireBlock(t, nextNotification(t, client), true, blocks[0], 102)
+
+	payHash := pay.TxHash()
+	confs, err := c.Confirmations(&payHash)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, confs)
+
+	// Spends of the outputs paying to the watched addresses are
+	// notified.
+	spend := wire.NewMsgTx(2)
+	spend.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: payHash}, nil,
+		wire.TxWitness{testScript(1)},
+	))
+	spend.AddTxOut(wire.NewTxOut(1e8-1000, otherPkScript))
+	block, err := c.GenerateBlock(spend)
+	require.NoError(t, err)
+	requireRelevantTx(t, nextNotification(t, client), spend, block)
+	requireBlock(t, nextNotification(t, client), true, block, 103)
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHash(), bs.Hash)
+	require.EqualValues(t, 103, bs.Height)
+
+	hash, height, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHash(), *hash)
+	require.EqualValues(t, 103, height)
+
+	height, err = client.GetBlockHeight(hash)
+	require.NoError(t, err)
+	require.EqualValues(t, 103, height)
+
+	header, err := client.GetBlockHeader(hash)
+	require.NoError(t, err)
+	require.Equal(t, block.Header, *header)
+
+	hash, err = client.GetBlockHash(102)
+	require.NoError(t, err)
+	require.Equal(t, blocks[0].BlockHash(), *hash)
+
+	// Stopped clients are no longer notified.
+	client.Stop()
+	client.WaitForShutdown()
+	_, err = c.GenerateBlocks(1)
+	require.NoError(t, err)
+}
+
+// TestChainPolicy tests that transactions violating the mempool policy are
+// rejected with the errors of the chain package.
+func TestChainPolicy(t *testing.T) {
+	t.Parallel()
+
+	c := newTestChain(t)
+	client := startClient(t, c)
+	_, pkScript := testAddr(t, 1)
+
+	coinbase, err := c.BlockAt(1)
+	require.NoError(t, err)
+	coinbaseTx := coinbase.Transactions[0]
+	value := coinbaseTx.TxOut[0].Value
+
+	immature, err := c.BlockAt(101)
+	require.NoError(t, err)
+
+	missing := spendTx(coinbaseTx, 0, pkScript, value-1000)
+	missing.TxIn[0].PreviousOutPoint.Index = 5
+
+	badScript := spendTx(coinbaseTx, 0, pkScript, value-1000)
+	badScript.TxIn[0].Witness = wire.TxWitness{{txscript.OP_FALSE}}
+
+	tests := []struct {
+		name string
+		tx   *wire.MsgTx
+		err  error
+	}{{
+		name: "coinbase",
+		tx:   coinbaseTx,
+		err:  chain.ErrCoinbaseTx,
+	}, {
+		name: "missing inputs",
+		tx:   missing,
+		err:  chain.ErrMissingInputs,
+	}, {
+		name: "immature coinbase",
+		tx: spendTx(
+			immature.Transactions[0], 0, pkScript, value-1000,
+		),
+		err: ErrPrematureSpend,
+	}, {
+		name: "dust",
+		tx:   spendTx(coinbaseTx, 0, pkScript, 100),
+		err:  chain.ErrDust,
+	}, {
+		name: "below min relay fee",
+		tx:   spendTx(coinbaseTx, 0, pkScript, value-10),
+		err:  chain.ErrMempoolMinFeeNotMet,
+	}, {
+		name: "above input value",
+		tx:   spendTx(coinbaseTx, 0, pkScript, value+1),
+		err:  chain.ErrBelowOutValue,
+	}, {
+		name: "invalid script",
+		tx:   badScript,
+		err:  chain.ErrScriptVerifyFlag,
+	}, {
+		name: "non-standard output",
+		tx: spendTx(
+			coinbaseTx, 0, []byte{txscript.OP_TRUE}, value-1000,
+		),
+		err: chain.ErrNonStandardScript,
+	}}
+	for _, test := range tests {
+		_, err := client.SendRawTransaction(test.tx, false)
+		require.ErrorIs(t, err, test.err, test.name)
+		if _, ok := test.err.(chain.RPCErr); ok {
+			require.ErrorIs(
+				t, client.MapRPCErr(err), test.err, test.name,
+			)
+		}
+	}
+	require.Empty(t, c.Mempool())
+
+	// A valid transaction is accepted once, and conflicting
+	// transactions are rejected.
+	valid := spendTx(coinbaseTx, 0, pkScript, value-1000)
+	results, err := client.TestMempoolAccept([]*wire.MsgTx{valid}, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.True(t, results[0].Allowed)
+	require.Equal(t, 1000e-8, results[0].Fees.Base)
+
+	txHash, err := client.SendRawTransaction(valid, false)
+	require.NoError(t, err)
+	require.Equal(t, valid.TxHash(), *txHash)
+
+	_, err = client.SendRawTransaction(valid, false)
+	require.ErrorIs(t, err, chain.ErrTxAlreadyInMempool)
+
+	conflict := spendTx(coinbaseTx, 0, pkScript, value-2000)
+	_, err = client.SendRawTransaction(conflict, false)
+	require.ErrorIs(t, err, chain.ErrMempoolConflict)
+
+	results, err = client.TestMempoolAccept(
+		[]*wire.MsgTx{conflict, valid}, 0,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.False(t, results[0].Allowed)
+	require.Contains(t, results[0].RejectReason, "mempool conflict")
+
+	// Mined transactions are rejected as confirmed.
+	_, err = c.GenerateBlocks(1)
+	require.NoError(t, err)
+	_, err = client.SendRawTransaction(valid, false)
+	require.ErrorIs(t, err, chain.ErrTxAlreadyConfirmed)
+
+	// Blocks can't contain transactions spending missing outputs.
+	_, err = c.GenerateBlock(conflict)
+	require.ErrorIs(t, err, chain.ErrMissingInputsOrSpent)
+
+	// SendOutputs fails once the mature coinbase outputs are spent.
+	_, err = c.SendOutputs(wire.NewTxOut(100e8, pkScript))
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+// TestChainReorg tests that reorganizations notify the disconnected and the
+// new blocks, and return the transactions of the disconnected blocks to the
+// mempool unless they conflict with the new blocks.
+func TestChainReorg(t *testing.T) {
+	t.Parallel()
+
+	c := newTestChain(t)
+	addr, pkScript := testAddr(t, 1)
+	_, otherPkScript := testAddr(t, 2)
+	client := startClient(t, c)
+	require.NoError(t, client.NotifyBlocks())
+	require.NoError(t, client.NotifyReceived([]btcutil.Address{addr}))
+
+	// The payment spends the second coinbase, as SendOutputs spends the
+	// first one.
+	coinbase, err := c.BlockAt(2)
+	require.NoError(t, err)
+	value := coinbase.Transactions[0].TxOut[0].Value
+	pay := spendTx(coinbase.Transactions[0], 0, pkScript, value-1000)
+	kept, err := c.SendOutputs(wire.NewTxOut(1e8, pkScript))
+	require.NoError(t, err)
+	requireRelevantTx(t, nextNotification(t, client), kept, nil)
+
+	block102, err := c.GenerateBlock(pay, kept)
+	require.NoError(t, err)
+	requireRelevantTx(t, nextNotification(t, client), pay, block102)
+	requireRelevantTx(t, nextNotification(t, client), kept, block102)
+	requireBlock(t, nextNotification(t, client), true, block102, 102)
+
+	block103, err := c.GenerateBlock()
+	require.NoError(t, err)
+	requireBlock(t, nextNotification(t, client), true, block103, 103)
+
+	// A reorg must lead to a longer chain.
+	_, err = c.Reorg(2, nil, nil)
+	require.Error(t, err)
+
+	// Replace both blocks, double spending the payment in the first new
+	// block.
+	conflict := spendTx(
+		coinbase.Transactions[0], 0, otherPkScript, value-2000,
+	)
+	blocks, err := c.Reorg(2, []*wire.MsgTx{conflict}, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+
+	requireBlock(t, nextNotification(t, client), false, block103, 103)
+	requireBlock(t, nextNotification(t, client), false, block102, 102)
+	requireBlock(t, nextNotification(t, client), true, blocks[0], 102)
+	requireBlock(t, nextNotification(t, client), true, blocks[1], 103)
+	requireBlock(t, nextNotification(t, client), true, blocks[2], 104)
+
+	// The double spent payment is gone, while the other transaction is
+	// back in the mempool.
+	payHash, keptHash := pay.TxHash(), kept.TxHash()
+	_, err = c.Confirmations(&payHash)
+	require.Error(t, err)
+	confs, err := c.Confirmations(&keptHash)
+	require.NoError(t, err)
+	require.Zero(t, confs)
+
+	// The disconnected blocks can still be fetched.
+	hash := block102.BlockHash()
+	block, err := client.GetBlock(&hash)
+	require.NoError(t, err)
+	require.Equal(t, hash, block.BlockHash())
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, waddrmgr.BlockStamp{
+		Hash:      blocks[2].BlockHash(),
+		Height:    104,
+		Timestamp: blocks[2].Header.Timestamp,
+	}, *bs)
+}
+
+// TestClientRescan tests that rescans notify the relevant transactions of the
+// best chain and the mempool, and that FilterBlocks finds the blocks paying
+// to the requested addresses.
+func TestClientRescan(t *testing.T) {
+	t.Parallel()
+
+	c := newTestChain(t)
+	addr, pkScript := testAddr(t, 1)
+	_, otherPkScript := testAddr(t, 2)
+
+	start, _ := c.BestBlock()
+	pay, err := c.SendOutputs(wire.NewTxOut(1e8, pkScript))
+	require.NoError(t, err)
+	_, err = c.SendOutputs(wire.NewTxOut(1e8, otherPkScript))
+	require.NoError(t, err)
+	blocks, err := c.GenerateBlocks(2)
+	require.NoError(t, err)
+	unconfirmed, err := c.SendOutputs(wire.NewTxOut(2e8, pkScript))
+	require.NoError(t, err)
+
+	client := startClient(t, c)
+	err = client.Rescan(start, []btcutil.Address{addr}, nil)
+	require.NoError(t, err)
+
+	requireRelevantTx(t, nextNotification(t, client), pay, blocks[0])
+	requireRelevantTx(t, nextNotification(t, client), unconfirmed, nil)
+	finished, ok := nextNotification(t, client).(*chain.RescanFinished)
+	require.True(t, ok)
+	require.Equal(t, blocks[1].BlockHash(), *finished.Hash)
+	require.EqualValues(t, 103, finished.Height)
+
+	// Rescanning from a block that is not part of the best chain fails.
+	require.Error(t, client.Rescan(&chainhash.Hash{}, nil, nil))
+
+	var reqBlocks []wtxmgr.BlockMeta
+	for i, block := range blocks {
+		reqBlocks = append(reqBlocks, *blockMeta(block, 102+int32(i)))
+	}
+	scopedIndex := waddrmgr.ScopedIndex{
+		Scope: waddrmgr.KeyScopeBIP0084,
+		Index: 7,
+	}
+	resp, err := client.FilterBlocks(&chain.FilterBlocksRequest{
+		Blocks: reqBlocks,
+		ExternalAddrs: map[waddrmgr.ScopedIndex]btcutil.Address{
+			scopedIndex: addr,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Zero(t, resp.BatchIndex)
+	require.Len(t, resp.RelevantTxns, 1)
+	require.Equal(t, pay.TxHash(), resp.RelevantTxns[0].TxHash())
+	require.Contains(
+		t, resp.FoundExternalAddrs[waddrmgr.KeyScopeBIP0084], uint32(7),
+	)
+
+	resp, err = client.FilterBlocks(&chain.FilterBlocksRequest{
+		Blocks: reqBlocks[1:],
+		ExternalAddrs: map[waddrmgr.ScopedIndex]btcutil.Address{
+			scopedIndex: addr,
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
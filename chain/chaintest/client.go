@@ -0,0 +1,469 @@
+package chaintest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// Client is a client of a Chain implementing the chain.Interface interface.
+// Its notifications are queued while the chain changes, so they are delivered
+// in order and are complete once the method changing the chain returned.
+type Client struct {
+	chain *Chain
+
+	notificationQueue *chain.ConcurrentQueue
+
+	// The following fields are guarded by the chain mutex.
+	started          bool
+	notifyBlocks     bool
+	watchedScripts   map[string]struct{}
+	watchedOutPoints map[wire.OutPoint]struct{}
+	bestBlock        waddrmgr.BlockStamp
+
+	stopOnce sync.Once
+	quit     chan struct{}
+}
+
+// A compile-time check to ensure that Client satisfies the chain.Interface
+// interface.
+var _ chain.Interface = (*Client)(nil)
+
+// BackEnd returns the name of the driver.
+func (c *Client) BackEnd() string {
+	return "chaintest"
+}
+
+// Start starts the client, which is notified of the changes of the chain from
+// now on.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) Start() error {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	select {
+	case <-c.quit:
+		return errors.New("client is stopped")
+	default:
+	}
+	if c.started {
+		return nil
+	}
+
+	c.notificationQueue.Start()
+	c.started = true
+	c.chain.clients[c] = struct{}{}
+
+	height := int32(len(c.chain.best) - 1)
+	tip := c.chain.best[height]
+	c.bestBlock = waddrmgr.BlockStamp{
+		Hash:      tip.BlockHash(),
+		Height:    height,
+		Timestamp: tip.Header.Timestamp,
+	}
+	c.notify(chain.ClientConnected{})
+
+	return nil
+}
+
+// Stop stops the client.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) Stop() {
+	c.stopOnce.Do(func() {
+		c.chain.mtx.Lock()
+		delete(c.chain.clients, c)
+		c.chain.mtx.Unlock()
+
+		close(c.quit)
+		c.notificationQueue.Stop()
+	})
+}
+
+// WaitForShutdown blocks until the client is stopped.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) WaitForShutdown() {
+	<-c.quit
+}
+
+// Notifications returns a channel to retrieve notifications from.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) Notifications() <-chan interface{} {
+	return c.notificationQueue.ChanOut()
+}
+
+// GetBestBlock returns the hash and height of the tip of the best chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) GetBestBlock() (*chainhash.Hash, int32, error) {
+	hash, height := c.chain.BestBlock()
+	return hash, height, nil
+}
+
+// GetBlock returns the block with the given hash, which doesn't need to be
+// part of the best chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	block, ok := c.chain.blocks[*hash]
+	if !ok {
+		return nil, fmt.Errorf("block %v not found", hash)
+	}
+	return copyBlock(block), nil
+}
+
+// GetBlockHash returns the hash of the block of the best chain at the given
+// height.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	if height < 0 || height >= int64(len(c.chain.best)) {
+		return nil, fmt.Errorf("block height %d out of range", height)
+	}
+	hash := c.chain.best[height].BlockHash()
+	return &hash, nil
+}
+
+// GetBlockHeader returns the header of the block with the given hash.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	block, ok := c.chain.blocks[*hash]
+	if !ok {
+		return nil, fmt.Errorf("block %v not found", hash)
+	}
+	header := block.Header
+	return &header, nil
+}
+
+// GetBlockHeight returns the height of the block with the given hash.
+func (c *Client) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	height, ok := c.chain.heights[*hash]
+	if !ok {
+		return 0, fmt.Errorf("block %v not found", hash)
+	}
+	return height, nil
+}
+
+// IsCurrent returns whether the chain is set to be current.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) IsCurrent() bool {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	return c.chain.current
+}
+
+// BlockStamp returns the latest block notified to the client.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	bestBlock := c.bestBlock
+	return &bestBlock, nil
+}
+
+// SendRawTransaction accepts the transaction into the mempool of the chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) SendRawTransaction(tx *wire.MsgTx,
+	_ bool) (*chainhash.Hash, error) {
+
+	if err := c.chain.AcceptTx(tx); err != nil {
+		return nil, err
+	}
+	txHash := tx.TxHash()
+	return &txHash, nil
+}
+
+// TestMempoolAccept checks whether the transactions would be accepted into the
+// mempool.  Like bitcoind, the result of transactions after the first one
+// rejected is not reported.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) TestMempoolAccept(txns []*wire.MsgTx,
+	_ float64) ([]*btcjson.TestMempoolAcceptResult, error) {
+
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	results := make([]*btcjson.TestMempoolAcceptResult, 0, len(txns))
+	for _, tx := range txns {
+		result := &btcjson.TestMempoolAcceptResult{
+			Txid:  tx.TxHash().String(),
+			Wtxid: tx.WitnessHash().String(),
+		}
+		results = append(results, result)
+
+		fee, err := c.chain.checkTx(tx)
+		if err != nil {
+			result.RejectReason = err.Error()
+			break
+		}
+		result.Allowed = true
+		result.Vsize = int32(txVirtualSize(tx))
+		result.Fees = &btcjson.TestMempoolAcceptFees{
+			Base: fee.ToBTC(),
+		}
+	}
+
+	return results, nil
+}
+
+// MapRPCErr returns the error of the chain package wrapped by the error, or
+// wraps it with ErrUndefined.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) MapRPCErr(err error) error {
+	var rpcErr chain.RPCErr
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return fmt.Errorf("%w: %v", chain.ErrUndefined, err)
+}
+
+// NotifyReceived watches the addresses for transactions paying to them, and
+// the outputs paying to them for spends.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) NotifyReceived(addrs []btcutil.Address) error {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	return c.watchAddrs(addrs)
+}
+
+// NotifyBlocks enables block notifications.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) NotifyBlocks() error {
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	c.notifyBlocks = true
+	return nil
+}
+
+// Rescan notifies the transactions of the best chain after the start block
+// and of the mempool that pay to the addresses or spend the outpoints, which
+// are watched from now on.  A RescanFinished notification for the tip of the
+// best chain follows them.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	c.chain.mtx.Lock()
+	defer c.chain.mtx.Unlock()
+
+	if !c.started {
+		return errors.New("can't do a rescan when the chain client " +
+			"is not started")
+	}
+
+	startHeight, ok := c.chain.heights[*startHash]
+	if !ok || int(startHeight) >= len(c.chain.best) ||
+		c.chain.best[startHeight].BlockHash() != *startHash {
+
+		return fmt.Errorf("block %v is not part of the best chain",
+			startHash)
+	}
+
+	if err := c.watchAddrs(addrs); err != nil {
+		return err
+	}
+	for op := range outPoints {
+		c.watchedOutPoints[op] = struct{}{}
+	}
+
+	for height := startHeight + 1; int(height) < len(c.chain.best); height++ {
+		block := c.chain.best[height]
+		meta := blockMeta(block, height)
+		for _, tx := range block.Transactions {
+			c.notifyTx(tx, meta)
+		}
+	}
+	for _, tx := range c.chain.mempool {
+		c.notifyTx(tx, nil)
+	}
+
+	height := int32(len(c.chain.best) - 1)
+	tip := c.chain.best[height]
+	tipHash := tip.BlockHash()
+	c.bestBlock = waddrmgr.BlockStamp{
+		Hash:      tipHash,
+		Height:    height,
+		Timestamp: tip.Header.Timestamp,
+	}
+	c.notify(&chain.RescanFinished{
+		Hash:   &tipHash,
+		Height: height,
+		Time:   tip.Header.Timestamp,
+	})
+
+	return nil
+}
+
+// FilterBlocks scans the blocks contained in the FilterBlocksRequest for any
+// addresses of interest.  This method returns a FilterBlocksResponse for the
+// first block containing a matching address.  If no matches are found in the
+// range of blocks requested, the returned response will be nil.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *Client) FilterBlocks(
+	req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+
+	blockFilterer := chain.NewBlockFilterer(c.chain.cfg.ChainParams, req)
+
+	for i, block := range req.Blocks {
+		rawBlock, err := c.GetBlock(&block.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if !blockFilterer.FilterBlock(rawBlock) {
+			continue
+		}
+
+		return &chain.FilterBlocksResponse{
+			BatchIndex:         uint32(i),
+			BlockMeta:          block,
+			FoundExternalAddrs: blockFilterer.FoundExternal,
+			FoundInternalAddrs: blockFilterer.FoundInternal,
+			FoundOutPoints:     blockFilterer.FoundOutPoints,
+			RelevantTxns:       blockFilterer.RelevantTxns,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// watchAddrs adds the output scripts of the addresses to the watched scripts.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Client) watchAddrs(addrs []btcutil.Address) error {
+	for _, addr := range addrs {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return fmt.Errorf("unable to watch address %v: %w",
+				addr, err)
+		}
+		c.watchedScripts[string(pkScript)] = struct{}{}
+	}
+	return nil
+}
+
+// connectBlock notifies the relevant transactions of the block connected to
+// the best chain at the given height, followed by the block itself if block
+// notifications are enabled.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Client) connectBlock(block *wire.MsgBlock, height int32) {
+	meta := blockMeta(block, height)
+	for _, tx := range block.Transactions {
+		c.notifyTx(tx, meta)
+	}
+
+	c.bestBlock = waddrmgr.BlockStamp{
+		Hash:      meta.Hash,
+		Height:    height,
+		Timestamp: meta.Time,
+	}
+	if c.notifyBlocks {
+		c.notify(chain.BlockConnected(*meta))
+	}
+}
+
+// disconnectBlock notifies that the block at the given height was
+// disconnected from the best chain if block notifications are enabled.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Client) disconnectBlock(block *wire.MsgBlock, height int32) {
+	prev := c.chain.best[height-1]
+	c.bestBlock = waddrmgr.BlockStamp{
+		Hash:      prev.BlockHash(),
+		Height:    height - 1,
+		Timestamp: prev.Header.Timestamp,
+	}
+	if c.notifyBlocks {
+		c.notify(chain.BlockDisconnected(*blockMeta(block, height)))
+	}
+}
+
+// notifyTx notifies the transaction if it pays to a watched script or spends a
+// watched outpoint.  The outputs paying to watched scripts are watched from
+// now on.
+//
+// NOTE: The caller must hold the chain mutex.
+func (c *Client) notifyTx(tx *wire.MsgTx, block *wtxmgr.BlockMeta) {
+	relevant := false
+	for _, in := range tx.TxIn {
+		if _, ok := c.watchedOutPoints[in.PreviousOutPoint]; ok {
+			relevant = true
+			break
+		}
+	}
+
+	txHash := tx.TxHash()
+	for i, out := range tx.TxOut {
+		if _, ok := c.watchedScripts[string(out.PkScript)]; !ok {
+			continue
+		}
+		relevant = true
+		op := wire.OutPoint{Hash: txHash, Index: uint32(i)}
+		c.watchedOutPoints[op] = struct{}{}
+	}
+	if !relevant {
+		return
+	}
+
+	var received time.Time
+	if block != nil {
+		received = block.Time
+	} else {
+		received = c.bestBlock.Timestamp
+	}
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx.Copy(), received)
+	if err != nil {
+		panic(err)
+	}
+	c.notify(chain.RelevantTx{TxRecord: rec, Block: block})
+}
+
+// notify queues a notification unless the client is stopped.
+func (c *Client) notify(n interface{}) {
+	select {
+	case c.notificationQueue.ChanIn() <- n:
+	case <-c.quit:
+	}
+}
@@ -0,0 +1,16 @@
+// Package chaintest provides a deterministic in-memory block chain with a
+// mempool, and clients of it implementing the chain.Interface interface, so
+// wallets can be tested end to end without running a chain backend.
+//
+// Blocks are mined with the given transactions or the transactions of the
+// mempool, and reorganizations of any depth can be triggered.  Transactions
+// broadcast by the clients are accepted into the mempool by checks following
+// the standardness policy of bitcoind, and rejected with the errors defined in
+// the chain package.  The clients emit the same notifications as the other
+// chain backends, and support rescans and block filtering.
+//
+// The chain is deterministic: mining the same transactions always results in
+// the same blocks, which are timestamped at fixed intervals.  Coinbase outputs
+// pay to a script anyone can spend, and SendOutputs funds transactions from
+// them once they are mature.
+package chaintest
@@ -0,0 +1,1758 @@
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// electrumProtocolVersion is the version of the Electrum protocol
+	// negotiated with the server.
+	electrumProtocolVersion = "1.4"
+
+	// electrumClientName is the client name sent to the server.
+	electrumClientName = "btcwallet"
+
+	// electrumRequestTimeout is the default timeout of a single request.
+	electrumRequestTimeout = 30 * time.Second
+
+	// electrumPingInterval is the default interval at which the server is
+	// pinged to keep the connection alive.
+	electrumPingInterval = time.Minute
+
+	// electrumMaxHeaders is the maximum number of headers requested at
+	// once, which is the limit of most servers.
+	electrumMaxHeaders = 2016
+
+	// electrumReorgDepth is the number of headers of the best chain that
+	// are kept to detect reorganizations.
+	electrumReorgDepth = 144
+
+	// electrumHeaderCacheSize is the maximum number of headers cached for
+	// lookups by hash.
+	electrumHeaderCacheSize = 10000
+
+	// electrumMaxConcurrentRequests is the maximum number of requests for
+	// scripthash histories, transactions and merkle proofs that are in
+	// flight at once.
+	electrumMaxConcurrentRequests = 8
+)
+
+// ElectrumConfig contains the configuration of an ElectrumClient.
+type ElectrumConfig struct {
+	// Server is the host and port of the Electrum server.
+	Server string
+
+	// ChainParams are the parameters of the network the server serves.
+	ChainParams *chaincfg.Params
+
+	// TLS enables TLS on the connection to the server.
+	TLS bool
+
+	// TLSConfig is the TLS configuration used when TLS is enabled.  If
+	// nil, the server's certificate is verified against the system roots.
+	TLSConfig *tls.Config
+
+	// Dialer is used to open the connection to the server, for example
+	// through a proxy.  If nil, a plain TCP connection is opened.
+	Dialer func(network, addr string) (net.Conn, error)
+
+	// RequestTimeout is the timeout of a single request.  A 30 second
+	// timeout is used if zero.
+	RequestTimeout time.Duration
+
+	// PingInterval is the interval at which the server is pinged to keep
+	// the connection alive.  The server is pinged every minute if zero.
+	PingInterval time.Duration
+}
+
+// electrumRequest is a JSON-RPC request sent to the server.
+type electrumRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// electrumResponse is a JSON-RPC message received from the server, either the
+// response to a request or a subscription notification.
+type electrumResponse struct {
+	ID     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// electrumError is an error returned by the server.
+type electrumError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the error message of the server.
+func (e *electrumError) Error() string {
+	return fmt.Sprintf("electrum error %d: %s", e.Code, e.Message)
+}
+
+// electrumHeaderNtfn is the tip of the server's best chain, as returned by
+// blockchain.headers.subscribe.
+type electrumHeaderNtfn struct {
+	Height int32  `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+// electrumStatusNtfn notifies that the history of a scripthash changed.
+type electrumStatusNtfn struct {
+	scriptHash string
+}
+
+// electrumHeaders is the result of blockchain.block.headers.
+type electrumHeaders struct {
+	Count int    `json:"count"`
+	Hex   string `json:"hex"`
+}
+
+// electrumHistoryTx is an entry of the result of
+// blockchain.scripthash.get_history.  The height of mempool transactions is
+// zero, or -1 if they spend unconfirmed outputs.
+type electrumHistoryTx struct {
+	TxHash string `json:"tx_hash"`
+	Height int32  `json:"height"`
+}
+
+// electrumMerkleProof is the result of blockchain.transaction.get_merkle.
+type electrumMerkleProof struct {
+	BlockHeight int32    `json:"block_height"`
+	Merkle      []string `json:"merkle"`
+	Pos         uint32   `json:"pos"`
+}
+
+// electrumTxFromPos is the result of blockchain.transaction.id_from_pos with
+// a merkle branch requested.
+type electrumTxFromPos struct {
+	TxHash string   `json:"tx_hash"`
+	Merkle []string `json:"merkle"`
+}
+
+// electrumRescan is a rescan request handled by the client's notification
+// goroutine.
+type electrumRescan struct {
+	start chainhash.Hash
+}
+
+// electrumCachedHeader is a header cached for lookups by hash.
+type electrumCachedHeader struct {
+	header wire.BlockHeader
+	height int32
+}
+
+// ElectrumClient is an implementation of the chain.Interface interface backed
+// by a server speaking the Electrum protocol, such as electrs or ElectrumX.
+// Relevant transactions are found by subscribing to the scripthashes of the
+// watched addresses and querying their histories, and are only notified as
+// confirmed once their merkle proof was checked against the block header.
+// Full blocks can not be fetched from an Electrum server.
+type ElectrumClient struct {
+	cfg  ElectrumConfig
+	conn net.Conn
+
+	writeMtx sync.Mutex
+
+	requestID  atomic.Uint64
+	pendingMtx sync.Mutex
+	pending    map[uint64]chan *electrumResponse
+
+	notificationQueue *ConcurrentQueue
+	serverNtfns       *ConcurrentQueue
+	rescanRequests    chan *electrumRescan
+	notifyBlocks      atomic.Bool
+
+	// watchMtx guards the watched scripthashes, mapped to the height of
+	// the best block when they were subscribed.  Transactions confirmed
+	// at or below that height are only notified by rescans.
+	watchMtx sync.Mutex
+	watched  map[string]int32
+
+	// headerMtx guards the best block, the headers of the best chain down
+	// to electrumReorgDepth blocks below it, and the header cache.
+	headerMtx   sync.RWMutex
+	bestBlock   waddrmgr.BlockStamp
+	chain       map[int32]*wire.BlockHeader
+	headerCache map[chainhash.Hash]*electrumCachedHeader
+
+	// notified records the transactions that have already been notified,
+	// and the hash of the block they were notified in or the zero hash for
+	// mempool transactions.  deferred contains the scripthashes with
+	// transactions confirmed in blocks that are not connected yet.  Both
+	// are only accessed by the notification goroutine.
+	notified map[chainhash.Hash]chainhash.Hash
+	deferred map[string]struct{}
+
+	started atomic.Bool
+	stopped atomic.Bool
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// A compile-time check to ensure that ElectrumClient satisfies the
+// chain.Interface interface.
+var _ Interface = (*ElectrumClient)(nil)
+
+// NewElectrumClient creates a client for the Electrum server described by the
+// config.  The connection is opened when the client is started.
+func NewElectrumClient(cfg *ElectrumConfig) (*ElectrumClient, error) {
+	if cfg.Server == "" {
+		return nil, errors.New("missing electrum server address")
+	}
+	if cfg.ChainParams == nil {
+		return nil, errors.New("missing chain params config")
+	}
+
+	c := &ElectrumClient{
+		cfg:               *cfg,
+		pending:           make(map[uint64]chan *electrumResponse),
+		notificationQueue: NewConcurrentQueue(20),
+		serverNtfns:       NewConcurrentQueue(20),
+		rescanRequests:    make(chan *electrumRescan),
+		watched:           make(map[string]int32),
+		chain:             make(map[int32]*wire.BlockHeader),
+		headerCache:       make(map[chainhash.Hash]*electrumCachedHeader),
+		notified:          make(map[chainhash.Hash]chainhash.Hash),
+		deferred:          make(map[string]struct{}),
+		quit:              make(chan struct{}),
+	}
+	if c.cfg.RequestTimeout == 0 {
+		c.cfg.RequestTimeout = electrumRequestTimeout
+	}
+	if c.cfg.PingInterval == 0 {
+		c.cfg.PingInterval = electrumPingInterval
+	}
+
+	return c, nil
+}
+
+// BackEnd returns the name of the driver.
+func (c *ElectrumClient) BackEnd() string {
+	return "electrum"
+}
+
+// Start connects to the server, verifies that it serves the expected network
+// and subscribes to new headers.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) Start() error {
+	if !c.started.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	if err := c.start(); err != nil {
+		c.Stop()
+		return err
+	}
+
+	return nil
+}
+
+func (c *ElectrumClient) start() error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+
+	// The queues are started before the connection is read from, so that
+	// notifications received while starting don't block the responses to
+	// our requests.
+	c.notificationQueue.Start()
+	c.serverNtfns.Start()
+
+	c.wg.Add(1)
+	go c.readHandler()
+
+	var version []string
+	err = c.call(
+		"server.version", &version, electrumClientName,
+		electrumProtocolVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to negotiate protocol version: %w",
+			err)
+	}
+	log.Infof("Connected to electrum server %v (%v)", c.cfg.Server,
+		version)
+
+	genesis, err := c.fetchHeader(0)
+	if err != nil {
+		return fmt.Errorf("unable to query genesis block: %w", err)
+	}
+	if genesis.BlockHash() != *c.cfg.ChainParams.GenesisHash {
+		return fmt.Errorf("expected network %v with genesis block %v, "+
+			"got genesis block %v", c.cfg.ChainParams.Name,
+			c.cfg.ChainParams.GenesisHash, genesis.BlockHash())
+	}
+
+	var tip electrumHeaderNtfn
+	if err := c.call("blockchain.headers.subscribe", &tip); err != nil {
+		return err
+	}
+
+	// Load the most recent headers of the best chain, so that
+	// reorganizations of blocks connected before the client was started
+	// are detected.
+	start := tip.Height - electrumReorgDepth + 1
+	if start < 0 {
+		start = 0
+	}
+	headers, err := c.fetchHeaders(start, tip.Height-start+1)
+	if err != nil {
+		return err
+	}
+	for i, header := range headers {
+		height := start + int32(i)
+		if i > 0 {
+			err := c.checkHeaderChain(height, header, headers[i-1])
+			if err != nil {
+				return err
+			}
+		}
+		c.connectHeader(height, header)
+	}
+
+	select {
+	case c.notificationQueue.ChanIn() <- ClientConnected{}:
+	case <-c.quit:
+		return errors.New("electrum client stopped")
+	}
+
+	c.wg.Add(1)
+	go c.ntfnHandler()
+
+	return nil
+}
+
+// Stop closes the connection to the server.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) Stop() {
+	if !c.stopped.CompareAndSwap(false, true) {
+		return
+	}
+
+	close(c.quit)
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.notificationQueue.Stop()
+	c.serverNtfns.Stop()
+}
+
+// WaitForShutdown blocks until the client has stopped.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// Notifications returns a channel to retrieve notifications from.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) Notifications() <-chan interface{} {
+	return c.notificationQueue.ChanOut()
+}
+
+// GetBestBlock returns the hash and height of the latest block notified by
+// the server.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	c.headerMtx.RLock()
+	defer c.headerMtx.RUnlock()
+
+	hash := c.bestBlock.Hash
+	return &hash, c.bestBlock.Height, nil
+}
+
+// GetBlockHeight returns the height of a block with the given hash.  Only the
+// blocks of the recent best chain and the blocks previously returned by
+// GetBlockHash are known, since the Electrum protocol can not look up blocks by
+// hash.
+func (c *ElectrumClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	cached, err := c.cachedHeader(hash)
+	if err != nil {
+		return 0, err
+	}
+	return cached.height, nil
+}
+
+// GetBlock is not supported by the Electrum protocol.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) GetBlock(*chainhash.Hash) (*wire.MsgBlock, error) {
+	return nil, ErrUnimplemented
+}
+
+// GetBlockHash returns the hash of the block at the given height of the best
+// chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	header, err := c.blockHeader(int32(height))
+	if err != nil {
+		return nil, err
+	}
+	hash := header.BlockHash()
+	return &hash, nil
+}
+
+// GetBlockHeader returns the header of the block with the given hash.  Only
+// the blocks of the recent best chain and the blocks previously returned by
+// GetBlockHash are known, since the Electrum protocol can not look up blocks by
+// hash.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	cached, err := c.cachedHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+	header := cached.header
+	return &header, nil
+}
+
+// IsCurrent returns whether the latest block notified by the server is
+// recent.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) IsCurrent() bool {
+	c.headerMtx.RLock()
+	defer c.headerMtx.RUnlock()
+
+	return c.bestBlock.Timestamp.After(time.Now().Add(-isCurrentDelta))
+}
+
+// BlockStamp returns the latest block notified by the server.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	c.headerMtx.RLock()
+	defer c.headerMtx.RUnlock()
+
+	bestBlock := c.bestBlock
+	return &bestBlock, nil
+}
+
+// SendRawTransaction broadcasts the transaction through the server.  High fees
+// can not be rejected by the server, so allowHighFees is ignored.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	var txid string
+	err := c.call(
+		"blockchain.transaction.broadcast", &txid,
+		hex.EncodeToString(buf.Bytes()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(txid)
+}
+
+// TestMempoolAccept is not supported by the Electrum protocol.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) TestMempoolAccept(txns []*wire.MsgTx,
+	maxFeeRate float64) ([]*btcjson.TestMempoolAcceptResult, error) {
+
+	return nil, ErrUnimplemented
+}
+
+// MapRPCErr maps an error returned by the server to an error defined in this
+// package.  Electrum servers relay the errors of the bitcoind node they are
+// backed by, so the bitcoind error strings are matched.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) MapRPCErr(rpcErr error) error {
+	for i := uint32(0); i < uint32(errSentinel); i++ {
+		err := RPCErr(i)
+		if matchErrStr(rpcErr, err.Error()) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %v", ErrUndefined, rpcErr)
+}
+
+// NotifyReceived subscribes to the scripthashes of the addresses.
+// Transactions paying to or spending from them are notified as they enter the
+// mempool or are confirmed.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) NotifyReceived(addrs []btcutil.Address) error {
+	return c.watchAddrs(addrs, true)
+}
+
+// NotifyBlocks enables BlockConnected notifications for new blocks.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) NotifyBlocks() error {
+	c.notifyBlocks.Store(true)
+	return nil
+}
+
+// Rescan subscribes to the scripthashes of the addresses and of the addresses
+// of the outpoints, and notifies all transactions relevant to them confirmed
+// from the block with the given hash up to the best block, followed by a
+// RescanFinished notification.  The rescan is performed asynchronously.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) Rescan(startHash *chainhash.Hash,
+	addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	if !c.started.Load() {
+		return errors.New("can't do a rescan when the chain client " +
+			"is not started")
+	}
+
+	watch := make([]btcutil.Address, 0, len(addrs)+len(outPoints))
+	watch = append(watch, addrs...)
+	for op, addr := range outPoints {
+		// Spends of an outpoint are part of the history of the
+		// script it pays to, so watching its address is sufficient.
+		if addr == nil {
+			log.Warnf("Unable to watch outpoint %v without an "+
+				"address", op)
+			continue
+		}
+		watch = append(watch, addr)
+	}
+	if err := c.watchAddrs(watch, false); err != nil {
+		return err
+	}
+
+	select {
+	case c.rescanRequests <- &electrumRescan{start: *startHash}:
+		return nil
+	case <-c.quit:
+		return errors.New("electrum client stopped")
+	}
+}
+
+// FilterBlocks scans the blocks contained in the FilterBlocksRequest for any
+// addresses of interest.  Rather than fetching each block, the histories of
+// the scripthashes of the requested addresses and outpoints are queried, and
+// only the transactions found in them are filtered after their merkle proofs
+// were checked.  This method returns a FilterBlocksResponse for the first
+// block containing a matching address.  If no matches are found in the range
+// of blocks requested, the returned response will be nil.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *ElectrumClient) FilterBlocks(
+	req *FilterBlocksRequest) (*FilterBlocksResponse, error) {
+
+	if len(req.Blocks) == 0 {
+		return nil, nil
+	}
+
+	heightIndex := make(map[int32]int, len(req.Blocks))
+	for i, block := range req.Blocks {
+		heightIndex[block.Height] = i
+	}
+
+	addrs := make([]btcutil.Address, 0, len(req.ExternalAddrs)+
+		len(req.InternalAddrs)+len(req.WatchedOutPoints))
+	for _, addr := range req.ExternalAddrs {
+		addrs = append(addrs, addr)
+	}
+	for _, addr := range req.InternalAddrs {
+		addrs = append(addrs, addr)
+	}
+	for _, addr := range req.WatchedOutPoints {
+		if addr != nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	scriptHashes, err := electrumScriptHashes(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := c.histories(scriptHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the transactions found by the index of their block within
+	// the request.
+	blockTxs := make(map[int]map[chainhash.Hash]struct{})
+	for _, history := range histories {
+		for _, tx := range history {
+			i, ok := heightIndex[tx.Height]
+			if tx.Height <= 0 || !ok {
+				continue
+			}
+			txHash, err := chainhash.NewHashFromStr(tx.TxHash)
+			if err != nil {
+				return nil, err
+			}
+			if blockTxs[i] == nil {
+				blockTxs[i] = make(map[chainhash.Hash]struct{})
+			}
+			blockTxs[i][*txHash] = struct{}{}
+		}
+	}
+
+	indexes := make([]int, 0, len(blockTxs))
+	for i := range blockTxs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	blockFilterer := NewBlockFilterer(c.cfg.ChainParams, req)
+	for _, i := range indexes {
+		block := req.Blocks[i]
+		header, err := c.blockHeader(block.Height)
+		if err != nil {
+			return nil, err
+		}
+		if header.BlockHash() != block.Hash {
+			return nil, fmt.Errorf("block %v at height %d is not "+
+				"part of the server's best chain", block.Hash,
+				block.Height)
+		}
+
+		txs, err := c.fetchVerifiedTxs(blockTxs[i], block.Height, header)
+		if err != nil {
+			return nil, err
+		}
+
+		var relevant bool
+		for _, tx := range sortTxsByDependency(txs) {
+			if blockFilterer.FilterTx(tx) {
+				blockFilterer.RelevantTxns = append(
+					blockFilterer.RelevantTxns, tx,
+				)
+				relevant = true
+			}
+		}
+		if !relevant {
+			continue
+		}
+
+		return &FilterBlocksResponse{
+			BatchIndex:         uint32(i),
+			BlockMeta:          block,
+			FoundExternalAddrs: blockFilterer.FoundExternal,
+			FoundInternalAddrs: blockFilterer.FoundInternal,
+			FoundOutPoints:     blockFilterer.FoundOutPoints,
+			RelevantTxns:       blockFilterer.RelevantTxns,
+		}, nil
+	}
+
+	// No addresses were found for this range.
+	return nil, nil
+}
+
+// ntfnHandler handles the notifications of the server, performs rescans and
+// keeps the connection alive.  Notifications and rescans are handled by the
+// same goroutine so they are dispatched in order.
+//
+// NOTE: This must be run as a goroutine.
+func (c *ElectrumClient) ntfnHandler() {
+	defer c.wg.Done()
+
+	ping := time.NewTicker(c.cfg.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case n := <-c.serverNtfns.ChanOut():
+			switch n := n.(type) {
+			case *electrumHeaderNtfn:
+				if err := c.syncChain(n); err != nil {
+					log.Errorf("Unable to sync to block "+
+						"%d: %v", n.Height, err)
+				}
+
+			case *electrumStatusNtfn:
+				err := c.processScripts([]string{n.scriptHash})
+				if err != nil {
+					log.Errorf("Unable to process history "+
+						"of scripthash %v: %v",
+						n.scriptHash, err)
+				}
+			}
+
+		case req := <-c.rescanRequests:
+			if err := c.rescan(req.start); err != nil {
+				log.Errorf("Unable to rescan from block %v: %v",
+					req.start, err)
+			}
+
+		case <-ping.C:
+			if err := c.call("server.ping", nil); err != nil {
+				log.Errorf("Unable to ping electrum server: %v",
+					err)
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// syncChain synchronizes the best chain of the client with the tip notified by
+// the server, notifying disconnected and connected blocks.
+func (c *ElectrumClient) syncChain(tip *electrumHeaderNtfn) error {
+	tipHeader, err := decodeElectrumHeader(tip.Hex)
+	if err != nil {
+		return err
+	}
+
+	c.headerMtx.RLock()
+	height := c.bestBlock.Height
+	c.headerMtx.RUnlock()
+
+	// Disconnect our blocks until we find the common ancestor of our best
+	// chain and the server's.
+	for height > 0 {
+		c.headerMtx.RLock()
+		ours := c.chain[height]
+		c.headerMtx.RUnlock()
+		if ours == nil {
+			log.Warnf("Unable to find the fork point of a "+
+				"reorganization deeper than %d blocks",
+				electrumReorgDepth)
+			break
+		}
+
+		if height <= tip.Height {
+			theirs := tipHeader
+			if height < tip.Height {
+				theirs, err = c.fetchHeader(height)
+				if err != nil {
+					return err
+				}
+			}
+			if theirs.BlockHash() == ours.BlockHash() {
+				break
+			}
+		}
+
+		c.disconnectHeader(height, ours)
+		height--
+	}
+
+	// Connect the blocks of the server's best chain.
+	for start := height + 1; start <= tip.Height; {
+		count := tip.Height - start + 1
+		if count > electrumMaxHeaders {
+			count = electrumMaxHeaders
+		}
+		headers, err := c.fetchHeaders(start, count)
+		if err != nil {
+			return err
+		}
+
+		for i, header := range headers {
+			height := start + int32(i)
+
+			c.headerMtx.RLock()
+			prev := c.chain[height-1]
+			c.headerMtx.RUnlock()
+			if prev != nil {
+				err := c.checkHeaderChain(height, header, prev)
+				if err != nil {
+					return err
+				}
+			}
+
+			c.connectHeader(height, header)
+			if c.notifyBlocks.Load() {
+				c.notify(BlockConnected(blockMeta(
+					&waddrmgr.BlockStamp{
+						Hash:      header.BlockHash(),
+						Height:    height,
+						Timestamp: header.Timestamp,
+					},
+				)))
+			}
+		}
+		start += int32(len(headers))
+	}
+
+	// Notify the transactions that were found before the blocks
+	// confirming them were connected.
+	if len(c.deferred) == 0 {
+		return nil
+	}
+	scriptHashes := make([]string, 0, len(c.deferred))
+	for scriptHash := range c.deferred {
+		scriptHashes = append(scriptHashes, scriptHash)
+	}
+	return c.processScripts(scriptHashes)
+}
+
+// checkHeaderChain checks that the header at the given height connects to the
+// previous header of the best chain and has the difficulty required after it.
+// Networks that allow minimum difficulty blocks are only checked against their
+// proof of work limit, since their required difficulty depends on more than
+// the previous header.
+func (c *ElectrumClient) checkHeaderChain(height int32, header,
+	prev *wire.BlockHeader) error {
+
+	if header.PrevBlock != prev.BlockHash() {
+		return fmt.Errorf("header %v at height %d does not connect to "+
+			"the best chain", header.BlockHash(), height)
+	}
+
+	params := c.cfg.ChainParams
+	if params.ReduceMinDifficulty {
+		return nil
+	}
+
+	bits := prev.Bits
+	blocksPerRetarget := int32(
+		params.TargetTimespan / params.TargetTimePerBlock,
+	)
+	if !params.PoWNoRetargeting && height%blocksPerRetarget == 0 {
+		first, err := c.blockHeader(height - blocksPerRetarget)
+		if err != nil {
+			return err
+		}
+		bits = electrumRetargetBits(params, prev, first)
+	}
+	if header.Bits != bits {
+		return fmt.Errorf("header %v at height %d has difficulty bits "+
+			"%08x, expected %08x", header.BlockHash(), height,
+			header.Bits, bits)
+	}
+	return nil
+}
+
+// connectHeader adds the header to the best chain.
+func (c *ElectrumClient) connectHeader(height int32,
+	header *wire.BlockHeader) {
+
+	c.headerMtx.Lock()
+	defer c.headerMtx.Unlock()
+
+	c.chain[height] = header
+	delete(c.chain, height-electrumReorgDepth)
+	c.cacheHeader(height, header)
+
+	c.bestBlock = waddrmgr.BlockStamp{
+		Hash:      header.BlockHash(),
+		Height:    height,
+		Timestamp: header.Timestamp,
+	}
+}
+
+// disconnectHeader removes the header at the tip of the best chain and
+// notifies the disconnection of its block.
+func (c *ElectrumClient) disconnectHeader(height int32,
+	header *wire.BlockHeader) {
+
+	hash := header.BlockHash()
+
+	c.headerMtx.Lock()
+	delete(c.chain, height)
+	delete(c.headerCache, hash)
+	c.bestBlock = waddrmgr.BlockStamp{
+		Hash:   header.PrevBlock,
+		Height: height - 1,
+	}
+	if prev, ok := c.chain[height-1]; ok {
+		c.bestBlock.Timestamp = prev.Timestamp
+	}
+	c.headerMtx.Unlock()
+
+	// Transactions of the disconnected block must be notified again when
+	// they are confirmed in another block, also when that block is below
+	// the height a scripthash was subscribed at.
+	for txid, blockHash := range c.notified {
+		if blockHash == hash {
+			delete(c.notified, txid)
+		}
+	}
+	c.watchMtx.Lock()
+	for scriptHash, since := range c.watched {
+		if since >= height {
+			c.watched[scriptHash] = height - 1
+		}
+	}
+	c.watchMtx.Unlock()
+
+	c.notify(BlockDisconnected(blockMeta(&waddrmgr.BlockStamp{
+		Hash:      hash,
+		Height:    height,
+		Timestamp: header.Timestamp,
+	})))
+}
+
+// processScripts notifies the transactions in the histories of the
+// scripthashes that have not been notified yet, and were confirmed after the
+// scripthashes were subscribed or are in the mempool.
+func (c *ElectrumClient) processScripts(scriptHashes []string) error {
+	histories, err := c.histories(scriptHashes)
+	if err != nil {
+		return err
+	}
+
+	c.headerMtx.RLock()
+	bestHeight := c.bestBlock.Height
+	c.headerMtx.RUnlock()
+
+	confirmed := make(map[int32]map[chainhash.Hash]struct{})
+	unconfirmed := make(map[chainhash.Hash]struct{})
+	for scriptHash, history := range histories {
+		delete(c.deferred, scriptHash)
+
+		c.watchMtx.Lock()
+		since := c.watched[scriptHash]
+		c.watchMtx.Unlock()
+
+		for _, tx := range history {
+			txHash, err := chainhash.NewHashFromStr(tx.TxHash)
+			if err != nil {
+				return err
+			}
+			notifiedIn, ok := c.notified[*txHash]
+
+			switch {
+			case tx.Height <= 0:
+				if !ok {
+					unconfirmed[*txHash] = struct{}{}
+				}
+				continue
+
+			case tx.Height > bestHeight:
+				c.deferred[scriptHash] = struct{}{}
+				continue
+
+			case tx.Height <= since:
+				continue
+			}
+
+			header, err := c.blockHeader(tx.Height)
+			if err != nil {
+				return err
+			}
+			if ok && notifiedIn == header.BlockHash() {
+				continue
+			}
+			if confirmed[tx.Height] == nil {
+				confirmed[tx.Height] = make(map[chainhash.Hash]struct{})
+			}
+			confirmed[tx.Height][*txHash] = struct{}{}
+		}
+	}
+
+	return c.notifyHistory(confirmed, unconfirmed)
+}
+
+// rescan notifies all transactions relevant to the watched scripthashes
+// confirmed from the block with the given hash up to the best block, and
+// those in the mempool.  Transactions confirmed in blocks that are not
+// connected yet are left to be notified once they are.
+func (c *ElectrumClient) rescan(start chainhash.Hash) error {
+	var startHeight int32
+	cached, err := c.cachedHeader(&start)
+	if err != nil {
+		log.Warnf("Rescanning from the genesis block: %v", err)
+	} else {
+		startHeight = cached.height
+	}
+
+	c.watchMtx.Lock()
+	scriptHashes := make([]string, 0, len(c.watched))
+	for scriptHash := range c.watched {
+		scriptHashes = append(scriptHashes, scriptHash)
+	}
+	c.watchMtx.Unlock()
+
+	histories, err := c.histories(scriptHashes)
+	if err != nil {
+		return err
+	}
+
+	bestBlock, _ := c.BlockStamp()
+
+	// A rescan notifies all relevant transactions again, even if they
+	// were notified before.
+	confirmed := make(map[int32]map[chainhash.Hash]struct{})
+	unconfirmed := make(map[chainhash.Hash]struct{})
+	for scriptHash, history := range histories {
+		for _, tx := range history {
+			txHash, err := chainhash.NewHashFromStr(tx.TxHash)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case tx.Height <= 0:
+				unconfirmed[*txHash] = struct{}{}
+
+			case tx.Height > bestBlock.Height:
+				c.deferred[scriptHash] = struct{}{}
+
+			case tx.Height >= startHeight:
+				if confirmed[tx.Height] == nil {
+					confirmed[tx.Height] = make(
+						map[chainhash.Hash]struct{},
+					)
+				}
+				confirmed[tx.Height][*txHash] = struct{}{}
+			}
+		}
+	}
+
+	if err := c.notifyHistory(confirmed, unconfirmed); err != nil {
+		return err
+	}
+
+	c.notify(&RescanFinished{
+		Hash:   &bestBlock.Hash,
+		Height: bestBlock.Height,
+		Time:   bestBlock.Timestamp,
+	})
+
+	return nil
+}
+
+// notifyHistory notifies the confirmed transactions in the order of the
+// heights of their blocks, followed by the unconfirmed ones.
+func (c *ElectrumClient) notifyHistory(
+	confirmed map[int32]map[chainhash.Hash]struct{},
+	unconfirmed map[chainhash.Hash]struct{}) error {
+
+	heights := make([]int32, 0, len(confirmed))
+	for height := range confirmed {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	for _, height := range heights {
+		header, err := c.blockHeader(height)
+		if err != nil {
+			return err
+		}
+		txs, err := c.fetchVerifiedTxs(confirmed[height], height, header)
+		if err != nil {
+			return err
+		}
+		err = c.notifyTxs(txs, &waddrmgr.BlockStamp{
+			Hash:      header.BlockHash(),
+			Height:    height,
+			Timestamp: header.Timestamp,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(unconfirmed) == 0 {
+		return nil
+	}
+	txs, err := c.fetchTxs(unconfirmed)
+	if err != nil {
+		return err
+	}
+	return c.notifyTxs(txs, nil)
+}
+
+// notifyTxs notifies the transactions as RelevantTx notifications in
+// dependency order.  A nil block notifies unconfirmed transactions.
+func (c *ElectrumClient) notifyTxs(txs []*wire.MsgTx,
+	block *waddrmgr.BlockStamp) error {
+
+	var (
+		meta      *wtxmgr.BlockMeta
+		blockHash chainhash.Hash
+		received  = time.Now()
+	)
+	if block != nil {
+		m := blockMeta(block)
+		meta = &m
+		blockHash = block.Hash
+		received = block.Timestamp
+	}
+
+	for _, tx := range sortTxsByDependency(txs) {
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, received)
+		if err != nil {
+			return err
+		}
+		c.notified[rec.Hash] = blockHash
+		c.notify(RelevantTx{
+			TxRecord: rec,
+			Block:    meta,
+		})
+	}
+
+	return nil
+}
+
+// notify queues a notification unless the client is stopping.
+func (c *ElectrumClient) notify(n interface{}) {
+	select {
+	case c.notificationQueue.ChanIn() <- n:
+	case <-c.quit:
+	}
+}
+
+// watchAddrs subscribes to the scripthashes of the addresses that are not
+// watched yet.  If checkHistory is set, the histories of the scripthashes that
+// already have transactions are checked for new mempool transactions.
+func (c *ElectrumClient) watchAddrs(addrs []btcutil.Address,
+	checkHistory bool) error {
+
+	scriptHashes, err := electrumScriptHashes(addrs)
+	if err != nil {
+		return err
+	}
+
+	c.headerMtx.RLock()
+	bestHeight := c.bestBlock.Height
+	c.headerMtx.RUnlock()
+
+	var subscribe []string
+	c.watchMtx.Lock()
+	for _, scriptHash := range scriptHashes {
+		if _, ok := c.watched[scriptHash]; ok {
+			continue
+		}
+		c.watched[scriptHash] = bestHeight
+		subscribe = append(subscribe, scriptHash)
+	}
+	c.watchMtx.Unlock()
+
+	g := new(errgroup.Group)
+	g.SetLimit(electrumMaxConcurrentRequests)
+	for _, scriptHash := range subscribe {
+		scriptHash := scriptHash
+		g.Go(func() error {
+			var status *string
+			err := c.call(
+				"blockchain.scripthash.subscribe", &status,
+				scriptHash,
+			)
+			if err != nil {
+				c.watchMtx.Lock()
+				delete(c.watched, scriptHash)
+				c.watchMtx.Unlock()
+				return err
+			}
+
+			if checkHistory && status != nil {
+				c.queueServerNtfn(&electrumStatusNtfn{
+					scriptHash: scriptHash,
+				})
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// histories queries the histories of the scripthashes.
+func (c *ElectrumClient) histories(
+	scriptHashes []string) (map[string][]electrumHistoryTx, error) {
+
+	var mtx sync.Mutex
+	histories := make(map[string][]electrumHistoryTx, len(scriptHashes))
+
+	g := new(errgroup.Group)
+	g.SetLimit(electrumMaxConcurrentRequests)
+	for _, scriptHash := range scriptHashes {
+		scriptHash := scriptHash
+		g.Go(func() error {
+			var history []electrumHistoryTx
+			err := c.call(
+				"blockchain.scripthash.get_history", &history,
+				scriptHash,
+			)
+			if err != nil {
+				return err
+			}
+
+			mtx.Lock()
+			histories[scriptHash] = history
+			mtx.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// fetchTxs fetches the transactions with the given hashes.
+func (c *ElectrumClient) fetchTxs(
+	txHashes map[chainhash.Hash]struct{}) ([]*wire.MsgTx, error) {
+
+	var mtx sync.Mutex
+	txs := make([]*wire.MsgTx, 0, len(txHashes))
+
+	g := new(errgroup.Group)
+	g.SetLimit(electrumMaxConcurrentRequests)
+	for txHash := range txHashes {
+		txHash := txHash
+		g.Go(func() error {
+			var rawHex string
+			err := c.call(
+				"blockchain.transaction.get", &rawHex,
+				txHash.String(),
+			)
+			if err != nil {
+				return err
+			}
+			raw, err := hex.DecodeString(rawHex)
+			if err != nil {
+				return err
+			}
+			var tx wire.MsgTx
+			if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+				return fmt.Errorf("unable to decode tx %v: %w",
+					txHash, err)
+			}
+			if tx.TxHash() != txHash {
+				return fmt.Errorf("server returned tx %v for "+
+					"tx %v", tx.TxHash(), txHash)
+			}
+
+			mtx.Lock()
+			txs = append(txs, &tx)
+			mtx.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// fetchVerifiedTxs fetches the transactions with the given hashes after
+// checking their merkle proofs against the header of the block at the given
+// height.
+func (c *ElectrumClient) fetchVerifiedTxs(txHashes map[chainhash.Hash]struct{},
+	height int32, header *wire.BlockHeader) ([]*wire.MsgTx, error) {
+
+	// The branch of the coinbase transaction, the first leaf of the merkle
+	// tree, has the depth of the tree.  Branches of any other length would
+	// prove an inner node of the tree, disguised as a 64 byte transaction.
+	var coinbase electrumTxFromPos
+	err := c.call(
+		"blockchain.transaction.id_from_pos", &coinbase, height, 0,
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseHash, err := chainhash.NewHashFromStr(coinbase.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	depth := len(coinbase.Merkle)
+	err = verifyElectrumMerkleProof(
+		coinbaseHash, &electrumMerkleProof{Merkle: coinbase.Merkle},
+		header, depth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	g := new(errgroup.Group)
+	g.SetLimit(electrumMaxConcurrentRequests)
+	for txHash := range txHashes {
+		txHash := txHash
+		g.Go(func() error {
+			var proof electrumMerkleProof
+			err := c.call(
+				"blockchain.transaction.get_merkle", &proof,
+				txHash.String(), height,
+			)
+			if err != nil {
+				return err
+			}
+			return verifyElectrumMerkleProof(
+				&txHash, &proof, header, depth,
+			)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return c.fetchTxs(txHashes)
+}
+
+// blockHeader returns the header of the block at the given height of the best
+// chain.
+func (c *ElectrumClient) blockHeader(height int32) (*wire.BlockHeader, error) {
+	c.headerMtx.RLock()
+	header, ok := c.chain[height]
+	c.headerMtx.RUnlock()
+	if ok {
+		return header, nil
+	}
+
+	header, err := c.fetchHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	c.headerMtx.Lock()
+	c.cacheHeader(height, header)
+	c.headerMtx.Unlock()
+
+	return header, nil
+}
+
+// cachedHeader returns the cached header with the given hash.
+func (c *ElectrumClient) cachedHeader(
+	hash *chainhash.Hash) (*electrumCachedHeader, error) {
+
+	c.headerMtx.RLock()
+	defer c.headerMtx.RUnlock()
+
+	cached, ok := c.headerCache[*hash]
+	if !ok {
+		return nil, fmt.Errorf("block %v is unknown to the electrum "+
+			"client", hash)
+	}
+	return cached, nil
+}
+
+// cacheHeader adds the header to the header cache.  The cache is reset to the
+// headers of the best chain when it is full.
+//
+// NOTE: The caller must hold the header mutex for writes.
+func (c *ElectrumClient) cacheHeader(height int32, header *wire.BlockHeader) {
+	if len(c.headerCache) >= electrumHeaderCacheSize {
+		c.headerCache = make(map[chainhash.Hash]*electrumCachedHeader)
+		for height, header := range c.chain {
+			c.headerCache[header.BlockHash()] = &electrumCachedHeader{
+				header: *header,
+				height: height,
+			}
+		}
+	}
+
+	c.headerCache[header.BlockHash()] = &electrumCachedHeader{
+		header: *header,
+		height: height,
+	}
+}
+
+// fetchHeader fetches the header of the block at the given height of the
+// server's best chain.
+func (c *ElectrumClient) fetchHeader(height int32) (*wire.BlockHeader, error) {
+	var headerHex string
+	err := c.call("blockchain.block.header", &headerHex, height)
+	if err != nil {
+		return nil, err
+	}
+	header, err := decodeElectrumHeader(headerHex)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkProofOfWork(header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// fetchHeaders fetches count headers of the server's best chain starting at
+// the given height.  Fewer headers are returned if the server limits the
+// number of headers per request.
+func (c *ElectrumClient) fetchHeaders(start,
+	count int32) ([]*wire.BlockHeader, error) {
+
+	var res electrumHeaders
+	err := c.call("blockchain.block.headers", &res, start, count)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(res.Hex)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != res.Count*wire.MaxBlockHeaderPayload || res.Count == 0 {
+		return nil, fmt.Errorf("invalid headers response of %d bytes "+
+			"for %d headers", len(raw), res.Count)
+	}
+
+	headers := make([]*wire.BlockHeader, res.Count)
+	r := bytes.NewReader(raw)
+	for i := range headers {
+		headers[i] = new(wire.BlockHeader)
+		if err := headers[i].Deserialize(r); err != nil {
+			return nil, err
+		}
+		if err := c.checkProofOfWork(headers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return headers, nil
+}
+
+// checkProofOfWork checks that the hash of the header satisfies its
+// difficulty bits and that the difficulty is within the proof of work limit
+// of the network.
+func (c *ElectrumClient) checkProofOfWork(header *wire.BlockHeader) error {
+	block := btcutil.NewBlock(&wire.MsgBlock{Header: *header})
+	err := blockchain.CheckProofOfWork(block, c.cfg.ChainParams.PowLimit)
+	if err != nil {
+		return fmt.Errorf("invalid header %v: %w", header.BlockHash(),
+			err)
+	}
+	return nil
+}
+
+// call sends a request to the server and decodes the result into result,
+// unless it is nil.
+func (c *ElectrumClient) call(method string, result interface{},
+	params ...interface{}) error {
+
+	if params == nil {
+		params = []interface{}{}
+	}
+	id := c.requestID.Add(1)
+	req, err := json.Marshal(&electrumRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	respChan := make(chan *electrumResponse, 1)
+	c.pendingMtx.Lock()
+	c.pending[id] = respChan
+	c.pendingMtx.Unlock()
+	defer func() {
+		c.pendingMtx.Lock()
+		delete(c.pending, id)
+		c.pendingMtx.Unlock()
+	}()
+
+	c.writeMtx.Lock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.cfg.RequestTimeout))
+	_, err = c.conn.Write(append(req, '\n'))
+	c.writeMtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-respChan:
+		if err := parseElectrumError(resp.Error); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("unable to decode result of %s: %w",
+				method, err)
+		}
+		return nil
+
+	case <-time.After(c.cfg.RequestTimeout):
+		return fmt.Errorf("%s: request timed out", method)
+
+	case <-c.quit:
+		return errors.New("electrum client stopped")
+	}
+}
+
+// readHandler reads the responses and notifications of the server.  The
+// client is stopped when the connection is lost.
+//
+// NOTE: This must be run as a goroutine.
+func (c *ElectrumClient) readHandler() {
+	defer c.wg.Done()
+
+	r := bufio.NewReader(c.conn)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			if !c.stopped.Load() {
+				log.Errorf("Lost connection to electrum server "+
+					"%v: %v", c.cfg.Server, err)
+				c.Stop()
+			}
+			return
+		}
+
+		var resp electrumResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			log.Errorf("Unable to decode message of electrum "+
+				"server: %v", err)
+			continue
+		}
+
+		if resp.ID != nil {
+			c.pendingMtx.Lock()
+			respChan, ok := c.pending[*resp.ID]
+			c.pendingMtx.Unlock()
+			if ok {
+				respChan <- &resp
+			}
+			continue
+		}
+
+		if err := c.handleServerNtfn(&resp); err != nil {
+			log.Errorf("Unable to handle %s notification: %v",
+				resp.Method, err)
+		}
+	}
+}
+
+// handleServerNtfn decodes a subscription notification and queues it for the
+// notification goroutine.
+func (c *ElectrumClient) handleServerNtfn(resp *electrumResponse) error {
+	switch resp.Method {
+	case "blockchain.headers.subscribe":
+		var params []electrumHeaderNtfn
+		if err := json.Unmarshal(resp.Params, &params); err != nil {
+			return err
+		}
+		if len(params) != 1 {
+			return fmt.Errorf("expected 1 header, got %d",
+				len(params))
+		}
+		c.queueServerNtfn(&params[0])
+
+	case "blockchain.scripthash.subscribe":
+		var params []*string
+		if err := json.Unmarshal(resp.Params, &params); err != nil {
+			return err
+		}
+		if len(params) != 2 || params[0] == nil {
+			return errors.New("invalid scripthash notification")
+		}
+		c.queueServerNtfn(&electrumStatusNtfn{scriptHash: *params[0]})
+	}
+
+	return nil
+}
+
+// queueServerNtfn queues a notification of the server for the notification
+// goroutine unless the client is stopping.
+func (c *ElectrumClient) queueServerNtfn(n interface{}) {
+	select {
+	case c.serverNtfns.ChanIn() <- n:
+	case <-c.quit:
+	}
+}
+
+// dial opens the connection to the server.
+func (c *ElectrumClient) dial() (net.Conn, error) {
+	dial := c.cfg.Dialer
+	if dial == nil {
+		d := net.Dialer{Timeout: c.cfg.RequestTimeout}
+		dial = d.Dial
+	}
+	conn, err := dial("tcp", c.cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	if !c.cfg.TLS {
+		return conn, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.cfg.TLSConfig != nil {
+		tlsConfig = c.cfg.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.cfg.Server)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConfig.ServerName = host
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	_ = tlsConn.SetDeadline(time.Now().Add(c.cfg.RequestTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	return tlsConn, nil
+}
+
+// parseElectrumError decodes the error of a response.  Servers return either
+// an error object or a plain error message.
+func parseElectrumError(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var e electrumError
+	if err := json.Unmarshal(raw, &e); err == nil && e.Message != "" {
+		return &e
+	}
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err == nil {
+		return &electrumError{Message: msg}
+	}
+	return &electrumError{Message: string(raw)}
+}
+
+// decodeElectrumHeader decodes a hex encoded block header.
+func decodeElectrumHeader(headerHex string) (*wire.BlockHeader, error) {
+	raw, err := hex.DecodeString(headerHex)
+	if err != nil {
+		return nil, err
+	}
+	var header wire.BlockHeader
+	if err := header.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+// verifyElectrumMerkleProof checks that the merkle branch proves the
+// inclusion of the transaction in the block with the given header, whose
+// merkle tree has the given depth.
+func verifyElectrumMerkleProof(txHash *chainhash.Hash,
+	proof *electrumMerkleProof, header *wire.BlockHeader, depth int) error {
+
+	if len(proof.Merkle) != depth || uint64(proof.Pos)>>depth != 0 {
+		return fmt.Errorf("invalid merkle proof for tx %v in block %v: "+
+			"branch of length %d at position %d in a tree of "+
+			"depth %d", txHash, header.BlockHash(),
+			len(proof.Merkle), proof.Pos, depth)
+	}
+
+	root := *txHash
+	pos := proof.Pos
+	for _, branchHex := range proof.Merkle {
+		branch, err := chainhash.NewHashFromStr(branchHex)
+		if err != nil {
+			return err
+		}
+
+		var buf [chainhash.HashSize * 2]byte
+		if pos&1 == 0 {
+			copy(buf[:], root[:])
+			copy(buf[chainhash.HashSize:], branch[:])
+		} else {
+			copy(buf[:], branch[:])
+			copy(buf[chainhash.HashSize:], root[:])
+		}
+		root = chainhash.DoubleHashH(buf[:])
+		pos >>= 1
+	}
+
+	if root != header.MerkleRoot {
+		return fmt.Errorf("invalid merkle proof for tx %v in block %v",
+			txHash, header.BlockHash())
+	}
+	return nil
+}
+
+// electrumRetargetBits returns the difficulty bits required at the start of a
+// difficulty period, given the last header of the previous period and its
+// first header.
+func electrumRetargetBits(params *chaincfg.Params, last,
+	first *wire.BlockHeader) uint32 {
+
+	targetTimespan := int64(params.TargetTimespan / time.Second)
+	minTimespan := targetTimespan / params.RetargetAdjustmentFactor
+	maxTimespan := targetTimespan * params.RetargetAdjustmentFactor
+
+	timespan := last.Timestamp.Unix() - first.Timestamp.Unix()
+	if timespan < minTimespan {
+		timespan = minTimespan
+	} else if timespan > maxTimespan {
+		timespan = maxTimespan
+	}
+
+	target := blockchain.CompactToBig(last.Bits)
+	target.Mul(target, big.NewInt(timespan))
+	target.Div(target, big.NewInt(targetTimespan))
+	if target.Cmp(params.PowLimit) > 0 {
+		target.Set(params.PowLimit)
+	}
+	return blockchain.BigToCompact(target)
+}
+
+// electrumScriptHash returns the scripthash used to subscribe to an output
+// script, which is the SHA256 of the script in reversed byte order, hex
+// encoded.
+func electrumScriptHash(pkScript []byte) string {
+	h := sha256.Sum256(pkScript)
+	for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+		h[i], h[j] = h[j], h[i]
+	}
+	return hex.EncodeToString(h[:])
+}
+
+// electrumScriptHashes returns the scripthashes of the output scripts of the
+// addresses.
+func electrumScriptHashes(addrs []btcutil.Address) ([]string, error) {
+	seen := make(map[string]struct{}, len(addrs))
+	scriptHashes := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		scriptHash := electrumScriptHash(script)
+		if _, ok := seen[scriptHash]; ok {
+			continue
+		}
+		seen[scriptHash] = struct{}{}
+		scriptHashes = append(scriptHashes, scriptHash)
+	}
+	return scriptHashes, nil
+}
@@ -0,0 +1,884 @@
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeElectrum is an in-process Electrum server implementing the subset of
+// the protocol used by the ElectrumClient.
+type fakeElectrum struct {
+	*fakeChain
+
+	// badMerkle makes the server return invalid merkle proofs.  It is
+	// guarded by the chain mutex.
+	badMerkle bool
+
+	connsMtx sync.Mutex
+	conns    map[*fakeElectrumConn]struct{}
+}
+
+// fakeElectrumConn is a client connection of the fake Electrum server and its
+// subscriptions.
+type fakeElectrumConn struct {
+	conn     net.Conn
+	writeMtx sync.Mutex
+
+	// mtx guards the subscriptions, which map the subscribed scripthashes
+	// to their last notified status.
+	mtx      sync.Mutex
+	headers  bool
+	statuses map[string]*string
+}
+
+func newFakeElectrum(t *testing.T, params *chaincfg.Params,
+	tlsConfig *tls.Config) (*fakeElectrum, string) {
+
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	f := &fakeElectrum{
+		fakeChain: newFakeChain(params),
+		conns:     make(map[*fakeElectrumConn]struct{}),
+	}
+	f.onChange = f.notifySubscribers
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c := &fakeElectrumConn{
+				conn:     conn,
+				statuses: make(map[string]*string),
+			}
+			f.connsMtx.Lock()
+			f.conns[c] = struct{}{}
+			f.connsMtx.Unlock()
+
+			go f.serve(c)
+		}
+	}()
+	t.Cleanup(func() {
+		ln.Close()
+		f.connsMtx.Lock()
+		for c := range f.conns {
+			c.conn.Close()
+		}
+		f.connsMtx.Unlock()
+	})
+
+	return f, ln.Addr().String()
+}
+
+func (f *fakeElectrum) serve(c *fakeElectrumConn) {
+	defer func() {
+		f.connsMtx.Lock()
+		delete(f.conns, c)
+		f.connsMtx.Unlock()
+		c.conn.Close()
+	}()
+
+	r := bufio.NewReader(c.conn)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+
+		var req struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(line, &req); err != nil {
+			return
+		}
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		result, err := f.handle(c, req.Method, req.Params)
+		if err != nil {
+			resp["error"] = map[string]interface{}{
+				"code":    1,
+				"message": err.Error(),
+			}
+		} else {
+			resp["result"] = result
+		}
+		c.send(resp)
+	}
+}
+
+func (c *fakeElectrumConn) send(msg interface{}) {
+	b, _ := json.Marshal(msg)
+
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+
+	_, _ = c.conn.Write(append(b, '\n'))
+}
+
+func (f *fakeElectrum) handle(c *fakeElectrumConn, method string,
+	params []json.RawMessage) (interface{}, error) {
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	param := func(i int, v interface{}) error {
+		if i >= len(params) {
+			return fmt.Errorf("missing param %d", i)
+		}
+		return json.Unmarshal(params[i], v)
+	}
+
+	switch method {
+	case "server.version":
+		return []string{"fake 1.0", electrumProtocolVersion}, nil
+
+	case "server.ping":
+		return nil, nil
+
+	case "blockchain.headers.subscribe":
+		c.mtx.Lock()
+		c.headers = true
+		c.mtx.Unlock()
+		return f.tipLocked(), nil
+
+	case "blockchain.block.header":
+		var height int
+		if err := param(0, &height); err != nil {
+			return nil, err
+		}
+		if height < 0 || height >= len(f.chain) {
+			return nil, errors.New("height out of range")
+		}
+		return headerHex(&f.chain[height].Header), nil
+
+	case "blockchain.block.headers":
+		var start, count int
+		if err := param(0, &start); err != nil {
+			return nil, err
+		}
+		if err := param(1, &count); err != nil {
+			return nil, err
+		}
+		var headers string
+		var n int
+		for h := start; h < start+count && h < len(f.chain); h++ {
+			headers += headerHex(&f.chain[h].Header)
+			n++
+		}
+		return map[string]interface{}{
+			"count": n,
+			"hex":   headers,
+			"max":   electrumMaxHeaders,
+		}, nil
+
+	case "blockchain.scripthash.subscribe":
+		var scriptHash string
+		if err := param(0, &scriptHash); err != nil {
+			return nil, err
+		}
+		status := f.statusLocked(scriptHash)
+		c.mtx.Lock()
+		c.statuses[scriptHash] = status
+		c.mtx.Unlock()
+		return status, nil
+
+	case "blockchain.scripthash.get_history":
+		var scriptHash string
+		if err := param(0, &scriptHash); err != nil {
+			return nil, err
+		}
+		return f.historyLocked(scriptHash), nil
+
+	case "blockchain.transaction.get":
+		var txid string
+		if err := param(0, &txid); err != nil {
+			return nil, err
+		}
+		tx, _ := f.tx(txid)
+		if tx == nil {
+			return nil, errors.New("transaction not found")
+		}
+		var buf bytes.Buffer
+		_ = tx.Serialize(&buf)
+		return hex.EncodeToString(buf.Bytes()), nil
+
+	case "blockchain.transaction.get_merkle":
+		var txid string
+		var height int
+		if err := param(0, &txid); err != nil {
+			return nil, err
+		}
+		if err := param(1, &height); err != nil {
+			return nil, err
+		}
+		if height <= 0 || height >= len(f.chain) {
+			return nil, errors.New("height out of range")
+		}
+		block := f.chain[height]
+		for pos, tx := range block.Transactions {
+			if tx.TxHash().String() != txid {
+				continue
+			}
+			proof := electrumMerkleProof{
+				BlockHeight: int32(height),
+				Merkle:      merkleBranch(block, pos),
+				Pos:         uint32(pos),
+			}
+			if f.badMerkle {
+				proof.Pos ^= 1
+			}
+			return proof, nil
+		}
+		return nil, errors.New("transaction not in block")
+
+	case "blockchain.transaction.id_from_pos":
+		var height, pos int
+		if err := param(0, &height); err != nil {
+			return nil, err
+		}
+		if err := param(1, &pos); err != nil {
+			return nil, err
+		}
+		if height < 0 || height >= len(f.chain) {
+			return nil, errors.New("height out of range")
+		}
+		block := f.chain[height]
+		if pos < 0 || pos >= len(block.Transactions) {
+			return nil, errors.New("position out of range")
+		}
+		return electrumTxFromPos{
+			TxHash: block.Transactions[pos].TxHash().String(),
+			Merkle: merkleBranch(block, pos),
+		}, nil
+
+	case "blockchain.transaction.broadcast":
+		if f.rejectTx != "" {
+			return nil, errors.New(f.rejectTx)
+		}
+		var rawHex string
+		if err := param(0, &rawHex); err != nil {
+			return nil, err
+		}
+		raw, err := hex.DecodeString(rawHex)
+		if err != nil {
+			return nil, err
+		}
+		var tx wire.MsgTx
+		if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+			return nil, err
+		}
+		f.published = append(f.published, &tx)
+		f.mempool = append(f.mempool, &tx)
+		return tx.TxHash().String(), nil
+	}
+
+	return nil, fmt.Errorf("unknown method %s", method)
+}
+
+// notifySubscribers sends the new tip to the connections subscribed to
+// headers, followed by the changed statuses of subscribed scripthashes.
+func (f *fakeElectrum) notifySubscribers() {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.connsMtx.Lock()
+	defer f.connsMtx.Unlock()
+
+	for c := range f.conns {
+		c.mtx.Lock()
+		if c.headers {
+			c.send(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "blockchain.headers.subscribe",
+				"params":  []interface{}{f.tipLocked()},
+			})
+		}
+		for scriptHash, old := range c.statuses {
+			status := f.statusLocked(scriptHash)
+			if (old == nil && status == nil) ||
+				(old != nil && status != nil && *old == *status) {
+
+				continue
+			}
+			c.statuses[scriptHash] = status
+			c.send(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "blockchain.scripthash.subscribe",
+				"params":  []interface{}{scriptHash, status},
+			})
+		}
+		c.mtx.Unlock()
+	}
+}
+
+func (f *fakeElectrum) tipLocked() electrumHeaderNtfn {
+	height := len(f.chain) - 1
+	return electrumHeaderNtfn{
+		Height: int32(height),
+		Hex:    headerHex(&f.chain[height].Header),
+	}
+}
+
+func (f *fakeElectrum) touches(tx *wire.MsgTx, scriptHash string) bool {
+	for _, pkScript := range f.pkScripts(tx) {
+		if electrumScriptHash(pkScript) == scriptHash {
+			return true
+		}
+	}
+	return false
+}
+
+// historyLocked returns the confirmed transactions of a scripthash in block
+// order, followed by its mempool transactions.
+func (f *fakeElectrum) historyLocked(scriptHash string) []electrumHistoryTx {
+	history := []electrumHistoryTx{}
+	for height, block := range f.chain {
+		for _, tx := range block.Transactions {
+			if f.touches(tx, scriptHash) {
+				history = append(history, electrumHistoryTx{
+					TxHash: tx.TxHash().String(),
+					Height: int32(height),
+				})
+			}
+		}
+	}
+	for _, tx := range f.mempool {
+		if f.touches(tx, scriptHash) {
+			history = append(history, electrumHistoryTx{
+				TxHash: tx.TxHash().String(),
+			})
+		}
+	}
+	return history
+}
+
+// statusLocked returns the status of a scripthash as defined by the Electrum
+// protocol, or nil if it has no history.
+func (f *fakeElectrum) statusLocked(scriptHash string) *string {
+	history := f.historyLocked(scriptHash)
+	if len(history) == 0 {
+		return nil
+	}
+	var preimage string
+	for _, tx := range history {
+		preimage += fmt.Sprintf("%s:%d:", tx.TxHash, tx.Height)
+	}
+	h := sha256.Sum256([]byte(preimage))
+	status := hex.EncodeToString(h[:])
+	return &status
+}
+
+func headerHex(header *wire.BlockHeader) string {
+	var buf bytes.Buffer
+	_ = header.Serialize(&buf)
+	return hex.EncodeToString(buf.Bytes())
+}
+
+// merkleBranch returns the merkle branch of the transaction at the given
+// position of the block.
+func merkleBranch(block *wire.MsgBlock, pos int) []string {
+	level := make([]chainhash.Hash, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		level[i] = tx.TxHash()
+	}
+
+	var branch []string
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		branch = append(branch, level[pos^1].String())
+
+		next := make([]chainhash.Hash, len(level)/2)
+		for i := range next {
+			var buf [chainhash.HashSize * 2]byte
+			copy(buf[:], level[2*i][:])
+			copy(buf[chainhash.HashSize:], level[2*i+1][:])
+			next[i] = chainhash.DoubleHashH(buf[:])
+		}
+		level = next
+		pos >>= 1
+	}
+	return branch
+}
+
+func startElectrumClient(t *testing.T, cfg *ElectrumConfig) *ElectrumClient {
+	t.Helper()
+
+	if cfg.ChainParams == nil {
+		cfg.ChainParams = &chaincfg.RegressionNetParams
+	}
+	client, err := NewElectrumClient(cfg)
+	require.NoError(t, err)
+	require.NoError(t, client.Start())
+	t.Cleanup(func() {
+		client.Stop()
+		client.WaitForShutdown()
+	})
+
+	require.IsType(t, ClientConnected{}, nextNotification(t, client))
+	return client
+}
+
+// TestElectrumClientQueries tests the chain queries and transaction broadcast
+// of the Electrum client over a TLS connection.
+func TestElectrumClientQueries(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := btcutil.NewTLSCertPair(
+		"electrum test", time.Now().Add(time.Hour), nil,
+	)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+
+	fake, addr := newFakeElectrum(
+		t, &chaincfg.RegressionNetParams, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		},
+	)
+	fake.addBlock()
+	block2 := fake.addBlock()
+	block3 := fake.addBlock()
+
+	// The client refuses to start against a server of another network.
+	wrongNet, err := NewElectrumClient(&ElectrumConfig{
+		Server:      addr,
+		ChainParams: &chaincfg.MainNetParams,
+		TLS:         true,
+		TLSConfig:   &tls.Config{RootCAs: roots},
+	})
+	require.NoError(t, err)
+	require.Error(t, wrongNet.Start())
+
+	// The server's certificate is verified.
+	untrusted, err := NewElectrumClient(&ElectrumConfig{
+		Server:      addr,
+		ChainParams: &chaincfg.RegressionNetParams,
+		TLS:         true,
+	})
+	require.NoError(t, err)
+	require.Error(t, untrusted.Start())
+
+	client := startElectrumClient(t, &ElectrumConfig{
+		Server:    addr,
+		TLS:       true,
+		TLSConfig: &tls.Config{RootCAs: roots},
+	})
+	require.Equal(t, "electrum", client.BackEnd())
+	require.Contains(t, BackEnds(), client.BackEnd())
+
+	hash, height, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.Equal(t, block3.BlockHash(), *hash)
+	require.EqualValues(t, 3, height)
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, block3.BlockHash(), bs.Hash)
+	require.Equal(t, block3.Header.Timestamp, bs.Timestamp)
+
+	hash, err = client.GetBlockHash(2)
+	require.NoError(t, err)
+	require.Equal(t, block2.BlockHash(), *hash)
+
+	_, err = client.GetBlockHash(4)
+	require.Error(t, err)
+
+	header, err := client.GetBlockHeader(hash)
+	require.NoError(t, err)
+	require.Equal(t, block2.Header, *header)
+
+	height, err = client.GetBlockHeight(hash)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, height)
+
+	// Blocks can't be looked up by hash unless the client has seen them.
+	_, err = client.GetBlockHeader(&chainhash.Hash{1})
+	require.Error(t, err)
+
+	_, err = client.GetBlock(hash)
+	require.ErrorIs(t, err, ErrUnimplemented)
+
+	// The regtest genesis block is too old for the client to be
+	// current.
+	require.False(t, client.IsCurrent())
+
+	tx := payTx(t, wire.OutPoint{Index: 1}, testAddr(t, 1), 1000)
+	txid, err := client.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), *txid)
+	fake.mtx.Lock()
+	require.Len(t, fake.published, 1)
+	fake.rejectTx = "the transaction was rejected by network rules.\n\n" +
+		"mempool min fee not met"
+	fake.mtx.Unlock()
+
+	_, err = client.SendRawTransaction(tx, false)
+	require.Error(t, err)
+	require.ErrorIs(t, client.MapRPCErr(err), ErrMempoolMinFeeNotMet)
+
+	_, err = client.TestMempoolAccept([]*wire.MsgTx{tx}, 0)
+	require.ErrorIs(t, err, ErrUnimplemented)
+}
+
+// TestElectrumClientRescan tests that a rescan notifies the confirmed and
+// unconfirmed transactions paying to and spending from the watched addresses
+// in order, followed by a RescanFinished notification.
+func TestElectrumClientRescan(t *testing.T) {
+	t.Parallel()
+
+	fake, addr := newFakeElectrum(t, &chaincfg.RegressionNetParams, nil)
+	watched := testAddr(t, 1)
+	other := testAddr(t, 2)
+
+	fake.addBlock()
+	pay := payTx(t, wire.OutPoint{Index: 1}, watched, 1000)
+	unrelated := payTx(t, wire.OutPoint{Index: 2}, other, 1000)
+	block2 := fake.addBlock(unrelated, pay)
+
+	// A transaction spending the wallet's output in the same block as
+	// another payment to it, which must be notified after the
+	// transaction it spends from.
+	pay2 := payTx(t, wire.OutPoint{Index: 3}, watched, 2000)
+	spend := payTx(t, wire.OutPoint{Hash: pay2.TxHash()}, other, 1500)
+	block3 := fake.addBlock(spend, pay2)
+	fake.addBlock()
+
+	unconfirmed := payTx(t, wire.OutPoint{Index: 4}, watched, 3000)
+	fake.addMempoolTx(unconfirmed)
+
+	client := startElectrumClient(t, &ElectrumConfig{Server: addr})
+	err := client.Rescan(
+		&block2.Header.PrevBlock, []btcutil.Address{watched}, nil,
+	)
+	require.NoError(t, err)
+
+	requireRelevantTx(t, nextNotification(t, client), pay, block2)
+	requireRelevantTx(t, nextNotification(t, client), pay2, block3)
+	requireRelevantTx(t, nextNotification(t, client), spend, block3)
+	requireRelevantTx(t, nextNotification(t, client), unconfirmed, nil)
+
+	finished, ok := nextNotification(t, client).(*RescanFinished)
+	require.True(t, ok)
+	require.EqualValues(t, 4, finished.Height)
+
+	// Rescanning from the block after the payments only notifies the
+	// mempool transaction.
+	tip, _, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.NoError(t, client.Rescan(tip, nil, nil))
+	requireRelevantTx(t, nextNotification(t, client), unconfirmed, nil)
+	require.IsType(t, &RescanFinished{}, nextNotification(t, client))
+}
+
+// TestElectrumClientNotifications tests that new blocks, relevant transactions
+// and reorganizations are notified from the subscriptions of the client.
+func TestElectrumClientNotifications(t *testing.T) {
+	t.Parallel()
+
+	fake, addr := newFakeElectrum(t, &chaincfg.RegressionNetParams, nil)
+	watched := testAddr(t, 1)
+	fake.addBlock()
+
+	client := startElectrumClient(t, &ElectrumConfig{Server: addr})
+	require.NoError(t, client.NotifyBlocks())
+	require.NoError(t, client.NotifyReceived([]btcutil.Address{watched}))
+
+	requireConnected := func(n interface{}, block *wire.MsgBlock,
+		height int32) {
+
+		t.Helper()
+
+		connected, ok := n.(BlockConnected)
+		require.True(t, ok, "expected BlockConnected, got %T", n)
+		require.Equal(t, block.BlockHash(), connected.Hash)
+		require.Equal(t, height, connected.Height)
+	}
+
+	// A payment first seen in the mempool is notified as unconfirmed,
+	// and again once it is mined.
+	pay := payTx(t, wire.OutPoint{Index: 1}, watched, 1000)
+	fake.addMempoolTx(pay)
+	requireRelevantTx(t, nextNotification(t, client), pay, nil)
+
+	block2 := fake.addBlock(pay)
+	requireConnected(nextNotification(t, client), block2, 2)
+	requireRelevantTx(t, nextNotification(t, client), pay, block2)
+
+	// Replace the block containing the payment with two new blocks, the
+	// second of which confirms the payment again.
+	fake.reorg(1, nil, []*wire.MsgTx{pay})
+	fake.mtx.Lock()
+	newBlock2, newBlock3 := fake.chain[2], fake.chain[3]
+	fake.mtx.Unlock()
+
+	disconnected, ok := nextNotification(t, client).(BlockDisconnected)
+	require.True(t, ok)
+	require.Equal(t, block2.BlockHash(), disconnected.Hash)
+	requireConnected(nextNotification(t, client), newBlock2, 2)
+	requireConnected(nextNotification(t, client), newBlock3, 3)
+	requireRelevantTx(t, nextNotification(t, client), pay, newBlock3)
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, newBlock3.BlockHash(), bs.Hash)
+
+	// The client stops when the server closes the connection.
+	fake.connsMtx.Lock()
+	for c := range fake.conns {
+		c.conn.Close()
+	}
+	fake.connsMtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		client.WaitForShutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not stop")
+	}
+}
+
+// TestElectrumClientFilterBlocks tests that FilterBlocks finds the first block
+// of the request paying to a requested address, and rejects transactions with
+// invalid merkle proofs.
+func TestElectrumClientFilterBlocks(t *testing.T) {
+	t.Parallel()
+
+	fake, addr := newFakeElectrum(t, &chaincfg.RegressionNetParams, nil)
+	watched := testAddr(t, 1)
+
+	const numBlocks = 10
+	blocks := make([]wtxmgr.BlockMeta, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		pay := payTx(t, wire.OutPoint{Index: uint32(i)}, watched, 1000)
+		block := fake.addBlock(pay)
+		blocks = append(blocks, wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   block.BlockHash(),
+				Height: int32(i + 1),
+			},
+			Time: block.Header.Timestamp,
+		})
+	}
+	empty := fake.addBlock()
+
+	client := startElectrumClient(t, &ElectrumConfig{Server: addr})
+
+	scopedIndex := waddrmgr.ScopedIndex{
+		Scope: waddrmgr.KeyScopeBIP0084,
+		Index: 7,
+	}
+	req := &FilterBlocksRequest{
+		Blocks: blocks,
+		ExternalAddrs: map[waddrmgr.ScopedIndex]btcutil.Address{
+			scopedIndex: watched,
+		},
+		InternalAddrs:    map[waddrmgr.ScopedIndex]btcutil.Address{},
+		WatchedOutPoints: map[wire.OutPoint]btcutil.Address{},
+	}
+	resp, err := client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.EqualValues(t, 0, resp.BatchIndex)
+	require.Equal(t, blocks[0], resp.BlockMeta)
+	require.Len(t, resp.RelevantTxns, 1)
+	require.Contains(t,
+		resp.FoundExternalAddrs[scopedIndex.Scope], scopedIndex.Index)
+	require.Len(t, resp.FoundOutPoints, 1)
+
+	// Continue after the first match.
+	req.Blocks = blocks[1:]
+	resp, err = client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.EqualValues(t, 0, resp.BatchIndex)
+	require.Equal(t, blocks[1], resp.BlockMeta)
+
+	// Blocks without relevant transactions are skipped.
+	req.Blocks = []wtxmgr.BlockMeta{{
+		Block: wtxmgr.Block{
+			Hash:   empty.BlockHash(),
+			Height: numBlocks + 1,
+		},
+	}}
+	resp, err = client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	// Transactions are rejected if their merkle proof doesn't match the
+	// block header.
+	fake.mtx.Lock()
+	fake.badMerkle = true
+	fake.mtx.Unlock()
+
+	req.Blocks = blocks
+	_, err = client.FilterBlocks(req)
+	require.ErrorContains(t, err, "invalid merkle proof")
+}
+
+// TestElectrumMerkleProofDepth tests that merkle branches are rejected unless
+// their length matches the depth of the block's merkle tree, so an inner node
+// of the tree can't be proven as a transaction.
+func TestElectrumMerkleProofDepth(t *testing.T) {
+	t.Parallel()
+
+	f := newFakeChain(&chaincfg.RegressionNetParams)
+	block := f.addBlock(
+		wire.NewMsgTx(1), wire.NewMsgTx(2), wire.NewMsgTx(3),
+	)
+
+	txHash := block.Transactions[2].TxHash()
+	proof := &electrumMerkleProof{
+		Merkle: merkleBranch(block, 2),
+		Pos:    2,
+	}
+	err := verifyElectrumMerkleProof(&txHash, proof, &block.Header, 2)
+	require.NoError(t, err)
+
+	// The inner node over the first two transactions verifies with a
+	// branch that is one level short.
+	var buf [chainhash.HashSize * 2]byte
+	hash0 := block.Transactions[0].TxHash()
+	hash1 := block.Transactions[1].TxHash()
+	copy(buf[:], hash0[:])
+	copy(buf[chainhash.HashSize:], hash1[:])
+	inner := chainhash.DoubleHashH(buf[:])
+	proof = &electrumMerkleProof{Merkle: merkleBranch(block, 0)[1:]}
+	err = verifyElectrumMerkleProof(&inner, proof, &block.Header, 1)
+	require.NoError(t, err)
+	err = verifyElectrumMerkleProof(&inner, proof, &block.Header, 2)
+	require.ErrorContains(t, err, "invalid merkle proof")
+
+	// Positions beyond the tree are rejected as well.
+	proof = &electrumMerkleProof{
+		Merkle: merkleBranch(block, 2),
+		Pos:    6,
+	}
+	err = verifyElectrumMerkleProof(&txHash, proof, &block.Header, 2)
+	require.ErrorContains(t, err, "invalid merkle proof")
+}
+
+// TestElectrumHeaderValidation tests that headers are rejected unless they
+// satisfy their proof of work and have the difficulty required by the
+// network.
+func TestElectrumHeaderValidation(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewElectrumClient(&ElectrumConfig{
+		Server:      "127.0.0.1:0",
+		ChainParams: &chaincfg.RegressionNetParams,
+	})
+	require.NoError(t, err)
+
+	f := newFakeChain(&chaincfg.RegressionNetParams)
+	prev := f.addBlock()
+	block := f.addBlock()
+	require.NoError(t, client.checkProofOfWork(&block.Header))
+
+	// A header that doesn't satisfy its difficulty is rejected.
+	header := block.Header
+	for {
+		hash := header.BlockHash()
+		target := blockchain.CompactToBig(header.Bits)
+		if blockchain.HashToBig(&hash).Cmp(target) > 0 {
+			break
+		}
+		header.Nonce++
+	}
+	require.Error(t, client.checkProofOfWork(&header))
+
+	// Headers must connect to the previous header.
+	err = client.checkHeaderChain(2, &block.Header, &prev.Header)
+	require.NoError(t, err)
+	err = client.checkHeaderChain(2, &block.Header, &block.Header)
+	require.ErrorContains(t, err, "does not connect")
+
+	// Without minimum difficulty blocks, the difficulty may only change
+	// at the start of a difficulty period.
+	client.cfg.ChainParams = &chaincfg.MainNetParams
+	header = block.Header
+	header.Bits = prev.Header.Bits - 1
+	err = client.checkHeaderChain(2, &header, &prev.Header)
+	require.ErrorContains(t, err, "difficulty bits")
+}
+
+// TestElectrumRetargetBits tests the difficulty required at the start of a
+// difficulty period.
+func TestElectrumRetargetBits(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+	first := &wire.BlockHeader{Timestamp: time.Unix(1_600_000_000, 0)}
+	last := &wire.BlockHeader{Bits: 0x1b0404cb}
+
+	tests := []struct {
+		name     string
+		timespan time.Duration
+		bits     uint32
+	}{{
+		name:     "on target",
+		timespan: params.TargetTimespan,
+		bits:     0x1b0404cb,
+	}, {
+		name:     "twice as fast",
+		timespan: params.TargetTimespan / 2,
+		bits:     0x1b020265,
+	}, {
+		name:     "clamped to a quarter",
+		timespan: params.TargetTimespan / 10,
+		bits:     0x1b010132,
+	}, {
+		name:     "clamped to the limit",
+		timespan: params.TargetTimespan * 100,
+		bits:     0x1b10132c,
+	}}
+	for _, test := range tests {
+		last.Timestamp = first.Timestamp.Add(test.timespan)
+		bits := electrumRetargetBits(params, last, first)
+		require.Equalf(t, test.bits, bits, "%s: got %08x", test.name,
+			bits)
+	}
+
+	// The target never exceeds the proof of work limit.
+	last.Bits = params.PowLimitBits
+	last.Timestamp = first.Timestamp.Add(params.TargetTimespan * 4)
+	require.Equal(t, params.PowLimitBits,
+		electrumRetargetBits(params, last, first))
+}
@@ -0,0 +1,1211 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultEsploraPollInterval is the default interval at which the
+	// Esplora API is polled for new blocks and mempool transactions.
+	DefaultEsploraPollInterval = 30 * time.Second
+
+	// esploraRequestTimeout is the timeout of a single HTTP request when no
+	// HTTP client is configured.
+	esploraRequestTimeout = 30 * time.Second
+
+	// esploraChainTxsPerPage is the number of confirmed transactions
+	// returned per page of a scripthash history.
+	esploraChainTxsPerPage = 25
+
+	// esploraMaxConcurrentRequests is the maximum number of scripthash
+	// history requests that are performed in parallel.
+	esploraMaxConcurrentRequests = 8
+)
+
+// EsploraConfig contains the configuration of an EsploraClient.
+type EsploraConfig struct {
+	// URL is the base URL of the Esplora HTTP API, for example
+	// https://blockstream.info/testnet/api.
+	URL string
+
+	// ChainParams are the parameters of the network the API serves.
+	ChainParams *chaincfg.Params
+
+	// HTTPClient is the client used for requests.  If nil, a client with
+	// a 30 second timeout is used.
+	HTTPClient *http.Client
+
+	// PollInterval is the interval at which the API is polled for new
+	// blocks and transactions.  DefaultEsploraPollInterval is used if
+	// zero.
+	PollInterval time.Duration
+}
+
+// esploraBlock is a block as returned by the /block/:hash endpoint.
+type esploraBlock struct {
+	ID                string `json:"id"`
+	Height            int32  `json:"height"`
+	Timestamp         int64  `json:"timestamp"`
+	PreviousBlockHash string `json:"previousblockhash"`
+}
+
+// esploraTxStatus is the confirmation status of a transaction.
+type esploraTxStatus struct {
+	Confirmed   bool   `json:"confirmed"`
+	BlockHeight int32  `json:"block_height"`
+	BlockHash   string `json:"block_hash"`
+	BlockTime   int64  `json:"block_time"`
+}
+
+// esploraTx is a transaction as returned by the scripthash history endpoints.
+// Only the fields used by the client are decoded.
+type esploraTx struct {
+	TxID   string          `json:"txid"`
+	Status esploraTxStatus `json:"status"`
+}
+
+// esploraRescan is a rescan request handled by the client's poll goroutine.
+type esploraRescan struct {
+	start chainhash.Hash
+}
+
+// EsploraClient is an implementation of the chain.Interface interface backed
+// by an Esplora-compatible HTTP API.  Instead of downloading full blocks, it
+// finds relevant transactions by querying the history of the scripts of the
+// watched addresses.  New blocks and mempool transactions are found by polling
+// the API.
+type EsploraClient struct {
+	cfg        EsploraConfig
+	baseURL    string
+	httpClient *http.Client
+
+	notificationQueue *ConcurrentQueue
+	rescanRequests    chan *esploraRescan
+	notifyBlocks      atomic.Bool
+
+	// watchMtx guards the scripts of the watched addresses, keyed by their
+	// scripthash.
+	watchMtx       sync.Mutex
+	watchedScripts map[string][]byte
+
+	// bestMtx guards the best block known to the client.
+	bestMtx   sync.RWMutex
+	bestBlock waddrmgr.BlockStamp
+
+	// notified records the transactions that have already been notified,
+	// and the hash of the block they were notified in or the zero hash for
+	// mempool transactions.  It is only accessed by the poll goroutine.
+	notified map[chainhash.Hash]chainhash.Hash
+
+	// historyMtx guards the cache of scripthash histories used by
+	// FilterBlocks.  Cached histories are only valid as long as the best
+	// block they were fetched at is unchanged.
+	historyMtx   sync.Mutex
+	historyTip   chainhash.Hash
+	historyCache map[string]*esploraHistory
+
+	ctx     context.Context
+	cancel  func()
+	started atomic.Bool
+	stopped atomic.Bool
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// esploraHistory is a cached scripthash history containing all transactions
+// confirmed at or above minHeight.
+type esploraHistory struct {
+	minHeight int32
+	txs       []esploraTx
+}
+
+// A compile-time check to ensure that EsploraClient satisfies the
+// chain.Interface interface.
+var _ Interface = (*EsploraClient)(nil)
+
+// NewEsploraClient creates a client for the Esplora API described by the
+// config.  No requests are made until the client is started.
+func NewEsploraClient(cfg *EsploraConfig) (*EsploraClient, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("missing esplora URL")
+	}
+	if cfg.ChainParams == nil {
+		return nil, errors.New("missing chain params config")
+	}
+
+	c := &EsploraClient{
+		cfg:               *cfg,
+		baseURL:           strings.TrimSuffix(cfg.URL, "/"),
+		httpClient:        cfg.HTTPClient,
+		notificationQueue: NewConcurrentQueue(20),
+		rescanRequests:    make(chan *esploraRescan),
+		watchedScripts:    make(map[string][]byte),
+		notified:          make(map[chainhash.Hash]chainhash.Hash),
+		historyCache:      make(map[string]*esploraHistory),
+		quit:              make(chan struct{}),
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: esploraRequestTimeout}
+	}
+	if c.cfg.PollInterval == 0 {
+		c.cfg.PollInterval = DefaultEsploraPollInterval
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	return c, nil
+}
+
+// BackEnd returns the name of the driver.
+func (c *EsploraClient) BackEnd() string {
+	return "esplora"
+}
+
+// Start verifies that the API serves the expected network, and starts polling
+// it for new blocks.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) Start() error {
+	if !c.started.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	genesis, err := c.GetBlockHash(0)
+	if err != nil {
+		return fmt.Errorf("unable to query genesis block: %w", err)
+	}
+	if *genesis != *c.cfg.ChainParams.GenesisHash {
+		return fmt.Errorf("expected network %v with genesis block %v, "+
+			"got genesis block %v", c.cfg.ChainParams.Name,
+			c.cfg.ChainParams.GenesisHash, genesis)
+	}
+
+	tip, err := c.tipBlock()
+	if err != nil {
+		return err
+	}
+	bestBlock, err := tip.blockStamp()
+	if err != nil {
+		return err
+	}
+	c.setBestBlock(*bestBlock)
+
+	c.notificationQueue.Start()
+	select {
+	case c.notificationQueue.ChanIn() <- ClientConnected{}:
+	case <-c.quit:
+		return errors.New("esplora client stopped")
+	}
+
+	c.wg.Add(1)
+	go c.pollHandler()
+
+	return nil
+}
+
+// Stop stops polling the API and cancels all pending requests.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) Stop() {
+	if !c.stopped.CompareAndSwap(false, true) {
+		return
+	}
+
+	c.cancel()
+	close(c.quit)
+	if c.started.Load() {
+		c.notificationQueue.Stop()
+	}
+}
+
+// WaitForShutdown blocks until the client has stopped.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// Notifications returns a channel to retrieve notifications from.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) Notifications() <-chan interface{} {
+	return c.notificationQueue.ChanOut()
+}
+
+// GetBestBlock returns the hash and height of the tip of the API's best chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	tip, err := c.tipBlock()
+	if err != nil {
+		return nil, 0, err
+	}
+	hash, err := chainhash.NewHashFromStr(tip.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash, tip.Height, nil
+}
+
+// GetBlockHeight returns the height of the block with the given hash.
+func (c *EsploraClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	block, err := c.blockInfo(hash.String())
+	if err != nil {
+		return 0, err
+	}
+	return block.Height, nil
+}
+
+// GetBlock returns the raw block with the given hash.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	raw, err := c.get("/block/" + hash.String() + "/raw")
+	if err != nil {
+		return nil, err
+	}
+	var block wire.MsgBlock
+	if err := block.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// GetBlockHash returns the hash of the block at the given height of the best
+// chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return c.getHash("/block-height/" + strconv.FormatInt(height, 10))
+}
+
+// GetBlockHeader returns the header of the block with the given hash.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	raw, err := c.getHex("/block/" + hash.String() + "/header")
+	if err != nil {
+		return nil, err
+	}
+	var header wire.BlockHeader
+	if err := header.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+// IsCurrent returns whether the tip of the API's best chain is recent.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) IsCurrent() bool {
+	tip, err := c.tipBlock()
+	if err != nil {
+		return false
+	}
+	return time.Unix(tip.Timestamp, 0).After(
+		time.Now().Add(-isCurrentDelta),
+	)
+}
+
+// BlockStamp returns the latest block notified by the client.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	c.bestMtx.RLock()
+	defer c.bestMtx.RUnlock()
+
+	bestBlock := c.bestBlock
+	return &bestBlock, nil
+}
+
+// SendRawTransaction broadcasts the transaction through the API.  High fees
+// can not be rejected by the API, so allowHighFees is ignored.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	body := strings.NewReader(hex.EncodeToString(buf.Bytes()))
+	resp, err := c.do(http.MethodPost, "/tx", body)
+	if err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(strings.TrimSpace(string(resp)))
+}
+
+// TestMempoolAccept is not supported by the Esplora API.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) TestMempoolAccept(txns []*wire.MsgTx,
+	maxFeeRate float64) ([]*btcjson.TestMempoolAcceptResult, error) {
+
+	return nil, ErrUnimplemented
+}
+
+// MapRPCErr maps an error returned by the API to an error defined in this
+// package.  Esplora relays the errors of the bitcoind node it is backed by, so
+// the bitcoind error strings are matched.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) MapRPCErr(rpcErr error) error {
+	for i := uint32(0); i < uint32(errSentinel); i++ {
+		err := RPCErr(i)
+		if matchErrStr(rpcErr, err.Error()) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %v", ErrUndefined, rpcErr)
+}
+
+// NotifyReceived adds the addresses to the watch list.  Transactions paying
+// to or spending from them are notified as they are found in the mempool or
+// in new blocks.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) NotifyReceived(addrs []btcutil.Address) error {
+	return c.watchAddrs(addrs)
+}
+
+// NotifyBlocks enables BlockConnected notifications for new blocks.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) NotifyBlocks() error {
+	c.notifyBlocks.Store(true)
+	return nil
+}
+
+// Rescan adds the addresses and the addresses of the outpoints to the watch
+// list, and notifies all transactions relevant to the watch list confirmed
+// from the block with the given hash up to the best block, followed by a
+// RescanFinished notification.  The rescan is performed asynchronously.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	if !c.started.Load() {
+		return errors.New("can't do a rescan when the chain client " +
+			"is not started")
+	}
+
+	watch := make([]btcutil.Address, 0, len(addrs)+len(outPoints))
+	watch = append(watch, addrs...)
+	for op, addr := range outPoints {
+		// Spends of an outpoint are part of the history of the
+		// script it pays to, so watching its address is sufficient.
+		if addr == nil {
+			log.Warnf("Unable to watch outpoint %v without an "+
+				"address", op)
+			continue
+		}
+		watch = append(watch, addr)
+	}
+	if err := c.watchAddrs(watch); err != nil {
+		return err
+	}
+
+	select {
+	case c.rescanRequests <- &esploraRescan{start: *startHash}:
+		return nil
+	case <-c.quit:
+		return errors.New("esplora client stopped")
+	}
+}
+
+// FilterBlocks scans the blocks contained in the FilterBlocksRequest for any
+// addresses of interest.  Rather than fetching each block, the histories of
+// the scripts of the requested addresses and outpoints are queried, and only
+// the transactions found in them are filtered.  This method returns a
+// FilterBlocksResponse for the first block containing a matching address.  If
+// no matches are found in the range of blocks requested, the returned response
+// will be nil.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *EsploraClient) FilterBlocks(
+	req *FilterBlocksRequest) (*FilterBlocksResponse, error) {
+
+	if len(req.Blocks) == 0 {
+		return nil, nil
+	}
+
+	blockIndex := make(map[string]int, len(req.Blocks))
+	minHeight := req.Blocks[0].Height
+	for i, block := range req.Blocks {
+		blockIndex[block.Hash.String()] = i
+		if block.Height < minHeight {
+			minHeight = block.Height
+		}
+	}
+
+	addrs := make([]btcutil.Address, 0, len(req.ExternalAddrs)+
+		len(req.InternalAddrs)+len(req.WatchedOutPoints))
+	for _, addr := range req.ExternalAddrs {
+		addrs = append(addrs, addr)
+	}
+	for _, addr := range req.InternalAddrs {
+		addrs = append(addrs, addr)
+	}
+	for _, addr := range req.WatchedOutPoints {
+		if addr != nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	scripts, err := addrScripts(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := c.histories(scripts, minHeight, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the transactions found by the index of their block within
+	// the request.
+	blockTxs := make(map[int]map[string]struct{})
+	for _, history := range histories {
+		for _, tx := range history {
+			i, ok := blockIndex[tx.Status.BlockHash]
+			if !tx.Status.Confirmed || !ok {
+				continue
+			}
+			if blockTxs[i] == nil {
+				blockTxs[i] = make(map[string]struct{})
+			}
+			blockTxs[i][tx.TxID] = struct{}{}
+		}
+	}
+
+	indexes := make([]int, 0, len(blockTxs))
+	for i := range blockTxs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	blockFilterer := NewBlockFilterer(c.cfg.ChainParams, req)
+	for _, i := range indexes {
+		txs, err := c.fetchTxs(blockTxs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		var relevant bool
+		for _, tx := range sortTxsByDependency(txs) {
+			if blockFilterer.FilterTx(tx) {
+				blockFilterer.RelevantTxns = append(
+					blockFilterer.RelevantTxns, tx,
+				)
+				relevant = true
+			}
+		}
+		if !relevant {
+			continue
+		}
+
+		return &FilterBlocksResponse{
+			BatchIndex:         uint32(i),
+			BlockMeta:          req.Blocks[i],
+			FoundExternalAddrs: blockFilterer.FoundExternal,
+			FoundInternalAddrs: blockFilterer.FoundInternal,
+			FoundOutPoints:     blockFilterer.FoundOutPoints,
+			RelevantTxns:       blockFilterer.RelevantTxns,
+		}, nil
+	}
+
+	// No addresses were found for this range.
+	return nil, nil
+}
+
+// pollHandler polls the API for new blocks and transactions, and performs
+// rescans.  Both are handled by the same goroutine so notifications are
+// dispatched in order.
+//
+// NOTE: This must be run as a goroutine.
+func (c *EsploraClient) pollHandler() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case req := <-c.rescanRequests:
+			if err := c.rescan(req.start); err != nil {
+				log.Errorf("Unable to rescan from block %v: %v",
+					req.start, err)
+			}
+
+		case <-ticker.C:
+			if err := c.poll(); err != nil {
+				log.Errorf("Unable to poll esplora: %v", err)
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// poll synchronizes the best block of the client with the tip of the API,
+// notifying disconnected and connected blocks and the relevant transactions
+// found in them and in the mempool.
+func (c *EsploraClient) poll() error {
+	tip, err := c.tipBlock()
+	if err != nil {
+		return err
+	}
+
+	c.bestMtx.RLock()
+	bestBlock := c.bestBlock
+	c.bestMtx.RUnlock()
+
+	// Walk back from the tip to the height of our best block, collecting
+	// the blocks to connect.
+	var connect []*esploraBlock
+	block := tip
+	for block.Height > bestBlock.Height {
+		connect = append(connect, block)
+		block, err = c.blockInfo(block.PreviousBlockHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Disconnect our blocks until we find the common ancestor.
+	for bestBlock.Height > block.Height ||
+		block.ID != bestBlock.Hash.String() {
+
+		prev, err := c.disconnectBlock(bestBlock)
+		if err != nil {
+			return err
+		}
+		bestBlock = *prev
+
+		if block.Height > bestBlock.Height {
+			connect = append(connect, block)
+			block, err = c.blockInfo(block.PreviousBlockHash)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Find the relevant transactions confirmed in the new blocks and in
+	// the mempool.
+	scripts := c.watchedScriptList()
+	histories, err := c.histories(scripts, bestBlock.Height+1, false)
+	if err != nil {
+		return err
+	}
+	confirmed, unconfirmed := c.newTxs(histories)
+
+	for i := len(connect) - 1; i >= 0; i-- {
+		blockStamp, err := connect[i].blockStamp()
+		if err != nil {
+			return err
+		}
+		err = c.notifyTxs(confirmed[blockStamp.Hash.String()], blockStamp)
+		if err != nil {
+			return err
+		}
+
+		c.setBestBlock(*blockStamp)
+		if c.notifyBlocks.Load() {
+			c.notify(BlockConnected(blockMeta(blockStamp)))
+		}
+	}
+
+	return c.notifyTxs(unconfirmed, nil)
+}
+
+// disconnectBlock notifies the disconnection of the block and returns the
+// block preceding it.
+func (c *EsploraClient) disconnectBlock(
+	blockStamp waddrmgr.BlockStamp) (*waddrmgr.BlockStamp, error) {
+
+	block, err := c.blockInfo(blockStamp.Hash.String())
+	if err != nil {
+		return nil, err
+	}
+	prevBlock, err := c.blockInfo(block.PreviousBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := prevBlock.blockStamp()
+	if err != nil {
+		return nil, err
+	}
+
+	// Transactions of the disconnected block must be notified again when
+	// they are confirmed in another block.
+	for txid, blockHash := range c.notified {
+		if blockHash == blockStamp.Hash {
+			delete(c.notified, txid)
+		}
+	}
+
+	c.setBestBlock(*prev)
+	c.notify(BlockDisconnected(blockMeta(&blockStamp)))
+
+	return prev, nil
+}
+
+// rescan notifies all transactions relevant to the watch list confirmed from
+// the block with the given hash up to the best block, and those in the
+// mempool.  Transactions confirmed in blocks that are not connected yet are
+// left to be notified by the next poll.
+func (c *EsploraClient) rescan(start chainhash.Hash) error {
+	startBlock, err := c.blockInfo(start.String())
+	if err != nil {
+		return err
+	}
+
+	c.bestMtx.RLock()
+	bestBlock := c.bestBlock
+	c.bestMtx.RUnlock()
+
+	scripts := c.watchedScriptList()
+	histories, err := c.histories(scripts, startBlock.Height, false)
+	if err != nil {
+		return err
+	}
+
+	// A rescan notifies all relevant transactions again, even if they
+	// were notified before.
+	txs := make(map[string]*esploraTx)
+	for _, history := range histories {
+		for i := range history {
+			tx := &history[i]
+			if tx.Status.Confirmed &&
+				tx.Status.BlockHeight > bestBlock.Height {
+
+				continue
+			}
+			txs[tx.TxID] = tx
+		}
+	}
+
+	blocks := make(map[string]*waddrmgr.BlockStamp)
+	confirmed := make(map[string]map[string]struct{})
+	unconfirmed := make(map[string]struct{})
+	for txid, tx := range txs {
+		if !tx.Status.Confirmed {
+			unconfirmed[txid] = struct{}{}
+			continue
+		}
+		blockStamp, err := tx.Status.blockStamp()
+		if err != nil {
+			return err
+		}
+		hash := tx.Status.BlockHash
+		blocks[hash] = blockStamp
+		if confirmed[hash] == nil {
+			confirmed[hash] = make(map[string]struct{})
+		}
+		confirmed[hash][txid] = struct{}{}
+	}
+
+	sorted := make([]*waddrmgr.BlockStamp, 0, len(blocks))
+	for _, blockStamp := range blocks {
+		sorted = append(sorted, blockStamp)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Height < sorted[j].Height
+	})
+
+	for _, blockStamp := range sorted {
+		err := c.notifyTxs(
+			confirmed[blockStamp.Hash.String()], blockStamp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.notifyTxs(unconfirmed, nil); err != nil {
+		return err
+	}
+
+	c.notify(&RescanFinished{
+		Hash:   &bestBlock.Hash,
+		Height: bestBlock.Height,
+		Time:   bestBlock.Timestamp,
+	})
+
+	return nil
+}
+
+// newTxs returns the transactions of the histories that have not been
+// notified yet, grouped by the hash of their block, and the unconfirmed ones.
+func (c *EsploraClient) newTxs(histories [][]esploraTx) (
+	map[string]map[string]struct{}, map[string]struct{}) {
+
+	confirmed := make(map[string]map[string]struct{})
+	unconfirmed := make(map[string]struct{})
+	for _, history := range histories {
+		for _, tx := range history {
+			txHash, err := chainhash.NewHashFromStr(tx.TxID)
+			if err != nil {
+				continue
+			}
+			notifiedIn, ok := c.notified[*txHash]
+
+			if !tx.Status.Confirmed {
+				if !ok {
+					unconfirmed[tx.TxID] = struct{}{}
+				}
+				continue
+			}
+
+			if ok && notifiedIn.String() == tx.Status.BlockHash {
+				continue
+			}
+			hash := tx.Status.BlockHash
+			if confirmed[hash] == nil {
+				confirmed[hash] = make(map[string]struct{})
+			}
+			confirmed[hash][tx.TxID] = struct{}{}
+		}
+	}
+
+	return confirmed, unconfirmed
+}
+
+// notifyTxs fetches the transactions and notifies them as RelevantTx
+// notifications in dependency order.  A nil block notifies unconfirmed
+// transactions.
+func (c *EsploraClient) notifyTxs(txids map[string]struct{},
+	block *waddrmgr.BlockStamp) error {
+
+	if len(txids) == 0 {
+		return nil
+	}
+
+	txs, err := c.fetchTxs(txids)
+	if err != nil {
+		return err
+	}
+
+	var (
+		meta      *wtxmgr.BlockMeta
+		blockHash chainhash.Hash
+		received  = time.Now()
+	)
+	if block != nil {
+		m := blockMeta(block)
+		meta = &m
+		blockHash = block.Hash
+		received = block.Timestamp
+	}
+
+	for _, tx := range sortTxsByDependency(txs) {
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, received)
+		if err != nil {
+			return err
+		}
+		c.notified[rec.Hash] = blockHash
+		c.notify(RelevantTx{
+			TxRecord: rec,
+			Block:    meta,
+		})
+	}
+
+	return nil
+}
+
+// notify queues a notification unless the client is stopping.
+func (c *EsploraClient) notify(n interface{}) {
+	select {
+	case c.notificationQueue.ChanIn() <- n:
+	case <-c.quit:
+	}
+}
+
+// setBestBlock sets the best block known to the client.
+func (c *EsploraClient) setBestBlock(bestBlock waddrmgr.BlockStamp) {
+	c.bestMtx.Lock()
+	c.bestBlock = bestBlock
+	c.bestMtx.Unlock()
+}
+
+// watchAddrs adds the scripts of the addresses to the watch list.
+func (c *EsploraClient) watchAddrs(addrs []btcutil.Address) error {
+	scripts, err := addrScripts(addrs)
+	if err != nil {
+		return err
+	}
+
+	c.watchMtx.Lock()
+	for scriptHash, script := range scripts {
+		c.watchedScripts[scriptHash] = script
+	}
+	c.watchMtx.Unlock()
+
+	return nil
+}
+
+// watchedScriptList returns a copy of the watch list.
+func (c *EsploraClient) watchedScriptList() map[string][]byte {
+	c.watchMtx.Lock()
+	defer c.watchMtx.Unlock()
+
+	scripts := make(map[string][]byte, len(c.watchedScripts))
+	for scriptHash, script := range c.watchedScripts {
+		scripts[scriptHash] = script
+	}
+	return scripts
+}
+
+// histories queries the histories of the scripts, containing all mempool
+// transactions and those confirmed at or above minHeight.  If useCache is set,
+// histories fetched at the current best block are reused.
+func (c *EsploraClient) histories(scripts map[string][]byte, minHeight int32,
+	useCache bool) ([][]esploraTx, error) {
+
+	c.bestMtx.RLock()
+	tip := c.bestBlock.Hash
+	c.bestMtx.RUnlock()
+
+	histories := make([][]esploraTx, 0, len(scripts))
+	var fetch []string
+
+	c.historyMtx.Lock()
+	if c.historyTip != tip {
+		c.historyTip = tip
+		c.historyCache = make(map[string]*esploraHistory)
+	}
+	for scriptHash := range scripts {
+		cached, ok := c.historyCache[scriptHash]
+		if useCache && ok && cached.minHeight <= minHeight {
+			histories = append(histories, cached.txs)
+			continue
+		}
+		fetch = append(fetch, scriptHash)
+	}
+	c.historyMtx.Unlock()
+
+	var mtx sync.Mutex
+	g, _ := errgroup.WithContext(c.ctx)
+	g.SetLimit(esploraMaxConcurrentRequests)
+	for _, scriptHash := range fetch {
+		scriptHash := scriptHash
+		g.Go(func() error {
+			txs, err := c.scriptHashHistory(scriptHash, minHeight)
+			if err != nil {
+				return err
+			}
+
+			mtx.Lock()
+			histories = append(histories, txs)
+			mtx.Unlock()
+
+			c.historyMtx.Lock()
+			if c.historyTip == tip {
+				c.historyCache[scriptHash] = &esploraHistory{
+					minHeight: minHeight,
+					txs:       txs,
+				}
+			}
+			c.historyMtx.Unlock()
+
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// scriptHashHistory returns the mempool transactions of a script and those
+// confirmed at or above minHeight.  The confirmed history is returned newest
+// first and is paged through until a transaction below minHeight is reached.
+func (c *EsploraClient) scriptHashHistory(scriptHash string,
+	minHeight int32) ([]esploraTx, error) {
+
+	var history []esploraTx
+	path := "/scripthash/" + scriptHash + "/txs"
+	for {
+		var page []esploraTx
+		if err := c.getJSON(path, &page); err != nil {
+			return nil, err
+		}
+
+		var numConfirmed int
+		var lastConfirmed string
+		for _, tx := range page {
+			if !tx.Status.Confirmed {
+				history = append(history, tx)
+				continue
+			}
+			if tx.Status.BlockHeight < minHeight {
+				return history, nil
+			}
+			history = append(history, tx)
+			numConfirmed++
+			lastConfirmed = tx.TxID
+		}
+
+		if numConfirmed < esploraChainTxsPerPage {
+			return history, nil
+		}
+		path = "/scripthash/" + scriptHash + "/txs/chain/" +
+			lastConfirmed
+	}
+}
+
+// fetchTxs fetches the raw transactions with the given IDs.
+func (c *EsploraClient) fetchTxs(txids map[string]struct{}) ([]*wire.MsgTx,
+	error) {
+
+	var mtx sync.Mutex
+	txs := make([]*wire.MsgTx, 0, len(txids))
+
+	g, _ := errgroup.WithContext(c.ctx)
+	g.SetLimit(esploraMaxConcurrentRequests)
+	for txid := range txids {
+		txid := txid
+		g.Go(func() error {
+			raw, err := c.getHex("/tx/" + txid + "/hex")
+			if err != nil {
+				return err
+			}
+			var tx wire.MsgTx
+			err = tx.Deserialize(bytes.NewReader(raw))
+			if err != nil {
+				return fmt.Errorf("unable to decode tx %s: %w",
+					txid, err)
+			}
+
+			mtx.Lock()
+			txs = append(txs, &tx)
+			mtx.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// tipBlock returns the tip of the API's best chain.
+func (c *EsploraClient) tipBlock() (*esploraBlock, error) {
+	hash, err := c.getHash("/blocks/tip/hash")
+	if err != nil {
+		return nil, err
+	}
+	return c.blockInfo(hash.String())
+}
+
+// blockInfo returns the block with the given hash.
+func (c *EsploraClient) blockInfo(hash string) (*esploraBlock, error) {
+	var block esploraBlock
+	if err := c.getJSON("/block/"+hash, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// get performs a GET request for the path relative to the API's base URL.
+func (c *EsploraClient) get(path string) ([]byte, error) {
+	return c.do(http.MethodGet, path, nil)
+}
+
+// getJSON performs a GET request and decodes the JSON response into v.
+func (c *EsploraClient) getJSON(path string, v interface{}) error {
+	resp, err := c.get(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(resp, v); err != nil {
+		return fmt.Errorf("unable to decode response of %s: %w", path,
+			err)
+	}
+	return nil
+}
+
+// getHex performs a GET request and decodes the hex encoded response.
+func (c *EsploraClient) getHex(path string) ([]byte, error) {
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimSpace(string(resp)))
+}
+
+// getHash performs a GET request and decodes the block or transaction hash in
+// the response.
+func (c *EsploraClient) getHash(path string) (*chainhash.Hash, error) {
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(strings.TrimSpace(string(resp)))
+}
+
+// do performs a request for the path relative to the API's base URL and
+// returns the body of a successful response.  The body of an unsuccessful
+// response is returned as the error message.
+func (c *EsploraClient) do(method, path string, body io.Reader) ([]byte,
+	error) {
+
+	req, err := http.NewRequestWithContext(
+		c.ctx, method, c.baseURL+path, body,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("esplora %s %s: %s (status %d)", method,
+			path, strings.TrimSpace(string(respBody)),
+			resp.StatusCode)
+	}
+
+	return respBody, nil
+}
+
+// blockStamp returns the block stamp of the block.
+func (b *esploraBlock) blockStamp() (*waddrmgr.BlockStamp, error) {
+	hash, err := chainhash.NewHashFromStr(b.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &waddrmgr.BlockStamp{
+		Hash:      *hash,
+		Height:    b.Height,
+		Timestamp: time.Unix(b.Timestamp, 0),
+	}, nil
+}
+
+// blockStamp returns the block stamp of the block a transaction is confirmed
+// in.
+func (s *esploraTxStatus) blockStamp() (*waddrmgr.BlockStamp, error) {
+	hash, err := chainhash.NewHashFromStr(s.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return &waddrmgr.BlockStamp{
+		Hash:      *hash,
+		Height:    s.BlockHeight,
+		Timestamp: time.Unix(s.BlockTime, 0),
+	}, nil
+}
+
+// blockMeta returns the block metadata of a block stamp.
+func blockMeta(blockStamp *waddrmgr.BlockStamp) wtxmgr.BlockMeta {
+	return wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   blockStamp.Hash,
+			Height: blockStamp.Height,
+		},
+		Time: blockStamp.Timestamp,
+	}
+}
+
+// esploraScriptHash returns the scripthash used to query the history of an
+// output script, which is the hex encoded SHA256 of the script.
+func esploraScriptHash(pkScript []byte) string {
+	h := sha256.Sum256(pkScript)
+	return hex.EncodeToString(h[:])
+}
+
+// addrScripts returns the output scripts of the addresses keyed by their
+// scripthash.
+func addrScripts(addrs []btcutil.Address) (map[string][]byte, error) {
+	scripts := make(map[string][]byte, len(addrs))
+	for _, addr := range addrs {
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		scripts[esploraScriptHash(script)] = script
+	}
+	return scripts, nil
+}
+
+// sortTxsByDependency sorts transactions so that every transaction comes
+// after the transactions it spends from.  Transactions without dependencies
+// between them are sorted by hash to make the order deterministic.
+func sortTxsByDependency(txs []*wire.MsgTx) []*wire.MsgTx {
+	byHash := make(map[chainhash.Hash]*wire.MsgTx, len(txs))
+	hashes := make([]chainhash.Hash, 0, len(txs))
+	for _, tx := range txs {
+		hash := tx.TxHash()
+		byHash[hash] = tx
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+
+	sorted := make([]*wire.MsgTx, 0, len(txs))
+	visited := make(map[chainhash.Hash]bool, len(txs))
+	var visit func(hash chainhash.Hash)
+	visit = func(hash chainhash.Hash) {
+		if visited[hash] {
+			return
+		}
+		visited[hash] = true
+
+		tx := byHash[hash]
+		for _, in := range tx.TxIn {
+			parent := in.PreviousOutPoint.Hash
+			if _, ok := byHash[parent]; ok {
+				visit(parent)
+			}
+		}
+		sorted = append(sorted, tx)
+	}
+	for _, hash := range hashes {
+		visit(hash)
+	}
+
+	return sorted
+}
@@ -0,0 +1,523 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEsplora is an in-memory implementation of the subset of the Esplora HTTP
+// API used by the EsploraClient.
+type fakeEsplora struct {
+	*fakeChain
+
+	srv *httptest.Server
+}
+
+func newFakeEsplora(t *testing.T, params *chaincfg.Params) (*fakeEsplora,
+	string) {
+
+	t.Helper()
+
+	f := &fakeEsplora{fakeChain: newFakeChain(params)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /blocks/tip/hash", f.handleTipHash)
+	mux.HandleFunc("GET /block-height/{height}", f.handleBlockHeight)
+	mux.HandleFunc("GET /block/{hash}", f.handleBlock)
+	mux.HandleFunc("GET /block/{hash}/header", f.handleBlockHeader)
+	mux.HandleFunc("GET /block/{hash}/raw", f.handleBlockRaw)
+	mux.HandleFunc("GET /tx/{txid}/hex", f.handleTxHex)
+	mux.HandleFunc("GET /scripthash/{hash}/txs", f.handleHistory)
+	mux.HandleFunc("GET /scripthash/{hash}/txs/chain/{last}", f.handleHistory)
+	mux.HandleFunc("POST /tx", f.handlePostTx)
+
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+
+	return f, f.srv.URL
+}
+
+// touches returns whether the transaction pays to or spends from an output
+// script with the given scripthash.
+func (f *fakeEsplora) touches(tx *wire.MsgTx, scriptHash string) bool {
+	for _, pkScript := range f.pkScripts(tx) {
+		if esploraScriptHash(pkScript) == scriptHash {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeEsplora) txJSON(tx *wire.MsgTx, block *wire.MsgBlock) esploraTx {
+	res := esploraTx{TxID: tx.TxHash().String()}
+	if block != nil {
+		hash := block.BlockHash()
+		res.Status = esploraTxStatus{
+			Confirmed:   true,
+			BlockHeight: f.heights[hash],
+			BlockHash:   hash.String(),
+			BlockTime:   block.Header.Timestamp.Unix(),
+		}
+	}
+	return res
+}
+
+func (f *fakeEsplora) handleTipHash(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	fmt.Fprint(w, f.chain[len(f.chain)-1].BlockHash())
+}
+
+func (f *fakeEsplora) handleBlockHeight(w http.ResponseWriter,
+	r *http.Request) {
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	height, err := strconv.Atoi(r.PathValue("height"))
+	if err != nil || height < 0 || height >= len(f.chain) {
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	fmt.Fprint(w, f.chain[height].BlockHash())
+}
+
+func (f *fakeEsplora) block(w http.ResponseWriter,
+	r *http.Request) *wire.MsgBlock {
+
+	hash, err := chainhash.NewHashFromStr(r.PathValue("hash"))
+	if err != nil {
+		http.Error(w, "Invalid hash", http.StatusBadRequest)
+		return nil
+	}
+	block, ok := f.blocks[*hash]
+	if !ok {
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return nil
+	}
+	return block
+}
+
+func (f *fakeEsplora) handleBlock(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	block := f.block(w, r)
+	if block == nil {
+		return
+	}
+	hash := block.BlockHash()
+	_ = json.NewEncoder(w).Encode(esploraBlock{
+		ID:                hash.String(),
+		Height:            f.heights[hash],
+		Timestamp:         block.Header.Timestamp.Unix(),
+		PreviousBlockHash: block.Header.PrevBlock.String(),
+	})
+}
+
+func (f *fakeEsplora) handleBlockHeader(w http.ResponseWriter,
+	r *http.Request) {
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	block := f.block(w, r)
+	if block == nil {
+		return
+	}
+	var buf bytes.Buffer
+	_ = block.Header.Serialize(&buf)
+	fmt.Fprint(w, hex.EncodeToString(buf.Bytes()))
+}
+
+func (f *fakeEsplora) handleBlockRaw(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	block := f.block(w, r)
+	if block == nil {
+		return
+	}
+	_ = block.Serialize(w)
+}
+
+func (f *fakeEsplora) handleTxHex(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	tx, _ := f.tx(r.PathValue("txid"))
+	if tx == nil {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	var buf bytes.Buffer
+	_ = tx.Serialize(&buf)
+	fmt.Fprint(w, hex.EncodeToString(buf.Bytes()))
+}
+
+// handleHistory serves the mempool transactions followed by the first page of
+// confirmed transactions of a scripthash, newest first, or the page of
+// confirmed transactions following the last seen one.
+func (f *fakeEsplora) handleHistory(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	scriptHash := r.PathValue("hash")
+	last := r.PathValue("last")
+
+	var page []esploraTx
+	if last == "" {
+		for i := len(f.mempool) - 1; i >= 0; i-- {
+			if f.touches(f.mempool[i], scriptHash) {
+				page = append(page, f.txJSON(f.mempool[i], nil))
+			}
+		}
+	}
+
+	var numConfirmed int
+	seenLast := last == ""
+	for h := len(f.chain) - 1; h >= 0; h-- {
+		block := f.chain[h]
+		for i := len(block.Transactions) - 1; i >= 0; i-- {
+			tx := block.Transactions[i]
+			if !f.touches(tx, scriptHash) {
+				continue
+			}
+			if !seenLast {
+				seenLast = tx.TxHash().String() == last
+				continue
+			}
+			if numConfirmed == esploraChainTxsPerPage {
+				break
+			}
+			page = append(page, f.txJSON(tx, block))
+			numConfirmed++
+		}
+	}
+
+	if page == nil {
+		page = []esploraTx{}
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func (f *fakeEsplora) handlePostTx(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.rejectTx != "" {
+		http.Error(w, f.rejectTx, http.StatusBadRequest)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	raw, err := hex.DecodeString(string(body))
+	if err != nil {
+		http.Error(w, "Invalid hex", http.StatusBadRequest)
+		return
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		http.Error(w, "TX decode failed", http.StatusBadRequest)
+		return
+	}
+	f.published = append(f.published, &tx)
+	f.mempool = append(f.mempool, &tx)
+	fmt.Fprint(w, tx.TxHash())
+}
+
+func startEsploraClient(t *testing.T, url string,
+	pollInterval time.Duration) *EsploraClient {
+
+	t.Helper()
+
+	client, err := NewEsploraClient(&EsploraConfig{
+		URL:          url,
+		ChainParams:  &chaincfg.RegressionNetParams,
+		PollInterval: pollInterval,
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Start())
+	t.Cleanup(func() {
+		client.Stop()
+		client.WaitForShutdown()
+	})
+
+	require.IsType(t, ClientConnected{}, nextNotification(t, client))
+	return client
+}
+
+// TestEsploraClientQueries tests the chain queries and transaction broadcast
+// of the Esplora client.
+func TestEsploraClientQueries(t *testing.T) {
+	t.Parallel()
+
+	fake, url := newFakeEsplora(t, &chaincfg.RegressionNetParams)
+	fake.addBlock()
+	block2 := fake.addBlock()
+	block3 := fake.addBlock()
+
+	// The client refuses to start against an API serving another
+	// network.
+	wrongNet, err := NewEsploraClient(&EsploraConfig{
+		URL:         url,
+		ChainParams: &chaincfg.MainNetParams,
+	})
+	require.NoError(t, err)
+	require.Error(t, wrongNet.Start())
+	wrongNet.Stop()
+
+	client := startEsploraClient(t, url, time.Hour)
+	require.Equal(t, "esplora", client.BackEnd())
+	require.Contains(t, BackEnds(), client.BackEnd())
+
+	hash, height, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.Equal(t, block3.BlockHash(), *hash)
+	require.EqualValues(t, 3, height)
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, block3.BlockHash(), bs.Hash)
+	require.EqualValues(t, 3, bs.Height)
+
+	hash, err = client.GetBlockHash(2)
+	require.NoError(t, err)
+	require.Equal(t, block2.BlockHash(), *hash)
+
+	_, err = client.GetBlockHash(4)
+	require.Error(t, err)
+
+	header, err := client.GetBlockHeader(hash)
+	require.NoError(t, err)
+	require.Equal(t, block2.Header, *header)
+
+	block, err := client.GetBlock(hash)
+	require.NoError(t, err)
+	require.Equal(t, block2.BlockHash(), block.BlockHash())
+	require.Len(t, block.Transactions, 1)
+
+	height, err = client.GetBlockHeight(hash)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, height)
+
+	// The regtest genesis block is too old for the client to be
+	// current.
+	require.False(t, client.IsCurrent())
+
+	tx := payTx(t, wire.OutPoint{Index: 1}, testAddr(t, 1), 1000)
+	txid, err := client.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), *txid)
+	fake.mtx.Lock()
+	require.Len(t, fake.published, 1)
+	fake.rejectTx = `sendrawtransaction RPC error: {"code":-26,` +
+		`"message":"mempool min fee not met"}`
+	fake.mtx.Unlock()
+
+	_, err = client.SendRawTransaction(tx, false)
+	require.Error(t, err)
+	require.ErrorIs(t, client.MapRPCErr(err), ErrMempoolMinFeeNotMet)
+
+	_, err = client.TestMempoolAccept([]*wire.MsgTx{tx}, 0)
+	require.ErrorIs(t, err, ErrUnimplemented)
+}
+
+// TestEsploraClientRescan tests that a rescan notifies the confirmed and
+// unconfirmed transactions paying to and spending from the watched addresses
+// in order, followed by a RescanFinished notification.
+func TestEsploraClientRescan(t *testing.T) {
+	t.Parallel()
+
+	fake, url := newFakeEsplora(t, &chaincfg.RegressionNetParams)
+	addr := testAddr(t, 1)
+	other := testAddr(t, 2)
+
+	fake.addBlock()
+	pay := payTx(t, wire.OutPoint{Index: 1}, addr, 1000)
+	unrelated := payTx(t, wire.OutPoint{Index: 2}, other, 1000)
+	block2 := fake.addBlock(unrelated, pay)
+
+	// A transaction spending the wallet's output in the same block as
+	// another payment to it, which must be notified after the
+	// transaction it spends from.
+	pay2 := payTx(t, wire.OutPoint{Index: 3}, addr, 2000)
+	spend := payTx(t, wire.OutPoint{Hash: pay2.TxHash()}, other, 1500)
+	block3 := fake.addBlock(spend, pay2)
+	fake.addBlock()
+
+	unconfirmed := payTx(t, wire.OutPoint{Index: 4}, addr, 3000)
+	fake.addMempoolTx(unconfirmed)
+
+	// Polling is effectively disabled so only the rescans notify
+	// transactions.
+	client := startEsploraClient(t, url, time.Hour)
+	err := client.Rescan(
+		&block2.Header.PrevBlock, []btcutil.Address{addr}, nil,
+	)
+	require.NoError(t, err)
+
+	requireRelevantTx(t, nextNotification(t, client), pay, block2)
+	requireRelevantTx(t, nextNotification(t, client), pay2, block3)
+	requireRelevantTx(t, nextNotification(t, client), spend, block3)
+	requireRelevantTx(t, nextNotification(t, client), unconfirmed, nil)
+
+	finished, ok := nextNotification(t, client).(*RescanFinished)
+	require.True(t, ok)
+	require.EqualValues(t, 4, finished.Height)
+
+	// Rescanning from the block after the payments only notifies the
+	// mempool transaction.
+	tip, _, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.NoError(t, client.Rescan(tip, nil, nil))
+	requireRelevantTx(t, nextNotification(t, client), unconfirmed, nil)
+	require.IsType(t, &RescanFinished{}, nextNotification(t, client))
+}
+
+// TestEsploraClientNotifications tests that new blocks, relevant transactions
+// and reorganizations are found by polling the API.
+func TestEsploraClientNotifications(t *testing.T) {
+	t.Parallel()
+
+	fake, url := newFakeEsplora(t, &chaincfg.RegressionNetParams)
+	addr := testAddr(t, 1)
+	fake.addBlock()
+
+	client := startEsploraClient(t, url, 10*time.Millisecond)
+	require.NoError(t, client.NotifyBlocks())
+	require.NoError(t, client.NotifyReceived([]btcutil.Address{addr}))
+
+	requireConnected := func(n interface{}, block *wire.MsgBlock,
+		height int32) {
+
+		t.Helper()
+
+		connected, ok := n.(BlockConnected)
+		require.True(t, ok, "expected BlockConnected, got %T", n)
+		require.Equal(t, block.BlockHash(), connected.Hash)
+		require.Equal(t, height, connected.Height)
+	}
+
+	// A payment first seen in the mempool is notified as unconfirmed,
+	// and again once it is mined.
+	pay := payTx(t, wire.OutPoint{Index: 1}, addr, 1000)
+	fake.addMempoolTx(pay)
+	requireRelevantTx(t, nextNotification(t, client), pay, nil)
+
+	block2 := fake.addBlock(pay)
+	requireRelevantTx(t, nextNotification(t, client), pay, block2)
+	requireConnected(nextNotification(t, client), block2, 2)
+
+	// Replace the block containing the payment with two new blocks, the
+	// second of which confirms the payment again.
+	fake.reorg(1, nil, []*wire.MsgTx{pay})
+	fake.mtx.Lock()
+	newBlock2, newBlock3 := fake.chain[2], fake.chain[3]
+	fake.mtx.Unlock()
+
+	disconnected, ok := nextNotification(t, client).(BlockDisconnected)
+	require.True(t, ok)
+	require.Equal(t, block2.BlockHash(), disconnected.Hash)
+	requireConnected(nextNotification(t, client), newBlock2, 2)
+	requireRelevantTx(t, nextNotification(t, client), pay, newBlock3)
+	requireConnected(nextNotification(t, client), newBlock3, 3)
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, newBlock3.BlockHash(), bs.Hash)
+}
+
+// TestEsploraClientFilterBlocks tests that FilterBlocks finds the first block
+// of the request paying to a requested address, including blocks only found
+// on later pages of a scripthash history.
+func TestEsploraClientFilterBlocks(t *testing.T) {
+	t.Parallel()
+
+	fake, url := newFakeEsplora(t, &chaincfg.RegressionNetParams)
+	addr := testAddr(t, 1)
+
+	// Pay to the address in more blocks than fit on a single history
+	// page.
+	numBlocks := esploraChainTxsPerPage + 5
+	blocks := make([]wtxmgr.BlockMeta, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		pay := payTx(t, wire.OutPoint{Index: uint32(i)}, addr, 1000)
+		block := fake.addBlock(pay)
+		blocks = append(blocks, wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   block.BlockHash(),
+				Height: int32(i + 1),
+			},
+			Time: block.Header.Timestamp,
+		})
+	}
+	empty := fake.addBlock()
+
+	client := startEsploraClient(t, url, time.Hour)
+
+	scopedIndex := waddrmgr.ScopedIndex{
+		Scope: waddrmgr.KeyScopeBIP0084,
+		Index: 7,
+	}
+	req := &FilterBlocksRequest{
+		Blocks: blocks,
+		ExternalAddrs: map[waddrmgr.ScopedIndex]btcutil.Address{
+			scopedIndex: addr,
+		},
+		InternalAddrs:    map[waddrmgr.ScopedIndex]btcutil.Address{},
+		WatchedOutPoints: map[wire.OutPoint]btcutil.Address{},
+	}
+	resp, err := client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.EqualValues(t, 0, resp.BatchIndex)
+	require.Equal(t, blocks[0], resp.BlockMeta)
+	require.Len(t, resp.RelevantTxns, 1)
+	require.Contains(t,
+		resp.FoundExternalAddrs[scopedIndex.Scope], scopedIndex.Index)
+	require.Len(t, resp.FoundOutPoints, 1)
+
+	// Continue after the first match.
+	req.Blocks = blocks[1:]
+	resp, err = client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.EqualValues(t, 0, resp.BatchIndex)
+	require.Equal(t, blocks[1], resp.BlockMeta)
+
+	// Blocks without relevant transactions are skipped.
+	emptyHash := empty.BlockHash()
+	req.Blocks = []wtxmgr.BlockMeta{{
+		Block: wtxmgr.Block{Hash: emptyHash, Height: int32(numBlocks + 1)},
+	}}
+	resp, err = client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	// Addresses without history are not found.
+	req.Blocks = blocks
+	req.ExternalAddrs = map[waddrmgr.ScopedIndex]btcutil.Address{
+		scopedIndex: testAddr(t, 2),
+	}
+	resp, err = client.FilterBlocks(req)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
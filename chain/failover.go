@@ -0,0 +1,1162 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+const (
+	// DefaultFailoverHealthCheckInterval is the default interval at which
+	// the health of the backends of a FailoverClient is checked.
+	DefaultFailoverHealthCheckInterval = 30 * time.Second
+
+	// failoverReorgDepth is the number of notified blocks that are kept to
+	// find the fork point between the notified chain and the chain of a
+	// backend that is failed over to.
+	failoverReorgDepth = 144
+)
+
+// ErrNoHealthyBackend is returned by a FailoverClient when none of its
+// backends is healthy.
+var ErrNoHealthyBackend = errors.New("no healthy chain backend")
+
+// FailoverBackend is a chain backend of a FailoverClient.
+type FailoverBackend struct {
+	// Name identifies the backend in logs.
+	Name string
+
+	// Start creates and starts a client of the backend.  It is called
+	// again to replace the client after it failed to start or shut down.
+	Start func() (Interface, error)
+}
+
+// FailoverConfig contains the configuration of a FailoverClient.
+type FailoverConfig struct {
+	// Backends are the chain backends, in order of preference.
+	Backends []FailoverBackend
+
+	// ChainParams are the parameters of the network of the backends.
+	ChainParams *chaincfg.Params
+
+	// HealthCheckInterval is the interval at which the health of the
+	// backends is checked.  DefaultFailoverHealthCheckInterval is used if
+	// zero.
+	HealthCheckInterval time.Duration
+}
+
+// failoverBackend is the state of a backend of a FailoverClient.
+type failoverBackend struct {
+	FailoverBackend
+
+	// instance is the running client of the backend, or nil if it is not
+	// started.  healthy and current are the results of the last health
+	// check of the instance.  All are guarded by the mutex of the client.
+	instance *failoverInstance
+	healthy  bool
+	current  bool
+}
+
+// failoverInstance is a started client of a backend.  done is closed once the
+// client shut down.
+type failoverInstance struct {
+	client Interface
+	done   chan struct{}
+}
+
+// failoverNtfn is a notification of a backend instance.
+type failoverNtfn struct {
+	instance *failoverInstance
+	ntfn     interface{}
+}
+
+// failoverDown signals that a backend instance shut down.
+type failoverDown struct {
+	backend  *failoverBackend
+	instance *failoverInstance
+}
+
+// failoverChecked signals that the health of the backends was checked.
+type failoverChecked struct{}
+
+// FailoverClient is an implementation of the chain.Interface interface
+// composed of several chain backends.  One healthy backend is active at a
+// time; queries are answered and notifications are delivered by it.  The
+// health of all backends is checked periodically, and the client fails over
+// to the most preferred healthy backend once the active backend shuts down,
+// fails to respond or is no longer current while another backend is.
+//
+// When failing over, blocks notified by the previous backend that are not part
+// of the chain of the new backend are notified as disconnected, and the new
+// backend is rescanned from the fork point.  Transactions are broadcast to all
+// healthy backends.
+type FailoverClient struct {
+	cfg FailoverConfig
+
+	backends []*failoverBackend
+
+	notificationQueue *ConcurrentQueue
+	events            *ConcurrentQueue
+
+	// mtx guards the state of the backends, the active backend, and the
+	// subscriptions that are applied to a backend when it becomes active.
+	mtx           sync.Mutex
+	active        *failoverBackend
+	notifyBlocks  bool
+	watchedAddrs  map[string]btcutil.Address
+	outPoints     map[wire.OutPoint]btcutil.Address
+	pendingRescan *chainhash.Hash
+
+	// bestMtx guards the notified chain, indexed by height, down to
+	// failoverReorgDepth blocks below the best notified block.
+	bestMtx   sync.RWMutex
+	bestBlock *wtxmgr.BlockMeta
+	chain     map[int32]wtxmgr.BlockMeta
+
+	// catchingUp is set while a backend that was failed over to is
+	// rescanned from the fork point without a rescan of the wallet being
+	// pending.
+	catchingUp atomic.Bool
+
+	started atomic.Bool
+	stopped atomic.Bool
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// A compile-time check to ensure that FailoverClient satisfies the
+// chain.Interface interface.
+var _ Interface = (*FailoverClient)(nil)
+
+// NewFailoverClient creates a client composed of the backends of the config.
+// No backend is started until the client is started.
+func NewFailoverClient(cfg *FailoverConfig) (*FailoverClient, error) {
+	if len(cfg.Backends) == 0 {
+		return nil, errors.New("no chain backends configured")
+	}
+	if cfg.ChainParams == nil {
+		return nil, errors.New("missing chain params config")
+	}
+
+	c := &FailoverClient{
+		cfg:               *cfg,
+		notificationQueue: NewConcurrentQueue(20),
+		events:            NewConcurrentQueue(20),
+		watchedAddrs:      make(map[string]btcutil.Address),
+		outPoints:         make(map[wire.OutPoint]btcutil.Address),
+		chain:             make(map[int32]wtxmgr.BlockMeta),
+		quit:              make(chan struct{}),
+	}
+	if c.cfg.HealthCheckInterval == 0 {
+		c.cfg.HealthCheckInterval = DefaultFailoverHealthCheckInterval
+	}
+	for _, backend := range cfg.Backends {
+		if backend.Start == nil {
+			return nil, fmt.Errorf("chain backend %v can not be "+
+				"started", backend.Name)
+		}
+		c.backends = append(c.backends, &failoverBackend{
+			FailoverBackend: backend,
+		})
+	}
+
+	return c, nil
+}
+
+// BackEnd returns the name of the driver.
+func (c *FailoverClient) BackEnd() string {
+	return "failover"
+}
+
+// ActiveBackend returns the name of the active backend, or an empty string if
+// no backend is healthy.
+func (c *FailoverClient) ActiveBackend() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.active == nil {
+		return ""
+	}
+	return c.active.Name
+}
+
+// Start starts the backends and activates the most preferred healthy one.  At
+// least one backend must start.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) Start() error {
+	if !c.started.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	c.notificationQueue.Start()
+	c.events.Start()
+
+	c.checkHealth()
+	c.mtx.Lock()
+	var numStarted int
+	for _, b := range c.backends {
+		if b.instance != nil {
+			numStarted++
+		}
+	}
+	c.mtx.Unlock()
+	if numStarted == 0 {
+		c.Stop()
+		return errors.New("unable to start any chain backend")
+	}
+
+	select {
+	case c.notificationQueue.ChanIn() <- ClientConnected{}:
+	case <-c.quit:
+		return errors.New("failover client stopped")
+	}
+
+	c.checkActive()
+
+	// The notified chain starts at the best block of the first active
+	// backend, which the wallet syncs to.
+	if client, err := c.activeClient(); err == nil {
+		bs, err := client.BlockStamp()
+		if err != nil {
+			c.Stop()
+			return err
+		}
+		c.setBest(wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{Hash: bs.Hash, Height: bs.Height},
+			Time:  bs.Timestamp,
+		})
+	}
+
+	c.wg.Add(2)
+	go c.eventHandler()
+	go c.healthHandler()
+
+	return nil
+}
+
+// Stop stops all backends.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) Stop() {
+	if !c.stopped.CompareAndSwap(false, true) {
+		return
+	}
+
+	close(c.quit)
+
+	c.mtx.Lock()
+	for _, b := range c.backends {
+		if b.instance != nil {
+			b.instance.client.Stop()
+		}
+	}
+	c.mtx.Unlock()
+
+	c.notificationQueue.Stop()
+	c.events.Stop()
+}
+
+// WaitForShutdown blocks until the client and all backends have stopped.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// Notifications returns a channel to retrieve notifications from.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) Notifications() <-chan interface{} {
+	return c.notificationQueue.ChanOut()
+}
+
+// GetBestBlock returns the best block of the active backend.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	client, err := c.activeClient()
+	if err != nil {
+		return nil, 0, err
+	}
+	return client.GetBestBlock()
+}
+
+// GetBlock returns the block with the given hash from the active backend, or
+// from another healthy backend if the active one doesn't know it.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock,
+	error) {
+
+	var block *wire.MsgBlock
+	err := c.tryHealthy(func(client Interface) error {
+		var err error
+		block, err = client.GetBlock(hash)
+		return err
+	})
+	return block, err
+}
+
+// GetBlockHash returns the hash of the block at the given height of the active
+// backend's best chain.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	client, err := c.activeClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetBlockHash(height)
+}
+
+// GetBlockHeader returns the header of the block with the given hash from the
+// active backend, or from another healthy backend if the active one doesn't
+// know it.  This allows blocks that were disconnected while failing over to
+// be looked up.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	var header *wire.BlockHeader
+	err := c.tryHealthy(func(client Interface) error {
+		var err error
+		header, err = client.GetBlockHeader(hash)
+		return err
+	})
+	return header, err
+}
+
+// GetBlockHeight returns the height of the block with the given hash from the
+// active backend.
+func (c *FailoverClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	client, err := c.activeClient()
+	if err != nil {
+		return 0, err
+	}
+
+	switch client := client.(type) {
+	case *RPCClient:
+		header, err := client.GetBlockHeaderVerbose(hash)
+		if err != nil {
+			return 0, err
+		}
+		return header.Height, nil
+
+	case interface {
+		GetBlockHeight(*chainhash.Hash) (int32, error)
+	}:
+		return client.GetBlockHeight(hash)
+	}
+
+	return 0, fmt.Errorf("chain backend %v can not look up block "+
+		"heights", client.BackEnd())
+}
+
+// IsCurrent returns whether the active backend is current.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) IsCurrent() bool {
+	client, err := c.activeClient()
+	if err != nil {
+		return false
+	}
+	return client.IsCurrent()
+}
+
+// BlockStamp returns the latest block notified by the client, or the latest
+// block notified by the active backend if no block was notified yet.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	c.bestMtx.RLock()
+	bestBlock := c.bestBlock
+	c.bestMtx.RUnlock()
+	if bestBlock != nil {
+		return &waddrmgr.BlockStamp{
+			Hash:      bestBlock.Hash,
+			Height:    bestBlock.Height,
+			Timestamp: bestBlock.Time,
+		}, nil
+	}
+
+	client, err := c.activeClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.BlockStamp()
+}
+
+// SendRawTransaction broadcasts the transaction to all healthy backends.  The
+// result of the active backend is returned, unless it failed and another
+// backend accepted the transaction.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	clients := c.healthyClients()
+	if len(clients) == 0 {
+		return nil, ErrNoHealthyBackend
+	}
+
+	type result struct {
+		hash *chainhash.Hash
+		err  error
+	}
+	results := make([]result, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client Interface) {
+			defer wg.Done()
+
+			hash, err := client.SendRawTransaction(tx, allowHighFees)
+			results[i] = result{hash: hash, err: err}
+		}(i, client)
+	}
+	wg.Wait()
+
+	// The healthy clients start with the active one, if it is healthy.
+	for i, res := range results {
+		if res.err != nil {
+			log.Debugf("Chain backend %v rejected tx %v: %v",
+				clients[i].BackEnd(), tx.TxHash(), res.err)
+			continue
+		}
+		return res.hash, nil
+	}
+	return nil, results[0].err
+}
+
+// TestMempoolAccept tests the transactions against the mempool of the active
+// backend.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) TestMempoolAccept(txns []*wire.MsgTx,
+	maxFeeRate float64) ([]*btcjson.TestMempoolAcceptResult, error) {
+
+	client, err := c.activeClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.TestMempoolAccept(txns, maxFeeRate)
+}
+
+// MapRPCErr maps an error using the active backend.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) MapRPCErr(err error) error {
+	client, activeErr := c.activeClient()
+	if activeErr != nil {
+		return fmt.Errorf("%w: %v", ErrUndefined, err)
+	}
+	return client.MapRPCErr(err)
+}
+
+// NotifyReceived watches the addresses on the active backend.  They are
+// watched on every backend that is failed over to.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) NotifyReceived(addrs []btcutil.Address) error {
+	c.mtx.Lock()
+	for _, addr := range addrs {
+		c.watchedAddrs[addr.EncodeAddress()] = addr
+	}
+	active := c.active
+	c.mtx.Unlock()
+
+	if active == nil {
+		return ErrNoHealthyBackend
+	}
+	return active.instance.client.NotifyReceived(addrs)
+}
+
+// NotifyBlocks enables block notifications on the active backend, and on
+// every backend that is failed over to.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) NotifyBlocks() error {
+	c.mtx.Lock()
+	c.notifyBlocks = true
+	active := c.active
+	c.mtx.Unlock()
+
+	if active == nil {
+		return ErrNoHealthyBackend
+	}
+	return active.instance.client.NotifyBlocks()
+}
+
+// Rescan rescans the active backend.  If the client fails over before the
+// rescan finished, the backend failed over to is rescanned instead.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) Rescan(startHash *chainhash.Hash,
+	addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	c.mtx.Lock()
+	for _, addr := range addrs {
+		c.watchedAddrs[addr.EncodeAddress()] = addr
+	}
+	for op, addr := range outPoints {
+		c.outPoints[op] = addr
+	}
+	start := *startHash
+	c.pendingRescan = &start
+	active := c.active
+	c.mtx.Unlock()
+
+	// The rescan of the wallet supersedes catching up after failing over.
+	c.catchingUp.Store(false)
+
+	if active == nil {
+		return ErrNoHealthyBackend
+	}
+	return active.instance.client.Rescan(startHash, addrs, outPoints)
+}
+
+// FilterBlocks filters the blocks using the active backend.
+//
+// NOTE: This is part of the chain.Interface interface.
+func (c *FailoverClient) FilterBlocks(
+	req *FilterBlocksRequest) (*FilterBlocksResponse, error) {
+
+	client, err := c.activeClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.FilterBlocks(req)
+}
+
+// SetBirthday sets the birthday of the backends that use it to skip blocks
+// that can't contain relevant transactions.
+func (c *FailoverClient) SetBirthday(t time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, b := range c.backends {
+		if b.instance == nil {
+			continue
+		}
+		switch client := b.instance.client.(type) {
+		case *NeutrinoClient:
+			client.SetStartTime(t)
+		case *BitcoindClient:
+			client.SetBirthday(t)
+		}
+	}
+}
+
+// eventHandler handles the notifications of the backends and the results of
+// health checks.
+//
+// NOTE: This must be run as a goroutine.
+func (c *FailoverClient) eventHandler() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case e := <-c.events.ChanOut():
+			switch e := e.(type) {
+			case *failoverNtfn:
+				c.mtx.Lock()
+				active := c.active != nil &&
+					c.active.instance == e.instance
+				c.mtx.Unlock()
+				if active {
+					c.handleNtfn(e.instance.client, e.ntfn)
+				}
+
+			case *failoverDown:
+				log.Warnf("Chain backend %v shut down",
+					e.backend.Name)
+
+				c.mtx.Lock()
+				if e.backend.instance == e.instance {
+					e.backend.instance = nil
+					e.backend.healthy = false
+					e.backend.current = false
+				}
+				c.mtx.Unlock()
+				c.checkActive()
+
+			case failoverChecked:
+				c.checkActive()
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// handleNtfn forwards a notification of the active backend, keeping track of
+// the notified chain and of the outputs paying to watched addresses.
+func (c *FailoverClient) handleNtfn(client Interface, n interface{}) {
+	switch n := n.(type) {
+	// Every backend notifies that it connected, but the wallet is only
+	// notified once.
+	case ClientConnected:
+		return
+
+	case BlockConnected:
+		// Blocks are notified once a backend that was failed over to
+		// finished catching up.
+		if c.catchingUp.Load() {
+			return
+		}
+		c.connectBlock(wtxmgr.BlockMeta(n))
+
+	case BlockDisconnected:
+		if c.catchingUp.Load() {
+			return
+		}
+		c.disconnectBlock(wtxmgr.BlockMeta(n))
+
+	case FilteredBlockConnected:
+		if !c.catchingUp.Load() {
+			c.connectBlock(*n.Block)
+		}
+		for _, rec := range n.RelevantTxs {
+			c.watchOutputs(&rec.MsgTx)
+		}
+
+	case RelevantTx:
+		c.watchOutputs(&n.TxRecord.MsgTx)
+
+	case *RescanProgress:
+		if c.catchingUp.Load() {
+			return
+		}
+
+	case *RescanFinished:
+		if c.catchingUp.CompareAndSwap(true, false) {
+			if err := c.syncBlocks(client); err != nil {
+				log.Errorf("Unable to sync blocks of chain "+
+					"backend: %v", err)
+			}
+			return
+		}
+
+		c.mtx.Lock()
+		c.pendingRescan = nil
+		c.mtx.Unlock()
+
+		c.setBest(wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{Hash: *n.Hash, Height: n.Height},
+			Time:  n.Time,
+		})
+	}
+
+	c.notify(n)
+}
+
+// checkActive fails over to the most preferred healthy backend if there is no
+// active backend, the active backend is unhealthy, or it is not current while
+// another backend is.
+func (c *FailoverClient) checkActive() {
+	c.mtx.Lock()
+	active := c.active
+	if active != nil && active.healthy && active.current {
+		c.mtx.Unlock()
+		return
+	}
+
+	var next *failoverBackend
+	for _, b := range c.backends {
+		if !b.healthy {
+			continue
+		}
+		if b.current {
+			next = b
+			break
+		}
+		if next == nil {
+			next = b
+		}
+	}
+	if next == nil || next == active ||
+		(active != nil && active.healthy && !next.current) {
+
+		if next == nil && active != nil {
+			log.Warnf("No healthy chain backend to fail over to "+
+				"from %v", active.Name)
+			c.active = nil
+		}
+		c.mtx.Unlock()
+		return
+	}
+
+	c.active = next
+	instance := next.instance
+	notifyBlocks := c.notifyBlocks
+	addrs := make([]btcutil.Address, 0, len(c.watchedAddrs))
+	for _, addr := range c.watchedAddrs {
+		addrs = append(addrs, addr)
+	}
+	outPoints := make(map[wire.OutPoint]btcutil.Address, len(c.outPoints))
+	for op, addr := range c.outPoints {
+		outPoints[op] = addr
+	}
+	pendingRescan := c.pendingRescan
+	c.mtx.Unlock()
+
+	if active == nil {
+		log.Infof("Using chain backend %v", next.Name)
+	} else {
+		log.Infof("Failing over from chain backend %v to %v",
+			active.Name, next.Name)
+	}
+
+	c.activate(instance.client, notifyBlocks, addrs, outPoints,
+		pendingRescan)
+}
+
+// activate applies the subscriptions to the backend that was failed over to,
+// notifies the blocks that are not part of its chain as disconnected, and
+// rescans it from the fork point, or from the start of the pending rescan of
+// the wallet.
+func (c *FailoverClient) activate(client Interface, notifyBlocks bool,
+	addrs []btcutil.Address, outPoints map[wire.OutPoint]btcutil.Address,
+	pendingRescan *chainhash.Hash) {
+
+	c.catchingUp.Store(false)
+
+	fork, err := c.disconnectStale(client)
+	if err != nil {
+		log.Errorf("Unable to find fork point of chain backend: %v",
+			err)
+	}
+
+	if notifyBlocks {
+		if err := client.NotifyBlocks(); err != nil {
+			log.Errorf("Unable to enable block notifications: %v",
+				err)
+		}
+	}
+	if len(addrs) > 0 {
+		if err := client.NotifyReceived(addrs); err != nil {
+			log.Errorf("Unable to watch addresses: %v", err)
+		}
+	}
+
+	// A pending rescan is restarted from its start, unless that block was
+	// disconnected.  Otherwise the blocks after the fork point are
+	// rescanned to find the transactions that were not notified.
+	start := fork
+	if pendingRescan != nil {
+		c.bestMtx.RLock()
+		_, notified := c.blockHeight(*pendingRescan)
+		c.bestMtx.RUnlock()
+		if fork == nil || !notified {
+			start = pendingRescan
+		}
+	}
+	if start == nil {
+		return
+	}
+
+	c.catchingUp.Store(pendingRescan == nil)
+	if err := client.Rescan(start, addrs, outPoints); err != nil {
+		log.Errorf("Unable to rescan chain backend from block %v: %v",
+			start, err)
+		c.catchingUp.Store(false)
+	}
+}
+
+// disconnectStale notifies the notified blocks that are not part of the chain
+// of the backend as disconnected, and returns the hash of the most recent
+// notified block that is.  Nil is returned if no blocks were notified.
+func (c *FailoverClient) disconnectStale(
+	client Interface) (*chainhash.Hash, error) {
+
+	for depth := 0; ; depth++ {
+		c.bestMtx.RLock()
+		bestBlock := c.bestBlock
+		c.bestMtx.RUnlock()
+		if bestBlock == nil {
+			return nil, nil
+		}
+
+		hash, err := client.GetBlockHash(int64(bestBlock.Height))
+		if err == nil && *hash == bestBlock.Hash {
+			return hash, nil
+		}
+		if depth == failoverReorgDepth || bestBlock.Height == 0 {
+			return nil, fmt.Errorf("fork point is deeper than "+
+				"%d blocks", depth)
+		}
+
+		// The window of notified blocks starts at the best block
+		// when the client starts, so earlier blocks are looked up.
+		c.bestMtx.RLock()
+		_, known := c.chain[bestBlock.Height-1]
+		c.bestMtx.RUnlock()
+		if !known {
+			prev, err := c.prevBlock(bestBlock)
+			if err != nil {
+				return nil, err
+			}
+			c.bestMtx.Lock()
+			c.chain[prev.Height] = *prev
+			c.bestMtx.Unlock()
+		}
+
+		c.disconnectBlock(*bestBlock)
+		c.notify(BlockDisconnected(*bestBlock))
+	}
+}
+
+// prevBlock looks up the block preceding the given block using any healthy
+// backend.
+func (c *FailoverClient) prevBlock(
+	block *wtxmgr.BlockMeta) (*wtxmgr.BlockMeta, error) {
+
+	header, err := c.GetBlockHeader(&block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	prevHeader, err := c.GetBlockHeader(&header.PrevBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   header.PrevBlock,
+			Height: block.Height - 1,
+		},
+		Time: prevHeader.Timestamp,
+	}, nil
+}
+
+// syncBlocks notifies the blocks of the backend after the latest notified
+// block of its chain as connected, after notifying the notified blocks that
+// are not part of its chain as disconnected.
+func (c *FailoverClient) syncBlocks(client Interface) error {
+	if _, err := c.disconnectStale(client); err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	notifyBlocks := c.notifyBlocks
+	c.mtx.Unlock()
+	if !notifyBlocks {
+		return nil
+	}
+
+	_, bestHeight, err := client.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	c.bestMtx.RLock()
+	var height int32
+	if c.bestBlock != nil {
+		height = c.bestBlock.Height + 1
+	}
+	c.bestMtx.RUnlock()
+
+	for ; height <= bestHeight; height++ {
+		hash, err := client.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		header, err := client.GetBlockHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		block := wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{Hash: *hash, Height: height},
+			Time:  header.Timestamp,
+		}
+		c.connectBlock(block)
+		c.notify(BlockConnected(block))
+	}
+
+	return nil
+}
+
+// connectBlock adds the block to the notified chain.
+func (c *FailoverClient) connectBlock(block wtxmgr.BlockMeta) {
+	c.bestMtx.Lock()
+	defer c.bestMtx.Unlock()
+
+	c.chain[block.Height] = block
+	delete(c.chain, block.Height-failoverReorgDepth)
+	c.bestBlock = &block
+}
+
+// disconnectBlock removes the block and all blocks after it from the notified
+// chain.
+func (c *FailoverClient) disconnectBlock(block wtxmgr.BlockMeta) {
+	c.bestMtx.Lock()
+	defer c.bestMtx.Unlock()
+
+	if c.bestBlock == nil {
+		return
+	}
+	for height := c.bestBlock.Height; height >= block.Height; height-- {
+		delete(c.chain, height)
+	}
+	if prev, ok := c.chain[block.Height-1]; ok {
+		c.bestBlock = &prev
+	} else {
+		c.bestBlock = nil
+	}
+}
+
+// setBest resets the notified chain to the block, unless it is already part
+// of it.
+func (c *FailoverClient) setBest(block wtxmgr.BlockMeta) {
+	c.bestMtx.Lock()
+	defer c.bestMtx.Unlock()
+
+	if known, ok := c.chain[block.Height]; ok && known.Hash == block.Hash {
+		return
+	}
+	c.chain = map[int32]wtxmgr.BlockMeta{block.Height: block}
+	c.bestBlock = &block
+}
+
+// blockHeight returns the height of a notified block.
+//
+// NOTE: The caller must hold the best block mutex for reads.
+func (c *FailoverClient) blockHeight(hash chainhash.Hash) (int32, bool) {
+	for height, block := range c.chain {
+		if block.Hash == hash {
+			return height, true
+		}
+	}
+	return 0, false
+}
+
+// watchOutputs adds the outputs of the transaction paying to watched
+// addresses to the watched outpoints, so their spends are found by rescans of
+// backends that are failed over to.  Spent outpoints are removed.
+func (c *FailoverClient) watchOutputs(tx *wire.MsgTx) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, in := range tx.TxIn {
+		delete(c.outPoints, in.PreviousOutPoint)
+	}
+
+	txHash := tx.TxHash()
+	for i, out := range tx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			out.PkScript, c.cfg.ChainParams,
+		)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			watched, ok := c.watchedAddrs[addr.EncodeAddress()]
+			if !ok {
+				continue
+			}
+			op := wire.OutPoint{Hash: txHash, Index: uint32(i)}
+			c.outPoints[op] = watched
+		}
+	}
+}
+
+// healthHandler periodically checks the health of the backends, restarting
+// those that are not running.
+//
+// NOTE: This must be run as a goroutine.
+func (c *FailoverClient) healthHandler() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkHealth()
+			select {
+			case c.events.ChanIn() <- failoverChecked{}:
+			case <-c.quit:
+				return
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// checkHealth starts the backends that are not running, and checks whether the
+// running backends respond and are current.
+func (c *FailoverClient) checkHealth() {
+	for _, b := range c.backends {
+		c.mtx.Lock()
+		instance := b.instance
+		c.mtx.Unlock()
+
+		if instance == nil {
+			client, err := b.Start()
+			if err != nil {
+				log.Debugf("Unable to start chain backend %v: %v",
+					b.Name, err)
+				continue
+			}
+			instance = c.addInstance(b, client)
+			if instance == nil {
+				return
+			}
+		}
+
+		_, _, err := instance.client.GetBestBlock()
+		healthy := err == nil
+		current := healthy && instance.client.IsCurrent()
+		if !healthy {
+			log.Debugf("Chain backend %v is unhealthy: %v", b.Name,
+				err)
+		}
+
+		c.mtx.Lock()
+		if b.instance == instance {
+			b.healthy = healthy
+			b.current = current
+		}
+		c.mtx.Unlock()
+	}
+}
+
+// addInstance registers a started client of the backend, and forwards its
+// notifications and shutdown to the event goroutine.  Nil is returned if the
+// failover client is stopping.
+func (c *FailoverClient) addInstance(b *failoverBackend,
+	client Interface) *failoverInstance {
+
+	instance := &failoverInstance{
+		client: client,
+		done:   make(chan struct{}),
+	}
+
+	c.mtx.Lock()
+	if c.stopped.Load() {
+		c.mtx.Unlock()
+		client.Stop()
+		return nil
+	}
+	b.instance = instance
+	c.mtx.Unlock()
+
+	c.wg.Add(2)
+	go func() {
+		defer c.wg.Done()
+
+		for {
+			select {
+			case n := <-client.Notifications():
+				select {
+				case c.events.ChanIn() <- &failoverNtfn{
+					instance: instance,
+					ntfn:     n,
+				}:
+				case <-c.quit:
+					return
+				}
+
+			case <-instance.done:
+				return
+
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+	go func() {
+		defer c.wg.Done()
+
+		client.WaitForShutdown()
+		close(instance.done)
+
+		select {
+		case c.events.ChanIn() <- &failoverDown{
+			backend:  b,
+			instance: instance,
+		}:
+		case <-c.quit:
+		}
+	}()
+
+	return instance
+}
+
+// activeClient returns the client of the active backend.
+func (c *FailoverClient) activeClient() (Interface, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.active == nil {
+		return nil, ErrNoHealthyBackend
+	}
+	return c.active.instance.client, nil
+}
+
+// healthyClients returns the clients of all healthy backends, starting with
+// the active one.
+func (c *FailoverClient) healthyClients() []Interface {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var clients []Interface
+	if c.active != nil {
+		clients = append(clients, c.active.instance.client)
+	}
+	for _, b := range c.backends {
+		if b != c.active && b.healthy {
+			clients = append(clients, b.instance.client)
+		}
+	}
+	return clients
+}
+
+// tryHealthy calls f with the client of the active backend, and then with the
+// clients of the other healthy backends until it succeeds.  The error of the
+// first call is returned if all calls fail.
+func (c *FailoverClient) tryHealthy(f func(Interface) error) error {
+	clients := c.healthyClients()
+	if len(clients) == 0 {
+		return ErrNoHealthyBackend
+	}
+
+	var firstErr error
+	for _, client := range clients {
+		err := f(client)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// notify queues a notification unless the client is stopping.
+func (c *FailoverClient) notify(n interface{}) {
+	select {
+	case c.notificationQueue.ChanIn() <- n:
+	case <-c.quit:
+	}
+}
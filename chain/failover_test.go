@@ -0,0 +1,202 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// esploraBackend returns a failover backend starting an Esplora client of the
+// API at the URL.
+func esploraBackend(name, url string) FailoverBackend {
+	return FailoverBackend{
+		Name: name,
+		Start: func() (Interface, error) {
+			client, err := NewEsploraClient(&EsploraConfig{
+				URL:          url,
+				ChainParams:  &chaincfg.RegressionNetParams,
+				PollInterval: 10 * time.Millisecond,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := client.Start(); err != nil {
+				client.Stop()
+				return nil, err
+			}
+			return client, nil
+		},
+	}
+}
+
+func startFailoverClient(t *testing.T,
+	backends ...FailoverBackend) *FailoverClient {
+
+	t.Helper()
+
+	client, err := NewFailoverClient(&FailoverConfig{
+		Backends:            backends,
+		ChainParams:         &chaincfg.RegressionNetParams,
+		HealthCheckInterval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Start())
+	t.Cleanup(func() {
+		client.Stop()
+		client.WaitForShutdown()
+	})
+
+	require.IsType(t, ClientConnected{}, nextNotification(t, client))
+	return client
+}
+
+// TestFailoverClientPrefersCurrent tests that the most preferred current
+// backend is activated, and that transactions are broadcast to all healthy
+// backends.
+func TestFailoverClientPrefersCurrent(t *testing.T) {
+	t.Parallel()
+
+	// The client fails to start if no backend starts.
+	failing := FailoverBackend{
+		Name: "failing",
+		Start: func() (Interface, error) {
+			return nil, errors.New("unreachable")
+		},
+	}
+	client, err := NewFailoverClient(&FailoverConfig{
+		Backends:    []FailoverBackend{failing},
+		ChainParams: &chaincfg.RegressionNetParams,
+	})
+	require.NoError(t, err)
+	require.Error(t, client.Start())
+
+	stale, staleURL := newFakeEsplora(t, &chaincfg.RegressionNetParams)
+	staleBlock := stale.addBlock()
+
+	current, currentURL := newFakeEsplora(
+		t, &chaincfg.RegressionNetParams,
+	)
+	current.current = true
+	block1 := current.addBlock()
+
+	client = startFailoverClient(
+		t, failing, esploraBackend("stale", staleURL),
+		esploraBackend("current", currentURL),
+	)
+	require.Equal(t, "failover", client.BackEnd())
+	require.Contains(t, BackEnds(), client.BackEnd())
+	require.Equal(t, "current", client.ActiveBackend())
+	require.True(t, client.IsCurrent())
+
+	hash, height, err := client.GetBestBlock()
+	require.NoError(t, err)
+	require.Equal(t, block1.BlockHash(), *hash)
+	require.EqualValues(t, 1, height)
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, block1.BlockHash(), bs.Hash)
+
+	// Blocks only known to another backend are still found.
+	staleHash := staleBlock.BlockHash()
+	header, err := client.GetBlockHeader(&staleHash)
+	require.NoError(t, err)
+	require.Equal(t, staleBlock.Header, *header)
+
+	tx := payTx(t, wire.OutPoint{Index: 1}, testAddr(t, 1), 1000)
+	txid, err := client.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), *txid)
+	for _, fake := range []*fakeEsplora{stale, current} {
+		fake.mtx.Lock()
+		require.Len(t, fake.published, 1)
+		fake.mtx.Unlock()
+	}
+
+	// The transaction is accepted as long as any backend accepts it.
+	current.mtx.Lock()
+	current.rejectTx = "transaction already in block chain"
+	current.mtx.Unlock()
+	_, err = client.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+}
+
+// TestFailoverClientReorg tests that when failing over to a backend following
+// another chain, the blocks only notified by the previous backend are
+// disconnected, and the relevant transactions and blocks of the new backend
+// are notified.
+func TestFailoverClientReorg(t *testing.T) {
+	t.Parallel()
+
+	addr := testAddr(t, 1)
+	pay := payTx(t, wire.OutPoint{Index: 1}, addr, 1000)
+
+	// Both backends share the first block, after which the second
+	// backend follows a longer chain confirming the payment later.
+	first, firstURL := newFakeEsplora(t, &chaincfg.RegressionNetParams)
+	second, secondURL := newFakeEsplora(
+		t, &chaincfg.RegressionNetParams,
+	)
+	block1 := first.addBlock()
+	require.Equal(t, block1.BlockHash(), second.addBlock().BlockHash())
+	block2B := second.addBlock()
+	block3B := second.addBlock(pay)
+
+	// Both backends are stale, so the preferred one is activated.
+	client := startFailoverClient(
+		t, esploraBackend("first", firstURL),
+		esploraBackend("second", secondURL),
+	)
+	require.Equal(t, "first", client.ActiveBackend())
+	require.NoError(t, client.NotifyBlocks())
+	require.NoError(t, client.NotifyReceived([]btcutil.Address{addr}))
+
+	requireConnected := func(n interface{}, block *wire.MsgBlock,
+		height int32) {
+
+		t.Helper()
+
+		connected, ok := n.(BlockConnected)
+		require.True(t, ok, "expected BlockConnected, got %T", n)
+		require.Equal(t, block.BlockHash(), connected.Hash)
+		require.Equal(t, height, connected.Height)
+	}
+
+	block2A := first.addBlock(pay)
+	requireRelevantTx(t, nextNotification(t, client), pay, block2A)
+	requireConnected(nextNotification(t, client), block2A, 2)
+
+	// Once the first backend goes down, its block is disconnected and
+	// the chain of the second backend is notified.
+	first.srv.Close()
+
+	disconnected, ok := nextNotification(t, client).(BlockDisconnected)
+	require.True(t, ok)
+	require.Equal(t, block2A.BlockHash(), disconnected.Hash)
+	requireRelevantTx(t, nextNotification(t, client), pay, block3B)
+	requireConnected(nextNotification(t, client), block2B, 2)
+	requireConnected(nextNotification(t, client), block3B, 3)
+	require.Equal(t, "second", client.ActiveBackend())
+
+	bs, err := client.BlockStamp()
+	require.NoError(t, err)
+	require.Equal(t, block3B.BlockHash(), bs.Hash)
+	require.EqualValues(t, 3, bs.Height)
+
+	// New blocks of the second backend are notified.
+	block4B := second.addBlock()
+	requireConnected(nextNotification(t, client), block4B, 4)
+
+	// Transactions are only broadcast to the healthy backend.
+	tx := payTx(t, wire.OutPoint{Index: 2}, testAddr(t, 2), 1000)
+	_, err = client.SendRawTransaction(tx, false)
+	require.NoError(t, err)
+	second.mtx.Lock()
+	require.Len(t, second.published, 1)
+	second.mtx.Unlock()
+}
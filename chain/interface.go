@@ -25,6 +25,9 @@ func BackEnds() []string {
 		"btcd",
 		"neutrino",
 		"bitcoind-rpc-polling",
+		"esplora",
+		"electrum",
+		"failover",
 	}
 }
 
@@ -111,6 +114,21 @@ type (
 		Block    *wtxmgr.BlockMeta // nil if unmined
 	}
 
+	// TxEvicted is a notification that a relevant unmined transaction was
+	// removed from the backend's mempool without being mined or replaced,
+	// e.g. because it expired or was evicted when the mempool was full.
+	TxEvicted struct {
+		Hash chainhash.Hash
+	}
+
+	// TxReplaced is a notification that a relevant unmined transaction was
+	// removed from the backend's mempool because a conflicting transaction
+	// spending one of its inputs replaced it.
+	TxReplaced struct {
+		Old chainhash.Hash
+		New chainhash.Hash
+	}
+
 	// RescanProgress is a notification describing the current status
 	// of an in-progress rescan.
 	RescanProgress struct {
@@ -222,6 +222,24 @@ func (m *mempool) Add(tx *wire.MsgTx) {
 	m.add(tx)
 }
 
+// Remove deletes the transaction of the given hash from our mempool. It
+// returns true if the transaction was found there.
+func (m *mempool) Remove(hash chainhash.Hash) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	if !m.containsTx(hash) {
+		return false
+	}
+
+	delete(m.txs, hash)
+
+	// Remove the inputs stored of this tx.
+	m.removeInputs(hash)
+
+	return true
+}
+
 // ContainsTx returns true if the given transaction hash is already in our
 // mempool.
 //
@@ -317,20 +335,23 @@ func (m *mempool) mark(hash chainhash.Hash) {
 	m.txs[hash] = true
 }
 
-// DeleteUnmarked removes all the unmarked transactions from our local mempool.
+// DeleteUnmarked removes all the unmarked transactions from our local mempool
+// and returns their hashes.
 //
 // NOTE: must be used inside a lock.
-func (m *mempool) DeleteUnmarked() {
+func (m *mempool) DeleteUnmarked() []chainhash.Hash {
 	m.Lock()
 	defer m.Unlock()
 
-	m.deleteUnmarked()
+	return m.deleteUnmarked()
 }
 
-// deleteUnmarked removes all the unmarked transactions from our local mempool.
+// deleteUnmarked removes all the unmarked transactions from our local mempool
+// and returns their hashes.
 //
 // NOTE: must be used inside a lock.
-func (m *mempool) deleteUnmarked() {
+func (m *mempool) deleteUnmarked() []chainhash.Hash {
+	var removed []chainhash.Hash
 	for hash, marked := range m.txs {
 		if marked {
 			continue
@@ -340,7 +361,11 @@ func (m *mempool) deleteUnmarked() {
 
 		// Remove the inputs stored of this tx.
 		m.removeInputs(hash)
+
+		removed = append(removed, hash)
 	}
+
+	return removed
 }
 
 // removeInputs takes a txid and removes the inputs of the tx from the
@@ -431,6 +456,17 @@ func (m *mempool) UpdateMempoolTxes() []*wire.MsgTx {
 		return nil
 	}
 
+	txesToNotify, _ := m.reconcile(txids)
+
+	return txesToNotify
+}
+
+// reconcile updates the internal mempool to contain the given transactions of
+// the current mempool. It returns a slice of transactions that's new to its
+// internal mempool, and the hashes of the ones removed from it.
+func (m *mempool) reconcile(txids []*chainhash.Hash) ([]*wire.MsgTx,
+	[]chainhash.Hash) {
+
 	// Set all mempool txs to false.
 	m.UnmarkAll()
 
@@ -447,7 +483,7 @@ func (m *mempool) UpdateMempoolTxes() []*wire.MsgTx {
 		if m.isShuttingDown() {
 			log.Info("UpdateMempoolTxes exited due to shutdown")
 
-			return nil
+			return nil, nil
 		}
 
 		// If the transaction is already in our local mempool, then we
@@ -465,7 +501,7 @@ func (m *mempool) UpdateMempoolTxes() []*wire.MsgTx {
 	// Now, we clear our internal mempool of any unmarked transactions.
 	// These are all the transactions that we still have in the mempool but
 	// that were not returned in the latest GetRawMempool query.
-	m.DeleteUnmarked()
+	removed := m.DeleteUnmarked()
 
 	// Fetch the raw transactions in batch.
 	txesToNotify, err := m.batchGetRawTxes(newTxids, true)
@@ -474,7 +510,7 @@ func (m *mempool) UpdateMempoolTxes() []*wire.MsgTx {
 
 	}
 
-	return txesToNotify
+	return txesToNotify, removed
 }
 
 // getRawMempool returns all the raw transactions found in mempool.
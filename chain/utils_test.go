@@ -1,18 +1,23 @@
 package chain
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
 	"net"
 	"runtime"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
 )
 
 // setupConnPair initiates a tcp connection between two peers.
@@ -252,3 +257,219 @@ func produceInvalidBlock(block *wire.MsgBlock) *wire.MsgBlock {
 	blockCopy.AddTransaction(lastTx)
 	return blockCopy
 }
+
+// fakeChain is an in-memory block chain and mempool served by the fake chain
+// backend servers of the tests.
+type fakeChain struct {
+	mtx sync.Mutex
+
+	// chain is the best chain, indexed by height.
+	chain []*wire.MsgBlock
+
+	// blocks contains all blocks ever added, including stale ones.
+	blocks  map[chainhash.Hash]*wire.MsgBlock
+	heights map[chainhash.Hash]int32
+
+	mempool   []*wire.MsgTx
+	published []*wire.MsgTx
+	rejectTx  string
+
+	// current timestamps new blocks with the current time, so that the
+	// backends serving the chain are current.
+	current bool
+
+	// onChange is called without holding mtx after blocks or mempool
+	// transactions were added.
+	onChange func()
+}
+
+func newFakeChain(params *chaincfg.Params) *fakeChain {
+	f := &fakeChain{
+		blocks:  make(map[chainhash.Hash]*wire.MsgBlock),
+		heights: make(map[chainhash.Hash]int32),
+	}
+	f.chain = append(f.chain, params.GenesisBlock)
+	f.blocks[*params.GenesisHash] = params.GenesisBlock
+	f.heights[*params.GenesisHash] = 0
+
+	return f
+}
+
+// addBlock mines a block containing the transactions on top of the best
+// chain.
+func (f *fakeChain) addBlock(txs ...*wire.MsgTx) *wire.MsgBlock {
+	f.mtx.Lock()
+	block := f.addBlockLocked(txs...)
+	f.mtx.Unlock()
+
+	f.changed()
+	return block
+}
+
+func (f *fakeChain) addBlockLocked(txs ...*wire.MsgTx) *wire.MsgBlock {
+	tip := f.chain[len(f.chain)-1]
+	height := int32(len(f.chain))
+
+	// Give every block a unique coinbase so blocks at the same height of
+	// competing chains differ.
+	coinbase := wire.NewMsgTx(2)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  []byte{byte(height), byte(len(f.blocks))},
+	})
+	coinbase.AddTxOut(wire.NewTxOut(50e8, []byte{txscript.OP_TRUE}))
+
+	timestamp := tip.Header.Timestamp.Add(10 * time.Minute)
+	if f.current {
+		timestamp = time.Unix(time.Now().Unix(), 0)
+	}
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   1,
+			PrevBlock: tip.BlockHash(),
+			Timestamp: timestamp,
+			Bits:      tip.Header.Bits,
+		},
+		Transactions: append([]*wire.MsgTx{coinbase}, txs...),
+	}
+	block.Header.MerkleRoot = blockchain.CalcMerkleRoot(
+		btcutil.NewBlock(block).Transactions(), false,
+	)
+	if !solveBlock(&block.Header) {
+		panic("could not solve block")
+	}
+	hash := block.BlockHash()
+	f.chain = append(f.chain, block)
+	f.blocks[hash] = block
+	f.heights[hash] = height
+
+	mined := make(map[chainhash.Hash]struct{})
+	for _, tx := range txs {
+		mined[tx.TxHash()] = struct{}{}
+	}
+	mempool := f.mempool[:0]
+	for _, tx := range f.mempool {
+		if _, ok := mined[tx.TxHash()]; !ok {
+			mempool = append(mempool, tx)
+		}
+	}
+	f.mempool = mempool
+
+	return block
+}
+
+// reorg replaces the last depth blocks of the best chain with new blocks
+// containing the given transactions.
+func (f *fakeChain) reorg(depth int, blocks ...[]*wire.MsgTx) {
+	f.mtx.Lock()
+	f.chain = f.chain[:len(f.chain)-depth]
+	for _, txs := range blocks {
+		f.addBlockLocked(txs...)
+	}
+	f.mtx.Unlock()
+
+	f.changed()
+}
+
+func (f *fakeChain) addMempoolTx(tx *wire.MsgTx) {
+	f.mtx.Lock()
+	f.mempool = append(f.mempool, tx)
+	f.mtx.Unlock()
+
+	f.changed()
+}
+
+func (f *fakeChain) changed() {
+	if f.onChange != nil {
+		f.onChange()
+	}
+}
+
+func (f *fakeChain) tx(txid string) (*wire.MsgTx, *wire.MsgBlock) {
+	for _, block := range f.chain {
+		for _, tx := range block.Transactions {
+			if tx.TxHash().String() == txid {
+				return tx, block
+			}
+		}
+	}
+	for _, tx := range f.mempool {
+		if tx.TxHash().String() == txid {
+			return tx, nil
+		}
+	}
+	return nil, nil
+}
+
+// pkScripts returns the output scripts the transaction pays to and spends
+// from.
+func (f *fakeChain) pkScripts(tx *wire.MsgTx) [][]byte {
+	pkScripts := make([][]byte, 0, len(tx.TxOut)+len(tx.TxIn))
+	for _, out := range tx.TxOut {
+		pkScripts = append(pkScripts, out.PkScript)
+	}
+	for _, in := range tx.TxIn {
+		prev, _ := f.tx(in.PreviousOutPoint.Hash.String())
+		if prev == nil || int(in.PreviousOutPoint.Index) >= len(prev.TxOut) {
+			continue
+		}
+		pkScripts = append(
+			pkScripts, prev.TxOut[in.PreviousOutPoint.Index].PkScript,
+		)
+	}
+	return pkScripts
+}
+
+// testAddr returns a P2WPKH address on the regression test network.
+func testAddr(t *testing.T, b byte) btcutil.Address {
+	t.Helper()
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		bytes.Repeat([]byte{b}, 20), &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	return addr
+}
+
+// payTx returns a transaction spending the outpoint and paying to the address.
+func payTx(t *testing.T, prev wire.OutPoint, addr btcutil.Address,
+	amount int64) *wire.MsgTx {
+
+	t.Helper()
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&prev, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(amount, pkScript))
+	return tx
+}
+
+func nextNotification(t *testing.T, client Interface) interface{} {
+	t.Helper()
+
+	select {
+	case n := <-client.Notifications():
+		return n
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+		return nil
+	}
+}
+
+func requireRelevantTx(t *testing.T, n interface{}, tx *wire.MsgTx,
+	block *wire.MsgBlock) {
+
+	t.Helper()
+
+	relevant, ok := n.(RelevantTx)
+	require.True(t, ok, "expected RelevantTx, got %T", n)
+	require.Equal(t, tx.TxHash(), relevant.TxRecord.Hash)
+	if block == nil {
+		require.Nil(t, relevant.Block)
+		return
+	}
+	require.NotNil(t, relevant.Block)
+	require.Equal(t, block.BlockHash(), relevant.Block.Hash)
+}
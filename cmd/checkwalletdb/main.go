@@ -0,0 +1,254 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/encdb"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/jessevdk/go-flags"
+	"go.etcd.io/bbolt"
+)
+
+const defaultNet = "mainnet"
+
+// compactTxMaxSize is the maximum size of the transactions writing the
+// compacted copy.
+const compactTxMaxSize = 65536
+
+var (
+	datadir = btcutil.AppDataDir("btcwallet", false)
+
+	waddrmgrNamespaceKey = []byte("waddrmgr")
+	wtxmgrNamespaceKey   = []byte("wtxmgr")
+)
+
+// Flags.
+var opts = struct {
+	Force      bool          `short:"f" description:"Overwrite an existing output file"`
+	DbPath     string        `long:"db" description:"Path to wallet database"`
+	OutPath    string        `long:"out" description:"Path of the compacted copy (default: <db>.compact)"`
+	Repair     bool          `long:"repair" description:"Rebuild the derived indexes of the compacted copy"`
+	WalletPass string        `long:"walletpass" default-mask:"-" description:"The public wallet password, needed to check an encrypted database"`
+	Timeout    time.Duration `long:"timeout" description:"Timeout value when opening the wallet database"`
+}{
+	DbPath:     filepath.Join(datadir, defaultNet, wallet.WalletDBName),
+	WalletPass: wallet.InsecurePubPassphrase,
+	Timeout:    wallet.DefaultDBTimeout,
+}
+
+func init() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if opts.OutPath == "" {
+		opts.OutPath = opts.DbPath + ".compact"
+	}
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+// mainInt checks the database and writes its compacted copy, which is then
+// checked and optionally repaired.  The original database is never modified.
+// It returns 0 if no problems remain, 2 if some do, and 1 on failure.
+func mainInt() int {
+	fmt.Println("Database path:", opts.DbPath)
+	srcInfo, err := os.Stat(opts.DbPath)
+	if os.IsNotExist(err) {
+		fmt.Println("Database file does not exist")
+		return 1
+	}
+	if err != nil {
+		fmt.Println("Failed to stat database:", err)
+		return 1
+	}
+	if _, err := os.Stat(opts.OutPath); err == nil && !opts.Force {
+		fmt.Println("Output file", opts.OutPath, "exists, use -f to "+
+			"overwrite it")
+		return 1
+	}
+
+	src, err := bbolt.Open(opts.DbPath, 0600, &bbolt.Options{
+		ReadOnly: true,
+		Timeout:  opts.Timeout,
+	})
+	if err != nil {
+		fmt.Println("Failed to open database:", err)
+		return 1
+	}
+	defer src.Close()
+
+	fmt.Println("Checking page consistency")
+	var pageProblems int
+	err = src.View(func(tx *bbolt.Tx) error {
+		for err := range tx.Check() {
+			fmt.Println("  ", err)
+			pageProblems++
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("Failed to check database:", err)
+		return 1
+	}
+	if pageProblems != 0 {
+		// The wallet records can't be trusted to be copied correctly
+		// from inconsistent pages.
+		fmt.Printf("Found %d page problems, not compacting\n",
+			pageProblems)
+		return 2
+	}
+
+	fmt.Println("Writing compacted copy to", opts.OutPath)
+	if err := os.Remove(opts.OutPath); err != nil && !os.IsNotExist(err) {
+		fmt.Println("Failed to remove output file:", err)
+		return 1
+	}
+	dst, err := bbolt.Open(opts.OutPath, 0600, nil)
+	if err != nil {
+		fmt.Println("Failed to create output file:", err)
+		return 1
+	}
+	err = bbolt.Compact(dst, src, compactTxMaxSize)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Println("Failed to compact database:", err)
+		return 1
+	}
+	dstInfo, err := os.Stat(opts.OutPath)
+	if err != nil {
+		fmt.Println("Failed to stat output file:", err)
+		return 1
+	}
+	fmt.Printf("Compacted %d bytes to %d bytes\n", srcInfo.Size(),
+		dstInfo.Size())
+
+	db, err := openCopy()
+	if err != nil {
+		fmt.Println("Failed to open compacted copy:", err)
+		return 1
+	}
+	defer db.Close()
+
+	problems, err := check(db)
+	if err != nil {
+		fmt.Println("Failed to check wallet records:", err)
+		return 1
+	}
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return 0
+	}
+	printProblems(problems)
+	if !opts.Repair {
+		return 2
+	}
+
+	fmt.Println("Repairing derived indexes of the compacted copy")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		if ns := tx.ReadWriteBucket(waddrmgrNamespaceKey); ns != nil {
+			if err := waddrmgr.RepairIndexes(ns); err != nil {
+				return err
+			}
+		}
+		if ns := tx.ReadWriteBucket(wtxmgrNamespaceKey); ns != nil {
+			return wtxmgr.RepairStore(ns)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("Failed to repair compacted copy:", err)
+		return 1
+	}
+
+	problems, err = check(db)
+	if err != nil {
+		fmt.Println("Failed to check wallet records:", err)
+		return 1
+	}
+	if len(problems) != 0 {
+		fmt.Println("Problems remaining after repair:")
+		printProblems(problems)
+		return 2
+	}
+
+	fmt.Println("All problems repaired")
+	return 0
+}
+
+// openCopy opens the compacted copy, decrypting it if it's encrypted.
+func openCopy() (walletdb.DB, error) {
+	db, err := walletdb.Open("bdb", opts.OutPath, true, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encdb.IsEncrypted(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !encrypted {
+		return db, nil
+	}
+
+	encDB, err := encdb.Open(db, []byte(opts.WalletPass))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return encDB, nil
+}
+
+// check checks the wallet records of the database, returning a description of
+// each problem found.
+func check(db walletdb.DB) ([]string, error) {
+	var problems []string
+	err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+		if ns := tx.ReadBucket(waddrmgrNamespaceKey); ns != nil {
+			fmt.Println("Checking address manager indexes")
+			p, err := waddrmgr.CheckIndexes(ns)
+			if err != nil {
+				return err
+			}
+			problems = append(problems, p...)
+		}
+		if ns := tx.ReadBucket(wtxmgrNamespaceKey); ns != nil {
+			fmt.Println("Checking transaction store records")
+			p, err := wtxmgr.CheckStore(ns)
+			if err != nil {
+				return err
+			}
+			for _, problem := range p {
+				problems = append(problems, "wtxmgr: "+problem)
+			}
+		}
+		return nil
+	})
+
+	return problems, err
+}
+
+func printProblems(problems []string) {
+	fmt.Printf("Found %d problems:\n", len(problems))
+	for _, p := range problems {
+		fmt.Println("  ", p)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
+	"github.com/btcsuite/btcwallet/netparams"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/jessevdk/go-flags"
+	"golang.org/x/term"
+)
+
+const defaultNet = "mainnet"
+
+var (
+	datadir = btcutil.AppDataDir("btcwallet", false)
+)
+
+// Flags.
+var opts = struct {
+	DbPath       string        `long:"db" description:"Path to wallet database"`
+	KeystorePath string        `long:"keystore" description:"Path to the legacy keystore (default: wallet.bin next to the database)"`
+	Net          string        `long:"net" description:"Network of the wallet (mainnet, testnet3, testnet4, regtest, simnet or signet)"`
+	Scope        string        `long:"scope" description:"Key scope to import the addresses into, as purpose/coin"`
+	WalletPass   string        `long:"walletpass" default-mask:"-" description:"The public wallet password"`
+	Timeout      time.Duration `long:"timeout" description:"Timeout value when opening the wallet database"`
+}{
+	DbPath:     filepath.Join(datadir, defaultNet, wallet.WalletDBName),
+	Net:        defaultNet,
+	Scope:      "44/0",
+	WalletPass: wallet.InsecurePubPassphrase,
+	Timeout:    wallet.DefaultDBTimeout,
+}
+
+var networks = []*netparams.Params{
+	&netparams.MainNetParams,
+	&netparams.TestNet3Params,
+	&netparams.TestNet4Params,
+	&netparams.RegressionNetParams,
+	&netparams.SimNetParams,
+	&netparams.SigNetParams,
+}
+
+func init() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if opts.KeystorePath == "" {
+		opts.KeystorePath = filepath.Join(
+			filepath.Dir(opts.DbPath), keystore.Filename,
+		)
+	}
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+func mainInt() int {
+	var chainParams *chaincfg.Params
+	for _, params := range networks {
+		if params.Name == opts.Net {
+			chainParams = params.Params
+		}
+	}
+	if chainParams == nil {
+		fmt.Println("Unknown network:", opts.Net)
+		return 1
+	}
+
+	scope, err := parseScope(opts.Scope)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	fmt.Println("Database path:", opts.DbPath)
+	fmt.Println("Legacy keystore path:", opts.KeystorePath)
+	if _, err := os.Stat(opts.DbPath); os.IsNotExist(err) {
+		fmt.Println("Database file does not exist")
+		return 1
+	}
+	if filepath.Base(opts.DbPath) != wallet.WalletDBName {
+		fmt.Printf("Database file must be named %s\n",
+			wallet.WalletDBName)
+		return 1
+	}
+
+	ks, err := openKeystore(opts.KeystorePath)
+	if err != nil {
+		fmt.Println("Failed to open legacy keystore:", err)
+		return 1
+	}
+
+	keystorePass, err := promptSecret("Legacy keystore passphrase")
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if err := ks.Unlock([]byte(keystorePass)); err != nil {
+		fmt.Println("Failed to unlock legacy keystore:", err)
+		return 1
+	}
+	defer func() { _ = ks.Lock() }()
+
+	privPass, err := promptSecret("Wallet private passphrase " +
+		"(empty for the keystore passphrase)")
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if privPass == "" {
+		privPass = keystorePass
+	}
+
+	loader := wallet.NewLoader(
+		chainParams, filepath.Dir(opts.DbPath), true, opts.Timeout, 0,
+	)
+	w, err := loader.OpenExistingWallet([]byte(opts.WalletPass), false)
+	if err != nil {
+		fmt.Println("Failed to open wallet:", err)
+		return 1
+	}
+	defer func() { _ = loader.UnloadWallet() }()
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{}
+	}()
+	if err := w.Unlock([]byte(privPass), lock); err != nil {
+		fmt.Println("Failed to unlock wallet:", err)
+		return 1
+	}
+
+	fmt.Println("Importing legacy keystore addresses")
+
+	report, err := w.ImportLegacyKeystore(ks, scope, false)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	if err := writeReport(report); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	if failed := len(report.Failed()); failed != 0 {
+		fmt.Printf("%d of %d addresses failed to import\n", failed,
+			len(report.Addresses))
+		return 1
+	}
+	if len(report.Addresses) == 0 {
+		fmt.Println("The legacy keystore has no addresses")
+		return 0
+	}
+
+	// The wallet's sync tip is kept, so the history of the addresses is
+	// only found by rescanning.
+	oldest := report.Addresses[0].Birthday.Height
+	for _, addr := range report.Addresses {
+		if addr.Birthday.Height < oldest {
+			oldest = addr.Birthday.Height
+		}
+	}
+	fmt.Printf("All %d addresses are in the wallet.  Rescan from height "+
+		"%d to find their transactions.\n", len(report.Addresses),
+		oldest)
+	return 0
+}
+
+// openKeystore reads a legacy keystore file.
+func openKeystore(path string) (*keystore.Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ks := new(keystore.Store)
+	if _, err := ks.ReadFrom(f); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// parseScope parses a key scope given as purpose/coin, optionally in the
+// m/purpose'/coin' form used to print them.
+func parseScope(s string) (waddrmgr.KeyScope, error) {
+	s = strings.TrimPrefix(s, "m/")
+	s = strings.ReplaceAll(s, "'", "")
+
+	var scope waddrmgr.KeyScope
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return scope, fmt.Errorf("invalid key scope %q", s)
+	}
+	purpose, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return scope, fmt.Errorf("invalid key scope purpose: %w", err)
+	}
+	coin, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return scope, fmt.Errorf("invalid key scope coin: %w", err)
+	}
+
+	scope.Purpose = uint32(purpose)
+	scope.Coin = uint32(coin)
+	return scope, nil
+}
+
+// writeReport writes the import of each address as a table.
+func writeReport(report *wallet.LegacyKeystoreImport) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tTYPE\tFIRST BLOCK\tBIRTHDAY\tSTATUS")
+	for _, addr := range report.Addresses {
+		kind := "pubkey"
+		if addr.Script {
+			kind = "script"
+		}
+
+		status := "imported"
+		switch {
+		case addr.Err != nil:
+			status = "failed: " + addr.Err.Error()
+		case addr.Existed:
+			status = "already in wallet"
+		}
+
+		fmt.Fprintf(tw, "%v\t%s\t%d\t%d\t%s\n", addr.Address, kind,
+			addr.FirstBlock, addr.Birthday.Height, status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if report.BirthdayBlock != nil {
+		fmt.Printf("Wallet birthday moved back to block %v (height %d)\n",
+			report.BirthdayBlock.Hash, report.BirthdayBlock.Height)
+	}
+	return nil
+}
+
+func promptSecret(what string) (string, error) {
+	fmt.Printf("%s: ", what)
+	fd := int(os.Stdin.Fd())
+	input, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	return string(input), nil
+}
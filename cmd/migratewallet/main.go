@@ -0,0 +1,264 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/encdb"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/jessevdk/go-flags"
+)
+
+const defaultNet = "mainnet"
+
+var datadir = btcutil.AppDataDir("btcwallet", false)
+
+// Flags.
+var opts = struct {
+	DbPath     string        `long:"db" description:"Path to wallet database"`
+	BackupPath string        `long:"backup" description:"Path of the pre-migration backup (default: <db>.premigration)"`
+	DryRun     bool          `long:"dryrun" description:"Apply the pending migrations to a temporary copy of the database"`
+	Migrate    bool          `long:"migrate" description:"Back up the database and apply the pending migrations"`
+	Rollback   bool          `long:"rollback" description:"Replace the database with the pre-migration backup"`
+	Force      bool          `short:"f" description:"Force rollback without prompt"`
+	WalletPass string        `long:"walletpass" default-mask:"-" description:"The public wallet password, needed for an encrypted database"`
+	Timeout    time.Duration `long:"timeout" description:"Timeout value when opening the wallet database"`
+}{
+	DbPath:     filepath.Join(datadir, defaultNet, wallet.WalletDBName),
+	WalletPass: wallet.InsecurePubPassphrase,
+	Timeout:    wallet.DefaultDBTimeout,
+}
+
+func init() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	actions := 0
+	for _, set := range []bool{opts.DryRun, opts.Migrate, opts.Rollback} {
+		if set {
+			actions++
+		}
+	}
+	if actions > 1 {
+		fmt.Fprintln(os.Stderr, "Only one of --dryrun, --migrate and "+
+			"--rollback may be used")
+		os.Exit(1)
+	}
+
+	if opts.BackupPath == "" {
+		opts.BackupPath = opts.DbPath + wallet.MigrationBackupSuffix
+	}
+}
+
+func yes(s string) bool {
+	switch s {
+	case "y", "Y", "yes", "Yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func no(s string) bool {
+	switch s {
+	case "n", "N", "no", "No":
+		return true
+	default:
+		return false
+	}
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+// mainInt reports the migration status of the database, and performs the
+// requested action.  It returns 0 on success, 2 if migrations are pending
+// when only reporting the status, and 1 on failure.
+func mainInt() int {
+	fmt.Println("Database path:", opts.DbPath)
+	if _, err := os.Stat(opts.DbPath); os.IsNotExist(err) {
+		fmt.Println("Database file does not exist")
+		return 1
+	}
+
+	if opts.Rollback {
+		return rollback()
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Println("Failed to open database:", err)
+		return 1
+	}
+	statuses, err := wallet.MigrationStatuses(db)
+	if err != nil {
+		db.Close()
+		fmt.Println("Failed to read database versions:", err)
+		return 1
+	}
+	printStatuses(statuses)
+
+	pending := false
+	for i := range statuses {
+		if statuses[i].Unsupported() {
+			db.Close()
+			fmt.Println("Database was written by a newer wallet " +
+				"and can not be migrated")
+			return 1
+		}
+		pending = pending || statuses[i].Pending()
+	}
+	if !pending {
+		db.Close()
+		fmt.Println("No migrations pending")
+		return 0
+	}
+
+	switch {
+	case opts.DryRun:
+		// The dry run opens the database itself.
+		db.Close()
+		return dryRun()
+
+	case opts.Migrate:
+		defer db.Close()
+		return migrate(db)
+
+	default:
+		db.Close()
+		return 2
+	}
+}
+
+// openDB opens the database, decrypting it if it's encrypted.
+func openDB() (walletdb.DB, error) {
+	db, err := walletdb.Open("bdb", opts.DbPath, true, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encdb.IsEncrypted(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !encrypted {
+		return db, nil
+	}
+
+	encDB, err := encdb.Open(db, []byte(opts.WalletPass))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return encDB, nil
+}
+
+func dryRun() int {
+	fmt.Println("Applying migrations to a temporary copy")
+	statuses, err := wallet.DryRunMigrations(
+		opts.DbPath, []byte(opts.WalletPass), opts.Timeout,
+	)
+	if err != nil {
+		fmt.Println("Migrations failed:", err)
+		return 1
+	}
+
+	fmt.Println("Migrations succeeded")
+	printStatuses(statuses)
+	return 0
+}
+
+func migrate(db walletdb.DB) int {
+	fmt.Println("Writing backup to", opts.BackupPath)
+	if err := wallet.BackupDB(db, opts.BackupPath); err != nil {
+		fmt.Println("Failed to write backup:", err)
+		return 1
+	}
+
+	fmt.Println("Applying migrations")
+	if err := wallet.ApplyMigrations(db); err != nil {
+		fmt.Println("Migrations failed, the database is "+
+			"unchanged:", err)
+		return 1
+	}
+
+	statuses, err := wallet.MigrationStatuses(db)
+	if err != nil {
+		fmt.Println("Failed to read database versions:", err)
+		return 1
+	}
+	fmt.Println("Migrations applied")
+	printStatuses(statuses)
+	return 0
+}
+
+func rollback() int {
+	fmt.Println("Backup path:", opts.BackupPath)
+	if _, err := os.Stat(opts.BackupPath); os.IsNotExist(err) {
+		fmt.Println("Backup file does not exist")
+		return 1
+	}
+
+	for !opts.Force {
+		fmt.Print("Replace the database with the backup, losing every " +
+			"change made since it was written? [y/N] ")
+
+		scanner := bufio.NewScanner(bufio.NewReader(os.Stdin))
+		if !scanner.Scan() {
+			// Exit on EOF.
+			return 0
+		}
+		err := scanner.Err()
+		if err != nil {
+			fmt.Println()
+			fmt.Println(err)
+			return 1
+		}
+		resp := scanner.Text()
+		if yes(resp) {
+			break
+		}
+		if no(resp) || resp == "" {
+			return 0
+		}
+
+		fmt.Println("Enter yes or no.")
+	}
+
+	err := wallet.RestoreBackup(opts.DbPath, opts.BackupPath, opts.Timeout)
+	if err != nil {
+		fmt.Println("Failed to restore backup:", err)
+		return 1
+	}
+
+	fmt.Println("Database restored from backup")
+	return 0
+}
+
+func printStatuses(statuses []wallet.MigrationStatus) {
+	for _, s := range statuses {
+		state := "up to date"
+		switch {
+		case s.Pending():
+			state = "migration pending"
+		case s.Unsupported():
+			state = "newer than supported"
+		}
+		fmt.Printf("  %s: version %d, latest %d (%s)\n", s.Name,
+			s.Version, s.LatestVersion, state)
+	}
+}
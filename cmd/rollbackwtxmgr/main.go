@@ -0,0 +1,198 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/encdb"
+	"github.com/btcsuite/btcwallet/netparams"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/jessevdk/go-flags"
+)
+
+const defaultNet = "mainnet"
+
+var (
+	datadir = btcutil.AppDataDir("btcwallet", false)
+)
+
+// Flags.
+var opts = struct {
+	Force      bool          `short:"f" description:"Force rollback without prompt"`
+	DbPath     string        `long:"db" description:"Path to wallet database"`
+	Net        string        `long:"net" description:"Network of the wallet (mainnet, testnet3, testnet4, regtest, simnet or signet)"`
+	Height     int32         `long:"height" description:"Height of the block to roll back to"`
+	Hash       string        `long:"hash" description:"Hash of the block to roll back to, instead of its height"`
+	WalletPass string        `long:"walletpass" default-mask:"-" description:"The public wallet password, needed for an encrypted database"`
+	Timeout    time.Duration `long:"timeout" description:"Timeout value when opening the wallet database"`
+}{
+	Force:      false,
+	DbPath:     filepath.Join(datadir, defaultNet, wallet.WalletDBName),
+	Net:        defaultNet,
+	Height:     -1,
+	WalletPass: wallet.InsecurePubPassphrase,
+	Timeout:    wallet.DefaultDBTimeout,
+}
+
+var networks = []*netparams.Params{
+	&netparams.MainNetParams,
+	&netparams.TestNet3Params,
+	&netparams.TestNet4Params,
+	&netparams.RegressionNetParams,
+	&netparams.SimNetParams,
+	&netparams.SigNetParams,
+}
+
+func init() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if (opts.Height < 0) == (opts.Hash == "") {
+		fmt.Fprintln(os.Stderr, "Exactly one of --height and --hash "+
+			"must be set")
+		os.Exit(1)
+	}
+}
+
+func yes(s string) bool {
+	switch s {
+	case "y", "Y", "yes", "Yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func no(s string) bool {
+	switch s {
+	case "n", "N", "no", "No":
+		return true
+	default:
+		return false
+	}
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+func mainInt() int {
+	var chainParams *chaincfg.Params
+	for _, params := range networks {
+		if params.Name == opts.Net {
+			chainParams = params.Params
+		}
+	}
+	if chainParams == nil {
+		fmt.Println("Unknown network:", opts.Net)
+		return 1
+	}
+
+	var hash *chainhash.Hash
+	if opts.Hash != "" {
+		var err error
+		hash, err = chainhash.NewHashFromStr(opts.Hash)
+		if err != nil {
+			fmt.Println("Invalid block hash:", err)
+			return 1
+		}
+	}
+
+	fmt.Println("Database path:", opts.DbPath)
+	_, err := os.Stat(opts.DbPath)
+	if os.IsNotExist(err) {
+		fmt.Println("Database file does not exist")
+		return 1
+	}
+
+	for !opts.Force {
+		if hash != nil {
+			fmt.Printf("Roll back btcwallet transaction history to "+
+				"block %v? [y/N] ", hash)
+		} else {
+			fmt.Printf("Roll back btcwallet transaction history to "+
+				"height %d? [y/N] ", opts.Height)
+		}
+
+		scanner := bufio.NewScanner(bufio.NewReader(os.Stdin))
+		if !scanner.Scan() {
+			// Exit on EOF.
+			return 0
+		}
+		err := scanner.Err()
+		if err != nil {
+			fmt.Println()
+			fmt.Println(err)
+			return 1
+		}
+		resp := scanner.Text()
+		if yes(resp) {
+			break
+		}
+		if no(resp) || resp == "" {
+			return 0
+		}
+
+		fmt.Println("Enter yes or no.")
+	}
+
+	db, err := openDB()
+	if err != nil {
+		fmt.Println("Failed to open database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	fmt.Println("Rolling back btcwallet transaction history")
+
+	syncedTo, err := wallet.RollbackTransactionHistory(
+		db, chainParams, opts.Height, hash,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	fmt.Printf("Wallet is now synced to block %v (height %d), and will "+
+		"rescan the blocks after it when started\n", syncedTo.Hash,
+		syncedTo.Height)
+	return 0
+}
+
+// openDB opens the database, decrypting it if it's encrypted.
+func openDB() (walletdb.DB, error) {
+	db, err := walletdb.Open("bdb", opts.DbPath, true, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encdb.IsEncrypted(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !encrypted {
+		return db, nil
+	}
+
+	encDB, err := encdb.Open(db, []byte(opts.WalletPass))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return encDB, nil
+}
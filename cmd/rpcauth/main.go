@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/btcsuite/btcwallet/rpc/legacyrpc"
+	"github.com/jessevdk/go-flags"
+	"golang.org/x/term"
+)
+
+// Flags.
+var opts = struct {
+	Username   string  `short:"u" long:"username" description:"Name of the legacy RPC user" required:"true"`
+	Role       string  `long:"role" description:"Role of the user: readonly, invoicing, spender or admin" required:"true"`
+	DailyLimit float64 `long:"dailylimit" description:"Maximum amount in BTC a spender may send within 24 hours"`
+}{}
+
+func main() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	role, err := legacyrpc.ParseRole(opts.Role)
+	if err != nil {
+		return err
+	}
+	if role != legacyrpc.RoleSpender && opts.DailyLimit != 0 {
+		return fmt.Errorf("--dailylimit is only allowed for the %v role",
+			legacyrpc.RoleSpender)
+	}
+
+	password, err := promptSecret("Password")
+	if err != nil {
+		return err
+	}
+	confirm, err := promptSecret("Confirm password")
+	if err != nil {
+		return err
+	}
+	if password != confirm {
+		return fmt.Errorf("passwords do not match")
+	}
+
+	salt, hash, err := legacyrpc.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintf("rpcauth=%s:%v:%s$%s", opts.Username, role, salt,
+		hash)
+	if role == legacyrpc.RoleSpender {
+		line += fmt.Sprintf(":%v", opts.DailyLimit)
+	}
+
+	// Parse the result to catch invalid usernames and limits.
+	if _, err := legacyrpc.ParseUser(line[len("rpcauth="):]); err != nil {
+		return err
+	}
+
+	fmt.Println("Add the following line to btcwallet.conf:")
+	fmt.Println(line)
+	return nil
+}
+
+func promptSecret(what string) (string, error) {
+	fmt.Printf("%s: ", what)
+	fd := int(os.Stdin.Fd())
+	input, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	return string(input), nil
+}
@@ -0,0 +1,372 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+const (
+	// descriptorInputCharset is the character set of output descriptors,
+	// ordered as needed to compute their checksum.  See BIP 380.
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+	// descriptorChecksumCharset is the character set of descriptor
+	// checksums.
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// descriptorChecksumLength is the number of characters in a
+	// descriptor checksum.
+	descriptorChecksumLength = 8
+
+	// pubKeyBytesLenUncompressed is the length of an uncompressed public
+	// key.
+	pubKeyBytesLenUncompressed = 65
+)
+
+// descriptorKeyForm is a set of the serializations of hex encoded public keys.
+type descriptorKeyForm uint8
+
+const (
+	// keyCompressed is a 33 byte compressed public key.
+	keyCompressed descriptorKeyForm = 1 << iota
+
+	// keyUncompressed is a 65 byte uncompressed public key.
+	keyUncompressed
+
+	// keyXOnly is a 32 byte x-only public key.
+	keyXOnly
+)
+
+// String returns the name of a single key serialization.
+func (f descriptorKeyForm) String() string {
+	switch f {
+	case keyCompressed:
+		return "compressed"
+	case keyUncompressed:
+		return "uncompressed"
+	case keyXOnly:
+		return "x-only"
+	default:
+		return fmt.Sprintf("descriptorKeyForm(%d)", uint8(f))
+	}
+}
+
+// descriptorKeyForms maps the descriptor functions taking a key to the
+// serializations of the hex encoded keys they allow.  Segwit v0 only allows
+// compressed keys, and x-only keys only exist in taproot.  See BIPs 381, 382
+// and 386.
+var descriptorKeyForms = map[string]descriptorKeyForm{
+	"pk":   keyCompressed | keyUncompressed,
+	"pkh":  keyCompressed | keyUncompressed,
+	"wpkh": keyCompressed,
+	"tr":   keyCompressed | keyXOnly,
+}
+
+// parseDescriptor returns the output script of an output descriptor that
+// describes a single script.  The addr, raw, pk, pkh, wpkh, sh(wpkh) and
+// key-path only tr descriptors are supported.  Keys are hex encoded public
+// keys, in the serializations each function allows, or extended public keys
+// followed by unhardened derivation steps, with an optional key origin.
+// Ranged descriptors are rejected since they don't describe a single script.
+// The checksum is optional, but is verified when present.
+func parseDescriptor(desc string,
+	params *chaincfg.Params) ([]byte, error) {
+
+	if i := strings.IndexByte(desc, '#'); i != -1 {
+		checksum := desc[i+1:]
+		desc = desc[:i]
+
+		expected, err := descriptorChecksum(desc)
+		if err != nil {
+			return nil, err
+		}
+		if checksum != expected {
+			return nil, fmt.Errorf("descriptor checksum %q doesn't "+
+				"match the expected %q", checksum, expected)
+		}
+	}
+
+	fn, arg, err := splitDescriptor(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch fn {
+	case "addr":
+		addr, err := btcutil.DecodeAddress(arg, params)
+		if err != nil {
+			return nil, err
+		}
+		if !addr.IsForNet(params) {
+			return nil, fmt.Errorf("address %v is not for %s", addr,
+				params.Name)
+		}
+		return txscript.PayToAddrScript(addr)
+
+	case "raw":
+		script, err := hex.DecodeString(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid raw script: %w", err)
+		}
+		if len(script) == 0 {
+			return nil, errors.New("raw script is empty")
+		}
+		return script, nil
+
+	case "pk":
+		_, serialized, err := parseDescriptorKey(fn, arg, params)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.NewScriptBuilder().
+			AddData(serialized).
+			AddOp(txscript.OP_CHECKSIG).
+			Script()
+
+	case "pkh":
+		_, serialized, err := parseDescriptorKey(fn, arg, params)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := btcutil.NewAddressPubKeyHash(
+			btcutil.Hash160(serialized), params,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.PayToAddrScript(addr)
+
+	case "wpkh":
+		return witnessPubKeyHashScript(arg, params)
+
+	case "sh":
+		innerFn, innerArg, err := splitDescriptor(arg)
+		if err != nil {
+			return nil, err
+		}
+		if innerFn != "wpkh" {
+			return nil, fmt.Errorf("unsupported descriptor "+
+				"sh(%s(...))", innerFn)
+		}
+		witnessScript, err := witnessPubKeyHashScript(innerArg, params)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := btcutil.NewAddressScriptHash(witnessScript, params)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.PayToAddrScript(addr)
+
+	case "tr":
+		if strings.ContainsRune(arg, ',') {
+			return nil, errors.New("taproot descriptors with a " +
+				"script tree are not supported")
+		}
+		internalKey, _, err := parseDescriptorKey(fn, arg, params)
+		if err != nil {
+			return nil, err
+		}
+		taprootKey := txscript.ComputeTaprootKeyNoScript(internalKey)
+		return txscript.PayToTaprootScript(taprootKey)
+
+	default:
+		return nil, fmt.Errorf("unsupported descriptor %s(...)", fn)
+	}
+}
+
+// splitDescriptor splits a descriptor of the form fn(arg) into its function
+// name and argument.
+func splitDescriptor(desc string) (string, string, error) {
+	open := strings.IndexByte(desc, '(')
+	if open <= 0 || !strings.HasSuffix(desc, ")") {
+		return "", "", fmt.Errorf("invalid descriptor %q", desc)
+	}
+	return desc[:open], desc[open+1 : len(desc)-1], nil
+}
+
+// witnessPubKeyHashScript returns the pay-to-witness-pubkey-hash script of a
+// descriptor key.
+func witnessPubKeyHashScript(key string,
+	params *chaincfg.Params) ([]byte, error) {
+
+	_, serialized, err := parseDescriptorKey("wpkh", key, params)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(serialized), params,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(addr)
+}
+
+// parseDescriptorKey parses the public key of a key expression of the
+// descriptor function fn, and returns it along with its serialization.  The
+// key is either a hex encoded public key, in one of the serializations fn
+// allows, or an extended public key followed by unhardened derivation steps,
+// which is serialized compressed.  A key origin is ignored.
+func parseDescriptorKey(fn, key string,
+	params *chaincfg.Params) (*btcec.PublicKey, []byte, error) {
+
+	if strings.HasPrefix(key, "[") {
+		end := strings.IndexByte(key, ']')
+		if end == -1 {
+			return nil, nil, fmt.Errorf("invalid key origin in %q",
+				key)
+		}
+		key = key[end+1:]
+	}
+
+	if keyBytes, err := hex.DecodeString(key); err == nil {
+		var form descriptorKeyForm
+		switch {
+		case len(keyBytes) == schnorr.PubKeyBytesLen:
+			form = keyXOnly
+		case len(keyBytes) == btcec.PubKeyBytesLenCompressed:
+			form = keyCompressed
+		case len(keyBytes) == pubKeyBytesLenUncompressed &&
+			keyBytes[0] == 0x04:
+
+			form = keyUncompressed
+		default:
+			return nil, nil, fmt.Errorf("invalid public key %q",
+				key)
+		}
+		if descriptorKeyForms[fn]&form == 0 {
+			return nil, nil, fmt.Errorf("%v public keys are not "+
+				"allowed in %s()", form, fn)
+		}
+
+		var pubKey *btcec.PublicKey
+		if form == keyXOnly {
+			pubKey, err = schnorr.ParsePubKey(keyBytes)
+		} else {
+			pubKey, err = btcec.ParsePubKey(keyBytes)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return pubKey, keyBytes, nil
+	}
+
+	steps := strings.Split(key, "/")
+	extKey, err := hdkeychain.NewKeyFromString(steps[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid descriptor key %q: %w",
+			key, err)
+	}
+	if extKey.IsPrivate() {
+		return nil, nil, errors.New("descriptor keys must be public")
+	}
+	if !extKey.IsForNet(params) {
+		return nil, nil, fmt.Errorf("extended key is not for %s",
+			params.Name)
+	}
+
+	for _, step := range steps[1:] {
+		switch {
+		case step == "*":
+			return nil, nil, errors.New("ranged descriptors are " +
+				"not supported")
+
+		case strings.HasSuffix(step, "'"), strings.HasSuffix(step, "h"):
+			return nil, nil, errors.New("hardened derivation " +
+				"requires a private key")
+		}
+
+		index, err := strconv.ParseUint(step, 10, 32)
+		if err != nil || index >= hdkeychain.HardenedKeyStart {
+			return nil, nil, fmt.Errorf("invalid derivation step %q",
+				step)
+		}
+		extKey, err = extKey.Derive(uint32(index))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pubKey, err := extKey.ECPubKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return pubKey, pubKey.SerializeCompressed(), nil
+}
+
+// descriptorChecksum computes the checksum of a descriptor as specified by
+// BIP 380.
+func descriptorChecksum(desc string) (string, error) {
+	c := uint64(1)
+	class, classCount := 0, 0
+	for _, ch := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos == -1 {
+			return "", fmt.Errorf("invalid descriptor character %q",
+				ch)
+		}
+
+		// Each character adds its position within its group of 32
+		// characters, and every three characters add their groups.
+		c = descriptorPolyMod(c, pos&31)
+		class = class*3 + pos>>5
+		classCount++
+		if classCount == 3 {
+			c = descriptorPolyMod(c, class)
+			class, classCount = 0, 0
+		}
+	}
+	if classCount > 0 {
+		c = descriptorPolyMod(c, class)
+	}
+	for i := 0; i < descriptorChecksumLength; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, descriptorChecksumLength)
+	for i := range checksum {
+		shift := 5 * (descriptorChecksumLength - 1 - i)
+		checksum[i] = descriptorChecksumCharset[(c>>shift)&31]
+	}
+	return string(checksum), nil
+}
+
+// descriptorPolyMod adds a value to the descriptor checksum polynomial.
+func descriptorPolyMod(c uint64, val int) uint64 {
+	c0 := c >> 35
+	c = (c&0x7ffffffff)<<5 ^ uint64(val)
+	if c0&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if c0&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if c0&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if c0&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if c0&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDescriptorChecksum checks the descriptor checksums against the test
+// vectors of BIP 380.
+func TestDescriptorChecksum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc  string
+		valid bool
+	}{
+		{"raw(deadbeef)#89f8spxm", true},
+		{"raw(deadbeef)", true},
+
+		// Missing checksum.
+		{"raw(deadbeef)#", false},
+		// Too long checksum.
+		{"raw(deadbeef)#89f8spxmx", false},
+		// Too short checksum.
+		{"raw(deadbeef)#89f8spx", false},
+		// Error in the payload.
+		{"raw(deedbeef)#89f8spxm", false},
+		// Error in the checksum.
+		{"raw(deadbeef)##9f8spxm", false},
+		// Invalid characters in the payload.
+		{"raw(Ü)#00000000", false},
+	}
+
+	for _, test := range tests {
+		_, err := parseDescriptor(test.desc, &chaincfg.MainNetParams)
+		if test.valid {
+			require.NoError(t, err, test.desc)
+		} else {
+			require.Error(t, err, test.desc)
+		}
+	}
+
+	checksum, err := descriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	require.Equal(t, "89f8spxm", checksum)
+}
+
+// TestParseDescriptor checks the output scripts of each supported descriptor
+// function, mostly against the test vectors of BIPs 381 to 386, and that
+// descriptors and keys they don't allow are rejected.
+func TestParseDescriptor(t *testing.T) {
+	t.Parallel()
+
+	const (
+		compressed = "03a34b99f22c790c4e36b2b3c2c35a36db06226e41c692" +
+			"fc82b8b56ac1c540c5bd"
+		uncompressed = "04a34b99f22c790c4e36b2b3c2c35a36db06226e41c6" +
+			"92fc82b8b56ac1c540c5bd5b8dec5235a0fa8722476c7709c0" +
+			"2559e3aa73aa03918ba2d492eea75abea235"
+		xOnly = "a34b99f22c790c4e36b2b3c2c35a36db06226e41c692fc82b8" +
+			"b56ac1c540c5bd"
+		xpub = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBF" +
+			"A1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2d" +
+			"ZXvgGDnw"
+		tpub = "tpubD6NzVbkrYhZ4WaWSyoBvQwbpLkojyoTZPRsgXELWz3Popb3" +
+			"qkjcJyJUGLnL4qHHoQvao8ESaAstxYSnhyswJ76uZPStJRJCTKv" +
+			"osUCJZL5B"
+	)
+
+	tests := []struct {
+		name   string
+		desc   string
+		script string
+	}{{
+		name:   "addr",
+		desc:   "addr(bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4)",
+		script: "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+	}, {
+		name:   "raw",
+		desc:   "raw(deadbeef)",
+		script: "deadbeef",
+	}, {
+		name: "pk",
+		desc: "pk(0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d95" +
+			"9f2815b16f81798)",
+		script: "210279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d9" +
+			"59f2815b16f81798ac",
+	}, {
+		name:   "pk uncompressed",
+		desc:   "pk(" + uncompressed + ")",
+		script: "41" + uncompressed + "ac",
+	}, {
+		name: "pkh",
+		desc: "pkh(02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7a" +
+			"bac09b95c709ee5)",
+		script: "76a91406afd46bcdfd22ef94ac122aa11f241244a37ecc88ac",
+	}, {
+		name:   "pkh uncompressed",
+		desc:   "pkh(" + uncompressed + ")",
+		script: "76a914b5bd079c4d57cc7fc28ecf8213a6b791625b818388ac",
+	}, {
+		name: "pkh key origin",
+		desc: "pkh([deadbeef/1/2'/3/4']02c6047f9441ed7d6d3045406e95c07" +
+			"cd85c778e4b8cef3ca7abac09b95c709ee5)",
+		script: "76a91406afd46bcdfd22ef94ac122aa11f241244a37ecc88ac",
+	}, {
+		name:   "pkh xpub",
+		desc:   "pkh(" + xpub + "/1/2)",
+		script: "76a914f833c08f02389c451ae35ec797fccf7f396616bf88ac",
+	}, {
+		name: "wpkh",
+		desc: "wpkh(02f9308a019258c31049344f85f89d5229b531c845836f99b0" +
+			"8601f113bce036f9)",
+		script: "00147dd65592d0ab2fe0d0257d571abf032cd9db93dc",
+	}, {
+		name: "sh(wpkh)",
+		desc: "sh(wpkh(03fff97bd5755eeea420453a14355235d382f6472f8568" +
+			"a18b2f057a1460297556))",
+		script: "a914cc6ffbc0bf31af759451068f90ba7a0272b6b33287",
+	}, {
+		name: "tr",
+		desc: "tr(" + xOnly + ")",
+		script: "512077aab6e066f8a7419c5ab714c12c67d25007ed55a43cadcac" +
+			"b4d7a970a093f11",
+	}, {
+		// A compressed key is used by its x coordinate.
+		name: "tr compressed",
+		desc: "tr(" + compressed + ")",
+		script: "512077aab6e066f8a7419c5ab714c12c67d25007ed55a43cadcac" +
+			"b4d7a970a093f11",
+	}, {
+		name: "checksum",
+		desc: "wpkh(02f9308a019258c31049344f85f89d5229b531c845836f99b0" +
+			"8601f113bce036f9)#8zl0zxma",
+		script: "00147dd65592d0ab2fe0d0257d571abf032cd9db93dc",
+	}}
+
+	for _, test := range tests {
+		script, err := parseDescriptor(test.desc, &chaincfg.MainNetParams)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.script, hex.EncodeToString(script),
+			test.name)
+	}
+
+	invalid := []struct {
+		name string
+		desc string
+	}{
+		{"wpkh uncompressed", "wpkh(" + uncompressed + ")"},
+		{"sh(wpkh) uncompressed", "sh(wpkh(" + uncompressed + "))"},
+		{"wpkh x-only", "wpkh(" + xOnly + ")"},
+		{"pk x-only", "pk(" + xOnly + ")"},
+		{"pkh x-only", "pkh(" + xOnly + ")"},
+		{"tr uncompressed", "tr(" + uncompressed + ")"},
+		{"hybrid key", "pkh(06" + uncompressed[2:] + ")"},
+		{"short key", "pkh(" + compressed[:64] + ")"},
+		{"ranged", "wpkh(" + xpub + "/1/*)"},
+		{"hardened", "wpkh(" + xpub + "/1h)"},
+		{"wrong network", "wpkh(" + tpub + "/1)"},
+		{"script tree", "tr(" + xOnly + ",pk(" + xOnly + "))"},
+		{"sh(pkh)", "sh(pkh(" + compressed + "))"},
+		{"wsh", "wsh(pk(" + compressed + "))"},
+		{"missing parenthesis", "pkh(" + compressed},
+		{"empty raw", "raw()"},
+		{"wrong address network", "addr(tb1qw508d6qejxtdg4y5r3zarvar" +
+			"y0c5xw7kxpjzsx)"},
+	}
+	for _, test := range invalid {
+		_, err := parseDescriptor(test.desc, &chaincfg.MainNetParams)
+		require.Error(t, err, test.name)
+	}
+}
@@ -5,17 +5,26 @@
 package main
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/internal/cfgutil"
+	"github.com/btcsuite/btcwallet/internal/walletjson"
 	"github.com/btcsuite/btcwallet/netparams"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
@@ -27,6 +36,11 @@ import (
 var (
 	walletDataDirectory = btcutil.AppDataDir("btcwallet", false)
 	newlineBytes        = []byte{'\n'}
+	activeNet           = &netparams.MainNetParams
+
+	// destinationScript is the output script of an external destination,
+	// or nil when sweeping to the destination account.
+	destinationScript []byte
 )
 
 func fatalf(format string, args ...interface{}) {
@@ -42,13 +56,21 @@ func errContext(err error, context string) error {
 // Flags.
 var opts = struct {
 	TestNet3              bool                `long:"testnet" description:"Use the test bitcoin network (version 3)"`
+	TestNet4              bool                `long:"testnet4" description:"Use the test bitcoin network (version 4)"`
+	RegTest               bool                `long:"regtest" description:"Use the regression test network"`
 	SimNet                bool                `long:"simnet" description:"Use the simulation bitcoin network"`
 	RPCConnect            string              `short:"c" long:"connect" description:"Hostname[:port] of wallet RPC server"`
 	RPCUsername           string              `short:"u" long:"rpcuser" description:"Wallet RPC username"`
 	RPCCertificateFile    string              `long:"cafile" description:"Wallet RPC TLS certificate"`
 	FeeRate               *cfgutil.AmountFlag `long:"feerate" description:"Transaction fee per kilobyte"`
+	SatPerVByte           float64             `long:"satpervbyte" description:"Transaction fee in satoshis per virtual byte, instead of --feerate"`
+	ConfTarget            int64               `long:"conftarget" description:"Use the fee rate estimated to confirm within this many blocks, instead of --feerate"`
 	SourceAccount         string              `long:"sourceacct" description:"Account to sweep outputs from"`
 	DestinationAccount    string              `long:"destacct" description:"Account to send sweeped outputs to"`
+	DestinationAddress    string              `long:"destaddr" description:"External address to send sweeped outputs to, instead of --destacct"`
+	DestinationDescriptor string              `long:"destdescriptor" description:"Output descriptor of an external script to send sweeped outputs to, instead of --destacct"`
+	PSBT                  bool                `long:"psbt" description:"Print an unsigned PSBT of each sweep transaction for offline signing instead of signing and publishing it"`
+	MaxTxSize             int                 `long:"maxtxsize" description:"Maximum virtual size of a sweep transaction; the outputs of an address are split across several transactions if needed (0 for no limit)"`
 	RequiredConfirmations int64               `long:"minconf" description:"Required confirmations to include an output"`
 }{
 	TestNet3:              false,
@@ -62,8 +84,8 @@ var opts = struct {
 	RequiredConfirmations: 1,
 }
 
-// Parse and validate flags.
-func init() {
+// parseFlags parses and validates the flags.
+func parseFlags() {
 	// Unset localhost defaults if certificate file can not be found.
 	certFileExists, err := cfgutil.FileExists(opts.RPCCertificateFile)
 	if err != nil {
@@ -74,19 +96,31 @@ func init() {
 		opts.RPCCertificateFile = ""
 	}
 
-	_, err = flags.Parse(&opts)
+	parser := flags.NewParser(&opts, flags.Default)
+	_, err = parser.Parse()
 	if err != nil {
 		os.Exit(1)
 	}
 
-	if opts.TestNet3 && opts.SimNet {
-		fatalf("Multiple bitcoin networks may not be used simultaneously")
-	}
-	var activeNet = &netparams.MainNetParams
+	numNets := 0
 	if opts.TestNet3 {
 		activeNet = &netparams.TestNet3Params
-	} else if opts.SimNet {
+		numNets++
+	}
+	if opts.TestNet4 {
+		activeNet = &netparams.TestNet4Params
+		numNets++
+	}
+	if opts.RegTest {
+		activeNet = &netparams.RegressionNetParams
+		numNets++
+	}
+	if opts.SimNet {
 		activeNet = &netparams.SimNetParams
+		numNets++
+	}
+	if numNets > 1 {
+		fatalf("Multiple bitcoin networks may not be used simultaneously")
 	}
 
 	if opts.RPCConnect == "" {
@@ -110,20 +144,120 @@ func init() {
 		fatalf("RPC certificate file `%s` not found", opts.RPCCertificateFile)
 	}
 
-	if opts.FeeRate.Amount > 1e6 {
-		fatalf("Fee rate `%v/kB` is exceptionally high", opts.FeeRate.Amount)
+	numFeeModes := 0
+	if parser.FindOptionByLongName("feerate").IsSet() {
+		numFeeModes++
+	}
+	if opts.SatPerVByte != 0 {
+		// Fee rates are kept per kilobyte, which is a thousand
+		// virtual bytes.
+		feeRate, err := btcutil.NewAmount(opts.SatPerVByte * 1000 / 1e8)
+		if err != nil {
+			fatalf("Invalid fee rate `%v sat/vB`: %v", opts.SatPerVByte,
+				err)
+		}
+		opts.FeeRate.Amount = feeRate
+		numFeeModes++
+	}
+	if opts.ConfTarget != 0 {
+		if opts.ConfTarget < 1 {
+			fatalf("Confirmation target must be positive")
+		}
+		numFeeModes++
+	}
+	if numFeeModes > 1 {
+		fatalf("Only one of --feerate, --satpervbyte and --conftarget " +
+			"may be used")
+	}
+	if err := checkFeeRate(opts.FeeRate.Amount); err != nil {
+		fatalf("%v", err)
+	}
+
+	numDestinations := 0
+	if parser.FindOptionByLongName("destacct").IsSet() {
+		numDestinations++
+	}
+	if opts.DestinationAddress != "" {
+		addr, err := btcutil.DecodeAddress(opts.DestinationAddress,
+			activeNet.Params)
+		if err != nil {
+			fatalf("Invalid destination address `%v`: %v",
+				opts.DestinationAddress, err)
+		}
+		if !addr.IsForNet(activeNet.Params) {
+			fatalf("Destination address `%v` is not for %s",
+				opts.DestinationAddress, activeNet.Params.Name)
+		}
+		destinationScript, err = txscript.PayToAddrScript(addr)
+		if err != nil {
+			fatalf("Invalid destination address `%v`: %v",
+				opts.DestinationAddress, err)
+		}
+		numDestinations++
 	}
-	if opts.FeeRate.Amount < 1e2 {
-		fatalf("Fee rate `%v/kB` is exceptionally low", opts.FeeRate.Amount)
+	if opts.DestinationDescriptor != "" {
+		destinationScript, err = parseDescriptor(
+			opts.DestinationDescriptor, activeNet.Params,
+		)
+		if err != nil {
+			fatalf("Invalid destination descriptor `%v`: %v",
+				opts.DestinationDescriptor, err)
+		}
+		numDestinations++
 	}
-	if opts.SourceAccount == opts.DestinationAccount {
+	if numDestinations > 1 {
+		fatalf("Only one of --destacct, --destaddr and " +
+			"--destdescriptor may be used")
+	}
+	if destinationScript == nil &&
+		opts.SourceAccount == opts.DestinationAccount {
+
 		fatalf("Source and destination accounts should not be equal")
 	}
+
+	if opts.MaxTxSize < 0 {
+		fatalf("Maximum transaction size must be non-negative")
+	}
 	if opts.RequiredConfirmations < 0 {
 		fatalf("Required confirmations must be non-negative")
 	}
 }
 
+// checkFeeRate returns an error if a fee rate is outside of the range that
+// is reasonable to sweep with.
+func checkFeeRate(feeRate btcutil.Amount) error {
+	if feeRate > 1e6 {
+		return fmt.Errorf("fee rate `%v/kB` is exceptionally high",
+			feeRate)
+	}
+	if feeRate < 1e2 {
+		return fmt.Errorf("fee rate `%v/kB` is exceptionally low",
+			feeRate)
+	}
+	return nil
+}
+
+// estimateFeeRate returns the fee rate per kilobyte estimated to confirm a
+// transaction within the confirmation target.
+func estimateFeeRate(rpcClient *rpcclient.Client,
+	confTarget int64) (btcutil.Amount, error) {
+
+	estimate, err := rpcClient.EstimateSmartFee(confTarget, nil)
+	if err != nil {
+		return 0, err
+	}
+	if estimate.FeeRate == nil {
+		if len(estimate.Errors) != 0 {
+			return 0, fmt.Errorf("no fee rate estimate: %s",
+				strings.Join(estimate.Errors, ", "))
+		}
+		return 0, fmt.Errorf("no fee rate estimate")
+	}
+
+	// The estimate is in bitcoin per kilobyte.
+	return btcutil.NewAmount(*estimate.FeeRate)
+}
+
 // noInputValue describes an error returned by the input source when no inputs
 // were selected because each previous output value was zero.  Callers of
 // txauthor.NewUnsignedTransaction need not report these errors to the user.
@@ -134,14 +268,15 @@ func (noInputValue) Error() string { return "no input value" }
 
 // makeInputSource creates an InputSource that creates inputs for every unspent
 // output with non-zero output values.  The target amount is ignored since every
-// output is consumed.  The InputSource does not return any previous output
-// scripts as they are not needed for creating the unsinged transaction and are
-// looked up again by the wallet during the call to signrawtransaction.
+// output is consumed.  The previous output scripts are returned so the size of
+// the signed transaction, and with it the fee, is estimated for the kind of
+// each input.
 func makeInputSource(outputs []btcjson.ListUnspentResult) txauthor.InputSource {
 	var (
 		totalInputValue btcutil.Amount
 		inputs          = make([]*wire.TxIn, 0, len(outputs))
 		inputValues     = make([]btcutil.Amount, 0, len(outputs))
+		prevScripts     = make([][]byte, 0, len(outputs))
 		sourceErr       error
 	)
 	for _, output := range outputs {
@@ -173,8 +308,17 @@ func makeInputSource(outputs []btcjson.ListUnspentResult) txauthor.InputSource {
 			break
 		}
 
+		prevScript, err := hex.DecodeString(output.ScriptPubKey)
+		if err != nil {
+			sourceErr = fmt.Errorf(
+				"invalid script in listunspent result: %v",
+				err)
+			break
+		}
+
 		inputs = append(inputs, wire.NewTxIn(&previousOutPoint, nil, nil))
 		inputValues = append(inputValues, outputAmount)
+		prevScripts = append(prevScripts, prevScript)
 	}
 
 	if sourceErr == nil && totalInputValue == 0 {
@@ -182,7 +326,99 @@ func makeInputSource(outputs []btcjson.ListUnspentResult) txauthor.InputSource {
 	}
 
 	return func(btcutil.Amount) (btcutil.Amount, []*wire.TxIn, []btcutil.Amount, [][]byte, error) {
-		return totalInputValue, inputs, inputValues, nil, sourceErr
+		return totalInputValue, inputs, inputValues, prevScripts, sourceErr
+	}
+}
+
+// inputCounts counts the inputs of a transaction by the kind of script they
+// spend, as needed to estimate the size of the signed transaction.
+type inputCounts struct {
+	p2pkh, p2tr, p2wpkh, nested int
+}
+
+// add counts an input spending the previous output script.  Like
+// txauthor.NewUnsignedTransaction, P2SH outputs are assumed to be nested
+// P2WPKH and any other unknown script P2PKH.
+func (c *inputCounts) add(prevScript []byte) {
+	switch {
+	case txscript.IsPayToScriptHash(prevScript):
+		c.nested++
+	case txscript.IsPayToWitnessPubKeyHash(prevScript):
+		c.p2wpkh++
+	case txscript.IsPayToTaproot(prevScript):
+		c.p2tr++
+	default:
+		c.p2pkh++
+	}
+}
+
+// virtualSize estimates the virtual size of a signed sweep transaction of
+// the counted inputs and a single output with a script of the given size.
+func (c *inputCounts) virtualSize(destinationScriptSize int) int {
+	return txsizes.EstimateVirtualSize(
+		c.p2pkh, c.p2tr, c.p2wpkh, c.nested, nil, destinationScriptSize,
+	)
+}
+
+// splitOutputs splits unspent outputs into batches that are each swept by a
+// transaction of at most maxSize virtual bytes.  All outputs are returned in
+// a single batch when maxSize is zero.
+func splitOutputs(outputs []btcjson.ListUnspentResult,
+	destinationScriptSize, maxSize int) ([][]btcjson.ListUnspentResult,
+	error) {
+
+	if maxSize == 0 {
+		return [][]btcjson.ListUnspentResult{outputs}, nil
+	}
+
+	var (
+		batches [][]btcjson.ListUnspentResult
+		batch   []btcjson.ListUnspentResult
+		counts  inputCounts
+	)
+	for _, output := range outputs {
+		prevScript, err := hex.DecodeString(output.ScriptPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script in listunspent "+
+				"result: %v", err)
+		}
+
+		var single inputCounts
+		single.add(prevScript)
+		if single.virtualSize(destinationScriptSize) > maxSize {
+			return nil, fmt.Errorf("output %s:%d can not be swept "+
+				"within %d vbytes", output.TxID, output.Vout,
+				maxSize)
+		}
+
+		// Start a new batch with this output if it doesn't fit in
+		// the current one.
+		next := counts
+		next.add(prevScript)
+		if next.virtualSize(destinationScriptSize) > maxSize {
+			batches = append(batches, batch)
+			batch = nil
+			next = single
+		}
+
+		batch = append(batch, output)
+		counts = next
+	}
+	if len(batch) != 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+// makeExternalScriptSource creates a ChangeSource which is used to receive
+// all correlated previous input value with an external output script.
+func makeExternalScriptSource(script []byte) *txauthor.ChangeSource {
+	return &txauthor.ChangeSource{
+		ScriptSize: len(script),
+		NewScript: func() ([]byte, error) {
+			return script, nil
+		},
 	}
 }
 
@@ -208,6 +444,8 @@ func makeDestinationScriptSource(rpcClient *rpcclient.Client, accountName string
 }
 
 func main() {
+	parseFlags()
+
 	err := sweep()
 	if err != nil {
 		fatalf("%v", err)
@@ -237,10 +475,30 @@ func sweep() error {
 	}
 	defer rpcClient.Shutdown()
 
+	feeRate := opts.FeeRate.Amount
+	if opts.ConfTarget != 0 {
+		feeRate, err = estimateFeeRate(rpcClient, opts.ConfTarget)
+		if err != nil {
+			return errContext(err, "failed to estimate fee rate")
+		}
+		if err := checkFeeRate(feeRate); err != nil {
+			return err
+		}
+		fmt.Printf("Using estimated fee rate %v/kB\n", feeRate)
+	}
+
+	destinationName := "destination account"
+	destinationScriptSize := txsizes.P2PKHPkScriptSize
+	if destinationScript != nil {
+		destinationName = "destination"
+		destinationScriptSize = len(destinationScript)
+	}
+
 	// Fetch all unspent outputs, ignore those not from the source
 	// account, and group by their destination address.  Each grouping of
 	// outputs will be used as inputs for a single transaction sending to a
-	// new destination account address.
+	// new destination account address or the external destination, or
+	// split across several transactions when limited in size.
 	unspentOutputs, err := rpcClient.ListUnspent()
 	if err != nil {
 		return errContext(err, "failed to fetch unspent outputs")
@@ -260,8 +518,10 @@ func sweep() error {
 		sourceOutputs[unspentOutput.Address] = append(sourceAddressOutputs, unspentOutput)
 	}
 
+	// Unsigned PSBTs are signed elsewhere, so the wallet is never
+	// unlocked for them.
 	var privatePassphrase string
-	if len(sourceOutputs) != 0 {
+	if len(sourceOutputs) != 0 && !opts.PSBT {
 		privatePassphrase, err = promptSecret("Wallet private passphrase")
 		if err != nil {
 			return errContext(err, "failed to read private passphrase")
@@ -269,66 +529,290 @@ func sweep() error {
 	}
 
 	var totalSwept btcutil.Amount
-	var numErrors int
+	var numSwept, numErrors int
 	var reportError = func(format string, args ...interface{}) {
 		fmt.Fprintf(os.Stderr, format, args...)
 		os.Stderr.Write(newlineBytes)
 		numErrors++
 	}
-	for _, previousOutputs := range sourceOutputs {
-		inputSource := makeInputSource(previousOutputs)
-		destinationSource := makeDestinationScriptSource(rpcClient, opts.DestinationAccount)
-		tx, err := txauthor.NewUnsignedTransaction(nil, opts.FeeRate.Amount,
-			inputSource, destinationSource)
+	for sourceAddress, previousOutputs := range sourceOutputs {
+		batches, err := splitOutputs(previousOutputs,
+			destinationScriptSize, opts.MaxTxSize)
 		if err != nil {
-			if err != (noInputValue{}) {
-				reportError("Failed to create unsigned transaction: %v", err)
-			}
+			reportError("Failed to sweep %s: %v", sourceAddress, err)
 			continue
 		}
 
-		// Unlock the wallet, sign the transaction, and immediately lock.
-		err = rpcClient.WalletPassphrase(privatePassphrase, 60)
-		if err != nil {
-			reportError("Failed to unlock wallet: %v", err)
-			continue
+		for _, batch := range batches {
+			inputSource := makeInputSource(batch)
+			destinationSource := makeExternalScriptSource(destinationScript)
+			if destinationScript == nil {
+				destinationSource = makeDestinationScriptSource(
+					rpcClient, opts.DestinationAccount,
+				)
+			}
+			tx, err := txauthor.NewUnsignedTransaction(nil, feeRate,
+				inputSource, destinationSource)
+			if err != nil {
+				if err != (noInputValue{}) {
+					reportError("Failed to create unsigned transaction: %v", err)
+				}
+				continue
+			}
+
+			// The output is left out when it would be dust after
+			// paying the fee.
+			if len(tx.Tx.TxOut) == 0 {
+				reportError("Outputs of %s are too small to sweep",
+					sourceAddress)
+				continue
+			}
+			outputAmount := btcutil.Amount(tx.Tx.TxOut[0].Value)
+
+			if opts.PSBT {
+				encoded, err := createPSBT(rpcClient, tx,
+					sourceAddress)
+				if err != nil {
+					reportError("Failed to create PSBT: %v", err)
+					continue
+				}
+
+				fmt.Printf("Unsigned PSBT sweeping %v to %s with "+
+					"%d %s:\n%s\n", outputAmount,
+					destinationName, len(tx.Tx.TxIn),
+					pickNoun(len(tx.Tx.TxIn), "input",
+						"inputs"), encoded)
+				totalSwept += outputAmount
+				numSwept++
+				continue
+			}
+
+			// Unlock the wallet, sign the transaction, and immediately lock.
+			err = rpcClient.WalletPassphrase(privatePassphrase, 60)
+			if err != nil {
+				reportError("Failed to unlock wallet: %v", err)
+				continue
+			}
+			signedTransaction, complete, err := rpcClient.SignRawTransaction(tx.Tx)
+			_ = rpcClient.WalletLock()
+			if err != nil {
+				reportError("Failed to sign transaction: %v", err)
+				continue
+			}
+			if !complete {
+				reportError("Failed to sign every input")
+				continue
+			}
+
+			// Publish the signed sweep transaction.
+			txHash, err := rpcClient.SendRawTransaction(signedTransaction, false)
+			if err != nil {
+				reportError("Failed to publish transaction: %v", err)
+				continue
+			}
+
+			fmt.Printf("Swept %v to %s with transaction %v\n",
+				outputAmount, destinationName, txHash)
+			totalSwept += outputAmount
+			numSwept++
 		}
-		signedTransaction, complete, err := rpcClient.SignRawTransaction(tx.Tx)
-		_ = rpcClient.WalletLock()
+	}
+
+	action := "Swept"
+	failure := "publish"
+	if opts.PSBT {
+		action = "Prepared sweep of"
+		failure = "create"
+	}
+	if numSwept != 0 {
+		fmt.Printf("%s %v to %s across %d %s\n", action, totalSwept,
+			destinationName, numSwept,
+			pickNoun(numSwept, "transaction", "transactions"))
+	}
+	if numErrors > 0 {
+		return fmt.Errorf("failed to %s %d %s", failure, numErrors,
+			pickNoun(numErrors, "transaction", "transactions"))
+	}
+
+	return nil
+}
+
+// createPSBT creates an unsigned PSBT of a sweep transaction for signing
+// offline.  Like the PSBTs funded by the wallet, each input carries its
+// previous output, the full previous transaction unless it spends a taproot
+// output, and the redeem script and BIP 32 derivation path of the swept
+// address when the wallet knows them.
+func createPSBT(rpcClient *rpcclient.Client, tx *txauthor.AuthoredTx,
+	sourceAddress string) (string, error) {
+
+	packet, err := psbt.NewFromUnsignedTx(tx.Tx)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := addressInfo(rpcClient, sourceAddress)
+	if err != nil {
+		return "", errContext(err, "failed to look up source address")
+	}
+	var redeemScript []byte
+	if info.Embedded != nil && info.IsScript {
+		redeemScript, err = hex.DecodeString(info.Embedded.Hex)
 		if err != nil {
-			reportError("Failed to sign transaction: %v", err)
-			continue
+			return "", errContext(err, "invalid redeem script")
 		}
-		if !complete {
-			reportError("Failed to sign every input")
+	}
+	derivation, err := derivationInfo(info)
+	if err != nil {
+		return "", errContext(err, "invalid derivation path")
+	}
+
+	prevTxs := make(map[chainhash.Hash]*wire.MsgTx)
+	for i, txIn := range tx.Tx.TxIn {
+		in := &packet.Inputs[i]
+		prevScript := tx.PrevScripts[i]
+		prevOut := wire.NewTxOut(int64(tx.PrevInputValues[i]), prevScript)
+
+		if txscript.IsPayToTaproot(prevScript) {
+			in.WitnessUtxo = prevOut
+			in.SighashType = txscript.SigHashDefault
+			if derivation != nil {
+				in.Bip32Derivation = []*psbt.Bip32Derivation{
+					derivation,
+				}
+				in.TaprootBip32Derivation = []*psbt.TaprootBip32Derivation{{
+					XOnlyPubKey:          derivation.PubKey[1:],
+					MasterKeyFingerprint: derivation.MasterKeyFingerprint,
+					Bip32Path:            derivation.Bip32Path,
+				}}
+			}
 			continue
 		}
 
-		// Publish the signed sweep transaction.
-		txHash, err := rpcClient.SendRawTransaction(signedTransaction, false)
-		if err != nil {
-			reportError("Failed to publish transaction: %v", err)
-			continue
+		// The full previous transaction is included for segwit v0
+		// inputs too, as a fix for CVE-2020-14199.
+		hash := txIn.PreviousOutPoint.Hash
+		prevTx, ok := prevTxs[hash]
+		if !ok {
+			prevTx, err = fetchTransaction(rpcClient, &hash)
+			if err != nil {
+				return "", errContext(err, "failed to fetch "+
+					"previous transaction")
+			}
+			prevTxs[hash] = prevTx
+		}
+		in.NonWitnessUtxo = prevTx
+		in.SighashType = txscript.SigHashAll
+
+		if txscript.IsPayToScriptHash(prevScript) {
+			in.RedeemScript = redeemScript
 		}
+		if txscript.IsWitnessProgram(prevScript) ||
+			txscript.IsWitnessProgram(in.RedeemScript) {
 
-		outputAmount := btcutil.Amount(tx.Tx.TxOut[0].Value)
-		fmt.Printf("Swept %v to destination account with transaction %v\n",
-			outputAmount, txHash)
-		totalSwept += outputAmount
+			in.WitnessUtxo = prevOut
+		}
+		if derivation != nil {
+			in.Bip32Derivation = []*psbt.Bip32Derivation{derivation}
+		}
 	}
 
-	numPublished := len(sourceOutputs) - numErrors
-	transactionNoun := pickNoun(numErrors, "transaction", "transactions")
-	if numPublished != 0 {
-		fmt.Printf("Swept %v to destination account across %d %s\n",
-			totalSwept, numPublished, transactionNoun)
+	return packet.B64Encode()
+}
+
+// addressInfo looks up a wallet address with getaddressinfo.  The request is
+// made directly as btcjson's result omits the master key fingerprint.
+func addressInfo(rpcClient *rpcclient.Client,
+	address string) (*walletjson.GetAddressInfoResult, error) {
+
+	param, err := json.Marshal(address)
+	if err != nil {
+		return nil, err
 	}
-	if numErrors > 0 {
-		return fmt.Errorf("failed to publish %d %s", numErrors,
-			transactionNoun)
+	reply, err := rpcClient.RawRequest(
+		"getaddressinfo", []json.RawMessage{param},
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var info walletjson.GetAddressInfoResult
+	if err := json.Unmarshal(reply, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// derivationInfo returns the BIP 32 derivation of the key of an address, or
+// nil if the address isn't derived from the wallet's seed, like imported
+// keys.
+func derivationInfo(
+	info *walletjson.GetAddressInfoResult) (*psbt.Bip32Derivation, error) {
+
+	if info.PubKey == "" || info.HDKeyPath == "" ||
+		info.HDMasterFingerprint == "" {
+
+		return nil, nil
+	}
+
+	pubKey, err := hex.DecodeString(info.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := hex.DecodeString(info.HDMasterFingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if len(fingerprint) != 4 {
+		return nil, fmt.Errorf("invalid master key fingerprint %q",
+			info.HDMasterFingerprint)
+	}
+
+	var path []uint32
+	steps := strings.Split(info.HDKeyPath, "/")
+	if steps[0] != "m" {
+		return nil, fmt.Errorf("invalid key path %q", info.HDKeyPath)
+	}
+	for _, step := range steps[1:] {
+		var offset uint32
+		if strings.HasSuffix(step, "'") {
+			step = strings.TrimSuffix(step, "'")
+			offset = hdkeychain.HardenedKeyStart
+		}
+		index, err := strconv.ParseUint(step, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key path %q",
+				info.HDKeyPath)
+		}
+		path = append(path, uint32(index)+offset)
+	}
+
+	// The fingerprint is encoded in the byte order of PSBT derivation
+	// paths.
+	return &psbt.Bip32Derivation{
+		PubKey:               pubKey,
+		MasterKeyFingerprint: binary.LittleEndian.Uint32(fingerprint),
+		Bip32Path:            path,
+	}, nil
+}
+
+// fetchTransaction fetches a transaction of the wallet.
+func fetchTransaction(rpcClient *rpcclient.Client,
+	txHash *chainhash.Hash) (*wire.MsgTx, error) {
+
+	result, err := rpcClient.GetTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	serialized, err := hex.DecodeString(result.Hex)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(serialized)); err != nil {
+		return nil, err
+	}
+	return &tx, nil
 }
 
 func promptSecret(what string) (string, error) {
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitOutputs ensures that unspent outputs are split into the fewest
+// batches in order that each fit within the maximum transaction size.
+func TestSplitOutputs(t *testing.T) {
+	t.Parallel()
+
+	const (
+		p2wpkh = "0014" + "751e76e8199196d454941c45d1b3a323f1433bd6"
+		p2pkh  = "76a914" + "06afd46bcdfd22ef94ac122aa11f241244a37ecc" +
+			"88ac"
+
+		destinationScriptSize = txsizes.P2WPKHPkScriptSize
+	)
+
+	output := func(vout uint32, script string) btcjson.ListUnspentResult {
+		return btcjson.ListUnspentResult{
+			TxID:         strings.Repeat("00", 32),
+			Vout:         vout,
+			ScriptPubKey: script,
+		}
+	}
+	vouts := func(batches [][]btcjson.ListUnspentResult) [][]uint32 {
+		var all [][]uint32
+		for _, batch := range batches {
+			var vouts []uint32
+			for _, output := range batch {
+				vouts = append(vouts, output.Vout)
+			}
+			all = append(all, vouts)
+		}
+		return all
+	}
+
+	outputs := []btcjson.ListUnspentResult{
+		output(0, p2wpkh), output(1, p2wpkh), output(2, p2wpkh),
+		output(3, p2wpkh), output(4, p2wpkh),
+	}
+	twoInputs := txsizes.EstimateVirtualSize(
+		0, 0, 2, 0, nil, destinationScriptSize,
+	)
+	oneInput := txsizes.EstimateVirtualSize(
+		0, 0, 1, 0, nil, destinationScriptSize,
+	)
+	p2pkhInput := txsizes.EstimateVirtualSize(
+		1, 0, 0, 0, nil, destinationScriptSize,
+	)
+
+	tests := []struct {
+		name    string
+		outputs []btcjson.ListUnspentResult
+		maxSize int
+		batches [][]uint32
+	}{{
+		name:    "no limit",
+		outputs: outputs,
+		maxSize: 0,
+		batches: [][]uint32{{0, 1, 2, 3, 4}},
+	}, {
+		name:    "all fit",
+		outputs: outputs,
+		maxSize: txsizes.EstimateVirtualSize(
+			0, 0, 5, 0, nil, destinationScriptSize,
+		),
+		batches: [][]uint32{{0, 1, 2, 3, 4}},
+	}, {
+		name:    "two per batch",
+		outputs: outputs,
+		maxSize: twoInputs,
+		batches: [][]uint32{{0, 1}, {2, 3}, {4}},
+	}, {
+		name:    "one per batch",
+		outputs: outputs[:3],
+		maxSize: oneInput,
+		batches: [][]uint32{{0}, {1}, {2}},
+	}, {
+		// The larger P2PKH input doesn't fit with the P2WPKH one
+		// before it.
+		name: "mixed scripts",
+		outputs: []btcjson.ListUnspentResult{
+			output(0, p2wpkh), output(1, p2pkh), output(2, p2wpkh),
+		},
+		maxSize: p2pkhInput,
+		batches: [][]uint32{{0}, {1}, {2}},
+	}}
+
+	for _, test := range tests {
+		batches, err := splitOutputs(
+			test.outputs, destinationScriptSize, test.maxSize,
+		)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.batches, vouts(batches), test.name)
+
+		for _, batch := range batches {
+			var counts inputCounts
+			for _, output := range batch {
+				script, err := hex.DecodeString(
+					output.ScriptPubKey,
+				)
+				require.NoError(t, err)
+				counts.add(script)
+			}
+			if test.maxSize != 0 {
+				require.LessOrEqual(t,
+					counts.virtualSize(destinationScriptSize),
+					test.maxSize, test.name)
+			}
+		}
+	}
+
+	// An output which can't be swept on its own is an error.
+	_, err := splitOutputs(outputs, destinationScriptSize, oneInput-1)
+	require.Error(t, err)
+
+	// As is an invalid script.
+	_, err = splitOutputs(
+		[]btcjson.ListUnspentResult{output(0, "zz")},
+		destinationScriptSize, twoInputs,
+	)
+	require.Error(t, err)
+}
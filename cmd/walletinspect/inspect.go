@@ -0,0 +1,513 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+var (
+	waddrmgrNamespaceKey = []byte("waddrmgr")
+	wtxmgrNamespaceKey   = []byte("wtxmgr")
+
+	// bucketTxLabels is the name of the wtxmgr bucket holding the
+	// transaction labels.
+	bucketTxLabels = []byte("l")
+)
+
+// report is the inspection report of a wallet database.  Sections that weren't
+// requested are nil.
+type report struct {
+	Wallet       *walletReport  `json:"wallet,omitempty"`
+	Transactions []txReport     `json:"transactions"`
+	Locked       []lockedReport `json:"locked_outputs"`
+	Labels       []labelReport  `json:"labels"`
+}
+
+type walletReport struct {
+	WatchingOnly          bool          `json:"watching_only"`
+	SyncedTo              blockReport   `json:"synced_to"`
+	Birthday              time.Time     `json:"birthday"`
+	BirthdayBlock         *blockReport  `json:"birthday_block,omitempty"`
+	BirthdayBlockVerified bool          `json:"birthday_block_verified"`
+	Scopes                []scopeReport `json:"scopes"`
+}
+
+type blockReport struct {
+	Height int32      `json:"height"`
+	Hash   string     `json:"hash"`
+	Time   *time.Time `json:"time,omitempty"`
+}
+
+type scopeReport struct {
+	Scope    string          `json:"scope"`
+	Accounts []accountReport `json:"accounts"`
+}
+
+type accountReport struct {
+	Number            uint32 `json:"number"`
+	Name              string `json:"name"`
+	ExternalAddresses uint32 `json:"external_addresses"`
+	InternalAddresses uint32 `json:"internal_addresses"`
+	ImportedAddresses uint32 `json:"imported_addresses"`
+}
+
+type txReport struct {
+	TxID      string         `json:"txid"`
+	Height    int32          `json:"height"`
+	BlockHash string         `json:"block_hash,omitempty"`
+	BlockTime *time.Time     `json:"block_time,omitempty"`
+	Received  time.Time      `json:"received"`
+	Label     string         `json:"label,omitempty"`
+	Credits   []creditReport `json:"credits"`
+	Debits    []debitReport  `json:"debits"`
+}
+
+type creditReport struct {
+	Index   uint32      `json:"index"`
+	Amount  int64       `json:"amount_sat"`
+	Address string      `json:"address,omitempty"`
+	Account *accountRef `json:"account,omitempty"`
+	Change  bool        `json:"change"`
+	Spent   bool        `json:"spent"`
+}
+
+type debitReport struct {
+	Index   uint32      `json:"index"`
+	Amount  int64       `json:"amount_sat"`
+	PrevOut string      `json:"prev_out"`
+	Address string      `json:"address,omitempty"`
+	Account *accountRef `json:"account,omitempty"`
+}
+
+type accountRef struct {
+	Scope  string `json:"scope"`
+	Number uint32 `json:"number"`
+	Name   string `json:"name"`
+}
+
+type lockedReport struct {
+	Outpoint   string    `json:"outpoint"`
+	LockID     string    `json:"lock_id"`
+	Expiration time.Time `json:"expiration"`
+}
+
+type labelReport struct {
+	TxID  string `json:"txid"`
+	Label string `json:"label"`
+}
+
+// filter selects the records shown in the report.
+type filter struct {
+	account     string
+	startHeight int32
+	endHeight   int32
+	txid        *chainhash.Hash
+}
+
+// matchesAccount returns whether the account with the given number and name
+// passes the filter.
+func (f *filter) matchesAccount(number uint32, name string) bool {
+	return f.account == "" || f.account == name ||
+		f.account == strconv.FormatUint(uint64(number), 10)
+}
+
+// inspector reads the records of a wallet database for a report.
+type inspector struct {
+	addrmgrNs   walletdb.ReadBucket
+	txmgrNs     walletdb.ReadBucket
+	mgr         *waddrmgr.Manager
+	store       *wtxmgr.Store
+	chainParams *chaincfg.Params
+	filter      *filter
+
+	// accounts caches the account of each script looked up, nil if it
+	// doesn't belong to the wallet.
+	accounts map[string]*accountRef
+}
+
+// inspect reads the requested sections of the report from the database.
+func inspect(db walletdb.DB, chainParams *chaincfg.Params, pubPass []byte,
+	f *filter, sections map[string]bool) (*report, error) {
+
+	r := &report{}
+	err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		if addrmgrNs == nil || txmgrNs == nil {
+			return errors.New("missing wallet namespace")
+		}
+
+		mgr, err := waddrmgr.Open(addrmgrNs, pubPass, chainParams)
+		if err != nil {
+			return err
+		}
+		defer mgr.Close()
+
+		store, err := wtxmgr.Open(txmgrNs, chainParams)
+		if err != nil {
+			return err
+		}
+
+		in := &inspector{
+			addrmgrNs:   addrmgrNs,
+			txmgrNs:     txmgrNs,
+			mgr:         mgr,
+			store:       store,
+			chainParams: chainParams,
+			filter:      f,
+			accounts:    make(map[string]*accountRef),
+		}
+
+		if sections[sectionWallet] {
+			r.Wallet, err = in.wallet()
+			if err != nil {
+				return err
+			}
+		}
+		if sections[sectionTransactions] {
+			r.Transactions, err = in.transactions()
+			if err != nil {
+				return err
+			}
+		}
+		if sections[sectionLocked] {
+			r.Locked, err = in.lockedOutputs()
+			if err != nil {
+				return err
+			}
+		}
+		if sections[sectionLabels] {
+			r.Labels, err = in.labels()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return r, err
+}
+
+func (in *inspector) wallet() (*walletReport, error) {
+	syncedTo := in.mgr.SyncedTo()
+	w := &walletReport{
+		WatchingOnly: in.mgr.WatchOnly(),
+		SyncedTo:     makeBlockReport(&syncedTo),
+		Birthday:     in.mgr.Birthday(),
+		Scopes:       []scopeReport{},
+	}
+
+	birthdayBlock, verified, err := in.mgr.BirthdayBlock(in.addrmgrNs)
+	switch {
+	case err == nil:
+		block := makeBlockReport(&birthdayBlock)
+		w.BirthdayBlock = &block
+		w.BirthdayBlockVerified = verified
+
+	case !waddrmgr.IsError(err, waddrmgr.ErrBirthdayBlockNotSet):
+		return nil, err
+	}
+
+	for _, scopedMgr := range sortedScopes(in.mgr) {
+		scope := scopeReport{
+			Scope:    scopedMgr.Scope().String(),
+			Accounts: []accountReport{},
+		}
+
+		lastAccount, err := scopedMgr.LastAccount(in.addrmgrNs)
+		if err != nil {
+			return nil, err
+		}
+		accounts := make([]uint32, 0, lastAccount+2)
+		for account := uint32(0); account <= lastAccount; account++ {
+			accounts = append(accounts, account)
+		}
+		accounts = append(accounts, waddrmgr.ImportedAddrAccount)
+
+		for _, account := range accounts {
+			props, err := scopedMgr.AccountProperties(
+				in.addrmgrNs, account,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if !in.filter.matchesAccount(account, props.AccountName) {
+				continue
+			}
+
+			scope.Accounts = append(scope.Accounts, accountReport{
+				Number:            account,
+				Name:              props.AccountName,
+				ExternalAddresses: props.ExternalKeyCount,
+				InternalAddresses: props.InternalKeyCount,
+				ImportedAddresses: props.ImportedKeyCount,
+			})
+		}
+
+		w.Scopes = append(w.Scopes, scope)
+	}
+
+	return w, nil
+}
+
+func (in *inspector) transactions() ([]txReport, error) {
+	txs := []txReport{}
+	add := func(details *wtxmgr.TxDetails) error {
+		tx, err := in.transaction(details)
+		if err != nil {
+			return err
+		}
+		if tx != nil {
+			txs = append(txs, *tx)
+		}
+		return nil
+	}
+
+	if in.filter.txid != nil {
+		details, err := in.store.TxDetails(in.txmgrNs, in.filter.txid)
+		if err != nil || details == nil {
+			return txs, err
+		}
+		return txs, add(details)
+	}
+
+	err := in.store.RangeTransactions(
+		in.txmgrNs, in.filter.startHeight, in.filter.endHeight,
+		func(details []wtxmgr.TxDetails) (bool, error) {
+			for i := range details {
+				if err := add(&details[i]); err != nil {
+					return false, err
+				}
+			}
+			return false, nil
+		},
+	)
+
+	return txs, err
+}
+
+// transaction returns the report of a transaction, or nil if none of its
+// credits and debits belong to the account filtered for.
+func (in *inspector) transaction(details *wtxmgr.TxDetails) (*txReport,
+	error) {
+
+	tx := &txReport{
+		TxID:     details.Hash.String(),
+		Height:   details.Block.Height,
+		Received: details.Received,
+		Label:    details.Label,
+		Credits:  []creditReport{},
+		Debits:   []debitReport{},
+	}
+	if details.Block.Height != -1 {
+		blockTime := details.Block.Time
+		tx.BlockHash = details.Block.Hash.String()
+		tx.BlockTime = &blockTime
+	}
+
+	matches := in.filter.account == ""
+	matchAccount := func(account *accountRef) {
+		if account != nil &&
+			in.filter.matchesAccount(account.Number, account.Name) {
+
+			matches = true
+		}
+	}
+
+	for _, credit := range details.Credits {
+		pkScript := details.MsgTx.TxOut[credit.Index].PkScript
+		address, account, err := in.scriptAccount(pkScript)
+		if err != nil {
+			return nil, err
+		}
+		matchAccount(account)
+
+		tx.Credits = append(tx.Credits, creditReport{
+			Index:   credit.Index,
+			Amount:  int64(credit.Amount),
+			Address: address,
+			Account: account,
+			Change:  credit.Change,
+			Spent:   credit.Spent,
+		})
+	}
+
+	for _, debit := range details.Debits {
+		prevOut := details.MsgTx.TxIn[debit.Index].PreviousOutPoint
+		address, account, err := in.prevOutAccount(&prevOut)
+		if err != nil {
+			return nil, err
+		}
+		matchAccount(account)
+
+		tx.Debits = append(tx.Debits, debitReport{
+			Index:   debit.Index,
+			Amount:  int64(debit.Amount),
+			PrevOut: prevOut.String(),
+			Address: address,
+			Account: account,
+		})
+	}
+
+	if !matches {
+		return nil, nil
+	}
+	return tx, nil
+}
+
+// prevOutAccount returns the address and account of the output spent by a
+// debit.
+func (in *inspector) prevOutAccount(prevOut *wire.OutPoint) (string,
+	*accountRef, error) {
+
+	prev, err := in.store.TxDetails(in.txmgrNs, &prevOut.Hash)
+	if err != nil {
+		return "", nil, err
+	}
+	if prev == nil || int(prevOut.Index) >= len(prev.MsgTx.TxOut) {
+		return "", nil, nil
+	}
+
+	return in.scriptAccount(prev.MsgTx.TxOut[prevOut.Index].PkScript)
+}
+
+// scriptAccount returns the address of an output script, and the account it
+// belongs to if it's one of the wallet's.
+func (in *inspector) scriptAccount(pkScript []byte) (string, *accountRef,
+	error) {
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+		pkScript, in.chainParams,
+	)
+	if err != nil || len(addrs) == 0 {
+		return "", nil, nil
+	}
+	address := addrs[0].EncodeAddress()
+
+	if account, ok := in.accounts[address]; ok {
+		return address, account, nil
+	}
+
+	scopedMgr, number, err := in.mgr.AddrAccount(in.addrmgrNs, addrs[0])
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound):
+		in.accounts[address] = nil
+		return address, nil, nil
+
+	case err != nil:
+		return "", nil, err
+	}
+
+	name, err := scopedMgr.AccountName(in.addrmgrNs, number)
+	if err != nil {
+		return "", nil, err
+	}
+	account := &accountRef{
+		Scope:  scopedMgr.Scope().String(),
+		Number: number,
+		Name:   name,
+	}
+	in.accounts[address] = account
+
+	return address, account, nil
+}
+
+func (in *inspector) lockedOutputs() ([]lockedReport, error) {
+	outputs, err := in.store.ListLockedOutputs(in.txmgrNs)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := []lockedReport{}
+	for _, output := range outputs {
+		if in.filter.txid != nil &&
+			output.Outpoint.Hash != *in.filter.txid {
+
+			continue
+		}
+
+		locked = append(locked, lockedReport{
+			Outpoint:   output.Outpoint.String(),
+			LockID:     hex.EncodeToString(output.LockID[:]),
+			Expiration: output.Expiration,
+		})
+	}
+
+	return locked, nil
+}
+
+func (in *inspector) labels() ([]labelReport, error) {
+	labels := []labelReport{}
+	bucket := in.txmgrNs.NestedReadBucket(bucketTxLabels)
+	if bucket == nil {
+		return labels, nil
+	}
+
+	err := bucket.ForEach(func(k, v []byte) error {
+		txid, err := chainhash.NewHash(k)
+		if err != nil {
+			return err
+		}
+		if in.filter.txid != nil && *txid != *in.filter.txid {
+			return nil
+		}
+
+		label, err := wtxmgr.DeserializeLabel(v)
+		if err != nil {
+			return err
+		}
+		labels = append(labels, labelReport{
+			TxID:  txid.String(),
+			Label: label,
+		})
+		return nil
+	})
+
+	return labels, err
+}
+
+func makeBlockReport(bs *waddrmgr.BlockStamp) blockReport {
+	block := blockReport{
+		Height: bs.Height,
+		Hash:   bs.Hash.String(),
+	}
+	if !bs.Timestamp.IsZero() {
+		timestamp := bs.Timestamp
+		block.Time = &timestamp
+	}
+	return block
+}
+
+// sortedScopes returns the active scoped managers ordered by their scope.
+func sortedScopes(mgr *waddrmgr.Manager) []*waddrmgr.ScopedKeyManager {
+	scopes := mgr.ActiveScopedKeyManagers()
+	for i := 1; i < len(scopes); i++ {
+		for j := i; j > 0 && scopeLess(scopes[j], scopes[j-1]); j-- {
+			scopes[j], scopes[j-1] = scopes[j-1], scopes[j]
+		}
+	}
+	return scopes
+}
+
+func scopeLess(a, b *waddrmgr.ScopedKeyManager) bool {
+	sa, sb := a.Scope(), b.Scope()
+	if sa.Purpose != sb.Purpose {
+		return sa.Purpose < sb.Purpose
+	}
+	return sa.Coin < sb.Coin
+}
@@ -0,0 +1,462 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPubPass  = []byte(wallet.InsecurePubPassphrase)
+	testPrivPass = []byte("private")
+
+	testParams = &chaincfg.TestNet3Params
+)
+
+// testWallet holds the transactions of the fixture wallet created by
+// newTestWallet:
+//   - tx1, mined at height 100, pays 10 BTC to the default account;
+//   - tx2, mined at height 200, pays 5 BTC to the savings account, is
+//     labelled and has its output locked;
+//   - tx3, unmined, spends the output of tx1 to an external address.
+type testWallet struct {
+	db                       walletdb.DB
+	savings                  uint32
+	tx1, tx2, tx3            *wtxmgr.TxRecord
+	defaultAddr, savingsAddr btcutil.Address
+}
+
+// newTestWallet creates the fixture wallet in a temporary directory and
+// returns it opened the way walletinspect opens wallets.
+func newTestWallet(t *testing.T) *testWallet {
+	t.Helper()
+
+	// Derive the passphrase keys quickly, as their strength doesn't
+	// matter here.
+	oldKeyGen := waddrmgr.SetSecretKeyGen(fastSecretKeyGen)
+	t.Cleanup(func() { waddrmgr.SetSecretKeyGen(oldKeyGen) })
+
+	dir := t.TempDir()
+	loader := wallet.NewLoader(testParams, dir, true,
+		wallet.DefaultDBTimeout, 250)
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	require.NoError(t, err)
+	w, err := loader.CreateNewWallet(
+		testPubPass, testPrivPass, seed, time.Now(),
+	)
+	require.NoError(t, err)
+
+	tw := &testWallet{}
+	err = walletdb.Update(w.Database(), func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		err := w.Manager.Unlock(addrmgrNs, testPrivPass)
+		if err != nil {
+			return err
+		}
+		scopedMgr, err := w.Manager.FetchScopedKeyManager(
+			waddrmgr.KeyScopeBIP0084,
+		)
+		if err != nil {
+			return err
+		}
+		tw.savings, err = scopedMgr.NewAccount(addrmgrNs, "savings")
+		if err != nil {
+			return err
+		}
+
+		addrs, err := scopedMgr.NextExternalAddresses(
+			addrmgrNs, waddrmgr.DefaultAccountNum, 1,
+		)
+		if err != nil {
+			return err
+		}
+		tw.defaultAddr = addrs[0].Address()
+		addrs, err = scopedMgr.NextExternalAddresses(
+			addrmgrNs, tw.savings, 1,
+		)
+		if err != nil {
+			return err
+		}
+		tw.savingsAddr = addrs[0].Address()
+
+		external, err := btcutil.NewAddressWitnessPubKeyHash(
+			make([]byte, 20), testParams,
+		)
+		if err != nil {
+			return err
+		}
+
+		tw.tx1 = newTestTx(t, wire.OutPoint{Hash: chainhash.Hash{1}},
+			tw.defaultAddr, 10e8)
+		tw.tx2 = newTestTx(t, wire.OutPoint{Hash: chainhash.Hash{2}},
+			tw.savingsAddr, 5e8)
+		tw.tx3 = newTestTx(t, wire.OutPoint{Hash: tw.tx1.Hash},
+			external, 9e8)
+
+		for _, mined := range []struct {
+			rec    *wtxmgr.TxRecord
+			height int32
+		}{{tw.tx1, 100}, {tw.tx2, 200}} {
+			block := &wtxmgr.BlockMeta{
+				Block: wtxmgr.Block{
+					Hash:   chainhash.Hash{byte(mined.height)},
+					Height: mined.height,
+				},
+				Time: time.Unix(1700000000+int64(mined.height), 0),
+			}
+			err := w.TxStore.InsertTx(txmgrNs, mined.rec, block)
+			if err != nil {
+				return err
+			}
+			err = w.TxStore.AddCredit(
+				txmgrNs, mined.rec, block, 0, false,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		if err := w.TxStore.InsertTx(txmgrNs, tw.tx3, nil); err != nil {
+			return err
+		}
+
+		err = w.TxStore.PutTxLabel(txmgrNs, tw.tx2.Hash, "deposit")
+		if err != nil {
+			return err
+		}
+		_, err = w.TxStore.LockOutput(
+			txmgrNs, wtxmgr.LockID{1},
+			wire.OutPoint{Hash: tw.tx2.Hash}, time.Hour,
+		)
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+
+	opts.DbPath = filepath.Join(dir, wallet.WalletDBName)
+	tw.db, err = openDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { tw.db.Close() })
+
+	return tw
+}
+
+// fastSecretKeyGen derives passphrase keys with the fast scrypt options.
+func fastSecretKeyGen(passphrase *[]byte,
+	_ *waddrmgr.KDFOptions) (*snacl.SecretKey, error) {
+
+	scrypt := waddrmgr.FastScryptOptions
+	return snacl.NewSecretKey(passphrase, scrypt.N, scrypt.R, scrypt.P)
+}
+
+// newTestTx returns a transaction record spending the outpoint to a single
+// output paying the address.
+func newTestTx(t *testing.T, prevOut wire.OutPoint, addr btcutil.Address,
+	amount int64) *wtxmgr.TxRecord {
+
+	t.Helper()
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	msgTx.AddTxIn(wire.NewTxIn(&prevOut, nil, nil))
+	msgTx.AddTxOut(wire.NewTxOut(amount, pkScript))
+
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	return rec
+}
+
+// reportTxIDs returns the transaction hashes of a report, in order.
+func reportTxIDs(r *report) []string {
+	txids := []string{}
+	for _, tx := range r.Transactions {
+		txids = append(txids, tx.TxID)
+	}
+	return txids
+}
+
+// TestInspectFilters ensures that the account, height range and transaction
+// filters select the expected records of the fixture wallet.
+func TestInspectFilters(t *testing.T) {
+	tw := newTestWallet(t)
+
+	tx1 := tw.tx1.Hash.String()
+	tx2 := tw.tx2.Hash.String()
+	tx3 := tw.tx3.Hash.String()
+	unknown := chainhash.Hash{0xff}
+
+	tests := []struct {
+		name     string
+		filter   filter
+		txids    []string
+		accounts []string
+		locked   int
+		labels   int
+	}{{
+		name:     "no filter",
+		filter:   filter{endHeight: -1},
+		txids:    []string{tx1, tx2, tx3},
+		accounts: []string{"default", "savings", "imported"},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "account name",
+		filter:   filter{account: "savings", endHeight: -1},
+		txids:    []string{tx2},
+		accounts: []string{"savings"},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "account number",
+		filter:   filter{account: "0", endHeight: -1},
+		txids:    []string{tx1, tx3},
+		accounts: []string{"default"},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "unknown account",
+		filter:   filter{account: "nope", endHeight: -1},
+		txids:    []string{},
+		accounts: []string{},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "mined only",
+		filter:   filter{endHeight: 1000},
+		txids:    []string{tx1, tx2},
+		accounts: []string{"default", "savings", "imported"},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "height range",
+		filter:   filter{startHeight: 150, endHeight: 250},
+		txids:    []string{tx2},
+		accounts: []string{"default", "savings", "imported"},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "txid",
+		filter:   filter{txid: &tw.tx2.Hash, endHeight: -1},
+		txids:    []string{tx2},
+		accounts: []string{"default", "savings", "imported"},
+		locked:   1,
+		labels:   1,
+	}, {
+		name:     "unmined txid",
+		filter:   filter{txid: &tw.tx3.Hash, endHeight: -1},
+		txids:    []string{tx3},
+		accounts: []string{"default", "savings", "imported"},
+		locked:   0,
+		labels:   0,
+	}, {
+		name:     "unknown txid",
+		filter:   filter{txid: &unknown, endHeight: -1},
+		txids:    []string{},
+		accounts: []string{"default", "savings", "imported"},
+		locked:   0,
+		labels:   0,
+	}}
+
+	allSections := map[string]bool{
+		sectionWallet:       true,
+		sectionTransactions: true,
+		sectionLocked:       true,
+		sectionLabels:       true,
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			r, err := inspect(
+				tw.db, testParams, testPubPass, &test.filter,
+				allSections,
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, test.txids, reportTxIDs(r))
+			require.Len(t, r.Locked, test.locked)
+			require.Len(t, r.Labels, test.labels)
+
+			// Only the BIP 84 scope holds the savings account, the
+			// other scopes list their default and imported
+			// accounts.
+			for _, scope := range r.Wallet.Scopes {
+				if scope.Scope != waddrmgr.KeyScopeBIP0084.String() {
+					continue
+				}
+				names := []string{}
+				for _, account := range scope.Accounts {
+					names = append(names, account.Name)
+				}
+				require.Equal(t, test.accounts, names)
+			}
+		})
+	}
+}
+
+// TestInspectTransaction ensures that the credits and debits of the
+// transactions are reported with their addresses and accounts.
+func TestInspectTransaction(t *testing.T) {
+	tw := newTestWallet(t)
+
+	r, err := inspect(
+		tw.db, testParams, testPubPass, &filter{endHeight: -1},
+		map[string]bool{sectionTransactions: true},
+	)
+	require.NoError(t, err)
+	require.Nil(t, r.Wallet)
+	require.Nil(t, r.Locked)
+	require.Nil(t, r.Labels)
+	require.Len(t, r.Transactions, 3)
+
+	scope := waddrmgr.KeyScopeBIP0084.String()
+
+	tx2 := r.Transactions[1]
+	require.Equal(t, int32(200), tx2.Height)
+	require.Equal(t, "deposit", tx2.Label)
+	require.NotNil(t, tx2.BlockTime)
+	require.Equal(t, []creditReport{{
+		Index:   0,
+		Amount:  5e8,
+		Address: tw.savingsAddr.EncodeAddress(),
+		Account: &accountRef{
+			Scope:  scope,
+			Number: tw.savings,
+			Name:   "savings",
+		},
+	}}, tx2.Credits)
+	require.Empty(t, tx2.Debits)
+
+	tx3 := r.Transactions[2]
+	require.Equal(t, int32(-1), tx3.Height)
+	require.Empty(t, tx3.BlockHash)
+	require.Nil(t, tx3.BlockTime)
+	require.Empty(t, tx3.Credits)
+	require.Equal(t, []debitReport{{
+		Index:   0,
+		Amount:  10e8,
+		PrevOut: wire.OutPoint{Hash: tw.tx1.Hash}.String(),
+		Address: tw.defaultAddr.EncodeAddress(),
+		Account: &accountRef{
+			Scope:  scope,
+			Number: waddrmgr.DefaultAccountNum,
+			Name:   "default",
+		},
+	}}, tx3.Debits)
+
+	// The spend of the first transaction marks its credit spent.
+	require.True(t, r.Transactions[0].Credits[0].Spent)
+}
+
+// TestWriteReport ensures that the tables and JSON encoding of a report only
+// hold the requested sections, with their records.
+func TestWriteReport(t *testing.T) {
+	tw := newTestWallet(t)
+
+	sections := map[string]bool{
+		sectionTransactions: true,
+		sectionLabels:       true,
+	}
+	r, err := inspect(
+		tw.db, testParams, testPubPass, &filter{endHeight: -1},
+		sections,
+	)
+	require.NoError(t, err)
+
+	var tables bytes.Buffer
+	require.NoError(t, writeTables(&tables, r))
+	out := tables.String()
+
+	require.Contains(t, out, "Transactions:")
+	require.Contains(t, out, "Credits:")
+	require.Contains(t, out, "Debits:")
+	require.Contains(t, out, "Labels:")
+	require.NotContains(t, out, "Accounts:")
+	require.NotContains(t, out, "Locked outputs:")
+
+	lines := strings.Split(out, "\n")
+	findLine := func(prefix string) string {
+		t.Helper()
+		for _, line := range lines {
+			if strings.HasPrefix(line, prefix) {
+				return line
+			}
+		}
+		t.Fatalf("no line starting with %q in\n%s", prefix, out)
+		return ""
+	}
+
+	tx2 := tw.tx2.Hash.String()
+	require.Regexp(t, `^\S+\s+200\s+\S+\s+5 BTC\s+0 BTC\s+deposit\s*$`,
+		findLine(tx2+" "))
+	require.Regexp(t, `^\S+\s+unmined\s+`,
+		findLine(tw.tx3.Hash.String()+" "))
+	require.Contains(t, findLine(tx2+":0"), fmt.Sprintf("%s/%d (savings)",
+		waddrmgr.KeyScopeBIP0084.String(), tw.savings))
+	require.Contains(t, findLine(tw.tx3.Hash.String()+":0"),
+		tw.defaultAddr.EncodeAddress())
+
+	encoded, err := json.Marshal(r)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Wallet       *json.RawMessage `json:"wallet"`
+		Transactions []struct {
+			TxID    string `json:"txid"`
+			Height  int32  `json:"height"`
+			Label   string `json:"label"`
+			Credits []struct {
+				Amount  int64  `json:"amount_sat"`
+				Address string `json:"address"`
+				Account struct {
+					Name string `json:"name"`
+				} `json:"account"`
+			} `json:"credits"`
+		} `json:"transactions"`
+		Locked []json.RawMessage `json:"locked_outputs"`
+		Labels []struct {
+			TxID  string `json:"txid"`
+			Label string `json:"label"`
+		} `json:"labels"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	require.Nil(t, decoded.Wallet)
+	require.Nil(t, decoded.Locked)
+	require.Len(t, decoded.Transactions, 3)
+	require.Equal(t, tx2, decoded.Transactions[1].TxID)
+	require.Equal(t, int32(200), decoded.Transactions[1].Height)
+	require.Equal(t, "deposit", decoded.Transactions[1].Label)
+	require.Len(t, decoded.Transactions[1].Credits, 1)
+	credit := decoded.Transactions[1].Credits[0]
+	require.Equal(t, int64(5e8), credit.Amount)
+	require.Equal(t, tw.savingsAddr.EncodeAddress(), credit.Address)
+	require.Equal(t, "savings", credit.Account.Name)
+	require.Equal(t, int32(-1), decoded.Transactions[2].Height)
+	require.Len(t, decoded.Labels, 1)
+	require.Equal(t, tx2, decoded.Labels[0].TxID)
+	require.Equal(t, "deposit", decoded.Labels[0].Label)
+}
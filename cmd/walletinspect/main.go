@@ -0,0 +1,171 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/encdb"
+	"github.com/btcsuite/btcwallet/netparams"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/jessevdk/go-flags"
+)
+
+const defaultNet = "mainnet"
+
+// The sections of the report.
+const (
+	sectionWallet       = "wallet"
+	sectionTransactions = "transactions"
+	sectionLocked       = "locked"
+	sectionLabels       = "labels"
+)
+
+var (
+	datadir = btcutil.AppDataDir("btcwallet", false)
+)
+
+// Flags.
+var opts = struct {
+	DbPath      string        `long:"db" description:"Path to wallet database"`
+	Net         string        `long:"net" description:"Network of the wallet (mainnet, testnet3, testnet4, regtest, simnet or signet)"`
+	WalletPass  string        `long:"walletpass" default-mask:"-" description:"The public wallet password"`
+	Timeout     time.Duration `long:"timeout" description:"Timeout value when opening the wallet database"`
+	JSON        bool          `long:"json" description:"Write the report as JSON instead of tables"`
+	Sections    []string      `long:"section" choice:"wallet" choice:"transactions" choice:"locked" choice:"labels" description:"Only show the given sections of the report, may be repeated"`
+	Account     string        `long:"account" description:"Only show the accounts and transactions of the account with this name or number"`
+	StartHeight int32         `long:"startheight" description:"Only show transactions mined at or after this height"`
+	EndHeight   int32         `long:"endheight" description:"Only show transactions mined at or before this height, -1 to include unmined transactions"`
+	TxID        string        `long:"txid" description:"Only show the transaction, locked outputs and label of the transaction with this hash"`
+}{
+	DbPath:     filepath.Join(datadir, defaultNet, wallet.WalletDBName),
+	Net:        defaultNet,
+	WalletPass: wallet.InsecurePubPassphrase,
+	Timeout:    wallet.DefaultDBTimeout,
+	EndHeight:  -1,
+}
+
+var networks = []*netparams.Params{
+	&netparams.MainNetParams,
+	&netparams.TestNet3Params,
+	&netparams.TestNet4Params,
+	&netparams.RegressionNetParams,
+	&netparams.SimNetParams,
+	&netparams.SigNetParams,
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+func mainInt() int {
+	if _, err := flags.Parse(&opts); err != nil {
+		return 1
+	}
+
+	var chainParams *chaincfg.Params
+	for _, params := range networks {
+		if params.Name == opts.Net {
+			chainParams = params.Params
+		}
+	}
+	if chainParams == nil {
+		fmt.Fprintln(os.Stderr, "Unknown network:", opts.Net)
+		return 1
+	}
+
+	f := &filter{
+		account:     opts.Account,
+		startHeight: opts.StartHeight,
+		endHeight:   opts.EndHeight,
+	}
+	if opts.TxID != "" {
+		hash, err := chainhash.NewHashFromStr(opts.TxID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid transaction hash:", err)
+			return 1
+		}
+		f.txid = hash
+	}
+
+	sections := make(map[string]bool)
+	for _, section := range opts.Sections {
+		sections[section] = true
+	}
+	if len(sections) == 0 {
+		sections = map[string]bool{
+			sectionWallet:       true,
+			sectionTransactions: true,
+			sectionLocked:       true,
+			sectionLabels:       true,
+		}
+	}
+
+	if _, err := os.Stat(opts.DbPath); os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "Database file does not exist:",
+			opts.DbPath)
+		return 1
+	}
+	db, err := openDB()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	r, err := inspect(db, chainParams, []byte(opts.WalletPass), f, sections)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to inspect database:", err)
+		return 1
+	}
+
+	if opts.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r)
+	} else {
+		err = writeTables(os.Stdout, r)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to write report:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// openDB opens the database, decrypting it if it's encrypted.  The database is
+// only read from.
+func openDB() (walletdb.DB, error) {
+	db, err := walletdb.Open("bdb", opts.DbPath, true, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encdb.IsEncrypted(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !encrypted {
+		return db, nil
+	}
+
+	encDB, err := encdb.Open(db, []byte(opts.WalletPass))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return encDB, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// writeTables writes the requested sections of the report as tables.
+func writeTables(w io.Writer, r *report) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	if r.Wallet != nil {
+		writeWallet(tw, r.Wallet)
+	}
+	if r.Transactions != nil {
+		writeTransactions(tw, r.Transactions)
+	}
+	if r.Locked != nil {
+		fmt.Fprintln(tw, "Locked outputs:")
+		fmt.Fprintln(tw, "OUTPOINT\tLOCK ID\tEXPIRATION")
+		for _, locked := range r.Locked {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", locked.Outpoint,
+				locked.LockID, formatTime(locked.Expiration))
+		}
+		fmt.Fprintln(tw)
+	}
+	if r.Labels != nil {
+		fmt.Fprintln(tw, "Labels:")
+		fmt.Fprintln(tw, "TXID\tLABEL")
+		for _, label := range r.Labels {
+			fmt.Fprintf(tw, "%s\t%s\n", label.TxID, label.Label)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+func writeWallet(tw *tabwriter.Writer, w *walletReport) {
+	fmt.Fprintf(tw, "Synced to:\t%s\n", formatBlock(&w.SyncedTo))
+	fmt.Fprintf(tw, "Birthday:\t%s\n", formatTime(w.Birthday))
+	if w.BirthdayBlock != nil {
+		verified := "unverified"
+		if w.BirthdayBlockVerified {
+			verified = "verified"
+		}
+		fmt.Fprintf(tw, "Birthday block:\t%s, %s\n",
+			formatBlock(w.BirthdayBlock), verified)
+	} else {
+		fmt.Fprintln(tw, "Birthday block:\tnot set")
+	}
+	fmt.Fprintf(tw, "Watching only:\t%t\n", w.WatchingOnly)
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "Accounts:")
+	fmt.Fprintln(tw, "SCOPE\tNUMBER\tNAME\tEXTERNAL\tINTERNAL\tIMPORTED")
+	for _, scope := range w.Scopes {
+		for _, account := range scope.Accounts {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\t%d\n",
+				scope.Scope, account.Number, account.Name,
+				account.ExternalAddresses,
+				account.InternalAddresses,
+				account.ImportedAddresses)
+		}
+	}
+	fmt.Fprintln(tw)
+}
+
+func writeTransactions(tw *tabwriter.Writer, txs []txReport) {
+	fmt.Fprintln(tw, "Transactions:")
+	fmt.Fprintln(tw, "TXID\tHEIGHT\tRECEIVED\tCREDITS\tDEBITS\tLABEL")
+	for _, tx := range txs {
+		var credits, debits btcutil.Amount
+		for _, credit := range tx.Credits {
+			credits += btcutil.Amount(credit.Amount)
+		}
+		for _, debit := range tx.Debits {
+			debits += btcutil.Amount(debit.Amount)
+		}
+
+		height := "unmined"
+		if tx.Height != -1 {
+			height = fmt.Sprint(tx.Height)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%v\t%s\n", tx.TxID, height,
+			formatTime(tx.Received), credits, debits, tx.Label)
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "Credits:")
+	fmt.Fprintln(tw, "OUTPUT\tAMOUNT\tADDRESS\tACCOUNT\tCHANGE\tSPENT")
+	for _, tx := range txs {
+		for _, credit := range tx.Credits {
+			fmt.Fprintf(tw, "%s:%d\t%v\t%s\t%s\t%t\t%t\n", tx.TxID,
+				credit.Index, btcutil.Amount(credit.Amount),
+				credit.Address, formatAccount(credit.Account),
+				credit.Change, credit.Spent)
+		}
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "Debits:")
+	fmt.Fprintln(tw, "INPUT\tAMOUNT\tPREVIOUS OUTPUT\tADDRESS\tACCOUNT")
+	for _, tx := range txs {
+		for _, debit := range tx.Debits {
+			fmt.Fprintf(tw, "%s:%d\t%v\t%s\t%s\t%s\n", tx.TxID,
+				debit.Index, btcutil.Amount(debit.Amount),
+				debit.PrevOut, debit.Address,
+				formatAccount(debit.Account))
+		}
+	}
+	fmt.Fprintln(tw)
+}
+
+func formatBlock(block *blockReport) string {
+	s := fmt.Sprintf("%s (height %d", block.Hash, block.Height)
+	if block.Time != nil {
+		s += ", " + formatTime(*block.Time)
+	}
+	return s + ")"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatAccount(account *accountRef) string {
+	if account == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s/%d (%s)", account.Scope, account.Number,
+		account.Name)
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/netparams"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/jessevdk/go-flags"
+)
+
+const defaultNet = "mainnet"
+
+var (
+	datadir = btcutil.AppDataDir("btcwallet", false)
+)
+
+// Flags.
+var opts = struct {
+	TestNet3   bool          `long:"testnet" description:"Use the test bitcoin network (version 3)"`
+	TestNet4   bool          `long:"testnet4" description:"Use the test bitcoin network (version 4)"`
+	RegTest    bool          `long:"regtest" description:"Use the regression test network"`
+	SimNet     bool          `long:"simnet" description:"Use the simulation bitcoin network"`
+	SigNet     bool          `long:"signet" description:"Use the signet bitcoin network"`
+	DbPath     string        `long:"db" description:"Path to wallet database"`
+	WalletPass string        `long:"walletpass" default-mask:"-" description:"The public wallet password"`
+	Export     string        `long:"export" description:"Write all labels as BIP-0329 JSON lines to this file (- for stdout)"`
+	Import     string        `long:"import" description:"Read BIP-0329 JSON lines from this file (- for stdin) and apply them to the wallet"`
+	Overwrite  bool          `long:"overwrite" description:"Apply imported labels that conflict with existing labels"`
+	Timeout    time.Duration `long:"timeout" description:"Timeout value when opening the wallet database"`
+}{
+	WalletPass: wallet.InsecurePubPassphrase,
+	Timeout:    wallet.DefaultDBTimeout,
+}
+
+var activeNet = &netparams.MainNetParams
+
+func init() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	numNets := 0
+	netName := defaultNet
+	if opts.TestNet3 {
+		activeNet = &netparams.TestNet3Params
+		netName = "testnet3"
+		numNets++
+	}
+	if opts.TestNet4 {
+		activeNet = &netparams.TestNet4Params
+		netName = "testnet4"
+		numNets++
+	}
+	if opts.RegTest {
+		activeNet = &netparams.RegressionNetParams
+		netName = "regtest"
+		numNets++
+	}
+	if opts.SimNet {
+		activeNet = &netparams.SimNetParams
+		netName = "simnet"
+		numNets++
+	}
+	if opts.SigNet {
+		activeNet = &netparams.SigNetParams
+		netName = "signet"
+		numNets++
+	}
+	if numNets > 1 {
+		fmt.Fprintln(os.Stderr, "Multiple bitcoin networks may not "+
+			"be used simultaneously")
+		os.Exit(1)
+	}
+
+	if opts.DbPath == "" {
+		opts.DbPath = filepath.Join(datadir, netName, wallet.WalletDBName)
+	}
+
+	if (opts.Export == "") == (opts.Import == "") {
+		fmt.Fprintln(os.Stderr, "Exactly one of --export or --import "+
+			"must be specified")
+		os.Exit(1)
+	}
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+func mainInt() int {
+	fmt.Fprintln(os.Stderr, "Database path:", opts.DbPath)
+	_, err := os.Stat(opts.DbPath)
+	if os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "Database file does not exist")
+		return 1
+	}
+
+	db, err := walletdb.Open("bdb", opts.DbPath, true, opts.Timeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	w, err := wallet.Open(
+		db, []byte(opts.WalletPass), nil, activeNet.Params, 0,
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open wallet:", err)
+		return 1
+	}
+	defer w.Manager.Close()
+
+	if opts.Export != "" {
+		return exportLabels(w)
+	}
+	return importLabels(w)
+}
+
+func exportLabels(w *wallet.Wallet) int {
+	var out io.Writer = os.Stdout
+	if opts.Export != "-" {
+		f, err := os.Create(opts.Export)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create export file:",
+				err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := w.ExportLabels(out); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to export labels:", err)
+		return 1
+	}
+
+	return 0
+}
+
+func importLabels(w *wallet.Wallet) int {
+	var in io.Reader = os.Stdin
+	if opts.Import != "-" {
+		f, err := os.Open(opts.Import)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to open import file:", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	report, err := w.ImportLabels(in, opts.Overwrite)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to import labels:", err)
+		return 1
+	}
+
+	fmt.Printf("Imported %d records, %d unchanged\n", report.Imported,
+		report.Unchanged)
+
+	for _, skipped := range report.Skipped {
+		fmt.Printf("Skipped %s %s: %s\n", skipped.Record.Type,
+			skipped.Record.Ref, skipped.Reason)
+	}
+
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("Conflicting %s %s: existing %s, imported %s\n",
+			conflict.Imported.Type, conflict.Imported.Ref,
+			describeRecord(conflict.Existing),
+			describeRecord(conflict.Imported))
+	}
+
+	// Conflicts that weren't applied leave the wallet in a different state
+	// than the import file, so report them through the exit status.
+	if len(report.Conflicts) > 0 && !opts.Overwrite {
+		return 2
+	}
+
+	return 0
+}
+
+func describeRecord(record wallet.LabelRecord) string {
+	if record.Spendable != nil {
+		return fmt.Sprintf("spendable=%v", *record.Spendable)
+	}
+	return fmt.Sprintf("%q", record.Label)
+}
@@ -6,8 +6,10 @@ package main
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -18,6 +20,8 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/internal/cfgutil"
 	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
 	"github.com/btcsuite/btcwallet/netparams"
@@ -34,6 +38,16 @@ const (
 	defaultLogFilename      = "btcwallet.log"
 	defaultRPCMaxClients    = 10
 	defaultRPCMaxWebsockets = 25
+
+	defaultBitcoindZMQReadDeadline = 5 * time.Second
+	defaultBitcoindPollingInterval = time.Minute
+	defaultBitcoindPrunedMaxPeers  = 4
+
+	defaultElectrumPort    = "50001"
+	defaultElectrumTLSPort = "50002"
+
+	adminMacaroonFilename    = "admin.macaroon"
+	readOnlyMacaroonFilename = "readonly.macaroon"
 )
 
 var (
@@ -53,6 +67,8 @@ type config struct {
 	CreateTemp      bool                    `long:"createtemp" description:"Create a temporary simulation wallet (pass=password) in the data directory indicated; must call with --datadir"`
 	AppDataDir      *cfgutil.ExplicitString `short:"A" long:"appdata" description:"Application data directory for wallet config, databases and logs"`
 	TestNet3        bool                    `long:"testnet" description:"Use the test Bitcoin network (version 3) (default mainnet)"`
+	TestNet4        bool                    `long:"testnet4" description:"Use the test Bitcoin network (version 4) (default mainnet)"`
+	RegTest         bool                    `long:"regtest" description:"Use the regression test network (default mainnet)"`
 	SimNet          bool                    `long:"simnet" description:"Use the simulation test network (default mainnet)"`
 	SigNet          bool                    `long:"signet" description:"Use the signet test network (default mainnet)"`
 	SigNetChallenge string                  `long:"signetchallenge" description:"Connect to a custom signet network defined by this challenge instead of using the global default signet test network -- Can be specified multiple times"`
@@ -64,7 +80,9 @@ type config struct {
 	DBTimeout       time.Duration           `long:"dbtimeout" description:"The timeout value to use when opening the wallet database."`
 
 	// Wallet options
-	WalletPass string `long:"walletpass" default-mask:"-" description:"The public wallet password -- Only required if the wallet was created with one"`
+	WalletPass    string `long:"walletpass" default-mask:"-" description:"The public wallet password -- Only required if the wallet was created with one"`
+	RescanWorkers int    `long:"rescanworkers" description:"The number of requests made concurrently to the chain backend when the wallet scans the chain during rescans and recovery"`
+	EncryptDB     bool   `long:"encryptdb" description:"Encrypt the contents of the wallet database with the public wallet password, which must not be the default -- Existing wallet databases are encrypted the next time they are opened"`
 
 	// RPC client options
 	RPCConnect       string                  `short:"c" long:"rpcconnect" description:"Hostname/IP and port of btcd RPC server to connect to (default localhost:8334, testnet: localhost:18334, simnet: localhost:18556)"`
@@ -84,6 +102,34 @@ type config struct {
 	BanDuration  time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	BanThreshold uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
 
+	// Bitcoind client options
+	UseBitcoind                  bool          `long:"usebitcoind" description:"Use a bitcoind node rather than btcd or SPV for chain synchronization"`
+	BitcoindRPCHost              string        `long:"bitcoindrpchost" description:"Hostname/IP and port of the bitcoind RPC server to connect to (default localhost:8332, testnet: localhost:18332, signet: localhost:38332)"`
+	BitcoindRPCUser              string        `long:"bitcoindrpcuser" description:"Username for bitcoind RPC authentication"`
+	BitcoindRPCPass              string        `long:"bitcoindrpcpass" default-mask:"-" description:"Password for bitcoind RPC authentication"`
+	BitcoindRPCCookie            string        `long:"bitcoindrpccookie" description:"Path to the bitcoind RPC cookie file (default: the .cookie file in the bitcoind data directory of the active network) -- Used when no username and password are set"`
+	BitcoindZMQPubRawBlock       string        `long:"bitcoindzmqpubrawblock" description:"The address of bitcoind's ZMQ rawblock publisher (eg. tcp://127.0.0.1:28332)"`
+	BitcoindZMQPubRawTx          string        `long:"bitcoindzmqpubrawtx" description:"The address of bitcoind's ZMQ rawtx publisher (eg. tcp://127.0.0.1:28333)"`
+	BitcoindZMQPubSequence       string        `long:"bitcoindzmqpubsequence" description:"The optional address of bitcoind's ZMQ sequence publisher (eg. tcp://127.0.0.1:28334), used to learn about mempool removals and resync after dropped messages instead of polling the mempool"`
+	BitcoindZMQReadDeadline      time.Duration `long:"bitcoindzmqreaddeadline" description:"The read deadline for ZMQ messages from bitcoind"`
+	BitcoindRPCPolling           bool          `long:"bitcoindrpcpolling" description:"Poll bitcoind's RPC server for new blocks and transactions instead of using ZMQ"`
+	BitcoindBlockPollingInterval time.Duration `long:"bitcoindblockpollinginterval" description:"The interval at which bitcoind is polled for new blocks when RPC polling is used"`
+	BitcoindTxPollingInterval    time.Duration `long:"bitcoindtxpollinginterval" description:"The interval at which bitcoind's mempool is polled for new transactions"`
+	BitcoindPrunedMaxPeers       int           `long:"bitcoindprunedmaxpeers" description:"The maximum number of peers blocks are fetched from when they are no longer available from a pruned bitcoind node"`
+
+	// Esplora client options
+	EsploraURL          string        `long:"esplora" description:"Use the Esplora HTTP API at this URL rather than btcd, SPV or bitcoind for chain synchronization (eg. https://blockstream.info/testnet/api)"`
+	EsploraPollInterval time.Duration `long:"esplorapollinterval" description:"The interval at which the Esplora API is polled for new blocks and transactions"`
+
+	// Electrum client options
+	Electrum       string `long:"electrum" description:"Use the Electrum server (eg. electrs) at this host and port rather than btcd, SPV or bitcoind for chain synchronization"`
+	ElectrumTLS    bool   `long:"electrumtls" description:"Connect to the Electrum server over TLS"`
+	ElectrumCAFile string `long:"electrumcafile" description:"File containing root certificates to authenticate the TLS connection to the Electrum server instead of the system roots"`
+
+	// Chain backend failover options
+	Failover                    []string      `long:"failover" description:"Use the listed chain backends (btcd, bitcoind, esplora or electrum), failing over to the next one in order of preference when a backend is down or not current; each backend uses its own options"`
+	FailoverHealthCheckInterval time.Duration `long:"failoverhealthcheckinterval" description:"The interval at which the health of the chain backends listed by --failover is checked"`
+
 	// RPC server options
 	//
 	// The legacy server is still enabled by default (and eventually will be
@@ -101,12 +147,14 @@ type config struct {
 	LegacyRPCMaxWebsockets int64                   `long:"rpcmaxwebsockets" description:"Max number of legacy RPC websocket connections"`
 	Username               string                  `short:"u" long:"username" description:"Username for legacy RPC and btcd authentication (if btcdusername is unset)"`
 	Password               string                  `short:"P" long:"password" default-mask:"-" description:"Password for legacy RPC and btcd authentication (if btcdpassword is unset)"`
+	RPCAuth                []string                `long:"rpcauth" default-mask:"-" description:"Additional legacy RPC user in the form USERNAME:ROLE:SALT$HASH[:DAILYLIMIT], where ROLE is one of readonly, invoicing, spender (requires DAILYLIMIT in BTC) or admin.  Use cmd/rpcauth to create the hashed password"`
 
 	// EXPERIMENTAL RPC server options
 	//
 	// These options will change (and require changes to config files, etc.)
 	// when the new gRPC server is enabled.
 	ExperimentalRPCListeners []string `long:"experimentalrpclisten" description:"Listen for RPC connections on this interface/port"`
+	NoMacaroons              bool     `long:"nomacaroons" description:"Disable macaroon authentication of the gRPC server"`
 
 	// Deprecated options
 	DataDir *cfgutil.ExplicitString `short:"b" long:"datadir" default-mask:"-" description:"DEPRECATED -- use appdata instead"`
@@ -278,6 +326,14 @@ func loadConfig() (*config, []string, error) {
 		BanDuration:            neutrino.BanDuration,
 		BanThreshold:           neutrino.BanThreshold,
 		DBTimeout:              wallet.DefaultDBTimeout,
+		RescanWorkers:          wallet.DefaultScanWorkers,
+
+		BitcoindZMQReadDeadline:      defaultBitcoindZMQReadDeadline,
+		BitcoindBlockPollingInterval: defaultBitcoindPollingInterval,
+		BitcoindTxPollingInterval:    defaultBitcoindPollingInterval,
+		EsploraPollInterval:          chain.DefaultEsploraPollInterval,
+		FailoverHealthCheckInterval:  chain.DefaultFailoverHealthCheckInterval,
+		BitcoindPrunedMaxPeers:       defaultBitcoindPrunedMaxPeers,
 	}
 
 	// Pre-parse the command line options to see if an alternative config
@@ -366,6 +422,14 @@ func loadConfig() (*config, []string, error) {
 		activeNet = &netparams.TestNet3Params
 		numNets++
 	}
+	if cfg.TestNet4 {
+		activeNet = &netparams.TestNet4Params
+		numNets++
+	}
+	if cfg.RegTest {
+		activeNet = &netparams.RegressionNetParams
+		numNets++
+	}
 	if cfg.SimNet {
 		activeNet = &netparams.SimNetParams
 		numNets++
@@ -410,8 +474,8 @@ func loadConfig() (*config, []string, error) {
 		activeNet.Params = &chainParams
 	}
 	if numNets > 1 {
-		str := "%s: The testnet, signet and simnet params can't be " +
-			"used together -- choose one"
+		str := "%s: The testnet, testnet4, regtest, signet and " +
+			"simnet params can't be used together -- choose one"
 		err := fmt.Errorf(str, "loadConfig")
 		fmt.Fprintln(os.Stderr, err)
 		parser.WriteHelp(os.Stderr)
@@ -450,10 +514,10 @@ func loadConfig() (*config, []string, error) {
 	}
 
 	// Exit if you try to use a simulation wallet on anything other than
-	// simnet or testnet3.
-	if !cfg.SimNet && cfg.CreateTemp {
+	// simnet or regtest.
+	if !cfg.SimNet && !cfg.RegTest && cfg.CreateTemp {
 		fmt.Fprintln(os.Stderr, "Tried to create a temporary simulation "+
-			"wallet for network other than simnet!")
+			"wallet for network other than simnet or regtest!")
 		os.Exit(0)
 	}
 
@@ -539,70 +603,90 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	if cfg.RescanWorkers < 1 {
+		err := fmt.Errorf("%s: --rescanworkers must be positive",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	localhostListeners := map[string]struct{}{
 		"localhost": {},
 		"127.0.0.1": {},
 		"::1":       {},
 	}
 
-	if cfg.UseSPV {
-		neutrino.MaxPeers = cfg.MaxPeers
-		neutrino.BanDuration = cfg.BanDuration
-		neutrino.BanThreshold = cfg.BanThreshold
-	} else {
-		if cfg.RPCConnect == "" {
-			cfg.RPCConnect = net.JoinHostPort("localhost", activeNet.RPCClientPort)
+	numBackends := 0
+	for _, enabled := range []bool{
+		cfg.UseSPV, cfg.UseBitcoind, cfg.EsploraURL != "",
+		cfg.Electrum != "",
+	} {
+		if enabled {
+			numBackends++
 		}
+	}
+	if numBackends > 1 && len(cfg.Failover) == 0 {
+		err := fmt.Errorf("%s: only one of the --usespv, --usebitcoind, "+
+			"--esplora and --electrum options may be used", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
 
-		// Add default port to connect flag if missing.
-		cfg.RPCConnect, err = cfgutil.NormalizeAddress(cfg.RPCConnect,
-			activeNet.RPCClientPort)
+	switch {
+	case len(cfg.Failover) > 0:
+		err := validateFailoverConfig(&cfg, localhostListeners)
 		if err != nil {
-			fmt.Fprintf(os.Stderr,
-				"Invalid rpcconnect network address: %v\n", err)
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
 			return nil, nil, err
 		}
 
-		RPCHost, _, err := net.SplitHostPort(cfg.RPCConnect)
-		if err != nil {
+	case cfg.EsploraURL != "":
+		if err := validateEsploraConfig(&cfg); err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
 			return nil, nil, err
 		}
-		if cfg.DisableClientTLS {
-			if _, ok := localhostListeners[RPCHost]; !ok {
-				str := "%s: the --noclienttls option may not be used " +
-					"when connecting RPC to non localhost " +
-					"addresses: %s"
-				err := fmt.Errorf(str, funcName, cfg.RPCConnect)
-				fmt.Fprintln(os.Stderr, err)
-				fmt.Fprintln(os.Stderr, usageMessage)
-				return nil, nil, err
-			}
-		} else {
-			// If CAFile is unset, choose either the copy or local btcd cert.
-			if !cfg.CAFile.ExplicitlySet() {
-				cfg.CAFile.Value = filepath.Join(cfg.AppDataDir.Value, defaultCAFilename)
-
-				// If the CA copy does not exist, check if we're connecting to
-				// a local btcd and switch to its RPC cert if it exists.
-				certExists, err := cfgutil.FileExists(cfg.CAFile.Value)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, err)
-					return nil, nil, err
-				}
-				if !certExists {
-					if _, ok := localhostListeners[RPCHost]; ok {
-						btcdCertExists, err := cfgutil.FileExists(
-							btcdDefaultCAFile)
-						if err != nil {
-							fmt.Fprintln(os.Stderr, err)
-							return nil, nil, err
-						}
-						if btcdCertExists {
-							cfg.CAFile.Value = btcdDefaultCAFile
-						}
-					}
-				}
-			}
+
+	case cfg.Electrum != "":
+		if err := validateElectrumConfig(&cfg); err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+
+	case cfg.UseBitcoind:
+		if err := validateBitcoindConfig(&cfg); err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+
+	case cfg.UseSPV:
+		if cfg.TestNet4 {
+			err := fmt.Errorf("%s: the --usespv option does not "+
+				"support testnet4, since the difficulty rules "+
+				"of BIP 94 are not implemented", funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		neutrino.MaxPeers = cfg.MaxPeers
+		neutrino.BanDuration = cfg.BanDuration
+		neutrino.BanThreshold = cfg.BanThreshold
+
+	default:
+		if err := validateBtcdConfig(&cfg, localhostListeners); err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
 		}
 	}
 
@@ -710,3 +794,234 @@ func loadConfig() (*config, []string, error) {
 
 	return &cfg, remainingArgs, nil
 }
+
+// validateBtcdConfig checks the btcd RPC client options and fills in the
+// default server address and CA file.
+func validateBtcdConfig(cfg *config,
+	localhostListeners map[string]struct{}) error {
+
+	if cfg.RPCConnect == "" {
+		cfg.RPCConnect = net.JoinHostPort("localhost", activeNet.RPCClientPort)
+	}
+
+	// Add default port to connect flag if missing.
+	var err error
+	cfg.RPCConnect, err = cfgutil.NormalizeAddress(cfg.RPCConnect,
+		activeNet.RPCClientPort)
+	if err != nil {
+		return fmt.Errorf("invalid rpcconnect network address: %v", err)
+	}
+
+	RPCHost, _, err := net.SplitHostPort(cfg.RPCConnect)
+	if err != nil {
+		return err
+	}
+	if cfg.DisableClientTLS {
+		if _, ok := localhostListeners[RPCHost]; !ok {
+			return fmt.Errorf("the --noclienttls option may not be "+
+				"used when connecting RPC to non localhost "+
+				"addresses: %s", cfg.RPCConnect)
+		}
+		return nil
+	}
+
+	// If CAFile is unset, choose either the copy or local btcd cert.
+	if cfg.CAFile.ExplicitlySet() {
+		return nil
+	}
+	cfg.CAFile.Value = filepath.Join(cfg.AppDataDir.Value, defaultCAFilename)
+
+	// If the CA copy does not exist, check if we're connecting to a local
+	// btcd and switch to its RPC cert if it exists.
+	certExists, err := cfgutil.FileExists(cfg.CAFile.Value)
+	if err != nil {
+		return err
+	}
+	if !certExists {
+		if _, ok := localhostListeners[RPCHost]; ok {
+			btcdCertExists, err := cfgutil.FileExists(
+				btcdDefaultCAFile)
+			if err != nil {
+				return err
+			}
+			if btcdCertExists {
+				cfg.CAFile.Value = btcdDefaultCAFile
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFailoverConfig checks the backends listed by the failover option
+// and the options of each of them.  SPV can't be failed over to, and the
+// backend selection options other than the addresses of the Esplora API and
+// Electrum server are replaced by the list.
+func validateFailoverConfig(cfg *config,
+	localhostListeners map[string]struct{}) error {
+
+	if cfg.UseSPV || cfg.UseBitcoind {
+		return errors.New("the --failover option may not be used with " +
+			"--usespv or --usebitcoind")
+	}
+	if cfg.FailoverHealthCheckInterval <= 0 {
+		return errors.New("failoverhealthcheckinterval must be positive")
+	}
+
+	seen := make(map[string]struct{}, len(cfg.Failover))
+	for _, backend := range cfg.Failover {
+		if _, ok := seen[backend]; ok {
+			return fmt.Errorf("chain backend %q is listed more than "+
+				"once by --failover", backend)
+		}
+		seen[backend] = struct{}{}
+
+		var err error
+		switch backend {
+		case "btcd":
+			err = validateBtcdConfig(cfg, localhostListeners)
+		case "bitcoind":
+			err = validateBitcoindConfig(cfg)
+		case "esplora":
+			if cfg.EsploraURL == "" {
+				return errors.New("the esplora backend requires " +
+					"the --esplora option")
+			}
+			err = validateEsploraConfig(cfg)
+		case "electrum":
+			if cfg.Electrum == "" {
+				return errors.New("the electrum backend requires " +
+					"the --electrum option")
+			}
+			err = validateElectrumConfig(cfg)
+		default:
+			return fmt.Errorf("unknown chain backend %q, the "+
+				"--failover option accepts btcd, bitcoind, "+
+				"esplora and electrum", backend)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateEsploraConfig checks the Esplora client options.
+func validateEsploraConfig(cfg *config) error {
+	u, err := url.Parse(cfg.EsploraURL)
+	if err != nil {
+		return fmt.Errorf("invalid esplora URL: %v", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid esplora URL %q: an http or https "+
+			"URL is required", cfg.EsploraURL)
+	}
+	if cfg.EsploraPollInterval <= 0 {
+		return errors.New("esplorapollinterval must be positive")
+	}
+
+	return nil
+}
+
+// validateElectrumConfig checks the Electrum client options.  The server
+// address defaults to the standard Electrum port of the connection type.
+func validateElectrumConfig(cfg *config) error {
+	defaultPort := defaultElectrumPort
+	if cfg.ElectrumTLS {
+		defaultPort = defaultElectrumTLSPort
+	}
+
+	var err error
+	cfg.Electrum, err = cfgutil.NormalizeAddress(cfg.Electrum, defaultPort)
+	if err != nil {
+		return fmt.Errorf("invalid electrum server address: %v", err)
+	}
+
+	if cfg.ElectrumCAFile != "" {
+		if !cfg.ElectrumTLS {
+			return errors.New("electrumcafile requires electrumtls")
+		}
+		cfg.ElectrumCAFile = cleanAndExpandPath(cfg.ElectrumCAFile)
+	}
+
+	return nil
+}
+
+// validateBitcoindConfig checks the bitcoind client options and fills in the
+// defaults that depend on the active network.
+func validateBitcoindConfig(cfg *config) error {
+	if activeNet.BitcoindRPCPort == "" {
+		return fmt.Errorf("bitcoind does not support the %s network",
+			activeNet.Params.Name)
+	}
+
+	if cfg.BitcoindRPCHost == "" {
+		cfg.BitcoindRPCHost = net.JoinHostPort("localhost",
+			activeNet.BitcoindRPCPort)
+	}
+	var err error
+	cfg.BitcoindRPCHost, err = cfgutil.NormalizeAddress(
+		cfg.BitcoindRPCHost, activeNet.BitcoindRPCPort,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid bitcoindrpchost network address: %v",
+			err)
+	}
+
+	// Authenticate with either a username and password or the cookie
+	// file, which defaults to the one of a local bitcoind.
+	hasUserPass := cfg.BitcoindRPCUser != "" || cfg.BitcoindRPCPass != ""
+	switch {
+	case hasUserPass && cfg.BitcoindRPCCookie != "":
+		return errors.New("the --bitcoindrpccookie option may not be " +
+			"used with --bitcoindrpcuser and --bitcoindrpcpass")
+	case hasUserPass && (cfg.BitcoindRPCUser == "" ||
+		cfg.BitcoindRPCPass == ""):
+		return errors.New("both --bitcoindrpcuser and --bitcoindrpcpass " +
+			"must be set")
+	case !hasUserPass && cfg.BitcoindRPCCookie == "":
+		cfg.BitcoindRPCCookie = filepath.Join(
+			btcutil.AppDataDir("bitcoin", false),
+			bitcoindNetDir(activeNet.Params), ".cookie",
+		)
+	}
+	if cfg.BitcoindRPCCookie != "" {
+		cfg.BitcoindRPCCookie = cleanAndExpandPath(cfg.BitcoindRPCCookie)
+	}
+
+	// Block and transaction notifications are received either by polling
+	// or from both ZMQ publishers.
+	hasZMQ := cfg.BitcoindZMQPubRawBlock != "" ||
+		cfg.BitcoindZMQPubRawTx != "" || cfg.BitcoindZMQPubSequence != ""
+	switch {
+	case cfg.BitcoindRPCPolling && hasZMQ:
+		return errors.New("the --bitcoindrpcpolling option may not be " +
+			"used with the bitcoind ZMQ options")
+	case !cfg.BitcoindRPCPolling && (cfg.BitcoindZMQPubRawBlock == "" ||
+		cfg.BitcoindZMQPubRawTx == ""):
+		return errors.New("both --bitcoindzmqpubrawblock and " +
+			"--bitcoindzmqpubrawtx must be set unless " +
+			"--bitcoindrpcpolling is used")
+	}
+
+	if cfg.BitcoindBlockPollingInterval <= 0 ||
+		cfg.BitcoindTxPollingInterval <= 0 {
+
+		return errors.New("bitcoind polling intervals must be positive")
+	}
+	if cfg.BitcoindPrunedMaxPeers <= 0 {
+		return errors.New("--bitcoindprunedmaxpeers must be positive")
+	}
+
+	return nil
+}
+
+// bitcoindNetDir returns the name of the subdirectory of the bitcoind data
+// directory used for a network.
+func bitcoindNetDir(params *chaincfg.Params) string {
+	if params.Net == wire.MainNet {
+		return ""
+	}
+	return params.Name
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encdb
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcwallet/internal/zero"
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+var (
+	// ErrNotEncrypted is returned when opening a database which isn't
+	// encrypted.
+	ErrNotEncrypted = errors.New("database is not encrypted")
+
+	// ErrNotEmpty is returned when creating an encrypted database in a
+	// database which already has contents.
+	ErrNotEmpty = errors.New("database is not empty")
+
+	// ErrAlreadyEncrypted is returned when encrypting a database which is
+	// already encrypted.
+	ErrAlreadyEncrypted = errors.New("database is already encrypted")
+)
+
+var (
+	// metaBucketName is the name of the top level bucket of the wrapped
+	// database holding the encryption parameters.  It's shorter than any
+	// encrypted key, so it never collides with an encrypted bucket.
+	metaBucketName = []byte("encdb")
+
+	// paramsName is the key of the parameters deriving the passphrase key.
+	paramsName = []byte("params")
+
+	// keysName is the key of the encryption keys, encrypted with the
+	// passphrase key.
+	keysName = []byte("keys")
+)
+
+// keys houses the keys encrypting the contents of the database, which are
+// stored in the wrapped database as a whole:
+//
+//	<nonce key><name key><value key>
+//
+// The nonce key is the key of the HMAC deriving the nonce of an encrypted key
+// from its plaintext, the name key encrypts the keys of the key/value pairs and
+// the names of the buckets, and the value key encrypts the values.
+type keys [3 * snacl.KeySize]byte
+
+// generateKeys returns new random keys.
+func generateKeys() (*keys, error) {
+	var k keys
+	if _, err := io.ReadFull(rand.Reader, k[:]); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+func (k *keys) nonceKey() []byte {
+	return k[:snacl.KeySize]
+}
+
+func (k *keys) nameKey() *[snacl.KeySize]byte {
+	return (*[snacl.KeySize]byte)(k[snacl.KeySize : 2*snacl.KeySize])
+}
+
+func (k *keys) valueKey() *snacl.CryptoKey {
+	return (*snacl.CryptoKey)(k[2*snacl.KeySize:])
+}
+
+// zero clears the keys.
+func (k *keys) zero() {
+	zero.Bytes(k[:])
+}
+
+// encryptKey encrypts a key deterministically, using a nonce derived from the
+// plaintext.
+func (k *keys) encryptKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, k.nonceKey())
+	mac.Write(key)
+
+	var nonce [snacl.NonceSize]byte
+	copy(nonce[:], mac.Sum(nil))
+
+	out := make([]byte, snacl.NonceSize, snacl.NonceSize+len(key)+
+		snacl.Overhead)
+	copy(out, nonce[:])
+	return secretbox.Seal(out, key, &nonce, k.nameKey())
+}
+
+// decryptKey decrypts a key encrypted by encryptKey.
+func (k *keys) decryptKey(encKey []byte) ([]byte, error) {
+	if len(encKey) < snacl.NonceSize+snacl.Overhead {
+		return nil, snacl.ErrMalformed
+	}
+
+	var nonce [snacl.NonceSize]byte
+	copy(nonce[:], encKey[:snacl.NonceSize])
+
+	key, ok := secretbox.Open(
+		nil, encKey[snacl.NonceSize:], &nonce, k.nameKey(),
+	)
+	if !ok {
+		return nil, snacl.ErrDecryptFailed
+	}
+	if key == nil {
+		key = []byte{}
+	}
+
+	return key, nil
+}
+
+// encryptValue encrypts a value with a random nonce.
+func (k *keys) encryptValue(value []byte) ([]byte, error) {
+	return k.valueKey().Encrypt(value)
+}
+
+// decryptValue decrypts a value encrypted by encryptValue.
+func (k *keys) decryptValue(encValue []byte) ([]byte, error) {
+	value, err := k.valueKey().Decrypt(encValue)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+
+	return value, nil
+}
+
+// IsEncrypted returns whether the given database holds an encrypted database.
+func IsEncrypted(db walletdb.DB) (bool, error) {
+	var encrypted bool
+	err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+		encrypted = tx.ReadBucket(metaBucketName) != nil
+		return nil
+	})
+
+	return encrypted, err
+}
+
+// Create creates an encrypted database in the given database, which must be
+// empty, and returns it.  The contents of the returned database are encrypted
+// with keys protected by the passphrase.
+func Create(db walletdb.DB, passphrase []byte) (walletdb.DB, error) {
+	return create(db, passphrase)
+}
+
+// create creates an encrypted database in the given database.
+func create(db walletdb.DB, passphrase []byte) (*encDB, error) {
+	k, err := generateKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey, err := snacl.NewSecretKey(
+		&passphrase, snacl.DefaultN, snacl.DefaultR, snacl.DefaultP,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer secretKey.Zero()
+
+	encKeys, err := secretKey.Encrypt(k[:])
+	if err != nil {
+		return nil, err
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		err := tx.ForEachBucket(func([]byte) error {
+			return ErrNotEmpty
+		})
+		if err != nil {
+			return err
+		}
+
+		meta, err := tx.CreateTopLevelBucket(metaBucketName)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put(paramsName, secretKey.Marshal()); err != nil {
+			return err
+		}
+		return meta.Put(keysName, encKeys)
+	})
+	if err != nil {
+		k.zero()
+		return nil, err
+	}
+
+	return &encDB{db: db, keys: k}, nil
+}
+
+// Open opens the encrypted database held by the given database, using the
+// passphrase it was created with.  ErrNotEncrypted is returned if the database
+// isn't encrypted, and snacl.ErrInvalidPassword if the passphrase is wrong.
+func Open(db walletdb.DB, passphrase []byte) (walletdb.DB, error) {
+	var params, encKeys []byte
+	err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+		meta := tx.ReadBucket(metaBucketName)
+		if meta == nil {
+			return ErrNotEncrypted
+		}
+
+		params = append([]byte(nil), meta.Get(paramsName)...)
+		encKeys = append([]byte(nil), meta.Get(keysName)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var secretKey snacl.SecretKey
+	if err := secretKey.Unmarshal(params); err != nil {
+		return nil, err
+	}
+	if err := secretKey.DeriveKey(&passphrase); err != nil {
+		return nil, err
+	}
+	defer secretKey.Zero()
+
+	serialized, err := secretKey.Decrypt(encKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer zero.Bytes(serialized)
+
+	var k keys
+	if len(serialized) != len(k) {
+		return nil, snacl.ErrMalformed
+	}
+	copy(k[:], serialized)
+
+	return &encDB{db: db, keys: &k}, nil
+}
+
+// ChangePassphrase re-encrypts the keys of an encrypted database with a new
+// passphrase, as part of the given read-write transaction so that it can be
+// changed atomically with the passphrase it's derived from.  Nothing is done if
+// the transaction doesn't belong to an encrypted database.
+func ChangePassphrase(tx walletdb.ReadWriteTx, old, new []byte) error {
+	encTx, ok := tx.(*transaction)
+	if !ok {
+		return nil
+	}
+	rwTx, err := encTx.rwTx()
+	if err != nil {
+		return err
+	}
+
+	meta := rwTx.ReadWriteBucket(metaBucketName)
+	if meta == nil {
+		return ErrNotEncrypted
+	}
+
+	var oldKey snacl.SecretKey
+	if err := oldKey.Unmarshal(meta.Get(paramsName)); err != nil {
+		return err
+	}
+	if err := oldKey.DeriveKey(&old); err != nil {
+		return err
+	}
+	defer oldKey.Zero()
+
+	serialized, err := oldKey.Decrypt(meta.Get(keysName))
+	if err != nil {
+		return err
+	}
+	defer zero.Bytes(serialized)
+
+	newKey, err := snacl.NewSecretKey(
+		&new, snacl.DefaultN, snacl.DefaultR, snacl.DefaultP,
+	)
+	if err != nil {
+		return err
+	}
+	defer newKey.Zero()
+
+	encKeys, err := newKey.Encrypt(serialized)
+	if err != nil {
+		return err
+	}
+
+	if err := meta.Put(paramsName, newKey.Marshal()); err != nil {
+		return err
+	}
+	return meta.Put(keysName, encKeys)
+}
@@ -0,0 +1,636 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encdb
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// transaction wraps a transaction of the wrapped database, encrypting and
+// decrypting the contents of the buckets it opens.  It implements the walletdb
+// Tx interfaces.
+type transaction struct {
+	tx   walletdb.ReadTx
+	keys *keys
+}
+
+// Enforce transaction implements the walletdb Tx interfaces.
+var _ walletdb.ReadWriteTx = (*transaction)(nil)
+
+// rwTx returns the wrapped transaction if it's a read-write transaction.
+func (tx *transaction) rwTx() (walletdb.ReadWriteTx, error) {
+	rwTx, ok := tx.tx.(walletdb.ReadWriteTx)
+	if !ok {
+		return nil, walletdb.ErrTxNotWritable
+	}
+	return rwTx, nil
+}
+
+// wrapBucket returns the encrypting bucket wrapping the given bucket, or nil if
+// it's nil.
+func (tx *transaction) wrapBucket(b walletdb.ReadBucket) *bucket {
+	if b == nil {
+		return nil
+	}
+	return &bucket{tx: tx, b: b}
+}
+
+func (tx *transaction) ReadBucket(key []byte) walletdb.ReadBucket {
+	// Don't return a non-nil interface to a nil pointer.
+	b := tx.wrapBucket(tx.tx.ReadBucket(tx.keys.encryptKey(key)))
+	if b == nil {
+		return nil
+	}
+	return b
+}
+
+// ForEachBucket will iterate through all top level buckets.
+func (tx *transaction) ForEachBucket(fn func(key []byte) error) error {
+	return tx.tx.ForEachBucket(func(encKey []byte) error {
+		if bytes.Equal(encKey, metaBucketName) {
+			return nil
+		}
+
+		key, err := tx.keys.decryptKey(encKey)
+		if err != nil {
+			return err
+		}
+		return fn(key)
+	})
+}
+
+func (tx *transaction) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	rwTx, err := tx.rwTx()
+	if err != nil {
+		return nil
+	}
+
+	// Don't return a non-nil interface to a nil pointer.
+	rwBucket := rwTx.ReadWriteBucket(tx.keys.encryptKey(key))
+	if rwBucket == nil {
+		return nil
+	}
+	return tx.wrapBucket(rwBucket)
+}
+
+func (tx *transaction) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	rwTx, err := tx.rwTx()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+
+	rwBucket, err := rwTx.CreateTopLevelBucket(tx.keys.encryptKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return tx.wrapBucket(rwBucket), nil
+}
+
+// DeleteTopLevelBucket deletes the top level bucket for a key.  This errors if
+// the bucket can not be found or the key keys a single value instead of a
+// bucket.
+//
+// This function is part of the walletdb.ReadWriteTx interface implementation.
+func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
+	rwTx, err := tx.rwTx()
+	if err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	return rwTx.DeleteTopLevelBucket(tx.keys.encryptKey(key))
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets to the database.
+//
+// This function is part of the walletdb.ReadWriteTx interface implementation.
+func (tx *transaction) Commit() error {
+	rwTx, err := tx.rwTx()
+	if err != nil {
+		return err
+	}
+	return rwTx.Commit()
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of
+// its sub-buckets.
+//
+// This function is part of the walletdb.ReadTx interface implementation.
+func (tx *transaction) Rollback() error {
+	return tx.tx.Rollback()
+}
+
+// OnCommit takes a function closure that will be executed when the transaction
+// successfully gets committed.
+//
+// This function is part of the walletdb.ReadWriteTx interface implementation.
+func (tx *transaction) OnCommit(f func()) {
+	if rwTx, err := tx.rwTx(); err == nil {
+		rwTx.OnCommit(f)
+	}
+}
+
+// bucket wraps a bucket of the wrapped database, encrypting the keys and values
+// put into it.  It implements the walletdb Bucket interfaces.
+type bucket struct {
+	tx *transaction
+	b  walletdb.ReadBucket
+}
+
+// Enforce bucket implements the walletdb Bucket interfaces.
+var _ walletdb.ReadWriteBucket = (*bucket)(nil)
+
+// rwBucket returns the wrapped bucket if it belongs to a read-write
+// transaction.
+func (b *bucket) rwBucket() (walletdb.ReadWriteBucket, error) {
+	rwBucket, ok := b.b.(walletdb.ReadWriteBucket)
+	if !ok {
+		return nil, walletdb.ErrTxNotWritable
+	}
+	return rwBucket, nil
+}
+
+// entries returns the decrypted keys of the bucket in ascending order, along
+// with their encrypted form.
+func (b *bucket) entries() ([]cursorEntry, error) {
+	var entries []cursorEntry
+	err := b.b.ForEach(func(encKey, encValue []byte) error {
+		key, err := b.tx.keys.decryptKey(encKey)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, cursorEntry{
+			key:      key,
+			encKey:   encKey,
+			isBucket: encValue == nil,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return entries, nil
+}
+
+// value returns the decrypted value of the given entry, or nil if it's a
+// nested bucket.
+func (b *bucket) value(entry *cursorEntry) ([]byte, error) {
+	if entry.isBucket {
+		return nil, nil
+	}
+	return b.tx.keys.decryptValue(b.b.Get(entry.encKey))
+}
+
+// NestedReadWriteBucket retrieves a nested bucket with the given key.  Returns
+// nil if the bucket does not exist.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return nil
+	}
+
+	// Don't return a non-nil interface to a nil pointer.
+	nested := rwBucket.NestedReadWriteBucket(b.tx.keys.encryptKey(key))
+	if nested == nil {
+		return nil
+	}
+	return b.tx.wrapBucket(nested)
+}
+
+func (b *bucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
+	// Don't return a non-nil interface to a nil pointer.
+	nested := b.tx.wrapBucket(b.b.NestedReadBucket(b.tx.keys.encryptKey(key)))
+	if nested == nil {
+		return nil
+	}
+	return nested
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key value is otherwise
+// invalid.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+
+	nested, err := rwBucket.CreateBucket(b.tx.keys.encryptKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return b.tx.wrapBucket(nested), nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.  Returns ErrBucketNameRequired if the
+// key is empty or ErrIncompatibleValue if the key value is otherwise invalid.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.ReadWriteBucket, error) {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+
+	nested, err := rwBucket.CreateBucketIfNotExists(
+		b.tx.keys.encryptKey(key),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return b.tx.wrapBucket(nested), nil
+}
+
+// DeleteNestedBucket removes a nested bucket with the given key.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction and
+// ErrBucketNotFound if the specified bucket does not exist.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) DeleteNestedBucket(key []byte) error {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return err
+	}
+
+	// An empty key can't name a bucket.
+	if len(key) == 0 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	return rwBucket.DeleteNestedBucket(b.tx.keys.encryptKey(key))
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket,
+// in ascending order of the decrypted keys.  This includes nested buckets, in
+// which case the value is nil, but it does not include the key/value pairs
+// within those nested buckets.
+//
+// This function is part of the walletdb.ReadBucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	entries, err := b.entries()
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		value, err := b.value(&entries[i])
+		if err != nil {
+			return err
+		}
+		if err := fn(entries[i].key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Put saves the specified key/value pair to the bucket.  Keys that do not
+// already exist are added and keys that already exist are overwritten.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return walletdb.ErrKeyRequired
+	}
+
+	encValue, err := b.tx.keys.encryptValue(value)
+	if err != nil {
+		return err
+	}
+	return rwBucket.Put(b.tx.keys.encryptKey(key), encValue)
+}
+
+// Get returns the value for the given key.  Returns nil if the key does not
+// exist in this bucket, or if its value fails to be authenticated.
+//
+// This function is part of the walletdb.ReadBucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	if len(key) == 0 {
+		return nil
+	}
+
+	encValue := b.b.Get(b.tx.keys.encryptKey(key))
+	if encValue == nil {
+		return nil
+	}
+
+	value, err := b.tx.keys.decryptValue(encValue)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// Delete removes the specified key from the bucket.  Deleting a key that does
+// not exist does not return an error.  Returns ErrTxNotWritable if attempted
+// against a read-only transaction.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return err
+	}
+	return rwBucket.Delete(b.tx.keys.encryptKey(key))
+}
+
+func (b *bucket) ReadCursor() walletdb.ReadCursor {
+	return &cursor{bucket: b, index: -1}
+}
+
+// ReadWriteCursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	return &cursor{bucket: b, index: -1}
+}
+
+// Tx returns the bucket's transaction.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) Tx() walletdb.ReadWriteTx {
+	return b.tx
+}
+
+// NextSequence returns an autoincrementing integer for the bucket.
+func (b *bucket) NextSequence() (uint64, error) {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return 0, err
+	}
+	return rwBucket.NextSequence()
+}
+
+// SetSequence updates the sequence number for the bucket.
+func (b *bucket) SetSequence(v uint64) error {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return err
+	}
+	return rwBucket.SetSequence(v)
+}
+
+// Sequence returns the current integer for the bucket without incrementing it.
+func (b *bucket) Sequence() uint64 {
+	rwBucket, err := b.rwBucket()
+	if err != nil {
+		return 0
+	}
+	return rwBucket.Sequence()
+}
+
+// cursorEntry is a key/value pair or nested bucket of the bucket of a cursor.
+type cursorEntry struct {
+	key      []byte
+	encKey   []byte
+	isBucket bool
+}
+
+// cursor represents a cursor over key/value pairs and nested buckets of a
+// bucket.  Since the encrypted keys aren't sorted like the plaintext ones, the
+// cursor decrypts and sorts all the keys of the bucket when it's first
+// positioned.
+//
+// Note that open cursors are not tracked on bucket changes and any
+// modifications to the bucket, with the exception of cursor.Delete, invalidate
+// the cursor.  After invalidation, the cursor must be repositioned, or the keys
+// and values returned may be unpredictable.
+type cursor struct {
+	bucket *bucket
+
+	// entries are the entries of the bucket, sorted by key.  They're
+	// loaded when the cursor is first positioned.
+	entries []cursorEntry
+	loaded  bool
+
+	// index is the position of the cursor among the entries.
+	index int
+}
+
+// load loads the entries of the bucket if they weren't yet.  Entries which
+// fail to be decrypted are skipped, since a cursor can't return errors.
+func (c *cursor) load() {
+	if c.loaded {
+		return
+	}
+
+	c.entries, _ = c.bucket.entries()
+	c.loaded = true
+}
+
+// position moves the cursor to the entry at the given index and returns it, or
+// nil if the index is out of range.
+func (c *cursor) position(i int) (key, value []byte) {
+	c.load()
+
+	switch {
+	case i < 0:
+		c.index = -1
+		return nil, nil
+	case i >= len(c.entries):
+		c.index = len(c.entries)
+		return nil, nil
+	}
+
+	c.index = i
+	entry := &c.entries[i]
+	value, err := c.bucket.value(entry)
+	if err != nil {
+		return entry.key, nil
+	}
+	return entry.key, value
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor.
+//
+// This function is part of the walletdb.ReadWriteCursor interface implementation.
+func (c *cursor) Delete() error {
+	rwBucket, err := c.bucket.rwBucket()
+	if err != nil {
+		return err
+	}
+
+	c.load()
+	if c.index < 0 || c.index >= len(c.entries) {
+		return nil
+	}
+	if c.entries[c.index].isBucket {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	if err := rwBucket.Delete(c.entries[c.index].encKey); err != nil {
+		return err
+	}
+
+	// Step back so that the next entry is the one following the deleted
+	// one.
+	c.entries = append(c.entries[:c.index], c.entries[c.index+1:]...)
+	c.index--
+	return nil
+}
+
+// First positions the cursor at the first key/value pair and returns the pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) First() (key, value []byte) {
+	return c.position(0)
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Last() (key, value []byte) {
+	c.load()
+	return c.position(len(c.entries) - 1)
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Next() (key, value []byte) {
+	return c.position(c.index + 1)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Prev() (key, value []byte) {
+	return c.position(c.index - 1)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not exist,
+// the cursor is moved to the next key after seek. Returns the new pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	c.load()
+	i := sort.Search(len(c.entries), func(i int) bool {
+		return bytes.Compare(c.entries[i].key, seek) >= 0
+	})
+	return c.position(i)
+}
+
+// encDB is an encrypted database wrapping another walletdb database.  It
+// implements the walletdb.DB interface.
+type encDB struct {
+	db   walletdb.DB
+	keys *keys
+}
+
+// Enforce encDB implements the walletdb.DB interface.
+var _ walletdb.DB = (*encDB)(nil)
+
+func (db *encDB) wrapTx(tx walletdb.ReadTx) *transaction {
+	return &transaction{tx: tx, keys: db.keys}
+}
+
+func (db *encDB) BeginReadTx() (walletdb.ReadTx, error) {
+	tx, err := db.db.BeginReadTx()
+	if err != nil {
+		return nil, err
+	}
+	return db.wrapTx(tx), nil
+}
+
+func (db *encDB) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	tx, err := db.db.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+	return db.wrapTx(tx), nil
+}
+
+// Copy writes a copy of the wrapped database, whose contents remain encrypted,
+// to the provided writer.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *encDB) Copy(w io.Writer) error {
+	return db.db.Copy(w)
+}
+
+// Close clears the encryption keys from memory and closes the wrapped
+// database.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *encDB) Close() error {
+	db.keys.zero()
+	return db.db.Close()
+}
+
+// Batch runs the function in a batch of the wrapped database if it supports
+// them, or in a read-write transaction otherwise.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *encDB) Batch(f func(tx walletdb.ReadWriteTx) error) error {
+	batchDB, ok := db.db.(walletdb.BatchDB)
+	if !ok {
+		return db.Update(f, func() {})
+	}
+
+	return batchDB.Batch(func(tx walletdb.ReadWriteTx) error {
+		return f(db.wrapTx(tx))
+	})
+}
+
+// View opens a read transaction of the wrapped database and executes the
+// function f with the transaction passed as a parameter, as described by the
+// wrapped database.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *encDB) View(f func(tx walletdb.ReadTx) error, reset func()) error {
+	return db.db.View(func(tx walletdb.ReadTx) error {
+		return f(db.wrapTx(tx))
+	}, reset)
+}
+
+// Update opens a read/write transaction of the wrapped database and executes
+// the function f with the transaction passed as a parameter, as described by
+// the wrapped database.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *encDB) Update(f func(tx walletdb.ReadWriteTx) error, reset func()) error {
+	return db.db.Update(func(tx walletdb.ReadWriteTx) error {
+		return f(db.wrapTx(tx))
+	}, reset)
+}
+
+// PrintStats returns all collected stats pretty printed into a string.
+func (db *encDB) PrintStats() string {
+	return db.db.PrintStats()
+}
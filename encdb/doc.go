@@ -0,0 +1,51 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package encdb implements a walletdb driver that encrypts the contents of
+another walletdb database, so that the transaction history, addresses and
+labels of a wallet aren't readable from a copy of its database file.
+
+# Usage
+
+This package is a driver to the walletdb package and provides the database type
+of "encdb". The Open and Create functions take the database to wrap, which must
+already be open, and the passphrase encrypting it, usually the public passphrase
+of the wallet:
+
+	boltDB, err := walletdb.Open("bdb", "path/to/database.db", true, 60*time.Second)
+	if err != nil {
+		// Handle error
+	}
+	db, err := walletdb.Open("encdb", boltDB, []byte("public"))
+	if err != nil {
+		// Handle error
+	}
+
+Create only accepts an empty database. Existing databases are encrypted by
+copying their contents to a new encrypted database with Encrypt.
+
+# Encryption
+
+The contents of the database are encrypted with random keys, which are stored
+in the wrapped database encrypted with a key derived from the passphrase with
+scrypt, using the snacl package.
+
+The keys of the key/value pairs and the names of the buckets are encrypted
+deterministically, using a nonce derived from the plaintext key with HMAC-SHA256,
+so that they can be looked up by their plaintext. The values are encrypted with
+random nonces. Both are authenticated by NaCl's secretbox.
+
+The encrypted keys don't keep the order of the plaintext keys, so a cursor
+decrypts and sorts the keys of its bucket when it's first positioned. Iterating
+over large buckets is therefore slower than with the wrapped database.
+
+The following isn't hidden by the encryption:
+
+  - The number of buckets and key/value pairs, and their nesting
+  - The length of the keys and values
+  - The bucket sequences
+  - Which keys are identical, in any bucket
+*/
+package encdb
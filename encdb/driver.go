@@ -0,0 +1,74 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encdb
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	dbType = "encdb"
+)
+
+// parseArgs parses the arguments from the walletdb Open/Create methods.
+func parseArgs(funcName string, args ...interface{}) (walletdb.DB, []byte,
+	error) {
+
+	if len(args) != 2 {
+		return nil, nil, fmt.Errorf("invalid arguments to %s.%s -- "+
+			"expected database and passphrase", dbType, funcName)
+	}
+
+	db, ok := args[0].(walletdb.DB)
+	if !ok {
+		return nil, nil, fmt.Errorf("first argument to %s.%s is "+
+			"invalid -- expected walletdb.DB", dbType, funcName)
+	}
+
+	passphrase, ok := args[1].([]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("second argument to %s.%s is "+
+			"invalid -- expected passphrase []byte", dbType, funcName)
+	}
+
+	return db, passphrase, nil
+}
+
+// openDBDriver is the callback provided during driver registration that opens
+// an existing database for use.
+func openDBDriver(args ...interface{}) (walletdb.DB, error) {
+	db, passphrase, err := parseArgs("Open", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(db, passphrase)
+}
+
+// createDBDriver is the callback provided during driver registration that
+// creates, initializes, and opens a database for use.
+func createDBDriver(args ...interface{}) (walletdb.DB, error) {
+	db, passphrase, err := parseArgs("Create", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return Create(db, passphrase)
+}
+
+func init() {
+	// Register the driver.
+	driver := walletdb.Driver{
+		DbType: dbType,
+		Create: createDBDriver,
+		Open:   openDBDriver,
+	}
+	if err := walletdb.RegisterDriver(driver); err != nil {
+		panic(fmt.Sprintf("Failed to regiser database driver '%s': %v",
+			dbType, err))
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encdb
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPassphrase = []byte("passphrase")
+	bucketKey      = []byte("secretbucket")
+)
+
+// newBoltDB returns a new bdb database in a temporary directory.
+func newBoltDB(t *testing.T) walletdb.DB {
+	t.Helper()
+
+	db, err := walletdb.Create(
+		"bdb", filepath.Join(t.TempDir(), "db"), true, 10*time.Second,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+// fillDB creates a bucket holding a few key/value pairs and a nested bucket.
+func fillDB(t *testing.T, db walletdb.DB) {
+	t.Helper()
+
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		for i := byte(0); i < 20; i++ {
+			err := b.Put([]byte{'k', i}, []byte("secretvalue"))
+			if err != nil {
+				return err
+			}
+		}
+		nested, err := b.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		return nested.SetSequence(7)
+	})
+	require.NoError(t, err)
+}
+
+// checkDB checks the contents created by fillDB.
+func checkDB(t *testing.T, db walletdb.DB) {
+	t.Helper()
+
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b := tx.ReadWriteBucket(bucketKey)
+		require.NotNil(t, b)
+
+		// The cursor returns the keys in order, even though their
+		// encrypted form isn't.
+		c := b.ReadCursor()
+		k, v := c.Seek([]byte{'k', 5})
+		require.Equal(t, []byte{'k', 5}, k)
+		require.Equal(t, []byte("secretvalue"), v)
+
+		k, _ = c.Next()
+		require.Equal(t, []byte{'k', 6}, k)
+
+		k, v = c.Last()
+		require.Equal(t, []byte("nested"), k)
+		require.Nil(t, v)
+
+		var count int
+		var prev []byte
+		err := b.ForEach(func(k, _ []byte) error {
+			require.Positive(t, bytes.Compare(k, prev))
+			prev = k
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 21, count)
+
+		nested := b.NestedReadWriteBucket([]byte("nested"))
+		require.NotNil(t, nested)
+		require.EqualValues(t, 7, nested.Sequence())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// checkEncrypted checks that the plaintext written by fillDB doesn't appear in
+// the wrapped database.
+func checkEncrypted(t *testing.T, db walletdb.DB) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, db.Copy(&buf))
+	require.NotContains(t, buf.String(), string(bucketKey))
+	require.NotContains(t, buf.String(), "secretvalue")
+	require.NotContains(t, buf.String(), "nested")
+}
+
+// TestCreateOpenFail ensures that errors related to creating and opening a
+// database are handled properly.
+func TestCreateOpenFail(t *testing.T) {
+	t.Parallel()
+
+	boltDB := newBoltDB(t)
+
+	_, err := walletdb.Open(dbType, boltDB)
+	require.EqualError(t, err, "invalid arguments to encdb.Open -- "+
+		"expected database and passphrase")
+
+	_, err = walletdb.Create(dbType, "db", testPassphrase)
+	require.EqualError(t, err, "first argument to encdb.Create is "+
+		"invalid -- expected walletdb.DB")
+
+	_, err = walletdb.Open(dbType, boltDB, "passphrase")
+	require.EqualError(t, err, "second argument to encdb.Open is "+
+		"invalid -- expected passphrase []byte")
+
+	// The database can't be opened before it's encrypted, and can't be
+	// encrypted in place once it has contents.
+	_, err = walletdb.Open(dbType, boltDB, testPassphrase)
+	require.ErrorIs(t, err, ErrNotEncrypted)
+
+	err = walletdb.Update(boltDB, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket(bucketKey)
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = walletdb.Create(dbType, boltDB, testPassphrase)
+	require.ErrorIs(t, err, ErrNotEmpty)
+
+	// Once encrypted, it can only be opened with the right passphrase.
+	boltDB = newBoltDB(t)
+	_, err = walletdb.Create(dbType, boltDB, testPassphrase)
+	require.NoError(t, err)
+
+	_, err = walletdb.Open(dbType, boltDB, []byte("wrong"))
+	require.ErrorIs(t, err, snacl.ErrInvalidPassword)
+
+	_, err = walletdb.Open(dbType, boltDB, testPassphrase)
+	require.NoError(t, err)
+}
+
+// TestEncryption ensures that the contents of an encrypted database can be
+// read back after it's reopened, and aren't readable from the wrapped
+// database.
+func TestEncryption(t *testing.T) {
+	t.Parallel()
+
+	boltDB := newBoltDB(t)
+	db, err := walletdb.Create(dbType, boltDB, testPassphrase)
+	require.NoError(t, err)
+	fillDB(t, db)
+	checkEncrypted(t, boltDB)
+
+	db, err = walletdb.Open(dbType, boltDB, testPassphrase)
+	require.NoError(t, err)
+	checkDB(t, db)
+
+	encrypted, err := IsEncrypted(boltDB)
+	require.NoError(t, err)
+	require.True(t, encrypted)
+}
+
+// TestEncrypt ensures that an existing database is encrypted by copying it.
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	src := newBoltDB(t)
+	fillDB(t, src)
+
+	dst := newBoltDB(t)
+	db, err := Encrypt(dst, src, testPassphrase)
+	require.NoError(t, err)
+	checkDB(t, db)
+	checkEncrypted(t, dst)
+
+	// An encrypted database isn't encrypted again.
+	_, err = Encrypt(newBoltDB(t), dst, testPassphrase)
+	require.ErrorIs(t, err, ErrAlreadyEncrypted)
+}
+
+// TestChangePassphrase ensures that an encrypted database is opened with its
+// new passphrase once it's changed.
+func TestChangePassphrase(t *testing.T) {
+	t.Parallel()
+
+	boltDB := newBoltDB(t)
+	db, err := walletdb.Create(dbType, boltDB, testPassphrase)
+	require.NoError(t, err)
+	fillDB(t, db)
+
+	newPassphrase := []byte("new")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return ChangePassphrase(tx, newPassphrase, newPassphrase)
+	})
+	require.ErrorIs(t, err, snacl.ErrInvalidPassword)
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return ChangePassphrase(tx, testPassphrase, newPassphrase)
+	})
+	require.NoError(t, err)
+
+	_, err = walletdb.Open(dbType, boltDB, testPassphrase)
+	require.ErrorIs(t, err, snacl.ErrInvalidPassword)
+
+	db, err = walletdb.Open(dbType, boltDB, newPassphrase)
+	require.NoError(t, err)
+	checkDB(t, db)
+
+	// Transactions of other databases are left alone.
+	err = walletdb.Update(boltDB, func(tx walletdb.ReadWriteTx) error {
+		return ChangePassphrase(tx, newPassphrase, testPassphrase)
+	})
+	require.NoError(t, err)
+}
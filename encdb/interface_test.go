@@ -0,0 +1,26 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encdb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/walletdb/walletdbtest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInterface performs all interfaces tests for this database driver.
+func TestInterface(t *testing.T) {
+	boltDB, err := walletdb.Create(
+		"bdb", filepath.Join(t.TempDir(), "db"), true, 10*time.Second,
+	)
+	require.NoError(t, err)
+
+	walletdbtest.TestInterface(t, dbType, boltDB, []byte("passphrase"))
+}
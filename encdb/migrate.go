@@ -0,0 +1,76 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encdb
+
+import (
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// sequencer is implemented by the buckets which expose their sequence.
+type sequencer interface {
+	Sequence() uint64
+}
+
+// Encrypt creates an encrypted database in dst, which must be empty, copies the
+// contents of the unencrypted database src into it and returns it.  The source
+// database is left untouched: once the copy is verified, it's up to the caller
+// to remove it, since its plaintext contents can't be erased in place.
+func Encrypt(dst, src walletdb.DB, passphrase []byte) (walletdb.DB, error) {
+	encrypted, err := IsEncrypted(src)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted {
+		return nil, ErrAlreadyEncrypted
+	}
+
+	db, err := create(dst, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	err = walletdb.View(src, func(srcTx walletdb.ReadTx) error {
+		return walletdb.Update(db, func(dstTx walletdb.ReadWriteTx) error {
+			return srcTx.ForEachBucket(func(key []byte) error {
+				dstBucket, err := dstTx.CreateTopLevelBucket(key)
+				if err != nil {
+					return err
+				}
+
+				return copyBucket(dstBucket, srcTx.ReadBucket(key))
+			})
+		})
+	})
+	if err != nil {
+		db.keys.zero()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// copyBucket copies the contents of a bucket to another bucket, recursively.
+func copyBucket(dst walletdb.ReadWriteBucket, src walletdb.ReadBucket) error {
+	err := src.ForEach(func(k, v []byte) error {
+		srcNested := src.NestedReadBucket(k)
+		if srcNested == nil {
+			return dst.Put(k, v)
+		}
+
+		nested, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(nested, srcNested)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s, ok := src.(sequencer); ok {
+		return dst.SetSequence(s.Sequence())
+	}
+	return nil
+}
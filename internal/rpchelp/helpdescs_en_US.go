@@ -45,6 +45,44 @@ var helpDescsEnUS = map[string]string{
 	"getaddressesbyaccount-account":   "Account name to fetch addresses for",
 	"getaddressesbyaccount--result0":  "All addresses controlled by 'account'",
 
+	// GetAddressInfoCmd help.
+	"getaddressinfo--synopsis": "Returns the script, derivation and wallet details of an address.\n" +
+		"Wallet specific fields are only set if the address is known to the wallet.\n" +
+		"The embedded script of a script address is left unset if the wallet is locked since the script cannot be decrypted.",
+	"getaddressinfo-address": "The address to return information for",
+
+	// GetAddressInfoResult help.
+	"getaddressinforesult-address":             "The payment address",
+	"getaddressinforesult-scriptPubKey":        "The hex-encoded output script of the address",
+	"getaddressinforesult-ismine":              "Whether the wallet holds the private keys of the address",
+	"getaddressinforesult-iswatchonly":         "Whether the address belongs to a watch-only account",
+	"getaddressinforesult-solvable":            "Whether the wallet knows how to spend outputs paid to the address, ignoring the lack of private keys",
+	"getaddressinforesult-isscript":            "Whether the address is a pay-to-script-hash or pay-to-witness-script-hash address",
+	"getaddressinforesult-ischange":            "Whether the address is from the internal branch used for change outputs",
+	"getaddressinforesult-iswitness":           "Whether the address is a segwit address",
+	"getaddressinforesult-witness_version":     "The witness version of a segwit address",
+	"getaddressinforesult-witness_program":     "The hex-encoded witness program of a segwit address",
+	"getaddressinforesult-script":              "The class of the output script",
+	"getaddressinforesult-pubkey":              "The hex-encoded public key of the address, if any",
+	"getaddressinforesult-iscompressed":        "Whether the public key of the address is compressed, if any",
+	"getaddressinforesult-hdkeypath":           "The BIP0032 derivation path of the key, unset for imported keys",
+	"getaddressinforesult-hdmasterfingerprint": "The fingerprint of the master key the key was derived from, if known",
+	"getaddressinforesult-account":             "The account the address belongs to",
+	"getaddressinforesult-keyscope":            "The key scope of the account the address belongs to",
+	"getaddressinforesult-used":                "Whether the address has been used in a transaction",
+	"getaddressinforesult-label":               "The label of the address",
+	"getaddressinforesult-embedded":            "The script embedded in a script address",
+
+	// GetAddressInfoEmbedded help.
+	"getaddressinfoembedded-address":      "The segwit address nested in a pay-to-script-hash address",
+	"getaddressinfoembedded-script":       "The class of the embedded script",
+	"getaddressinfoembedded-hex":          "The hex-encoded embedded script, or the revealed tapscript leaf",
+	"getaddressinfoembedded-pubkeys":      "The public keys of a multisig script",
+	"getaddressinfoembedded-sigsrequired": "The number of signatures required by a multisig script",
+	"getaddressinfoembedded-internalkey":  "The hex-encoded taproot internal key",
+	"getaddressinfoembedded-roothash":     "The hex-encoded root hash of the taproot script tree",
+	"getaddressinfoembedded-leaves":       "The hex-encoded scripts of all taproot script tree leaves",
+
 	// GetBalanceCmd help.
 	"getbalance--synopsis":   "Calculates and returns the balance of one or all accounts.",
 	"getbalance-minconf":     "Minimum number of block confirmations required before an unspent output's value is included in the balance",
@@ -143,6 +181,25 @@ var helpDescsEnUS = map[string]string{
 	"gettransactiondetailsresult-vout":              "The transaction output index",
 	"gettransactiondetailsresult-involveswatchonly": "Unset",
 
+	// GetWalletInfoCmd help.
+	"getwalletinfo--synopsis": "Returns the state of the wallet and the key derivation parameters of its passphrases.",
+
+	// GetWalletInfoResult help.
+	"getwalletinforesult-walletversion":        "The version of the address manager",
+	"getwalletinforesult-private_keys_enabled": "Whether the wallet holds private keys, false for a watching-only wallet",
+	"getwalletinforesult-unlocked":             "Whether the wallet is unlocked",
+	"getwalletinforesult-publicpassphrasekdf":  "The key derivation parameters of the public passphrase",
+	"getwalletinforesult-privatepassphrasekdf": "The key derivation parameters of the private passphrase, unset for a watching-only wallet",
+
+	// PassphraseKDF help.
+	"passphrasekdf-algorithm": `The key derivation function: "scrypt" or "argon2id"`,
+	"passphrasekdf-n":         "The scrypt CPU/memory cost",
+	"passphrasekdf-r":         "The scrypt block size",
+	"passphrasekdf-p":         "The scrypt parallelization",
+	"passphrasekdf-time":      "The number of Argon2id passes",
+	"passphrasekdf-memory":    "The Argon2id memory in KiB",
+	"passphrasekdf-threads":   "The number of Argon2id threads",
+
 	// ImportPrivKeyCmd help.
 	"importprivkey--synopsis": "Imports a WIF-encoded private key to the 'imported' account.",
 	"importprivkey-privkey":   "The WIF-encoded private key",
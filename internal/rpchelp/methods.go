@@ -7,7 +7,10 @@
 
 package rpchelp
 
-import "github.com/btcsuite/btcd/btcjson"
+import (
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcwallet/internal/walletjson"
+)
 
 // Common return types.
 var (
@@ -30,6 +33,7 @@ var Methods = []struct {
 	{"getaccount", returnsString},
 	{"getaccountaddress", returnsString},
 	{"getaddressesbyaccount", returnsStringArray},
+	{"getaddressinfo", []interface{}{(*walletjson.GetAddressInfoResult)(nil)}},
 	{"getbalance", append(returnsNumber, returnsNumber[0])},
 	{"getbestblockhash", returnsString},
 	{"getblockcount", returnsNumber},
@@ -39,6 +43,7 @@ var Methods = []struct {
 	{"getreceivedbyaccount", returnsNumber},
 	{"getreceivedbyaddress", returnsNumber},
 	{"gettransaction", []interface{}{(*btcjson.GetTransactionResult)(nil)}},
+	{"getwalletinfo", []interface{}{(*walletjson.GetWalletInfoResult)(nil)}},
 	{"help", append(returnsString, returnsString[0])},
 	{"importprivkey", nil},
 	{"keypoolrefill", nil},
@@ -0,0 +1,67 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package walletjson defines the result types of btcwallet specific JSON-RPC
+// methods, or of methods for which btcjson does not provide a suitable type.
+package walletjson
+
+// GetAddressInfoResult models the data returned from the getaddressinfo
+// command.
+type GetAddressInfoResult struct {
+	Address             string                  `json:"address"`
+	ScriptPubKey        string                  `json:"scriptPubKey"`
+	IsMine              bool                    `json:"ismine"`
+	IsWatchOnly         bool                    `json:"iswatchonly"`
+	Solvable            bool                    `json:"solvable"`
+	IsScript            bool                    `json:"isscript"`
+	IsChange            bool                    `json:"ischange"`
+	IsWitness           bool                    `json:"iswitness"`
+	WitnessVersion      *int32                  `json:"witness_version,omitempty"`
+	WitnessProgram      string                  `json:"witness_program,omitempty"`
+	Script              string                  `json:"script"`
+	PubKey              string                  `json:"pubkey,omitempty"`
+	IsCompressed        *bool                   `json:"iscompressed,omitempty"`
+	HDKeyPath           string                  `json:"hdkeypath,omitempty"`
+	HDMasterFingerprint string                  `json:"hdmasterfingerprint,omitempty"`
+	Account             string                  `json:"account,omitempty"`
+	KeyScope            string                  `json:"keyscope,omitempty"`
+	Used                bool                    `json:"used"`
+	Label               string                  `json:"label"`
+	Embedded            *GetAddressInfoEmbedded `json:"embedded,omitempty"`
+}
+
+// GetAddressInfoEmbedded models the script embedded in a P2SH, P2WSH or
+// taproot script address as returned by the getaddressinfo command.
+type GetAddressInfoEmbedded struct {
+	Address      string   `json:"address,omitempty"`
+	Script       string   `json:"script"`
+	Hex          string   `json:"hex,omitempty"`
+	PubKeys      []string `json:"pubkeys,omitempty"`
+	SigsRequired int32    `json:"sigsrequired,omitempty"`
+	InternalKey  string   `json:"internalkey,omitempty"`
+	RootHash     string   `json:"roothash,omitempty"`
+	Leaves       []string `json:"leaves,omitempty"`
+}
+
+// GetWalletInfoResult models the data returned from the getwalletinfo command.
+type GetWalletInfoResult struct {
+	WalletVersion        int            `json:"walletversion"`
+	PrivateKeysEnabled   bool           `json:"private_keys_enabled"`
+	Unlocked             bool           `json:"unlocked"`
+	PublicPassphraseKDF  *PassphraseKDF `json:"publicpassphrasekdf"`
+	PrivatePassphraseKDF *PassphraseKDF `json:"privatepassphrasekdf,omitempty"`
+}
+
+// PassphraseKDF models the key derivation parameters of a passphrase as
+// returned by the getwalletinfo command.  Only the parameters of the algorithm
+// in use are set.
+type PassphraseKDF struct {
+	Algorithm string `json:"algorithm"`
+	N         int    `json:"n,omitempty"`
+	R         int    `json:"r,omitempty"`
+	P         int    `json:"p,omitempty"`
+	Time      uint32 `json:"time,omitempty"`
+	Memory    uint32 `json:"memory,omitempty"`
+	Threads   uint8  `json:"threads,omitempty"`
+}
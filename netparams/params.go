@@ -15,22 +15,46 @@ type Params struct {
 	*chaincfg.Params
 	RPCClientPort string
 	RPCServerPort string
+
+	// BitcoindRPCPort is the default RPC port of a bitcoind node on the
+	// network, or empty if bitcoind does not support the network.
+	BitcoindRPCPort string
 }
 
 // MainNetParams contains parameters specific running btcwallet and
 // btcd on the main network (wire.MainNet).
 var MainNetParams = Params{
-	Params:        &chaincfg.MainNetParams,
-	RPCClientPort: "8334",
-	RPCServerPort: "8332",
+	Params:          &chaincfg.MainNetParams,
+	RPCClientPort:   "8334",
+	RPCServerPort:   "8332",
+	BitcoindRPCPort: "8332",
 }
 
 // TestNet3Params contains parameters specific running btcwallet and
 // btcd on the test network (version 3) (wire.TestNet3).
 var TestNet3Params = Params{
-	Params:        &chaincfg.TestNet3Params,
-	RPCClientPort: "18334",
-	RPCServerPort: "18332",
+	Params:          &chaincfg.TestNet3Params,
+	RPCClientPort:   "18334",
+	RPCServerPort:   "18332",
+	BitcoindRPCPort: "18332",
+}
+
+// TestNet4Params contains parameters specific running btcwallet and
+// btcd on the test network (version 4) (TestNet4Wire).
+var TestNet4Params = Params{
+	Params:          &testNet4ChainParams,
+	RPCClientPort:   "48334",
+	RPCServerPort:   "48332",
+	BitcoindRPCPort: "48332",
+}
+
+// RegressionNetParams contains parameters specific to the regression test
+// network (wire.TestNet).
+var RegressionNetParams = Params{
+	Params:          &chaincfg.RegressionNetParams,
+	RPCClientPort:   "18334",
+	RPCServerPort:   "18332",
+	BitcoindRPCPort: "18443",
 }
 
 // SimNetParams contains parameters specific to the simulation test network
@@ -44,9 +68,10 @@ var SimNetParams = Params{
 // SigNetParams contains parameters specific to the signet test network
 // (wire.SigNet).
 var SigNetParams = Params{
-	Params:        &chaincfg.SigNetParams,
-	RPCClientPort: "38334",
-	RPCServerPort: "38332",
+	Params:          &chaincfg.SigNetParams,
+	RPCClientPort:   "38334",
+	RPCServerPort:   "38332",
+	BitcoindRPCPort: "38332",
 }
 
 // SigNetWire is a helper function that either returns the given chain
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netparams
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TestNet4Wire is the network magic of the test network (version 4).  It is
+// not yet defined by the wire package.
+const TestNet4Wire wire.BitcoinNet = 0x283f161c
+
+// testNet4GenesisCoinbaseTx is the coinbase transaction of the genesis block
+// of the test network (version 4).
+var testNet4GenesisCoinbaseTx = wire.MsgTx{
+	Version: 1,
+	TxIn: []*wire.TxIn{{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{},
+			Index: 0xffffffff,
+		},
+		SignatureScript: append([]byte{
+			0x04, 0xff, 0xff, 0x00, 0x1d, 0x01, 0x04, 0x4c, 0x4c,
+		}, "03/May/2024 000000000000000000001ebd58c244970b3aa9d783bb"+
+			"001011fbe8ea8e98e00e"...),
+		Sequence: 0xffffffff,
+	}},
+	TxOut: []*wire.TxOut{{
+		Value: 0x12a05f200,
+		PkScript: append(append([]byte{0x21}, make([]byte, 33)...),
+			0xac),
+	}},
+	LockTime: 0,
+}
+
+// testNet4GenesisMerkleRoot is the hash of the only transaction in the genesis
+// block of the test network (version 4).
+var testNet4GenesisMerkleRoot = chainhash.Hash([chainhash.HashSize]byte{
+	0x4e, 0x7b, 0x2b, 0x91, 0x28, 0xfe, 0x02, 0x91,
+	0xdb, 0x06, 0x93, 0xaf, 0x2a, 0xe4, 0x18, 0xb7,
+	0x67, 0xe6, 0x57, 0xcd, 0x40, 0x7e, 0x80, 0xcb,
+	0x14, 0x34, 0x22, 0x1e, 0xae, 0xa7, 0xa0, 0x7a,
+})
+
+// testNet4GenesisHash is the hash of the genesis block of the test network
+// (version 4).
+var testNet4GenesisHash = chainhash.Hash([chainhash.HashSize]byte{
+	0x43, 0xf0, 0x8b, 0xda, 0xb0, 0x50, 0xe3, 0x5b,
+	0x56, 0x7c, 0x86, 0x4b, 0x91, 0xf4, 0x7f, 0x50,
+	0xae, 0x72, 0x5a, 0xe2, 0xde, 0x53, 0xbc, 0xfb,
+	0xba, 0xf2, 0x84, 0xda, 0x00, 0x00, 0x00, 0x00,
+})
+
+// testNet4GenesisBlock is the genesis block of the test network (version 4).
+var testNet4GenesisBlock = wire.MsgBlock{
+	Header: wire.BlockHeader{
+		Version:    1,
+		PrevBlock:  chainhash.Hash{},
+		MerkleRoot: testNet4GenesisMerkleRoot,
+		Timestamp:  time.Unix(1714777860, 0), // 2024-05-03 23:11:00 UTC
+		Bits:       0x1d00ffff,
+		Nonce:      393743547,
+	},
+	Transactions: []*wire.MsgTx{&testNet4GenesisCoinbaseTx},
+}
+
+// testNet4ChainParams are the chain parameters of the test network (version
+// 4).  Address encodings and HD key IDs are shared with the other test
+// networks, so the parameters are derived from those of testnet3.
+//
+// The difficulty rules of BIP 94, which retarget from the first block of a
+// period rather than a minimum difficulty block and limit the timestamp of
+// the first block of a period, can't be expressed by chaincfg.Params.  Block
+// headers validated with these parameters are therefore rejected after a
+// minimum difficulty block, so only the RPC backends, which rely on the
+// node to validate the chain, support testnet4.
+var testNet4ChainParams = func() chaincfg.Params {
+	params := chaincfg.TestNet3Params
+	params.Name = "testnet4"
+	params.Net = TestNet4Wire
+	params.DefaultPort = "48333"
+	params.DNSSeeds = []chaincfg.DNSSeed{
+		{Host: "seed.testnet4.bitcoin.sprovoost.nl", HasFiltering: true},
+		{Host: "seed.testnet4.wiz.biz", HasFiltering: true},
+	}
+	params.GenesisBlock = &testNet4GenesisBlock
+	params.GenesisHash = &testNet4GenesisHash
+	params.BIP0034Height = 1
+	params.BIP0065Height = 1
+	params.BIP0066Height = 1
+	params.Checkpoints = nil
+	return params
+}()
+
+func init() {
+	// Register the network so addresses and extended keys are recognized
+	// by packages that look up registered networks.  A btcd release that
+	// defines testnet4 itself registers the same magic first.
+	err := chaincfg.Register(&testNet4ChainParams)
+	if err != nil && !errors.Is(err, chaincfg.ErrDuplicateNet) {
+		panic("failed to register testnet4: " + err.Error())
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netparams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTestNet4Genesis tests that the genesis block of testnet4 hashes to the
+// well known genesis hash.
+func TestTestNet4Genesis(t *testing.T) {
+	t.Parallel()
+
+	block := TestNet4Params.GenesisBlock
+	require.Equal(t, "7aa0a7ae1e223414cb807e40cd57e667b718e42aaf9306db9102fe28912b7b4e",
+		block.Transactions[0].TxHash().String())
+	require.Equal(t, block.Header.MerkleRoot, block.Transactions[0].TxHash())
+	require.Equal(t, "00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043",
+		block.BlockHash().String())
+	require.Equal(t, *TestNet4Params.GenesisHash, block.BlockHash())
+}
@@ -0,0 +1,484 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// Role is the authorization role of a legacy RPC user.  The role determines
+// which RPC methods the user may call.
+type Role uint8
+
+// These constants define the roles of legacy RPC users.
+const (
+	// RoleReadOnly may query balances, addresses and transaction history.
+	RoleReadOnly Role = iota
+
+	// RoleInvoicing may only generate new receiving addresses.
+	RoleInvoicing
+
+	// RoleSpender may query the wallet, generate receiving addresses and
+	// send funds, subject to a daily limit.  Unlocking the wallet requires
+	// the admin role, as the spender could otherwise unlock it for every
+	// user.
+	RoleSpender
+
+	// RoleAdmin may call every method.
+	RoleAdmin
+)
+
+var roleStrings = map[Role]string{
+	RoleReadOnly:  "readonly",
+	RoleInvoicing: "invoicing",
+	RoleSpender:   "spender",
+	RoleAdmin:     "admin",
+}
+
+// String returns the name of the role as used in the config file.
+func (r Role) String() string {
+	if s, ok := roleStrings[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown Role (%d)", uint8(r))
+}
+
+// ParseRole parses a role name.
+func ParseRole(s string) (Role, error) {
+	for role, name := range roleStrings {
+		if s == name {
+			return role, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown RPC role %q", s)
+}
+
+// permission describes the class of an RPC method for authorization.  The
+// zero value requires the admin role, so methods must explicitly opt in to be
+// callable by other roles.
+type permission uint8
+
+const (
+	permAdmin permission = iota
+	permPublic
+	permRead
+	permInvoice
+	permSend
+)
+
+// allows returns whether a role may call methods of the given permission
+// class.
+func (r Role) allows(p permission) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleSpender:
+		return p != permAdmin
+	case RoleInvoicing:
+		return p == permPublic || p == permInvoice
+	case RoleReadOnly:
+		return p == permPublic || p == permRead
+	default:
+		return false
+	}
+}
+
+// spendWindow is the duration over which the amounts sent by a spender are
+// summed and compared against their daily limit.
+const spendWindow = 24 * time.Hour
+
+// User describes the credentials and role of a legacy RPC user.
+type User struct {
+	Name string
+	Role Role
+
+	// Salt and Hash are the hex encoded salt and the HMAC-SHA256 of the
+	// user's password keyed by the salt.
+	Salt string
+	Hash []byte
+
+	// DailyLimit is the maximum amount a spender may send within 24
+	// hours.  It is only used by the spender role.
+	DailyLimit btcutil.Amount
+}
+
+// HashPassword creates a random salt and returns it together with the hex
+// encoded HMAC-SHA256 of the password.  The result is used in the
+// USERNAME:ROLE:SALT$HASH[:DAILYLIMIT] format parsed by ParseUser.
+func HashPassword(password string) (salt, hash string, err error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", "", err
+	}
+	salt = hex.EncodeToString(b[:])
+	return salt, hex.EncodeToString(passwordHMAC(salt, password)), nil
+}
+
+// NewUser returns a user with the given role authenticated by a plaintext
+// password.  The password itself is not retained.
+func NewUser(name, password string, role Role) (*User, error) {
+	salt, hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	h, _ := hex.DecodeString(hash)
+	return &User{Name: name, Role: role, Salt: salt, Hash: h}, nil
+}
+
+func passwordHMAC(salt, password string) []byte {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+// ParseUser parses the credentials of a user in the
+// USERNAME:ROLE:SALT$HASH[:DAILYLIMIT] format, where the daily limit is
+// denominated in bitcoin and is required for, and only allowed with, the
+// spender role.
+func ParseUser(s string) (*User, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, errors.New("RPC user must be in the form " +
+			"USERNAME:ROLE:SALT$HASH[:DAILYLIMIT]")
+	}
+	if parts[0] == "" {
+		return nil, errors.New("RPC user has an empty username")
+	}
+	role, err := ParseRole(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	salt, hash, ok := strings.Cut(parts[2], "$")
+	if !ok || salt == "" {
+		return nil, fmt.Errorf("RPC user %s: password must be in the "+
+			"form SALT$HASH", parts[0])
+	}
+	h, err := hex.DecodeString(hash)
+	if err != nil || len(h) != sha256.Size {
+		return nil, fmt.Errorf("RPC user %s: invalid password hash",
+			parts[0])
+	}
+
+	user := &User{Name: parts[0], Role: role, Salt: salt, Hash: h}
+	switch {
+	case role == RoleSpender && len(parts) != 4:
+		return nil, fmt.Errorf("RPC user %s: spender role requires a "+
+			"daily limit", parts[0])
+	case role != RoleSpender && len(parts) == 4:
+		return nil, fmt.Errorf("RPC user %s: daily limit is only "+
+			"allowed for the spender role", parts[0])
+	case len(parts) == 4:
+		btc, err := strconv.ParseFloat(parts[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("RPC user %s: invalid daily "+
+				"limit: %v", parts[0], err)
+		}
+		limit, err := btcutil.NewAmount(btc)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("RPC user %s: invalid daily "+
+				"limit %s", parts[0], parts[3])
+		}
+		user.DailyLimit = limit
+	}
+
+	return user, nil
+}
+
+// checkPassword returns whether the password matches the user's password
+// hash.  This check is time-constant.
+func (u *User) checkPassword(password string) bool {
+	h := passwordHMAC(u.Salt, password)
+	return subtle.ConstantTimeCompare(h, u.Hash) == 1
+}
+
+// spendNamespaceKey is the top level bucket of the wallet database that
+// stores the amounts sent by spenders, so their daily limits survive a
+// restart.  It holds a nested bucket for each user that maps the time and
+// sequence number of each spend to its amount.
+var spendNamespaceKey = []byte("legacyrpcspends")
+
+// spendRecord records an amount sent by a user.
+type spendRecord struct {
+	key    []byte
+	time   time.Time
+	amount btcutil.Amount
+}
+
+// spendTracker tracks the amounts sent by each spender to enforce their
+// daily limits.  Once a wallet is loaded, the records are stored in its
+// database as well.
+type spendTracker struct {
+	mu     sync.Mutex
+	db     walletdb.DB
+	seq    uint32
+	spends map[string][]*spendRecord
+}
+
+func newSpendTracker() *spendTracker {
+	return &spendTracker{spends: make(map[string][]*spendRecord)}
+}
+
+// spendKey returns the database key of a spend record.
+func spendKey(t time.Time, seq uint32) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	binary.BigEndian.PutUint32(k[8:], seq)
+	return k
+}
+
+func putSpendRecord(ns walletdb.ReadWriteBucket, name string,
+	r *spendRecord) error {
+
+	bucket, err := ns.CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return err
+	}
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(r.amount))
+	return bucket.Put(r.key, v[:])
+}
+
+func deleteSpendRecord(ns walletdb.ReadWriteBucket, name string,
+	r *spendRecord) error {
+
+	bucket := ns.NestedReadWriteBucket([]byte(name))
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Delete(r.key)
+}
+
+// setDB stores the records of the tracker in a wallet database, replacing
+// them with the records previously stored there.  The records made before the
+// first database is set are added to it.
+func (t *spendTracker) setDB(db walletdb.DB) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spends := make(map[string][]*spendRecord)
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns, err := tx.CreateTopLevelBucket(spendNamespaceKey)
+		if err != nil {
+			return err
+		}
+
+		err = ns.ForEach(func(name, v []byte) error {
+			bucket := ns.NestedReadBucket(name)
+			if v != nil || bucket == nil {
+				return nil
+			}
+			return bucket.ForEach(func(k, v []byte) error {
+				if len(k) != 12 || len(v) != 8 {
+					return fmt.Errorf("invalid spend record "+
+						"of RPC user %s", name)
+				}
+				nanos := int64(binary.BigEndian.Uint64(k))
+				amount := binary.BigEndian.Uint64(v)
+				spends[string(name)] = append(
+					spends[string(name)], &spendRecord{
+						key:    append([]byte(nil), k...),
+						time:   time.Unix(0, nanos),
+						amount: btcutil.Amount(amount),
+					},
+				)
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		if t.db != nil {
+			return nil
+		}
+		for name, records := range t.spends {
+			for _, r := range records {
+				err := putSpendRecord(ns, name, r)
+				if err != nil {
+					return err
+				}
+				spends[name] = append(spends[name], r)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	t.db = db
+	t.spends = spends
+	return nil
+}
+
+// reserve records an amount sent by a user if it does not exceed the user's
+// limit within the last 24 hours.  The returned function removes the record
+// again and must be called if the send fails.
+func (t *spendTracker) reserve(user *User, amount btcutil.Amount,
+	now time.Time) (func(), error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Prune records that are outside the window.
+	var spent btcutil.Amount
+	var kept, pruned []*spendRecord
+	for _, r := range t.spends[user.Name] {
+		if now.Sub(r.time) < spendWindow {
+			kept = append(kept, r)
+			spent += r.amount
+		} else {
+			pruned = append(pruned, r)
+		}
+	}
+
+	if spent+amount > user.DailyLimit {
+		return nil, fmt.Errorf("sending %v exceeds the daily limit of "+
+			"%v (%v already sent in the last 24 hours)", amount,
+			user.DailyLimit, spent)
+	}
+
+	t.seq++
+	record := &spendRecord{
+		key:    spendKey(now, t.seq),
+		time:   now,
+		amount: amount,
+	}
+	if t.db != nil {
+		err := walletdb.Update(t.db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(spendNamespaceKey)
+			for _, r := range pruned {
+				err := deleteSpendRecord(ns, user.Name, r)
+				if err != nil {
+					return err
+				}
+			}
+			return putSpendRecord(ns, user.Name, record)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to record the amount "+
+				"sent: %w", err)
+		}
+	}
+	t.spends[user.Name] = append(kept, record)
+
+	release := func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		records := t.spends[user.Name]
+		for i, r := range records {
+			if r != record {
+				continue
+			}
+			t.spends[user.Name] = append(
+				records[:i:i], records[i+1:]...,
+			)
+			if t.db == nil {
+				return
+			}
+			err := walletdb.Update(t.db, func(tx walletdb.ReadWriteTx) error {
+				ns := tx.ReadWriteBucket(spendNamespaceKey)
+				return deleteSpendRecord(ns, user.Name, record)
+			})
+			if err != nil {
+				log.Errorf("Unable to remove the amount sent "+
+					"by RPC user %s: %v", user.Name, err)
+			}
+			return
+		}
+	}
+	return release, nil
+}
+
+// sendAmount returns the total amount sent by a send command, excluding fees.
+func sendAmount(cmd interface{}) (btcutil.Amount, error) {
+	switch cmd := cmd.(type) {
+	case *btcjson.SendToAddressCmd:
+		return btcutil.NewAmount(cmd.Amount)
+	case *btcjson.SendFromCmd:
+		return btcutil.NewAmount(cmd.Amount)
+	case *btcjson.SendManyCmd:
+		var total btcutil.Amount
+		for _, btc := range cmd.Amounts {
+			amt, err := btcutil.NewAmount(btc)
+			if err != nil {
+				return 0, err
+			}
+			total += amt
+		}
+		return total, nil
+	default:
+		return 0, nil
+	}
+}
+
+// ErrMethodNotPermitted is returned when a user calls a method that their role
+// does not permit.
+var ErrMethodNotPermitted = btcjson.RPCError{
+	Code:    btcjson.ErrRPCInvalidRequest.Code,
+	Message: "Method not permitted for the RPC user's role",
+}
+
+// methodPermission returns the permission class of a method.  Methods that
+// are not implemented by the wallet are passed through to the chain server
+// and require the admin role.
+func methodPermission(method string) permission {
+	switch method {
+	case "stop":
+		return permAdmin
+	}
+	handlerData, ok := rpcHandlers[method]
+	if !ok {
+		return permAdmin
+	}
+	return handlerData.perm
+}
+
+// authorize checks that a user may call the method of a request.  For senders
+// with a daily limit, the amount sent by the request is reserved and the
+// returned function must be called to release it if the request fails.
+func (s *Server) authorize(user *User, req *btcjson.Request) (func(), *btcjson.RPCError) {
+	perm := methodPermission(req.Method)
+	if !user.Role.allows(perm) {
+		log.Warnf("RPC user %s with role %v denied access to %s",
+			user.Name, user.Role, req.Method)
+		return nil, &ErrMethodNotPermitted
+	}
+	if perm != permSend || user.Role != RoleSpender {
+		return func() {}, nil
+	}
+
+	cmd, err := btcjson.UnmarshalCmd(req)
+	if err != nil {
+		return nil, btcjson.ErrRPCInvalidRequest
+	}
+	amount, err := sendAmount(cmd)
+	if err != nil {
+		return nil, jsonError(InvalidParameterError{err})
+	}
+	release, err := s.spends.reserve(user, amount, time.Now())
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	return release, nil
+}
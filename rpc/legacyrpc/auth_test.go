@@ -0,0 +1,306 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func testUser(t *testing.T, name, password, role, limit string) string {
+	t.Helper()
+
+	salt, hash, err := HashPassword(password)
+	require.NoError(t, err)
+	s := name + ":" + role + ":" + salt + "$" + hash
+	if limit != "" {
+		s += ":" + limit
+	}
+	return s
+}
+
+// TestParseUser tests parsing of the rpcauth user format.
+func TestParseUser(t *testing.T) {
+	t.Parallel()
+
+	user, err := ParseUser(testUser(t, "alice", "pw", "spender", "0.5"))
+	require.NoError(t, err)
+	require.Equal(t, "alice", user.Name)
+	require.Equal(t, RoleSpender, user.Role)
+	require.Equal(t, btcutil.Amount(50_000_000), user.DailyLimit)
+	require.True(t, user.checkPassword("pw"))
+	require.False(t, user.checkPassword("wrong"))
+
+	invalid := []string{
+		"",
+		"alice:admin",
+		":admin:00$" + strings.Repeat("00", 32),
+		"alice:root:00$" + strings.Repeat("00", 32),
+		"alice:admin:00" + strings.Repeat("00", 32),
+		"alice:admin:00$0011",
+		"alice:admin:00$" + strings.Repeat("zz", 32),
+		testUser(t, "alice", "pw", "spender", ""),
+		testUser(t, "alice", "pw", "readonly", "1"),
+		testUser(t, "alice", "pw", "spender", "lots"),
+		testUser(t, "alice", "pw", "spender", "-1"),
+	}
+	for _, s := range invalid {
+		_, err := ParseUser(s)
+		require.Error(t, err, s)
+	}
+}
+
+// TestRolePermissions tests which method classes each role may call.
+func TestRolePermissions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		role    Role
+		allowed []string
+		denied  []string
+	}{{
+		role:    RoleReadOnly,
+		allowed: []string{"help", "getbalance", "listtransactions"},
+		denied: []string{"getnewaddress", "sendtoaddress",
+			"walletpassphrase", "dumpprivkey", "stop", "getblock"},
+	}, {
+		role:    RoleInvoicing,
+		allowed: []string{"help", "getnewaddress"},
+		denied: []string{"getbalance", "sendtoaddress",
+			"getrawchangeaddress", "stop"},
+	}, {
+		role: RoleSpender,
+		allowed: []string{"getbalance", "getnewaddress",
+			"sendtoaddress", "sendmany", "walletlock"},
+		denied: []string{"walletpassphrase", "dumpprivkey",
+			"signrawtransaction", "importprivkey",
+			"walletpassphrasechange", "stop"},
+	}, {
+		role: RoleAdmin,
+		allowed: []string{"getbalance", "sendtoaddress", "dumpprivkey",
+			"stop", "getblock"},
+	}}
+
+	for _, test := range tests {
+		for _, method := range test.allowed {
+			require.True(t, test.role.allows(methodPermission(method)),
+				"%v %s", test.role, method)
+		}
+		for _, method := range test.denied {
+			require.False(t, test.role.allows(methodPermission(method)),
+				"%v %s", test.role, method)
+		}
+	}
+}
+
+// TestSpendTracker tests that the daily limit of a spender is enforced over a
+// rolling 24 hour window and that released reservations are not counted.
+func TestSpendTracker(t *testing.T) {
+	t.Parallel()
+
+	user := &User{Name: "alice", Role: RoleSpender, DailyLimit: 1000}
+	tracker := newSpendTracker()
+	now := time.Now()
+
+	_, err := tracker.reserve(user, 600, now)
+	require.NoError(t, err)
+	release, err := tracker.reserve(user, 400, now.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = tracker.reserve(user, 1, now.Add(2*time.Hour))
+	require.Error(t, err)
+
+	// A failed send releases its reservation.
+	release()
+	_, err = tracker.reserve(user, 400, now.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	// The first spend leaves the window after 24 hours.
+	_, err = tracker.reserve(user, 600, now.Add(spendWindow-time.Second))
+	require.Error(t, err)
+	_, err = tracker.reserve(user, 600, now.Add(spendWindow))
+	require.NoError(t, err)
+}
+
+// TestSpendTrackerPersistence tests that the amounts sent by a spender are
+// stored in the wallet database and count towards the daily limit after a
+// restart.
+func TestSpendTrackerPersistence(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	db, err := walletdb.Create("bdb", dbPath, true, time.Second*10)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	user := &User{Name: "alice", Role: RoleSpender, DailyLimit: 1000}
+	now := time.Now()
+
+	// A spend made before the wallet is loaded is stored once it is.
+	tracker := newSpendTracker()
+	_, err = tracker.reserve(user, 300, now)
+	require.NoError(t, err)
+	require.NoError(t, tracker.setDB(db))
+	_, err = tracker.reserve(user, 300, now.Add(time.Hour))
+	require.NoError(t, err)
+	release, err := tracker.reserve(user, 400, now.Add(time.Hour))
+	require.NoError(t, err)
+	release()
+
+	tracker = newSpendTracker()
+	require.NoError(t, tracker.setDB(db))
+	_, err = tracker.reserve(user, 401, now.Add(2*time.Hour))
+	require.Error(t, err)
+	_, err = tracker.reserve(user, 400, now.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	// Spends that leave the window are removed from the database.
+	_, err = tracker.reserve(user, 300, now.Add(spendWindow))
+	require.NoError(t, err)
+	tracker = newSpendTracker()
+	require.NoError(t, tracker.setDB(db))
+	require.Len(t, tracker.spends[user.Name], 3)
+}
+
+func testAuthServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var users []*User
+	for _, s := range []string{
+		testUser(t, "reader", "readerpw", "readonly", ""),
+		testUser(t, "spender", "spenderpw", "spender", "0.001"),
+	} {
+		user, err := ParseUser(s)
+		require.NoError(t, err)
+		users = append(users, user)
+	}
+	opts := &Options{
+		Username:            "admin",
+		Password:            "adminpw",
+		Users:               users,
+		MaxPOSTClients:      10,
+		MaxWebsocketClients: 10,
+	}
+	server := NewServer(opts, nil, nil)
+	srv := httptest.NewServer(server.httpServer.Handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func postRPC(t *testing.T, url, username, password string,
+	cmd interface{}) (int, *btcjson.Response) {
+
+	t.Helper()
+
+	body, err := btcjson.MarshalCmd(btcjson.RpcVersion1, 1, cmd)
+	require.NoError(t, err)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.SetBasicAuth(username, password)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return res.StatusCode, nil
+	}
+	var resp btcjson.Response
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+	return res.StatusCode, &resp
+}
+
+func isNotPermitted(resp *btcjson.Response) bool {
+	return resp.Error != nil &&
+		resp.Error.Message == ErrMethodNotPermitted.Message
+}
+
+// TestPOSTAuthorization tests that credentials and roles are enforced for
+// HTTP POST clients.
+func TestPOSTAuthorization(t *testing.T) {
+	t.Parallel()
+
+	srv := testAuthServer(t)
+	getBalance := btcjson.NewGetBalanceCmd(nil, nil)
+	send := btcjson.NewSendToAddressCmd("addr", 0.0005, nil, nil)
+	bigSend := btcjson.NewSendToAddressCmd("addr", 0.002, nil, nil)
+
+	code, _ := postRPC(t, srv.URL, "reader", "wrong", getBalance)
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = postRPC(t, srv.URL, "nobody", "readerpw", getBalance)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	// Without a loaded wallet, permitted requests fail for other reasons.
+	_, resp := postRPC(t, srv.URL, "reader", "readerpw", getBalance)
+	require.NotNil(t, resp.Error)
+	require.False(t, isNotPermitted(resp))
+
+	_, resp = postRPC(t, srv.URL, "reader", "readerpw", send)
+	require.True(t, isNotPermitted(resp))
+
+	_, resp = postRPC(t, srv.URL, "reader", "readerpw",
+		btcjson.NewStopCmd())
+	require.True(t, isNotPermitted(resp))
+
+	_, resp = postRPC(t, srv.URL, "spender", "spenderpw", send)
+	require.NotNil(t, resp.Error)
+	require.False(t, isNotPermitted(resp))
+
+	_, resp = postRPC(t, srv.URL, "spender", "spenderpw", bigSend)
+	require.NotNil(t, resp.Error)
+	require.Contains(t, resp.Error.Message, "daily limit")
+
+	_, resp = postRPC(t, srv.URL, "admin", "adminpw", send)
+	require.NotNil(t, resp.Error)
+	require.False(t, isNotPermitted(resp))
+}
+
+// TestWebsocketAuthorization tests that credentials and roles are enforced for
+// websocket clients authenticating with the authenticate method.
+func TestWebsocketAuthorization(t *testing.T) {
+	t.Parallel()
+
+	srv := testAuthServer(t)
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	call := func(id int, cmd interface{}) *btcjson.Response {
+		body, err := btcjson.MarshalCmd(btcjson.RpcVersion1, id, cmd)
+		require.NoError(t, err)
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, body))
+		_, msg, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var resp btcjson.Response
+		require.NoError(t, json.Unmarshal(msg, &resp))
+		return &resp
+	}
+
+	resp := call(1, btcjson.NewAuthenticateCmd("reader", "readerpw"))
+	require.Nil(t, resp.Error)
+
+	resp = call(2, btcjson.NewGetNewAddressCmd(nil, nil))
+	require.True(t, isNotPermitted(resp))
+
+	resp = call(3, btcjson.NewGetBalanceCmd(nil, nil))
+	require.NotNil(t, resp.Error)
+	require.False(t, isNotPermitted(resp))
+
+	resp = call(4, btcjson.NewStopCmd())
+	require.True(t, isNotPermitted(resp))
+}
@@ -6,9 +6,14 @@ package legacyrpc
 
 // Options contains the required options for running the legacy RPC server.
 type Options struct {
+	// Username and Password, if both set, authenticate a user with the
+	// admin role.
 	Username string
 	Password string
 
+	// Users are additional users authenticated by hashed passwords.
+	Users []*User
+
 	MaxPOSTClients      int64
 	MaxWebsocketClients int64
 }
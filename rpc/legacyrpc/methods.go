@@ -8,6 +8,7 @@ package legacyrpc
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -24,6 +26,8 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/internal/walletjson"
+	"github.com/btcsuite/btcwallet/snacl"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
@@ -61,6 +65,11 @@ var rpcHandlers = map[string]struct {
 	handler          requestHandler
 	handlerWithChain requestHandlerChainRequired
 
+	// perm is the permission class of the method, which determines the
+	// user roles allowed to call it.  Methods without an explicit class
+	// require the admin role.
+	perm permission
+
 	// Function variables cannot be compared against anything but nil, so
 	// use a boolean to record whether help generation is necessary.  This
 	// is used by the tests to ensure that help can be generated for every
@@ -73,47 +82,48 @@ var rpcHandlers = map[string]struct {
 }{
 	// Reference implementation wallet methods (implemented)
 	"addmultisigaddress":     {handler: addMultiSigAddress},
-	"createmultisig":         {handler: createMultiSig},
+	"createmultisig":         {handler: createMultiSig, perm: permRead},
 	"dumpprivkey":            {handler: dumpPrivKey},
-	"getaccount":             {handler: getAccount},
+	"getaccount":             {handler: getAccount, perm: permRead},
 	"getaccountaddress":      {handler: getAccountAddress},
-	"getaddressesbyaccount":  {handler: getAddressesByAccount},
-	"getbalance":             {handler: getBalance},
-	"getbestblockhash":       {handler: getBestBlockHash},
-	"getblockcount":          {handler: getBlockCount},
-	"getinfo":                {handlerWithChain: getInfo},
-	"getnewaddress":          {handler: getNewAddress},
+	"getaddressinfo":         {handler: getAddressInfo, perm: permRead},
+	"getaddressesbyaccount":  {handler: getAddressesByAccount, perm: permRead},
+	"getbalance":             {handler: getBalance, perm: permRead},
+	"getbestblockhash":       {handler: getBestBlockHash, perm: permRead},
+	"getblockcount":          {handler: getBlockCount, perm: permRead},
+	"getinfo":                {handlerWithChain: getInfo, perm: permRead},
+	"getnewaddress":          {handler: getNewAddress, perm: permInvoice},
 	"getrawchangeaddress":    {handler: getRawChangeAddress},
-	"getreceivedbyaccount":   {handler: getReceivedByAccount},
-	"getreceivedbyaddress":   {handler: getReceivedByAddress},
-	"gettransaction":         {handler: getTransaction},
-	"help":                   {handler: helpNoChainRPC, handlerWithChain: helpWithChainRPC},
+	"getreceivedbyaccount":   {handler: getReceivedByAccount, perm: permRead},
+	"getreceivedbyaddress":   {handler: getReceivedByAddress, perm: permRead},
+	"gettransaction":         {handler: getTransaction, perm: permRead},
+	"getwalletinfo":          {handler: getWalletInfo, perm: permRead},
+	"help":                   {handler: helpNoChainRPC, handlerWithChain: helpWithChainRPC, perm: permPublic},
 	"importprivkey":          {handler: importPrivKey},
 	"keypoolrefill":          {handler: keypoolRefill},
-	"listaccounts":           {handler: listAccounts},
-	"listlockunspent":        {handler: listLockUnspent},
-	"listreceivedbyaccount":  {handler: listReceivedByAccount},
-	"listreceivedbyaddress":  {handler: listReceivedByAddress},
-	"listsinceblock":         {handlerWithChain: listSinceBlock},
-	"listtransactions":       {handler: listTransactions},
-	"listunspent":            {handler: listUnspent},
+	"listaccounts":           {handler: listAccounts, perm: permRead},
+	"listlockunspent":        {handler: listLockUnspent, perm: permRead},
+	"listreceivedbyaccount":  {handler: listReceivedByAccount, perm: permRead},
+	"listreceivedbyaddress":  {handler: listReceivedByAddress, perm: permRead},
+	"listsinceblock":         {handlerWithChain: listSinceBlock, perm: permRead},
+	"listtransactions":       {handler: listTransactions, perm: permRead},
+	"listunspent":            {handler: listUnspent, perm: permRead},
 	"lockunspent":            {handler: lockUnspent},
-	"sendfrom":               {handlerWithChain: sendFrom},
-	"sendmany":               {handler: sendMany},
-	"sendtoaddress":          {handler: sendToAddress},
+	"sendfrom":               {handlerWithChain: sendFrom, perm: permSend},
+	"sendmany":               {handler: sendMany, perm: permSend},
+	"sendtoaddress":          {handler: sendToAddress, perm: permSend},
 	"settxfee":               {handler: setTxFee},
 	"signmessage":            {handler: signMessage},
 	"signrawtransaction":     {handlerWithChain: signRawTransaction},
-	"validateaddress":        {handler: validateAddress},
-	"verifymessage":          {handler: verifyMessage},
-	"walletlock":             {handler: walletLock},
+	"validateaddress":        {handler: validateAddress, perm: permRead},
+	"verifymessage":          {handler: verifyMessage, perm: permRead},
+	"walletlock":             {handler: walletLock, perm: permSend},
 	"walletpassphrase":       {handler: walletPassphrase},
 	"walletpassphrasechange": {handler: walletPassphraseChange},
 
 	// Reference implementation methods (still unimplemented)
 	"backupwallet":         {handler: unimplemented, noHelp: true},
 	"dumpwallet":           {handler: unimplemented, noHelp: true},
-	"getwalletinfo":        {handler: unimplemented, noHelp: true},
 	"importwallet":         {handler: unimplemented, noHelp: true},
 	"listaddressgroupings": {handler: unimplemented, noHelp: true},
 
@@ -125,16 +135,16 @@ var rpcHandlers = map[string]struct {
 
 	// Extensions to the reference client JSON-RPC API
 	"createnewaccount": {handler: createNewAccount},
-	"getbestblock":     {handler: getBestBlock},
+	"getbestblock":     {handler: getBestBlock, perm: permRead},
 	// This was an extension but the reference implementation added it as
 	// well, but with a different API (no account parameter).  It's listed
 	// here because it hasn't been update to use the reference
 	// implemenation's API.
-	"getunconfirmedbalance":   {handler: getUnconfirmedBalance},
-	"listaddresstransactions": {handler: listAddressTransactions},
-	"listalltransactions":     {handler: listAllTransactions},
+	"getunconfirmedbalance":   {handler: getUnconfirmedBalance, perm: permRead},
+	"listaddresstransactions": {handler: listAddressTransactions, perm: permRead},
+	"listalltransactions":     {handler: listAllTransactions, perm: permRead},
 	"renameaccount":           {handler: renameAccount},
-	"walletislocked":          {handler: walletIsLocked},
+	"walletislocked":          {handler: walletIsLocked, perm: permRead},
 }
 
 // unimplemented handles an unimplemented RPC request with the
@@ -1951,3 +1961,226 @@ func decodeHexStr(hexStr string) ([]byte, error) {
 	}
 	return decoded, nil
 }
+
+// getAddressInfo handles the getaddressinfo command by returning the script,
+// derivation and wallet specific details of an address.
+func getAddressInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*btcjson.GetAddressInfoCmd)
+
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.GetAddressInfoResult{
+		Address:      addr.EncodeAddress(),
+		ScriptPubKey: hex.EncodeToString(pkScript),
+		Script:       txscript.GetScriptClass(pkScript).String(),
+	}
+	switch addr.(type) {
+	case *btcutil.AddressScriptHash, *btcutil.AddressWitnessScriptHash:
+		result.IsScript = true
+	}
+	if wa, ok := addr.(interface {
+		WitnessVersion() byte
+		WitnessProgram() []byte
+	}); ok {
+		version := int32(wa.WitnessVersion())
+		result.IsWitness = true
+		result.WitnessVersion = &version
+		result.WitnessProgram = hex.EncodeToString(wa.WitnessProgram())
+	}
+
+	details, err := w.AddressDetails(addr)
+	if err != nil {
+		if waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+			// The address is valid but not known to the wallet.
+			return result, nil
+		}
+		return nil, err
+	}
+
+	result.IsMine = !details.WatchOnly
+	result.IsWatchOnly = details.WatchOnly
+	result.IsChange = details.Address.Internal()
+	result.Account = details.AccountName
+	result.KeyScope = details.KeyScope.String()
+	result.Used = details.Used
+	result.Label = details.Label
+
+	switch ma := details.Address.(type) {
+	case waddrmgr.ManagedPubKeyAddress:
+		compressed := ma.Compressed()
+		result.Solvable = true
+		result.PubKey = ma.ExportPubKey()
+		result.IsCompressed = &compressed
+
+		scope, path, ok := ma.DerivationInfo()
+		if ok {
+			result.HDKeyPath = waddrmgr.FormatKeyPath(scope, path)
+			result.HDMasterFingerprint = formatFingerprint(
+				path.MasterKeyFingerprint,
+			)
+		}
+
+		// A nested witness address embeds a pay-to-witness-pubkey-hash
+		// script in its pay-to-script-hash output.
+		if ma.AddrType() == waddrmgr.NestedWitnessPubKey {
+			pubKeyHash := btcutil.Hash160(ma.PubKey().SerializeCompressed())
+			witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+				pubKeyHash, w.ChainParams(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			witnessScript, err := txscript.PayToAddrScript(witnessAddr)
+			if err != nil {
+				return nil, err
+			}
+			result.Embedded = &walletjson.GetAddressInfoEmbedded{
+				Address: witnessAddr.EncodeAddress(),
+				Script:  txscript.WitnessV0PubKeyHashTy.String(),
+				Hex:     hex.EncodeToString(witnessScript),
+			}
+		}
+
+	case waddrmgr.ManagedTaprootScriptAddress:
+		// The tapscript may be encrypted and not be available while the
+		// wallet is locked, in which case nothing further is reported.
+		tapscript, err := ma.TaprootScript()
+		if err != nil {
+			break
+		}
+		result.Solvable = true
+		result.Embedded = tapscriptInfo(tapscript)
+
+	case waddrmgr.ManagedScriptAddress:
+		// The script is only available if the manager is unlocked, so
+		// just break out now if there is an error.
+		script, err := ma.Script()
+		if err != nil {
+			break
+		}
+		result.Solvable = true
+		result.Embedded = embeddedScriptInfo(script, w.ChainParams())
+	}
+
+	return result, nil
+}
+
+// embeddedScriptInfo returns the getaddressinfo details of a redeem or witness
+// script.
+func embeddedScriptInfo(script []byte,
+	params *chaincfg.Params) *walletjson.GetAddressInfoEmbedded {
+
+	embedded := &walletjson.GetAddressInfoEmbedded{
+		Hex: hex.EncodeToString(script),
+	}
+
+	// An imported script that can't be parsed is reported as
+	// non-standard.
+	class, addrs, reqSigs, err := txscript.ExtractPkScriptAddrs(
+		script, params,
+	)
+	if err != nil {
+		embedded.Script = txscript.NonStandardTy.String()
+		return embedded
+	}
+	embedded.Script = class.String()
+
+	if class == txscript.MultiSigTy {
+		embedded.SigsRequired = int32(reqSigs)
+		embedded.PubKeys = make([]string, len(addrs))
+		for i, a := range addrs {
+			embedded.PubKeys[i] = hex.EncodeToString(a.ScriptAddress())
+		}
+	}
+
+	return embedded
+}
+
+// tapscriptInfo returns the getaddressinfo details of a taproot script tree.
+func tapscriptInfo(
+	tapscript *waddrmgr.Tapscript) *walletjson.GetAddressInfoEmbedded {
+
+	embedded := &walletjson.GetAddressInfoEmbedded{
+		Script: txscript.WitnessV1TaprootTy.String(),
+	}
+	if tapscript.ControlBlock != nil &&
+		tapscript.ControlBlock.InternalKey != nil {
+
+		embedded.InternalKey = hex.EncodeToString(
+			schnorr.SerializePubKey(tapscript.ControlBlock.InternalKey),
+		)
+	}
+
+	switch tapscript.Type {
+	case waddrmgr.TapscriptTypeFullTree:
+		embedded.Leaves = make([]string, len(tapscript.Leaves))
+		for i, leaf := range tapscript.Leaves {
+			embedded.Leaves[i] = hex.EncodeToString(leaf.Script)
+		}
+
+	case waddrmgr.TapscriptTypePartialReveal:
+		embedded.Hex = hex.EncodeToString(tapscript.RevealedScript)
+
+	case waddrmgr.TaprootKeySpendRootHash:
+		embedded.RootHash = hex.EncodeToString(tapscript.RootHash)
+	}
+
+	return embedded
+}
+
+// getWalletInfo handles a getwalletinfo request by returning the state of the
+// wallet and the key derivation parameters of its passphrases.
+func getWalletInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	public, private := w.PassphraseKDFs()
+	return &walletjson.GetWalletInfoResult{
+		WalletVersion:        int(waddrmgr.LatestMgrVersion),
+		PrivateKeysEnabled:   !w.Manager.WatchOnly(),
+		Unlocked:             !w.Locked(),
+		PublicPassphraseKDF:  passphraseKDF(public),
+		PrivatePassphraseKDF: passphraseKDF(private),
+	}, nil
+}
+
+// passphraseKDF returns the getwalletinfo description of key derivation
+// options, or nil if there are none.
+func passphraseKDF(opts *waddrmgr.KDFOptions) *walletjson.PassphraseKDF {
+	switch {
+	case opts == nil:
+		return nil
+
+	case opts.Argon2id != nil:
+		return &walletjson.PassphraseKDF{
+			Algorithm: snacl.KDFArgon2id.String(),
+			Time:      opts.Argon2id.Time,
+			Memory:    opts.Argon2id.Memory,
+			Threads:   opts.Argon2id.Threads,
+		}
+
+	default:
+		return &walletjson.PassphraseKDF{
+			Algorithm: snacl.KDFScrypt.String(),
+			N:         opts.N,
+			R:         opts.R,
+			P:         opts.P,
+		}
+	}
+}
+
+// formatFingerprint returns the hex encoding of a master key fingerprint in
+// the byte order used by PSBT derivation paths, or an empty string if the
+// fingerprint is unknown.
+func formatFingerprint(fingerprint uint32) string {
+	if fingerprint == 0 {
+		return ""
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], fingerprint)
+	return hex.EncodeToString(b[:])
+}
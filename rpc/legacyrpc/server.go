@@ -5,9 +5,6 @@
 package legacyrpc
 
 import (
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"io"
@@ -26,6 +23,7 @@ import (
 type websocketClient struct {
 	conn          *websocket.Conn
 	authenticated bool
+	user          *User
 	remoteAddr    string
 	allRequests   chan []byte
 	responses     chan []byte
@@ -33,10 +31,11 @@ type websocketClient struct {
 	wg            sync.WaitGroup
 }
 
-func newWebsocketClient(c *websocket.Conn, authenticated bool, remoteAddr string) *websocketClient {
+func newWebsocketClient(c *websocket.Conn, user *User, remoteAddr string) *websocketClient {
 	return &websocketClient{
 		conn:          c,
-		authenticated: authenticated,
+		authenticated: user != nil,
+		user:          user,
 		remoteAddr:    remoteAddr,
 		allRequests:   make(chan []byte),
 		responses:     make(chan []byte),
@@ -63,7 +62,8 @@ type Server struct {
 	handlerMu    sync.Mutex
 
 	listeners []net.Listener
-	authsha   [sha256.Size]byte
+	users     map[string]*User
+	spends    *spendTracker
 	upgrader  websocket.Upgrader
 
 	maxPostClients      int64 // Max concurrent HTTP POST clients.
@@ -83,7 +83,8 @@ func jsonAuthFail(w http.ResponseWriter) {
 }
 
 // NewServer creates a new server for serving legacy RPC client connections,
-// both HTTP POST and websocket.
+// both HTTP POST and websocket.  The username and password of the options, if
+// set, authenticate an admin user in addition to the configured users.
 func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Listener) *Server {
 	serveMux := http.NewServeMux()
 	const rpcAuthTimeoutSeconds = 10
@@ -100,9 +101,8 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 		maxPostClients:      opts.MaxPOSTClients,
 		maxWebsocketClients: opts.MaxWebsocketClients,
 		listeners:           listeners,
-		// A hash of the HTTP basic auth string is used for a constant
-		// time comparison.
-		authsha: sha256.Sum256(httpBasicAuth(opts.Username, opts.Password)),
+		users:               make(map[string]*User, len(opts.Users)+1),
+		spends:              newSpendTracker(),
 		upgrader: websocket.Upgrader{
 			// Allow all origins.
 			CheckOrigin: func(r *http.Request) bool { return true },
@@ -111,28 +111,41 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 		requestShutdownChan: make(chan struct{}, 1),
 	}
 
+	for _, user := range opts.Users {
+		server.users[user.Name] = user
+	}
+	if opts.Username != "" && opts.Password != "" {
+		admin, err := NewUser(opts.Username, opts.Password, RoleAdmin)
+		if err != nil {
+			// Reading random bytes for the salt is not expected
+			// to fail.
+			panic(err)
+		}
+		server.users[admin.Name] = admin
+	}
+
 	serveMux.Handle("/", throttledFn(opts.MaxPOSTClients,
 		func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Connection", "close")
 			w.Header().Set("Content-Type", "application/json")
 			r.Close = true
 
-			if err := server.checkAuthHeader(r); err != nil {
+			user, err := server.checkAuthHeader(r)
+			if err != nil {
 				log.Warnf("Unauthorized client connection attempt")
 				jsonAuthFail(w)
 				return
 			}
 			server.wg.Add(1)
-			server.postClientRPC(w, r)
+			server.postClientRPC(w, r, user)
 			server.wg.Done()
 		}))
 
 	serveMux.Handle("/ws", throttledFn(opts.MaxWebsocketClients,
 		func(w http.ResponseWriter, r *http.Request) {
-			authenticated := false
-			switch server.checkAuthHeader(r) {
+			user, err := server.checkAuthHeader(r)
+			switch err {
 			case nil:
-				authenticated = true
 			case ErrNoAuth:
 				// nothing
 			default:
@@ -150,7 +163,7 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 					r.RemoteAddr, err)
 				return
 			}
-			wsc := newWebsocketClient(conn, authenticated, r.RemoteAddr)
+			wsc := newWebsocketClient(conn, user, r.RemoteAddr)
 			server.websocketClientRPC(wsc)
 		}))
 
@@ -161,26 +174,6 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 	return server
 }
 
-// httpBasicAuth returns the UTF-8 bytes of the HTTP Basic authentication
-// string:
-//
-//   "Basic " + base64(username + ":" + password)
-func httpBasicAuth(username, password string) []byte {
-	const header = "Basic "
-	base64 := base64.StdEncoding
-
-	b64InputLen := len(username) + len(":") + len(password)
-	b64Input := make([]byte, 0, b64InputLen)
-	b64Input = append(b64Input, username...)
-	b64Input = append(b64Input, ':')
-	b64Input = append(b64Input, password...)
-
-	output := make([]byte, len(header)+base64.EncodedLen(b64InputLen))
-	copy(output, header)
-	base64.Encode(output[len(header):], b64Input)
-	return output
-}
-
 // serve serves HTTP POST and websocket RPC for the legacy JSON-RPC RPC server.
 // This function does not block on lis.Accept.
 func (s *Server) serve(lis net.Listener) {
@@ -199,6 +192,11 @@ func (s *Server) RegisterWallet(w *wallet.Wallet) {
 	s.handlerMu.Lock()
 	s.wallet = w
 	s.handlerMu.Unlock()
+
+	if err := s.spends.setDB(w.Database()); err != nil {
+		log.Errorf("Unable to load the amounts sent by RPC users: %v",
+			err)
+	}
 }
 
 // Stop gracefully shuts down the rpc server by stopping and disconnecting all
@@ -264,11 +262,13 @@ func (s *Server) SetChainServer(chainClient chain.Interface) {
 // handlerClosure creates a closure function for handling requests of the given
 // method.  This may be a request that is handled directly by btcwallet, or
 // a chain server request that is handled by passing the request down to btcd.
+// The returned closure errors without handling the request if the user's role
+// does not permit the method or the request exceeds the user's spend limit.
 //
 // NOTE: These handlers do not handle special cases, such as the authenticate
 // method.  Each of these must be checked beforehand (the method is already
 // known) and handled accordingly.
-func (s *Server) handlerClosure(request *btcjson.Request) lazyHandler {
+func (s *Server) handlerClosure(user *User, request *btcjson.Request) lazyHandler {
 	s.handlerMu.Lock()
 	// With the lock held, make copies of these pointers for the closure.
 	wallet := s.wallet
@@ -279,7 +279,18 @@ func (s *Server) handlerClosure(request *btcjson.Request) lazyHandler {
 	}
 	s.handlerMu.Unlock()
 
-	return lazyApplyHandler(request, wallet, chainClient)
+	handler := lazyApplyHandler(request, wallet, chainClient)
+	return func() (interface{}, *btcjson.RPCError) {
+		release, jsonErr := s.authorize(user, request)
+		if jsonErr != nil {
+			return nil, jsonErr
+		}
+		resp, jsonErr := handler()
+		if jsonErr != nil {
+			release()
+		}
+		return resp, jsonErr
+	}
 }
 
 // ErrNoAuth represents an error where authentication could not succeed
@@ -287,23 +298,35 @@ func (s *Server) handlerClosure(request *btcjson.Request) lazyHandler {
 var ErrNoAuth = errors.New("no auth")
 
 // checkAuthHeader checks the HTTP Basic authentication supplied by a client
-// in the HTTP request r.  It errors with ErrNoAuth if the request does not
-// contain the Authorization header, or another non-nil error if the
-// authentication was provided but incorrect.
+// in the HTTP request r and returns the authenticated user.  It errors with
+// ErrNoAuth if the request does not contain the Authorization header, or
+// another non-nil error if the authentication was provided but incorrect.
 //
-// This check is time-constant.
-func (s *Server) checkAuthHeader(r *http.Request) error {
-	authhdr := r.Header["Authorization"]
-	if len(authhdr) == 0 {
-		return ErrNoAuth
+// The password check is time-constant.
+func (s *Server) checkAuthHeader(r *http.Request) (*User, error) {
+	if len(r.Header["Authorization"]) == 0 {
+		return nil, ErrNoAuth
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, errors.New("bad auth")
+	}
+	user := s.checkCredentials(username, password)
+	if user == nil {
+		return nil, errors.New("bad auth")
 	}
+	return user, nil
+}
 
-	authsha := sha256.Sum256([]byte(authhdr[0]))
-	cmp := subtle.ConstantTimeCompare(authsha[:], s.authsha[:])
-	if cmp != 1 {
-		return errors.New("bad auth")
+// checkCredentials returns the user authenticated by a username and password,
+// or nil if the credentials are incorrect.
+func (s *Server) checkCredentials(username, password string) *User {
+	user, ok := s.users[username]
+	if !ok || !user.checkPassword(password) {
+		return nil
 	}
-	return nil
+	return user
 }
 
 // throttledFn wraps an http.HandlerFunc with throttling of concurrent active
@@ -342,23 +365,20 @@ func idPointer(id interface{}) (p *interface{}) {
 	return
 }
 
-// invalidAuth checks whether a websocket request is a valid (parsable)
+// authenticateUser checks whether a websocket request is a valid (parsable)
 // authenticate request and checks the supplied username and passphrase
-// against the server auth.
-func (s *Server) invalidAuth(req *btcjson.Request) bool {
+// against the server's users.  It returns the authenticated user, or nil if
+// the request or credentials are invalid.
+func (s *Server) authenticateUser(req *btcjson.Request) *User {
 	cmd, err := btcjson.UnmarshalCmd(req)
 	if err != nil {
-		return false
+		return nil
 	}
 	authCmd, ok := cmd.(*btcjson.AuthenticateCmd)
 	if !ok {
-		return false
+		return nil
 	}
-	// Check credentials.
-	login := authCmd.Username + ":" + authCmd.Passphrase
-	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-	authSha := sha256.Sum256([]byte(auth))
-	return subtle.ConstantTimeCompare(authSha[:], s.authsha[:]) != 1
+	return s.checkCredentials(authCmd.Username, authCmd.Passphrase)
 }
 
 func (s *Server) websocketClientRead(wsc *websocketClient) {
@@ -415,11 +435,17 @@ out:
 			}
 
 			if req.Method == "authenticate" {
-				if wsc.authenticated || s.invalidAuth(&req) {
+				if wsc.authenticated {
+					// Disconnect immediately.
+					break out
+				}
+				user := s.authenticateUser(&req)
+				if user == nil {
 					// Disconnect immediately.
 					break out
 				}
 				wsc.authenticated = true
+				wsc.user = user
 				resp := makeResponse(req.ID, nil, nil)
 				// Expected to never fail.
 				mresp, err := json.Marshal(resp)
@@ -440,6 +466,20 @@ out:
 
 			switch req.Method {
 			case "stop":
+				if !wsc.user.Role.allows(permAdmin) {
+					resp := makeResponse(req.ID, nil,
+						&ErrMethodNotPermitted)
+					mresp, err := json.Marshal(resp)
+					// Expected to never fail.
+					if err != nil {
+						panic(err)
+					}
+					err = wsc.send(mresp)
+					if err != nil {
+						break out
+					}
+					continue
+				}
 				resp := makeResponse(req.ID,
 					"btcwallet stopping.", nil)
 				mresp, err := json.Marshal(resp)
@@ -456,7 +496,7 @@ out:
 
 			default:
 				req := req // Copy for the closure
-				f := s.handlerClosure(&req)
+				f := s.handlerClosure(wsc.user, &req)
 				wsc.wg.Add(1)
 				go func() {
 					resp, jsonErr := f()
@@ -545,8 +585,9 @@ func (s *Server) websocketClientRPC(wsc *websocketClient) {
 // that may be read from a client.  This is currently limited to 4MB.
 const maxRequestSize = 1024 * 1024 * 4
 
-// postClientRPC processes and replies to a JSON-RPC client request.
-func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
+// postClientRPC processes and replies to a JSON-RPC client request from an
+// authenticated user.
+func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request, user *User) {
 	body := http.MaxBytesReader(w, r.Body, maxRequestSize)
 	rpcRequest, err := io.ReadAll(body)
 	if err != nil {
@@ -591,10 +632,14 @@ func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
 		// Drop it.
 		return
 	case "stop":
+		if !user.Role.allows(permAdmin) {
+			jsonErr = &ErrMethodNotPermitted
+			break
+		}
 		stop = true
 		res = "btcwallet stopping"
 	default:
-		res, jsonErr = s.handlerClosure(&req)()
+		res, jsonErr = s.handlerClosure(user, &req)()
 	}
 
 	// Marshal and send.
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package macaroons
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/macaroon.v2"
+)
+
+// MetadataKey is the gRPC metadata key that clients pass the hex encoded
+// macaroon with.
+const MetadataKey = "macaroon"
+
+// InterceptorConfig describes how the RPCs of a gRPC server are authorized.
+type InterceptorConfig struct {
+	// Permissions maps full gRPC method names to the permission entity
+	// required to call them.  Calls to methods that are not listed here
+	// and are not unauthenticated are always rejected.
+	Permissions map[string]string
+
+	// Unauthenticated is the set of full method names that never require
+	// a macaroon.
+	Unauthenticated map[string]struct{}
+
+	// Bootstrap is the set of full method names that do not require a
+	// macaroon while the service is bootstrapping, that is before a
+	// wallet has first been created or opened.  Once a wallet has been
+	// loaded they always require one, even after the wallet is closed.
+	Bootstrap map[string]struct{}
+
+	// SpendAmount returns the total amount spent by a unary request, and
+	// false if the request does not spend wallet funds.  It may be nil.
+	SpendAmount func(req interface{}) (btcutil.Amount, bool)
+}
+
+// UnaryServerInterceptor returns a gRPC unary interceptor checking the
+// macaroon of every call against the configured permissions.
+func (s *Service) UnaryServerInterceptor(cfg *InterceptorConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		var spends bool
+		var amount btcutil.Amount
+		if cfg.SpendAmount != nil {
+			amount, spends = cfg.SpendAmount(req)
+		}
+		err := s.authorize(ctx, cfg, info.FullMethod, spends, amount)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream interceptor checking the
+// macaroon of every call against the configured permissions.
+func (s *Service) StreamServerInterceptor(cfg *InterceptorConfig) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		err := s.authorize(ss.Context(), cfg, info.FullMethod, false, 0)
+		if err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize checks that the macaroon passed with a call grants the permission
+// required by the method.  The returned error is a gRPC status error.
+func (s *Service) authorize(ctx context.Context, cfg *InterceptorConfig,
+	fullMethod string, spends bool, amount btcutil.Amount) error {
+
+	if _, ok := cfg.Unauthenticated[fullMethod]; ok {
+		return nil
+	}
+	if _, ok := cfg.Bootstrap[fullMethod]; ok && s.Bootstrapping() {
+		return nil
+	}
+	entity, ok := cfg.Permissions[fullMethod]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied,
+			"no permission is defined for method %s", fullMethod)
+	}
+
+	mac, err := macaroonFromContext(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	req := &Request{
+		Entity:      entity,
+		PeerIP:      peerIP(ctx),
+		Spends:      spends,
+		SpendAmount: amount,
+		Now:         time.Now(),
+	}
+	if err := s.Verify(mac, req); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// macaroonFromContext decodes the macaroon passed in the metadata of a gRPC
+// call.
+func macaroonFromContext(ctx context.Context) (*macaroon.Macaroon, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(MetadataKey)) != 1 {
+		return nil, errMissingMacaroon
+	}
+	b, err := hex.DecodeString(md.Get(MetadataKey)[0])
+	if err != nil {
+		return nil, err
+	}
+	mac := new(macaroon.Macaroon)
+	if err := mac.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return mac, nil
+}
+
+// peerIP returns the IP address of the client of a gRPC call, or nil if it is
+// unknown.
+func peerIP(ctx context.Context) net.IP {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil
+	}
+	switch addr := p.Addr.(type) {
+	case *net.TCPAddr:
+		return addr.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return nil
+		}
+		return net.ParseIP(host)
+	}
+}
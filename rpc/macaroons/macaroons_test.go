@@ -0,0 +1,334 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package macaroons
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/macaroon.v2"
+)
+
+func testService(t *testing.T) *Service {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	db, err := walletdb.Create("bdb", dbPath, true, time.Second*10)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := NewRootKeyStore(db)
+	require.NoError(t, err)
+
+	svc := NewService("btcwallet")
+	svc.SetRootKeyStore(store)
+	return svc
+}
+
+// TestBakeVerify tests that the caveats of baked macaroons are enforced.
+func TestBakeVerify(t *testing.T) {
+	t.Parallel()
+
+	svc := testService(t)
+	now := time.Now()
+	ip := net.ParseIP("127.0.0.1")
+
+	tests := []struct {
+		name     string
+		entities []string
+		caveats  []string
+		req      Request
+		valid    bool
+	}{{
+		name:     "granted entity",
+		entities: []string{EntityRead, EntitySend},
+		req:      Request{Entity: EntitySend, Now: now},
+		valid:    true,
+	}, {
+		name:     "missing entity",
+		entities: []string{EntityRead},
+		req:      Request{Entity: EntitySign, Now: now},
+	}, {
+		name:     "matching ip",
+		entities: []string{EntityRead},
+		caveats:  []string{IPAddrCaveat(ip)},
+		req:      Request{Entity: EntityRead, PeerIP: ip, Now: now},
+		valid:    true,
+	}, {
+		name:     "other ip",
+		entities: []string{EntityRead},
+		caveats:  []string{IPAddrCaveat(ip)},
+		req: Request{
+			Entity: EntityRead,
+			PeerIP: net.ParseIP("10.0.0.1"),
+			Now:    now,
+		},
+	}, {
+		name:     "not expired",
+		entities: []string{EntityRead},
+		caveats:  []string{TimeBeforeCaveat(now.Add(time.Hour))},
+		req:      Request{Entity: EntityRead, Now: now},
+		valid:    true,
+	}, {
+		name:     "expired",
+		entities: []string{EntityRead},
+		caveats:  []string{TimeBeforeCaveat(now.Add(-time.Hour))},
+		req:      Request{Entity: EntityRead, Now: now},
+	}, {
+		name:     "within spend limit",
+		entities: []string{EntitySend},
+		caveats:  []string{SpendLimitCaveat(1000)},
+		req: Request{
+			Entity:      EntitySend,
+			Spends:      true,
+			SpendAmount: 1000,
+			Now:         now,
+		},
+		valid: true,
+	}, {
+		name:     "above spend limit",
+		entities: []string{EntitySend},
+		caveats:  []string{SpendLimitCaveat(1000)},
+		req: Request{
+			Entity:      EntitySend,
+			Spends:      true,
+			SpendAmount: 1001,
+			Now:         now,
+		},
+	}, {
+		name:     "spend limit ignored when not spending",
+		entities: []string{EntitySend},
+		caveats:  []string{SpendLimitCaveat(0)},
+		req:      Request{Entity: EntitySend, Now: now},
+		valid:    true,
+	}, {
+		name:     "unknown caveat",
+		entities: []string{EntityRead},
+		caveats:  []string{"unknown condition"},
+		req:      Request{Entity: EntityRead, Now: now},
+	}}
+
+	for _, test := range tests {
+		mac, err := svc.Bake(0, test.entities, test.caveats...)
+		require.NoError(t, err, test.name)
+
+		err = svc.Verify(mac, &test.req)
+		if test.valid {
+			require.NoError(t, err, test.name)
+		} else {
+			require.Error(t, err, test.name)
+		}
+	}
+}
+
+// TestBakeInvalidEntity tests that macaroons can only be baked for known
+// permission entities.
+func TestBakeInvalidEntity(t *testing.T) {
+	t.Parallel()
+
+	svc := testService(t)
+
+	_, err := svc.Bake(0, nil)
+	require.Error(t, err)
+
+	_, err = svc.Bake(0, []string{"everything"})
+	require.Error(t, err)
+
+	_, err = NewService("btcwallet").Bake(0, []string{EntityRead})
+	require.ErrorIs(t, err, ErrNoRootKeyStore)
+}
+
+// TestRevokeRootKey tests that revoking a root key invalidates the macaroons
+// baked with it, but not those baked with other root keys.
+func TestRevokeRootKey(t *testing.T) {
+	t.Parallel()
+
+	svc := testService(t)
+	req := &Request{Entity: EntityRead, Now: time.Now()}
+
+	mac0, err := svc.Bake(0, []string{EntityRead})
+	require.NoError(t, err)
+	mac1, err := svc.Bake(1, []string{EntityRead})
+	require.NoError(t, err)
+
+	ids, err := svc.RootKeyStore().RootKeyIDs()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 1}, ids)
+
+	require.NoError(t, svc.RootKeyStore().RevokeRootKey(0))
+	require.ErrorIs(t, svc.Verify(mac0, req), ErrRootKeyNotFound)
+	require.NoError(t, svc.Verify(mac1, req))
+
+	err = svc.RootKeyStore().RevokeRootKey(0)
+	require.ErrorIs(t, err, ErrRootKeyNotFound)
+
+	// Baking with a revoked root key ID creates a new root key, which
+	// does not validate the macaroons baked with the old one.
+	_, err = svc.Bake(0, []string{EntityRead})
+	require.NoError(t, err)
+	require.Error(t, svc.Verify(mac0, req))
+}
+
+// TestRootKeyStoreSnapshot tests that a snapshot of a root key store verifies
+// the macaroons baked with its root keys after the database is closed, and
+// that its root keys can't be changed.
+func TestRootKeyStoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	svc := testService(t)
+	req := &Request{Entity: EntityRead, Now: time.Now()}
+
+	mac0, err := svc.Bake(0, []string{EntityRead})
+	require.NoError(t, err)
+	mac1, err := svc.Bake(1, []string{EntityRead})
+	require.NoError(t, err)
+	require.NoError(t, svc.RootKeyStore().RevokeRootKey(1))
+
+	snapshot, err := svc.RootKeyStore().Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, svc.RootKeyStore().db.Close())
+	svc.SetRootKeyStore(snapshot)
+
+	require.NoError(t, svc.Verify(mac0, req))
+	require.ErrorIs(t, svc.Verify(mac1, req), ErrRootKeyNotFound)
+
+	ids, err := snapshot.RootKeyIDs()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0}, ids)
+
+	_, err = svc.Bake(2, []string{EntityRead})
+	require.ErrorIs(t, err, ErrReadOnlyRootKeyStore)
+	require.ErrorIs(t, snapshot.RevokeRootKey(0), ErrReadOnlyRootKeyStore)
+}
+
+func macaroonContext(t *testing.T, mac *macaroon.Macaroon) context.Context {
+	t.Helper()
+
+	b, err := mac.MarshalBinary()
+	require.NoError(t, err)
+	md := metadata.Pairs(MetadataKey, hex.EncodeToString(b))
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	return peer.NewContext(ctx, &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234},
+	})
+}
+
+// TestUnaryServerInterceptor tests that the unary interceptor enforces the
+// permissions of every method.
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService("btcwallet")
+	cfg := &InterceptorConfig{
+		Permissions: map[string]string{
+			"/test/Read":      EntityRead,
+			"/test/Send":      EntitySend,
+			"/test/Bootstrap": EntityAdmin,
+		},
+		Unauthenticated: map[string]struct{}{"/test/Version": {}},
+		Bootstrap:       map[string]struct{}{"/test/Bootstrap": {}},
+		SpendAmount: func(req interface{}) (btcutil.Amount, bool) {
+			amount, ok := req.(btcutil.Amount)
+			return amount, ok
+		},
+	}
+	interceptor := svc.UnaryServerInterceptor(cfg)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(ctx context.Context, method string, req interface{}) error {
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		_, err := interceptor(ctx, req, info, handler)
+		return err
+	}
+
+	// Before a root key store is set, only unauthenticated and bootstrap
+	// methods may be called.
+	ctx := context.Background()
+	require.NoError(t, call(ctx, "/test/Version", nil))
+	require.NoError(t, call(ctx, "/test/Bootstrap", nil))
+	require.Equal(t, codes.Unauthenticated,
+		status.Code(call(ctx, "/test/Read", nil)))
+
+	svc.SetRootKeyStore(testService(t).RootKeyStore())
+	require.NoError(t, call(ctx, "/test/Version", nil))
+	require.Equal(t, codes.Unauthenticated,
+		status.Code(call(ctx, "/test/Bootstrap", nil)))
+
+	// Once a root key store has been set, the bootstrap methods require a
+	// macaroon even if the store is cleared again.
+	store := svc.RootKeyStore()
+	svc.SetRootKeyStore(nil)
+	require.Equal(t, codes.Unauthenticated,
+		status.Code(call(ctx, "/test/Bootstrap", nil)))
+	svc.SetRootKeyStore(store)
+
+	mac, err := svc.Bake(0, []string{EntityRead, EntitySend},
+		SpendLimitCaveat(500), IPAddrCaveat(net.ParseIP("127.0.0.1")))
+	require.NoError(t, err)
+	ctx = macaroonContext(t, mac)
+
+	require.NoError(t, call(ctx, "/test/Read", nil))
+	require.NoError(t, call(ctx, "/test/Send", btcutil.Amount(500)))
+	require.Equal(t, codes.PermissionDenied,
+		status.Code(call(ctx, "/test/Send", btcutil.Amount(501))))
+	require.Equal(t, codes.PermissionDenied,
+		status.Code(call(ctx, "/test/Bootstrap", nil)))
+	require.Equal(t, codes.PermissionDenied,
+		status.Code(call(ctx, "/test/Unknown", nil)))
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// TestStreamServerInterceptor tests that the stream interceptor checks the
+// macaroon of streaming calls.
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	svc := testService(t)
+	cfg := &InterceptorConfig{
+		Permissions: map[string]string{"/test/Stream": EntityRead},
+	}
+	interceptor := svc.StreamServerInterceptor(cfg)
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/test/Stream"}
+
+	ss := &testServerStream{ctx: context.Background()}
+	err := interceptor(nil, ss, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	mac, err := svc.Bake(0, []string{EntitySend})
+	require.NoError(t, err)
+	ss.ctx = macaroonContext(t, mac)
+	err = interceptor(nil, ss, info, handler)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	mac, err = svc.Bake(0, []string{EntityRead})
+	require.NoError(t, err)
+	ss.ctx = macaroonContext(t, mac)
+	require.NoError(t, interceptor(nil, ss, info, handler))
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package macaroons implements macaroon based authorization of the wallet's
+// gRPC services.
+//
+// Macaroons are bearer tokens that are baked from a root key stored in the
+// wallet database.  Each macaroon carries first-party caveats restricting the
+// permission entities it grants and optionally the client IP address, an
+// expiry time and the amount a single request may spend.  Revoking a root key
+// invalidates every macaroon baked with it.
+package macaroons
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"gopkg.in/macaroon.v2"
+)
+
+// Permission entities a macaroon may grant.  Every RPC requires exactly one
+// of these.
+const (
+	// EntityRead grants access to queries and notifications.
+	EntityRead = "read"
+
+	// EntityAddress grants access to address and account creation, imports
+	// and labels.
+	EntityAddress = "address"
+
+	// EntitySend grants access to creating, funding and publishing
+	// transactions.
+	EntitySend = "send"
+
+	// EntitySign grants access to signing transactions and messages.
+	EntitySign = "sign"
+
+	// EntityAdmin grants access to wallet loading, passphrase changes,
+	// rescans and macaroon management.
+	EntityAdmin = "admin"
+)
+
+// AllEntities lists every permission entity.
+var AllEntities = []string{
+	EntityRead, EntityAddress, EntitySend, EntitySign, EntityAdmin,
+}
+
+// Caveat conditions.
+const (
+	condEntities   = "entities"
+	condIPAddr     = "ipaddr"
+	condTimeBefore = "time-before"
+	condSpendLimit = "spend-limit"
+)
+
+// macaroonIDVersion is the version of the macaroon ID encoding.  An ID is the
+// version byte, followed by the big-endian root key ID and a random nonce.
+const macaroonIDVersion = 0
+
+var (
+	// ErrNoRootKeyStore is returned when a macaroon is baked or verified
+	// before a wallet, and with it the root key store, is loaded.
+	ErrNoRootKeyStore = errors.New("macaroon root key store is not loaded")
+
+	// ErrInvalidMacaroonID is returned when the ID of a macaroon can not be
+	// decoded.
+	ErrInvalidMacaroonID = errors.New("invalid macaroon ID")
+
+	errMissingMacaroon = errors.New("expected 1 macaroon in request metadata")
+)
+
+// IPAddrCaveat returns a caveat restricting a macaroon to clients connecting
+// from the given IP address.
+func IPAddrCaveat(ip net.IP) string {
+	return condIPAddr + " " + ip.String()
+}
+
+// TimeBeforeCaveat returns a caveat restricting a macaroon to be used before
+// the given time.
+func TimeBeforeCaveat(t time.Time) string {
+	return condTimeBefore + " " + t.UTC().Format(time.RFC3339)
+}
+
+// SpendLimitCaveat returns a caveat restricting the amount a single request
+// authorized by a macaroon may spend.
+func SpendLimitCaveat(limit btcutil.Amount) string {
+	return condSpendLimit + " " + strconv.FormatInt(int64(limit), 10)
+}
+
+// entitiesCaveat returns the caveat restricting a macaroon to the given
+// permission entities.
+func entitiesCaveat(entities []string) string {
+	return condEntities + " " + strings.Join(entities, " ")
+}
+
+// Request describes an authorization request that the caveats of a macaroon
+// are checked against.
+type Request struct {
+	// Entity is the permission entity required by the request.
+	Entity string
+
+	// PeerIP is the IP address of the client, if known.
+	PeerIP net.IP
+
+	// Spends is true if the request spends wallet funds, in which case
+	// SpendAmount holds the total amount spent.
+	Spends      bool
+	SpendAmount btcutil.Amount
+
+	// Now is the time the request is checked at.
+	Now time.Time
+}
+
+// Service bakes and verifies macaroons using the root keys of a RootKeyStore.
+// The store is set once a wallet is loaded.
+type Service struct {
+	location string
+
+	mu    sync.RWMutex
+	store *RootKeyStore
+
+	// loaded is set once a root key store has been set, and is never
+	// cleared.
+	loaded bool
+}
+
+// NewService returns a macaroon service baking macaroons for the given
+// location.  No macaroons can be baked or verified until a root key store is
+// set.
+func NewService(location string) *Service {
+	return &Service{location: location}
+}
+
+// SetRootKeyStore sets the root key store of the service.
+func (s *Service) SetRootKeyStore(store *RootKeyStore) {
+	s.mu.Lock()
+	s.store = store
+	if store != nil {
+		s.loaded = true
+	}
+	s.mu.Unlock()
+}
+
+// Bootstrapping returns whether a root key store has never been set, that is
+// whether no wallet has been loaded yet.  Clearing the store when a wallet is
+// closed does not return the service to bootstrapping.
+func (s *Service) Bootstrapping() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.loaded
+}
+
+// RootKeyStore returns the root key store of the service, or nil if none has
+// been set.
+func (s *Service) RootKeyStore() *RootKeyStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store
+}
+
+// Bake returns a new macaroon granting the passed permission entities, baked
+// with the root key of the given ID.  The root key is created if it does not
+// exist.  Any additional caveats are added to the macaroon.
+func (s *Service) Bake(rootKeyID uint64, entities []string,
+	caveats ...string) (*macaroon.Macaroon, error) {
+
+	store := s.RootKeyStore()
+	if store == nil {
+		return nil, ErrNoRootKeyStore
+	}
+	if len(entities) == 0 {
+		return nil, errors.New("no permission entities specified")
+	}
+	for _, entity := range entities {
+		if !isEntity(entity) {
+			return nil, fmt.Errorf("unknown permission entity %q",
+				entity)
+		}
+	}
+
+	rootKey, err := store.GenerateRootKey(rootKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 1+8+16)
+	id[0] = macaroonIDVersion
+	binary.BigEndian.PutUint64(id[1:9], rootKeyID)
+	if _, err := rand.Read(id[9:]); err != nil {
+		return nil, err
+	}
+
+	mac, err := macaroon.New(rootKey, id, s.location, macaroon.LatestVersion)
+	if err != nil {
+		return nil, err
+	}
+	caveats = append([]string{entitiesCaveat(entities)}, caveats...)
+	for _, caveat := range caveats {
+		err := mac.AddFirstPartyCaveat([]byte(caveat))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mac, nil
+}
+
+// RootKeyID returns the ID of the root key a macaroon was baked with.
+func RootKeyID(mac *macaroon.Macaroon) (uint64, error) {
+	id := mac.Id()
+	if len(id) < 9 || id[0] != macaroonIDVersion {
+		return 0, ErrInvalidMacaroonID
+	}
+	return binary.BigEndian.Uint64(id[1:9]), nil
+}
+
+// Verify checks the signature of a macaroon and that all of its caveats are
+// satisfied by the request.
+func (s *Service) Verify(mac *macaroon.Macaroon, req *Request) error {
+	store := s.RootKeyStore()
+	if store == nil {
+		return ErrNoRootKeyStore
+	}
+
+	rootKeyID, err := RootKeyID(mac)
+	if err != nil {
+		return err
+	}
+	rootKey, err := store.RootKey(rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	// A macaroon must restrict the entities it grants, so a macaroon
+	// without any entities caveat is rejected.
+	var checkedEntities bool
+	check := func(caveat string) error {
+		cond, arg, _ := strings.Cut(caveat, " ")
+		if cond == condEntities {
+			checkedEntities = true
+		}
+		return checkCaveat(cond, arg, req)
+	}
+	if err := mac.Verify(rootKey, check, nil); err != nil {
+		return err
+	}
+	if !checkedEntities {
+		return errors.New("macaroon grants no permission entities")
+	}
+
+	return nil
+}
+
+// checkCaveat checks a single first-party caveat against a request.
+func checkCaveat(cond, arg string, req *Request) error {
+	switch cond {
+	case condEntities:
+		for _, entity := range strings.Fields(arg) {
+			if entity == req.Entity {
+				return nil
+			}
+		}
+		return fmt.Errorf("macaroon does not grant the %q permission",
+			req.Entity)
+
+	case condIPAddr:
+		ip := net.ParseIP(arg)
+		if ip == nil {
+			return fmt.Errorf("invalid IP address caveat %q", arg)
+		}
+		if req.PeerIP == nil || !ip.Equal(req.PeerIP) {
+			return fmt.Errorf("macaroon is restricted to IP "+
+				"address %v", ip)
+		}
+		return nil
+
+	case condTimeBefore:
+		expiry, err := time.Parse(time.RFC3339, arg)
+		if err != nil {
+			return fmt.Errorf("invalid expiry caveat %q", arg)
+		}
+		if !req.Now.Before(expiry) {
+			return fmt.Errorf("macaroon expired at %v", expiry)
+		}
+		return nil
+
+	case condSpendLimit:
+		limit, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid spend limit caveat %q", arg)
+		}
+		if req.Spends && req.SpendAmount > btcutil.Amount(limit) {
+			return fmt.Errorf("request spends %v which exceeds the "+
+				"macaroon spend limit of %v", req.SpendAmount,
+				btcutil.Amount(limit))
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown caveat condition %q", cond)
+	}
+}
+
+func isEntity(entity string) bool {
+	for _, e := range AllEntities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
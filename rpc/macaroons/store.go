@@ -0,0 +1,179 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package macaroons
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// RootKeyLen is the length of a macaroon root key in bytes.
+const RootKeyLen = 32
+
+var (
+	// rootKeyNamespaceKey is the top level bucket of the wallet database
+	// that houses the macaroon root keys, keyed by their big-endian
+	// encoded root key ID.
+	rootKeyNamespaceKey = []byte("macaroonrootkeys")
+
+	// ErrRootKeyNotFound is returned when a root key ID is unknown, either
+	// because it was never generated or because it has been revoked.
+	ErrRootKeyNotFound = errors.New("macaroon root key not found")
+
+	// ErrReadOnlyRootKeyStore is returned when generating or revoking a
+	// root key of a snapshot of a root key store.
+	ErrReadOnlyRootKeyStore = errors.New("macaroon root key store is " +
+		"read-only while no wallet is loaded")
+)
+
+// RootKeyStore stores the root keys that macaroons are baked with inside of
+// the wallet database.  Revoking a root key invalidates every macaroon baked
+// with it.
+type RootKeyStore struct {
+	db walletdb.DB
+
+	// snapshot holds the root keys of a read-only store that is no longer
+	// backed by a database, keyed by their ID.
+	snapshot map[uint64][]byte
+}
+
+// NewRootKeyStore returns a root key store backed by the passed wallet
+// database, creating the root key bucket if it does not exist yet.
+func NewRootKeyStore(db walletdb.DB) (*RootKeyStore, error) {
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		_, err := tx.CreateTopLevelBucket(rootKeyNamespaceKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RootKeyStore{db: db}, nil
+}
+
+func rootKeyIDBytes(id uint64) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], id)
+	return k[:]
+}
+
+// Snapshot returns a read-only copy of the store that is kept in memory, so
+// the macaroons baked with its root keys can still be verified once the
+// wallet database is closed.
+func (s *RootKeyStore) Snapshot() (*RootKeyStore, error) {
+	if s.snapshot != nil {
+		return s, nil
+	}
+
+	snapshot := make(map[uint64][]byte)
+	err := walletdb.View(s.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(rootKeyNamespaceKey)
+		return ns.ForEach(func(k, v []byte) error {
+			if len(k) != 8 {
+				return nil
+			}
+			id := binary.BigEndian.Uint64(k)
+			snapshot[id] = append([]byte(nil), v...)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RootKeyStore{snapshot: snapshot}, nil
+}
+
+// RootKey returns the root key with the given ID.
+func (s *RootKeyStore) RootKey(id uint64) ([]byte, error) {
+	if s.snapshot != nil {
+		rootKey, ok := s.snapshot[id]
+		if !ok {
+			return nil, ErrRootKeyNotFound
+		}
+		return append([]byte(nil), rootKey...), nil
+	}
+
+	var rootKey []byte
+	err := walletdb.View(s.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(rootKeyNamespaceKey)
+		v := ns.Get(rootKeyIDBytes(id))
+		if v == nil {
+			return ErrRootKeyNotFound
+		}
+		rootKey = append([]byte(nil), v...)
+		return nil
+	})
+	return rootKey, err
+}
+
+// GenerateRootKey returns the root key with the given ID, generating and
+// storing a new random root key if none exists yet.
+func (s *RootKeyStore) GenerateRootKey(id uint64) ([]byte, error) {
+	if s.snapshot != nil {
+		return nil, ErrReadOnlyRootKeyStore
+	}
+
+	var rootKey []byte
+	err := walletdb.Update(s.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(rootKeyNamespaceKey)
+		k := rootKeyIDBytes(id)
+		if v := ns.Get(k); v != nil {
+			rootKey = append([]byte(nil), v...)
+			return nil
+		}
+
+		rootKey = make([]byte, RootKeyLen)
+		if _, err := rand.Read(rootKey); err != nil {
+			return err
+		}
+		return ns.Put(k, rootKey)
+	})
+	return rootKey, err
+}
+
+// RevokeRootKey deletes the root key with the given ID.  Every macaroon baked
+// with the root key fails verification afterwards.
+func (s *RootKeyStore) RevokeRootKey(id uint64) error {
+	if s.snapshot != nil {
+		return ErrReadOnlyRootKeyStore
+	}
+
+	return walletdb.Update(s.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(rootKeyNamespaceKey)
+		k := rootKeyIDBytes(id)
+		if ns.Get(k) == nil {
+			return ErrRootKeyNotFound
+		}
+		return ns.Delete(k)
+	})
+}
+
+// RootKeyIDs returns the IDs of all root keys in increasing order.
+func (s *RootKeyStore) RootKeyIDs() ([]uint64, error) {
+	if s.snapshot != nil {
+		ids := make([]uint64, 0, len(s.snapshot))
+		for id := range s.snapshot {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids, nil
+	}
+
+	var ids []uint64
+	err := walletdb.View(s.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(rootKeyNamespaceKey)
+		return ns.ForEach(func(k, _ []byte) error {
+			if len(k) != 8 {
+				return nil
+			}
+			ids = append(ids, binary.BigEndian.Uint64(k))
+			return nil
+		})
+	})
+	return ids, err
+}
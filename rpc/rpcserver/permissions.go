@@ -0,0 +1,175 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/rpc/macaroons"
+	pb "github.com/btcsuite/btcwallet/rpc/walletrpc"
+	"github.com/btcsuite/btcwallet/wallet"
+)
+
+const (
+	walletServicePrefix = "/walletrpc.WalletService/"
+	loaderServicePrefix = "/walletrpc.WalletLoaderService/"
+	versionServicePath  = "/walletrpc.VersionService/Version"
+)
+
+// macaroonPermissions maps the full method name of every WalletService and
+// WalletLoaderService RPC to the permission entity required to call it.
+var macaroonPermissions = map[string]string{
+	walletServicePrefix + "Ping":                     macaroons.EntityRead,
+	walletServicePrefix + "Network":                  macaroons.EntityRead,
+	walletServicePrefix + "AccountNumber":            macaroons.EntityRead,
+	walletServicePrefix + "Accounts":                 macaroons.EntityRead,
+	walletServicePrefix + "Balance":                  macaroons.EntityRead,
+	walletServicePrefix + "GetTransactions":          macaroons.EntityRead,
+	walletServicePrefix + "GetTransaction":           macaroons.EntityRead,
+	walletServicePrefix + "ListUnspent":              macaroons.EntityRead,
+	walletServicePrefix + "ListLeasedOutputs":        macaroons.EntityRead,
+	walletServicePrefix + "AddressInfo":              macaroons.EntityRead,
+	walletServicePrefix + "VerifyMessage":            macaroons.EntityRead,
+	walletServicePrefix + "TransactionNotifications": macaroons.EntityRead,
+	walletServicePrefix + "SpentnessNotifications":   macaroons.EntityRead,
+	walletServicePrefix + "AccountNotifications":     macaroons.EntityRead,
+	walletServicePrefix + "ChangePassphrase":         macaroons.EntityAdmin,
+	walletServicePrefix + "RenameAccount":            macaroons.EntityAddress,
+	walletServicePrefix + "NextAccount":              macaroons.EntityAddress,
+	walletServicePrefix + "NextAddress":              macaroons.EntityAddress,
+	walletServicePrefix + "ImportPrivateKey":         macaroons.EntityAdmin,
+	walletServicePrefix + "FundTransaction":          macaroons.EntitySend,
+	walletServicePrefix + "SignTransaction":          macaroons.EntitySign,
+	walletServicePrefix + "PublishTransaction":       macaroons.EntitySend,
+	walletServicePrefix + "CreateSimpleTransaction":  macaroons.EntitySend,
+	walletServicePrefix + "FundPsbt":                 macaroons.EntitySend,
+	walletServicePrefix + "FinalizePsbt":             macaroons.EntitySign,
+	walletServicePrefix + "LeaseOutput":              macaroons.EntitySend,
+	walletServicePrefix + "ReleaseOutput":            macaroons.EntitySend,
+	walletServicePrefix + "ImportAccount":            macaroons.EntityAddress,
+	walletServicePrefix + "ImportPublicKey":          macaroons.EntityAddress,
+	walletServicePrefix + "ImportTaprootScript":      macaroons.EntityAddress,
+	walletServicePrefix + "ImportLegacyKeystore":     macaroons.EntityAdmin,
+	walletServicePrefix + "LabelTransaction":         macaroons.EntityAddress,
+	walletServicePrefix + "Rescan":                   macaroons.EntityAdmin,
+	walletServicePrefix + "RecoveryProgress":         macaroons.EntityRead,
+	walletServicePrefix + "PassphraseKdf":            macaroons.EntityRead,
+	walletServicePrefix + "SignMessage":              macaroons.EntitySign,
+	walletServicePrefix + "BakeMacaroon":             macaroons.EntityAdmin,
+	walletServicePrefix + "RevokeMacaroonRootKey":    macaroons.EntityAdmin,
+	walletServicePrefix + "ListMacaroonRootKeys":     macaroons.EntityAdmin,
+
+	loaderServicePrefix + "WalletExists":               macaroons.EntityRead,
+	loaderServicePrefix + "CreateWallet":               macaroons.EntityAdmin,
+	loaderServicePrefix + "OpenWallet":                 macaroons.EntityAdmin,
+	loaderServicePrefix + "CloseWallet":                macaroons.EntityAdmin,
+	loaderServicePrefix + "StartConsensusRpc":          macaroons.EntityAdmin,
+	loaderServicePrefix + "RollbackTransactionHistory": macaroons.EntityAdmin,
+}
+
+// MacaroonInterceptorConfig returns the configuration of the macaroon
+// interceptors for a gRPC server hosting the services of this package.
+//
+// The VersionService never requires a macaroon.  As the macaroon root keys
+// are stored in the wallet database, the WalletLoaderService calls needed to
+// create or open the first wallet don't require one until a wallet has been
+// loaded.  Every other call, and all calls after the first wallet is loaded,
+// do.  The loader is used to determine the amount spent by a request:
+// outputs paying to addresses of the loaded wallet are not counted against
+// spend limits.
+func MacaroonInterceptorConfig(loader *wallet.Loader) *macaroons.InterceptorConfig {
+	bootstrap := map[string]struct{}{
+		loaderServicePrefix + "WalletExists": {},
+		loaderServicePrefix + "CreateWallet": {},
+		loaderServicePrefix + "OpenWallet":   {},
+	}
+
+	return &macaroons.InterceptorConfig{
+		Permissions:     macaroonPermissions,
+		Unauthenticated: map[string]struct{}{versionServicePath: {}},
+		Bootstrap:       bootstrap,
+		SpendAmount: func(req interface{}) (btcutil.Amount, bool) {
+			return spendAmount(loader, req)
+		},
+	}
+}
+
+// spendAmount returns the total amount paid to addresses not belonging to the
+// loaded wallet by a request that creates, signs or publishes a transaction.
+// Requests that can not be decoded are reported as spending nothing and are
+// rejected by their handler.
+func spendAmount(loader *wallet.Loader, req interface{}) (btcutil.Amount, bool) {
+	w, _ := loader.LoadedWallet()
+
+	var outputs []*wire.TxOut
+	switch req := req.(type) {
+	case *pb.CreateSimpleTransactionRequest:
+		if req.DryRun {
+			return 0, false
+		}
+		var total btcutil.Amount
+		for _, output := range req.Outputs {
+			if w != nil {
+				addr, err := btcutil.DecodeAddress(
+					output.Address, w.ChainParams(),
+				)
+				if err == nil && isOwnAddress(w, addr) {
+					continue
+				}
+			}
+			total += btcutil.Amount(output.Amount)
+		}
+		return total, true
+
+	case *pb.SignTransactionRequest:
+		tx := new(wire.MsgTx)
+		err := tx.Deserialize(bytes.NewReader(req.SerializedTransaction))
+		if err != nil {
+			return 0, true
+		}
+		outputs = tx.TxOut
+
+	case *pb.PublishTransactionRequest:
+		tx := new(wire.MsgTx)
+		err := tx.Deserialize(bytes.NewReader(req.SignedTransaction))
+		if err != nil {
+			return 0, true
+		}
+		outputs = tx.TxOut
+
+	case *pb.FinalizePsbtRequest:
+		packet, err := psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+		if err != nil {
+			return 0, true
+		}
+		outputs = packet.UnsignedTx.TxOut
+
+	default:
+		return 0, false
+	}
+
+	var total btcutil.Amount
+	for _, output := range outputs {
+		if w != nil {
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				output.PkScript, w.ChainParams(),
+			)
+			if err == nil && len(addrs) == 1 && isOwnAddress(w, addrs[0]) {
+				continue
+			}
+		}
+		total += btcutil.Amount(output.Value)
+	}
+	return total, true
+}
+
+func isOwnAddress(w *wallet.Wallet, addr btcutil.Address) bool {
+	ok, err := w.HaveAddress(addr)
+	return err == nil && ok
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/rpc/macaroons"
+	pb "github.com/btcsuite/btcwallet/rpc/walletrpc"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestMacaroonPermissions tests that every method of the registered services,
+// except for the unauthenticated version service, has a known permission
+// entity, and that no permission is defined for a method that doesn't exist.
+func TestMacaroonPermissions(t *testing.T) {
+	server := grpc.NewServer()
+	StartVersionService(server)
+	StartWalletService(server, nil, nil)
+	StartWalletLoaderService(server, nil, nil, nil)
+
+	methods := make(map[string]struct{})
+	for service, info := range server.GetServiceInfo() {
+		for _, method := range info.Methods {
+			fullMethod := "/" + service + "/" + method.Name
+			methods[fullMethod] = struct{}{}
+
+			entity, ok := macaroonPermissions[fullMethod]
+			if fullMethod == versionServicePath {
+				require.False(t, ok, fullMethod)
+				continue
+			}
+			require.True(t, ok, "no permission for %s", fullMethod)
+			require.Contains(t, macaroons.AllEntities, entity,
+				fullMethod)
+		}
+	}
+	require.Contains(t, methods, versionServicePath)
+
+	for fullMethod := range macaroonPermissions {
+		require.Contains(t, methods, fullMethod)
+	}
+}
+
+// TestSpendLimit tests that the spend limit of a macaroon is checked against
+// the amount paid by a request to addresses outside of the loaded wallet.
+func TestSpendLimit(t *testing.T) {
+	s := newTestServer(t)
+
+	store, err := macaroons.NewRootKeyStore(s.wallet.Database())
+	require.NoError(t, err)
+	svc := macaroons.NewService("btcwallet")
+	svc.SetRootKeyStore(store)
+
+	const limit = 5e7
+	mac, err := svc.Bake(0, []string{
+		macaroons.EntitySend, macaroons.EntitySign,
+	}, macaroons.SpendLimitCaveat(limit))
+	require.NoError(t, err)
+	macBytes, err := mac.MarshalBinary()
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		macaroons.MetadataKey, hex.EncodeToString(macBytes),
+	))
+
+	interceptor := svc.UnaryServerInterceptor(
+		MacaroonInterceptorConfig(s.loader),
+	)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	call := func(method string, req interface{}) codes.Code {
+		info := &grpc.UnaryServerInfo{
+			FullMethod: walletServicePrefix + method,
+		}
+		_, err := interceptor(ctx, req, info, handler)
+		return status.Code(err)
+	}
+
+	// Each transaction pays the amount to an external address, and more
+	// than the limit back to the wallet, which isn't counted.
+	ownPkScript, err := txscript.PayToAddrScript(s.addr)
+	require.NoError(t, err)
+	extPkScript, err := txscript.PayToAddrScript(newExternalAddr(t))
+	require.NoError(t, err)
+	newTx := func(amount int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+		tx.AddTxOut(wire.NewTxOut(amount, extPkScript))
+		tx.AddTxOut(wire.NewTxOut(10*limit, ownPkScript))
+		return tx
+	}
+	serialize := func(tx *wire.MsgTx) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, tx.Serialize(&buf))
+		return buf.Bytes()
+	}
+	newPsbt := func(amount int64) []byte {
+		packet, err := psbt.NewFromUnsignedTx(newTx(amount))
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, packet.Serialize(&buf))
+		return buf.Bytes()
+	}
+
+	extAddr := newExternalAddr(t).EncodeAddress()
+	newSimpleTx := func(amount int64) *pb.CreateSimpleTransactionRequest {
+		return &pb.CreateSimpleTransactionRequest{
+			Outputs: []*pb.CreateSimpleTransactionRequest_Output{{
+				Address: extAddr, Amount: amount,
+			}, {
+				Address: s.addr.EncodeAddress(), Amount: 10 * limit,
+			}},
+		}
+	}
+
+	requests := map[string]func(amount int64) interface{}{
+		"CreateSimpleTransaction": func(amount int64) interface{} {
+			return newSimpleTx(amount)
+		},
+		"SignTransaction": func(amount int64) interface{} {
+			return &pb.SignTransactionRequest{
+				SerializedTransaction: serialize(newTx(amount)),
+			}
+		},
+		"PublishTransaction": func(amount int64) interface{} {
+			return &pb.PublishTransactionRequest{
+				SignedTransaction: serialize(newTx(amount)),
+			}
+		},
+		"FinalizePsbt": func(amount int64) interface{} {
+			return &pb.FinalizePsbtRequest{Psbt: newPsbt(amount)}
+		},
+	}
+	for method, newRequest := range requests {
+		require.Equal(t, codes.OK, call(method, newRequest(limit)),
+			method)
+		require.Equal(t, codes.PermissionDenied,
+			call(method, newRequest(limit+1)), method)
+	}
+
+	// A dry run doesn't spend anything, and requests that can't be
+	// decoded are left to their handler to reject.
+	dryRun := newSimpleTx(limit + 1)
+	dryRun.DryRun = true
+	require.Equal(t, codes.OK, call("CreateSimpleTransaction", dryRun))
+	require.Equal(t, codes.OK, call("PublishTransaction",
+		&pb.PublishTransactionRequest{SignedTransaction: []byte("bogus")}))
+
+	// Without a loaded wallet, no address is known to be its own.
+	loader := wallet.NewLoader(
+		testParams, t.TempDir(), true, wallet.DefaultDBTimeout, 0,
+	)
+	amount, spends := spendAmount(loader, newSimpleTx(limit))
+	require.True(t, spends)
+	require.Equal(t, btcutil.Amount(11*limit), amount)
+}
@@ -17,7 +17,10 @@ package rpcserver
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"math"
+	"net"
 	"sync"
 	"time"
 
@@ -26,28 +29,37 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/internal/cfgutil"
+	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
 	"github.com/btcsuite/btcwallet/internal/zero"
 	"github.com/btcsuite/btcwallet/netparams"
+	"github.com/btcsuite/btcwallet/rpc/macaroons"
 	pb "github.com/btcsuite/btcwallet/rpc/walletrpc"
+	"github.com/btcsuite/btcwallet/snacl"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
 )
 
 // Public API version constants
 const (
-	semverString = "2.0.1"
+	semverString = "2.7.0"
 	semverMajor  = 2
-	semverMinor  = 0
-	semverPatch  = 1
+	semverMinor  = 7
+	semverPatch  = 0
 )
 
 // translateError creates a new gRPC error with an appropriate error code for
@@ -65,7 +77,8 @@ func errorCode(err error) codes.Code {
 	// waddrmgr.IsError is convenient, but not granular enough when the
 	// underlying error has to be checked.  Unwrap the underlying error
 	// if it exists.
-	if e, ok := err.(waddrmgr.ManagerError); ok {
+	var e waddrmgr.ManagerError
+	if errors.As(err, &e) {
 		// For these waddrmgr error codes, the underlying error isn't
 		// needed to determine the grpc error code.
 		switch e.ErrorCode {
@@ -77,27 +90,59 @@ func errorCode(err error) codes.Code {
 			return codes.InvalidArgument
 		case waddrmgr.ErrDuplicateAccount:
 			return codes.AlreadyExists
+		case waddrmgr.ErrAddressNotFound:
+			return codes.NotFound
+		case waddrmgr.ErrLocked:
+			return codes.FailedPrecondition
 		}
 
 		err = e.Err
 	}
 
-	switch err {
-	case wallet.ErrLoaded:
+	// The errors may be wrapped with more context, such as the hash of
+	// the transaction which wasn't found.
+	switch {
+	case isAnyError(err, wallet.ErrLoaded):
 		return codes.FailedPrecondition
-	case walletdb.ErrDbNotOpen:
+	case isAnyError(err, walletdb.ErrDbNotOpen):
 		return codes.Aborted
-	case walletdb.ErrDbExists:
+	case isAnyError(err, walletdb.ErrDbExists):
 		return codes.AlreadyExists
-	case walletdb.ErrDbDoesNotExist:
+	case isAnyError(err, walletdb.ErrDbDoesNotExist):
+		return codes.NotFound
+	case isAnyError(err, hdkeychain.ErrInvalidSeedLen,
+		wallet.ErrInsecureDBPassphrase, snacl.ErrInvalidParams):
+		return codes.InvalidArgument
+	case isAnyError(err, wallet.ErrNoTx, wallet.ErrUnknownTransaction,
+		wtxmgr.ErrUnknownOutput):
 		return codes.NotFound
-	case hdkeychain.ErrInvalidSeedLen:
+	case isAnyError(err, wallet.ErrTxLabelExists,
+		wtxmgr.ErrOutputAlreadyLocked):
+		return codes.AlreadyExists
+	case isAnyError(err, wtxmgr.ErrOutputUnlockNotAllowed):
+		return codes.PermissionDenied
+	case isAnyError(err, wtxmgr.ErrEmptyLabel, wtxmgr.ErrLabelTooLong):
 		return codes.InvalidArgument
+	case isAnyError(err, macaroons.ErrRootKeyNotFound):
+		return codes.NotFound
+	case isAnyError(err, macaroons.ErrNoRootKeyStore,
+		macaroons.ErrReadOnlyRootKeyStore):
+		return codes.FailedPrecondition
 	default:
 		return codes.Unknown
 	}
 }
 
+// isAnyError returns whether err is, or wraps, any of the targets.
+func isAnyError(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // versionServer provides RPC clients with the ability to query the RPC server
 // version.
 type versionServer struct {
@@ -105,7 +150,8 @@ type versionServer struct {
 
 // walletServer provides wallet services for RPC clients.
 type walletServer struct {
-	wallet *wallet.Wallet
+	wallet    *wallet.Wallet
+	macaroons *macaroons.Service
 }
 
 // loaderServer provides RPC clients with the ability to load and close wallets,
@@ -113,6 +159,7 @@ type walletServer struct {
 type loaderServer struct {
 	loader    *wallet.Loader
 	activeNet *netparams.Params
+	macaroons *macaroons.Service
 	rpcClient *chain.RPCClient
 	mu        sync.Mutex
 }
@@ -133,9 +180,12 @@ func (*versionServer) Version(ctx context.Context, req *pb.VersionRequest) (*pb.
 }
 
 // StartWalletService creates an implementation of the WalletService and
-// registers it with the gRPC server.
-func StartWalletService(server *grpc.Server, wallet *wallet.Wallet) {
-	service := &walletServer{wallet}
+// registers it with the gRPC server.  The macaroon service is used to bake and
+// revoke macaroons and may be nil if macaroon authentication is disabled.
+func StartWalletService(server *grpc.Server, wallet *wallet.Wallet,
+	macaroonService *macaroons.Service) {
+
+	service := &walletServer{wallet, macaroonService}
 	pb.RegisterWalletServiceServer(server, service)
 }
 
@@ -423,12 +473,18 @@ func (s *walletServer) ChangePassphrase(ctx context.Context, req *pb.ChangePassp
 		zero.Bytes(req.NewPassphrase)
 	}()
 
-	var err error
+	kdf, err := passphraseKDFOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
 	switch req.Key {
 	case pb.ChangePassphraseRequest_PRIVATE:
-		err = s.wallet.ChangePrivatePassphrase(req.OldPassphrase, req.NewPassphrase)
+		err = s.wallet.ChangePrivatePassphraseKDF(req.OldPassphrase,
+			req.NewPassphrase, kdf)
 	case pb.ChangePassphraseRequest_PUBLIC:
-		err = s.wallet.ChangePublicPassphrase(req.OldPassphrase, req.NewPassphrase)
+		err = s.wallet.ChangePublicPassphraseKDF(req.OldPassphrase,
+			req.NewPassphrase, kdf)
 	default:
 		return nil, status.Errorf(codes.InvalidArgument, "Unknown key type (%d)", req.Key)
 	}
@@ -438,6 +494,156 @@ func (s *walletServer) ChangePassphrase(ctx context.Context, req *pb.ChangePassp
 	return &pb.ChangePassphraseResponse{}, nil
 }
 
+// Limits on the unlock time of a ChangePassphrase request.  The limits of the
+// key derivation parameters themselves are enforced by snacl and checked here
+// only to return a clearer error.
+const (
+	// maxTargetUnlockMilliseconds is the longest target unlock time.
+	maxTargetUnlockMilliseconds = 60 * 1000
+
+	// maxTunedUnlockFactor is how many times the target unlock time the
+	// estimated unlock time of the tuned parameters may be.
+	maxTunedUnlockFactor = 2
+)
+
+// passphraseKDFOptions returns the key derivation options of the new
+// passphrase of a ChangePassphrase request.  Nil is returned when the request
+// leaves the choice to the wallet.
+func passphraseKDFOptions(req *pb.ChangePassphraseRequest) (
+	*waddrmgr.KDFOptions, error) {
+
+	if req.Kdf != nil && req.RecommendedKdf {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"kdf and recommended_kdf are mutually exclusive")
+	}
+	if req.TargetUnlockMilliseconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Negative target unlock time")
+	}
+	if req.TargetUnlockMilliseconds > maxTargetUnlockMilliseconds {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Target unlock time above %d milliseconds",
+			maxTargetUnlockMilliseconds)
+	}
+
+	// Tune the number of Argon2id passes to the target unlock time, using
+	// the requested memory and threads if any.
+	if req.TargetUnlockMilliseconds != 0 {
+		memory := waddrmgr.DefaultArgon2idOptions.Argon2id.Memory
+		threads := waddrmgr.DefaultArgon2idOptions.Argon2id.Threads
+		if req.Kdf != nil {
+			if req.Kdf.Algorithm != pb.KdfParameters_ARGON2ID {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"Target unlock time requires Argon2id")
+			}
+			if req.Kdf.Argon2MemoryKib > snacl.MaxMemoryKiB {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"Argon2id memory above %d KiB",
+					snacl.MaxMemoryKiB)
+			}
+			if req.Kdf.Argon2MemoryKib != 0 {
+				memory = req.Kdf.Argon2MemoryKib
+			}
+			if req.Kdf.Argon2Threads != 0 {
+				if req.Kdf.Argon2Threads > math.MaxUint8 {
+					return nil, status.Errorf(codes.InvalidArgument,
+						"Too many Argon2id threads (%d)",
+						req.Kdf.Argon2Threads)
+				}
+				threads = uint8(req.Kdf.Argon2Threads)
+			}
+		}
+		target := time.Duration(req.TargetUnlockMilliseconds) *
+			time.Millisecond
+		kdf, estimate, err := waddrmgr.TuneArgon2idOptions(
+			target, memory, threads,
+		)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Invalid Argon2id parameters: %v", err)
+		}
+
+		// A single pass may already take much longer than the
+		// target, when the memory is too large for this host.
+		if estimate > maxTunedUnlockFactor*target {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Unlocking would take about %v with %d KiB of "+
+					"Argon2id memory, well above the target %v",
+				estimate.Round(time.Millisecond), memory, target)
+		}
+		if kdf.Argon2id.Time > snacl.MaxArgon2Time {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Target unlock time requires more than %d "+
+					"Argon2id passes, use more memory",
+				snacl.MaxArgon2Time)
+		}
+		return kdf, nil
+	}
+
+	if req.RecommendedKdf {
+		kdf := waddrmgr.DefaultArgon2idOptions
+		return &kdf, nil
+	}
+	if req.Kdf == nil {
+		return nil, nil
+	}
+
+	switch req.Kdf.Algorithm {
+	case pb.KdfParameters_SCRYPT:
+		if req.Kdf.ScryptN <= 1 || req.Kdf.ScryptR <= 0 ||
+			req.Kdf.ScryptP <= 0 || req.Kdf.ScryptR > math.MaxInt32 ||
+			req.Kdf.ScryptP > math.MaxInt32 {
+
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Invalid scrypt parameters")
+		}
+
+		// scrypt uses 128 * N * r bytes of memory.
+		if req.Kdf.ScryptN > snacl.MaxScryptN ||
+			req.Kdf.ScryptP > snacl.MaxScryptP ||
+			req.Kdf.ScryptR > snacl.MaxMemoryKiB*1024/128/req.Kdf.ScryptN {
+
+			return nil, status.Errorf(codes.InvalidArgument,
+				"scrypt parameters above the limits of N %d, "+
+					"p %d and %d KiB of memory", snacl.MaxScryptN,
+				snacl.MaxScryptP, snacl.MaxMemoryKiB)
+		}
+		return &waddrmgr.KDFOptions{
+			N: int(req.Kdf.ScryptN),
+			R: int(req.Kdf.ScryptR),
+			P: int(req.Kdf.ScryptP),
+		}, nil
+
+	case pb.KdfParameters_ARGON2ID:
+		if req.Kdf.Argon2Time == 0 || req.Kdf.Argon2MemoryKib == 0 ||
+			req.Kdf.Argon2Threads == 0 ||
+			req.Kdf.Argon2Threads > math.MaxUint8 {
+
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Invalid Argon2id parameters")
+		}
+		if req.Kdf.Argon2Time > snacl.MaxArgon2Time ||
+			req.Kdf.Argon2MemoryKib > snacl.MaxMemoryKiB {
+
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Argon2id parameters above the limits of %d "+
+					"passes and %d KiB of memory",
+				snacl.MaxArgon2Time, snacl.MaxMemoryKiB)
+		}
+		return &waddrmgr.KDFOptions{
+			Argon2id: &waddrmgr.Argon2idOptions{
+				Time:    req.Kdf.Argon2Time,
+				Memory:  req.Kdf.Argon2MemoryKib,
+				Threads: uint8(req.Kdf.Argon2Threads),
+			},
+		}, nil
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Unknown KDF algorithm (%d)", req.Kdf.Algorithm)
+	}
+}
+
 // BUGS:
 // - InputIndexes request field is ignored.
 func (s *walletServer) SignTransaction(ctx context.Context, req *pb.SignTransactionRequest) (
@@ -510,180 +716,1130 @@ func (s *walletServer) PublishTransaction(ctx context.Context, req *pb.PublishTr
 	return &pb.PublishTransactionResponse{}, nil
 }
 
-func marshalTransactionInputs(v []wallet.TransactionSummaryInput) []*pb.TransactionDetails_Input {
-	inputs := make([]*pb.TransactionDetails_Input, len(v))
-	for i := range v {
-		input := &v[i]
-		inputs[i] = &pb.TransactionDetails_Input{
-			Index:           input.Index,
-			PreviousAccount: input.PreviousAccount,
-			PreviousAmount:  int64(input.PreviousAmount),
-		}
+func (s *walletServer) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (
+	*pb.GetTransactionResponse, error) {
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
 	}
-	return inputs
-}
 
-func marshalTransactionOutputs(v []wallet.TransactionSummaryOutput) []*pb.TransactionDetails_Output {
-	outputs := make([]*pb.TransactionDetails_Output, len(v))
-	for i := range v {
-		output := &v[i]
-		outputs[i] = &pb.TransactionDetails_Output{
-			Index:    output.Index,
-			Account:  output.Account,
-			Internal: output.Internal,
-		}
+	res, err := s.wallet.GetTransaction(*txHash)
+	if err != nil {
+		return nil, translateError(err)
 	}
-	return outputs
-}
 
-func marshalTransactionDetails(v []wallet.TransactionSummary) []*pb.TransactionDetails {
-	txs := make([]*pb.TransactionDetails, len(v))
-	for i := range v {
-		tx := &v[i]
-		txs[i] = &pb.TransactionDetails{
-			Hash:        tx.Hash[:],
-			Transaction: tx.Transaction,
-			Debits:      marshalTransactionInputs(tx.MyInputs),
-			Credits:     marshalTransactionOutputs(tx.MyOutputs),
-			Fee:         int64(tx.Fee),
-			Timestamp:   tx.Timestamp,
-		}
+	resp := &pb.GetTransactionResponse{
+		Transaction: marshalTransactionDetails(
+			[]wallet.TransactionSummary{res.Summary},
+		)[0],
+		BlockHeight:    res.Height,
+		Confirmations:  res.Confirmations,
+		BlockTimestamp: res.Timestamp,
+		Label:          res.Summary.Label,
 	}
-	return txs
+	if res.BlockHash != nil {
+		resp.BlockHash = res.BlockHash[:]
+	}
+	return resp, nil
 }
 
-func marshalBlocks(v []wallet.Block) []*pb.BlockDetails {
-	blocks := make([]*pb.BlockDetails, len(v))
-	for i := range v {
-		block := &v[i]
-		blocks[i] = &pb.BlockDetails{
-			Hash:         block.Hash[:],
-			Height:       block.Height,
-			Timestamp:    block.Timestamp,
-			Transactions: marshalTransactionDetails(block.Transactions),
-		}
+func (s *walletServer) ListUnspent(ctx context.Context, req *pb.ListUnspentRequest) (
+	*pb.ListUnspentResponse, error) {
+
+	maxConfs := req.MaxConfirmations
+	if maxConfs == 0 {
+		maxConfs = math.MaxInt32
+	}
+	if req.MinConfirmations < 0 || maxConfs < req.MinConfirmations {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"invalid confirmation range [%d, %d]",
+			req.MinConfirmations, maxConfs)
 	}
-	return blocks
-}
 
-func marshalHashes(v []*chainhash.Hash) [][]byte {
-	hashes := make([][]byte, len(v))
-	for i, hash := range v {
-		hashes[i] = hash[:]
+	results, err := s.wallet.ListUnspent(
+		req.MinConfirmations, maxConfs, req.AccountName,
+	)
+	if err != nil {
+		return nil, translateError(err)
 	}
-	return hashes
-}
 
-func (s *walletServer) TransactionNotifications(req *pb.TransactionNotificationsRequest,
-	svr pb.WalletService_TransactionNotificationsServer) error {
+	unspent := make([]*pb.ListUnspentResponse_Unspent, 0, len(results))
+	for _, result := range results {
+		txHash, err := chainhash.NewHashFromStr(result.TxID)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		amount, err := btcutil.NewAmount(result.Amount)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		pkScript, err := hex.DecodeString(result.ScriptPubKey)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		redeemScript, err := hex.DecodeString(result.RedeemScript)
+		if err != nil {
+			return nil, translateError(err)
+		}
 
-	n := s.wallet.NtfnServer.TransactionNotifications()
-	defer n.Done()
+		unspent = append(unspent, &pb.ListUnspentResponse_Unspent{
+			Outpoint: &pb.OutPoint{
+				TransactionHash: txHash[:],
+				OutputIndex:     result.Vout,
+			},
+			Address:       result.Address,
+			AccountName:   result.Account,
+			Amount:        int64(amount),
+			PkScript:      pkScript,
+			RedeemScript:  redeemScript,
+			Confirmations: result.Confirmations,
+			Spendable:     result.Spendable,
+		})
+	}
 
-	ctxDone := svr.Context().Done()
-	for {
-		select {
-		case v := <-n.C:
-			resp := pb.TransactionNotificationsResponse{
-				AttachedBlocks:           marshalBlocks(v.AttachedBlocks),
-				DetachedBlocks:           marshalHashes(v.DetachedBlocks),
-				UnminedTransactions:      marshalTransactionDetails(v.UnminedTransactions),
-				UnminedTransactionHashes: marshalHashes(v.UnminedTransactionHashes),
-			}
-			err := svr.Send(&resp)
-			if err != nil {
-				return translateError(err)
-			}
+	return &pb.ListUnspentResponse{Unspent: unspent}, nil
+}
 
-		case <-ctxDone:
-			return nil
+func (s *walletServer) ListLeasedOutputs(ctx context.Context, req *pb.ListLeasedOutputsRequest) (
+	*pb.ListLeasedOutputsResponse, error) {
+
+	results, err := s.wallet.ListLeasedOutputs()
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	leased := make([]*pb.ListLeasedOutputsResponse_LeasedOutput, len(results))
+	for i, result := range results {
+		id := result.LockID
+		leased[i] = &pb.ListLeasedOutputsResponse_LeasedOutput{
+			Id:         id[:],
+			Outpoint:   marshalOutPoint(&result.Outpoint),
+			Expiration: result.Expiration.Unix(),
+			Value:      result.Value,
+			PkScript:   result.PkScript,
 		}
 	}
+
+	return &pb.ListLeasedOutputsResponse{LeasedOutputs: leased}, nil
 }
 
-func (s *walletServer) SpentnessNotifications(req *pb.SpentnessNotificationsRequest,
-	svr pb.WalletService_SpentnessNotificationsServer) error {
+func (s *walletServer) AddressInfo(ctx context.Context, req *pb.AddressInfoRequest) (
+	*pb.AddressInfoResponse, error) {
 
-	if req.NoNotifyUnspent && req.NoNotifySpent {
-		return status.Errorf(codes.InvalidArgument,
-			"no_notify_unspent and no_notify_spent may not both be true")
+	addr, err := decodeAddress(req.Address, s.wallet.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, translateError(err)
 	}
 
-	n := s.wallet.NtfnServer.AccountSpentnessNotifications(req.Account)
-	defer n.Done()
+	details, err := s.wallet.AddressDetails(addr)
+	if err != nil {
+		return nil, translateError(err)
+	}
 
-	ctxDone := svr.Context().Done()
-	for {
-		select {
-		case v := <-n.C:
-			spenderHash, spenderIndex, spent := v.Spender()
-			if (spent && req.NoNotifySpent) || (!spent && req.NoNotifyUnspent) {
-				continue
-			}
-			index := v.Index()
-			resp := pb.SpentnessNotificationsResponse{
-				TransactionHash: v.Hash()[:],
-				OutputIndex:     index,
-			}
-			if spent {
-				resp.Spender = &pb.SpentnessNotificationsResponse_Spender{
-					TransactionHash: spenderHash[:],
-					InputIndex:      spenderIndex,
-				}
-			}
-			err := svr.Send(&resp)
-			if err != nil {
-				return translateError(err)
-			}
+	resp := &pb.AddressInfoResponse{
+		Address:     addr.EncodeAddress(),
+		PkScript:    pkScript,
+		IsMine:      !details.WatchOnly,
+		IsWatchOnly: details.WatchOnly,
+		IsChange:    details.Address.Internal(),
+		AccountName: details.AccountName,
+		KeyScope:    marshalKeyScope(details.KeyScope),
+		Used:        details.Used,
+		Label:       details.Label,
+	}
 
-		case <-ctxDone:
-			return nil
+	switch ma := details.Address.(type) {
+	case waddrmgr.ManagedPubKeyAddress:
+		// The key is serialized as the address commits to it, like the
+		// legacy getaddressinfo does.
+		switch {
+		case ma.AddrType() == waddrmgr.TaprootPubKey:
+			resp.PublicKey = schnorr.SerializePubKey(ma.PubKey())
+		case ma.Compressed():
+			resp.PublicKey = ma.PubKey().SerializeCompressed()
+		default:
+			resp.PublicKey = ma.PubKey().SerializeUncompressed()
+		}
+
+		scope, path, ok := ma.DerivationInfo()
+		if ok {
+			resp.DerivationPath = waddrmgr.FormatKeyPath(scope, path)
+			resp.MasterKeyFingerprint = path.MasterKeyFingerprint
+		}
+
+	case waddrmgr.ManagedScriptAddress:
+		// The script is only available while the wallet is unlocked.
+		script, err := ma.Script()
+		if err == nil {
+			resp.Script = script
 		}
 	}
+
+	return resp, nil
 }
 
-func (s *walletServer) AccountNotifications(req *pb.AccountNotificationsRequest,
-	svr pb.WalletService_AccountNotificationsServer) error {
+func (s *walletServer) VerifyMessage(ctx context.Context, req *pb.VerifyMessageRequest) (
+	*pb.VerifyMessageResponse, error) {
 
-	n := s.wallet.NtfnServer.AccountNotifications()
-	defer n.Done()
+	addr, err := decodeAddress(req.Address, s.wallet.ChainParams())
+	if err != nil {
+		return nil, err
+	}
 
-	ctxDone := svr.Context().Done()
-	for {
-		select {
-		case v := <-n.C:
-			resp := pb.AccountNotificationsResponse{
-				AccountNumber:    v.AccountNumber,
-				AccountName:      v.AccountName,
-				ExternalKeyCount: v.ExternalKeyCount,
-				InternalKeyCount: v.InternalKeyCount,
-				ImportedKeyCount: v.ImportedKeyCount,
-			}
-			err := svr.Send(&resp)
-			if err != nil {
-				return translateError(err)
-			}
+	pubKey, wasCompressed, err := ecdsa.RecoverCompact(
+		req.Signature, signedMessageHash(req.Message),
+	)
+	if err != nil {
+		// A signature a public key can't be recovered from is invalid.
+		return &pb.VerifyMessageResponse{Valid: false}, nil
+	}
 
-		case <-ctxDone:
-			return nil
-		}
+	var serializedPubKey []byte
+	if wasCompressed {
+		serializedPubKey = pubKey.SerializeCompressed()
+	} else {
+		serializedPubKey = pubKey.SerializeUncompressed()
 	}
-}
 
-// StartWalletLoaderService creates an implementation of the WalletLoaderService
-// and registers it with the gRPC server.
-func StartWalletLoaderService(server *grpc.Server, loader *wallet.Loader,
-	activeNet *netparams.Params) {
+	var valid bool
+	switch a := addr.(type) {
+	case *btcutil.AddressPubKeyHash:
+		valid = bytes.Equal(btcutil.Hash160(serializedPubKey), a.Hash160()[:])
+	case *btcutil.AddressPubKey:
+		valid = bytes.Equal(serializedPubKey, a.ScriptAddress())
+	default:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"address type %T not supported", addr)
+	}
 
-	service := &loaderServer{loader: loader, activeNet: activeNet}
-	pb.RegisterWalletLoaderServiceServer(server, service)
+	return &pb.VerifyMessageResponse{Valid: valid}, nil
 }
 
-func (s *loaderServer) CreateWallet(ctx context.Context, req *pb.CreateWalletRequest) (
-	*pb.CreateWalletResponse, error) {
+func (s *walletServer) CreateSimpleTransaction(ctx context.Context,
+	req *pb.CreateSimpleTransactionRequest) (
+	*pb.CreateSimpleTransactionResponse, error) {
 
-	defer func() {
-		zero.Bytes(req.PrivatePassphrase)
+	defer zero.Bytes(req.Passphrase)
+
+	if len(req.Outputs) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"no outputs specified")
+	}
+	outputs := make([]*wire.TxOut, len(req.Outputs))
+	for i, output := range req.Outputs {
+		addr, err := decodeAddress(output.Address, s.wallet.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		outputs[i] = wire.NewTxOut(output.Amount, pkScript)
+	}
+
+	coinSelectionStrategy, err := unmarshalCoinSelectionStrategy(
+		req.CoinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signing the transaction requires the private keys, so the wallet is
+	// only unlocked if this is not a dry run.
+	if !req.DryRun {
+		lock := make(chan time.Time, 1)
+		defer func() {
+			lock <- time.Time{} // send matters, not the value
+		}()
+		err := s.wallet.Unlock(req.Passphrase, lock)
+		if err != nil {
+			return nil, translateError(err)
+		}
+	}
+
+	tx, err := s.wallet.CreateSimpleTx(
+		unmarshalKeyScope(req.KeyScope), req.Account, outputs,
+		req.RequiredConfirmations, btcutil.Amount(req.FeeRateSatPerKb),
+		coinSelectionStrategy, req.DryRun,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var serializedTransaction bytes.Buffer
+	serializedTransaction.Grow(tx.Tx.SerializeSize())
+	err = tx.Tx.Serialize(&serializedTransaction)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	prevAmounts := make([]int64, len(tx.PrevInputValues))
+	for i, amount := range tx.PrevInputValues {
+		prevAmounts[i] = int64(amount)
+	}
+	var totalOutput int64
+	for _, txOut := range tx.Tx.TxOut {
+		totalOutput += txOut.Value
+	}
+
+	return &pb.CreateSimpleTransactionResponse{
+		Transaction:       serializedTransaction.Bytes(),
+		PreviousAmounts:   prevAmounts,
+		PreviousPkScripts: tx.PrevScripts,
+		TotalInput:        int64(tx.TotalInput),
+		Fee:               int64(tx.TotalInput) - totalOutput,
+		ChangeIndex:       int32(tx.ChangeIndex),
+	}, nil
+}
+
+func (s *walletServer) FundPsbt(ctx context.Context, req *pb.FundPsbtRequest) (
+	*pb.FundPsbtResponse, error) {
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid PSBT: %v", err)
+	}
+
+	coinSelectionStrategy, err := unmarshalCoinSelectionStrategy(
+		req.CoinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	changeIndex, err := s.wallet.FundPsbt(
+		packet, unmarshalKeyScope(req.KeyScope),
+		req.RequiredConfirmations, req.Account,
+		btcutil.Amount(req.FeeRateSatPerKb), coinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var serializedPsbt bytes.Buffer
+	err = packet.Serialize(&serializedPsbt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.FundPsbtResponse{
+		Psbt:        serializedPsbt.Bytes(),
+		ChangeIndex: changeIndex,
+	}, nil
+}
+
+func (s *walletServer) FinalizePsbt(ctx context.Context, req *pb.FinalizePsbtRequest) (
+	*pb.FinalizePsbtResponse, error) {
+
+	defer zero.Bytes(req.Passphrase)
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid PSBT: %v", err)
+	}
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{} // send matters, not the value
+	}()
+	err = s.wallet.Unlock(req.Passphrase, lock)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	err = s.wallet.FinalizePsbt(
+		unmarshalKeyScope(req.KeyScope), req.Account, packet,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	finalTx, err := psbt.Extract(packet)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var serializedPsbt, serializedTransaction bytes.Buffer
+	err = packet.Serialize(&serializedPsbt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	err = finalTx.Serialize(&serializedTransaction)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.FinalizePsbtResponse{
+		Psbt:        serializedPsbt.Bytes(),
+		Transaction: serializedTransaction.Bytes(),
+	}, nil
+}
+
+func (s *walletServer) LeaseOutput(ctx context.Context, req *pb.LeaseOutputRequest) (
+	*pb.LeaseOutputResponse, error) {
+
+	id, err := unmarshalLockID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	op, err := unmarshalOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+	if req.DurationSeconds <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"lease duration must be positive")
+	}
+
+	expiration, err := s.wallet.LeaseOutput(
+		id, *op, time.Duration(req.DurationSeconds)*time.Second,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.LeaseOutputResponse{Expiration: expiration.Unix()}, nil
+}
+
+func (s *walletServer) ReleaseOutput(ctx context.Context, req *pb.ReleaseOutputRequest) (
+	*pb.ReleaseOutputResponse, error) {
+
+	id, err := unmarshalLockID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	op, err := unmarshalOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.wallet.ReleaseOutput(id, *op)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.ReleaseOutputResponse{}, nil
+}
+
+func (s *walletServer) ImportAccount(ctx context.Context, req *pb.ImportAccountRequest) (
+	*pb.ImportAccountResponse, error) {
+
+	if req.AccountName == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "account name may not be empty")
+	}
+	accountPubKey, err := hdkeychain.NewKeyFromString(req.ExtendedPublicKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Invalid extended public key: %v", err)
+	}
+
+	var addrType *waddrmgr.AddressType
+	if req.AddressType != pb.AddressType_UNKNOWN {
+		t, err := unmarshalAddressType(req.AddressType)
+		if err != nil {
+			return nil, err
+		}
+		addrType = &t
+	}
+
+	props, err := s.wallet.ImportAccount(
+		req.AccountName, accountPubKey, req.MasterKeyFingerprint,
+		addrType,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.ImportAccountResponse{
+		AccountNumber:    props.AccountNumber,
+		AccountName:      props.AccountName,
+		KeyScope:         marshalKeyScope(props.KeyScope),
+		ExternalKeyCount: props.ExternalKeyCount,
+		InternalKeyCount: props.InternalKeyCount,
+		WatchOnly:        props.IsWatchOnly,
+	}, nil
+}
+
+func (s *walletServer) ImportPublicKey(ctx context.Context, req *pb.ImportPublicKeyRequest) (
+	*pb.ImportPublicKeyResponse, error) {
+
+	pubKey, err := btcec.ParsePubKey(req.PublicKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Invalid public key: %v", err)
+	}
+	addrType, err := unmarshalAddressType(req.AddressType)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.wallet.ImportPublicKey(pubKey, addrType)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.ImportPublicKeyResponse{}, nil
+}
+
+func (s *walletServer) ImportTaprootScript(ctx context.Context,
+	req *pb.ImportTaprootScriptRequest) (*pb.ImportTaprootScriptResponse,
+	error) {
+
+	internalKey, err := schnorr.ParsePubKey(req.InternalKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Invalid internal key: %v", err)
+	}
+
+	tapscript := &waddrmgr.Tapscript{
+		ControlBlock: &txscript.ControlBlock{
+			InternalKey: internalKey,
+		},
+	}
+	switch {
+	case len(req.Leaves) > 0 && len(req.RootHash) > 0:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"leaves and root hash may not be specified simultaneously")
+
+	case len(req.Leaves) > 0:
+		tapscript.Type = waddrmgr.TapscriptTypeFullTree
+		tapscript.Leaves = make([]txscript.TapLeaf, len(req.Leaves))
+		for i, leaf := range req.Leaves {
+			leafVersion := txscript.TapscriptLeafVersion(leaf.LeafVersion)
+			if leaf.LeafVersion == 0 {
+				leafVersion = txscript.BaseLeafVersion
+			}
+			tapscript.Leaves[i] = txscript.NewTapLeaf(
+				leafVersion, leaf.Script,
+			)
+		}
+
+	case len(req.RootHash) == chainhash.HashSize:
+		tapscript.Type = waddrmgr.TaprootKeySpendRootHash
+		tapscript.RootHash = req.RootHash
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"either leaves or a 32 byte root hash must be specified")
+	}
+
+	keyScope := waddrmgr.KeyScopeBIP0086
+	if req.KeyScope != nil {
+		keyScope = *unmarshalKeyScope(req.KeyScope)
+	}
+
+	var bs *waddrmgr.BlockStamp
+	if req.BirthdayHeight > 0 {
+		chainClient := s.wallet.ChainClient()
+		if chainClient == nil {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"blockchain RPC is inactive")
+		}
+		hash, err := chainClient.GetBlockHash(int64(req.BirthdayHeight))
+		if err != nil {
+			return nil, translateError(err)
+		}
+		bs = &waddrmgr.BlockStamp{
+			Hash:   *hash,
+			Height: req.BirthdayHeight,
+		}
+	}
+
+	addr, err := s.wallet.ImportTaprootScript(
+		keyScope, tapscript, bs, 1, false,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.ImportTaprootScriptResponse{
+		Address: addr.Address().EncodeAddress(),
+	}, nil
+}
+
+func (s *walletServer) ImportLegacyKeystore(ctx context.Context,
+	req *pb.ImportLegacyKeystoreRequest) (*pb.ImportLegacyKeystoreResponse,
+	error) {
+
+	defer zero.Bytes(req.Passphrase)
+	defer zero.Bytes(req.KeystorePassphrase)
+
+	ks := new(keystore.Store)
+	if _, err := ks.ReadFrom(bytes.NewReader(req.Keystore)); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Invalid legacy keystore: %v", err)
+	}
+	if ks.Net().Net != s.wallet.ChainParams().Net {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Legacy keystore is for network %s", ks.Net().Name)
+	}
+
+	// The keystore is usually encrypted with the private passphrase of
+	// the wallet it's migrated to.
+	keystorePass := req.KeystorePassphrase
+	if len(keystorePass) == 0 {
+		keystorePass = req.Passphrase
+	}
+	if err := ks.Unlock(keystorePass); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Unable to unlock legacy keystore: %v", err)
+	}
+	defer func() { _ = ks.Lock() }()
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{} // send matters, not the value
+	}()
+	err := s.wallet.Unlock(req.Passphrase, lock)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	keyScope := waddrmgr.KeyScopeBIP0044
+	if req.KeyScope != nil {
+		keyScope = *unmarshalKeyScope(req.KeyScope)
+	}
+
+	report, err := s.wallet.ImportLegacyKeystore(ks, keyScope, req.Rescan)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	resp := &pb.ImportLegacyKeystoreResponse{
+		KeyScope: marshalKeyScope(report.Scope),
+		Account:  report.Account,
+	}
+	for _, addr := range report.Addresses {
+		a := &pb.ImportLegacyKeystoreResponse_Address{
+			Address:        addr.Address.EncodeAddress(),
+			Script:         addr.Script,
+			FirstBlock:     addr.FirstBlock,
+			BirthdayHeight: addr.Birthday.Height,
+			BirthdayHash:   addr.Birthday.Hash[:],
+			Existed:        addr.Existed,
+		}
+		if addr.Err != nil {
+			a.Error = addr.Err.Error()
+		}
+		resp.Addresses = append(resp.Addresses, a)
+	}
+	if report.BirthdayBlock != nil {
+		resp.BirthdayChanged = true
+		resp.BirthdayHeight = report.BirthdayBlock.Height
+		resp.BirthdayHash = report.BirthdayBlock.Hash[:]
+	}
+
+	return resp, nil
+}
+
+func (s *walletServer) LabelTransaction(ctx context.Context, req *pb.LabelTransactionRequest) (
+	*pb.LabelTransactionResponse, error) {
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	}
+
+	err = s.wallet.LabelTransaction(*txHash, req.Label, req.Overwrite)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.LabelTransactionResponse{}, nil
+}
+
+func (s *walletServer) Rescan(ctx context.Context, req *pb.RescanRequest) (
+	*pb.RescanResponse, error) {
+
+	if req.BeginHeight < 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"begin height may not be negative")
+	}
+
+	addrs := make([]btcutil.Address, len(req.Addresses))
+	for i, a := range req.Addresses {
+		addr, err := decodeAddress(a, s.wallet.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+
+	err := s.wallet.RescanFromHeight(req.BeginHeight, addrs)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.RescanResponse{}, nil
+}
+
+func (s *walletServer) RecoveryProgress(ctx context.Context,
+	req *pb.RecoveryProgressRequest) (*pb.RecoveryProgressResponse, error) {
+
+	progress := s.wallet.RecoveryProgress()
+	return &pb.RecoveryProgressResponse{
+		Recovering:  progress.Recovering,
+		Finished:    progress.Finished,
+		StartHeight: progress.StartHeight,
+		Height:      progress.Height,
+		BestHeight:  progress.BestHeight,
+		Progress:    progress.Progress,
+		EtaSeconds:  int64(progress.ETA.Seconds()),
+	}, nil
+}
+
+func (s *walletServer) PassphraseKdf(ctx context.Context,
+	req *pb.PassphraseKdfRequest) (*pb.PassphraseKdfResponse, error) {
+
+	public, private := s.wallet.PassphraseKDFs()
+	return &pb.PassphraseKdfResponse{
+		Public:  marshalKdfParameters(public),
+		Private: marshalKdfParameters(private),
+	}, nil
+}
+
+// marshalKdfParameters returns the protobuf representation of passphrase key
+// derivation options, or nil when there are none.
+func marshalKdfParameters(kdf *waddrmgr.KDFOptions) *pb.KdfParameters {
+	switch {
+	case kdf == nil:
+		return nil
+
+	case kdf.Argon2id != nil:
+		return &pb.KdfParameters{
+			Algorithm:       pb.KdfParameters_ARGON2ID,
+			Argon2Time:      kdf.Argon2id.Time,
+			Argon2MemoryKib: kdf.Argon2id.Memory,
+			Argon2Threads:   uint32(kdf.Argon2id.Threads),
+		}
+
+	default:
+		return &pb.KdfParameters{
+			Algorithm: pb.KdfParameters_SCRYPT,
+			ScryptN:   int64(kdf.N),
+			ScryptR:   int64(kdf.R),
+			ScryptP:   int64(kdf.P),
+		}
+	}
+}
+
+func (s *walletServer) SignMessage(ctx context.Context, req *pb.SignMessageRequest) (
+	*pb.SignMessageResponse, error) {
+
+	defer zero.Bytes(req.Passphrase)
+
+	addr, err := decodeAddress(req.Address, s.wallet.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{} // send matters, not the value
+	}()
+	err = s.wallet.Unlock(req.Passphrase, lock)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	privKey, err := s.wallet.PrivKeyForAddress(addr)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	sig, err := ecdsa.SignCompact(privKey, signedMessageHash(req.Message), true)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.SignMessageResponse{Signature: sig}, nil
+}
+
+func (s *walletServer) BakeMacaroon(ctx context.Context, req *pb.BakeMacaroonRequest) (
+	*pb.BakeMacaroonResponse, error) {
+
+	if s.macaroons == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"macaroon authentication is disabled")
+	}
+
+	var caveats []string
+	if req.IpAddress != "" {
+		ip := net.ParseIP(req.IpAddress)
+		if ip == nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid IP address %q", req.IpAddress)
+		}
+		caveats = append(caveats, macaroons.IPAddrCaveat(ip))
+	}
+	if req.ExpiryTime != 0 {
+		expiry := time.Unix(req.ExpiryTime, 0)
+		caveats = append(caveats, macaroons.TimeBeforeCaveat(expiry))
+	}
+	if req.SpendLimit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"spend limit may not be negative")
+	}
+	if req.SpendLimit != 0 {
+		limit := btcutil.Amount(req.SpendLimit)
+		caveats = append(caveats, macaroons.SpendLimitCaveat(limit))
+	}
+
+	mac, err := s.macaroons.Bake(req.RootKeyId, req.Permissions, caveats...)
+	if errors.Is(err, macaroons.ErrNoRootKeyStore) {
+		return nil, translateError(err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	b, err := mac.MarshalBinary()
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.BakeMacaroonResponse{Macaroon: b}, nil
+}
+
+func (s *walletServer) RevokeMacaroonRootKey(ctx context.Context,
+	req *pb.RevokeMacaroonRootKeyRequest) (*pb.RevokeMacaroonRootKeyResponse, error) {
+
+	if s.macaroons == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"macaroon authentication is disabled")
+	}
+	store := s.macaroons.RootKeyStore()
+	if store == nil {
+		return nil, translateError(macaroons.ErrNoRootKeyStore)
+	}
+
+	err := store.RevokeRootKey(req.RootKeyId)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.RevokeMacaroonRootKeyResponse{}, nil
+}
+
+func (s *walletServer) ListMacaroonRootKeys(ctx context.Context,
+	req *pb.ListMacaroonRootKeysRequest) (*pb.ListMacaroonRootKeysResponse, error) {
+
+	if s.macaroons == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"macaroon authentication is disabled")
+	}
+	store := s.macaroons.RootKeyStore()
+	if store == nil {
+		return nil, translateError(macaroons.ErrNoRootKeyStore)
+	}
+
+	ids, err := store.RootKeyIDs()
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.ListMacaroonRootKeysResponse{RootKeyIds: ids}, nil
+}
+
+// signedMessageHash returns the hash committed to by a signed message.
+func signedMessageHash(message string) []byte {
+	var buf bytes.Buffer
+	_ = wire.WriteVarString(&buf, 0, "Bitcoin Signed Message:\n")
+	_ = wire.WriteVarString(&buf, 0, message)
+	return chainhash.DoubleHashB(buf.Bytes())
+}
+
+// decodeAddress decodes an address and checks that it is intended for the
+// wallet's network.
+func decodeAddress(a string, params *chaincfg.Params) (btcutil.Address, error) {
+	addr, err := btcutil.DecodeAddress(a, params)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Invalid address %q: %v", a, err)
+	}
+	if !addr.IsForNet(params) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Address %q is not intended for use on %s", a, params.Name)
+	}
+	return addr, nil
+}
+
+func marshalKeyScope(scope waddrmgr.KeyScope) *pb.KeyScope {
+	return &pb.KeyScope{
+		Purpose: scope.Purpose,
+		Coin:    scope.Coin,
+	}
+}
+
+func unmarshalKeyScope(scope *pb.KeyScope) *waddrmgr.KeyScope {
+	if scope == nil {
+		return nil
+	}
+	return &waddrmgr.KeyScope{
+		Purpose: scope.Purpose,
+		Coin:    scope.Coin,
+	}
+}
+
+func unmarshalAddressType(t pb.AddressType) (waddrmgr.AddressType, error) {
+	switch t {
+	case pb.AddressType_PUBKEY_HASH:
+		return waddrmgr.PubKeyHash, nil
+	case pb.AddressType_NESTED_WITNESS_PUBKEY_HASH:
+		return waddrmgr.NestedWitnessPubKey, nil
+	case pb.AddressType_WITNESS_PUBKEY_HASH:
+		return waddrmgr.WitnessPubKey, nil
+	case pb.AddressType_TAPROOT_PUBKEY:
+		return waddrmgr.TaprootPubKey, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument,
+			"Unknown address type (%d)", t)
+	}
+}
+
+func unmarshalCoinSelectionStrategy(s pb.CoinSelectionStrategy) (
+	wallet.CoinSelectionStrategy, error) {
+
+	switch s {
+	case pb.CoinSelectionStrategy_LARGEST:
+		return wallet.CoinSelectionLargest, nil
+	case pb.CoinSelectionStrategy_RANDOM:
+		return wallet.CoinSelectionRandom, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Unknown coin selection strategy (%d)", s)
+	}
+}
+
+func marshalOutPoint(op *wire.OutPoint) *pb.OutPoint {
+	return &pb.OutPoint{
+		TransactionHash: op.Hash[:],
+		OutputIndex:     op.Index,
+	}
+}
+
+func unmarshalOutPoint(op *pb.OutPoint) (*wire.OutPoint, error) {
+	if op == nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"outpoint must be specified")
+	}
+	hash, err := chainhash.NewHash(op.TransactionHash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	}
+	return wire.NewOutPoint(hash, op.OutputIndex), nil
+}
+
+func unmarshalLockID(id []byte) (wtxmgr.LockID, error) {
+	var lockID wtxmgr.LockID
+	if len(id) != len(lockID) {
+		return lockID, status.Errorf(codes.InvalidArgument,
+			"lease id must be %d bytes", len(lockID))
+	}
+	copy(lockID[:], id)
+	return lockID, nil
+}
+
+func marshalTransactionInputs(v []wallet.TransactionSummaryInput) []*pb.TransactionDetails_Input {
+	inputs := make([]*pb.TransactionDetails_Input, len(v))
+	for i := range v {
+		input := &v[i]
+		inputs[i] = &pb.TransactionDetails_Input{
+			Index:           input.Index,
+			PreviousAccount: input.PreviousAccount,
+			PreviousAmount:  int64(input.PreviousAmount),
+		}
+	}
+	return inputs
+}
+
+func marshalTransactionOutputs(v []wallet.TransactionSummaryOutput) []*pb.TransactionDetails_Output {
+	outputs := make([]*pb.TransactionDetails_Output, len(v))
+	for i := range v {
+		output := &v[i]
+		outputs[i] = &pb.TransactionDetails_Output{
+			Index:    output.Index,
+			Account:  output.Account,
+			Internal: output.Internal,
+		}
+	}
+	return outputs
+}
+
+func marshalTransactionDetails(v []wallet.TransactionSummary) []*pb.TransactionDetails {
+	txs := make([]*pb.TransactionDetails, len(v))
+	for i := range v {
+		tx := &v[i]
+		txs[i] = &pb.TransactionDetails{
+			Hash:        tx.Hash[:],
+			Transaction: tx.Transaction,
+			Debits:      marshalTransactionInputs(tx.MyInputs),
+			Credits:     marshalTransactionOutputs(tx.MyOutputs),
+			Fee:         int64(tx.Fee),
+			Timestamp:   tx.Timestamp,
+		}
+	}
+	return txs
+}
+
+func marshalBlocks(v []wallet.Block) []*pb.BlockDetails {
+	blocks := make([]*pb.BlockDetails, len(v))
+	for i := range v {
+		block := &v[i]
+		blocks[i] = &pb.BlockDetails{
+			Hash:         block.Hash[:],
+			Height:       block.Height,
+			Timestamp:    block.Timestamp,
+			Transactions: marshalTransactionDetails(block.Transactions),
+		}
+	}
+	return blocks
+}
+
+func marshalHashes(v []*chainhash.Hash) [][]byte {
+	hashes := make([][]byte, len(v))
+	for i, hash := range v {
+		hashes[i] = hash[:]
+	}
+	return hashes
+}
+
+func marshalReplacedTransactions(v []wallet.ReplacedTransaction) []*pb.TransactionNotificationsResponse_ReplacedTransaction {
+	txs := make([]*pb.TransactionNotificationsResponse_ReplacedTransaction, len(v))
+	for i := range v {
+		tx := &v[i]
+		txs[i] = &pb.TransactionNotificationsResponse_ReplacedTransaction{
+			Hash:       tx.Hash[:],
+			ReplacedBy: tx.ReplacedBy[:],
+		}
+	}
+	return txs
+}
+
+func (s *walletServer) TransactionNotifications(req *pb.TransactionNotificationsRequest,
+	svr pb.WalletService_TransactionNotificationsServer) error {
+
+	n := s.wallet.NtfnServer.TransactionNotifications()
+	defer n.Done()
+
+	ctxDone := svr.Context().Done()
+	for {
+		select {
+		case v := <-n.C:
+			resp := pb.TransactionNotificationsResponse{
+				AttachedBlocks:           marshalBlocks(v.AttachedBlocks),
+				DetachedBlocks:           marshalHashes(v.DetachedBlocks),
+				UnminedTransactions:      marshalTransactionDetails(v.UnminedTransactions),
+				UnminedTransactionHashes: marshalHashes(v.UnminedTransactionHashes),
+				EvictedTransactions:      marshalHashes(v.EvictedTransactions),
+				ReplacedTransactions:     marshalReplacedTransactions(v.ReplacedTransactions),
+			}
+			err := svr.Send(&resp)
+			if err != nil {
+				return translateError(err)
+			}
+
+		case <-ctxDone:
+			return nil
+		}
+	}
+}
+
+func (s *walletServer) SpentnessNotifications(req *pb.SpentnessNotificationsRequest,
+	svr pb.WalletService_SpentnessNotificationsServer) error {
+
+	if req.NoNotifyUnspent && req.NoNotifySpent {
+		return status.Errorf(codes.InvalidArgument,
+			"no_notify_unspent and no_notify_spent may not both be true")
+	}
+
+	n := s.wallet.NtfnServer.AccountSpentnessNotifications(req.Account)
+	defer n.Done()
+
+	ctxDone := svr.Context().Done()
+	for {
+		select {
+		case v := <-n.C:
+			spenderHash, spenderIndex, spent := v.Spender()
+			if (spent && req.NoNotifySpent) || (!spent && req.NoNotifyUnspent) {
+				continue
+			}
+			index := v.Index()
+			resp := pb.SpentnessNotificationsResponse{
+				TransactionHash: v.Hash()[:],
+				OutputIndex:     index,
+			}
+			if spent {
+				resp.Spender = &pb.SpentnessNotificationsResponse_Spender{
+					TransactionHash: spenderHash[:],
+					InputIndex:      spenderIndex,
+				}
+			}
+			err := svr.Send(&resp)
+			if err != nil {
+				return translateError(err)
+			}
+
+		case <-ctxDone:
+			return nil
+		}
+	}
+}
+
+func (s *walletServer) AccountNotifications(req *pb.AccountNotificationsRequest,
+	svr pb.WalletService_AccountNotificationsServer) error {
+
+	n := s.wallet.NtfnServer.AccountNotifications()
+	defer n.Done()
+
+	ctxDone := svr.Context().Done()
+	for {
+		select {
+		case v := <-n.C:
+			resp := pb.AccountNotificationsResponse{
+				AccountNumber:    v.AccountNumber,
+				AccountName:      v.AccountName,
+				ExternalKeyCount: v.ExternalKeyCount,
+				InternalKeyCount: v.InternalKeyCount,
+				ImportedKeyCount: v.ImportedKeyCount,
+			}
+			err := svr.Send(&resp)
+			if err != nil {
+				return translateError(err)
+			}
+
+		case <-ctxDone:
+			return nil
+		}
+	}
+}
+
+// StartWalletLoaderService creates an implementation of the WalletLoaderService
+// and registers it with the gRPC server.  The macaroon service, which may be
+// nil, stops using the wallet's root keys when the wallet is closed.
+func StartWalletLoaderService(server *grpc.Server, loader *wallet.Loader,
+	activeNet *netparams.Params, macaroonService *macaroons.Service) {
+
+	service := &loaderServer{
+		loader:    loader,
+		activeNet: activeNet,
+		macaroons: macaroonService,
+	}
+	pb.RegisterWalletLoaderServiceServer(server, service)
+}
+
+func (s *loaderServer) CreateWallet(ctx context.Context, req *pb.CreateWalletRequest) (
+	*pb.CreateWalletResponse, error) {
+
+	defer func() {
+		zero.Bytes(req.PrivatePassphrase)
 		zero.Bytes(req.Seed)
 	}()
 
@@ -742,9 +1898,64 @@ func (s *loaderServer) WalletExists(ctx context.Context, req *pb.WalletExistsReq
 	return &pb.WalletExistsResponse{Exists: exists}, nil
 }
 
+func (s *loaderServer) RollbackTransactionHistory(ctx context.Context,
+	req *pb.RollbackTransactionHistoryRequest) (
+	*pb.RollbackTransactionHistoryResponse, error) {
+
+	// Use an insecure public passphrase when the request's is empty.
+	pubPassphrase := req.PublicPassphrase
+	if len(pubPassphrase) == 0 {
+		pubPassphrase = []byte(wallet.InsecurePubPassphrase)
+	}
+
+	var blockHash *chainhash.Hash
+	if len(req.BlockHash) != 0 {
+		var err error
+		blockHash, err = chainhash.NewHash(req.BlockHash)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"Invalid block hash: %v", err)
+		}
+	} else if req.Height < 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"Negative block height")
+	}
+
+	syncedTo, err := s.loader.RollbackTransactionHistory(
+		pubPassphrase, req.Height, blockHash,
+	)
+	var managerErr waddrmgr.ManagerError
+	switch {
+	case err == wallet.ErrLoaded:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"wallet must be closed")
+	case errors.As(err, &managerErr) &&
+		managerErr.ErrorCode == waddrmgr.ErrBlockNotFound:
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	case err != nil:
+		return nil, translateError(err)
+	}
+
+	return &pb.RollbackTransactionHistoryResponse{
+		Height:    syncedTo.Height,
+		BlockHash: syncedTo.Hash[:],
+	}, nil
+}
+
 func (s *loaderServer) CloseWallet(ctx context.Context, req *pb.CloseWalletRequest) (
 	*pb.CloseWalletResponse, error) {
 
+	// Keep the root keys in memory, so that the macaroons of the wallet
+	// still authorize loader calls once its database is closed.
+	var snapshot *macaroons.RootKeyStore
+	if s.macaroons != nil && s.macaroons.RootKeyStore() != nil {
+		var err error
+		snapshot, err = s.macaroons.RootKeyStore().Snapshot()
+		if err != nil {
+			return nil, translateError(err)
+		}
+	}
+
 	err := s.loader.UnloadWallet()
 	if err == wallet.ErrNotLoaded {
 		return nil, status.Errorf(codes.FailedPrecondition, "wallet is not loaded")
@@ -753,6 +1964,10 @@ func (s *loaderServer) CloseWallet(ctx context.Context, req *pb.CloseWalletReque
 		return nil, translateError(err)
 	}
 
+	if snapshot != nil {
+		s.macaroons.SetRootKeyStore(snapshot)
+	}
+
 	return &pb.CloseWalletResponse{}, nil
 }
 
@@ -0,0 +1,580 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain/chaintest"
+	pb "github.com/btcsuite/btcwallet/rpc/walletrpc"
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	testParams   = &chaincfg.RegressionNetParams
+	testPubPass  = []byte("hello")
+	testPrivPass = []byte("world")
+
+	// testAmount is the amount paid to the test wallet.
+	testAmount = btcutil.Amount(1e8)
+)
+
+// testServer is a wallet server of a wallet synced to an in-memory chain,
+// which paid it testAmount in a mined transaction.
+type testServer struct {
+	*walletServer
+
+	loader  *wallet.Loader
+	chain   *chaintest.Chain
+	payment *wire.MsgTx
+	addr    btcutil.Address
+}
+
+// fastSecretKeyGen derives the passphrase keys of the test wallets quickly, as
+// their strength doesn't matter here.
+func fastSecretKeyGen(passphrase *[]byte,
+	_ *waddrmgr.KDFOptions) (*snacl.SecretKey, error) {
+
+	scrypt := waddrmgr.FastScryptOptions
+	return snacl.NewSecretKey(passphrase, scrypt.N, scrypt.R, scrypt.P)
+}
+
+// newTestServer creates the test wallet and returns its wallet server.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	oldKeyGen := waddrmgr.SetSecretKeyGen(fastSecretKeyGen)
+	t.Cleanup(func() { waddrmgr.SetSecretKeyGen(oldKeyGen) })
+
+	testChain, err := chaintest.NewChain(&chaintest.Config{
+		ChainParams: testParams,
+	})
+	require.NoError(t, err)
+	_, err = testChain.GenerateBlocks(int(testParams.CoinbaseMaturity) + 1)
+	require.NoError(t, err)
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+	loader := wallet.NewLoader(
+		testParams, t.TempDir(), true, wallet.DefaultDBTimeout, 0,
+		wallet.WithWalletSyncRetryInterval(10*time.Millisecond),
+	)
+	w, err := loader.CreateNewWallet(
+		testPubPass, testPrivPass, seed, time.Now(),
+	)
+	require.NoError(t, err)
+
+	client := testChain.NewClient()
+	require.NoError(t, client.Start())
+	w.SynchronizeRPC(client)
+	t.Cleanup(func() {
+		require.NoError(t, loader.UnloadWallet())
+	})
+	require.Eventually(t, w.ChainSynced, 30*time.Second,
+		10*time.Millisecond)
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	payment, err := testChain.SendOutputs(
+		wire.NewTxOut(int64(testAmount), pkScript),
+	)
+	require.NoError(t, err)
+	_, err = testChain.GenerateBlocks(1)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		balance, err := w.CalculateBalance(1)
+		return err == nil && balance == testAmount
+	}, 30*time.Second, 10*time.Millisecond)
+
+	return &testServer{
+		walletServer: &walletServer{wallet: w},
+		loader:       loader,
+		chain:        testChain,
+		payment:      payment,
+		addr:         addr,
+	}
+}
+
+// requireCode checks that err is a gRPC error with the given code.
+func requireCode(t *testing.T, code codes.Code, err error) {
+	t.Helper()
+
+	require.Error(t, err)
+	require.Equal(t, code, status.Code(err), err.Error())
+}
+
+// newExternalAddr returns a P2WPKH address the test wallet doesn't know.
+func newExternalAddr(t *testing.T) btcutil.Address {
+	t.Helper()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(privKey.PubKey().SerializeCompressed()),
+		testParams,
+	)
+	require.NoError(t, err)
+
+	return addr
+}
+
+// requireValidTx checks that the inputs of tx are validly signed, using the
+// outputs they spend.
+func requireValidTx(t *testing.T, tx *wire.MsgTx,
+	prevOuts map[wire.OutPoint]*wire.TxOut) {
+
+	t.Helper()
+
+	fetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+	for i, txIn := range tx.TxIn {
+		prevOut := prevOuts[txIn.PreviousOutPoint]
+		require.NotNil(t, prevOut)
+
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+			nil, sigHashes, prevOut.Value, fetcher,
+		)
+		require.NoError(t, err)
+		require.NoError(t, vm.Execute())
+	}
+}
+
+// TestGetTransaction tests the GetTransaction handler.
+func TestGetTransaction(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := s.GetTransaction(ctx, &pb.GetTransactionRequest{
+		TransactionHash: []byte{1, 2, 3},
+	})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = s.GetTransaction(ctx, &pb.GetTransactionRequest{
+		TransactionHash: make([]byte, 32),
+	})
+	requireCode(t, codes.NotFound, err)
+
+	hash := s.payment.TxHash()
+	resp, err := s.GetTransaction(ctx, &pb.GetTransactionRequest{
+		TransactionHash: hash[:],
+	})
+	require.NoError(t, err)
+	require.Equal(t, hash[:], resp.Transaction.Hash)
+	require.Len(t, resp.Transaction.Credits, 1)
+	require.Equal(t, uint32(0), resp.Transaction.Credits[0].Index)
+	require.Equal(t, uint32(0), resp.Transaction.Credits[0].Account)
+
+	bestHash, bestHeight := s.chain.BestBlock()
+	require.Equal(t, bestHash[:], resp.BlockHash)
+	require.Equal(t, bestHeight, resp.BlockHeight)
+}
+
+// TestListUnspent tests the ListUnspent handler.
+func TestListUnspent(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	invalid := []*pb.ListUnspentRequest{
+		{MinConfirmations: -1},
+		{MinConfirmations: 2, MaxConfirmations: 1},
+	}
+	for _, req := range invalid {
+		_, err := s.ListUnspent(ctx, req)
+		requireCode(t, codes.InvalidArgument, err)
+	}
+
+	resp, err := s.ListUnspent(ctx, &pb.ListUnspentRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Unspent, 1)
+
+	unspent := resp.Unspent[0]
+	hash := s.payment.TxHash()
+	pkScript, err := txscript.PayToAddrScript(s.addr)
+	require.NoError(t, err)
+	require.Equal(t, hash[:], unspent.Outpoint.TransactionHash)
+	require.Equal(t, s.addr.EncodeAddress(), unspent.Address)
+	require.Equal(t, "default", unspent.AccountName)
+	require.Equal(t, int64(testAmount), unspent.Amount)
+	require.Equal(t, pkScript, unspent.PkScript)
+	require.Equal(t, int64(1), unspent.Confirmations)
+	require.True(t, unspent.Spendable)
+
+	// The output isn't listed with more confirmations than it has.
+	resp, err = s.ListUnspent(ctx, &pb.ListUnspentRequest{
+		MinConfirmations: 2,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Unspent)
+}
+
+// TestListLeasedOutputs tests the ListLeasedOutputs handler.
+func TestListLeasedOutputs(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	resp, err := s.ListLeasedOutputs(ctx, &pb.ListLeasedOutputsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.LeasedOutputs)
+
+	id := wtxmgr.LockID{1}
+	op := wire.OutPoint{Hash: s.payment.TxHash()}
+	expiration, err := s.wallet.LeaseOutput(id, op, time.Hour)
+	require.NoError(t, err)
+
+	resp, err = s.ListLeasedOutputs(ctx, &pb.ListLeasedOutputsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.LeasedOutputs, 1)
+
+	leased := resp.LeasedOutputs[0]
+	require.Equal(t, id[:], leased.Id)
+	require.Equal(t, marshalOutPoint(&op), leased.Outpoint)
+	require.Equal(t, expiration.Unix(), leased.Expiration)
+	require.Equal(t, int64(testAmount), leased.Value)
+	require.Equal(t, s.payment.TxOut[0].PkScript, leased.PkScript)
+}
+
+// TestAddressInfo tests the AddressInfo handler, and that the public keys are
+// serialized the way their addresses use them.
+func TestAddressInfo(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	mainNetAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), &chaincfg.MainNetParams,
+	)
+	require.NoError(t, err)
+	for _, addr := range []string{"bogus", mainNetAddr.EncodeAddress()} {
+		_, err := s.AddressInfo(ctx, &pb.AddressInfoRequest{
+			Address: addr,
+		})
+		requireCode(t, codes.InvalidArgument, err)
+	}
+
+	_, err = s.AddressInfo(ctx, &pb.AddressInfoRequest{
+		Address: newExternalAddr(t).EncodeAddress(),
+	})
+	requireCode(t, codes.NotFound, err)
+
+	addressInfo := func(addr btcutil.Address) *pb.AddressInfoResponse {
+		t.Helper()
+
+		resp, err := s.AddressInfo(ctx, &pb.AddressInfoRequest{
+			Address: addr.EncodeAddress(),
+		})
+		require.NoError(t, err)
+		require.True(t, resp.IsMine)
+		require.False(t, resp.IsWatchOnly)
+
+		pkScript, err := txscript.PayToAddrScript(addr)
+		require.NoError(t, err)
+		require.Equal(t, pkScript, resp.PkScript)
+
+		return resp
+	}
+
+	// A P2WPKH address uses the compressed key.
+	resp := addressInfo(s.addr)
+	require.Equal(t, "default", resp.AccountName)
+	require.True(t, resp.Used)
+	require.False(t, resp.IsChange)
+	require.Equal(t, "m/84'/0'/0'/0/0", resp.DerivationPath)
+	require.Len(t, resp.PublicKey, btcec.PubKeyBytesLenCompressed)
+	require.Equal(t, btcutil.Hash160(resp.PublicKey),
+		s.addr.ScriptAddress())
+
+	// A taproot address uses the x-only key.
+	trAddr, err := s.wallet.CurrentAddress(0, waddrmgr.KeyScopeBIP0086)
+	require.NoError(t, err)
+	resp = addressInfo(trAddr)
+	require.Len(t, resp.PublicKey, schnorr.PubKeyBytesLen)
+
+	// An imported uncompressed key is serialized uncompressed, as its
+	// P2PKH address commits to it this way.
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	wif, err := btcutil.NewWIF(privKey, testParams, false)
+	require.NoError(t, err)
+	require.NoError(t, s.wallet.Unlock(testPrivPass, nil))
+	encoded, err := s.wallet.ImportPrivateKey(
+		waddrmgr.KeyScopeBIP0044, wif, nil, false,
+	)
+	require.NoError(t, err)
+	importedAddr, err := btcutil.DecodeAddress(encoded, testParams)
+	require.NoError(t, err)
+
+	resp = addressInfo(importedAddr)
+	require.Equal(t, waddrmgr.ImportedAddrAccountName, resp.AccountName)
+	require.Equal(t, privKey.PubKey().SerializeUncompressed(),
+		resp.PublicKey)
+	require.Empty(t, resp.DerivationPath)
+}
+
+// TestVerifyMessage tests the VerifyMessage handler.
+func TestVerifyMessage(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	const message = "test message"
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	p2pkhAddr := func(compressed bool) string {
+		pubKey := privKey.PubKey().SerializeUncompressed()
+		if compressed {
+			pubKey = privKey.PubKey().SerializeCompressed()
+		}
+		addr, err := btcutil.NewAddressPubKeyHash(
+			btcutil.Hash160(pubKey), testParams,
+		)
+		require.NoError(t, err)
+		return addr.EncodeAddress()
+	}
+	sign := func(message string, compressed bool) []byte {
+		sig, err := ecdsa.SignCompact(
+			privKey, signedMessageHash(message), compressed,
+		)
+		require.NoError(t, err)
+		return sig
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature []byte
+		valid     bool
+	}{{
+		name:      "compressed",
+		address:   p2pkhAddr(true),
+		message:   message,
+		signature: sign(message, true),
+		valid:     true,
+	}, {
+		name:      "uncompressed",
+		address:   p2pkhAddr(false),
+		message:   message,
+		signature: sign(message, false),
+		valid:     true,
+	}, {
+		name:      "other message",
+		address:   p2pkhAddr(true),
+		message:   "other message",
+		signature: sign(message, true),
+	}, {
+		name:      "other key serialization",
+		address:   p2pkhAddr(false),
+		message:   message,
+		signature: sign(message, true),
+	}, {
+		name:      "malformed signature",
+		address:   p2pkhAddr(true),
+		message:   message,
+		signature: []byte{1, 2, 3},
+	}}
+	for _, test := range tests {
+		resp, err := s.VerifyMessage(ctx, &pb.VerifyMessageRequest{
+			Address:   test.address,
+			Message:   test.message,
+			Signature: test.signature,
+		})
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.valid, resp.Valid, test.name)
+	}
+
+	// Only P2PKH and P2PK addresses can be verified.
+	for _, addr := range []string{"bogus", s.addr.EncodeAddress()} {
+		_, err = s.VerifyMessage(ctx, &pb.VerifyMessageRequest{
+			Address:   addr,
+			Message:   message,
+			Signature: sign(message, true),
+		})
+		requireCode(t, codes.InvalidArgument, err)
+	}
+}
+
+// TestCreateSimpleTransaction tests the CreateSimpleTransaction handler.
+func TestCreateSimpleTransaction(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	output := &pb.CreateSimpleTransactionRequest_Output{
+		Address: newExternalAddr(t).EncodeAddress(),
+		Amount:  5e7,
+	}
+	newRequest := func() *pb.CreateSimpleTransactionRequest {
+		return &pb.CreateSimpleTransactionRequest{
+			Outputs:               []*pb.CreateSimpleTransactionRequest_Output{output},
+			RequiredConfirmations: 1,
+			FeeRateSatPerKb:       2000,
+			Passphrase:            append([]byte(nil), testPrivPass...),
+		}
+	}
+
+	invalid := []func(*pb.CreateSimpleTransactionRequest){
+		func(req *pb.CreateSimpleTransactionRequest) {
+			req.Outputs = nil
+		},
+		func(req *pb.CreateSimpleTransactionRequest) {
+			req.Outputs = []*pb.CreateSimpleTransactionRequest_Output{{
+				Address: "bogus", Amount: 5e7,
+			}}
+		},
+		func(req *pb.CreateSimpleTransactionRequest) {
+			req.CoinSelectionStrategy = 99
+		},
+		func(req *pb.CreateSimpleTransactionRequest) {
+			req.Passphrase = []byte("wrong")
+		},
+	}
+	for i, modify := range invalid {
+		req := newRequest()
+		modify(req)
+		_, err := s.CreateSimpleTransaction(ctx, req)
+		requireCode(t, codes.InvalidArgument, err)
+		require.True(t, s.wallet.Locked(), "request %d", i)
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		req := newRequest()
+		req.DryRun = dryRun
+
+		// A dry run takes no passphrase, but the wallet still has to
+		// be unlocked to create the transaction.
+		if dryRun {
+			req.Passphrase = nil
+			err := s.wallet.Unlock(testPrivPass, nil)
+			require.NoError(t, err)
+		}
+		resp, err := s.CreateSimpleTransaction(ctx, req)
+		require.NoError(t, err)
+
+		var tx wire.MsgTx
+		err = tx.Deserialize(bytes.NewReader(resp.Transaction))
+		require.NoError(t, err)
+		require.Len(t, tx.TxIn, 1)
+		require.Equal(t, s.payment.TxHash(),
+			tx.TxIn[0].PreviousOutPoint.Hash)
+		require.Len(t, tx.TxOut, 2)
+		require.Equal(t, []int64{int64(testAmount)},
+			resp.PreviousAmounts)
+		require.Equal(t, [][]byte{s.payment.TxOut[0].PkScript},
+			resp.PreviousPkScripts)
+		require.Equal(t, int64(testAmount), resp.TotalInput)
+		require.Positive(t, resp.Fee)
+
+		change := tx.TxOut[resp.ChangeIndex]
+		require.Equal(t, int64(testAmount)-5e7-resp.Fee, change.Value)
+
+		// Only the transaction of a real run is signed.
+		if dryRun {
+			continue
+		}
+		requireValidTx(t, &tx, map[wire.OutPoint]*wire.TxOut{
+			tx.TxIn[0].PreviousOutPoint: s.payment.TxOut[0],
+		})
+	}
+}
+
+// TestFundFinalizePsbt tests that a PSBT funded by the FundPsbt handler is
+// signed by the FinalizePsbt handler.
+func TestFundFinalizePsbt(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := s.FundPsbt(ctx, &pb.FundPsbtRequest{Psbt: []byte("bogus")})
+	requireCode(t, codes.InvalidArgument, err)
+	_, err = s.FinalizePsbt(ctx, &pb.FinalizePsbtRequest{
+		Psbt:       []byte("bogus"),
+		Passphrase: append([]byte(nil), testPrivPass...),
+	})
+	requireCode(t, codes.InvalidArgument, err)
+
+	pkScript, err := txscript.PayToAddrScript(newExternalAddr(t))
+	require.NoError(t, err)
+	packet, err := psbt.New(
+		nil, []*wire.TxOut{wire.NewTxOut(5e7, pkScript)}, 2, 0, nil,
+	)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, packet.Serialize(&buf))
+
+	_, err = s.FundPsbt(ctx, &pb.FundPsbtRequest{
+		Psbt:                  buf.Bytes(),
+		RequiredConfirmations: 1,
+		FeeRateSatPerKb:       2000,
+		CoinSelectionStrategy: 99,
+	})
+	requireCode(t, codes.InvalidArgument, err)
+
+	// Funding takes no passphrase, but needs the wallet unlocked.
+	require.NoError(t, s.wallet.Unlock(testPrivPass, nil))
+	funded, err := s.FundPsbt(ctx, &pb.FundPsbtRequest{
+		Psbt:                  buf.Bytes(),
+		RequiredConfirmations: 1,
+		FeeRateSatPerKb:       2000,
+	})
+	require.NoError(t, err)
+
+	fundedPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(funded.Psbt), false,
+	)
+	require.NoError(t, err)
+	require.Len(t, fundedPacket.UnsignedTx.TxIn, 1)
+	require.Len(t, fundedPacket.UnsignedTx.TxOut, 2)
+	require.NotEqual(t, -1, funded.ChangeIndex)
+	prevOut := fundedPacket.UnsignedTx.TxIn[0].PreviousOutPoint
+	require.Equal(t, s.payment.TxHash(), prevOut.Hash)
+
+	_, err = s.FinalizePsbt(ctx, &pb.FinalizePsbtRequest{
+		Psbt:       funded.Psbt,
+		Passphrase: []byte("wrong"),
+	})
+	requireCode(t, codes.InvalidArgument, err)
+
+	finalized, err := s.FinalizePsbt(ctx, &pb.FinalizePsbtRequest{
+		Psbt:       funded.Psbt,
+		Passphrase: append([]byte(nil), testPrivPass...),
+	})
+	require.NoError(t, err)
+
+	var tx wire.MsgTx
+	require.NoError(t, tx.Deserialize(bytes.NewReader(finalized.Transaction)))
+	require.Equal(t, fundedPacket.UnsignedTx.TxHash(), tx.TxHash())
+	requireValidTx(t, &tx, map[wire.OutPoint]*wire.TxOut{
+		prevOut: s.payment.TxOut[prevOut.Index],
+	})
+
+	// The finalized PSBT holds the same transaction.
+	finalPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(finalized.Psbt), false,
+	)
+	require.NoError(t, err)
+	require.True(t, finalPacket.IsComplete())
+	extracted, err := psbt.Extract(finalPacket)
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), extracted.TxHash())
+}
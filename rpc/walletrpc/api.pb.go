@@ -5,9 +5,11 @@
 Package walletrpc is a generated protocol buffer package.
 
 It is generated from these files:
+
 	api.proto
 
 It has these top-level messages:
+
 	VersionRequest
 	VersionResponse
 	TransactionDetails
@@ -35,12 +37,59 @@ It has these top-level messages:
 	GetTransactionsResponse
 	ChangePassphraseRequest
 	ChangePassphraseResponse
+	KdfParameters
 	FundTransactionRequest
 	FundTransactionResponse
 	SignTransactionRequest
 	SignTransactionResponse
 	PublishTransactionRequest
 	PublishTransactionResponse
+	KeyScope
+	OutPoint
+	GetTransactionRequest
+	GetTransactionResponse
+	ListUnspentRequest
+	ListUnspentResponse
+	ListLeasedOutputsRequest
+	ListLeasedOutputsResponse
+	AddressInfoRequest
+	AddressInfoResponse
+	VerifyMessageRequest
+	VerifyMessageResponse
+	CreateSimpleTransactionRequest
+	CreateSimpleTransactionResponse
+	FundPsbtRequest
+	FundPsbtResponse
+	FinalizePsbtRequest
+	FinalizePsbtResponse
+	LeaseOutputRequest
+	LeaseOutputResponse
+	ReleaseOutputRequest
+	ReleaseOutputResponse
+	ImportAccountRequest
+	ImportAccountResponse
+	ImportPublicKeyRequest
+	ImportPublicKeyResponse
+	ImportTaprootScriptRequest
+	ImportTaprootScriptResponse
+	ImportLegacyKeystoreRequest
+	ImportLegacyKeystoreResponse
+	LabelTransactionRequest
+	LabelTransactionResponse
+	RescanRequest
+	RescanResponse
+	RecoveryProgressRequest
+	RecoveryProgressResponse
+	PassphraseKdfRequest
+	PassphraseKdfResponse
+	SignMessageRequest
+	SignMessageResponse
+	BakeMacaroonRequest
+	BakeMacaroonResponse
+	RevokeMacaroonRootKeyRequest
+	RevokeMacaroonRootKeyResponse
+	ListMacaroonRootKeysRequest
+	ListMacaroonRootKeysResponse
 	TransactionNotificationsRequest
 	TransactionNotificationsResponse
 	SpentnessNotificationsRequest
@@ -55,20 +104,19 @@ It has these top-level messages:
 	CloseWalletResponse
 	WalletExistsRequest
 	WalletExistsResponse
+	RollbackTransactionHistoryRequest
+	RollbackTransactionHistoryResponse
 	StartConsensusRpcRequest
 	StartConsensusRpcResponse
 */
 package walletrpc
 
-import (
-	fmt "fmt"
-
-	proto "github.com/golang/protobuf/proto"
-
-	math "math"
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
 
+import (
 	context "golang.org/x/net/context"
-
 	grpc "google.golang.org/grpc"
 )
 
@@ -83,6 +131,57 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type AddressType int32
+
+const (
+	AddressType_UNKNOWN                    AddressType = 0
+	AddressType_PUBKEY_HASH                AddressType = 1
+	AddressType_NESTED_WITNESS_PUBKEY_HASH AddressType = 2
+	AddressType_WITNESS_PUBKEY_HASH        AddressType = 3
+	AddressType_TAPROOT_PUBKEY             AddressType = 4
+)
+
+var AddressType_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "PUBKEY_HASH",
+	2: "NESTED_WITNESS_PUBKEY_HASH",
+	3: "WITNESS_PUBKEY_HASH",
+	4: "TAPROOT_PUBKEY",
+}
+var AddressType_value = map[string]int32{
+	"UNKNOWN":                    0,
+	"PUBKEY_HASH":                1,
+	"NESTED_WITNESS_PUBKEY_HASH": 2,
+	"WITNESS_PUBKEY_HASH":        3,
+	"TAPROOT_PUBKEY":             4,
+}
+
+func (x AddressType) String() string {
+	return proto.EnumName(AddressType_name, int32(x))
+}
+func (AddressType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type CoinSelectionStrategy int32
+
+const (
+	CoinSelectionStrategy_LARGEST CoinSelectionStrategy = 0
+	CoinSelectionStrategy_RANDOM  CoinSelectionStrategy = 1
+)
+
+var CoinSelectionStrategy_name = map[int32]string{
+	0: "LARGEST",
+	1: "RANDOM",
+}
+var CoinSelectionStrategy_value = map[string]int32{
+	"LARGEST": 0,
+	"RANDOM":  1,
+}
+
+func (x CoinSelectionStrategy) String() string {
+	return proto.EnumName(CoinSelectionStrategy_name, int32(x))
+}
+func (CoinSelectionStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type NextAddressRequest_Kind int32
 
 const (
@@ -127,6 +226,27 @@ func (ChangePassphraseRequest_Key) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor0, []int{25, 0}
 }
 
+type KdfParameters_Algorithm int32
+
+const (
+	KdfParameters_SCRYPT   KdfParameters_Algorithm = 0
+	KdfParameters_ARGON2ID KdfParameters_Algorithm = 1
+)
+
+var KdfParameters_Algorithm_name = map[int32]string{
+	0: "SCRYPT",
+	1: "ARGON2ID",
+}
+var KdfParameters_Algorithm_value = map[string]int32{
+	"SCRYPT":   0,
+	"ARGON2ID": 1,
+}
+
+func (x KdfParameters_Algorithm) String() string {
+	return proto.EnumName(KdfParameters_Algorithm_name, int32(x))
+}
+func (KdfParameters_Algorithm) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{27, 0} }
+
 type VersionRequest struct {
 }
 
@@ -847,9 +967,12 @@ func (m *GetTransactionsResponse) GetUnminedTransactions() []*TransactionDetails
 }
 
 type ChangePassphraseRequest struct {
-	Key           ChangePassphraseRequest_Key `protobuf:"varint,1,opt,name=key,enum=walletrpc.ChangePassphraseRequest_Key" json:"key,omitempty"`
-	OldPassphrase []byte                      `protobuf:"bytes,2,opt,name=old_passphrase,json=oldPassphrase,proto3" json:"old_passphrase,omitempty"`
-	NewPassphrase []byte                      `protobuf:"bytes,3,opt,name=new_passphrase,json=newPassphrase,proto3" json:"new_passphrase,omitempty"`
+	Key                      ChangePassphraseRequest_Key `protobuf:"varint,1,opt,name=key,enum=walletrpc.ChangePassphraseRequest_Key" json:"key,omitempty"`
+	OldPassphrase            []byte                      `protobuf:"bytes,2,opt,name=old_passphrase,json=oldPassphrase,proto3" json:"old_passphrase,omitempty"`
+	NewPassphrase            []byte                      `protobuf:"bytes,3,opt,name=new_passphrase,json=newPassphrase,proto3" json:"new_passphrase,omitempty"`
+	Kdf                      *KdfParameters              `protobuf:"bytes,4,opt,name=kdf" json:"kdf,omitempty"`
+	RecommendedKdf           bool                        `protobuf:"varint,5,opt,name=recommended_kdf,json=recommendedKdf" json:"recommended_kdf,omitempty"`
+	TargetUnlockMilliseconds int64                       `protobuf:"varint,6,opt,name=target_unlock_milliseconds,json=targetUnlockMilliseconds" json:"target_unlock_milliseconds,omitempty"`
 }
 
 func (m *ChangePassphraseRequest) Reset()                    { *m = ChangePassphraseRequest{} }
@@ -878,6 +1001,27 @@ func (m *ChangePassphraseRequest) GetNewPassphrase() []byte {
 	return nil
 }
 
+func (m *ChangePassphraseRequest) GetKdf() *KdfParameters {
+	if m != nil {
+		return m.Kdf
+	}
+	return nil
+}
+
+func (m *ChangePassphraseRequest) GetRecommendedKdf() bool {
+	if m != nil {
+		return m.RecommendedKdf
+	}
+	return false
+}
+
+func (m *ChangePassphraseRequest) GetTargetUnlockMilliseconds() int64 {
+	if m != nil {
+		return m.TargetUnlockMilliseconds
+	}
+	return 0
+}
+
 type ChangePassphraseResponse struct {
 }
 
@@ -886,6 +1030,70 @@ func (m *ChangePassphraseResponse) String() string            { return proto.Com
 func (*ChangePassphraseResponse) ProtoMessage()               {}
 func (*ChangePassphraseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
+type KdfParameters struct {
+	Algorithm       KdfParameters_Algorithm `protobuf:"varint,1,opt,name=algorithm,enum=walletrpc.KdfParameters_Algorithm" json:"algorithm,omitempty"`
+	ScryptN         int64                   `protobuf:"varint,2,opt,name=scrypt_n,json=scryptN" json:"scrypt_n,omitempty"`
+	ScryptR         int64                   `protobuf:"varint,3,opt,name=scrypt_r,json=scryptR" json:"scrypt_r,omitempty"`
+	ScryptP         int64                   `protobuf:"varint,4,opt,name=scrypt_p,json=scryptP" json:"scrypt_p,omitempty"`
+	Argon2Time      uint32                  `protobuf:"varint,5,opt,name=argon2_time,json=argon2Time" json:"argon2_time,omitempty"`
+	Argon2MemoryKib uint32                  `protobuf:"varint,6,opt,name=argon2_memory_kib,json=argon2MemoryKib" json:"argon2_memory_kib,omitempty"`
+	Argon2Threads   uint32                  `protobuf:"varint,7,opt,name=argon2_threads,json=argon2Threads" json:"argon2_threads,omitempty"`
+}
+
+func (m *KdfParameters) Reset()                    { *m = KdfParameters{} }
+func (m *KdfParameters) String() string            { return proto.CompactTextString(m) }
+func (*KdfParameters) ProtoMessage()               {}
+func (*KdfParameters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *KdfParameters) GetAlgorithm() KdfParameters_Algorithm {
+	if m != nil {
+		return m.Algorithm
+	}
+	return KdfParameters_SCRYPT
+}
+
+func (m *KdfParameters) GetScryptN() int64 {
+	if m != nil {
+		return m.ScryptN
+	}
+	return 0
+}
+
+func (m *KdfParameters) GetScryptR() int64 {
+	if m != nil {
+		return m.ScryptR
+	}
+	return 0
+}
+
+func (m *KdfParameters) GetScryptP() int64 {
+	if m != nil {
+		return m.ScryptP
+	}
+	return 0
+}
+
+func (m *KdfParameters) GetArgon2Time() uint32 {
+	if m != nil {
+		return m.Argon2Time
+	}
+	return 0
+}
+
+func (m *KdfParameters) GetArgon2MemoryKib() uint32 {
+	if m != nil {
+		return m.Argon2MemoryKib
+	}
+	return 0
+}
+
+func (m *KdfParameters) GetArgon2Threads() uint32 {
+	if m != nil {
+		return m.Argon2Threads
+	}
+	return 0
+}
+
 type FundTransactionRequest struct {
 	Account                  uint32 `protobuf:"varint,1,opt,name=account" json:"account,omitempty"`
 	TargetAmount             int64  `protobuf:"varint,2,opt,name=target_amount,json=targetAmount" json:"target_amount,omitempty"`
@@ -897,7 +1105,7 @@ type FundTransactionRequest struct {
 func (m *FundTransactionRequest) Reset()                    { *m = FundTransactionRequest{} }
 func (m *FundTransactionRequest) String() string            { return proto.CompactTextString(m) }
 func (*FundTransactionRequest) ProtoMessage()               {}
-func (*FundTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*FundTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
 func (m *FundTransactionRequest) GetAccount() uint32 {
 	if m != nil {
@@ -943,7 +1151,7 @@ type FundTransactionResponse struct {
 func (m *FundTransactionResponse) Reset()                    { *m = FundTransactionResponse{} }
 func (m *FundTransactionResponse) String() string            { return proto.CompactTextString(m) }
 func (*FundTransactionResponse) ProtoMessage()               {}
-func (*FundTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*FundTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
 
 func (m *FundTransactionResponse) GetSelectedOutputs() []*FundTransactionResponse_PreviousOutput {
 	if m != nil {
@@ -981,7 +1189,7 @@ func (m *FundTransactionResponse_PreviousOutput) Reset() {
 func (m *FundTransactionResponse_PreviousOutput) String() string { return proto.CompactTextString(m) }
 func (*FundTransactionResponse_PreviousOutput) ProtoMessage()    {}
 func (*FundTransactionResponse_PreviousOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{28, 0}
+	return fileDescriptor0, []int{29, 0}
 }
 
 func (m *FundTransactionResponse_PreviousOutput) GetTransactionHash() []byte {
@@ -1040,7 +1248,7 @@ type SignTransactionRequest struct {
 func (m *SignTransactionRequest) Reset()                    { *m = SignTransactionRequest{} }
 func (m *SignTransactionRequest) String() string            { return proto.CompactTextString(m) }
 func (*SignTransactionRequest) ProtoMessage()               {}
-func (*SignTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*SignTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 func (m *SignTransactionRequest) GetPassphrase() []byte {
 	if m != nil {
@@ -1071,7 +1279,7 @@ type SignTransactionResponse struct {
 func (m *SignTransactionResponse) Reset()                    { *m = SignTransactionResponse{} }
 func (m *SignTransactionResponse) String() string            { return proto.CompactTextString(m) }
 func (*SignTransactionResponse) ProtoMessage()               {}
-func (*SignTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*SignTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
 
 func (m *SignTransactionResponse) GetTransaction() []byte {
 	if m != nil {
@@ -1094,7 +1302,7 @@ type PublishTransactionRequest struct {
 func (m *PublishTransactionRequest) Reset()                    { *m = PublishTransactionRequest{} }
 func (m *PublishTransactionRequest) String() string            { return proto.CompactTextString(m) }
 func (*PublishTransactionRequest) ProtoMessage()               {}
-func (*PublishTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+func (*PublishTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
 
 func (m *PublishTransactionRequest) GetSignedTransaction() []byte {
 	if m != nil {
@@ -1109,1100 +1317,3508 @@ type PublishTransactionResponse struct {
 func (m *PublishTransactionResponse) Reset()                    { *m = PublishTransactionResponse{} }
 func (m *PublishTransactionResponse) String() string            { return proto.CompactTextString(m) }
 func (*PublishTransactionResponse) ProtoMessage()               {}
-func (*PublishTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+func (*PublishTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
-type TransactionNotificationsRequest struct {
+type KeyScope struct {
+	Purpose uint32 `protobuf:"varint,1,opt,name=purpose" json:"purpose,omitempty"`
+	Coin    uint32 `protobuf:"varint,2,opt,name=coin" json:"coin,omitempty"`
 }
 
-func (m *TransactionNotificationsRequest) Reset()         { *m = TransactionNotificationsRequest{} }
-func (m *TransactionNotificationsRequest) String() string { return proto.CompactTextString(m) }
-func (*TransactionNotificationsRequest) ProtoMessage()    {}
-func (*TransactionNotificationsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{33}
+func (m *KeyScope) Reset()                    { *m = KeyScope{} }
+func (m *KeyScope) String() string            { return proto.CompactTextString(m) }
+func (*KeyScope) ProtoMessage()               {}
+func (*KeyScope) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *KeyScope) GetPurpose() uint32 {
+	if m != nil {
+		return m.Purpose
+	}
+	return 0
 }
 
-type TransactionNotificationsResponse struct {
-	// Sorted by increasing height.  This is a repeated field so many new blocks
-	// in a new best chain can be notified at once during a reorganize.
-	AttachedBlocks []*BlockDetails `protobuf:"bytes,1,rep,name=attached_blocks,json=attachedBlocks" json:"attached_blocks,omitempty"`
-	// If there was a chain reorganize, there may have been blocks with wallet
-	// transactions that are no longer in the best chain.  These are those
-	// block's hashes.
-	DetachedBlocks [][]byte `protobuf:"bytes,2,rep,name=detached_blocks,json=detachedBlocks,proto3" json:"detached_blocks,omitempty"`
-	// Any new unmined transactions are included here.  These unmined transactions
-	// refer to the current best chain, so transactions from detached blocks may
-	// be moved to mempool and included here if they are not mined or double spent
-	// in the new chain.  Additonally, if no new blocks were attached but a relevant
-	// unmined transaction is seen by the wallet, it will be reported here.
-	UnminedTransactions []*TransactionDetails `protobuf:"bytes,3,rep,name=unmined_transactions,json=unminedTransactions" json:"unmined_transactions,omitempty"`
-	// Instead of notifying all of the removed unmined transactions,
-	// just send all of the current hashes.
-	UnminedTransactionHashes [][]byte `protobuf:"bytes,4,rep,name=unmined_transaction_hashes,json=unminedTransactionHashes,proto3" json:"unmined_transaction_hashes,omitempty"`
+func (m *KeyScope) GetCoin() uint32 {
+	if m != nil {
+		return m.Coin
+	}
+	return 0
 }
 
-func (m *TransactionNotificationsResponse) Reset()         { *m = TransactionNotificationsResponse{} }
-func (m *TransactionNotificationsResponse) String() string { return proto.CompactTextString(m) }
-func (*TransactionNotificationsResponse) ProtoMessage()    {}
-func (*TransactionNotificationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{34}
+type OutPoint struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex     uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
 }
 
-func (m *TransactionNotificationsResponse) GetAttachedBlocks() []*BlockDetails {
+func (m *OutPoint) Reset()                    { *m = OutPoint{} }
+func (m *OutPoint) String() string            { return proto.CompactTextString(m) }
+func (*OutPoint) ProtoMessage()               {}
+func (*OutPoint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *OutPoint) GetTransactionHash() []byte {
 	if m != nil {
-		return m.AttachedBlocks
+		return m.TransactionHash
 	}
 	return nil
 }
 
-func (m *TransactionNotificationsResponse) GetDetachedBlocks() [][]byte {
+func (m *OutPoint) GetOutputIndex() uint32 {
 	if m != nil {
-		return m.DetachedBlocks
+		return m.OutputIndex
 	}
-	return nil
+	return 0
 }
 
-func (m *TransactionNotificationsResponse) GetUnminedTransactions() []*TransactionDetails {
+type GetTransactionRequest struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+}
+
+func (m *GetTransactionRequest) Reset()                    { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()               {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *GetTransactionRequest) GetTransactionHash() []byte {
 	if m != nil {
-		return m.UnminedTransactions
+		return m.TransactionHash
 	}
 	return nil
 }
 
-func (m *TransactionNotificationsResponse) GetUnminedTransactionHashes() [][]byte {
+type GetTransactionResponse struct {
+	Transaction    *TransactionDetails `protobuf:"bytes,1,opt,name=transaction" json:"transaction,omitempty"`
+	BlockHash      []byte              `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockHeight    int32               `protobuf:"varint,3,opt,name=block_height,json=blockHeight" json:"block_height,omitempty"`
+	Confirmations  int32               `protobuf:"varint,4,opt,name=confirmations" json:"confirmations,omitempty"`
+	BlockTimestamp int64               `protobuf:"varint,5,opt,name=block_timestamp,json=blockTimestamp" json:"block_timestamp,omitempty"`
+	Label          string              `protobuf:"bytes,6,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *GetTransactionResponse) Reset()                    { *m = GetTransactionResponse{} }
+func (m *GetTransactionResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionResponse) ProtoMessage()               {}
+func (*GetTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *GetTransactionResponse) GetTransaction() *TransactionDetails {
 	if m != nil {
-		return m.UnminedTransactionHashes
+		return m.Transaction
 	}
 	return nil
 }
 
-type SpentnessNotificationsRequest struct {
-	Account         uint32 `protobuf:"varint,1,opt,name=account" json:"account,omitempty"`
-	NoNotifyUnspent bool   `protobuf:"varint,2,opt,name=no_notify_unspent,json=noNotifyUnspent" json:"no_notify_unspent,omitempty"`
-	NoNotifySpent   bool   `protobuf:"varint,3,opt,name=no_notify_spent,json=noNotifySpent" json:"no_notify_spent,omitempty"`
+func (m *GetTransactionResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
 }
 
-func (m *SpentnessNotificationsRequest) Reset()                    { *m = SpentnessNotificationsRequest{} }
-func (m *SpentnessNotificationsRequest) String() string            { return proto.CompactTextString(m) }
-func (*SpentnessNotificationsRequest) ProtoMessage()               {}
-func (*SpentnessNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
-
-func (m *SpentnessNotificationsRequest) GetAccount() uint32 {
+func (m *GetTransactionResponse) GetBlockHeight() int32 {
 	if m != nil {
-		return m.Account
+		return m.BlockHeight
 	}
 	return 0
 }
 
-func (m *SpentnessNotificationsRequest) GetNoNotifyUnspent() bool {
+func (m *GetTransactionResponse) GetConfirmations() int32 {
 	if m != nil {
-		return m.NoNotifyUnspent
+		return m.Confirmations
 	}
-	return false
+	return 0
 }
 
-func (m *SpentnessNotificationsRequest) GetNoNotifySpent() bool {
+func (m *GetTransactionResponse) GetBlockTimestamp() int64 {
 	if m != nil {
-		return m.NoNotifySpent
+		return m.BlockTimestamp
 	}
-	return false
+	return 0
 }
 
-type SpentnessNotificationsResponse struct {
-	TransactionHash []byte                                  `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
-	OutputIndex     uint32                                  `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
-	Spender         *SpentnessNotificationsResponse_Spender `protobuf:"bytes,3,opt,name=spender" json:"spender,omitempty"`
+func (m *GetTransactionResponse) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
 }
 
-func (m *SpentnessNotificationsResponse) Reset()         { *m = SpentnessNotificationsResponse{} }
-func (m *SpentnessNotificationsResponse) String() string { return proto.CompactTextString(m) }
-func (*SpentnessNotificationsResponse) ProtoMessage()    {}
-func (*SpentnessNotificationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{36}
+type ListUnspentRequest struct {
+	MinConfirmations int32  `protobuf:"varint,1,opt,name=min_confirmations,json=minConfirmations" json:"min_confirmations,omitempty"`
+	MaxConfirmations int32  `protobuf:"varint,2,opt,name=max_confirmations,json=maxConfirmations" json:"max_confirmations,omitempty"`
+	AccountName      string `protobuf:"bytes,3,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
 }
 
-func (m *SpentnessNotificationsResponse) GetTransactionHash() []byte {
+func (m *ListUnspentRequest) Reset()                    { *m = ListUnspentRequest{} }
+func (m *ListUnspentRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListUnspentRequest) ProtoMessage()               {}
+func (*ListUnspentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *ListUnspentRequest) GetMinConfirmations() int32 {
 	if m != nil {
-		return m.TransactionHash
+		return m.MinConfirmations
 	}
-	return nil
+	return 0
 }
 
-func (m *SpentnessNotificationsResponse) GetOutputIndex() uint32 {
+func (m *ListUnspentRequest) GetMaxConfirmations() int32 {
 	if m != nil {
-		return m.OutputIndex
+		return m.MaxConfirmations
 	}
 	return 0
 }
 
-func (m *SpentnessNotificationsResponse) GetSpender() *SpentnessNotificationsResponse_Spender {
+func (m *ListUnspentRequest) GetAccountName() string {
 	if m != nil {
-		return m.Spender
+		return m.AccountName
 	}
-	return nil
+	return ""
 }
 
-type SpentnessNotificationsResponse_Spender struct {
-	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
-	InputIndex      uint32 `protobuf:"varint,2,opt,name=input_index,json=inputIndex" json:"input_index,omitempty"`
+type ListUnspentResponse struct {
+	Unspent []*ListUnspentResponse_Unspent `protobuf:"bytes,1,rep,name=unspent" json:"unspent,omitempty"`
 }
 
-func (m *SpentnessNotificationsResponse_Spender) Reset() {
-	*m = SpentnessNotificationsResponse_Spender{}
-}
-func (m *SpentnessNotificationsResponse_Spender) String() string { return proto.CompactTextString(m) }
-func (*SpentnessNotificationsResponse_Spender) ProtoMessage()    {}
-func (*SpentnessNotificationsResponse_Spender) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{36, 0}
-}
+func (m *ListUnspentResponse) Reset()                    { *m = ListUnspentResponse{} }
+func (m *ListUnspentResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListUnspentResponse) ProtoMessage()               {}
+func (*ListUnspentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
 
-func (m *SpentnessNotificationsResponse_Spender) GetTransactionHash() []byte {
+func (m *ListUnspentResponse) GetUnspent() []*ListUnspentResponse_Unspent {
 	if m != nil {
-		return m.TransactionHash
+		return m.Unspent
 	}
 	return nil
 }
 
-func (m *SpentnessNotificationsResponse_Spender) GetInputIndex() uint32 {
-	if m != nil {
-		return m.InputIndex
-	}
-	return 0
+type ListUnspentResponse_Unspent struct {
+	Outpoint      *OutPoint `protobuf:"bytes,1,opt,name=outpoint" json:"outpoint,omitempty"`
+	Address       string    `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	AccountName   string    `protobuf:"bytes,3,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	Amount        int64     `protobuf:"varint,4,opt,name=amount" json:"amount,omitempty"`
+	PkScript      []byte    `protobuf:"bytes,5,opt,name=pk_script,json=pkScript,proto3" json:"pk_script,omitempty"`
+	RedeemScript  []byte    `protobuf:"bytes,6,opt,name=redeem_script,json=redeemScript,proto3" json:"redeem_script,omitempty"`
+	Confirmations int64     `protobuf:"varint,7,opt,name=confirmations" json:"confirmations,omitempty"`
+	Spendable     bool      `protobuf:"varint,8,opt,name=spendable" json:"spendable,omitempty"`
 }
 
-type AccountNotificationsRequest struct {
+func (m *ListUnspentResponse_Unspent) Reset()         { *m = ListUnspentResponse_Unspent{} }
+func (m *ListUnspentResponse_Unspent) String() string { return proto.CompactTextString(m) }
+func (*ListUnspentResponse_Unspent) ProtoMessage()    {}
+func (*ListUnspentResponse_Unspent) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{39, 0}
 }
 
-func (m *AccountNotificationsRequest) Reset()                    { *m = AccountNotificationsRequest{} }
-func (m *AccountNotificationsRequest) String() string            { return proto.CompactTextString(m) }
-func (*AccountNotificationsRequest) ProtoMessage()               {}
-func (*AccountNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
-
-type AccountNotificationsResponse struct {
-	AccountNumber    uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
-	AccountName      string `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
-	ExternalKeyCount uint32 `protobuf:"varint,3,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
-	InternalKeyCount uint32 `protobuf:"varint,4,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
-	ImportedKeyCount uint32 `protobuf:"varint,5,opt,name=imported_key_count,json=importedKeyCount" json:"imported_key_count,omitempty"`
+func (m *ListUnspentResponse_Unspent) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
 }
 
-func (m *AccountNotificationsResponse) Reset()                    { *m = AccountNotificationsResponse{} }
-func (m *AccountNotificationsResponse) String() string            { return proto.CompactTextString(m) }
-func (*AccountNotificationsResponse) ProtoMessage()               {}
-func (*AccountNotificationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
-
-func (m *AccountNotificationsResponse) GetAccountNumber() uint32 {
+func (m *ListUnspentResponse_Unspent) GetAddress() string {
 	if m != nil {
-		return m.AccountNumber
+		return m.Address
 	}
-	return 0
+	return ""
 }
 
-func (m *AccountNotificationsResponse) GetAccountName() string {
+func (m *ListUnspentResponse_Unspent) GetAccountName() string {
 	if m != nil {
 		return m.AccountName
 	}
 	return ""
 }
 
-func (m *AccountNotificationsResponse) GetExternalKeyCount() uint32 {
+func (m *ListUnspentResponse_Unspent) GetAmount() int64 {
 	if m != nil {
-		return m.ExternalKeyCount
+		return m.Amount
 	}
 	return 0
 }
 
-func (m *AccountNotificationsResponse) GetInternalKeyCount() uint32 {
+func (m *ListUnspentResponse_Unspent) GetPkScript() []byte {
 	if m != nil {
-		return m.InternalKeyCount
+		return m.PkScript
 	}
-	return 0
+	return nil
 }
 
-func (m *AccountNotificationsResponse) GetImportedKeyCount() uint32 {
+func (m *ListUnspentResponse_Unspent) GetRedeemScript() []byte {
 	if m != nil {
-		return m.ImportedKeyCount
+		return m.RedeemScript
 	}
-	return 0
+	return nil
 }
 
-type CreateWalletRequest struct {
-	PublicPassphrase  []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
-	PrivatePassphrase []byte `protobuf:"bytes,2,opt,name=private_passphrase,json=privatePassphrase,proto3" json:"private_passphrase,omitempty"`
-	Seed              []byte `protobuf:"bytes,3,opt,name=seed,proto3" json:"seed,omitempty"`
+func (m *ListUnspentResponse_Unspent) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
 }
 
-func (m *CreateWalletRequest) Reset()                    { *m = CreateWalletRequest{} }
-func (m *CreateWalletRequest) String() string            { return proto.CompactTextString(m) }
-func (*CreateWalletRequest) ProtoMessage()               {}
-func (*CreateWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
-
-func (m *CreateWalletRequest) GetPublicPassphrase() []byte {
+func (m *ListUnspentResponse_Unspent) GetSpendable() bool {
 	if m != nil {
-		return m.PublicPassphrase
+		return m.Spendable
 	}
-	return nil
+	return false
 }
 
-func (m *CreateWalletRequest) GetPrivatePassphrase() []byte {
+type ListLeasedOutputsRequest struct {
+}
+
+func (m *ListLeasedOutputsRequest) Reset()                    { *m = ListLeasedOutputsRequest{} }
+func (m *ListLeasedOutputsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListLeasedOutputsRequest) ProtoMessage()               {}
+func (*ListLeasedOutputsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+type ListLeasedOutputsResponse struct {
+	LeasedOutputs []*ListLeasedOutputsResponse_LeasedOutput `protobuf:"bytes,1,rep,name=leased_outputs,json=leasedOutputs" json:"leased_outputs,omitempty"`
+}
+
+func (m *ListLeasedOutputsResponse) Reset()                    { *m = ListLeasedOutputsResponse{} }
+func (m *ListLeasedOutputsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListLeasedOutputsResponse) ProtoMessage()               {}
+func (*ListLeasedOutputsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ListLeasedOutputsResponse) GetLeasedOutputs() []*ListLeasedOutputsResponse_LeasedOutput {
 	if m != nil {
-		return m.PrivatePassphrase
+		return m.LeasedOutputs
 	}
 	return nil
 }
 
-func (m *CreateWalletRequest) GetSeed() []byte {
+type ListLeasedOutputsResponse_LeasedOutput struct {
+	Id         []byte    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Outpoint   *OutPoint `protobuf:"bytes,2,opt,name=outpoint" json:"outpoint,omitempty"`
+	Expiration int64     `protobuf:"varint,3,opt,name=expiration" json:"expiration,omitempty"`
+	Value      int64     `protobuf:"varint,4,opt,name=value" json:"value,omitempty"`
+	PkScript   []byte    `protobuf:"bytes,5,opt,name=pk_script,json=pkScript,proto3" json:"pk_script,omitempty"`
+}
+
+func (m *ListLeasedOutputsResponse_LeasedOutput) Reset() {
+	*m = ListLeasedOutputsResponse_LeasedOutput{}
+}
+func (m *ListLeasedOutputsResponse_LeasedOutput) String() string { return proto.CompactTextString(m) }
+func (*ListLeasedOutputsResponse_LeasedOutput) ProtoMessage()    {}
+func (*ListLeasedOutputsResponse_LeasedOutput) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{41, 0}
+}
+
+func (m *ListLeasedOutputsResponse_LeasedOutput) GetId() []byte {
 	if m != nil {
-		return m.Seed
+		return m.Id
 	}
 	return nil
 }
 
-type CreateWalletResponse struct {
+func (m *ListLeasedOutputsResponse_LeasedOutput) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
 }
 
-func (m *CreateWalletResponse) Reset()                    { *m = CreateWalletResponse{} }
-func (m *CreateWalletResponse) String() string            { return proto.CompactTextString(m) }
-func (*CreateWalletResponse) ProtoMessage()               {}
-func (*CreateWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
-
-type OpenWalletRequest struct {
-	PublicPassphrase []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
+func (m *ListLeasedOutputsResponse_LeasedOutput) GetExpiration() int64 {
+	if m != nil {
+		return m.Expiration
+	}
+	return 0
 }
 
-func (m *OpenWalletRequest) Reset()                    { *m = OpenWalletRequest{} }
-func (m *OpenWalletRequest) String() string            { return proto.CompactTextString(m) }
-func (*OpenWalletRequest) ProtoMessage()               {}
-func (*OpenWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (m *ListLeasedOutputsResponse_LeasedOutput) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
 
-func (m *OpenWalletRequest) GetPublicPassphrase() []byte {
+func (m *ListLeasedOutputsResponse_LeasedOutput) GetPkScript() []byte {
 	if m != nil {
-		return m.PublicPassphrase
+		return m.PkScript
 	}
 	return nil
 }
 
-type OpenWalletResponse struct {
+type AddressInfoRequest struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
 }
 
-func (m *OpenWalletResponse) Reset()                    { *m = OpenWalletResponse{} }
-func (m *OpenWalletResponse) String() string            { return proto.CompactTextString(m) }
-func (*OpenWalletResponse) ProtoMessage()               {}
-func (*OpenWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (m *AddressInfoRequest) Reset()                    { *m = AddressInfoRequest{} }
+func (m *AddressInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddressInfoRequest) ProtoMessage()               {}
+func (*AddressInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
 
-type CloseWalletRequest struct {
+func (m *AddressInfoRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
 }
 
-func (m *CloseWalletRequest) Reset()                    { *m = CloseWalletRequest{} }
-func (m *CloseWalletRequest) String() string            { return proto.CompactTextString(m) }
-func (*CloseWalletRequest) ProtoMessage()               {}
-func (*CloseWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+type AddressInfoResponse struct {
+	Address              string    `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	PkScript             []byte    `protobuf:"bytes,2,opt,name=pk_script,json=pkScript,proto3" json:"pk_script,omitempty"`
+	IsMine               bool      `protobuf:"varint,3,opt,name=is_mine,json=isMine" json:"is_mine,omitempty"`
+	IsWatchOnly          bool      `protobuf:"varint,4,opt,name=is_watch_only,json=isWatchOnly" json:"is_watch_only,omitempty"`
+	IsChange             bool      `protobuf:"varint,5,opt,name=is_change,json=isChange" json:"is_change,omitempty"`
+	AccountName          string    `protobuf:"bytes,6,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	KeyScope             *KeyScope `protobuf:"bytes,7,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	Used                 bool      `protobuf:"varint,8,opt,name=used" json:"used,omitempty"`
+	Label                string    `protobuf:"bytes,9,opt,name=label" json:"label,omitempty"`
+	PublicKey            []byte    `protobuf:"bytes,10,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	DerivationPath       string    `protobuf:"bytes,11,opt,name=derivation_path,json=derivationPath" json:"derivation_path,omitempty"`
+	MasterKeyFingerprint uint32    `protobuf:"varint,12,opt,name=master_key_fingerprint,json=masterKeyFingerprint" json:"master_key_fingerprint,omitempty"`
+	Script               []byte    `protobuf:"bytes,13,opt,name=script,proto3" json:"script,omitempty"`
+}
+
+func (m *AddressInfoResponse) Reset()                    { *m = AddressInfoResponse{} }
+func (m *AddressInfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddressInfoResponse) ProtoMessage()               {}
+func (*AddressInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *AddressInfoResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
 
-type CloseWalletResponse struct {
+func (m *AddressInfoResponse) GetPkScript() []byte {
+	if m != nil {
+		return m.PkScript
+	}
+	return nil
 }
 
-func (m *CloseWalletResponse) Reset()                    { *m = CloseWalletResponse{} }
-func (m *CloseWalletResponse) String() string            { return proto.CompactTextString(m) }
-func (*CloseWalletResponse) ProtoMessage()               {}
-func (*CloseWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (m *AddressInfoResponse) GetIsMine() bool {
+	if m != nil {
+		return m.IsMine
+	}
+	return false
+}
 
-type WalletExistsRequest struct {
+func (m *AddressInfoResponse) GetIsWatchOnly() bool {
+	if m != nil {
+		return m.IsWatchOnly
+	}
+	return false
 }
 
-func (m *WalletExistsRequest) Reset()                    { *m = WalletExistsRequest{} }
-func (m *WalletExistsRequest) String() string            { return proto.CompactTextString(m) }
-func (*WalletExistsRequest) ProtoMessage()               {}
-func (*WalletExistsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (m *AddressInfoResponse) GetIsChange() bool {
+	if m != nil {
+		return m.IsChange
+	}
+	return false
+}
 
-type WalletExistsResponse struct {
-	Exists bool `protobuf:"varint,1,opt,name=exists" json:"exists,omitempty"`
+func (m *AddressInfoResponse) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
 }
 
-func (m *WalletExistsResponse) Reset()                    { *m = WalletExistsResponse{} }
-func (m *WalletExistsResponse) String() string            { return proto.CompactTextString(m) }
-func (*WalletExistsResponse) ProtoMessage()               {}
-func (*WalletExistsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (m *AddressInfoResponse) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
+	}
+	return nil
+}
 
-func (m *WalletExistsResponse) GetExists() bool {
+func (m *AddressInfoResponse) GetUsed() bool {
 	if m != nil {
-		return m.Exists
+		return m.Used
 	}
 	return false
 }
 
-type StartConsensusRpcRequest struct {
-	NetworkAddress string `protobuf:"bytes,1,opt,name=network_address,json=networkAddress" json:"network_address,omitempty"`
-	Username       string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
-	Password       []byte `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
-	Certificate    []byte `protobuf:"bytes,4,opt,name=certificate,proto3" json:"certificate,omitempty"`
+func (m *AddressInfoResponse) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
 }
 
-func (m *StartConsensusRpcRequest) Reset()                    { *m = StartConsensusRpcRequest{} }
-func (m *StartConsensusRpcRequest) String() string            { return proto.CompactTextString(m) }
-func (*StartConsensusRpcRequest) ProtoMessage()               {}
-func (*StartConsensusRpcRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
-
-func (m *StartConsensusRpcRequest) GetNetworkAddress() string {
+func (m *AddressInfoResponse) GetPublicKey() []byte {
 	if m != nil {
-		return m.NetworkAddress
+		return m.PublicKey
 	}
-	return ""
+	return nil
 }
 
-func (m *StartConsensusRpcRequest) GetUsername() string {
+func (m *AddressInfoResponse) GetDerivationPath() string {
 	if m != nil {
-		return m.Username
+		return m.DerivationPath
 	}
 	return ""
 }
 
-func (m *StartConsensusRpcRequest) GetPassword() []byte {
+func (m *AddressInfoResponse) GetMasterKeyFingerprint() uint32 {
 	if m != nil {
-		return m.Password
+		return m.MasterKeyFingerprint
 	}
-	return nil
+	return 0
 }
 
-func (m *StartConsensusRpcRequest) GetCertificate() []byte {
+func (m *AddressInfoResponse) GetScript() []byte {
 	if m != nil {
-		return m.Certificate
+		return m.Script
 	}
 	return nil
 }
 
-type StartConsensusRpcResponse struct {
+type VerifyMessageRequest struct {
+	Address   string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Message   string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
-func (m *StartConsensusRpcResponse) Reset()                    { *m = StartConsensusRpcResponse{} }
-func (m *StartConsensusRpcResponse) String() string            { return proto.CompactTextString(m) }
-func (*StartConsensusRpcResponse) ProtoMessage()               {}
-func (*StartConsensusRpcResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (m *VerifyMessageRequest) Reset()                    { *m = VerifyMessageRequest{} }
+func (m *VerifyMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMessageRequest) ProtoMessage()               {}
+func (*VerifyMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
 
-func init() {
-	proto.RegisterType((*VersionRequest)(nil), "walletrpc.VersionRequest")
-	proto.RegisterType((*VersionResponse)(nil), "walletrpc.VersionResponse")
-	proto.RegisterType((*TransactionDetails)(nil), "walletrpc.TransactionDetails")
-	proto.RegisterType((*TransactionDetails_Input)(nil), "walletrpc.TransactionDetails.Input")
-	proto.RegisterType((*TransactionDetails_Output)(nil), "walletrpc.TransactionDetails.Output")
-	proto.RegisterType((*BlockDetails)(nil), "walletrpc.BlockDetails")
-	proto.RegisterType((*AccountBalance)(nil), "walletrpc.AccountBalance")
-	proto.RegisterType((*PingRequest)(nil), "walletrpc.PingRequest")
-	proto.RegisterType((*PingResponse)(nil), "walletrpc.PingResponse")
-	proto.RegisterType((*NetworkRequest)(nil), "walletrpc.NetworkRequest")
-	proto.RegisterType((*NetworkResponse)(nil), "walletrpc.NetworkResponse")
-	proto.RegisterType((*AccountNumberRequest)(nil), "walletrpc.AccountNumberRequest")
-	proto.RegisterType((*AccountNumberResponse)(nil), "walletrpc.AccountNumberResponse")
-	proto.RegisterType((*AccountsRequest)(nil), "walletrpc.AccountsRequest")
-	proto.RegisterType((*AccountsResponse)(nil), "walletrpc.AccountsResponse")
-	proto.RegisterType((*AccountsResponse_Account)(nil), "walletrpc.AccountsResponse.Account")
-	proto.RegisterType((*RenameAccountRequest)(nil), "walletrpc.RenameAccountRequest")
-	proto.RegisterType((*RenameAccountResponse)(nil), "walletrpc.RenameAccountResponse")
-	proto.RegisterType((*NextAccountRequest)(nil), "walletrpc.NextAccountRequest")
-	proto.RegisterType((*NextAccountResponse)(nil), "walletrpc.NextAccountResponse")
-	proto.RegisterType((*NextAddressRequest)(nil), "walletrpc.NextAddressRequest")
-	proto.RegisterType((*NextAddressResponse)(nil), "walletrpc.NextAddressResponse")
-	proto.RegisterType((*ImportPrivateKeyRequest)(nil), "walletrpc.ImportPrivateKeyRequest")
-	proto.RegisterType((*ImportPrivateKeyResponse)(nil), "walletrpc.ImportPrivateKeyResponse")
-	proto.RegisterType((*BalanceRequest)(nil), "walletrpc.BalanceRequest")
-	proto.RegisterType((*BalanceResponse)(nil), "walletrpc.BalanceResponse")
-	proto.RegisterType((*GetTransactionsRequest)(nil), "walletrpc.GetTransactionsRequest")
-	proto.RegisterType((*GetTransactionsResponse)(nil), "walletrpc.GetTransactionsResponse")
-	proto.RegisterType((*ChangePassphraseRequest)(nil), "walletrpc.ChangePassphraseRequest")
-	proto.RegisterType((*ChangePassphraseResponse)(nil), "walletrpc.ChangePassphraseResponse")
-	proto.RegisterType((*FundTransactionRequest)(nil), "walletrpc.FundTransactionRequest")
-	proto.RegisterType((*FundTransactionResponse)(nil), "walletrpc.FundTransactionResponse")
-	proto.RegisterType((*FundTransactionResponse_PreviousOutput)(nil), "walletrpc.FundTransactionResponse.PreviousOutput")
-	proto.RegisterType((*SignTransactionRequest)(nil), "walletrpc.SignTransactionRequest")
-	proto.RegisterType((*SignTransactionResponse)(nil), "walletrpc.SignTransactionResponse")
-	proto.RegisterType((*PublishTransactionRequest)(nil), "walletrpc.PublishTransactionRequest")
-	proto.RegisterType((*PublishTransactionResponse)(nil), "walletrpc.PublishTransactionResponse")
-	proto.RegisterType((*TransactionNotificationsRequest)(nil), "walletrpc.TransactionNotificationsRequest")
-	proto.RegisterType((*TransactionNotificationsResponse)(nil), "walletrpc.TransactionNotificationsResponse")
-	proto.RegisterType((*SpentnessNotificationsRequest)(nil), "walletrpc.SpentnessNotificationsRequest")
-	proto.RegisterType((*SpentnessNotificationsResponse)(nil), "walletrpc.SpentnessNotificationsResponse")
-	proto.RegisterType((*SpentnessNotificationsResponse_Spender)(nil), "walletrpc.SpentnessNotificationsResponse.Spender")
-	proto.RegisterType((*AccountNotificationsRequest)(nil), "walletrpc.AccountNotificationsRequest")
-	proto.RegisterType((*AccountNotificationsResponse)(nil), "walletrpc.AccountNotificationsResponse")
-	proto.RegisterType((*CreateWalletRequest)(nil), "walletrpc.CreateWalletRequest")
-	proto.RegisterType((*CreateWalletResponse)(nil), "walletrpc.CreateWalletResponse")
-	proto.RegisterType((*OpenWalletRequest)(nil), "walletrpc.OpenWalletRequest")
-	proto.RegisterType((*OpenWalletResponse)(nil), "walletrpc.OpenWalletResponse")
-	proto.RegisterType((*CloseWalletRequest)(nil), "walletrpc.CloseWalletRequest")
-	proto.RegisterType((*CloseWalletResponse)(nil), "walletrpc.CloseWalletResponse")
-	proto.RegisterType((*WalletExistsRequest)(nil), "walletrpc.WalletExistsRequest")
-	proto.RegisterType((*WalletExistsResponse)(nil), "walletrpc.WalletExistsResponse")
-	proto.RegisterType((*StartConsensusRpcRequest)(nil), "walletrpc.StartConsensusRpcRequest")
-	proto.RegisterType((*StartConsensusRpcResponse)(nil), "walletrpc.StartConsensusRpcResponse")
-	proto.RegisterEnum("walletrpc.NextAddressRequest_Kind", NextAddressRequest_Kind_name, NextAddressRequest_Kind_value)
-	proto.RegisterEnum("walletrpc.ChangePassphraseRequest_Key", ChangePassphraseRequest_Key_name, ChangePassphraseRequest_Key_value)
+func (m *VerifyMessageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *VerifyMessageRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *VerifyMessageRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
 
-// Client API for VersionService service
+type VerifyMessageResponse struct {
+	Valid bool `protobuf:"varint,1,opt,name=valid" json:"valid,omitempty"`
+}
 
-type VersionServiceClient interface {
-	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+func (m *VerifyMessageResponse) Reset()                    { *m = VerifyMessageResponse{} }
+func (m *VerifyMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMessageResponse) ProtoMessage()               {}
+func (*VerifyMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *VerifyMessageResponse) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
 }
 
-type versionServiceClient struct {
-	cc *grpc.ClientConn
+type CreateSimpleTransactionRequest struct {
+	KeyScope              *KeyScope                                `protobuf:"bytes,1,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	Account               uint32                                   `protobuf:"varint,2,opt,name=account" json:"account,omitempty"`
+	Outputs               []*CreateSimpleTransactionRequest_Output `protobuf:"bytes,3,rep,name=outputs" json:"outputs,omitempty"`
+	RequiredConfirmations int32                                    `protobuf:"varint,4,opt,name=required_confirmations,json=requiredConfirmations" json:"required_confirmations,omitempty"`
+	FeeRateSatPerKb       int64                                    `protobuf:"varint,5,opt,name=fee_rate_sat_per_kb,json=feeRateSatPerKb" json:"fee_rate_sat_per_kb,omitempty"`
+	CoinSelectionStrategy CoinSelectionStrategy                    `protobuf:"varint,6,opt,name=coin_selection_strategy,json=coinSelectionStrategy,enum=walletrpc.CoinSelectionStrategy" json:"coin_selection_strategy,omitempty"`
+	DryRun                bool                                     `protobuf:"varint,7,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+	Passphrase            []byte                                   `protobuf:"bytes,8,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 }
 
-func NewVersionServiceClient(cc *grpc.ClientConn) VersionServiceClient {
-	return &versionServiceClient{cc}
+func (m *CreateSimpleTransactionRequest) Reset()         { *m = CreateSimpleTransactionRequest{} }
+func (m *CreateSimpleTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSimpleTransactionRequest) ProtoMessage()    {}
+func (*CreateSimpleTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{46}
 }
 
-func (c *versionServiceClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error) {
-	out := new(VersionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.VersionService/Version", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionRequest) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
 	}
-	return out, nil
+	return nil
 }
 
-// Server API for VersionService service
-
-type VersionServiceServer interface {
-	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+func (m *CreateSimpleTransactionRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
 }
 
-func RegisterVersionServiceServer(s *grpc.Server, srv VersionServiceServer) {
-	s.RegisterService(&_VersionService_serviceDesc, srv)
+func (m *CreateSimpleTransactionRequest) GetOutputs() []*CreateSimpleTransactionRequest_Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
 }
 
-func _VersionService_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(VersionRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionRequest) GetRequiredConfirmations() int32 {
+	if m != nil {
+		return m.RequiredConfirmations
 	}
-	if interceptor == nil {
-		return srv.(VersionServiceServer).Version(ctx, in)
+	return 0
+}
+
+func (m *CreateSimpleTransactionRequest) GetFeeRateSatPerKb() int64 {
+	if m != nil {
+		return m.FeeRateSatPerKb
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/walletrpc.VersionService/Version",
+	return 0
+}
+
+func (m *CreateSimpleTransactionRequest) GetCoinSelectionStrategy() CoinSelectionStrategy {
+	if m != nil {
+		return m.CoinSelectionStrategy
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(VersionServiceServer).Version(ctx, req.(*VersionRequest))
+	return CoinSelectionStrategy_LARGEST
+}
+
+func (m *CreateSimpleTransactionRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
 	}
-	return interceptor(ctx, in, info, handler)
+	return false
 }
 
-var _VersionService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "walletrpc.VersionService",
-	HandlerType: (*VersionServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Version",
-			Handler:    _VersionService_Version_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "api.proto",
+func (m *CreateSimpleTransactionRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
+	}
+	return nil
 }
 
-// Client API for WalletService service
-
-type WalletServiceClient interface {
-	// Queries
-	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
-	Network(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkResponse, error)
-	AccountNumber(ctx context.Context, in *AccountNumberRequest, opts ...grpc.CallOption) (*AccountNumberResponse, error)
-	Accounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error)
-	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
-	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error)
-	// Notifications
-	TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error)
-	SpentnessNotifications(ctx context.Context, in *SpentnessNotificationsRequest, opts ...grpc.CallOption) (WalletService_SpentnessNotificationsClient, error)
-	AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error)
-	// Control
-	ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error)
-	RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error)
-	NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error)
-	NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error)
-	ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error)
-	FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error)
-	SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error)
-	PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error)
+type CreateSimpleTransactionRequest_Output struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Amount  int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
 }
 
-type walletServiceClient struct {
-	cc *grpc.ClientConn
+func (m *CreateSimpleTransactionRequest_Output) Reset()         { *m = CreateSimpleTransactionRequest_Output{} }
+func (m *CreateSimpleTransactionRequest_Output) String() string { return proto.CompactTextString(m) }
+func (*CreateSimpleTransactionRequest_Output) ProtoMessage()    {}
+func (*CreateSimpleTransactionRequest_Output) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{46, 0}
 }
 
-func NewWalletServiceClient(cc *grpc.ClientConn) WalletServiceClient {
-	return &walletServiceClient{cc}
+func (m *CreateSimpleTransactionRequest_Output) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
 }
 
-func (c *walletServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
-	out := new(PingResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Ping", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionRequest_Output) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
 	}
-	return out, nil
+	return 0
 }
 
-func (c *walletServiceClient) Network(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkResponse, error) {
-	out := new(NetworkResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Network", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type CreateSimpleTransactionResponse struct {
+	Transaction       []byte   `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	PreviousAmounts   []int64  `protobuf:"varint,2,rep,packed,name=previous_amounts,json=previousAmounts" json:"previous_amounts,omitempty"`
+	PreviousPkScripts [][]byte `protobuf:"bytes,3,rep,name=previous_pk_scripts,json=previousPkScripts,proto3" json:"previous_pk_scripts,omitempty"`
+	TotalInput        int64    `protobuf:"varint,4,opt,name=total_input,json=totalInput" json:"total_input,omitempty"`
+	Fee               int64    `protobuf:"varint,5,opt,name=fee" json:"fee,omitempty"`
+	ChangeIndex       int32    `protobuf:"varint,6,opt,name=change_index,json=changeIndex" json:"change_index,omitempty"`
 }
 
-func (c *walletServiceClient) AccountNumber(ctx context.Context, in *AccountNumberRequest, opts ...grpc.CallOption) (*AccountNumberResponse, error) {
-	out := new(AccountNumberResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/AccountNumber", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *CreateSimpleTransactionResponse) Reset()         { *m = CreateSimpleTransactionResponse{} }
+func (m *CreateSimpleTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateSimpleTransactionResponse) ProtoMessage()    {}
+func (*CreateSimpleTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{47}
 }
 
-func (c *walletServiceClient) Accounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error) {
-	out := new(AccountsResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Accounts", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionResponse) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
 	}
-	return out, nil
+	return nil
 }
 
-func (c *walletServiceClient) Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
-	out := new(BalanceResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Balance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionResponse) GetPreviousAmounts() []int64 {
+	if m != nil {
+		return m.PreviousAmounts
 	}
-	return out, nil
+	return nil
 }
 
-func (c *walletServiceClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error) {
-	out := new(GetTransactionsResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/GetTransactions", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionResponse) GetPreviousPkScripts() [][]byte {
+	if m != nil {
+		return m.PreviousPkScripts
 	}
-	return out, nil
+	return nil
 }
 
-func (c *walletServiceClient) TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[0], c.cc, "/walletrpc.WalletService/TransactionNotifications", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &walletServiceTransactionNotificationsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+func (m *CreateSimpleTransactionResponse) GetTotalInput() int64 {
+	if m != nil {
+		return m.TotalInput
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return 0
+}
+
+func (m *CreateSimpleTransactionResponse) GetFee() int64 {
+	if m != nil {
+		return m.Fee
 	}
-	return x, nil
+	return 0
 }
 
-type WalletService_TransactionNotificationsClient interface {
-	Recv() (*TransactionNotificationsResponse, error)
-	grpc.ClientStream
+func (m *CreateSimpleTransactionResponse) GetChangeIndex() int32 {
+	if m != nil {
+		return m.ChangeIndex
+	}
+	return 0
 }
 
-type walletServiceTransactionNotificationsClient struct {
-	grpc.ClientStream
+type FundPsbtRequest struct {
+	Psbt                  []byte                `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	KeyScope              *KeyScope             `protobuf:"bytes,2,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	Account               uint32                `protobuf:"varint,3,opt,name=account" json:"account,omitempty"`
+	RequiredConfirmations int32                 `protobuf:"varint,4,opt,name=required_confirmations,json=requiredConfirmations" json:"required_confirmations,omitempty"`
+	FeeRateSatPerKb       int64                 `protobuf:"varint,5,opt,name=fee_rate_sat_per_kb,json=feeRateSatPerKb" json:"fee_rate_sat_per_kb,omitempty"`
+	CoinSelectionStrategy CoinSelectionStrategy `protobuf:"varint,6,opt,name=coin_selection_strategy,json=coinSelectionStrategy,enum=walletrpc.CoinSelectionStrategy" json:"coin_selection_strategy,omitempty"`
 }
 
-func (x *walletServiceTransactionNotificationsClient) Recv() (*TransactionNotificationsResponse, error) {
-	m := new(TransactionNotificationsResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *FundPsbtRequest) Reset()                    { *m = FundPsbtRequest{} }
+func (m *FundPsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*FundPsbtRequest) ProtoMessage()               {}
+func (*FundPsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *FundPsbtRequest) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
 	}
-	return m, nil
+	return nil
 }
 
-func (c *walletServiceClient) SpentnessNotifications(ctx context.Context, in *SpentnessNotificationsRequest, opts ...grpc.CallOption) (WalletService_SpentnessNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[1], c.cc, "/walletrpc.WalletService/SpentnessNotifications", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &walletServiceSpentnessNotificationsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *FundPsbtRequest) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
 	}
-	return x, nil
+	return nil
 }
 
-type WalletService_SpentnessNotificationsClient interface {
-	Recv() (*SpentnessNotificationsResponse, error)
-	grpc.ClientStream
+func (m *FundPsbtRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
 }
 
-type walletServiceSpentnessNotificationsClient struct {
-	grpc.ClientStream
+func (m *FundPsbtRequest) GetRequiredConfirmations() int32 {
+	if m != nil {
+		return m.RequiredConfirmations
+	}
+	return 0
 }
 
-func (x *walletServiceSpentnessNotificationsClient) Recv() (*SpentnessNotificationsResponse, error) {
-	m := new(SpentnessNotificationsResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *FundPsbtRequest) GetFeeRateSatPerKb() int64 {
+	if m != nil {
+		return m.FeeRateSatPerKb
 	}
-	return m, nil
+	return 0
 }
 
-func (c *walletServiceClient) AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[2], c.cc, "/walletrpc.WalletService/AccountNotifications", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &walletServiceAccountNotificationsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *FundPsbtRequest) GetCoinSelectionStrategy() CoinSelectionStrategy {
+	if m != nil {
+		return m.CoinSelectionStrategy
 	}
-	return x, nil
+	return CoinSelectionStrategy_LARGEST
 }
 
-type WalletService_AccountNotificationsClient interface {
-	Recv() (*AccountNotificationsResponse, error)
-	grpc.ClientStream
+type FundPsbtResponse struct {
+	Psbt        []byte `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	ChangeIndex int32  `protobuf:"varint,2,opt,name=change_index,json=changeIndex" json:"change_index,omitempty"`
 }
 
-type walletServiceAccountNotificationsClient struct {
-	grpc.ClientStream
-}
+func (m *FundPsbtResponse) Reset()                    { *m = FundPsbtResponse{} }
+func (m *FundPsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*FundPsbtResponse) ProtoMessage()               {}
+func (*FundPsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
 
-func (x *walletServiceAccountNotificationsClient) Recv() (*AccountNotificationsResponse, error) {
-	m := new(AccountNotificationsResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *FundPsbtResponse) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
 	}
-	return m, nil
+	return nil
 }
 
-func (c *walletServiceClient) ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error) {
-	out := new(ChangePassphraseResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ChangePassphrase", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FundPsbtResponse) GetChangeIndex() int32 {
+	if m != nil {
+		return m.ChangeIndex
 	}
-	return out, nil
+	return 0
 }
 
-func (c *walletServiceClient) RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error) {
-	out := new(RenameAccountResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/RenameAccount", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type FinalizePsbtRequest struct {
+	Passphrase []byte    `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	Psbt       []byte    `protobuf:"bytes,2,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	KeyScope   *KeyScope `protobuf:"bytes,3,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	Account    uint32    `protobuf:"varint,4,opt,name=account" json:"account,omitempty"`
 }
 
-func (c *walletServiceClient) NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error) {
-	out := new(NextAccountResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAccount", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FinalizePsbtRequest) Reset()                    { *m = FinalizePsbtRequest{} }
+func (m *FinalizePsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*FinalizePsbtRequest) ProtoMessage()               {}
+func (*FinalizePsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *FinalizePsbtRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
 	}
-	return out, nil
+	return nil
 }
 
-func (c *walletServiceClient) NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error) {
-	out := new(NextAddressResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FinalizePsbtRequest) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
 	}
-	return out, nil
+	return nil
 }
 
-func (c *walletServiceClient) ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error) {
-	out := new(ImportPrivateKeyResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportPrivateKey", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FinalizePsbtRequest) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
 	}
-	return out, nil
+	return nil
 }
 
-func (c *walletServiceClient) FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error) {
-	out := new(FundTransactionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FundTransaction", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FinalizePsbtRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
 	}
-	return out, nil
+	return 0
 }
 
-func (c *walletServiceClient) SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error) {
-	out := new(SignTransactionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignTransaction", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type FinalizePsbtResponse struct {
+	Psbt        []byte `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	Transaction []byte `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
 }
 
-func (c *walletServiceClient) PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error) {
-	out := new(PublishTransactionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/PublishTransaction", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FinalizePsbtResponse) Reset()                    { *m = FinalizePsbtResponse{} }
+func (m *FinalizePsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*FinalizePsbtResponse) ProtoMessage()               {}
+func (*FinalizePsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *FinalizePsbtResponse) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
 	}
-	return out, nil
+	return nil
 }
 
-// Server API for WalletService service
+func (m *FinalizePsbtResponse) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+type LeaseOutputRequest struct {
+	Id              []byte    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Outpoint        *OutPoint `protobuf:"bytes,2,opt,name=outpoint" json:"outpoint,omitempty"`
+	DurationSeconds int64     `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds" json:"duration_seconds,omitempty"`
+}
+
+func (m *LeaseOutputRequest) Reset()                    { *m = LeaseOutputRequest{} }
+func (m *LeaseOutputRequest) String() string            { return proto.CompactTextString(m) }
+func (*LeaseOutputRequest) ProtoMessage()               {}
+func (*LeaseOutputRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *LeaseOutputRequest) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *LeaseOutputRequest) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
+}
+
+func (m *LeaseOutputRequest) GetDurationSeconds() int64 {
+	if m != nil {
+		return m.DurationSeconds
+	}
+	return 0
+}
+
+type LeaseOutputResponse struct {
+	Expiration int64 `protobuf:"varint,1,opt,name=expiration" json:"expiration,omitempty"`
+}
+
+func (m *LeaseOutputResponse) Reset()                    { *m = LeaseOutputResponse{} }
+func (m *LeaseOutputResponse) String() string            { return proto.CompactTextString(m) }
+func (*LeaseOutputResponse) ProtoMessage()               {}
+func (*LeaseOutputResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *LeaseOutputResponse) GetExpiration() int64 {
+	if m != nil {
+		return m.Expiration
+	}
+	return 0
+}
+
+type ReleaseOutputRequest struct {
+	Id       []byte    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Outpoint *OutPoint `protobuf:"bytes,2,opt,name=outpoint" json:"outpoint,omitempty"`
+}
+
+func (m *ReleaseOutputRequest) Reset()                    { *m = ReleaseOutputRequest{} }
+func (m *ReleaseOutputRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReleaseOutputRequest) ProtoMessage()               {}
+func (*ReleaseOutputRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *ReleaseOutputRequest) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *ReleaseOutputRequest) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
+}
+
+type ReleaseOutputResponse struct {
+}
+
+func (m *ReleaseOutputResponse) Reset()                    { *m = ReleaseOutputResponse{} }
+func (m *ReleaseOutputResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReleaseOutputResponse) ProtoMessage()               {}
+func (*ReleaseOutputResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+type ImportAccountRequest struct {
+	AccountName          string      `protobuf:"bytes,1,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	ExtendedPublicKey    string      `protobuf:"bytes,2,opt,name=extended_public_key,json=extendedPublicKey" json:"extended_public_key,omitempty"`
+	MasterKeyFingerprint uint32      `protobuf:"varint,3,opt,name=master_key_fingerprint,json=masterKeyFingerprint" json:"master_key_fingerprint,omitempty"`
+	AddressType          AddressType `protobuf:"varint,4,opt,name=address_type,json=addressType,enum=walletrpc.AddressType" json:"address_type,omitempty"`
+}
+
+func (m *ImportAccountRequest) Reset()                    { *m = ImportAccountRequest{} }
+func (m *ImportAccountRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportAccountRequest) ProtoMessage()               {}
+func (*ImportAccountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *ImportAccountRequest) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
+}
+
+func (m *ImportAccountRequest) GetExtendedPublicKey() string {
+	if m != nil {
+		return m.ExtendedPublicKey
+	}
+	return ""
+}
+
+func (m *ImportAccountRequest) GetMasterKeyFingerprint() uint32 {
+	if m != nil {
+		return m.MasterKeyFingerprint
+	}
+	return 0
+}
+
+func (m *ImportAccountRequest) GetAddressType() AddressType {
+	if m != nil {
+		return m.AddressType
+	}
+	return AddressType_UNKNOWN
+}
+
+type ImportAccountResponse struct {
+	AccountNumber    uint32    `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
+	AccountName      string    `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	KeyScope         *KeyScope `protobuf:"bytes,3,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	ExternalKeyCount uint32    `protobuf:"varint,4,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
+	InternalKeyCount uint32    `protobuf:"varint,5,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
+	WatchOnly        bool      `protobuf:"varint,6,opt,name=watch_only,json=watchOnly" json:"watch_only,omitempty"`
+}
+
+func (m *ImportAccountResponse) Reset()                    { *m = ImportAccountResponse{} }
+func (m *ImportAccountResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportAccountResponse) ProtoMessage()               {}
+func (*ImportAccountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+func (m *ImportAccountResponse) GetAccountNumber() uint32 {
+	if m != nil {
+		return m.AccountNumber
+	}
+	return 0
+}
+
+func (m *ImportAccountResponse) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
+}
+
+func (m *ImportAccountResponse) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
+	}
+	return nil
+}
+
+func (m *ImportAccountResponse) GetExternalKeyCount() uint32 {
+	if m != nil {
+		return m.ExternalKeyCount
+	}
+	return 0
+}
+
+func (m *ImportAccountResponse) GetInternalKeyCount() uint32 {
+	if m != nil {
+		return m.InternalKeyCount
+	}
+	return 0
+}
+
+func (m *ImportAccountResponse) GetWatchOnly() bool {
+	if m != nil {
+		return m.WatchOnly
+	}
+	return false
+}
+
+type ImportPublicKeyRequest struct {
+	PublicKey   []byte      `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	AddressType AddressType `protobuf:"varint,2,opt,name=address_type,json=addressType,enum=walletrpc.AddressType" json:"address_type,omitempty"`
+}
+
+func (m *ImportPublicKeyRequest) Reset()                    { *m = ImportPublicKeyRequest{} }
+func (m *ImportPublicKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportPublicKeyRequest) ProtoMessage()               {}
+func (*ImportPublicKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *ImportPublicKeyRequest) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *ImportPublicKeyRequest) GetAddressType() AddressType {
+	if m != nil {
+		return m.AddressType
+	}
+	return AddressType_UNKNOWN
+}
+
+type ImportPublicKeyResponse struct {
+}
+
+func (m *ImportPublicKeyResponse) Reset()                    { *m = ImportPublicKeyResponse{} }
+func (m *ImportPublicKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportPublicKeyResponse) ProtoMessage()               {}
+func (*ImportPublicKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+type ImportTaprootScriptRequest struct {
+	KeyScope       *KeyScope                             `protobuf:"bytes,1,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	InternalKey    []byte                                `protobuf:"bytes,2,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
+	Leaves         []*ImportTaprootScriptRequest_TapLeaf `protobuf:"bytes,3,rep,name=leaves" json:"leaves,omitempty"`
+	RootHash       []byte                                `protobuf:"bytes,4,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	BirthdayHeight int32                                 `protobuf:"varint,5,opt,name=birthday_height,json=birthdayHeight" json:"birthday_height,omitempty"`
+}
+
+func (m *ImportTaprootScriptRequest) Reset()                    { *m = ImportTaprootScriptRequest{} }
+func (m *ImportTaprootScriptRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportTaprootScriptRequest) ProtoMessage()               {}
+func (*ImportTaprootScriptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *ImportTaprootScriptRequest) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
+	}
+	return nil
+}
+
+func (m *ImportTaprootScriptRequest) GetInternalKey() []byte {
+	if m != nil {
+		return m.InternalKey
+	}
+	return nil
+}
+
+func (m *ImportTaprootScriptRequest) GetLeaves() []*ImportTaprootScriptRequest_TapLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *ImportTaprootScriptRequest) GetRootHash() []byte {
+	if m != nil {
+		return m.RootHash
+	}
+	return nil
+}
+
+func (m *ImportTaprootScriptRequest) GetBirthdayHeight() int32 {
+	if m != nil {
+		return m.BirthdayHeight
+	}
+	return 0
+}
+
+type ImportTaprootScriptRequest_TapLeaf struct {
+	LeafVersion uint32 `protobuf:"varint,1,opt,name=leaf_version,json=leafVersion" json:"leaf_version,omitempty"`
+	Script      []byte `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`
+}
+
+func (m *ImportTaprootScriptRequest_TapLeaf) Reset()         { *m = ImportTaprootScriptRequest_TapLeaf{} }
+func (m *ImportTaprootScriptRequest_TapLeaf) String() string { return proto.CompactTextString(m) }
+func (*ImportTaprootScriptRequest_TapLeaf) ProtoMessage()    {}
+func (*ImportTaprootScriptRequest_TapLeaf) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{60, 0}
+}
+
+func (m *ImportTaprootScriptRequest_TapLeaf) GetLeafVersion() uint32 {
+	if m != nil {
+		return m.LeafVersion
+	}
+	return 0
+}
+
+func (m *ImportTaprootScriptRequest_TapLeaf) GetScript() []byte {
+	if m != nil {
+		return m.Script
+	}
+	return nil
+}
+
+type ImportTaprootScriptResponse struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *ImportTaprootScriptResponse) Reset()                    { *m = ImportTaprootScriptResponse{} }
+func (m *ImportTaprootScriptResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportTaprootScriptResponse) ProtoMessage()               {}
+func (*ImportTaprootScriptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *ImportTaprootScriptResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type ImportLegacyKeystoreRequest struct {
+	Passphrase         []byte    `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	Keystore           []byte    `protobuf:"bytes,2,opt,name=keystore,proto3" json:"keystore,omitempty"`
+	KeystorePassphrase []byte    `protobuf:"bytes,3,opt,name=keystore_passphrase,json=keystorePassphrase,proto3" json:"keystore_passphrase,omitempty"`
+	KeyScope           *KeyScope `protobuf:"bytes,4,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	Rescan             bool      `protobuf:"varint,5,opt,name=rescan" json:"rescan,omitempty"`
+}
+
+func (m *ImportLegacyKeystoreRequest) Reset()                    { *m = ImportLegacyKeystoreRequest{} }
+func (m *ImportLegacyKeystoreRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportLegacyKeystoreRequest) ProtoMessage()               {}
+func (*ImportLegacyKeystoreRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *ImportLegacyKeystoreRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreRequest) GetKeystore() []byte {
+	if m != nil {
+		return m.Keystore
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreRequest) GetKeystorePassphrase() []byte {
+	if m != nil {
+		return m.KeystorePassphrase
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreRequest) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreRequest) GetRescan() bool {
+	if m != nil {
+		return m.Rescan
+	}
+	return false
+}
+
+type ImportLegacyKeystoreResponse struct {
+	KeyScope        *KeyScope                               `protobuf:"bytes,1,opt,name=key_scope,json=keyScope" json:"key_scope,omitempty"`
+	Account         uint32                                  `protobuf:"varint,2,opt,name=account" json:"account,omitempty"`
+	Addresses       []*ImportLegacyKeystoreResponse_Address `protobuf:"bytes,3,rep,name=addresses" json:"addresses,omitempty"`
+	BirthdayChanged bool                                    `protobuf:"varint,4,opt,name=birthday_changed,json=birthdayChanged" json:"birthday_changed,omitempty"`
+	BirthdayHeight  int32                                   `protobuf:"varint,5,opt,name=birthday_height,json=birthdayHeight" json:"birthday_height,omitempty"`
+	BirthdayHash    []byte                                  `protobuf:"bytes,6,opt,name=birthday_hash,json=birthdayHash,proto3" json:"birthday_hash,omitempty"`
+}
+
+func (m *ImportLegacyKeystoreResponse) Reset()                    { *m = ImportLegacyKeystoreResponse{} }
+func (m *ImportLegacyKeystoreResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportLegacyKeystoreResponse) ProtoMessage()               {}
+func (*ImportLegacyKeystoreResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *ImportLegacyKeystoreResponse) GetKeyScope() *KeyScope {
+	if m != nil {
+		return m.KeyScope
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreResponse) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
+}
+
+func (m *ImportLegacyKeystoreResponse) GetAddresses() []*ImportLegacyKeystoreResponse_Address {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreResponse) GetBirthdayChanged() bool {
+	if m != nil {
+		return m.BirthdayChanged
+	}
+	return false
+}
+
+func (m *ImportLegacyKeystoreResponse) GetBirthdayHeight() int32 {
+	if m != nil {
+		return m.BirthdayHeight
+	}
+	return 0
+}
+
+func (m *ImportLegacyKeystoreResponse) GetBirthdayHash() []byte {
+	if m != nil {
+		return m.BirthdayHash
+	}
+	return nil
+}
+
+type ImportLegacyKeystoreResponse_Address struct {
+	Address        string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Script         bool   `protobuf:"varint,2,opt,name=script" json:"script,omitempty"`
+	FirstBlock     int32  `protobuf:"varint,3,opt,name=first_block,json=firstBlock" json:"first_block,omitempty"`
+	BirthdayHeight int32  `protobuf:"varint,4,opt,name=birthday_height,json=birthdayHeight" json:"birthday_height,omitempty"`
+	BirthdayHash   []byte `protobuf:"bytes,5,opt,name=birthday_hash,json=birthdayHash,proto3" json:"birthday_hash,omitempty"`
+	Existed        bool   `protobuf:"varint,6,opt,name=existed" json:"existed,omitempty"`
+	Error          string `protobuf:"bytes,7,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) Reset()         { *m = ImportLegacyKeystoreResponse_Address{} }
+func (m *ImportLegacyKeystoreResponse_Address) String() string { return proto.CompactTextString(m) }
+func (*ImportLegacyKeystoreResponse_Address) ProtoMessage()    {}
+func (*ImportLegacyKeystoreResponse_Address) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{63, 0}
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetScript() bool {
+	if m != nil {
+		return m.Script
+	}
+	return false
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetFirstBlock() int32 {
+	if m != nil {
+		return m.FirstBlock
+	}
+	return 0
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetBirthdayHeight() int32 {
+	if m != nil {
+		return m.BirthdayHeight
+	}
+	return 0
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetBirthdayHash() []byte {
+	if m != nil {
+		return m.BirthdayHash
+	}
+	return nil
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetExisted() bool {
+	if m != nil {
+		return m.Existed
+	}
+	return false
+}
+
+func (m *ImportLegacyKeystoreResponse_Address) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type LabelTransactionRequest struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	Label           string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+	Overwrite       bool   `protobuf:"varint,3,opt,name=overwrite" json:"overwrite,omitempty"`
+}
+
+func (m *LabelTransactionRequest) Reset()                    { *m = LabelTransactionRequest{} }
+func (m *LabelTransactionRequest) String() string            { return proto.CompactTextString(m) }
+func (*LabelTransactionRequest) ProtoMessage()               {}
+func (*LabelTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *LabelTransactionRequest) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *LabelTransactionRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *LabelTransactionRequest) GetOverwrite() bool {
+	if m != nil {
+		return m.Overwrite
+	}
+	return false
+}
+
+type LabelTransactionResponse struct {
+}
+
+func (m *LabelTransactionResponse) Reset()                    { *m = LabelTransactionResponse{} }
+func (m *LabelTransactionResponse) String() string            { return proto.CompactTextString(m) }
+func (*LabelTransactionResponse) ProtoMessage()               {}
+func (*LabelTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+type RescanRequest struct {
+	BeginHeight int32    `protobuf:"varint,1,opt,name=begin_height,json=beginHeight" json:"begin_height,omitempty"`
+	Addresses   []string `protobuf:"bytes,2,rep,name=addresses" json:"addresses,omitempty"`
+}
+
+func (m *RescanRequest) Reset()                    { *m = RescanRequest{} }
+func (m *RescanRequest) String() string            { return proto.CompactTextString(m) }
+func (*RescanRequest) ProtoMessage()               {}
+func (*RescanRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *RescanRequest) GetBeginHeight() int32 {
+	if m != nil {
+		return m.BeginHeight
+	}
+	return 0
+}
+
+func (m *RescanRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type RescanResponse struct {
+}
+
+func (m *RescanResponse) Reset()                    { *m = RescanResponse{} }
+func (m *RescanResponse) String() string            { return proto.CompactTextString(m) }
+func (*RescanResponse) ProtoMessage()               {}
+func (*RescanResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+type RecoveryProgressRequest struct {
+}
+
+func (m *RecoveryProgressRequest) Reset()                    { *m = RecoveryProgressRequest{} }
+func (m *RecoveryProgressRequest) String() string            { return proto.CompactTextString(m) }
+func (*RecoveryProgressRequest) ProtoMessage()               {}
+func (*RecoveryProgressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+type RecoveryProgressResponse struct {
+	Recovering  bool    `protobuf:"varint,1,opt,name=recovering" json:"recovering,omitempty"`
+	Finished    bool    `protobuf:"varint,2,opt,name=finished" json:"finished,omitempty"`
+	StartHeight int32   `protobuf:"varint,3,opt,name=start_height,json=startHeight" json:"start_height,omitempty"`
+	Height      int32   `protobuf:"varint,4,opt,name=height" json:"height,omitempty"`
+	BestHeight  int32   `protobuf:"varint,5,opt,name=best_height,json=bestHeight" json:"best_height,omitempty"`
+	Progress    float64 `protobuf:"fixed64,6,opt,name=progress" json:"progress,omitempty"`
+	EtaSeconds  int64   `protobuf:"varint,7,opt,name=eta_seconds,json=etaSeconds" json:"eta_seconds,omitempty"`
+}
+
+func (m *RecoveryProgressResponse) Reset()                    { *m = RecoveryProgressResponse{} }
+func (m *RecoveryProgressResponse) String() string            { return proto.CompactTextString(m) }
+func (*RecoveryProgressResponse) ProtoMessage()               {}
+func (*RecoveryProgressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+func (m *RecoveryProgressResponse) GetRecovering() bool {
+	if m != nil {
+		return m.Recovering
+	}
+	return false
+}
+
+func (m *RecoveryProgressResponse) GetFinished() bool {
+	if m != nil {
+		return m.Finished
+	}
+	return false
+}
+
+func (m *RecoveryProgressResponse) GetStartHeight() int32 {
+	if m != nil {
+		return m.StartHeight
+	}
+	return 0
+}
+
+func (m *RecoveryProgressResponse) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *RecoveryProgressResponse) GetBestHeight() int32 {
+	if m != nil {
+		return m.BestHeight
+	}
+	return 0
+}
+
+func (m *RecoveryProgressResponse) GetProgress() float64 {
+	if m != nil {
+		return m.Progress
+	}
+	return 0
+}
+
+func (m *RecoveryProgressResponse) GetEtaSeconds() int64 {
+	if m != nil {
+		return m.EtaSeconds
+	}
+	return 0
+}
+
+type PassphraseKdfRequest struct {
+}
+
+func (m *PassphraseKdfRequest) Reset()                    { *m = PassphraseKdfRequest{} }
+func (m *PassphraseKdfRequest) String() string            { return proto.CompactTextString(m) }
+func (*PassphraseKdfRequest) ProtoMessage()               {}
+func (*PassphraseKdfRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+
+type PassphraseKdfResponse struct {
+	Public  *KdfParameters `protobuf:"bytes,1,opt,name=public" json:"public,omitempty"`
+	Private *KdfParameters `protobuf:"bytes,2,opt,name=private" json:"private,omitempty"`
+}
+
+func (m *PassphraseKdfResponse) Reset()                    { *m = PassphraseKdfResponse{} }
+func (m *PassphraseKdfResponse) String() string            { return proto.CompactTextString(m) }
+func (*PassphraseKdfResponse) ProtoMessage()               {}
+func (*PassphraseKdfResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+
+func (m *PassphraseKdfResponse) GetPublic() *KdfParameters {
+	if m != nil {
+		return m.Public
+	}
+	return nil
+}
+
+func (m *PassphraseKdfResponse) GetPrivate() *KdfParameters {
+	if m != nil {
+		return m.Private
+	}
+	return nil
+}
+
+type SignMessageRequest struct {
+	Passphrase []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	Address    string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Message    string `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *SignMessageRequest) Reset()                    { *m = SignMessageRequest{} }
+func (m *SignMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageRequest) ProtoMessage()               {}
+func (*SignMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+func (m *SignMessageRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
+	}
+	return nil
+}
+
+func (m *SignMessageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SignMessageRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type SignMessageResponse struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignMessageResponse) Reset()                    { *m = SignMessageResponse{} }
+func (m *SignMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageResponse) ProtoMessage()               {}
+func (*SignMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+func (m *SignMessageResponse) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type BakeMacaroonRequest struct {
+	Permissions []string `protobuf:"bytes,1,rep,name=permissions" json:"permissions,omitempty"`
+	RootKeyId   uint64   `protobuf:"varint,2,opt,name=root_key_id,json=rootKeyId" json:"root_key_id,omitempty"`
+	IpAddress   string   `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress" json:"ip_address,omitempty"`
+	ExpiryTime  int64    `protobuf:"varint,4,opt,name=expiry_time,json=expiryTime" json:"expiry_time,omitempty"`
+	SpendLimit  int64    `protobuf:"varint,5,opt,name=spend_limit,json=spendLimit" json:"spend_limit,omitempty"`
+}
+
+func (m *BakeMacaroonRequest) Reset()                    { *m = BakeMacaroonRequest{} }
+func (m *BakeMacaroonRequest) String() string            { return proto.CompactTextString(m) }
+func (*BakeMacaroonRequest) ProtoMessage()               {}
+func (*BakeMacaroonRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *BakeMacaroonRequest) GetPermissions() []string {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
+func (m *BakeMacaroonRequest) GetRootKeyId() uint64 {
+	if m != nil {
+		return m.RootKeyId
+	}
+	return 0
+}
+
+func (m *BakeMacaroonRequest) GetIpAddress() string {
+	if m != nil {
+		return m.IpAddress
+	}
+	return ""
+}
+
+func (m *BakeMacaroonRequest) GetExpiryTime() int64 {
+	if m != nil {
+		return m.ExpiryTime
+	}
+	return 0
+}
+
+func (m *BakeMacaroonRequest) GetSpendLimit() int64 {
+	if m != nil {
+		return m.SpendLimit
+	}
+	return 0
+}
+
+type BakeMacaroonResponse struct {
+	Macaroon []byte `protobuf:"bytes,1,opt,name=macaroon,proto3" json:"macaroon,omitempty"`
+}
+
+func (m *BakeMacaroonResponse) Reset()                    { *m = BakeMacaroonResponse{} }
+func (m *BakeMacaroonResponse) String() string            { return proto.CompactTextString(m) }
+func (*BakeMacaroonResponse) ProtoMessage()               {}
+func (*BakeMacaroonResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+
+func (m *BakeMacaroonResponse) GetMacaroon() []byte {
+	if m != nil {
+		return m.Macaroon
+	}
+	return nil
+}
+
+type RevokeMacaroonRootKeyRequest struct {
+	RootKeyId uint64 `protobuf:"varint,1,opt,name=root_key_id,json=rootKeyId" json:"root_key_id,omitempty"`
+}
+
+func (m *RevokeMacaroonRootKeyRequest) Reset()                    { *m = RevokeMacaroonRootKeyRequest{} }
+func (m *RevokeMacaroonRootKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeMacaroonRootKeyRequest) ProtoMessage()               {}
+func (*RevokeMacaroonRootKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+func (m *RevokeMacaroonRootKeyRequest) GetRootKeyId() uint64 {
+	if m != nil {
+		return m.RootKeyId
+	}
+	return 0
+}
+
+type RevokeMacaroonRootKeyResponse struct {
+}
+
+func (m *RevokeMacaroonRootKeyResponse) Reset()                    { *m = RevokeMacaroonRootKeyResponse{} }
+func (m *RevokeMacaroonRootKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*RevokeMacaroonRootKeyResponse) ProtoMessage()               {}
+func (*RevokeMacaroonRootKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+type ListMacaroonRootKeysRequest struct {
+}
+
+func (m *ListMacaroonRootKeysRequest) Reset()                    { *m = ListMacaroonRootKeysRequest{} }
+func (m *ListMacaroonRootKeysRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListMacaroonRootKeysRequest) ProtoMessage()               {}
+func (*ListMacaroonRootKeysRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+type ListMacaroonRootKeysResponse struct {
+	RootKeyIds []uint64 `protobuf:"varint,1,rep,packed,name=root_key_ids,json=rootKeyIds" json:"root_key_ids,omitempty"`
+}
+
+func (m *ListMacaroonRootKeysResponse) Reset()                    { *m = ListMacaroonRootKeysResponse{} }
+func (m *ListMacaroonRootKeysResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListMacaroonRootKeysResponse) ProtoMessage()               {}
+func (*ListMacaroonRootKeysResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+func (m *ListMacaroonRootKeysResponse) GetRootKeyIds() []uint64 {
+	if m != nil {
+		return m.RootKeyIds
+	}
+	return nil
+}
+
+type TransactionNotificationsRequest struct {
+}
+
+func (m *TransactionNotificationsRequest) Reset()         { *m = TransactionNotificationsRequest{} }
+func (m *TransactionNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionNotificationsRequest) ProtoMessage()    {}
+func (*TransactionNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{80}
+}
+
+type TransactionNotificationsResponse struct {
+	// Sorted by increasing height.  This is a repeated field so many new blocks
+	// in a new best chain can be notified at once during a reorganize.
+	AttachedBlocks []*BlockDetails `protobuf:"bytes,1,rep,name=attached_blocks,json=attachedBlocks" json:"attached_blocks,omitempty"`
+	// If there was a chain reorganize, there may have been blocks with wallet
+	// transactions that are no longer in the best chain.  These are those
+	// block's hashes.
+	DetachedBlocks [][]byte `protobuf:"bytes,2,rep,name=detached_blocks,json=detachedBlocks,proto3" json:"detached_blocks,omitempty"`
+	// Any new unmined transactions are included here.  These unmined transactions
+	// refer to the current best chain, so transactions from detached blocks may
+	// be moved to mempool and included here if they are not mined or double spent
+	// in the new chain.  Additonally, if no new blocks were attached but a relevant
+	// unmined transaction is seen by the wallet, it will be reported here.
+	UnminedTransactions []*TransactionDetails `protobuf:"bytes,3,rep,name=unmined_transactions,json=unminedTransactions" json:"unmined_transactions,omitempty"`
+	// Instead of notifying all of the removed unmined transactions,
+	// just send all of the current hashes.
+	UnminedTransactionHashes [][]byte `protobuf:"bytes,4,rep,name=unmined_transaction_hashes,json=unminedTransactionHashes,proto3" json:"unmined_transaction_hashes,omitempty"`
+	// Hashes of the unmined transactions removed from the wallet because
+	// they were evicted from the mempool.
+	EvictedTransactions  [][]byte                                                `protobuf:"bytes,5,rep,name=evicted_transactions,json=evictedTransactions,proto3" json:"evicted_transactions,omitempty"`
+	ReplacedTransactions []*TransactionNotificationsResponse_ReplacedTransaction `protobuf:"bytes,6,rep,name=replaced_transactions,json=replacedTransactions" json:"replaced_transactions,omitempty"`
+}
+
+func (m *TransactionNotificationsResponse) Reset()         { *m = TransactionNotificationsResponse{} }
+func (m *TransactionNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactionNotificationsResponse) ProtoMessage()    {}
+func (*TransactionNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{81}
+}
+
+func (m *TransactionNotificationsResponse) GetAttachedBlocks() []*BlockDetails {
+	if m != nil {
+		return m.AttachedBlocks
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetDetachedBlocks() [][]byte {
+	if m != nil {
+		return m.DetachedBlocks
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetUnminedTransactions() []*TransactionDetails {
+	if m != nil {
+		return m.UnminedTransactions
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetUnminedTransactionHashes() [][]byte {
+	if m != nil {
+		return m.UnminedTransactionHashes
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetEvictedTransactions() [][]byte {
+	if m != nil {
+		return m.EvictedTransactions
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetReplacedTransactions() []*TransactionNotificationsResponse_ReplacedTransaction {
+	if m != nil {
+		return m.ReplacedTransactions
+	}
+	return nil
+}
+
+// The unmined transactions removed from the wallet because a conflicting
+// transaction replaced them in the mempool.
+type TransactionNotificationsResponse_ReplacedTransaction struct {
+	Hash       []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	ReplacedBy []byte `protobuf:"bytes,2,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+}
+
+func (m *TransactionNotificationsResponse_ReplacedTransaction) Reset() {
+	*m = TransactionNotificationsResponse_ReplacedTransaction{}
+}
+func (m *TransactionNotificationsResponse_ReplacedTransaction) String() string {
+	return proto.CompactTextString(m)
+}
+func (*TransactionNotificationsResponse_ReplacedTransaction) ProtoMessage() {}
+func (*TransactionNotificationsResponse_ReplacedTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{81, 0}
+}
+
+func (m *TransactionNotificationsResponse_ReplacedTransaction) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse_ReplacedTransaction) GetReplacedBy() []byte {
+	if m != nil {
+		return m.ReplacedBy
+	}
+	return nil
+}
+
+type SpentnessNotificationsRequest struct {
+	Account         uint32 `protobuf:"varint,1,opt,name=account" json:"account,omitempty"`
+	NoNotifyUnspent bool   `protobuf:"varint,2,opt,name=no_notify_unspent,json=noNotifyUnspent" json:"no_notify_unspent,omitempty"`
+	NoNotifySpent   bool   `protobuf:"varint,3,opt,name=no_notify_spent,json=noNotifySpent" json:"no_notify_spent,omitempty"`
+}
+
+func (m *SpentnessNotificationsRequest) Reset()                    { *m = SpentnessNotificationsRequest{} }
+func (m *SpentnessNotificationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SpentnessNotificationsRequest) ProtoMessage()               {}
+func (*SpentnessNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *SpentnessNotificationsRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
+}
+
+func (m *SpentnessNotificationsRequest) GetNoNotifyUnspent() bool {
+	if m != nil {
+		return m.NoNotifyUnspent
+	}
+	return false
+}
+
+func (m *SpentnessNotificationsRequest) GetNoNotifySpent() bool {
+	if m != nil {
+		return m.NoNotifySpent
+	}
+	return false
+}
+
+type SpentnessNotificationsResponse struct {
+	TransactionHash []byte                                  `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex     uint32                                  `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
+	Spender         *SpentnessNotificationsResponse_Spender `protobuf:"bytes,3,opt,name=spender" json:"spender,omitempty"`
+}
+
+func (m *SpentnessNotificationsResponse) Reset()         { *m = SpentnessNotificationsResponse{} }
+func (m *SpentnessNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*SpentnessNotificationsResponse) ProtoMessage()    {}
+func (*SpentnessNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{83}
+}
+
+func (m *SpentnessNotificationsResponse) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *SpentnessNotificationsResponse) GetOutputIndex() uint32 {
+	if m != nil {
+		return m.OutputIndex
+	}
+	return 0
+}
+
+func (m *SpentnessNotificationsResponse) GetSpender() *SpentnessNotificationsResponse_Spender {
+	if m != nil {
+		return m.Spender
+	}
+	return nil
+}
+
+type SpentnessNotificationsResponse_Spender struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	InputIndex      uint32 `protobuf:"varint,2,opt,name=input_index,json=inputIndex" json:"input_index,omitempty"`
+}
+
+func (m *SpentnessNotificationsResponse_Spender) Reset() {
+	*m = SpentnessNotificationsResponse_Spender{}
+}
+func (m *SpentnessNotificationsResponse_Spender) String() string { return proto.CompactTextString(m) }
+func (*SpentnessNotificationsResponse_Spender) ProtoMessage()    {}
+func (*SpentnessNotificationsResponse_Spender) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{83, 0}
+}
+
+func (m *SpentnessNotificationsResponse_Spender) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *SpentnessNotificationsResponse_Spender) GetInputIndex() uint32 {
+	if m != nil {
+		return m.InputIndex
+	}
+	return 0
+}
+
+type AccountNotificationsRequest struct {
+}
+
+func (m *AccountNotificationsRequest) Reset()                    { *m = AccountNotificationsRequest{} }
+func (m *AccountNotificationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*AccountNotificationsRequest) ProtoMessage()               {}
+func (*AccountNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+type AccountNotificationsResponse struct {
+	AccountNumber    uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
+	AccountName      string `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	ExternalKeyCount uint32 `protobuf:"varint,3,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
+	InternalKeyCount uint32 `protobuf:"varint,4,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
+	ImportedKeyCount uint32 `protobuf:"varint,5,opt,name=imported_key_count,json=importedKeyCount" json:"imported_key_count,omitempty"`
+}
+
+func (m *AccountNotificationsResponse) Reset()                    { *m = AccountNotificationsResponse{} }
+func (m *AccountNotificationsResponse) String() string            { return proto.CompactTextString(m) }
+func (*AccountNotificationsResponse) ProtoMessage()               {}
+func (*AccountNotificationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *AccountNotificationsResponse) GetAccountNumber() uint32 {
+	if m != nil {
+		return m.AccountNumber
+	}
+	return 0
+}
+
+func (m *AccountNotificationsResponse) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
+}
+
+func (m *AccountNotificationsResponse) GetExternalKeyCount() uint32 {
+	if m != nil {
+		return m.ExternalKeyCount
+	}
+	return 0
+}
+
+func (m *AccountNotificationsResponse) GetInternalKeyCount() uint32 {
+	if m != nil {
+		return m.InternalKeyCount
+	}
+	return 0
+}
+
+func (m *AccountNotificationsResponse) GetImportedKeyCount() uint32 {
+	if m != nil {
+		return m.ImportedKeyCount
+	}
+	return 0
+}
+
+type CreateWalletRequest struct {
+	PublicPassphrase  []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
+	PrivatePassphrase []byte `protobuf:"bytes,2,opt,name=private_passphrase,json=privatePassphrase,proto3" json:"private_passphrase,omitempty"`
+	Seed              []byte `protobuf:"bytes,3,opt,name=seed,proto3" json:"seed,omitempty"`
+}
+
+func (m *CreateWalletRequest) Reset()                    { *m = CreateWalletRequest{} }
+func (m *CreateWalletRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateWalletRequest) ProtoMessage()               {}
+func (*CreateWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
+func (m *CreateWalletRequest) GetPublicPassphrase() []byte {
+	if m != nil {
+		return m.PublicPassphrase
+	}
+	return nil
+}
+
+func (m *CreateWalletRequest) GetPrivatePassphrase() []byte {
+	if m != nil {
+		return m.PrivatePassphrase
+	}
+	return nil
+}
+
+func (m *CreateWalletRequest) GetSeed() []byte {
+	if m != nil {
+		return m.Seed
+	}
+	return nil
+}
+
+type CreateWalletResponse struct {
+}
+
+func (m *CreateWalletResponse) Reset()                    { *m = CreateWalletResponse{} }
+func (m *CreateWalletResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateWalletResponse) ProtoMessage()               {}
+func (*CreateWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+
+type OpenWalletRequest struct {
+	PublicPassphrase []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
+}
+
+func (m *OpenWalletRequest) Reset()                    { *m = OpenWalletRequest{} }
+func (m *OpenWalletRequest) String() string            { return proto.CompactTextString(m) }
+func (*OpenWalletRequest) ProtoMessage()               {}
+func (*OpenWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
+
+func (m *OpenWalletRequest) GetPublicPassphrase() []byte {
+	if m != nil {
+		return m.PublicPassphrase
+	}
+	return nil
+}
+
+type OpenWalletResponse struct {
+}
+
+func (m *OpenWalletResponse) Reset()                    { *m = OpenWalletResponse{} }
+func (m *OpenWalletResponse) String() string            { return proto.CompactTextString(m) }
+func (*OpenWalletResponse) ProtoMessage()               {}
+func (*OpenWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
+
+type CloseWalletRequest struct {
+}
+
+func (m *CloseWalletRequest) Reset()                    { *m = CloseWalletRequest{} }
+func (m *CloseWalletRequest) String() string            { return proto.CompactTextString(m) }
+func (*CloseWalletRequest) ProtoMessage()               {}
+func (*CloseWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
+
+type CloseWalletResponse struct {
+}
+
+func (m *CloseWalletResponse) Reset()                    { *m = CloseWalletResponse{} }
+func (m *CloseWalletResponse) String() string            { return proto.CompactTextString(m) }
+func (*CloseWalletResponse) ProtoMessage()               {}
+func (*CloseWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
+
+type WalletExistsRequest struct {
+}
+
+func (m *WalletExistsRequest) Reset()                    { *m = WalletExistsRequest{} }
+func (m *WalletExistsRequest) String() string            { return proto.CompactTextString(m) }
+func (*WalletExistsRequest) ProtoMessage()               {}
+func (*WalletExistsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
+
+type WalletExistsResponse struct {
+	Exists bool `protobuf:"varint,1,opt,name=exists" json:"exists,omitempty"`
+}
+
+func (m *WalletExistsResponse) Reset()                    { *m = WalletExistsResponse{} }
+func (m *WalletExistsResponse) String() string            { return proto.CompactTextString(m) }
+func (*WalletExistsResponse) ProtoMessage()               {}
+func (*WalletExistsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
+
+func (m *WalletExistsResponse) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+type RollbackTransactionHistoryRequest struct {
+	PublicPassphrase []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
+	Height           int32  `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+	BlockHash        []byte `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+}
+
+func (m *RollbackTransactionHistoryRequest) Reset()         { *m = RollbackTransactionHistoryRequest{} }
+func (m *RollbackTransactionHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*RollbackTransactionHistoryRequest) ProtoMessage()    {}
+func (*RollbackTransactionHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{94}
+}
+
+func (m *RollbackTransactionHistoryRequest) GetPublicPassphrase() []byte {
+	if m != nil {
+		return m.PublicPassphrase
+	}
+	return nil
+}
+
+func (m *RollbackTransactionHistoryRequest) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *RollbackTransactionHistoryRequest) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+type RollbackTransactionHistoryResponse struct {
+	Height    int32  `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	BlockHash []byte `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+}
+
+func (m *RollbackTransactionHistoryResponse) Reset()         { *m = RollbackTransactionHistoryResponse{} }
+func (m *RollbackTransactionHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*RollbackTransactionHistoryResponse) ProtoMessage()    {}
+func (*RollbackTransactionHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{95}
+}
+
+func (m *RollbackTransactionHistoryResponse) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *RollbackTransactionHistoryResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+type StartConsensusRpcRequest struct {
+	NetworkAddress string `protobuf:"bytes,1,opt,name=network_address,json=networkAddress" json:"network_address,omitempty"`
+	Username       string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Password       []byte `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Certificate    []byte `protobuf:"bytes,4,opt,name=certificate,proto3" json:"certificate,omitempty"`
+}
+
+func (m *StartConsensusRpcRequest) Reset()                    { *m = StartConsensusRpcRequest{} }
+func (m *StartConsensusRpcRequest) String() string            { return proto.CompactTextString(m) }
+func (*StartConsensusRpcRequest) ProtoMessage()               {}
+func (*StartConsensusRpcRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+func (m *StartConsensusRpcRequest) GetNetworkAddress() string {
+	if m != nil {
+		return m.NetworkAddress
+	}
+	return ""
+}
+
+func (m *StartConsensusRpcRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *StartConsensusRpcRequest) GetPassword() []byte {
+	if m != nil {
+		return m.Password
+	}
+	return nil
+}
+
+func (m *StartConsensusRpcRequest) GetCertificate() []byte {
+	if m != nil {
+		return m.Certificate
+	}
+	return nil
+}
+
+type StartConsensusRpcResponse struct {
+}
+
+func (m *StartConsensusRpcResponse) Reset()                    { *m = StartConsensusRpcResponse{} }
+func (m *StartConsensusRpcResponse) String() string            { return proto.CompactTextString(m) }
+func (*StartConsensusRpcResponse) ProtoMessage()               {}
+func (*StartConsensusRpcResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
+
+func init() {
+	proto.RegisterType((*VersionRequest)(nil), "walletrpc.VersionRequest")
+	proto.RegisterType((*VersionResponse)(nil), "walletrpc.VersionResponse")
+	proto.RegisterType((*TransactionDetails)(nil), "walletrpc.TransactionDetails")
+	proto.RegisterType((*TransactionDetails_Input)(nil), "walletrpc.TransactionDetails.Input")
+	proto.RegisterType((*TransactionDetails_Output)(nil), "walletrpc.TransactionDetails.Output")
+	proto.RegisterType((*BlockDetails)(nil), "walletrpc.BlockDetails")
+	proto.RegisterType((*AccountBalance)(nil), "walletrpc.AccountBalance")
+	proto.RegisterType((*PingRequest)(nil), "walletrpc.PingRequest")
+	proto.RegisterType((*PingResponse)(nil), "walletrpc.PingResponse")
+	proto.RegisterType((*NetworkRequest)(nil), "walletrpc.NetworkRequest")
+	proto.RegisterType((*NetworkResponse)(nil), "walletrpc.NetworkResponse")
+	proto.RegisterType((*AccountNumberRequest)(nil), "walletrpc.AccountNumberRequest")
+	proto.RegisterType((*AccountNumberResponse)(nil), "walletrpc.AccountNumberResponse")
+	proto.RegisterType((*AccountsRequest)(nil), "walletrpc.AccountsRequest")
+	proto.RegisterType((*AccountsResponse)(nil), "walletrpc.AccountsResponse")
+	proto.RegisterType((*AccountsResponse_Account)(nil), "walletrpc.AccountsResponse.Account")
+	proto.RegisterType((*RenameAccountRequest)(nil), "walletrpc.RenameAccountRequest")
+	proto.RegisterType((*RenameAccountResponse)(nil), "walletrpc.RenameAccountResponse")
+	proto.RegisterType((*NextAccountRequest)(nil), "walletrpc.NextAccountRequest")
+	proto.RegisterType((*NextAccountResponse)(nil), "walletrpc.NextAccountResponse")
+	proto.RegisterType((*NextAddressRequest)(nil), "walletrpc.NextAddressRequest")
+	proto.RegisterType((*NextAddressResponse)(nil), "walletrpc.NextAddressResponse")
+	proto.RegisterType((*ImportPrivateKeyRequest)(nil), "walletrpc.ImportPrivateKeyRequest")
+	proto.RegisterType((*ImportPrivateKeyResponse)(nil), "walletrpc.ImportPrivateKeyResponse")
+	proto.RegisterType((*BalanceRequest)(nil), "walletrpc.BalanceRequest")
+	proto.RegisterType((*BalanceResponse)(nil), "walletrpc.BalanceResponse")
+	proto.RegisterType((*GetTransactionsRequest)(nil), "walletrpc.GetTransactionsRequest")
+	proto.RegisterType((*GetTransactionsResponse)(nil), "walletrpc.GetTransactionsResponse")
+	proto.RegisterType((*ChangePassphraseRequest)(nil), "walletrpc.ChangePassphraseRequest")
+	proto.RegisterType((*ChangePassphraseResponse)(nil), "walletrpc.ChangePassphraseResponse")
+	proto.RegisterType((*KdfParameters)(nil), "walletrpc.KdfParameters")
+	proto.RegisterType((*FundTransactionRequest)(nil), "walletrpc.FundTransactionRequest")
+	proto.RegisterType((*FundTransactionResponse)(nil), "walletrpc.FundTransactionResponse")
+	proto.RegisterType((*FundTransactionResponse_PreviousOutput)(nil), "walletrpc.FundTransactionResponse.PreviousOutput")
+	proto.RegisterType((*SignTransactionRequest)(nil), "walletrpc.SignTransactionRequest")
+	proto.RegisterType((*SignTransactionResponse)(nil), "walletrpc.SignTransactionResponse")
+	proto.RegisterType((*PublishTransactionRequest)(nil), "walletrpc.PublishTransactionRequest")
+	proto.RegisterType((*PublishTransactionResponse)(nil), "walletrpc.PublishTransactionResponse")
+	proto.RegisterType((*KeyScope)(nil), "walletrpc.KeyScope")
+	proto.RegisterType((*OutPoint)(nil), "walletrpc.OutPoint")
+	proto.RegisterType((*GetTransactionRequest)(nil), "walletrpc.GetTransactionRequest")
+	proto.RegisterType((*GetTransactionResponse)(nil), "walletrpc.GetTransactionResponse")
+	proto.RegisterType((*ListUnspentRequest)(nil), "walletrpc.ListUnspentRequest")
+	proto.RegisterType((*ListUnspentResponse)(nil), "walletrpc.ListUnspentResponse")
+	proto.RegisterType((*ListUnspentResponse_Unspent)(nil), "walletrpc.ListUnspentResponse.Unspent")
+	proto.RegisterType((*ListLeasedOutputsRequest)(nil), "walletrpc.ListLeasedOutputsRequest")
+	proto.RegisterType((*ListLeasedOutputsResponse)(nil), "walletrpc.ListLeasedOutputsResponse")
+	proto.RegisterType((*ListLeasedOutputsResponse_LeasedOutput)(nil), "walletrpc.ListLeasedOutputsResponse.LeasedOutput")
+	proto.RegisterType((*AddressInfoRequest)(nil), "walletrpc.AddressInfoRequest")
+	proto.RegisterType((*AddressInfoResponse)(nil), "walletrpc.AddressInfoResponse")
+	proto.RegisterType((*VerifyMessageRequest)(nil), "walletrpc.VerifyMessageRequest")
+	proto.RegisterType((*VerifyMessageResponse)(nil), "walletrpc.VerifyMessageResponse")
+	proto.RegisterType((*CreateSimpleTransactionRequest)(nil), "walletrpc.CreateSimpleTransactionRequest")
+	proto.RegisterType((*CreateSimpleTransactionRequest_Output)(nil), "walletrpc.CreateSimpleTransactionRequest.Output")
+	proto.RegisterType((*CreateSimpleTransactionResponse)(nil), "walletrpc.CreateSimpleTransactionResponse")
+	proto.RegisterType((*FundPsbtRequest)(nil), "walletrpc.FundPsbtRequest")
+	proto.RegisterType((*FundPsbtResponse)(nil), "walletrpc.FundPsbtResponse")
+	proto.RegisterType((*FinalizePsbtRequest)(nil), "walletrpc.FinalizePsbtRequest")
+	proto.RegisterType((*FinalizePsbtResponse)(nil), "walletrpc.FinalizePsbtResponse")
+	proto.RegisterType((*LeaseOutputRequest)(nil), "walletrpc.LeaseOutputRequest")
+	proto.RegisterType((*LeaseOutputResponse)(nil), "walletrpc.LeaseOutputResponse")
+	proto.RegisterType((*ReleaseOutputRequest)(nil), "walletrpc.ReleaseOutputRequest")
+	proto.RegisterType((*ReleaseOutputResponse)(nil), "walletrpc.ReleaseOutputResponse")
+	proto.RegisterType((*ImportAccountRequest)(nil), "walletrpc.ImportAccountRequest")
+	proto.RegisterType((*ImportAccountResponse)(nil), "walletrpc.ImportAccountResponse")
+	proto.RegisterType((*ImportPublicKeyRequest)(nil), "walletrpc.ImportPublicKeyRequest")
+	proto.RegisterType((*ImportPublicKeyResponse)(nil), "walletrpc.ImportPublicKeyResponse")
+	proto.RegisterType((*ImportTaprootScriptRequest)(nil), "walletrpc.ImportTaprootScriptRequest")
+	proto.RegisterType((*ImportTaprootScriptRequest_TapLeaf)(nil), "walletrpc.ImportTaprootScriptRequest.TapLeaf")
+	proto.RegisterType((*ImportTaprootScriptResponse)(nil), "walletrpc.ImportTaprootScriptResponse")
+	proto.RegisterType((*ImportLegacyKeystoreRequest)(nil), "walletrpc.ImportLegacyKeystoreRequest")
+	proto.RegisterType((*ImportLegacyKeystoreResponse)(nil), "walletrpc.ImportLegacyKeystoreResponse")
+	proto.RegisterType((*ImportLegacyKeystoreResponse_Address)(nil), "walletrpc.ImportLegacyKeystoreResponse.Address")
+	proto.RegisterType((*LabelTransactionRequest)(nil), "walletrpc.LabelTransactionRequest")
+	proto.RegisterType((*LabelTransactionResponse)(nil), "walletrpc.LabelTransactionResponse")
+	proto.RegisterType((*RescanRequest)(nil), "walletrpc.RescanRequest")
+	proto.RegisterType((*RescanResponse)(nil), "walletrpc.RescanResponse")
+	proto.RegisterType((*RecoveryProgressRequest)(nil), "walletrpc.RecoveryProgressRequest")
+	proto.RegisterType((*RecoveryProgressResponse)(nil), "walletrpc.RecoveryProgressResponse")
+	proto.RegisterType((*PassphraseKdfRequest)(nil), "walletrpc.PassphraseKdfRequest")
+	proto.RegisterType((*PassphraseKdfResponse)(nil), "walletrpc.PassphraseKdfResponse")
+	proto.RegisterType((*SignMessageRequest)(nil), "walletrpc.SignMessageRequest")
+	proto.RegisterType((*SignMessageResponse)(nil), "walletrpc.SignMessageResponse")
+	proto.RegisterType((*BakeMacaroonRequest)(nil), "walletrpc.BakeMacaroonRequest")
+	proto.RegisterType((*BakeMacaroonResponse)(nil), "walletrpc.BakeMacaroonResponse")
+	proto.RegisterType((*RevokeMacaroonRootKeyRequest)(nil), "walletrpc.RevokeMacaroonRootKeyRequest")
+	proto.RegisterType((*RevokeMacaroonRootKeyResponse)(nil), "walletrpc.RevokeMacaroonRootKeyResponse")
+	proto.RegisterType((*ListMacaroonRootKeysRequest)(nil), "walletrpc.ListMacaroonRootKeysRequest")
+	proto.RegisterType((*ListMacaroonRootKeysResponse)(nil), "walletrpc.ListMacaroonRootKeysResponse")
+	proto.RegisterType((*TransactionNotificationsRequest)(nil), "walletrpc.TransactionNotificationsRequest")
+	proto.RegisterType((*TransactionNotificationsResponse)(nil), "walletrpc.TransactionNotificationsResponse")
+	proto.RegisterType((*TransactionNotificationsResponse_ReplacedTransaction)(nil), "walletrpc.TransactionNotificationsResponse.ReplacedTransaction")
+	proto.RegisterType((*SpentnessNotificationsRequest)(nil), "walletrpc.SpentnessNotificationsRequest")
+	proto.RegisterType((*SpentnessNotificationsResponse)(nil), "walletrpc.SpentnessNotificationsResponse")
+	proto.RegisterType((*SpentnessNotificationsResponse_Spender)(nil), "walletrpc.SpentnessNotificationsResponse.Spender")
+	proto.RegisterType((*AccountNotificationsRequest)(nil), "walletrpc.AccountNotificationsRequest")
+	proto.RegisterType((*AccountNotificationsResponse)(nil), "walletrpc.AccountNotificationsResponse")
+	proto.RegisterType((*CreateWalletRequest)(nil), "walletrpc.CreateWalletRequest")
+	proto.RegisterType((*CreateWalletResponse)(nil), "walletrpc.CreateWalletResponse")
+	proto.RegisterType((*OpenWalletRequest)(nil), "walletrpc.OpenWalletRequest")
+	proto.RegisterType((*OpenWalletResponse)(nil), "walletrpc.OpenWalletResponse")
+	proto.RegisterType((*CloseWalletRequest)(nil), "walletrpc.CloseWalletRequest")
+	proto.RegisterType((*CloseWalletResponse)(nil), "walletrpc.CloseWalletResponse")
+	proto.RegisterType((*WalletExistsRequest)(nil), "walletrpc.WalletExistsRequest")
+	proto.RegisterType((*WalletExistsResponse)(nil), "walletrpc.WalletExistsResponse")
+	proto.RegisterType((*RollbackTransactionHistoryRequest)(nil), "walletrpc.RollbackTransactionHistoryRequest")
+	proto.RegisterType((*RollbackTransactionHistoryResponse)(nil), "walletrpc.RollbackTransactionHistoryResponse")
+	proto.RegisterType((*StartConsensusRpcRequest)(nil), "walletrpc.StartConsensusRpcRequest")
+	proto.RegisterType((*StartConsensusRpcResponse)(nil), "walletrpc.StartConsensusRpcResponse")
+	proto.RegisterEnum("walletrpc.AddressType", AddressType_name, AddressType_value)
+	proto.RegisterEnum("walletrpc.CoinSelectionStrategy", CoinSelectionStrategy_name, CoinSelectionStrategy_value)
+	proto.RegisterEnum("walletrpc.NextAddressRequest_Kind", NextAddressRequest_Kind_name, NextAddressRequest_Kind_value)
+	proto.RegisterEnum("walletrpc.ChangePassphraseRequest_Key", ChangePassphraseRequest_Key_name, ChangePassphraseRequest_Key_value)
+	proto.RegisterEnum("walletrpc.KdfParameters_Algorithm", KdfParameters_Algorithm_name, KdfParameters_Algorithm_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for VersionService service
+
+type VersionServiceClient interface {
+	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+}
+
+type versionServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewVersionServiceClient(cc *grpc.ClientConn) VersionServiceClient {
+	return &versionServiceClient{cc}
+}
+
+func (c *versionServiceClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.VersionService/Version", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for VersionService service
+
+type VersionServiceServer interface {
+	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+}
+
+func RegisterVersionServiceServer(s *grpc.Server, srv VersionServiceServer) {
+	s.RegisterService(&_VersionService_serviceDesc, srv)
+}
+
+func _VersionService_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VersionServiceServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.VersionService/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VersionServiceServer).Version(ctx, req.(*VersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _VersionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "walletrpc.VersionService",
+	HandlerType: (*VersionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Version",
+			Handler:    _VersionService_Version_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
+// Client API for WalletService service
+
+type WalletServiceClient interface {
+	// Queries
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Network(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkResponse, error)
+	AccountNumber(ctx context.Context, in *AccountNumberRequest, opts ...grpc.CallOption) (*AccountNumberResponse, error)
+	Accounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error)
+	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
+	ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error)
+	ListLeasedOutputs(ctx context.Context, in *ListLeasedOutputsRequest, opts ...grpc.CallOption) (*ListLeasedOutputsResponse, error)
+	AddressInfo(ctx context.Context, in *AddressInfoRequest, opts ...grpc.CallOption) (*AddressInfoResponse, error)
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
+	// Notifications
+	TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error)
+	SpentnessNotifications(ctx context.Context, in *SpentnessNotificationsRequest, opts ...grpc.CallOption) (WalletService_SpentnessNotificationsClient, error)
+	AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error)
+	// Control
+	ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error)
+	RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error)
+	NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error)
+	NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error)
+	ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error)
+	FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error)
+	SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error)
+	PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error)
+	CreateSimpleTransaction(ctx context.Context, in *CreateSimpleTransactionRequest, opts ...grpc.CallOption) (*CreateSimpleTransactionResponse, error)
+	FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error)
+	FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error)
+	LeaseOutput(ctx context.Context, in *LeaseOutputRequest, opts ...grpc.CallOption) (*LeaseOutputResponse, error)
+	ReleaseOutput(ctx context.Context, in *ReleaseOutputRequest, opts ...grpc.CallOption) (*ReleaseOutputResponse, error)
+	ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error)
+	ImportPublicKey(ctx context.Context, in *ImportPublicKeyRequest, opts ...grpc.CallOption) (*ImportPublicKeyResponse, error)
+	ImportTaprootScript(ctx context.Context, in *ImportTaprootScriptRequest, opts ...grpc.CallOption) (*ImportTaprootScriptResponse, error)
+	ImportLegacyKeystore(ctx context.Context, in *ImportLegacyKeystoreRequest, opts ...grpc.CallOption) (*ImportLegacyKeystoreResponse, error)
+	LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error)
+	Rescan(ctx context.Context, in *RescanRequest, opts ...grpc.CallOption) (*RescanResponse, error)
+	RecoveryProgress(ctx context.Context, in *RecoveryProgressRequest, opts ...grpc.CallOption) (*RecoveryProgressResponse, error)
+	PassphraseKdf(ctx context.Context, in *PassphraseKdfRequest, opts ...grpc.CallOption) (*PassphraseKdfResponse, error)
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	// Macaroons
+	BakeMacaroon(ctx context.Context, in *BakeMacaroonRequest, opts ...grpc.CallOption) (*BakeMacaroonResponse, error)
+	RevokeMacaroonRootKey(ctx context.Context, in *RevokeMacaroonRootKeyRequest, opts ...grpc.CallOption) (*RevokeMacaroonRootKeyResponse, error)
+	ListMacaroonRootKeys(ctx context.Context, in *ListMacaroonRootKeysRequest, opts ...grpc.CallOption) (*ListMacaroonRootKeysResponse, error)
+}
+
+type walletServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWalletServiceClient(cc *grpc.ClientConn) WalletServiceClient {
+	return &walletServiceClient{cc}
+}
+
+func (c *walletServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Ping", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Network(ctx context.Context, in *NetworkRequest, opts ...grpc.CallOption) (*NetworkResponse, error) {
+	out := new(NetworkResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Network", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) AccountNumber(ctx context.Context, in *AccountNumberRequest, opts ...grpc.CallOption) (*AccountNumberResponse, error) {
+	out := new(AccountNumberResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/AccountNumber", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Accounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error) {
+	out := new(AccountsResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Accounts", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	out := new(BalanceResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Balance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error) {
+	out := new(GetTransactionsResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/GetTransactions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error) {
+	out := new(GetTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/GetTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error) {
+	out := new(ListUnspentResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ListUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListLeasedOutputs(ctx context.Context, in *ListLeasedOutputsRequest, opts ...grpc.CallOption) (*ListLeasedOutputsResponse, error) {
+	out := new(ListLeasedOutputsResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ListLeasedOutputs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) AddressInfo(ctx context.Context, in *AddressInfoRequest, opts ...grpc.CallOption) (*AddressInfoResponse, error) {
+	out := new(AddressInfoResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/AddressInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/VerifyMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[0], c.cc, "/walletrpc.WalletService/TransactionNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceTransactionNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_TransactionNotificationsClient interface {
+	Recv() (*TransactionNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceTransactionNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceTransactionNotificationsClient) Recv() (*TransactionNotificationsResponse, error) {
+	m := new(TransactionNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *walletServiceClient) SpentnessNotifications(ctx context.Context, in *SpentnessNotificationsRequest, opts ...grpc.CallOption) (WalletService_SpentnessNotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[1], c.cc, "/walletrpc.WalletService/SpentnessNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceSpentnessNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_SpentnessNotificationsClient interface {
+	Recv() (*SpentnessNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceSpentnessNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceSpentnessNotificationsClient) Recv() (*SpentnessNotificationsResponse, error) {
+	m := new(SpentnessNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *walletServiceClient) AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[2], c.cc, "/walletrpc.WalletService/AccountNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceAccountNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_AccountNotificationsClient interface {
+	Recv() (*AccountNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceAccountNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceAccountNotificationsClient) Recv() (*AccountNotificationsResponse, error) {
+	m := new(AccountNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *walletServiceClient) ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error) {
+	out := new(ChangePassphraseResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ChangePassphrase", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error) {
+	out := new(RenameAccountResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/RenameAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error) {
+	out := new(NextAccountResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error) {
+	out := new(NextAddressResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error) {
+	out := new(ImportPrivateKeyResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportPrivateKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error) {
+	out := new(FundTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FundTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error) {
+	out := new(SignTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error) {
+	out := new(PublishTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/PublishTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) CreateSimpleTransaction(ctx context.Context, in *CreateSimpleTransactionRequest, opts ...grpc.CallOption) (*CreateSimpleTransactionResponse, error) {
+	out := new(CreateSimpleTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/CreateSimpleTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error) {
+	out := new(FundPsbtResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FundPsbt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error) {
+	out := new(FinalizePsbtResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FinalizePsbt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) LeaseOutput(ctx context.Context, in *LeaseOutputRequest, opts ...grpc.CallOption) (*LeaseOutputResponse, error) {
+	out := new(LeaseOutputResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/LeaseOutput", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ReleaseOutput(ctx context.Context, in *ReleaseOutputRequest, opts ...grpc.CallOption) (*ReleaseOutputResponse, error) {
+	out := new(ReleaseOutputResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ReleaseOutput", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error) {
+	out := new(ImportAccountResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportPublicKey(ctx context.Context, in *ImportPublicKeyRequest, opts ...grpc.CallOption) (*ImportPublicKeyResponse, error) {
+	out := new(ImportPublicKeyResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportPublicKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportTaprootScript(ctx context.Context, in *ImportTaprootScriptRequest, opts ...grpc.CallOption) (*ImportTaprootScriptResponse, error) {
+	out := new(ImportTaprootScriptResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportTaprootScript", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportLegacyKeystore(ctx context.Context, in *ImportLegacyKeystoreRequest, opts ...grpc.CallOption) (*ImportLegacyKeystoreResponse, error) {
+	out := new(ImportLegacyKeystoreResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportLegacyKeystore", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error) {
+	out := new(LabelTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/LabelTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Rescan(ctx context.Context, in *RescanRequest, opts ...grpc.CallOption) (*RescanResponse, error) {
+	out := new(RescanResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/Rescan", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) RecoveryProgress(ctx context.Context, in *RecoveryProgressRequest, opts ...grpc.CallOption) (*RecoveryProgressResponse, error) {
+	out := new(RecoveryProgressResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/RecoveryProgress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) PassphraseKdf(ctx context.Context, in *PassphraseKdfRequest, opts ...grpc.CallOption) (*PassphraseKdfResponse, error) {
+	out := new(PassphraseKdfResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/PassphraseKdf", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) BakeMacaroon(ctx context.Context, in *BakeMacaroonRequest, opts ...grpc.CallOption) (*BakeMacaroonResponse, error) {
+	out := new(BakeMacaroonResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/BakeMacaroon", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) RevokeMacaroonRootKey(ctx context.Context, in *RevokeMacaroonRootKeyRequest, opts ...grpc.CallOption) (*RevokeMacaroonRootKeyResponse, error) {
+	out := new(RevokeMacaroonRootKeyResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/RevokeMacaroonRootKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListMacaroonRootKeys(ctx context.Context, in *ListMacaroonRootKeysRequest, opts ...grpc.CallOption) (*ListMacaroonRootKeysResponse, error) {
+	out := new(ListMacaroonRootKeysResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ListMacaroonRootKeys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for WalletService service
+
+type WalletServiceServer interface {
+	// Queries
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	Network(context.Context, *NetworkRequest) (*NetworkResponse, error)
+	AccountNumber(context.Context, *AccountNumberRequest) (*AccountNumberResponse, error)
+	Accounts(context.Context, *AccountsRequest) (*AccountsResponse, error)
+	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
+	GetTransactions(context.Context, *GetTransactionsRequest) (*GetTransactionsResponse, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error)
+	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
+	ListLeasedOutputs(context.Context, *ListLeasedOutputsRequest) (*ListLeasedOutputsResponse, error)
+	AddressInfo(context.Context, *AddressInfoRequest) (*AddressInfoResponse, error)
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
+	// Notifications
+	TransactionNotifications(*TransactionNotificationsRequest, WalletService_TransactionNotificationsServer) error
+	SpentnessNotifications(*SpentnessNotificationsRequest, WalletService_SpentnessNotificationsServer) error
+	AccountNotifications(*AccountNotificationsRequest, WalletService_AccountNotificationsServer) error
+	// Control
+	ChangePassphrase(context.Context, *ChangePassphraseRequest) (*ChangePassphraseResponse, error)
+	RenameAccount(context.Context, *RenameAccountRequest) (*RenameAccountResponse, error)
+	NextAccount(context.Context, *NextAccountRequest) (*NextAccountResponse, error)
+	NextAddress(context.Context, *NextAddressRequest) (*NextAddressResponse, error)
+	ImportPrivateKey(context.Context, *ImportPrivateKeyRequest) (*ImportPrivateKeyResponse, error)
+	FundTransaction(context.Context, *FundTransactionRequest) (*FundTransactionResponse, error)
+	SignTransaction(context.Context, *SignTransactionRequest) (*SignTransactionResponse, error)
+	PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error)
+	CreateSimpleTransaction(context.Context, *CreateSimpleTransactionRequest) (*CreateSimpleTransactionResponse, error)
+	FundPsbt(context.Context, *FundPsbtRequest) (*FundPsbtResponse, error)
+	FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	LeaseOutput(context.Context, *LeaseOutputRequest) (*LeaseOutputResponse, error)
+	ReleaseOutput(context.Context, *ReleaseOutputRequest) (*ReleaseOutputResponse, error)
+	ImportAccount(context.Context, *ImportAccountRequest) (*ImportAccountResponse, error)
+	ImportPublicKey(context.Context, *ImportPublicKeyRequest) (*ImportPublicKeyResponse, error)
+	ImportTaprootScript(context.Context, *ImportTaprootScriptRequest) (*ImportTaprootScriptResponse, error)
+	ImportLegacyKeystore(context.Context, *ImportLegacyKeystoreRequest) (*ImportLegacyKeystoreResponse, error)
+	LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error)
+	Rescan(context.Context, *RescanRequest) (*RescanResponse, error)
+	RecoveryProgress(context.Context, *RecoveryProgressRequest) (*RecoveryProgressResponse, error)
+	PassphraseKdf(context.Context, *PassphraseKdfRequest) (*PassphraseKdfResponse, error)
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
+	// Macaroons
+	BakeMacaroon(context.Context, *BakeMacaroonRequest) (*BakeMacaroonResponse, error)
+	RevokeMacaroonRootKey(context.Context, *RevokeMacaroonRootKeyRequest) (*RevokeMacaroonRootKeyResponse, error)
+	ListMacaroonRootKeys(context.Context, *ListMacaroonRootKeysRequest) (*ListMacaroonRootKeysResponse, error)
+}
+
+func RegisterWalletServiceServer(s *grpc.Server, srv WalletServiceServer) {
+	s.RegisterService(&_WalletService_serviceDesc, srv)
+}
+
+func _WalletService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Network_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Network(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/Network",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Network(ctx, req.(*NetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_AccountNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).AccountNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/AccountNumber",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).AccountNumber(ctx, req.(*AccountNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Accounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Accounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/Accounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Accounts(ctx, req.(*AccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Balance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/Balance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Balance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/GetTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-type WalletServiceServer interface {
-	// Queries
-	Ping(context.Context, *PingRequest) (*PingResponse, error)
-	Network(context.Context, *NetworkRequest) (*NetworkResponse, error)
-	AccountNumber(context.Context, *AccountNumberRequest) (*AccountNumberResponse, error)
-	Accounts(context.Context, *AccountsRequest) (*AccountsResponse, error)
-	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
-	GetTransactions(context.Context, *GetTransactionsRequest) (*GetTransactionsResponse, error)
-	// Notifications
-	TransactionNotifications(*TransactionNotificationsRequest, WalletService_TransactionNotificationsServer) error
-	SpentnessNotifications(*SpentnessNotificationsRequest, WalletService_SpentnessNotificationsServer) error
-	AccountNotifications(*AccountNotificationsRequest, WalletService_AccountNotificationsServer) error
-	// Control
-	ChangePassphrase(context.Context, *ChangePassphraseRequest) (*ChangePassphraseResponse, error)
-	RenameAccount(context.Context, *RenameAccountRequest) (*RenameAccountResponse, error)
-	NextAccount(context.Context, *NextAccountRequest) (*NextAccountResponse, error)
-	NextAddress(context.Context, *NextAddressRequest) (*NextAddressResponse, error)
-	ImportPrivateKey(context.Context, *ImportPrivateKeyRequest) (*ImportPrivateKeyResponse, error)
-	FundTransaction(context.Context, *FundTransactionRequest) (*FundTransactionResponse, error)
-	SignTransaction(context.Context, *SignTransactionRequest) (*SignTransactionResponse, error)
-	PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error)
+func _WalletService_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterWalletServiceServer(s *grpc.Server, srv WalletServiceServer) {
-	s.RegisterService(&_WalletService_serviceDesc, srv)
+func _WalletService_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnspentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListUnspent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ListUnspent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListUnspent(ctx, req.(*ListUnspentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ListLeasedOutputs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLeasedOutputsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListLeasedOutputs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ListLeasedOutputs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListLeasedOutputs(ctx, req.(*ListLeasedOutputsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_AddressInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).AddressInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/AddressInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).AddressInfo(ctx, req.(*AddressInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_TransactionNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TransactionNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).TransactionNotifications(m, &walletServiceTransactionNotificationsServer{stream})
+}
+
+type WalletService_TransactionNotificationsServer interface {
+	Send(*TransactionNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type walletServiceTransactionNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceTransactionNotificationsServer) Send(m *TransactionNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WalletService_SpentnessNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SpentnessNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).SpentnessNotifications(m, &walletServiceSpentnessNotificationsServer{stream})
+}
+
+type WalletService_SpentnessNotificationsServer interface {
+	Send(*SpentnessNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type walletServiceSpentnessNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceSpentnessNotificationsServer) Send(m *SpentnessNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WalletService_AccountNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AccountNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).AccountNotifications(m, &walletServiceAccountNotificationsServer{stream})
+}
+
+type WalletService_AccountNotificationsServer interface {
+	Send(*AccountNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type walletServiceAccountNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceAccountNotificationsServer) Send(m *AccountNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WalletService_ChangePassphrase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePassphraseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ChangePassphrase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ChangePassphrase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ChangePassphrase(ctx, req.(*ChangePassphraseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_RenameAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).RenameAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/RenameAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).RenameAccount(ctx, req.(*RenameAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_NextAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).NextAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/NextAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).NextAccount(ctx, req.(*NextAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_NextAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).NextAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/NextAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).NextAddress(ctx, req.(*NextAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ImportPrivateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportPrivateKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportPrivateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ImportPrivateKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportPrivateKey(ctx, req.(*ImportPrivateKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_FundTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).FundTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/FundTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).FundTransaction(ctx, req.(*FundTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PingRequest)
+func _WalletService_SignTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignTransactionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).Ping(ctx, in)
+		return srv.(WalletServiceServer).SignTransaction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/Ping",
+		FullMethod: "/walletrpc.WalletService/SignTransaction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).Ping(ctx, req.(*PingRequest))
+		return srv.(WalletServiceServer).SignTransaction(ctx, req.(*SignTransactionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_Network_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NetworkRequest)
+func _WalletService_PublishTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishTransactionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).Network(ctx, in)
+		return srv.(WalletServiceServer).PublishTransaction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/Network",
+		FullMethod: "/walletrpc.WalletService/PublishTransaction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).Network(ctx, req.(*NetworkRequest))
+		return srv.(WalletServiceServer).PublishTransaction(ctx, req.(*PublishTransactionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_AccountNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AccountNumberRequest)
+func _WalletService_CreateSimpleTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSimpleTransactionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).AccountNumber(ctx, in)
+		return srv.(WalletServiceServer).CreateSimpleTransaction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/AccountNumber",
+		FullMethod: "/walletrpc.WalletService/CreateSimpleTransaction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).AccountNumber(ctx, req.(*AccountNumberRequest))
+		return srv.(WalletServiceServer).CreateSimpleTransaction(ctx, req.(*CreateSimpleTransactionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_Accounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AccountsRequest)
+func _WalletService_FundPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundPsbtRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).Accounts(ctx, in)
+		return srv.(WalletServiceServer).FundPsbt(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/Accounts",
+		FullMethod: "/walletrpc.WalletService/FundPsbt",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).Accounts(ctx, req.(*AccountsRequest))
+		return srv.(WalletServiceServer).FundPsbt(ctx, req.(*FundPsbtRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BalanceRequest)
+func _WalletService_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizePsbtRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).Balance(ctx, in)
+		return srv.(WalletServiceServer).FinalizePsbt(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/Balance",
+		FullMethod: "/walletrpc.WalletService/FinalizePsbt",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).Balance(ctx, req.(*BalanceRequest))
+		return srv.(WalletServiceServer).FinalizePsbt(ctx, req.(*FinalizePsbtRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTransactionsRequest)
+func _WalletService_LeaseOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseOutputRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).GetTransactions(ctx, in)
+		return srv.(WalletServiceServer).LeaseOutput(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/GetTransactions",
+		FullMethod: "/walletrpc.WalletService/LeaseOutput",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+		return srv.(WalletServiceServer).LeaseOutput(ctx, req.(*LeaseOutputRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_TransactionNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(TransactionNotificationsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _WalletService_ReleaseOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseOutputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(WalletServiceServer).TransactionNotifications(m, &walletServiceTransactionNotificationsServer{stream})
-}
-
-type WalletService_TransactionNotificationsServer interface {
-	Send(*TransactionNotificationsResponse) error
-	grpc.ServerStream
-}
-
-type walletServiceTransactionNotificationsServer struct {
-	grpc.ServerStream
-}
-
-func (x *walletServiceTransactionNotificationsServer) Send(m *TransactionNotificationsResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _WalletService_SpentnessNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SpentnessNotificationsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ReleaseOutput(ctx, in)
 	}
-	return srv.(WalletServiceServer).SpentnessNotifications(m, &walletServiceSpentnessNotificationsServer{stream})
-}
-
-type WalletService_SpentnessNotificationsServer interface {
-	Send(*SpentnessNotificationsResponse) error
-	grpc.ServerStream
-}
-
-type walletServiceSpentnessNotificationsServer struct {
-	grpc.ServerStream
-}
-
-func (x *walletServiceSpentnessNotificationsServer) Send(m *SpentnessNotificationsResponse) error {
-	return x.ServerStream.SendMsg(m)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ReleaseOutput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ReleaseOutput(ctx, req.(*ReleaseOutputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_AccountNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(AccountNotificationsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _WalletService_ImportAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(WalletServiceServer).AccountNotifications(m, &walletServiceAccountNotificationsServer{stream})
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ImportAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportAccount(ctx, req.(*ImportAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type WalletService_AccountNotificationsServer interface {
-	Send(*AccountNotificationsResponse) error
-	grpc.ServerStream
+func _WalletService_ImportPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ImportPublicKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportPublicKey(ctx, req.(*ImportPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type walletServiceAccountNotificationsServer struct {
-	grpc.ServerStream
+func _WalletService_ImportTaprootScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTaprootScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportTaprootScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ImportTaprootScript",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportTaprootScript(ctx, req.(*ImportTaprootScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *walletServiceAccountNotificationsServer) Send(m *AccountNotificationsResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _WalletService_ImportLegacyKeystore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportLegacyKeystoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportLegacyKeystore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ImportLegacyKeystore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportLegacyKeystore(ctx, req.(*ImportLegacyKeystoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_ChangePassphrase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChangePassphraseRequest)
+func _WalletService_LabelTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelTransactionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).ChangePassphrase(ctx, in)
+		return srv.(WalletServiceServer).LabelTransaction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/ChangePassphrase",
+		FullMethod: "/walletrpc.WalletService/LabelTransaction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).ChangePassphrase(ctx, req.(*ChangePassphraseRequest))
+		return srv.(WalletServiceServer).LabelTransaction(ctx, req.(*LabelTransactionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_RenameAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RenameAccountRequest)
+func _WalletService_Rescan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescanRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).RenameAccount(ctx, in)
+		return srv.(WalletServiceServer).Rescan(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/RenameAccount",
+		FullMethod: "/walletrpc.WalletService/Rescan",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).RenameAccount(ctx, req.(*RenameAccountRequest))
+		return srv.(WalletServiceServer).Rescan(ctx, req.(*RescanRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_NextAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NextAccountRequest)
+func _WalletService_RecoveryProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoveryProgressRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).NextAccount(ctx, in)
+		return srv.(WalletServiceServer).RecoveryProgress(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/NextAccount",
+		FullMethod: "/walletrpc.WalletService/RecoveryProgress",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).NextAccount(ctx, req.(*NextAccountRequest))
+		return srv.(WalletServiceServer).RecoveryProgress(ctx, req.(*RecoveryProgressRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_NextAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NextAddressRequest)
+func _WalletService_PassphraseKdf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PassphraseKdfRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).NextAddress(ctx, in)
+		return srv.(WalletServiceServer).PassphraseKdf(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/NextAddress",
+		FullMethod: "/walletrpc.WalletService/PassphraseKdf",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).NextAddress(ctx, req.(*NextAddressRequest))
+		return srv.(WalletServiceServer).PassphraseKdf(ctx, req.(*PassphraseKdfRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_ImportPrivateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ImportPrivateKeyRequest)
+func _WalletService_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).ImportPrivateKey(ctx, in)
+		return srv.(WalletServiceServer).SignMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/ImportPrivateKey",
+		FullMethod: "/walletrpc.WalletService/SignMessage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).ImportPrivateKey(ctx, req.(*ImportPrivateKeyRequest))
+		return srv.(WalletServiceServer).SignMessage(ctx, req.(*SignMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_FundTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FundTransactionRequest)
+func _WalletService_BakeMacaroon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BakeMacaroonRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).FundTransaction(ctx, in)
+		return srv.(WalletServiceServer).BakeMacaroon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/FundTransaction",
+		FullMethod: "/walletrpc.WalletService/BakeMacaroon",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).FundTransaction(ctx, req.(*FundTransactionRequest))
+		return srv.(WalletServiceServer).BakeMacaroon(ctx, req.(*BakeMacaroonRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_SignTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignTransactionRequest)
+func _WalletService_RevokeMacaroonRootKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeMacaroonRootKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).SignTransaction(ctx, in)
+		return srv.(WalletServiceServer).RevokeMacaroonRootKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/SignTransaction",
+		FullMethod: "/walletrpc.WalletService/RevokeMacaroonRootKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).SignTransaction(ctx, req.(*SignTransactionRequest))
+		return srv.(WalletServiceServer).RevokeMacaroonRootKey(ctx, req.(*RevokeMacaroonRootKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WalletService_PublishTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PublishTransactionRequest)
+func _WalletService_ListMacaroonRootKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMacaroonRootKeysRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WalletServiceServer).PublishTransaction(ctx, in)
+		return srv.(WalletServiceServer).ListMacaroonRootKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/walletrpc.WalletService/PublishTransaction",
+		FullMethod: "/walletrpc.WalletService/ListMacaroonRootKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletServiceServer).PublishTransaction(ctx, req.(*PublishTransactionRequest))
+		return srv.(WalletServiceServer).ListMacaroonRootKeys(ctx, req.(*ListMacaroonRootKeysRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -2235,6 +4851,26 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetTransactions",
 			Handler:    _WalletService_GetTransactions_Handler,
 		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _WalletService_GetTransaction_Handler,
+		},
+		{
+			MethodName: "ListUnspent",
+			Handler:    _WalletService_ListUnspent_Handler,
+		},
+		{
+			MethodName: "ListLeasedOutputs",
+			Handler:    _WalletService_ListLeasedOutputs_Handler,
+		},
+		{
+			MethodName: "AddressInfo",
+			Handler:    _WalletService_AddressInfo_Handler,
+		},
+		{
+			MethodName: "VerifyMessage",
+			Handler:    _WalletService_VerifyMessage_Handler,
+		},
 		{
 			MethodName: "ChangePassphrase",
 			Handler:    _WalletService_ChangePassphrase_Handler,
@@ -2267,6 +4903,74 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PublishTransaction",
 			Handler:    _WalletService_PublishTransaction_Handler,
 		},
+		{
+			MethodName: "CreateSimpleTransaction",
+			Handler:    _WalletService_CreateSimpleTransaction_Handler,
+		},
+		{
+			MethodName: "FundPsbt",
+			Handler:    _WalletService_FundPsbt_Handler,
+		},
+		{
+			MethodName: "FinalizePsbt",
+			Handler:    _WalletService_FinalizePsbt_Handler,
+		},
+		{
+			MethodName: "LeaseOutput",
+			Handler:    _WalletService_LeaseOutput_Handler,
+		},
+		{
+			MethodName: "ReleaseOutput",
+			Handler:    _WalletService_ReleaseOutput_Handler,
+		},
+		{
+			MethodName: "ImportAccount",
+			Handler:    _WalletService_ImportAccount_Handler,
+		},
+		{
+			MethodName: "ImportPublicKey",
+			Handler:    _WalletService_ImportPublicKey_Handler,
+		},
+		{
+			MethodName: "ImportTaprootScript",
+			Handler:    _WalletService_ImportTaprootScript_Handler,
+		},
+		{
+			MethodName: "ImportLegacyKeystore",
+			Handler:    _WalletService_ImportLegacyKeystore_Handler,
+		},
+		{
+			MethodName: "LabelTransaction",
+			Handler:    _WalletService_LabelTransaction_Handler,
+		},
+		{
+			MethodName: "Rescan",
+			Handler:    _WalletService_Rescan_Handler,
+		},
+		{
+			MethodName: "RecoveryProgress",
+			Handler:    _WalletService_RecoveryProgress_Handler,
+		},
+		{
+			MethodName: "PassphraseKdf",
+			Handler:    _WalletService_PassphraseKdf_Handler,
+		},
+		{
+			MethodName: "SignMessage",
+			Handler:    _WalletService_SignMessage_Handler,
+		},
+		{
+			MethodName: "BakeMacaroon",
+			Handler:    _WalletService_BakeMacaroon_Handler,
+		},
+		{
+			MethodName: "RevokeMacaroonRootKey",
+			Handler:    _WalletService_RevokeMacaroonRootKey_Handler,
+		},
+		{
+			MethodName: "ListMacaroonRootKeys",
+			Handler:    _WalletService_ListMacaroonRootKeys_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2296,6 +5000,7 @@ type WalletLoaderServiceClient interface {
 	OpenWallet(ctx context.Context, in *OpenWalletRequest, opts ...grpc.CallOption) (*OpenWalletResponse, error)
 	CloseWallet(ctx context.Context, in *CloseWalletRequest, opts ...grpc.CallOption) (*CloseWalletResponse, error)
 	StartConsensusRpc(ctx context.Context, in *StartConsensusRpcRequest, opts ...grpc.CallOption) (*StartConsensusRpcResponse, error)
+	RollbackTransactionHistory(ctx context.Context, in *RollbackTransactionHistoryRequest, opts ...grpc.CallOption) (*RollbackTransactionHistoryResponse, error)
 }
 
 type walletLoaderServiceClient struct {
@@ -2351,6 +5056,15 @@ func (c *walletLoaderServiceClient) StartConsensusRpc(ctx context.Context, in *S
 	return out, nil
 }
 
+func (c *walletLoaderServiceClient) RollbackTransactionHistory(ctx context.Context, in *RollbackTransactionHistoryRequest, opts ...grpc.CallOption) (*RollbackTransactionHistoryResponse, error) {
+	out := new(RollbackTransactionHistoryResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletLoaderService/RollbackTransactionHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for WalletLoaderService service
 
 type WalletLoaderServiceServer interface {
@@ -2359,6 +5073,7 @@ type WalletLoaderServiceServer interface {
 	OpenWallet(context.Context, *OpenWalletRequest) (*OpenWalletResponse, error)
 	CloseWallet(context.Context, *CloseWalletRequest) (*CloseWalletResponse, error)
 	StartConsensusRpc(context.Context, *StartConsensusRpcRequest) (*StartConsensusRpcResponse, error)
+	RollbackTransactionHistory(context.Context, *RollbackTransactionHistoryRequest) (*RollbackTransactionHistoryResponse, error)
 }
 
 func RegisterWalletLoaderServiceServer(s *grpc.Server, srv WalletLoaderServiceServer) {
@@ -2455,6 +5170,24 @@ func _WalletLoaderService_StartConsensusRpc_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletLoaderService_RollbackTransactionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackTransactionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletLoaderServiceServer).RollbackTransactionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletLoaderService/RollbackTransactionHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletLoaderServiceServer).RollbackTransactionHistory(ctx, req.(*RollbackTransactionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "walletrpc.WalletLoaderService",
 	HandlerType: (*WalletLoaderServiceServer)(nil),
@@ -2479,6 +5212,10 @@ var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StartConsensusRpc",
 			Handler:    _WalletLoaderService_StartConsensusRpc_Handler,
 		},
+		{
+			MethodName: "RollbackTransactionHistory",
+			Handler:    _WalletLoaderService_RollbackTransactionHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
@@ -2487,155 +5224,317 @@ var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2391 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x59, 0x5b, 0x73, 0xdc, 0x48,
-	0x15, 0x66, 0x2c, 0xdf, 0x72, 0xe6, 0xde, 0xbe, 0x4d, 0x94, 0x38, 0xc9, 0x2a, 0xbb, 0xd9, 0xec,
-	0x2e, 0x98, 0x60, 0x02, 0x2c, 0xc5, 0x56, 0xd8, 0xc4, 0x64, 0xd9, 0x21, 0xc1, 0x99, 0x92, 0x93,
-	0x4d, 0xaa, 0xa0, 0x98, 0x92, 0x35, 0xed, 0x58, 0x78, 0x46, 0x9a, 0x48, 0x9a, 0x38, 0xe1, 0x89,
-	0xa2, 0x8a, 0x47, 0x5e, 0x80, 0x07, 0x0a, 0x6a, 0x5f, 0xf8, 0x05, 0x54, 0xf1, 0xc2, 0x23, 0xfc,
-	0x06, 0x1e, 0xf9, 0x17, 0xfc, 0x02, 0xfa, 0x72, 0x7a, 0xd4, 0x2d, 0x69, 0xc6, 0xf6, 0x16, 0x6f,
-	0xa3, 0xaf, 0xcf, 0x39, 0x7d, 0xfa, 0xf4, 0xb9, 0xf6, 0xc0, 0x25, 0x6f, 0x1c, 0xec, 0x8c, 0xe3,
-	0x28, 0x8d, 0xc8, 0xa5, 0x53, 0x6f, 0x38, 0xa4, 0x69, 0x3c, 0xf6, 0x9d, 0x16, 0x34, 0xbe, 0xa0,
-	0x71, 0x12, 0x44, 0xa1, 0x4b, 0x5f, 0x4d, 0x68, 0x92, 0x3a, 0xff, 0xaa, 0x40, 0x73, 0x0a, 0x25,
-	0xe3, 0x28, 0x4c, 0x28, 0x79, 0x0f, 0x1a, 0xaf, 0x25, 0xd4, 0x4f, 0xd2, 0x38, 0x08, 0x5f, 0x76,
-	0x2a, 0x37, 0x2a, 0xb7, 0x2f, 0xb9, 0x75, 0x44, 0x0f, 0x04, 0x48, 0xd6, 0x61, 0x69, 0xe4, 0xfd,
-	0x32, 0x8a, 0x3b, 0x0b, 0x6c, 0xb5, 0xee, 0xca, 0x0f, 0x81, 0x06, 0x21, 0x43, 0x2d, 0x44, 0xf9,
-	0x07, 0x47, 0xc7, 0x5e, 0xea, 0x1f, 0x77, 0x16, 0x25, 0x2a, 0x3e, 0xc8, 0x35, 0x80, 0x71, 0x4c,
-	0x63, 0x3a, 0xa4, 0x5e, 0x42, 0x3b, 0x4b, 0x62, 0x13, 0x0d, 0xe1, 0x8a, 0x1c, 0x4e, 0x82, 0xe1,
-	0xa0, 0x3f, 0xa2, 0xa9, 0x37, 0xf0, 0x52, 0xaf, 0xb3, 0x2c, 0x15, 0x11, 0xe8, 0x4f, 0x11, 0x74,
-	0xfe, 0x69, 0x01, 0x79, 0x1a, 0x7b, 0x61, 0xe2, 0xf9, 0x29, 0x53, 0xef, 0x47, 0x0c, 0x0f, 0x86,
-	0x09, 0x21, 0xb0, 0x78, 0xec, 0x25, 0xc7, 0x42, 0xf9, 0x9a, 0x2b, 0x7e, 0x93, 0x1b, 0x50, 0x4d,
-	0x33, 0x4a, 0xa1, 0x79, 0xcd, 0xd5, 0x21, 0xf2, 0x03, 0x58, 0x1e, 0xd0, 0xc3, 0x20, 0x4d, 0xd8,
-	0x01, 0xac, 0xdb, 0xd5, 0xdd, 0x9b, 0x3b, 0x53, 0xf3, 0xed, 0x14, 0x37, 0xd9, 0xe9, 0x86, 0xe3,
-	0x49, 0xea, 0x22, 0x0b, 0xb9, 0x07, 0x2b, 0x7e, 0x4c, 0x07, 0x9c, 0x7b, 0x51, 0x70, 0xbf, 0x3b,
-	0x9f, 0xfb, 0xc9, 0x24, 0xe5, 0xec, 0x8a, 0x89, 0xb4, 0xc0, 0x3a, 0xa2, 0xd2, 0x12, 0x96, 0xcb,
-	0x7f, 0x92, 0xab, 0x70, 0x29, 0x0d, 0x46, 0xec, 0xa6, 0xbc, 0xd1, 0x58, 0x9c, 0xde, 0x72, 0x33,
-	0xc0, 0x7e, 0x05, 0x4b, 0x42, 0x01, 0x6e, 0xdf, 0x20, 0x1c, 0xd0, 0x37, 0xe2, 0xb0, 0xcc, 0xbe,
-	0xe2, 0x83, 0x7c, 0x00, 0x2d, 0x66, 0xcd, 0xd7, 0x41, 0x34, 0x49, 0xfa, 0x9e, 0xef, 0x47, 0x93,
-	0x30, 0xc5, 0xcb, 0x6a, 0x2a, 0xfc, 0xbe, 0x84, 0xc9, 0xfb, 0xd0, 0xcc, 0x48, 0x47, 0x82, 0xd2,
-	0x12, 0xbb, 0x35, 0xa6, 0x94, 0x02, 0xb5, 0x9f, 0xc2, 0xb2, 0xd4, 0x7a, 0xc6, 0x9e, 0x1d, 0x58,
-	0x31, 0xb7, 0x52, 0x9f, 0xc4, 0x86, 0xd5, 0x20, 0x4c, 0x69, 0x1c, 0x7a, 0x43, 0x21, 0x7b, 0xd5,
-	0x9d, 0x7e, 0x3b, 0x7f, 0xa9, 0x40, 0xed, 0xc1, 0x30, 0xf2, 0x4f, 0xe6, 0x5d, 0xde, 0x26, 0x2c,
-	0x1f, 0xd3, 0xe0, 0xe5, 0xb1, 0x94, 0xbc, 0xe4, 0xe2, 0x97, 0x69, 0x23, 0x2b, 0x67, 0x23, 0x72,
-	0x1f, 0x6a, 0xda, 0xfd, 0xaa, 0x8b, 0xd9, 0x9e, 0x7b, 0x31, 0xae, 0xc1, 0xe2, 0x3c, 0x81, 0x06,
-	0xda, 0xe9, 0x81, 0x37, 0xf4, 0x42, 0x9f, 0xea, 0xa7, 0xac, 0x98, 0xa7, 0xbc, 0x09, 0xf5, 0x34,
-	0x4a, 0xbd, 0x61, 0xff, 0x50, 0x92, 0x0a, 0x5d, 0x2d, 0x26, 0x90, 0x83, 0xc8, 0xee, 0xd4, 0xa1,
-	0xda, 0x63, 0x21, 0xa4, 0x82, 0xb0, 0x01, 0x35, 0xf9, 0x29, 0x03, 0x90, 0x87, 0xe9, 0x3e, 0x4d,
-	0x4f, 0xa3, 0xf8, 0x44, 0x51, 0x7c, 0x0c, 0xcd, 0x29, 0x92, 0x45, 0x29, 0xd7, 0xef, 0x35, 0xed,
-	0x87, 0x72, 0x05, 0x35, 0xa9, 0x4b, 0x14, 0xc9, 0x9d, 0xef, 0xc3, 0x3a, 0xea, 0xbe, 0x3f, 0x19,
-	0x1d, 0xd2, 0x18, 0x25, 0x92, 0x77, 0xa0, 0x86, 0x2a, 0xf7, 0x43, 0x6f, 0x44, 0x31, 0xc4, 0xab,
-	0x88, 0xed, 0x33, 0xc8, 0xb9, 0x07, 0x1b, 0x39, 0x56, 0x7d, 0x6b, 0xe4, 0x15, 0x2b, 0xd9, 0xd6,
-	0x1a, 0xb9, 0xd3, 0x86, 0x26, 0xf2, 0x27, 0xea, 0x1c, 0xff, 0xb0, 0xa0, 0x95, 0x61, 0x28, 0xee,
-	0x87, 0xb0, 0x8a, 0x8c, 0x09, 0x13, 0x94, 0x0f, 0xba, 0x3c, 0xb9, 0x02, 0xdc, 0x29, 0x13, 0xf9,
-	0x3a, 0x10, 0x7f, 0x12, 0xc7, 0x94, 0xe9, 0x73, 0xc8, 0x9d, 0xa8, 0x2f, 0x5c, 0x47, 0x06, 0x77,
-	0x0b, 0x57, 0x84, 0x77, 0x7d, 0xce, 0xdd, 0xe8, 0x0e, 0xac, 0xe7, 0xa8, 0xa5, 0x53, 0x59, 0xc2,
-	0xa9, 0x88, 0x41, 0x2f, 0x56, 0xec, 0xdf, 0x2c, 0xc0, 0x8a, 0x0a, 0x94, 0xf3, 0x9d, 0xbd, 0x60,
-	0xde, 0x85, 0x82, 0x79, 0x8b, 0x9e, 0x62, 0x15, 0x3d, 0x85, 0x1f, 0x8d, 0xbe, 0x91, 0x41, 0xd2,
-	0x3f, 0xa1, 0x6f, 0xfb, 0xd2, 0xe7, 0x64, 0x16, 0x6d, 0xa9, 0x95, 0x47, 0xf4, 0xed, 0x9e, 0x50,
-	0x8e, 0x51, 0xab, 0x90, 0xd2, 0xa8, 0x97, 0x24, 0xb5, 0x5a, 0x31, 0xa8, 0x47, 0xe3, 0x28, 0x4e,
-	0xe9, 0x40, 0xa3, 0x5e, 0x46, 0x6a, 0x5c, 0x51, 0xd4, 0xce, 0x0b, 0x58, 0x77, 0x29, 0x3f, 0x8b,
-	0xb2, 0x3f, 0x3a, 0xd2, 0x39, 0x0d, 0x72, 0x19, 0x56, 0x43, 0x7a, 0xaa, 0x1b, 0x63, 0x85, 0x7d,
-	0x0b, 0x3f, 0xdb, 0x82, 0x8d, 0x9c, 0x64, 0x8c, 0x83, 0xe7, 0x40, 0xf6, 0xd9, 0x19, 0x73, 0x1b,
-	0xf2, 0xaa, 0xe1, 0x25, 0xc9, 0xf8, 0x38, 0xe6, 0x55, 0x43, 0x26, 0x08, 0x0d, 0x39, 0x87, 0xe9,
-	0x9d, 0x4f, 0x60, 0xcd, 0x10, 0x7c, 0x31, 0xbf, 0xfe, 0x73, 0x05, 0xf5, 0x1a, 0x0c, 0x62, 0x9a,
-	0x28, 0xdf, 0x9e, 0x93, 0x13, 0xbe, 0x0b, 0x8b, 0x27, 0x2c, 0x3b, 0x0a, 0x4d, 0x1a, 0xbb, 0x8e,
-	0xe6, 0xdc, 0x45, 0x31, 0x3b, 0x8f, 0x18, 0xa5, 0x2b, 0xe8, 0x9d, 0x5d, 0x58, 0xe4, 0x5f, 0x2c,
-	0xd3, 0xb6, 0x1e, 0x74, 0x7b, 0x77, 0xee, 0xdc, 0xbd, 0xdb, 0x7f, 0xf8, 0xe2, 0xe9, 0x43, 0x77,
-	0xff, 0xfe, 0xe3, 0xd6, 0xd7, 0x74, 0xb4, 0xbb, 0x8f, 0x68, 0xc5, 0xf9, 0x26, 0x1e, 0x4d, 0x09,
-	0xc5, 0xa3, 0x71, 0xe5, 0x24, 0x84, 0x91, 0xae, 0x3e, 0x9d, 0x3f, 0x54, 0x60, 0xab, 0x2b, 0x2e,
-	0xbb, 0x17, 0x07, 0xaf, 0xbd, 0x94, 0xb2, 0x1b, 0x3f, 0xaf, 0xa9, 0x67, 0x27, 0xfb, 0x5b, 0xbc,
-	0x9e, 0x08, 0x71, 0xc2, 0xb5, 0x4e, 0x83, 0x23, 0xe1, 0xde, 0xac, 0x76, 0x8f, 0xa7, 0xbb, 0x3c,
-	0x0f, 0x8e, 0x78, 0x4e, 0x67, 0x5a, 0xf8, 0x5e, 0x28, 0x7c, 0x7a, 0xd5, 0xc5, 0x2f, 0xc7, 0x86,
-	0x4e, 0x51, 0x29, 0x74, 0x8b, 0x10, 0x1a, 0x18, 0x1e, 0x17, 0xf4, 0xc1, 0xef, 0xc0, 0x66, 0xcc,
-	0x38, 0x02, 0x56, 0x6d, 0x99, 0xb3, 0x87, 0x47, 0x41, 0x3c, 0xf2, 0x64, 0x51, 0x90, 0x05, 0x65,
-	0x43, 0xad, 0xee, 0xe9, 0x8b, 0x6c, 0xbf, 0xe6, 0x74, 0x3f, 0x34, 0x27, 0xab, 0x7d, 0x22, 0x4c,
-	0xc5, 0x3e, 0x96, 0x2b, 0x3f, 0x78, 0x21, 0x4a, 0xc6, 0x34, 0x1c, 0x78, 0x87, 0x43, 0x95, 0xf7,
-	0x33, 0x80, 0x97, 0xd8, 0x60, 0xc4, 0x64, 0x4e, 0x62, 0xda, 0x8f, 0xe9, 0xa9, 0x17, 0x0f, 0x54,
-	0x89, 0x55, 0xb0, 0x2b, 0x50, 0xe7, 0x4f, 0x0b, 0xb0, 0xf9, 0x63, 0x9a, 0x6a, 0x65, 0x69, 0xea,
-	0x63, 0x3b, 0xb0, 0xc6, 0xaa, 0x5a, 0x9c, 0xb2, 0x6a, 0xa1, 0xa7, 0x3a, 0x79, 0x33, 0x6d, 0xb5,
-	0x94, 0xe5, 0xba, 0x5d, 0xd8, 0xc8, 0xd3, 0x67, 0x15, 0xb4, 0xed, 0xae, 0x99, 0x1c, 0xb2, 0x9c,
-	0x7e, 0x08, 0x6d, 0xa6, 0x72, 0x6e, 0x07, 0x4b, 0xec, 0xd0, 0x94, 0x0b, 0x99, 0x7c, 0xa6, 0x8f,
-	0x49, 0x2b, 0xa5, 0x2f, 0x0a, 0x73, 0xb6, 0x75, 0x6a, 0x29, 0xfb, 0x1e, 0x5c, 0x61, 0x0d, 0x61,
-	0x30, 0x9a, 0x8c, 0x98, 0x09, 0x7c, 0x9e, 0x82, 0x8d, 0xda, 0xbc, 0x24, 0xf8, 0x2e, 0x23, 0x89,
-	0x2b, 0x28, 0x74, 0x33, 0x38, 0x7f, 0x67, 0xce, 0x5a, 0x30, 0x0d, 0xde, 0xc9, 0x67, 0x40, 0x18,
-	0x23, 0xbb, 0x5a, 0x43, 0xa4, 0x2c, 0x28, 0x5b, 0x5a, 0xcc, 0xe9, 0x7d, 0x86, 0xdb, 0x16, 0x2c,
-	0xba, 0x3c, 0xd2, 0x83, 0xf5, 0x49, 0x58, 0x22, 0x69, 0xe1, 0x3c, 0x8d, 0xc3, 0x1a, 0xb2, 0x1a,
-	0x5a, 0xb3, 0x26, 0x7b, 0x6b, 0xef, 0xd8, 0x0b, 0x5f, 0xd2, 0xde, 0x34, 0x76, 0xd4, 0x8d, 0x7e,
-	0x0c, 0x16, 0x0b, 0x10, 0x71, 0x83, 0x8d, 0xdd, 0x5b, 0x9a, 0xf0, 0x19, 0x0c, 0x3b, 0x3c, 0x12,
-	0x38, 0x0b, 0x77, 0xfa, 0x88, 0xf5, 0xc6, 0x5a, 0x80, 0xca, 0x8a, 0x57, 0x67, 0x68, 0xc6, 0xc6,
-	0xc9, 0x78, 0xe2, 0xd5, 0xc8, 0xe4, 0x5d, 0xd6, 0x19, 0x9a, 0x91, 0x39, 0xd7, 0xc0, 0x62, 0x92,
-	0x49, 0x15, 0x56, 0x7a, 0x6e, 0xf7, 0x8b, 0xfb, 0x4f, 0x1f, 0xb2, 0x0c, 0x03, 0xb0, 0xdc, 0x7b,
-	0xf6, 0xe0, 0x71, 0x77, 0x8f, 0xe5, 0x15, 0x16, 0x90, 0x45, 0x8d, 0x30, 0x20, 0x7f, 0xcd, 0x1c,
-	0xf6, 0xb3, 0x49, 0xa8, 0x1f, 0xfa, 0xec, 0xa4, 0xc8, 0xcb, 0x9f, 0x17, 0xbf, 0xa4, 0xa9, 0xea,
-	0x37, 0x55, 0xa3, 0x24, 0x40, 0xd9, 0x6d, 0xce, 0x89, 0x58, 0x6b, 0x4e, 0xc4, 0x92, 0x4f, 0xc0,
-	0x0e, 0x42, 0x7f, 0x38, 0x19, 0xd0, 0xfe, 0x34, 0xe4, 0xfc, 0x28, 0x08, 0x0f, 0x99, 0xd6, 0x09,
-	0x66, 0x9a, 0x0e, 0x52, 0x74, 0x91, 0x60, 0x4f, 0xad, 0xf3, 0xa0, 0x51, 0xdc, 0xbe, 0x38, 0x72,
-	0x3f, 0xf1, 0xe3, 0x60, 0x2c, 0x0b, 0xe9, 0xaa, 0xbb, 0x86, 0x8b, 0xd2, 0x1c, 0x07, 0x62, 0xc9,
-	0xf9, 0xab, 0x05, 0x5b, 0x05, 0x13, 0xa0, 0x63, 0xfe, 0x1c, 0x5a, 0x09, 0x9b, 0x68, 0x7c, 0x5e,
-	0x67, 0x23, 0xd1, 0x3b, 0x2b, 0xb7, 0xfc, 0x96, 0x76, 0xdf, 0x33, 0xb8, 0x77, 0x7a, 0xd8, 0x7f,
-	0xe3, 0xac, 0xd0, 0x54, 0xa2, 0xe4, 0x77, 0xc2, 0xcb, 0x9d, 0x6c, 0x23, 0x0c, 0x33, 0x56, 0x05,
-	0x86, 0x56, 0xbc, 0x0d, 0x2d, 0x3c, 0xc8, 0xf8, 0x44, 0x9d, 0x45, 0x3a, 0x41, 0x43, 0xe2, 0xbd,
-	0x13, 0x79, 0x0c, 0xfb, 0x3f, 0x15, 0x68, 0x98, 0x1b, 0xf2, 0x21, 0x42, 0x0b, 0x03, 0x3d, 0xdf,
-	0x34, 0x35, 0x5c, 0x64, 0x03, 0xa6, 0x8a, 0x3c, 0x5f, 0x5f, 0x0e, 0x06, 0xb2, 0x26, 0x54, 0x25,
-	0xd6, 0x15, 0xe3, 0x01, 0xcb, 0xf7, 0xc6, 0x78, 0x81, 0x5f, 0xe4, 0x0a, 0x5c, 0xca, 0x74, 0x5b,
-	0x14, 0xe2, 0x57, 0xc7, 0xa8, 0x15, 0x97, 0xcb, 0xb3, 0x05, 0xef, 0x75, 0x79, 0x5f, 0x8f, 0xf3,
-	0x51, 0x15, 0xb1, 0xa7, 0x81, 0x6c, 0xa6, 0x8e, 0xe2, 0x68, 0x34, 0xbd, 0x65, 0xd1, 0xc6, 0xac,
-	0xba, 0x35, 0x0e, 0xaa, 0x9b, 0x75, 0xfe, 0x58, 0x81, 0xcd, 0x83, 0xe0, 0x65, 0x58, 0xe2, 0xa7,
-	0x67, 0x55, 0x3a, 0xe6, 0x88, 0x09, 0x8d, 0x03, 0x6f, 0x18, 0xfc, 0xca, 0xcc, 0x0b, 0x18, 0x74,
-	0x1b, 0xd9, 0xaa, 0x26, 0x9d, 0xab, 0x15, 0x84, 0x53, 0x83, 0x50, 0x39, 0x54, 0xd6, 0xdd, 0x9a,
-	0x00, 0xbb, 0x12, 0x73, 0x5e, 0xc1, 0x56, 0x41, 0x2b, 0x74, 0x9d, 0xdc, 0xbc, 0x5a, 0x29, 0xce,
-	0xab, 0x77, 0x61, 0x73, 0x12, 0x26, 0x8c, 0x9d, 0xa9, 0x65, 0x6e, 0xb5, 0x20, 0xb6, 0x5a, 0x57,
-	0xab, 0x5d, 0x7d, 0xcb, 0x9f, 0xc0, 0xe5, 0xde, 0xe4, 0x70, 0x18, 0x24, 0xc7, 0x25, 0xb6, 0xf8,
-	0x06, 0x10, 0x14, 0x58, 0xdc, 0xbb, 0x2d, 0x57, 0x34, 0x2e, 0xe7, 0x2a, 0xd8, 0x65, 0xb2, 0x30,
-	0x37, 0xbc, 0x03, 0xd7, 0x35, 0x78, 0x3f, 0x4a, 0x83, 0xa3, 0xc0, 0xf7, 0xf4, 0xa2, 0xe6, 0x7c,
-	0xb9, 0x00, 0x37, 0x66, 0xd3, 0xa0, 0x25, 0x3e, 0x85, 0xa6, 0x97, 0xa6, 0x9e, 0x7f, 0xcc, 0xd4,
-	0x12, 0xb5, 0xe6, 0xcc, 0xd4, 0xde, 0x50, 0xf4, 0x02, 0x4d, 0x78, 0xfd, 0x1d, 0x50, 0x53, 0x02,
-	0x37, 0x11, 0x0b, 0x02, 0x05, 0x23, 0xe1, 0xac, 0x02, 0x60, 0x7d, 0xd5, 0x02, 0xc0, 0xf3, 0x51,
-	0x89, 0x44, 0x11, 0x4b, 0x54, 0x4e, 0xa4, 0x35, 0xb7, 0x53, 0x64, 0xfc, 0x5c, 0xac, 0x3b, 0xbf,
-	0xab, 0xc0, 0xf6, 0x01, 0x6b, 0x23, 0xd2, 0x90, 0xf5, 0x6b, 0x65, 0x16, 0x9c, 0x93, 0x65, 0x59,
-	0x31, 0x0f, 0xa3, 0x7e, 0xc8, 0x99, 0xde, 0xf6, 0x99, 0x2b, 0x70, 0x31, 0xc2, 0x65, 0x57, 0xdd,
-	0x66, 0x18, 0x09, 0x61, 0x6f, 0x9f, 0x49, 0x98, 0xf7, 0x6c, 0x19, 0xad, 0xa4, 0x94, 0x73, 0x7a,
-	0x5d, 0x51, 0x0a, 0x2d, 0x9c, 0xdf, 0x2f, 0xc0, 0xb5, 0x59, 0xfa, 0xe0, 0x6d, 0xfd, 0x7f, 0x93,
-	0xc6, 0x23, 0x58, 0x11, 0x6d, 0x14, 0x95, 0xaf, 0x4a, 0x66, 0xde, 0x9c, 0xaf, 0x89, 0x58, 0x66,
-	0x8c, 0xae, 0x92, 0x60, 0x3f, 0x83, 0x15, 0xc4, 0x2e, 0xa2, 0xe5, 0x75, 0xa8, 0x6a, 0xd1, 0x85,
-	0x4a, 0x42, 0x16, 0xc6, 0xce, 0x36, 0x5c, 0x51, 0xc3, 0x72, 0x99, 0x8f, 0xff, 0xb7, 0x02, 0x57,
-	0xcb, 0xd7, 0x2f, 0x34, 0x7b, 0x9c, 0x67, 0xae, 0x2c, 0x1f, 0x19, 0xad, 0x0b, 0x8d, 0x8c, 0x8b,
-	0x17, 0x1a, 0x19, 0x97, 0x66, 0x8c, 0x8c, 0xbf, 0xad, 0xc0, 0xda, 0x5e, 0x4c, 0x59, 0xfb, 0xfe,
-	0x5c, 0x5c, 0x97, 0x72, 0xd7, 0x8f, 0xa0, 0x3d, 0xe6, 0x19, 0xc3, 0xef, 0x17, 0x72, 0x6e, 0x4b,
-	0x2e, 0x68, 0xfd, 0x0b, 0xcb, 0x46, 0x6a, 0x92, 0x28, 0xb4, 0x3a, 0x6d, 0x5c, 0xd1, 0xc8, 0x09,
-	0x2c, 0x26, 0x94, 0x0e, 0xb0, 0xbe, 0x89, 0xdf, 0xce, 0x26, 0xac, 0x9b, 0x6a, 0x60, 0x6e, 0xfa,
-	0x14, 0xda, 0x4f, 0x98, 0x2b, 0x7c, 0x75, 0xe5, 0x9c, 0x75, 0x20, 0xba, 0x04, 0x94, 0xcb, 0xd0,
-	0xbd, 0x61, 0x94, 0x98, 0xa7, 0x76, 0x36, 0x98, 0x31, 0x74, 0x14, 0x89, 0x19, 0x2c, 0x91, 0x87,
-	0x6f, 0x82, 0x24, 0x7b, 0x29, 0xd9, 0x81, 0x75, 0x13, 0x46, 0x3f, 0x61, 0x05, 0x94, 0x0a, 0x44,
-	0xe8, 0xc4, 0x06, 0x26, 0xf9, 0xe5, 0x7c, 0x59, 0x81, 0xce, 0x01, 0xef, 0xe6, 0xf7, 0x38, 0x59,
-	0x98, 0x4c, 0x12, 0x77, 0xec, 0xab, 0x33, 0xb1, 0xd4, 0x87, 0x8f, 0x44, 0x7d, 0x73, 0x0a, 0x6c,
-	0x20, 0x8c, 0xe3, 0x22, 0x7f, 0xa3, 0x9b, 0x24, 0xfc, 0xca, 0xa7, 0xae, 0x35, 0xfd, 0xe6, 0x6b,
-	0xdc, 0x22, 0x8c, 0x5c, 0x59, 0x77, 0xfa, 0xcd, 0xeb, 0x94, 0x4f, 0x63, 0xf4, 0x6b, 0x8a, 0x05,
-	0x5c, 0x87, 0x9c, 0x2b, 0x70, 0xb9, 0x44, 0x3d, 0x79, 0xa8, 0x5d, 0x77, 0xfa, 0x2e, 0x7d, 0x40,
-	0xe3, 0xd7, 0x81, 0xcf, 0xd3, 0xfd, 0x0a, 0x22, 0xe4, 0xb2, 0x16, 0xec, 0xe6, 0xeb, 0xb5, 0x6d,
-	0x97, 0x2d, 0xa1, 0xcc, 0x7f, 0x57, 0xa1, 0x2e, 0x2d, 0xa8, 0x64, 0x7e, 0x0f, 0x16, 0xf9, 0x33,
-	0x1b, 0xd9, 0xd4, 0xb8, 0xb4, 0x67, 0x38, 0x7b, 0xab, 0x80, 0x4f, 0x6b, 0xcf, 0x0a, 0x3e, 0xa7,
-	0x19, 0xca, 0x98, 0x6f, 0x74, 0x86, 0x32, 0xf9, 0xc7, 0x3a, 0x17, 0xea, 0xc6, 0x53, 0x1a, 0xb9,
-	0x5e, 0x7c, 0xe1, 0x32, 0xde, 0xe7, 0xec, 0x1b, 0xb3, 0x09, 0x50, 0xe6, 0x1e, 0xac, 0xaa, 0xb7,
-	0x31, 0x62, 0x97, 0x3e, 0x98, 0x49, 0x49, 0x57, 0xe6, 0x3c, 0xa6, 0xf1, 0xa3, 0xa9, 0xa7, 0x26,
-	0xfd, 0x68, 0xe6, 0x7c, 0x6d, 0x1c, 0x2d, 0x3f, 0x0a, 0xbf, 0x80, 0x66, 0x6e, 0x22, 0x23, 0xef,
-	0x68, 0xe4, 0xe5, 0x83, 0xac, 0xed, 0xcc, 0x23, 0x41, 0xc9, 0x13, 0xe8, 0xcc, 0x6a, 0x0b, 0xc8,
-	0x87, 0xe5, 0x55, 0xb8, 0x2c, 0xf7, 0xda, 0x1f, 0x9d, 0x8b, 0x56, 0x6e, 0x7a, 0xa7, 0x42, 0x22,
-	0xd6, 0x24, 0x96, 0xd6, 0x14, 0x72, 0xfb, 0x1c, 0x65, 0x47, 0x6e, 0xf9, 0xc1, 0xb9, 0x0b, 0x14,
-	0xdb, 0x30, 0xc8, 0x9e, 0x68, 0x8d, 0xed, 0x6e, 0x95, 0xb8, 0x40, 0xd9, 0x66, 0xef, 0x9f, 0x49,
-	0x37, 0xdd, 0xea, 0x67, 0xd0, 0xca, 0x4f, 0x71, 0xc4, 0x39, 0x7b, 0xe8, 0xb4, 0x6f, 0xce, 0xa5,
-	0xc9, 0x9c, 0xdc, 0x78, 0xc7, 0x33, 0x9c, 0xbc, 0xec, 0xed, 0xd0, 0x70, 0xf2, 0xd2, 0x27, 0x40,
-	0xf2, 0x18, 0xaa, 0xda, 0x4b, 0x1d, 0xd9, 0xce, 0xbf, 0x9d, 0x99, 0xf2, 0xae, 0xcd, 0x5a, 0xce,
-	0x49, 0xc3, 0x6c, 0xb7, 0x3d, 0xf7, 0x25, 0xae, 0x28, 0x2d, 0xf7, 0xa6, 0xc6, 0x8c, 0x99, 0x7f,
-	0xa3, 0x32, 0x8c, 0x39, 0xe3, 0x55, 0xcd, 0x30, 0xe6, 0xac, 0x47, 0x2e, 0x1e, 0x56, 0xb9, 0x89,
-	0xd0, 0x08, 0xab, 0xf2, 0x71, 0xdb, 0x08, 0xab, 0x59, 0xe3, 0x28, 0x93, 0x9c, 0x1b, 0x37, 0x0c,
-	0xc9, 0xe5, 0x03, 0x92, 0x21, 0x79, 0xd6, 0xb4, 0xe2, 0x01, 0x29, 0x4e, 0x02, 0x44, 0xff, 0x0f,
-	0x6c, 0xe6, 0xd0, 0x61, 0xbf, 0x77, 0x06, 0x15, 0x66, 0xf5, 0xbf, 0x59, 0xaa, 0x5c, 0x3e, 0x8e,
-	0x3c, 0xd6, 0xc3, 0xa9, 0xdc, 0xfe, 0x04, 0x6a, 0x7a, 0xb9, 0x24, 0xfa, 0xdd, 0x95, 0x94, 0x57,
-	0xfb, 0xfa, 0xcc, 0x75, 0x3c, 0x0b, 0x13, 0xa8, 0xf7, 0x0c, 0x86, 0xc0, 0x92, 0x9e, 0xc6, 0x10,
-	0x58, 0xd6, 0x6c, 0x90, 0x2e, 0x40, 0xd6, 0x2a, 0x90, 0xab, 0x1a, 0x79, 0xa1, 0x07, 0xb1, 0xb7,
-	0x67, 0xac, 0x66, 0x6e, 0xac, 0x75, 0x12, 0x86, 0x1b, 0x17, 0xfb, 0x0e, 0xc3, 0x8d, 0x4b, 0x1a,
-	0x10, 0xf2, 0x0b, 0x68, 0x17, 0x2a, 0x33, 0xd1, 0x7d, 0x74, 0x56, 0x5b, 0x61, 0xbf, 0x3b, 0x9f,
-	0x48, 0xca, 0x3f, 0x5c, 0x16, 0x7f, 0x43, 0x7f, 0xfb, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x22,
-	0xfc, 0x70, 0xb3, 0x93, 0x1e, 0x00, 0x00,
+	// 4986 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x3c, 0x5d, 0x6f, 0x1c, 0xd7,
+	0x75, 0x9e, 0xdd, 0x25, 0xb9, 0x3c, 0xfb, 0xc1, 0xe5, 0x2c, 0x3f, 0x56, 0x23, 0x89, 0xa4, 0x46,
+	0xb6, 0x24, 0x2b, 0x36, 0xad, 0x30, 0x71, 0xe3, 0xb4, 0xa9, 0x63, 0x4a, 0x96, 0x6d, 0x9a, 0x14,
+	0xb9, 0x18, 0x52, 0x96, 0x03, 0x17, 0x5d, 0xcc, 0xee, 0x5c, 0x92, 0x37, 0xdc, 0x9d, 0x19, 0xcf,
+	0xcc, 0x92, 0xda, 0x02, 0x05, 0x82, 0x02, 0x45, 0x9f, 0xda, 0xa2, 0x69, 0x03, 0x14, 0x29, 0xfa,
+	0x92, 0xd7, 0xbe, 0x14, 0xe8, 0x4b, 0x80, 0xa2, 0x40, 0x0b, 0xf4, 0x27, 0xe4, 0x29, 0x7d, 0xeb,
+	0x73, 0x81, 0x00, 0x45, 0x7f, 0x40, 0x71, 0xbf, 0x66, 0xee, 0x9d, 0x8f, 0xe5, 0xca, 0x51, 0x1f,
+	0xfa, 0xb6, 0xf7, 0x9c, 0x73, 0xcf, 0xfd, 0x3a, 0xf7, 0x7c, 0xde, 0x59, 0x58, 0xb4, 0x7d, 0xbc,
+	0xed, 0x07, 0x5e, 0xe4, 0xe9, 0x8b, 0x57, 0xf6, 0x70, 0x88, 0xa2, 0xc0, 0x1f, 0x98, 0x2d, 0x68,
+	0x7e, 0x81, 0x82, 0x10, 0x7b, 0xae, 0x85, 0xbe, 0x1e, 0xa3, 0x30, 0x32, 0xff, 0x4d, 0x83, 0xa5,
+	0x18, 0x14, 0xfa, 0x9e, 0x1b, 0x22, 0xfd, 0x2d, 0x68, 0x5e, 0x32, 0x50, 0x2f, 0x8c, 0x02, 0xec,
+	0x9e, 0x75, 0xb4, 0x2d, 0xed, 0xc1, 0xa2, 0xd5, 0xe0, 0xd0, 0x63, 0x0a, 0xd4, 0x57, 0x60, 0x6e,
+	0x64, 0xff, 0xd8, 0x0b, 0x3a, 0xa5, 0x2d, 0xed, 0x41, 0xc3, 0x62, 0x0d, 0x0a, 0xc5, 0xae, 0x17,
+	0x74, 0xca, 0x1c, 0x8a, 0x5d, 0x06, 0xf5, 0xed, 0x68, 0x70, 0xde, 0xa9, 0x30, 0x28, 0x6d, 0xe8,
+	0x1b, 0x00, 0x7e, 0x80, 0x02, 0x34, 0x44, 0x76, 0x88, 0x3a, 0x73, 0x74, 0x10, 0x09, 0x42, 0x26,
+	0xd2, 0x1f, 0xe3, 0xa1, 0xd3, 0x1b, 0xa1, 0xc8, 0x76, 0xec, 0xc8, 0xee, 0xcc, 0xb3, 0x89, 0x50,
+	0xe8, 0x33, 0x0e, 0x34, 0xff, 0xb5, 0x0c, 0xfa, 0x49, 0x60, 0xbb, 0xa1, 0x3d, 0x88, 0xb0, 0xe7,
+	0x7e, 0x8c, 0x22, 0x1b, 0x0f, 0x43, 0x5d, 0x87, 0xca, 0xb9, 0x1d, 0x9e, 0xd3, 0xc9, 0xd7, 0x2d,
+	0xfa, 0x5b, 0xdf, 0x82, 0x5a, 0x94, 0x50, 0xd2, 0x99, 0xd7, 0x2d, 0x19, 0xa4, 0xff, 0x1e, 0xcc,
+	0x3b, 0xa8, 0x8f, 0xa3, 0xb0, 0x53, 0xde, 0x2a, 0x3f, 0xa8, 0xed, 0xdc, 0xdd, 0x8e, 0xb7, 0x6f,
+	0x3b, 0x3b, 0xc8, 0xf6, 0x9e, 0xeb, 0x8f, 0x23, 0x8b, 0x77, 0xd1, 0x3f, 0x84, 0x85, 0x41, 0x80,
+	0x1c, 0xd2, 0xbb, 0x42, 0x7b, 0xbf, 0x39, 0xbd, 0xf7, 0xd1, 0x38, 0x22, 0xdd, 0x45, 0x27, 0xbd,
+	0x05, 0xe5, 0x53, 0xc4, 0x76, 0xa2, 0x6c, 0x91, 0x9f, 0xfa, 0x2d, 0x58, 0x8c, 0xf0, 0x08, 0x85,
+	0x91, 0x3d, 0xf2, 0xe9, 0xea, 0xcb, 0x56, 0x02, 0x30, 0xbe, 0x86, 0x39, 0x3a, 0x01, 0xb2, 0xbf,
+	0xd8, 0x75, 0xd0, 0x4b, 0xba, 0xd8, 0x86, 0xc5, 0x1a, 0xfa, 0xdb, 0xd0, 0xf2, 0x03, 0x74, 0x89,
+	0xbd, 0x71, 0xd8, 0xb3, 0x07, 0x03, 0x6f, 0xec, 0x46, 0xfc, 0xb0, 0x96, 0x04, 0x7c, 0x97, 0x81,
+	0xf5, 0xfb, 0xb0, 0x94, 0x90, 0x8e, 0x28, 0x65, 0x99, 0x8e, 0xd6, 0x8c, 0x29, 0x29, 0xd4, 0x38,
+	0x81, 0x79, 0x36, 0xeb, 0x82, 0x31, 0x3b, 0xb0, 0xa0, 0x0e, 0x25, 0x9a, 0xba, 0x01, 0x55, 0xec,
+	0x46, 0x28, 0x70, 0xed, 0x21, 0xe5, 0x5d, 0xb5, 0xe2, 0xb6, 0xf9, 0x77, 0x1a, 0xd4, 0x1f, 0x0f,
+	0xbd, 0xc1, 0xc5, 0xb4, 0xc3, 0x5b, 0x83, 0xf9, 0x73, 0x84, 0xcf, 0xce, 0x19, 0xe7, 0x39, 0x8b,
+	0xb7, 0xd4, 0x3d, 0x2a, 0xa7, 0xf6, 0x48, 0xdf, 0x85, 0xba, 0x74, 0xbe, 0xe2, 0x60, 0x6e, 0x4f,
+	0x3d, 0x18, 0x4b, 0xe9, 0x62, 0x1e, 0x41, 0x93, 0xef, 0xd3, 0x63, 0x7b, 0x68, 0xbb, 0x03, 0x24,
+	0xaf, 0x52, 0x53, 0x57, 0x79, 0x17, 0x1a, 0x91, 0x17, 0xd9, 0xc3, 0x5e, 0x9f, 0x91, 0xd2, 0xb9,
+	0x96, 0xad, 0x3a, 0x05, 0xf2, 0xee, 0x66, 0x03, 0x6a, 0x5d, 0xec, 0x9e, 0x89, 0x4b, 0xd8, 0x84,
+	0x3a, 0x6b, 0xb2, 0x0b, 0x48, 0xae, 0xe9, 0x21, 0x8a, 0xae, 0xbc, 0xe0, 0x42, 0x50, 0x7c, 0x00,
+	0x4b, 0x31, 0x24, 0xb9, 0xa5, 0x64, 0x7e, 0x97, 0xa8, 0xe7, 0x32, 0x0c, 0x9f, 0x49, 0x83, 0x41,
+	0x39, 0xb9, 0xf9, 0x7d, 0x58, 0xe1, 0x73, 0x3f, 0x1c, 0x8f, 0xfa, 0x28, 0xe0, 0x1c, 0xf5, 0x3b,
+	0x50, 0xe7, 0x53, 0xee, 0xb9, 0xf6, 0x08, 0xf1, 0x2b, 0x5e, 0xe3, 0xb0, 0x43, 0x7b, 0x84, 0xcc,
+	0x0f, 0x61, 0x35, 0xd5, 0x55, 0x1e, 0x9a, 0xf7, 0xa5, 0x98, 0x64, 0x68, 0x89, 0xdc, 0x5c, 0x86,
+	0x25, 0xde, 0x3f, 0x14, 0xeb, 0xf8, 0x65, 0x19, 0x5a, 0x09, 0x8c, 0xb3, 0xfb, 0x21, 0x54, 0x79,
+	0xc7, 0xb0, 0xa3, 0x65, 0x2e, 0x5d, 0x9a, 0x5c, 0x00, 0xac, 0xb8, 0x93, 0xfe, 0x0e, 0xe8, 0x83,
+	0x71, 0x10, 0x20, 0x37, 0xea, 0xf5, 0x89, 0x10, 0xf5, 0xa8, 0xe8, 0xb0, 0xcb, 0xdd, 0xe2, 0x18,
+	0x2a, 0x5d, 0x9f, 0x11, 0x31, 0x7a, 0x04, 0x2b, 0x29, 0x6a, 0x26, 0x54, 0x65, 0x2a, 0x54, 0xba,
+	0x42, 0x4f, 0x31, 0xc6, 0x9f, 0x94, 0x60, 0x41, 0x5c, 0x94, 0xd9, 0xd6, 0x9e, 0xd9, 0xde, 0x52,
+	0x66, 0x7b, 0xb3, 0x92, 0x52, 0xce, 0x4a, 0x0a, 0x59, 0x1a, 0x7a, 0xc9, 0x2e, 0x49, 0xef, 0x02,
+	0x4d, 0x7a, 0x4c, 0xe6, 0x98, 0x16, 0x6d, 0x09, 0xcc, 0x3e, 0x9a, 0x3c, 0xa1, 0x93, 0x7b, 0x07,
+	0x74, 0xec, 0x66, 0xa8, 0xe7, 0x18, 0x35, 0x76, 0x73, 0xa8, 0x47, 0xbe, 0x17, 0x44, 0xc8, 0x91,
+	0xa8, 0xe7, 0x39, 0x35, 0xc7, 0x08, 0x6a, 0xf3, 0x4b, 0x58, 0xb1, 0x10, 0x59, 0x8b, 0xd8, 0x7f,
+	0x2e, 0x48, 0x33, 0x6e, 0xc8, 0x0d, 0xa8, 0xba, 0xe8, 0x4a, 0xde, 0x8c, 0x05, 0x17, 0x5d, 0x51,
+	0x39, 0x5b, 0x87, 0xd5, 0x14, 0x67, 0x7e, 0x0f, 0x5e, 0x80, 0x7e, 0x88, 0x5e, 0x46, 0xa9, 0x01,
+	0x89, 0xd5, 0xb0, 0xc3, 0xd0, 0x3f, 0x0f, 0x88, 0xd5, 0x60, 0x0a, 0x42, 0x82, 0xcc, 0xb0, 0xf5,
+	0xe6, 0x0f, 0xa0, 0xad, 0x30, 0x7e, 0x35, 0xb9, 0xfe, 0xb9, 0xc6, 0xe7, 0xe5, 0x38, 0x01, 0x0a,
+	0x85, 0x6c, 0x4f, 0xd1, 0x09, 0xbf, 0x03, 0x95, 0x0b, 0xec, 0x3a, 0x74, 0x26, 0xcd, 0x1d, 0x53,
+	0x12, 0xee, 0x2c, 0x9b, 0xed, 0x7d, 0xec, 0x3a, 0x16, 0xa5, 0x37, 0x77, 0xa0, 0x42, 0x5a, 0xfa,
+	0x0a, 0xb4, 0x1e, 0xef, 0x75, 0x1f, 0x3d, 0xfa, 0xee, 0x77, 0x7b, 0x4f, 0xbf, 0x3c, 0x79, 0x6a,
+	0x1d, 0xee, 0x1e, 0xb4, 0xde, 0x90, 0xa1, 0x7b, 0x87, 0x1c, 0xaa, 0x99, 0xef, 0x41, 0x5b, 0x61,
+	0xca, 0x97, 0x46, 0x26, 0xc7, 0x40, 0xfc, 0xa6, 0x8b, 0xa6, 0xf9, 0xd7, 0x1a, 0xac, 0xef, 0xd1,
+	0xc3, 0xee, 0x06, 0xf8, 0xd2, 0x8e, 0xd0, 0x3e, 0x9a, 0xcc, 0xba, 0xd5, 0xc5, 0xca, 0xfe, 0x1e,
+	0xb1, 0x27, 0x94, 0x1d, 0x15, 0xad, 0x2b, 0x7c, 0x4a, 0xc5, 0x7b, 0xd1, 0x6a, 0xf8, 0xf1, 0x28,
+	0x2f, 0xf0, 0x29, 0xd1, 0xe9, 0x01, 0x0a, 0x07, 0xb6, 0x4b, 0x65, 0xba, 0x6a, 0xf1, 0x96, 0x69,
+	0x40, 0x27, 0x3b, 0x29, 0x2e, 0x16, 0x2e, 0x34, 0xf9, 0xf5, 0x78, 0x45, 0x19, 0x7c, 0x1f, 0xd6,
+	0x02, 0xf4, 0xf5, 0x18, 0x07, 0xc8, 0xe9, 0x0d, 0x3c, 0xf7, 0x14, 0x07, 0x23, 0x9b, 0x19, 0x05,
+	0x66, 0x50, 0x56, 0x05, 0xf6, 0x89, 0x8c, 0x34, 0x5d, 0x58, 0x8a, 0xc7, 0xe3, 0xdb, 0xb9, 0x02,
+	0x73, 0xf4, 0x9a, 0xd2, 0x71, 0xca, 0x16, 0x6b, 0x10, 0x43, 0x14, 0xfa, 0xc8, 0x75, 0xec, 0xfe,
+	0x50, 0xe8, 0xfd, 0x04, 0x40, 0x4c, 0x2c, 0x1e, 0x8d, 0xec, 0x68, 0x1c, 0xa0, 0x5e, 0x80, 0xae,
+	0xec, 0xc0, 0x11, 0x26, 0x56, 0x80, 0x2d, 0x0a, 0x35, 0xff, 0xb6, 0x04, 0x6b, 0x9f, 0xa2, 0x48,
+	0x32, 0x4b, 0xb1, 0x8c, 0x6d, 0x43, 0x3b, 0x8c, 0xec, 0x20, 0xc2, 0xee, 0x99, 0xac, 0xea, 0xd8,
+	0xc9, 0x2c, 0x0b, 0x54, 0xa2, 0xeb, 0x76, 0x60, 0x35, 0x4d, 0x9f, 0x58, 0xd0, 0x65, 0xab, 0xad,
+	0xf6, 0xa0, 0x28, 0xfd, 0x21, 0x2c, 0x23, 0xd7, 0x49, 0x8d, 0x50, 0xa6, 0x23, 0x2c, 0x31, 0x44,
+	0xc2, 0x7f, 0x1b, 0xda, 0x2a, 0x2d, 0xe3, 0x5e, 0xa1, 0xdb, 0xb9, 0x2c, 0x53, 0x33, 0xde, 0x1f,
+	0xc2, 0xcd, 0x11, 0x76, 0xf1, 0x68, 0x3c, 0xea, 0x05, 0x68, 0x40, 0x54, 0xb0, 0x62, 0x9b, 0xe7,
+	0x68, 0xbf, 0x1b, 0x9c, 0xc4, 0xa2, 0x14, 0xf2, 0x36, 0x98, 0xff, 0xa4, 0xc1, 0x7a, 0x66, 0x6b,
+	0xf8, 0x99, 0x7c, 0x02, 0xfa, 0x08, 0xbb, 0xc8, 0x51, 0x59, 0x32, 0x83, 0xb2, 0x2e, 0xdd, 0x39,
+	0xd9, 0xcf, 0xb0, 0x96, 0x69, 0x17, 0x99, 0x9f, 0xde, 0x85, 0x95, 0xb1, 0x9b, 0xc3, 0xa9, 0x34,
+	0x8b, 0xe3, 0xd0, 0xe6, 0x5d, 0x95, 0x59, 0xff, 0xaa, 0x04, 0xeb, 0x4f, 0xce, 0x6d, 0xf7, 0x0c,
+	0x75, 0xe3, 0xbb, 0x23, 0x4e, 0xf4, 0x03, 0x28, 0x5f, 0xa0, 0x09, 0x3d, 0xc1, 0xe6, 0xce, 0x3d,
+	0x89, 0x79, 0x41, 0x87, 0x6d, 0x72, 0x13, 0x48, 0x17, 0x22, 0xf4, 0xde, 0xd0, 0xe9, 0x49, 0x17,
+	0x94, 0x59, 0xbc, 0x86, 0x37, 0x74, 0x92, 0x6e, 0x84, 0x8c, 0x28, 0x5e, 0x89, 0x8c, 0x9d, 0x65,
+	0xc3, 0x45, 0x57, 0x12, 0xd9, 0x43, 0x28, 0x5f, 0x38, 0xa7, 0xf4, 0xe4, 0x6a, 0x3b, 0x1d, 0x69,
+	0x1e, 0xfb, 0xce, 0x69, 0xd7, 0x0e, 0xec, 0x11, 0x8a, 0x50, 0x10, 0x5a, 0x84, 0x88, 0x48, 0x72,
+	0x80, 0x06, 0xde, 0x68, 0x84, 0x5c, 0x87, 0xd8, 0x0e, 0xe7, 0x94, 0x9e, 0x5c, 0xd5, 0x6a, 0x4a,
+	0xe0, 0x7d, 0xe7, 0x54, 0xff, 0x01, 0x18, 0x91, 0x1d, 0x9c, 0xa1, 0xa8, 0x37, 0x76, 0xa9, 0x7c,
+	0x8c, 0xf0, 0x70, 0x88, 0x43, 0x34, 0xf0, 0x5c, 0x27, 0xe4, 0xee, 0x6c, 0x87, 0x51, 0x3c, 0xa7,
+	0x04, 0xcf, 0x24, 0xbc, 0xb9, 0x01, 0xe5, 0x7d, 0x34, 0xd1, 0x6b, 0xb0, 0xd0, 0xb5, 0xf6, 0xbe,
+	0xd8, 0x3d, 0x79, 0xda, 0x7a, 0x43, 0x07, 0x98, 0xef, 0x3e, 0x7f, 0x7c, 0xb0, 0xf7, 0xa4, 0xa5,
+	0x11, 0x1d, 0x91, 0xdd, 0x24, 0xae, 0x23, 0xfe, 0xa5, 0x04, 0x0d, 0x65, 0xe6, 0xfa, 0x47, 0xb0,
+	0x68, 0x0f, 0xcf, 0xbc, 0x00, 0x47, 0xe7, 0xa3, 0x8e, 0x96, 0xd1, 0xc4, 0x0a, 0xf1, 0xf6, 0xae,
+	0xa0, 0xb4, 0x92, 0x4e, 0xc4, 0x84, 0x85, 0x83, 0x60, 0xe2, 0x47, 0x3d, 0x97, 0xdf, 0xee, 0x05,
+	0xd6, 0x3e, 0x94, 0x50, 0x41, 0xa7, 0x2c, 0xa3, 0x2c, 0x09, 0xe5, 0x77, 0x2a, 0x32, 0xaa, 0xab,
+	0x6f, 0x42, 0xcd, 0x0e, 0xce, 0x3c, 0x77, 0xa7, 0x47, 0xdc, 0x55, 0x6e, 0xa7, 0x81, 0x81, 0x4e,
+	0xf0, 0x88, 0x1c, 0xca, 0x32, 0x27, 0x18, 0xa1, 0x91, 0x17, 0x4c, 0x7a, 0x17, 0xb8, 0xcf, 0x0d,
+	0xf4, 0x12, 0x43, 0x3c, 0xa3, 0xf0, 0x7d, 0xdc, 0xa7, 0x3a, 0x90, 0x33, 0x3b, 0x0f, 0x90, 0xed,
+	0x84, 0x9d, 0x05, 0xae, 0x03, 0x19, 0x3f, 0x06, 0x34, 0xdf, 0x82, 0xc5, 0x78, 0x71, 0x64, 0x37,
+	0x8f, 0x9f, 0x58, 0x3f, 0xea, 0x9e, 0xb4, 0xde, 0xd0, 0xeb, 0x50, 0xdd, 0xb5, 0x3e, 0x3d, 0x3a,
+	0xdc, 0xd9, 0xfb, 0xb8, 0xa5, 0x99, 0x3f, 0x29, 0xc1, 0xda, 0x27, 0x63, 0x57, 0x96, 0xe3, 0xeb,
+	0xed, 0x1c, 0xf1, 0x68, 0xd8, 0x71, 0xf3, 0x10, 0x42, 0xf8, 0xbe, 0x14, 0xc8, 0x02, 0x88, 0x29,
+	0x4a, 0xb8, 0x3c, 0x45, 0x09, 0x13, 0x51, 0xc2, 0xee, 0x60, 0x38, 0x76, 0x50, 0x2f, 0xd6, 0xa2,
+	0x03, 0x0f, 0xbb, 0x7d, 0x3b, 0x44, 0x21, 0x37, 0x1e, 0x1d, 0x4e, 0xb1, 0xc7, 0x09, 0x9e, 0x08,
+	0x3c, 0xd1, 0x83, 0xa2, 0xf7, 0x80, 0x8a, 0x4c, 0x2f, 0x1c, 0x04, 0xd8, 0x8f, 0xb8, 0xdc, 0xb6,
+	0x39, 0x92, 0x89, 0xd3, 0x31, 0x45, 0x99, 0xbf, 0x28, 0xc3, 0x7a, 0x66, 0x0b, 0xb8, 0xae, 0xf9,
+	0x03, 0x68, 0x85, 0x68, 0x88, 0x06, 0xc4, 0x75, 0xf2, 0x68, 0x38, 0x24, 0x34, 0xcd, 0xb7, 0x25,
+	0x99, 0x2a, 0xe8, 0xbd, 0xdd, 0xe5, 0x21, 0x15, 0x0f, 0xff, 0x96, 0x04, 0x2b, 0xd6, 0x0e, 0x89,
+	0x07, 0xc3, 0x3c, 0x43, 0x65, 0x1b, 0x6b, 0x14, 0xc6, 0x77, 0xf1, 0x01, 0xb4, 0xf8, 0x42, 0xfc,
+	0x0b, 0xb1, 0x16, 0x76, 0xaf, 0x9b, 0x0c, 0xde, 0xbd, 0x60, 0xcb, 0x30, 0xfe, 0x43, 0x83, 0xa6,
+	0x3a, 0x20, 0x89, 0x0b, 0x25, 0xcd, 0x26, 0x9b, 0x90, 0x25, 0x09, 0x4e, 0x15, 0xfc, 0x1d, 0xa8,
+	0xb3, 0xf5, 0xf5, 0x58, 0xac, 0xc7, 0xcc, 0x7c, 0x8d, 0xc1, 0xf6, 0x08, 0x88, 0x98, 0x70, 0x25,
+	0x62, 0xe4, 0x2d, 0xfd, 0x26, 0x2c, 0x26, 0x73, 0xab, 0x50, 0xf6, 0x55, 0x9f, 0xcf, 0x8a, 0xf0,
+	0x25, 0x06, 0x80, 0x84, 0x2f, 0xb1, 0xec, 0x97, 0xad, 0x1a, 0x87, 0x51, 0xe1, 0xbf, 0x0b, 0x8d,
+	0xd3, 0xc0, 0x1b, 0xc5, 0xa7, 0x4c, 0x05, 0xbf, 0x6a, 0xd5, 0x09, 0x50, 0x9c, 0xac, 0xf9, 0x37,
+	0x1a, 0xac, 0x1d, 0xe3, 0x33, 0x37, 0x47, 0x4e, 0xaf, 0x73, 0x5e, 0xde, 0x87, 0xb5, 0x10, 0x05,
+	0xd8, 0x1e, 0xe2, 0x3f, 0x52, 0x55, 0x3d, 0xd7, 0xa3, 0xab, 0x09, 0x56, 0xe2, 0x4e, 0xa6, 0x85,
+	0xdd, 0x78, 0x43, 0x10, 0xcb, 0x13, 0x34, 0xac, 0x3a, 0x76, 0xc5, 0x8e, 0xa0, 0xd0, 0xfc, 0x1a,
+	0xd6, 0x33, 0xb3, 0xe2, 0xa2, 0x93, 0x4a, 0x41, 0x68, 0xd9, 0x14, 0xc4, 0x77, 0x61, 0x6d, 0xec,
+	0x86, 0xf8, 0x8c, 0x58, 0x20, 0x75, 0xa8, 0x12, 0x1d, 0x6a, 0x45, 0x60, 0xf7, 0xe4, 0x21, 0x3f,
+	0x87, 0x1b, 0xdd, 0x71, 0x7f, 0x88, 0xc3, 0xf3, 0x9c, 0xbd, 0x78, 0x17, 0x74, 0xce, 0x30, 0x3b,
+	0xf6, 0x32, 0xc3, 0x48, 0xbd, 0xcc, 0x5b, 0x60, 0xe4, 0xf1, 0xe2, 0xba, 0xf5, 0x03, 0xa8, 0xee,
+	0xa3, 0xc9, 0xf1, 0xc0, 0xf3, 0xa9, 0x07, 0xe8, 0x8f, 0x03, 0xdf, 0xe3, 0x3b, 0xdc, 0xb0, 0x44,
+	0x93, 0x44, 0xf0, 0xe4, 0xe4, 0xb8, 0xc4, 0xd0, 0xdf, 0xe6, 0x97, 0x50, 0x3d, 0x1a, 0x47, 0x5d,
+	0x0f, 0xbb, 0xaf, 0x59, 0x08, 0xcd, 0xc7, 0xb0, 0xaa, 0xfa, 0x05, 0x62, 0xe5, 0xb3, 0x0f, 0x43,
+	0x75, 0x5e, 0x9a, 0x49, 0x1c, 0xa2, 0x66, 0x0e, 0xed, 0x5a, 0x57, 0x40, 0x39, 0xd3, 0xdb, 0x00,
+	0x99, 0xd0, 0x74, 0xb1, 0x1f, 0xfb, 0x51, 0x77, 0xa0, 0x9e, 0x13, 0x8b, 0xd6, 0xfa, 0x92, 0xeb,
+	0xf4, 0x26, 0x34, 0x54, 0x75, 0xc9, 0x9c, 0x2c, 0x15, 0x48, 0x4c, 0x33, 0x63, 0x94, 0x64, 0x44,
+	0xd8, 0xd5, 0x6a, 0x52, 0xf0, 0x89, 0x80, 0x12, 0x0f, 0x76, 0x68, 0xf7, 0xd1, 0x90, 0xa7, 0xd4,
+	0x58, 0xc3, 0xfc, 0x2b, 0x0d, 0xf4, 0x03, 0x1c, 0x46, 0xcf, 0xdd, 0xd0, 0x47, 0x49, 0xc8, 0xf5,
+	0x2d, 0x20, 0x7e, 0x52, 0x4a, 0x5d, 0x6b, 0x74, 0xfc, 0xd6, 0x08, 0xbb, 0xaa, 0xa6, 0x26, 0xc4,
+	0xf6, 0xcb, 0x5c, 0x07, 0xbb, 0x35, 0xb2, 0x5f, 0xaa, 0xc4, 0xe9, 0x60, 0xad, 0x9c, 0x0d, 0xd6,
+	0x7e, 0x53, 0x82, 0xb6, 0x32, 0x27, 0x7e, 0x26, 0x1f, 0xc1, 0xc2, 0x98, 0x81, 0xb8, 0xea, 0x95,
+	0xbd, 0xa7, 0x9c, 0x0e, 0xdb, 0xa2, 0x2d, 0xba, 0x19, 0x3f, 0x2b, 0xc1, 0x02, 0x07, 0xea, 0xef,
+	0x41, 0x95, 0xc8, 0x13, 0x11, 0x4d, 0x7e, 0xbc, 0x6d, 0x89, 0x9d, 0x90, 0x5a, 0x2b, 0x26, 0x92,
+	0x23, 0xaa, 0x92, 0x12, 0x51, 0xcd, 0xb0, 0x26, 0x49, 0x67, 0x56, 0x8a, 0x75, 0xe6, 0x5c, 0x4a,
+	0x67, 0xde, 0x85, 0x46, 0x80, 0x1c, 0x84, 0x46, 0x82, 0x60, 0x9e, 0x12, 0xd4, 0x19, 0x90, 0x13,
+	0x65, 0xc4, 0x64, 0x81, 0x0e, 0xa0, 0x02, 0xd5, 0x48, 0xa5, 0x4a, 0xf5, 0x6a, 0x02, 0x20, 0x8e,
+	0x15, 0xd9, 0xbf, 0x03, 0x64, 0x87, 0xb1, 0x51, 0x12, 0x19, 0x9c, 0x9f, 0x97, 0xe0, 0x46, 0x0e,
+	0x92, 0x9f, 0xc9, 0x97, 0xd0, 0xa4, 0xa9, 0xdb, 0x69, 0x56, 0xb1, 0xb0, 0xf7, 0xb6, 0x0c, 0xb5,
+	0x1a, 0x43, 0x99, 0xc6, 0xf8, 0x85, 0x06, 0x75, 0x19, 0xaf, 0x37, 0xa1, 0x84, 0x1d, 0x7e, 0x95,
+	0x4b, 0xd8, 0x51, 0x0e, 0xb0, 0x34, 0xcb, 0x01, 0x6e, 0x00, 0xa0, 0x97, 0x3e, 0x0e, 0xe8, 0x96,
+	0x70, 0xdb, 0x25, 0x41, 0xc8, 0x0d, 0xb9, 0xb4, 0x87, 0x63, 0xc4, 0x8f, 0x88, 0x35, 0xa6, 0x9e,
+	0x90, 0xb9, 0x0d, 0x3a, 0x0f, 0xbc, 0xf7, 0xdc, 0x53, 0x4f, 0x76, 0x98, 0xf2, 0x63, 0xef, 0x7f,
+	0x2e, 0x43, 0x5b, 0xe9, 0x70, 0x5d, 0xb4, 0xae, 0x0e, 0x5f, 0x4a, 0x09, 0xc8, 0x3a, 0x2c, 0xe0,
+	0xb0, 0x47, 0xe2, 0x0f, 0x9e, 0x60, 0x9d, 0xc7, 0xe1, 0x33, 0xec, 0x22, 0xdd, 0x84, 0x06, 0x0e,
+	0x7b, 0x57, 0x24, 0xe9, 0xde, 0xf3, 0xdc, 0xe1, 0x84, 0xfb, 0x4b, 0x35, 0x1c, 0xbe, 0x20, 0xb0,
+	0x23, 0x77, 0x38, 0x21, 0x9c, 0x71, 0xc8, 0xbd, 0x23, 0xee, 0x16, 0x55, 0x71, 0xc8, 0x3c, 0xa2,
+	0x8c, 0x48, 0xcf, 0x67, 0x45, 0xfa, 0x11, 0x2c, 0x92, 0x48, 0x3f, 0x24, 0x66, 0xa1, 0xb3, 0x90,
+	0x39, 0x00, 0x61, 0x31, 0xac, 0xea, 0x05, 0xff, 0x45, 0x2c, 0xc4, 0x38, 0x44, 0x0e, 0x97, 0x3f,
+	0xfa, 0x3b, 0x51, 0x4b, 0x8b, 0x92, 0x5a, 0x22, 0xda, 0xd3, 0x27, 0xf6, 0x68, 0x40, 0x92, 0x09,
+	0x1d, 0x60, 0xda, 0x93, 0x41, 0x48, 0x84, 0x70, 0x1f, 0x96, 0x1c, 0x44, 0xf3, 0x04, 0x44, 0xc5,
+	0xfb, 0x76, 0x74, 0xde, 0xa9, 0xd1, 0xee, 0xcd, 0x04, 0xdc, 0xb5, 0xa3, 0x73, 0x62, 0x59, 0x47,
+	0x76, 0x18, 0xa1, 0x80, 0xf0, 0xe9, 0x9d, 0x62, 0xf7, 0x0c, 0x05, 0x7e, 0x40, 0x24, 0xa6, 0x4e,
+	0x4d, 0xca, 0x0a, 0xc3, 0xee, 0xa3, 0xc9, 0x27, 0x09, 0x8e, 0x5c, 0x56, 0xbe, 0xe1, 0x0d, 0x3a,
+	0x32, 0x6f, 0x99, 0xe7, 0xb0, 0xf2, 0x05, 0x0a, 0xf0, 0xe9, 0xe4, 0x19, 0x0a, 0x43, 0xfb, 0x0c,
+	0x5d, 0x7b, 0xde, 0x04, 0x33, 0x62, 0xb4, 0x42, 0x67, 0xf0, 0x26, 0xbd, 0x90, 0xf8, 0xcc, 0xa5,
+	0x6e, 0x2b, 0x77, 0xe4, 0x12, 0x80, 0xf9, 0x2e, 0xac, 0xa6, 0x46, 0x4a, 0xf2, 0x10, 0x97, 0xf6,
+	0x90, 0xdf, 0x83, 0xaa, 0xc5, 0x1a, 0xe6, 0x7f, 0x95, 0x61, 0xe3, 0x49, 0x80, 0xec, 0x08, 0x1d,
+	0xe3, 0x91, 0x3f, 0x44, 0x39, 0x66, 0x51, 0x39, 0x2d, 0x6d, 0x96, 0xd3, 0x2a, 0xce, 0xf5, 0x7c,
+	0x0e, 0x0b, 0xe2, 0xb6, 0xb3, 0x9a, 0xc9, 0x23, 0x39, 0x8c, 0x9d, 0x3a, 0x8f, 0xb8, 0x02, 0xc2,
+	0x19, 0x4c, 0x89, 0x0e, 0x2a, 0xd3, 0xa2, 0x83, 0x77, 0xa0, 0x7d, 0x8a, 0x50, 0x2f, 0xb0, 0x23,
+	0xd4, 0x0b, 0xed, 0xa8, 0xe7, 0x93, 0x23, 0xee, 0x73, 0xd3, 0xb7, 0x74, 0x8a, 0x90, 0x45, 0x26,
+	0x61, 0x47, 0x5d, 0x14, 0xec, 0xf7, 0xf5, 0x2f, 0x61, 0x9d, 0xb8, 0x23, 0x3d, 0xe6, 0x77, 0xf3,
+	0x3a, 0x97, 0x1d, 0xa1, 0xb3, 0x09, 0x15, 0xec, 0xe6, 0xce, 0x96, 0xbc, 0x00, 0x0f, 0xbb, 0xc7,
+	0x82, 0xf0, 0x98, 0xd3, 0x59, 0xab, 0x83, 0x3c, 0x30, 0xb9, 0x81, 0x4e, 0x30, 0xe9, 0x05, 0x63,
+	0x97, 0x5e, 0x81, 0xaa, 0x35, 0xef, 0x04, 0x13, 0x6b, 0xec, 0xa6, 0x9c, 0xd1, 0x6a, 0xda, 0x19,
+	0x35, 0x7e, 0x37, 0x2e, 0xab, 0x14, 0x4b, 0x4f, 0x62, 0x34, 0x4a, 0xb2, 0xd1, 0x30, 0xff, 0x47,
+	0x83, 0xcd, 0xc2, 0x6d, 0x9e, 0xd9, 0xeb, 0x54, 0x8a, 0x45, 0x23, 0x96, 0x8d, 0x27, 0xfe, 0x66,
+	0x59, 0x2a, 0x16, 0x31, 0x30, 0xc9, 0xfa, 0xc4, 0xa4, 0xb1, 0x36, 0x62, 0x87, 0x5f, 0xb7, 0x96,
+	0x05, 0x4a, 0x44, 0x20, 0x21, 0x89, 0x73, 0x59, 0x3c, 0x43, 0xbd, 0x59, 0xae, 0x4f, 0x81, 0x82,
+	0x58, 0xf9, 0x2a, 0x5b, 0xf7, 0xba, 0x03, 0x75, 0x1e, 0xdf, 0x30, 0x97, 0x6f, 0x9e, 0x39, 0x44,
+	0x0c, 0xc6, 0x5c, 0xbe, 0x7f, 0x2c, 0xc1, 0x12, 0x89, 0xb0, 0xba, 0x61, 0x3f, 0x76, 0x54, 0x74,
+	0xa8, 0xf8, 0x61, 0x3f, 0x12, 0x65, 0x23, 0xf2, 0x5b, 0x15, 0xf5, 0xd2, 0x2b, 0x8a, 0x7a, 0x59,
+	0x15, 0xf5, 0xff, 0xdf, 0xe2, 0x69, 0xee, 0x41, 0x2b, 0xd9, 0x31, 0x2e, 0x19, 0x79, 0x5b, 0x96,
+	0xde, 0xfd, 0x52, 0x76, 0xf7, 0x7f, 0xa6, 0x41, 0xfb, 0x13, 0xec, 0xd2, 0xf8, 0x48, 0x3e, 0x81,
+	0xeb, 0xa2, 0x2e, 0x31, 0x5c, 0xa9, 0xe8, 0x84, 0xca, 0xaf, 0x78, 0x42, 0x15, 0xe5, 0x84, 0xcc,
+	0x03, 0x58, 0x51, 0xa7, 0x35, 0x65, 0x99, 0xd7, 0x56, 0x83, 0xcd, 0x9f, 0x10, 0x7f, 0x18, 0xd9,
+	0x21, 0xe2, 0x7a, 0x8a, 0x2f, 0xf2, 0xb7, 0xf6, 0x3d, 0xde, 0x86, 0x96, 0x33, 0x66, 0x7e, 0x46,
+	0x4f, 0xa4, 0xc3, 0x98, 0x07, 0xb2, 0x24, 0xe0, 0xc7, 0x0c, 0x6c, 0xbe, 0x0f, 0x6d, 0x65, 0x06,
+	0x7c, 0x3d, 0xaa, 0xf7, 0xa2, 0xa5, 0xbd, 0x17, 0xf3, 0x05, 0x29, 0xd7, 0x0c, 0x5f, 0xff, 0xd4,
+	0x59, 0xb5, 0x66, 0x98, 0x9d, 0x91, 0xf9, 0x6b, 0x0d, 0x56, 0x58, 0xce, 0x3e, 0x55, 0xb0, 0xb9,
+	0xbe, 0xd4, 0x48, 0xf3, 0xc8, 0x2f, 0x23, 0x96, 0x4e, 0x94, 0x2c, 0x3d, 0x33, 0x92, 0xcb, 0x02,
+	0xd5, 0x8d, 0x2d, 0x7e, 0xb1, 0x21, 0x2f, 0x4f, 0x31, 0xe4, 0xdf, 0x87, 0x3a, 0xd7, 0xa5, 0xbd,
+	0x68, 0xe2, 0x33, 0xc7, 0xae, 0xb9, 0xb3, 0x26, 0x17, 0x1b, 0x19, 0xfa, 0x64, 0xe2, 0x23, 0xab,
+	0x66, 0x27, 0x0d, 0xf3, 0x2f, 0x4a, 0xb0, 0x9a, 0x5a, 0xdc, 0x2b, 0x15, 0x8d, 0x66, 0x29, 0x08,
+	0xbe, 0xfa, 0x35, 0xf8, 0xbf, 0xac, 0x0e, 0xde, 0x06, 0x90, 0x1c, 0x46, 0x96, 0x7b, 0x59, 0xbc,
+	0x12, 0xee, 0xa2, 0x19, 0xc0, 0x1a, 0x2f, 0xd0, 0x88, 0x43, 0x11, 0xc7, 0xad, 0x3a, 0x6b, 0x5a,
+	0xda, 0x59, 0x4b, 0x1f, 0x42, 0x69, 0xf6, 0x43, 0xb8, 0x11, 0x57, 0xaa, 0x92, 0x31, 0xb9, 0xf0,
+	0xfd, 0x7b, 0x09, 0x0c, 0x86, 0x3b, 0xb1, 0xfd, 0xc0, 0xf3, 0x22, 0x66, 0x7b, 0xbe, 0xb9, 0xbb,
+	0x73, 0x07, 0xea, 0xf2, 0x66, 0x09, 0xe5, 0x20, 0x6d, 0x93, 0xfe, 0x14, 0xe6, 0x87, 0xc8, 0xbe,
+	0x44, 0xc2, 0xed, 0x79, 0x57, 0xe2, 0x58, 0x3c, 0x97, 0xed, 0x13, 0xdb, 0x3f, 0x40, 0xf6, 0xa9,
+	0xc5, 0x3b, 0x13, 0xc7, 0x9b, 0x10, 0xb1, 0xcc, 0x00, 0xcf, 0x93, 0x11, 0x00, 0x4d, 0x0c, 0x90,
+	0x78, 0x1e, 0x07, 0xd1, 0xb9, 0x63, 0x4f, 0x44, 0x6e, 0x80, 0x15, 0x49, 0x9a, 0x02, 0xcc, 0x6b,
+	0xd4, 0x1f, 0xc3, 0x02, 0x67, 0x4c, 0xa6, 0x3e, 0x44, 0xf6, 0x69, 0x8f, 0x3f, 0xd7, 0xe1, 0xf2,
+	0x58, 0x23, 0x30, 0xfe, 0xd4, 0x47, 0x72, 0x69, 0x4b, 0x8a, 0x4b, 0xfb, 0x3d, 0xb8, 0x99, 0x3b,
+	0xf3, 0x6b, 0xab, 0x88, 0xbf, 0xd2, 0x44, 0xcf, 0x03, 0x74, 0x66, 0x0f, 0x26, 0xfb, 0x68, 0x12,
+	0x46, 0x5e, 0x80, 0x66, 0x35, 0x0b, 0x06, 0x54, 0x2f, 0x78, 0x17, 0x11, 0xd6, 0x88, 0xb6, 0xfe,
+	0x1e, 0xb4, 0xc5, 0xef, 0x6c, 0x19, 0x43, 0x17, 0x28, 0xa9, 0x96, 0xa1, 0x9c, 0x76, 0x65, 0x96,
+	0xd3, 0x4e, 0xca, 0x90, 0x73, 0x4a, 0x19, 0xf2, 0x2f, 0x2b, 0x70, 0x2b, 0x7f, 0x59, 0x7c, 0x47,
+	0x5e, 0xa7, 0x1f, 0xfd, 0x0c, 0x16, 0xf9, 0x76, 0xc6, 0x22, 0xf5, 0x5e, 0x46, 0xa4, 0xf2, 0xe7,
+	0x21, 0xee, 0x8c, 0x95, 0x70, 0x20, 0x36, 0x26, 0x16, 0x1d, 0x66, 0xb9, 0x1d, 0x1e, 0xf7, 0xc5,
+	0x22, 0xc5, 0xa2, 0x3b, 0x67, 0x66, 0x29, 0x23, 0x29, 0x88, 0x84, 0x90, 0xc8, 0x2b, 0x4f, 0x41,
+	0xc4, 0x64, 0x76, 0x78, 0x6e, 0xfc, 0x5a, 0x83, 0x85, 0xdd, 0x24, 0xe2, 0x29, 0xf6, 0x66, 0x25,
+	0x11, 0xac, 0x0a, 0x11, 0x24, 0xce, 0xe2, 0x29, 0x0e, 0x42, 0xfe, 0x38, 0x83, 0x67, 0xc2, 0x80,
+	0x82, 0x68, 0xe5, 0x2e, 0x6f, 0xb2, 0x95, 0xd9, 0x26, 0x3b, 0x97, 0x9d, 0x2c, 0x99, 0x20, 0x7a,
+	0x89, 0xc3, 0x08, 0x39, 0x5c, 0xc7, 0x89, 0x26, 0x89, 0xad, 0x50, 0x10, 0x78, 0x01, 0xf5, 0xe4,
+	0x17, 0x2d, 0xd6, 0x30, 0x5f, 0xc2, 0xfa, 0x01, 0x89, 0x49, 0x7f, 0xab, 0x54, 0x63, 0x12, 0xe6,
+	0x96, 0xe4, 0x30, 0xf7, 0x16, 0x2c, 0x7a, 0x97, 0x28, 0xb8, 0x0a, 0x70, 0x24, 0x22, 0xf8, 0x04,
+	0x40, 0xb3, 0x32, 0x99, 0x91, 0xb9, 0xfa, 0xeb, 0x42, 0xc3, 0xa2, 0x12, 0x2b, 0xd9, 0xdc, 0x3e,
+	0x3a, 0xc3, 0xae, 0xd8, 0x21, 0x8d, 0x27, 0x14, 0x09, 0xec, 0xb3, 0xf8, 0xd9, 0x54, 0x22, 0x6e,
+	0xc4, 0xd3, 0x5f, 0x94, 0xa4, 0x87, 0xbc, 0x41, 0x12, 0x1c, 0xf9, 0x18, 0x37, 0x60, 0xdd, 0x42,
+	0x03, 0x32, 0x9f, 0x49, 0x37, 0xf0, 0xce, 0xa4, 0x37, 0x0b, 0xe6, 0x6f, 0x34, 0xe8, 0x64, 0x71,
+	0x89, 0xa7, 0x12, 0x30, 0x9c, 0x78, 0x4a, 0x58, 0xb5, 0x24, 0x08, 0xb9, 0xfa, 0xa7, 0xd8, 0xc5,
+	0xe1, 0x39, 0x72, 0xb8, 0x28, 0xc4, 0x6d, 0xb2, 0x0c, 0x5a, 0xa2, 0x4e, 0xe5, 0x45, 0x29, 0x8c,
+	0x2f, 0x23, 0x79, 0x15, 0x56, 0x51, 0x5e, 0x85, 0x6d, 0x42, 0xad, 0x8f, 0xc2, 0x48, 0x95, 0x67,
+	0x20, 0x20, 0xde, 0xd1, 0x80, 0xaa, 0xcf, 0xe7, 0x4a, 0x8f, 0x5e, 0xb3, 0xe2, 0x36, 0xe9, 0x8c,
+	0x22, 0x3b, 0x76, 0xcd, 0x16, 0xb8, 0x7b, 0x15, 0xd9, 0xc2, 0x2b, 0x5b, 0x83, 0x95, 0x44, 0xe1,
+	0xec, 0x3b, 0xa7, 0x62, 0x27, 0xfe, 0x18, 0x56, 0x53, 0xf0, 0x58, 0x51, 0xcc, 0x33, 0x1b, 0xd8,
+	0xd1, 0xae, 0x29, 0xb1, 0x72, 0x3a, 0x7d, 0x07, 0x16, 0xf8, 0x5b, 0x89, 0x4e, 0xe9, 0x9a, 0x2e,
+	0x82, 0xd0, 0x3c, 0x07, 0x9d, 0xd4, 0x1d, 0x52, 0x09, 0x89, 0x59, 0x9e, 0x71, 0xe4, 0xa7, 0x32,
+	0xa5, 0x84, 0x45, 0x59, 0x49, 0x58, 0x98, 0xdf, 0x81, 0xb6, 0x32, 0x12, 0x5f, 0xa6, 0x92, 0xc7,
+	0xd0, 0xd2, 0x79, 0x8c, 0x5f, 0x6a, 0xd0, 0x7e, 0x6c, 0x5f, 0xa0, 0x67, 0xf6, 0xc0, 0x0e, 0xbc,
+	0xe4, 0xe6, 0x6c, 0x41, 0xcd, 0x47, 0xc1, 0x08, 0x87, 0x61, 0x5c, 0xb3, 0x5f, 0xb4, 0x64, 0x90,
+	0xbe, 0x01, 0x35, 0x6a, 0x24, 0x89, 0xb2, 0xc5, 0x4c, 0x4e, 0x2a, 0x16, 0xb5, 0x9b, 0xfb, 0x68,
+	0xb2, 0xe7, 0x10, 0xa7, 0x03, 0xfb, 0x3d, 0xb1, 0x0a, 0x36, 0xd7, 0x45, 0xec, 0x0b, 0x35, 0x44,
+	0xce, 0x93, 0xf8, 0xc6, 0x13, 0x56, 0x6d, 0xaa, 0x48, 0xee, 0xf2, 0x84, 0x16, 0x9b, 0x36, 0xa1,
+	0x46, 0xf3, 0x9f, 0xbd, 0x21, 0x1e, 0xe1, 0x88, 0x47, 0x66, 0x40, 0x41, 0x07, 0x04, 0x62, 0xee,
+	0xc0, 0x8a, 0x3a, 0x73, 0xbe, 0x60, 0x03, 0xaa, 0x23, 0x0e, 0xe3, 0xeb, 0x8d, 0xdb, 0xe6, 0x87,
+	0x70, 0xcb, 0x42, 0x97, 0x9e, 0xd4, 0x8b, 0xcd, 0x37, 0x39, 0x17, 0x65, 0x51, 0x5a, 0x6a, 0x51,
+	0xe6, 0x26, 0xdc, 0x2e, 0xe8, 0xcf, 0xaf, 0xe4, 0x6d, 0xb8, 0x49, 0xb2, 0xa9, 0x29, 0x74, 0x7c,
+	0x2d, 0x3f, 0x82, 0x5b, 0xf9, 0xe8, 0x38, 0x29, 0x50, 0x97, 0xc6, 0x67, 0xfb, 0x5e, 0xb1, 0x20,
+	0x9e, 0x40, 0x68, 0xde, 0x81, 0x4d, 0x49, 0xdd, 0x1c, 0x7a, 0x11, 0x3e, 0xc5, 0x03, 0x5b, 0x7e,
+	0x92, 0x62, 0xfe, 0xb4, 0x02, 0x5b, 0xc5, 0x34, 0x71, 0xae, 0x7e, 0xc9, 0x8e, 0x22, 0x7b, 0x70,
+	0x8e, 0x1c, 0xa6, 0xd7, 0xaf, 0x7d, 0x98, 0xd1, 0x14, 0xf4, 0x14, 0x1a, 0xb2, 0x1c, 0x9f, 0xca,
+	0xa1, 0x44, 0xf3, 0x0d, 0x4d, 0x07, 0x29, 0x84, 0x45, 0xcf, 0x37, 0xca, 0xdf, 0xf4, 0xf9, 0x06,
+	0x29, 0x3d, 0xe7, 0x70, 0xa4, 0xfa, 0x1d, 0xb1, 0xf7, 0xa4, 0x75, 0xab, 0x93, 0xed, 0xf8, 0x19,
+	0xc5, 0xeb, 0xdf, 0x86, 0x15, 0x74, 0x89, 0x07, 0x91, 0xda, 0x9b, 0xbc, 0x75, 0x21, 0xfd, 0xda,
+	0x1c, 0xa7, 0x0c, 0x18, 0xc1, 0x6a, 0x80, 0xfc, 0xa1, 0x3d, 0x48, 0xf7, 0x99, 0xa7, 0x6b, 0xf8,
+	0x61, 0xfe, 0x1a, 0x72, 0x77, 0x7e, 0xdb, 0xe2, 0x8c, 0x24, 0x42, 0x6b, 0x25, 0xc8, 0x02, 0x43,
+	0xe3, 0x73, 0x68, 0xe7, 0x10, 0xe7, 0xbe, 0xc4, 0xdd, 0x84, 0x5a, 0x3c, 0xc1, 0xbe, 0xf0, 0x8d,
+	0x41, 0x80, 0x1e, 0x4f, 0xcc, 0x3f, 0xd7, 0xe0, 0xf6, 0xb1, 0x8f, 0xdc, 0xc8, 0x45, 0x61, 0x98,
+	0x27, 0x36, 0x53, 0x5e, 0x11, 0x3c, 0x84, 0x65, 0xd7, 0xeb, 0xb9, 0xa4, 0xd3, 0xa4, 0x27, 0x2a,
+	0x3c, 0xcc, 0x30, 0x2c, 0xb9, 0x1e, 0x65, 0x36, 0x11, 0x55, 0x9b, 0x7b, 0xb0, 0x94, 0xd0, 0x32,
+	0x4a, 0x66, 0x37, 0x1b, 0x82, 0x92, 0xce, 0xc2, 0xfc, 0x69, 0x09, 0x36, 0x8a, 0xe6, 0xc3, 0x45,
+	0xf4, 0xf5, 0x16, 0xc5, 0xf7, 0x61, 0x81, 0x2a, 0x0f, 0x14, 0xf0, 0x48, 0x4e, 0xae, 0x80, 0x4c,
+	0x9f, 0x09, 0x45, 0x3b, 0x28, 0xb0, 0x04, 0x07, 0xe3, 0x39, 0x2c, 0x70, 0xd8, 0xab, 0xcc, 0x72,
+	0x13, 0x6a, 0xd8, 0x4d, 0x4f, 0x12, 0x92, 0x32, 0x35, 0xd1, 0x1e, 0xe2, 0x7d, 0x6f, 0xde, 0xc5,
+	0xfe, 0x6f, 0x0d, 0x6e, 0xe5, 0xe3, 0x5f, 0x7b, 0xe4, 0x9b, 0x1f, 0xc7, 0x96, 0x5f, 0x29, 0x8e,
+	0xad, 0xbc, 0xd2, 0x2b, 0xd7, 0xb9, 0x82, 0x57, 0xae, 0x7f, 0xaa, 0x41, 0x9b, 0xe5, 0x52, 0x5f,
+	0xd0, 0xe3, 0x92, 0x2a, 0xa0, 0x3c, 0xa8, 0xcd, 0x58, 0xd2, 0x16, 0x43, 0x48, 0xf1, 0xc7, 0xbb,
+	0xa0, 0x8b, 0xc7, 0x8f, 0x99, 0xd7, 0x59, 0xcb, 0x1c, 0xd3, 0x55, 0x52, 0x62, 0x21, 0x42, 0x0e,
+	0x0f, 0x68, 0xe8, 0x6f, 0xe2, 0x5f, 0xa8, 0xd3, 0xe0, 0x1a, 0xff, 0x23, 0x58, 0x3e, 0xf2, 0x91,
+	0xfb, 0xcd, 0x27, 0x67, 0xae, 0x80, 0x2e, 0x73, 0xe0, 0x7c, 0x57, 0x40, 0x7f, 0x32, 0xf4, 0x42,
+	0x75, 0xd5, 0xe6, 0x2a, 0xb4, 0x15, 0x28, 0x27, 0x5e, 0x85, 0x36, 0x83, 0x3c, 0x25, 0xae, 0x72,
+	0x2c, 0x30, 0xdb, 0xb0, 0xa2, 0x82, 0xb9, 0x9c, 0xac, 0xc1, 0x3c, 0xf5, 0xa9, 0x43, 0xee, 0xfc,
+	0xf1, 0x96, 0xf9, 0x67, 0x1a, 0xdc, 0xb1, 0xbc, 0xe1, 0xb0, 0x6f, 0x0f, 0x2e, 0x64, 0xbd, 0x89,
+	0x49, 0x68, 0x33, 0xf9, 0x46, 0x3b, 0x5f, 0xf4, 0x89, 0x80, 0x5a, 0x7e, 0x2f, 0xa7, 0xca, 0xef,
+	0xe6, 0x57, 0x60, 0x4e, 0x9b, 0x48, 0xb2, 0x0e, 0xc5, 0x9b, 0xce, 0x67, 0x9e, 0xae, 0xed, 0x9b,
+	0x7f, 0xaf, 0x41, 0xe7, 0x38, 0xb2, 0x83, 0xe8, 0x09, 0xe1, 0xe2, 0x86, 0xe3, 0xd0, 0xf2, 0x07,
+	0x62, 0x75, 0xf7, 0x61, 0x89, 0x3f, 0xdf, 0xef, 0xa9, 0x71, 0x52, 0x93, 0x83, 0x85, 0x07, 0x63,
+	0x40, 0x75, 0x1c, 0xa2, 0x40, 0xba, 0x41, 0x71, 0x9b, 0xe0, 0xc8, 0xde, 0x5c, 0x79, 0x81, 0x10,
+	0xa2, 0xb8, 0x4d, 0x5c, 0xab, 0x01, 0x0a, 0xf8, 0xf5, 0x45, 0x3c, 0xbf, 0x20, 0x83, 0xcc, 0x9b,
+	0x70, 0x23, 0x67, 0x7a, 0x6c, 0xcd, 0x0f, 0x5f, 0x42, 0x4d, 0x4a, 0xc7, 0x90, 0xb7, 0x78, 0xcf,
+	0x0f, 0xf7, 0x0f, 0x8f, 0x5e, 0x1c, 0xb6, 0xde, 0xd0, 0x97, 0xa0, 0xd6, 0x7d, 0xfe, 0x78, 0xff,
+	0xe9, 0x8f, 0x7a, 0x9f, 0xed, 0x1e, 0x7f, 0xd6, 0xd2, 0xf4, 0x0d, 0x30, 0x0e, 0x9f, 0x1e, 0x9f,
+	0x3c, 0xfd, 0xb8, 0xf7, 0x62, 0xef, 0xe4, 0xf0, 0xe9, 0xf1, 0x71, 0x4f, 0xc6, 0x97, 0xf4, 0x75,
+	0x68, 0xe7, 0x21, 0xca, 0xba, 0x0e, 0xcd, 0x93, 0xdd, 0xae, 0x75, 0x74, 0x74, 0xc2, 0x11, 0xad,
+	0xca, 0xc3, 0x47, 0xb0, 0x9a, 0x9b, 0xdb, 0x26, 0x73, 0x38, 0xd8, 0xb5, 0x3e, 0x7d, 0x7a, 0x7c,
+	0xc2, 0xde, 0x03, 0x5a, 0xbb, 0x87, 0x1f, 0x1f, 0x3d, 0x6b, 0x69, 0x3b, 0x56, 0xfc, 0x75, 0xd3,
+	0x31, 0x0a, 0x2e, 0xf1, 0x80, 0x3e, 0x11, 0xe0, 0x10, 0xfd, 0x86, 0xa4, 0x7f, 0xd5, 0x6f, 0xa0,
+	0x0c, 0x23, 0x0f, 0xc5, 0xd6, 0xbf, 0xf3, 0x9f, 0x06, 0x34, 0x98, 0x50, 0x0b, 0x9e, 0xdf, 0x83,
+	0x0a, 0xf9, 0x58, 0x43, 0x97, 0x33, 0x56, 0xd2, 0xc7, 0x1c, 0xc6, 0x7a, 0x06, 0x9e, 0xbc, 0x57,
+	0xe0, 0x1f, 0x65, 0x28, 0x93, 0x51, 0xbf, 0xf4, 0x30, 0x8c, 0x3c, 0x14, 0xe7, 0x60, 0x41, 0x43,
+	0xf9, 0x20, 0x43, 0xdf, 0xcc, 0x7e, 0x27, 0xa1, 0x7c, 0xe5, 0x61, 0x6c, 0x15, 0x13, 0x70, 0x9e,
+	0x4f, 0xa0, 0xca, 0x11, 0xa1, 0x6e, 0xe4, 0x7e, 0x76, 0xc1, 0x38, 0xdd, 0x9c, 0xf2, 0x49, 0x06,
+	0x59, 0x9a, 0xf8, 0x60, 0x41, 0x5e, 0x9a, 0xfa, 0x4a, 0xdb, 0x30, 0xf2, 0x50, 0xf1, 0xc3, 0x81,
+	0xa5, 0xd4, 0xbb, 0x5e, 0xfd, 0x8e, 0x44, 0x9e, 0xff, 0x1c, 0xda, 0x30, 0xa7, 0x91, 0x70, 0xce,
+	0xcf, 0xa1, 0xa9, 0xa2, 0xf4, 0xad, 0xc2, 0x5e, 0x82, 0xef, 0x9d, 0x29, 0x14, 0x9c, 0xed, 0x01,
+	0xd4, 0xa4, 0x37, 0x26, 0xfa, 0xed, 0xa2, 0xb7, 0x27, 0x8c, 0xe1, 0xc6, 0xf4, 0xa7, 0x29, 0xfa,
+	0x1f, 0xc2, 0x72, 0xe6, 0x59, 0x84, 0x7e, 0x77, 0xfa, 0xa3, 0x09, 0xc6, 0xf9, 0xcd, 0x59, 0x5e,
+	0x56, 0xe8, 0x07, 0xf1, 0x35, 0x26, 0xef, 0x0c, 0x94, 0xd9, 0x66, 0x1f, 0x2c, 0x18, 0x1b, 0x45,
+	0xe8, 0x44, 0x0e, 0x95, 0x72, 0xb4, 0x22, 0x87, 0x79, 0x25, 0x71, 0x63, 0xab, 0x98, 0x80, 0xf3,
+	0x1c, 0x43, 0xa7, 0xc8, 0x97, 0xd5, 0x1f, 0xce, 0xe4, 0xf0, 0xb2, 0x91, 0xbe, 0xf5, 0x0a, 0xce,
+	0xf1, 0x23, 0x4d, 0xf7, 0x60, 0x2d, 0xdf, 0x1b, 0xd3, 0x1f, 0xcc, 0xe0, 0xb0, 0xb1, 0x21, 0xdf,
+	0x9e, 0xd9, 0xb5, 0x7b, 0xa4, 0xe9, 0x38, 0xf9, 0x1e, 0x4b, 0x19, 0xee, 0x5e, 0xce, 0x4d, 0xcd,
+	0x1b, 0xec, 0xfe, 0xb5, 0x74, 0xf1, 0x50, 0x5f, 0x41, 0x2b, 0xfd, 0x3e, 0x5a, 0x37, 0xaf, 0x7f,
+	0x61, 0x6e, 0xdc, 0x9d, 0x4a, 0x93, 0xc8, 0x80, 0xf2, 0xd1, 0x8e, 0x22, 0x03, 0x79, 0x1f, 0x0a,
+	0x19, 0x5b, 0xc5, 0x04, 0x89, 0x94, 0x4a, 0x9f, 0xe5, 0x28, 0x52, 0x9a, 0xfd, 0x0e, 0xc8, 0xd8,
+	0x28, 0x42, 0xa7, 0xb8, 0x71, 0x03, 0x7a, 0x7b, 0xea, 0x67, 0x37, 0xc6, 0x46, 0x11, 0x9a, 0x73,
+	0xfb, 0x0a, 0x5a, 0xe9, 0x0f, 0x52, 0x94, 0xcd, 0x2c, 0xf8, 0x84, 0xc6, 0xb8, 0x3b, 0x95, 0x26,
+	0xd1, 0x7e, 0xa9, 0xb7, 0xc2, 0x8a, 0xf6, 0xcb, 0x7f, 0x88, 0x6d, 0x98, 0xd3, 0x48, 0x12, 0xce,
+	0xa9, 0x87, 0xa8, 0x0a, 0xe7, 0xfc, 0xa7, 0xb3, 0x86, 0x39, 0x8d, 0x84, 0x73, 0xb6, 0x41, 0xcf,
+	0xbe, 0x11, 0xd5, 0x65, 0x75, 0x54, 0xf8, 0x1c, 0xd5, 0x78, 0xeb, 0x1a, 0x2a, 0x3e, 0x84, 0x0f,
+	0xeb, 0x05, 0xef, 0x1a, 0xf4, 0xb7, 0x67, 0x7e, 0x62, 0x62, 0x3c, 0x9c, 0x85, 0x34, 0xb1, 0x86,
+	0xa2, 0x40, 0xae, 0x58, 0xc3, 0xd4, 0x3b, 0x03, 0xe3, 0x66, 0x2e, 0x8e, 0x33, 0x39, 0x82, 0xba,
+	0x5c, 0x82, 0xd6, 0x65, 0xd1, 0xca, 0x29, 0x99, 0x1b, 0x9b, 0x85, 0x78, 0xc9, 0xd6, 0x24, 0x05,
+	0x57, 0xd5, 0xd6, 0x64, 0x2a, 0xbc, 0xc6, 0x46, 0x11, 0x5a, 0xbe, 0xb9, 0x52, 0x01, 0x37, 0x75,
+	0x73, 0xb3, 0x35, 0x63, 0x63, 0xab, 0x98, 0x20, 0xe1, 0xa9, 0x54, 0x47, 0x15, 0x9e, 0x79, 0x45,
+	0x61, 0x63, 0xab, 0x98, 0x20, 0x11, 0xdd, 0x54, 0xb5, 0x4f, 0x11, 0xdd, 0xfc, 0xea, 0xa3, 0x61,
+	0x4e, 0x23, 0xe1, 0x9c, 0x1d, 0x68, 0xe7, 0x54, 0xb9, 0xf4, 0xb7, 0x66, 0xaa, 0xdf, 0x19, 0xf7,
+	0xae, 0x23, 0xe3, 0xa3, 0x9c, 0xc1, 0x4a, 0x5e, 0xc9, 0x46, 0xbf, 0x77, 0x6d, 0x4d, 0x27, 0xab,
+	0xe9, 0xa7, 0xd6, 0xa0, 0xbe, 0x82, 0x56, 0xba, 0x30, 0xa0, 0xa8, 0xa6, 0x82, 0x7a, 0x85, 0x71,
+	0x77, 0x2a, 0x0d, 0x67, 0xfe, 0xfb, 0x30, 0xcf, 0xea, 0x00, 0x7a, 0x47, 0x91, 0x02, 0xa9, 0xd8,
+	0x60, 0xdc, 0xc8, 0xc1, 0x24, 0x73, 0x4b, 0x17, 0x06, 0x94, 0xb9, 0x15, 0x54, 0x14, 0x8c, 0xbb,
+	0x53, 0x69, 0x12, 0xa9, 0x53, 0x92, 0xed, 0x8a, 0xd4, 0xe5, 0xa5, 0xe7, 0x8d, 0xad, 0x62, 0x82,
+	0xe4, 0xae, 0x49, 0x79, 0x6d, 0xe5, 0xae, 0x65, 0x33, 0xeb, 0xc6, 0x46, 0x11, 0x3a, 0x51, 0x05,
+	0x72, 0xd6, 0x58, 0x51, 0x05, 0x39, 0x89, 0x70, 0x63, 0xb3, 0x10, 0xcf, 0x19, 0xfe, 0x18, 0x56,
+	0x73, 0x53, 0xc2, 0xfa, 0x7d, 0x65, 0xc3, 0x8a, 0x93, 0xce, 0xc6, 0x83, 0xeb, 0x09, 0x13, 0x01,
+	0xce, 0x4b, 0x1f, 0xeb, 0xe9, 0x77, 0xd6, 0x05, 0xe9, 0x67, 0xe3, 0xfe, 0xb5, 0x74, 0x3c, 0xc8,
+	0xfa, 0x87, 0x8a, 0x48, 0x28, 0x1c, 0x78, 0xb6, 0x83, 0x02, 0x11, 0x6a, 0x1d, 0x41, 0x5d, 0x4e,
+	0x28, 0x28, 0xbb, 0x97, 0x93, 0x80, 0x30, 0x36, 0x0b, 0xf1, 0xc9, 0x71, 0xc8, 0x59, 0x15, 0x85,
+	0x61, 0x4e, 0xd6, 0xc7, 0xd8, 0x2c, 0xc4, 0x73, 0x86, 0x7b, 0x00, 0x49, 0x32, 0x45, 0xbf, 0x25,
+	0x91, 0x67, 0xb2, 0x34, 0xc6, 0xed, 0x02, 0x6c, 0x22, 0x78, 0x52, 0xae, 0x45, 0x11, 0xbc, 0x6c,
+	0x66, 0xc6, 0xd8, 0x28, 0x42, 0x27, 0x01, 0x45, 0x26, 0xa8, 0x57, 0x02, 0x8a, 0xa2, 0x8c, 0x84,
+	0xf1, 0xe6, 0x74, 0x22, 0xce, 0x7f, 0x02, 0x46, 0x71, 0xc6, 0x44, 0x7f, 0x47, 0x96, 0xb1, 0xeb,
+	0x32, 0x3c, 0xc6, 0xbb, 0x33, 0x52, 0xb3, 0xa1, 0xfb, 0xf3, 0xf4, 0x6f, 0x4d, 0xbe, 0xf3, 0xbf,
+	0x03, 0x00, 0x35, 0xf3, 0x67, 0x69, 0xe3, 0x44, 0x00, 0x00,
 }
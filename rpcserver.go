@@ -16,7 +16,9 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/internal/cfgutil"
 	"github.com/btcsuite/btcwallet/rpc/legacyrpc"
+	"github.com/btcsuite/btcwallet/rpc/macaroons"
 	"github.com/btcsuite/btcwallet/rpc/rpcserver"
 	"github.com/btcsuite/btcwallet/wallet"
 	"google.golang.org/grpc"
@@ -101,7 +103,9 @@ func generateRPCKeyPair(writeKey bool) (tls.Certificate, error) {
 	return keyPair, nil
 }
 
-func startRPCServers(walletLoader *wallet.Loader) (*grpc.Server, *legacyrpc.Server, error) {
+func startRPCServers(walletLoader *wallet.Loader,
+	macaroonService *macaroons.Service) (*grpc.Server, *legacyrpc.Server, error) {
+
 	var (
 		server       *grpc.Server
 		legacyServer *legacyrpc.Server
@@ -134,9 +138,25 @@ func startRPCServers(walletLoader *wallet.Loader) (*grpc.Server, *legacyrpc.Serv
 				return nil, nil, err
 			}
 			creds := credentials.NewServerTLSFromCert(&keyPair)
-			server = grpc.NewServer(grpc.Creds(creds))
+			opts := []grpc.ServerOption{grpc.Creds(creds)}
+			if macaroonService != nil {
+				macCfg := rpcserver.MacaroonInterceptorConfig(walletLoader)
+				opts = append(opts,
+					grpc.ChainUnaryInterceptor(
+						macaroonService.UnaryServerInterceptor(macCfg),
+					),
+					grpc.ChainStreamInterceptor(
+						macaroonService.StreamServerInterceptor(macCfg),
+					),
+				)
+			} else {
+				log.Warn("Macaroon authentication is disabled.  Any " +
+					"client may call every gRPC method")
+			}
+			server = grpc.NewServer(opts...)
 			rpcserver.StartVersionService(server)
-			rpcserver.StartWalletLoaderService(server, walletLoader, activeNet)
+			rpcserver.StartWalletLoaderService(server, walletLoader,
+				activeNet, macaroonService)
 			for _, lis := range listeners {
 				lis := lis
 				go func() {
@@ -150,8 +170,14 @@ func startRPCServers(walletLoader *wallet.Loader) (*grpc.Server, *legacyrpc.Serv
 		}
 	}
 
-	if cfg.Username == "" || cfg.Password == "" {
-		log.Info("Legacy RPC server disabled (requires username and password)")
+	users, err := legacyRPCUsers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if (cfg.Username == "" || cfg.Password == "") && len(users) == 0 {
+		log.Info("Legacy RPC server disabled (requires username and " +
+			"password or rpcauth users)")
 	} else if len(cfg.LegacyRPCListeners) != 0 {
 		listeners := makeListeners(cfg.LegacyRPCListeners, legacyListen)
 		if len(listeners) == 0 {
@@ -161,6 +187,7 @@ func startRPCServers(walletLoader *wallet.Loader) (*grpc.Server, *legacyrpc.Serv
 		opts := legacyrpc.Options{
 			Username:            cfg.Username,
 			Password:            cfg.Password,
+			Users:               users,
 			MaxPOSTClients:      cfg.LegacyRPCMaxClients,
 			MaxWebsocketClients: cfg.LegacyRPCMaxWebsockets,
 		}
@@ -175,6 +202,28 @@ func startRPCServers(walletLoader *wallet.Loader) (*grpc.Server, *legacyrpc.Serv
 	return server, legacyServer, nil
 }
 
+// legacyRPCUsers parses the users configured with the rpcauth option.
+func legacyRPCUsers() ([]*legacyrpc.User, error) {
+	users := make([]*legacyrpc.User, 0, len(cfg.RPCAuth))
+	seen := make(map[string]struct{}, len(cfg.RPCAuth)+1)
+	if cfg.Username != "" && cfg.Password != "" {
+		seen[cfg.Username] = struct{}{}
+	}
+	for _, s := range cfg.RPCAuth {
+		user, err := legacyrpc.ParseUser(s)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[user.Name]; ok {
+			return nil, fmt.Errorf("duplicate legacy RPC user %s",
+				user.Name)
+		}
+		seen[user.Name] = struct{}{}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
 type listenFunc func(net string, laddr string) (net.Listener, error)
 
 // makeListeners splits the normalized listen addresses into IPv4 and IPv6
@@ -241,13 +290,69 @@ func makeListeners(normalizedListenAddrs []string, listen listenFunc) []net.List
 
 // startWalletRPCServices associates each of the (optionally-nil) RPC servers
 // with a wallet to enable remote wallet access.  For the GRPC server, this
-// registers the WalletService service, and for the legacy JSON-RPC server it
-// enables methods that require a loaded wallet.
-func startWalletRPCServices(wallet *wallet.Wallet, server *grpc.Server, legacyServer *legacyrpc.Server) {
+// registers the WalletService service and, unless macaroons are disabled, loads
+// the macaroon root keys from the wallet database.  For the legacy JSON-RPC
+// server it enables methods that require a loaded wallet.  An error is
+// returned if the macaroon root keys can't be loaded, in which case no service
+// is started.
+func startWalletRPCServices(wallet *wallet.Wallet, server *grpc.Server,
+	macaroonService *macaroons.Service, legacyServer *legacyrpc.Server) error {
+
 	if server != nil {
-		rpcserver.StartWalletService(server, wallet)
+		if macaroonService != nil {
+			err := initMacaroons(wallet, macaroonService)
+			if err != nil {
+				return fmt.Errorf("unable to initialize "+
+					"macaroons: %w", err)
+			}
+		}
+		rpcserver.StartWalletService(server, wallet, macaroonService)
 	}
 	if legacyServer != nil {
 		legacyServer.RegisterWallet(wallet)
 	}
+	return nil
+}
+
+// initMacaroons sets the root key store of the macaroon service to the wallet
+// database and writes the default admin and read-only macaroons to the network
+// directory if they do not exist yet.
+func initMacaroons(w *wallet.Wallet, macaroonService *macaroons.Service) error {
+	store, err := macaroons.NewRootKeyStore(w.Database())
+	if err != nil {
+		return err
+	}
+	macaroonService.SetRootKeyStore(store)
+
+	netDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
+	defaultMacaroons := []struct {
+		path     string
+		entities []string
+	}{
+		{filepath.Join(netDir, adminMacaroonFilename), macaroons.AllEntities},
+		{filepath.Join(netDir, readOnlyMacaroonFilename),
+			[]string{macaroons.EntityRead}},
+	}
+	for _, m := range defaultMacaroons {
+		exists, err := cfgutil.FileExists(m.path)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		mac, err := macaroonService.Bake(0, m.entities)
+		if err != nil {
+			return err
+		}
+		b, err := mac.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(m.path, b, 0600); err != nil {
+			return err
+		}
+		log.Infof("Wrote macaroon %s", m.path)
+	}
+	return nil
 }
@@ -14,6 +14,7 @@ import (
 	"runtime/debug"
 
 	"github.com/btcsuite/btcwallet/internal/zero"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/nacl/secretbox"
 	"golang.org/x/crypto/scrypt"
 )
@@ -27,6 +28,7 @@ var (
 	ErrInvalidPassword = errors.New("invalid password")
 	ErrMalformed       = errors.New("malformed data")
 	ErrDecryptFailed   = errors.New("unable to decrypt")
+	ErrInvalidParams   = errors.New("invalid key derivation parameters")
 )
 
 // Various constants needed for encryption scheme.
@@ -38,6 +40,81 @@ const (
 	DefaultN  = 16384 // 2^14
 	DefaultR  = 8
 	DefaultP  = 1
+
+	// The default Argon2id parameters follow the second recommended
+	// option of RFC 9106: 3 passes over 64 MiB with 4 lanes.
+	DefaultArgon2Time    = 3
+	DefaultArgon2Memory  = 64 * 1024 // KiB
+	DefaultArgon2Threads = 4
+)
+
+// Limits of the key derivation parameters.  Parameters beyond them are
+// rejected, both when creating a key and when unmarshalling stored ones, so
+// that a passphrase can't be protected by a key which takes hours or all the
+// memory of the machine to derive again.
+const (
+	// MaxMemoryKiB is the most memory, in KiB, that deriving a key may
+	// use, 4 GiB.  scrypt uses 128 * N * R bytes.
+	MaxMemoryKiB = 4 << 20
+
+	// MaxArgon2Time is the largest number of Argon2id passes.
+	MaxArgon2Time = 1 << 12
+
+	// MaxScryptN is the largest scrypt CPU/memory cost.
+	MaxScryptN = 1 << 22
+
+	// MaxScryptP is the largest scrypt parallelization.
+	MaxScryptP = 16
+)
+
+// KDF identifies the key derivation function deriving a secret key from a
+// passphrase.
+type KDF uint8
+
+// These constants define the supported key derivation functions.  They are
+// part of the marshalled parameters, so they must remain stable.
+const (
+	// KDFScrypt derives the key with scrypt, using the N, R and P
+	// parameters.
+	KDFScrypt KDF = 0
+
+	// KDFArgon2id derives the key with Argon2id, using the Time, Memory
+	// and Threads parameters.
+	KDFArgon2id KDF = 1
+)
+
+// String returns the name of the key derivation function.
+func (k KDF) String() string {
+	switch k {
+	case KDFScrypt:
+		return "scrypt"
+	case KDFArgon2id:
+		return "argon2id"
+	default:
+		return "unknown"
+	}
+}
+
+// paramsVersion is the version of the versioned marshalled parameters.  The
+// original format, which is only used for scrypt, has no version.
+const paramsVersion = 1
+
+// Sizes of the marshalled parameters.
+const (
+	// legacyParamsSize is the size of the original, unversioned format.
+	legacyParamsSize = KeySize + sha256.Size + 24
+
+	// versionedHeaderSize is the size of the common part of the versioned
+	// format.
+	versionedHeaderSize = 2 + KeySize + sha256.Size
+
+	// scryptParamsSize is the size of the scrypt parameters in the
+	// versioned format.
+	scryptParamsSize = 24
+
+	// argon2ParamsSize is the size of the Argon2id parameters in the
+	// versioned format.
+	argon2ParamsSize = 9
 )
 
 // CryptoKey represents a secret key which can be used to encrypt and decrypt
@@ -97,9 +174,46 @@ func GenerateCryptoKey() (*CryptoKey, error) {
 type Parameters struct {
 	Salt   [KeySize]byte
 	Digest [sha256.Size]byte
-	N      int
-	R      int
-	P      int
+	KDF    KDF
+
+	// N, R and P are the scrypt parameters.
+	N int
+	R int
+	P int
+
+	// Time, Memory (in KiB) and Threads are the Argon2id parameters.
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// validate checks that the key derivation parameters can be used.
+func (p *Parameters) validate() error {
+	switch p.KDF {
+	case KDFScrypt:
+		// scrypt checks the rest of its parameters itself.
+		if p.N <= 1 || p.R <= 0 || p.P <= 0 {
+			return ErrInvalidParams
+		}
+		if p.N > MaxScryptN || p.P > MaxScryptP ||
+			p.R > MaxMemoryKiB*1024/128/p.N {
+
+			return ErrInvalidParams
+		}
+		return nil
+
+	case KDFArgon2id:
+		if p.Time == 0 || p.Threads == 0 {
+			return ErrInvalidParams
+		}
+		if p.Time > MaxArgon2Time || p.Memory > MaxMemoryKiB {
+			return ErrInvalidParams
+		}
+		return nil
+
+	default:
+		return ErrInvalidParams
+	}
 }
 
 // SecretKey houses a crypto key and the parameters needed to derive it from a
@@ -111,14 +225,30 @@ type SecretKey struct {
 
 // deriveKey fills out the Key field.
 func (sk *SecretKey) deriveKey(password *[]byte) error {
-	key, err := scrypt.Key(*password, sk.Parameters.Salt[:],
-		sk.Parameters.N,
-		sk.Parameters.R,
-		sk.Parameters.P,
-		len(sk.Key))
-	if err != nil {
+	if err := sk.Parameters.validate(); err != nil {
 		return err
 	}
+
+	var key []byte
+	switch sk.Parameters.KDF {
+	case KDFArgon2id:
+		key = argon2.IDKey(*password, sk.Parameters.Salt[:],
+			sk.Parameters.Time,
+			sk.Parameters.Memory,
+			sk.Parameters.Threads,
+			uint32(len(sk.Key)))
+
+	default:
+		var err error
+		key, err = scrypt.Key(*password, sk.Parameters.Salt[:],
+			sk.Parameters.N,
+			sk.Parameters.R,
+			sk.Parameters.P,
+			len(sk.Key))
+		if err != nil {
+			return err
+		}
+	}
 	copy(sk.Key[:], key)
 	zero.Bytes(key)
 
@@ -127,7 +257,8 @@ func (sk *SecretKey) deriveKey(password *[]byte) error {
 	// between means you end up needing twice the amount of memory.  For
 	// example, if your scrypt parameters are such that you require 1GB and
 	// you call it twice in a row, without this you end up allocating 2GB
-	// since the first GB probably hasn't been released yet.
+	// since the first GB probably hasn't been released yet.  The same
+	// holds for the memory of Argon2id.
 	debug.FreeOSMemory()
 
 	return nil
@@ -135,55 +266,135 @@ func (sk *SecretKey) deriveKey(password *[]byte) error {
 
 // Marshal returns the Parameters field marshalled into a format suitable for
 // storage.  This result of this can be stored in clear text.
+//
+// The parameters of scrypt keys are marshalled in the original format, so that
+// they remain readable by older versions:
+//
+//	<salt><digest><N><R><P>
+//
+// KeySize + sha256.Size + N (8 bytes) + R (8 bytes) + P (8 bytes)
+//
+// The parameters of the other key derivation functions are marshalled in the
+// versioned format, which differs in size from the original format:
+//
+//	<version><kdf><salt><digest><kdf parameters>
+//
+// 1 byte version + 1 byte kdf + KeySize + sha256.Size + kdf parameters, which
+// for Argon2id are Time (4 bytes) + Memory (4 bytes) + Threads (1 byte).
 func (sk *SecretKey) Marshal() []byte {
 	params := &sk.Parameters
 
-	// The marshalled format for the the params is as follows:
-	//   <salt><digest><N><R><P>
-	//
-	// KeySize + sha256.Size + N (8 bytes) + R (8 bytes) + P (8 bytes)
-	marshalled := make([]byte, KeySize+sha256.Size+24)
+	if params.KDF == KDFScrypt {
+		marshalled := make([]byte, legacyParamsSize)
+
+		b := marshalled
+		copy(b[:KeySize], params.Salt[:])
+		b = b[KeySize:]
+		copy(b[:sha256.Size], params.Digest[:])
+		b = b[sha256.Size:]
+		putScryptParams(b, params)
+
+		return marshalled
+	}
+
+	marshalled := make([]byte, versionedHeaderSize+argon2ParamsSize)
 
 	b := marshalled
+	b[0] = paramsVersion
+	b[1] = byte(params.KDF)
+	b = b[2:]
 	copy(b[:KeySize], params.Salt[:])
 	b = b[KeySize:]
 	copy(b[:sha256.Size], params.Digest[:])
 	b = b[sha256.Size:]
+	binary.LittleEndian.PutUint32(b[:4], params.Time)
+	b = b[4:]
+	binary.LittleEndian.PutUint32(b[:4], params.Memory)
+	b = b[4:]
+	b[0] = params.Threads
+
+	return marshalled
+}
+
+// putScryptParams marshals the scrypt parameters.
+func putScryptParams(b []byte, params *Parameters) {
 	binary.LittleEndian.PutUint64(b[:8], uint64(params.N))
 	b = b[8:]
 	binary.LittleEndian.PutUint64(b[:8], uint64(params.R))
 	b = b[8:]
 	binary.LittleEndian.PutUint64(b[:8], uint64(params.P))
+}
 
-	return marshalled
+// readScryptParams unmarshals the scrypt parameters.
+func readScryptParams(b []byte, params *Parameters) {
+	params.N = int(binary.LittleEndian.Uint64(b[:8]))
+	b = b[8:]
+	params.R = int(binary.LittleEndian.Uint64(b[:8]))
+	b = b[8:]
+	params.P = int(binary.LittleEndian.Uint64(b[:8]))
 }
 
 // Unmarshal unmarshalls the parameters needed to derive the secret key from a
-// passphrase into sk.
+// passphrase into sk.  Both the original and the versioned formats described
+// in Marshal are accepted.
 func (sk *SecretKey) Unmarshal(marshalled []byte) error {
 	if sk.Key == nil {
 		sk.Key = (*CryptoKey)(&[KeySize]byte{})
 	}
 
-	// The marshalled format for the the params is as follows:
-	//   <salt><digest><N><R><P>
-	//
-	// KeySize + sha256.Size + N (8 bytes) + R (8 bytes) + P (8 bytes)
-	if len(marshalled) != KeySize+sha256.Size+24 {
-		return ErrMalformed
+	var params Parameters
+	if len(marshalled) == legacyParamsSize {
+		copy(params.Salt[:], marshalled[:KeySize])
+		marshalled = marshalled[KeySize:]
+		copy(params.Digest[:], marshalled[:sha256.Size])
+		marshalled = marshalled[sha256.Size:]
+		params.KDF = KDFScrypt
+		readScryptParams(marshalled, &params)
+		if err := params.validate(); err != nil {
+			return ErrMalformed
+		}
+
+		sk.Parameters = params
+		return nil
 	}
 
-	params := &sk.Parameters
+	if len(marshalled) < versionedHeaderSize ||
+		marshalled[0] != paramsVersion {
+
+		return ErrMalformed
+	}
+	params.KDF = KDF(marshalled[1])
+	marshalled = marshalled[2:]
 	copy(params.Salt[:], marshalled[:KeySize])
 	marshalled = marshalled[KeySize:]
 	copy(params.Digest[:], marshalled[:sha256.Size])
 	marshalled = marshalled[sha256.Size:]
-	params.N = int(binary.LittleEndian.Uint64(marshalled[:8]))
-	marshalled = marshalled[8:]
-	params.R = int(binary.LittleEndian.Uint64(marshalled[:8]))
-	marshalled = marshalled[8:]
-	params.P = int(binary.LittleEndian.Uint64(marshalled[:8]))
 
+	switch params.KDF {
+	case KDFScrypt:
+		if len(marshalled) != scryptParamsSize {
+			return ErrMalformed
+		}
+		readScryptParams(marshalled, &params)
+
+	case KDFArgon2id:
+		if len(marshalled) != argon2ParamsSize {
+			return ErrMalformed
+		}
+		params.Time = binary.LittleEndian.Uint32(marshalled[:4])
+		marshalled = marshalled[4:]
+		params.Memory = binary.LittleEndian.Uint32(marshalled[:4])
+		marshalled = marshalled[4:]
+		params.Threads = marshalled[0]
+
+	default:
+		return ErrMalformed
+	}
+	if err := params.validate(); err != nil {
+		return ErrMalformed
+	}
+
+	sk.Parameters = params
 	return nil
 }
 
@@ -221,15 +432,42 @@ func (sk *SecretKey) Decrypt(in []byte) ([]byte, error) {
 	return sk.Key.Decrypt(in)
 }
 
-// NewSecretKey returns a SecretKey structure based on the passed parameters.
+// NewSecretKey returns a SecretKey structure based on the passed scrypt
+// parameters.
 func NewSecretKey(password *[]byte, N, r, p int) (*SecretKey, error) { // nolint:gocritic
+	return newSecretKey(password, Parameters{
+		KDF: KDFScrypt,
+		N:   N,
+		R:   r,
+		P:   p,
+	})
+}
+
+// NewArgon2idSecretKey returns a SecretKey structure derived with Argon2id,
+// using the passed number of passes over the passed amount of memory in KiB,
+// with the passed number of threads.
+func NewArgon2idSecretKey(password *[]byte, time, memory uint32,
+	threads uint8) (*SecretKey, error) {
+
+	return newSecretKey(password, Parameters{
+		KDF:     KDFArgon2id,
+		Time:    time,
+		Memory:  memory,
+		Threads: threads,
+	})
+}
+
+// newSecretKey returns a SecretKey structure derived with the passed key
+// derivation parameters and a new random salt.
+func newSecretKey(password *[]byte, params Parameters) (*SecretKey, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
 	sk := SecretKey{
-		Key: (*CryptoKey)(&[KeySize]byte{}),
+		Key:        (*CryptoKey)(&[KeySize]byte{}),
+		Parameters: params,
 	}
-	// setup parameters
-	sk.Parameters.N = N
-	sk.Parameters.R = r
-	sk.Parameters.P = p
 	_, err := io.ReadFull(prng, sk.Parameters.Salt[:])
 	if err != nil {
 		return nil, err
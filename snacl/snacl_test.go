@@ -114,3 +114,146 @@ func TestDeriveKeyInvalid(t *testing.T) {
 		t.Errorf("unexpected DeriveKey key failure: %v", err)
 	}
 }
+
+func TestArgon2idSecretKey(t *testing.T) {
+	sk, err := NewArgon2idSecretKey(&password, 1, 64, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The scrypt parameters keep their original format, which the
+	// versioned format must not be confused with.
+	if len(params) != legacyParamsSize {
+		t.Fatalf("scrypt params size %d, want %d", len(params),
+			legacyParamsSize)
+	}
+	marshalled := sk.Marshal()
+	if len(marshalled) == legacyParamsSize {
+		t.Fatalf("argon2id params marshalled in the original format")
+	}
+
+	var sk2 SecretKey
+	if err := sk2.Unmarshal(marshalled); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if sk2.Parameters != sk.Parameters {
+		t.Fatalf("params not equal: got %v, want %v", sk2.Parameters,
+			sk.Parameters)
+	}
+
+	p := []byte("wrong password")
+	if err := sk2.DeriveKey(&p); err != ErrInvalidPassword {
+		t.Fatalf("wrong password didn't fail: %v", err)
+	}
+	if err := sk2.DeriveKey(&password); err != nil {
+		t.Fatalf("unexpected DeriveKey error: %v", err)
+	}
+	if !bytes.Equal(sk2.Key[:], sk.Key[:]) {
+		t.Fatalf("keys not equal")
+	}
+
+	// An argon2id key derived from the same password differs from the
+	// scrypt key.
+	if bytes.Equal(sk.Key[:], key.Key[:]) {
+		t.Fatalf("argon2id key equals scrypt key")
+	}
+}
+
+func TestUnmarshalSecretKeyMalformed(t *testing.T) {
+	sk, err := NewArgon2idSecretKey(&password, 1, 64, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	valid := sk.Marshal()
+
+	tests := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{"empty", func([]byte) []byte { return nil }},
+		{"truncated", func(b []byte) []byte { return b[:len(b)-1] }},
+		{"version", func(b []byte) []byte { b[0] = 2; return b }},
+		{"kdf", func(b []byte) []byte { b[1] = 9; return b }},
+		{"time", func(b []byte) []byte {
+			copy(b[versionedHeaderSize:], []byte{0, 0, 0, 0})
+			return b
+		}},
+		{"threads", func(b []byte) []byte {
+			b[len(b)-1] = 0
+			return b
+		}},
+	}
+	for _, test := range tests {
+		b := test.modify(append([]byte(nil), valid...))
+
+		var sk SecretKey
+		if err := sk.Unmarshal(b); err != ErrMalformed {
+			t.Errorf("%s: got %v, want %v", test.name, err,
+				ErrMalformed)
+		}
+	}
+
+	if _, err := NewArgon2idSecretKey(&password, 0, 64, 1); err !=
+		ErrInvalidParams {
+
+		t.Errorf("zero time: got %v, want %v", err, ErrInvalidParams)
+	}
+}
+
+// TestParameterLimits ensures that key derivation parameters beyond the limits
+// are rejected both when creating a key and when unmarshalling one.
+func TestParameterLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Parameters
+	}{
+		{"argon2id time", Parameters{
+			KDF: KDFArgon2id, Time: MaxArgon2Time + 1, Memory: 64,
+			Threads: 1,
+		}},
+		{"argon2id memory", Parameters{
+			KDF: KDFArgon2id, Time: 1, Memory: MaxMemoryKiB + 1,
+			Threads: 1,
+		}},
+		{"scrypt N", Parameters{
+			KDF: KDFScrypt, N: MaxScryptN * 2, R: 1, P: 1,
+		}},
+		{"scrypt p", Parameters{
+			KDF: KDFScrypt, N: DefaultN, R: 1, P: MaxScryptP + 1,
+		}},
+		{"scrypt memory", Parameters{
+			KDF: KDFScrypt, N: MaxScryptN, R: 16, P: 1,
+		}},
+		{"scrypt r", Parameters{
+			KDF: KDFScrypt, N: DefaultN, R: 0, P: 1,
+		}},
+	}
+	for _, test := range tests {
+		_, err := newSecretKey(&password, test.params)
+		if err != ErrInvalidParams {
+			t.Errorf("%s: got %v, want %v", test.name, err,
+				ErrInvalidParams)
+		}
+
+		// Stored parameters beyond the limits are malformed.
+		sk := SecretKey{Parameters: test.params}
+		if err := new(SecretKey).Unmarshal(sk.Marshal()); err !=
+			ErrMalformed {
+
+			t.Errorf("%s: unmarshal got %v, want %v", test.name,
+				err, ErrMalformed)
+		}
+	}
+
+	// The limits themselves are allowed.
+	limits := []Parameters{
+		{KDF: KDFArgon2id, Time: MaxArgon2Time, Memory: MaxMemoryKiB,
+			Threads: 1},
+		{KDF: KDFScrypt, N: MaxScryptN, R: 4, P: MaxScryptP},
+	}
+	for _, params := range limits {
+		if err := params.validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", params, err)
+		}
+	}
+}
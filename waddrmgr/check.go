@@ -0,0 +1,515 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package waddrmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// checkAccount houses the fields of an account row which the indexes are
+// checked against.
+type checkAccount struct {
+	name      string
+	nextIndex [2]uint32
+}
+
+// checkScope houses the primary records of a scope, which the derived indexes
+// are checked against and rebuilt from.
+type checkScope struct {
+	scope    KeyScope
+	accounts map[uint32]*checkAccount
+	addrs    map[string]uint32
+}
+
+// problemf formats a problem found in the scope.
+func (s *checkScope) problemf(format string, args ...interface{}) string {
+	return fmt.Sprintf("scope %v: %s", s.scope, fmt.Sprintf(format, args...))
+}
+
+// loadCheckScope reads the account and address rows of a scope bucket.  Rows
+// which can't be deserialized are reported as problems and left out.
+func loadCheckScope(scope KeyScope,
+	bucket walletdb.ReadBucket) (*checkScope, []string, error) {
+
+	s := &checkScope{
+		scope:    scope,
+		accounts: make(map[uint32]*checkAccount),
+		addrs:    make(map[string]uint32),
+	}
+	var problems []string
+
+	acctBucket := bucket.NestedReadBucket(acctBucketName)
+	if acctBucket == nil {
+		return nil, nil, managerError(ErrDatabase, fmt.Sprintf(
+			"scope %v: missing account bucket", scope), nil)
+	}
+	err := acctBucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+		if len(k) != 4 {
+			problems = append(problems, s.problemf(
+				"malformed account key %x", k))
+			return nil
+		}
+		account := binary.LittleEndian.Uint32(k)
+
+		row, err := deserializeAccountRow(k, v)
+		if err != nil {
+			problems = append(problems, s.problemf(
+				"account %d: %v", account, err))
+			return nil
+		}
+
+		acct := &checkAccount{}
+		switch row.acctType {
+		case accountDefault:
+			r, err := deserializeDefaultAccountRow(k, row)
+			if err != nil {
+				problems = append(problems, s.problemf(
+					"account %d: %v", account, err))
+				return nil
+			}
+			acct.name = r.name
+			acct.nextIndex = [2]uint32{
+				r.nextExternalIndex, r.nextInternalIndex,
+			}
+
+		case accountWatchOnly:
+			r, err := deserializeWatchOnlyAccountRow(k, row)
+			if err != nil {
+				problems = append(problems, s.problemf(
+					"account %d: %v", account, err))
+				return nil
+			}
+			acct.name = r.name
+			acct.nextIndex = [2]uint32{
+				r.nextExternalIndex, r.nextInternalIndex,
+			}
+
+		default:
+			problems = append(problems, s.problemf(
+				"account %d: unsupported account type '%d'",
+				account, row.acctType))
+			return nil
+		}
+		s.accounts[account] = acct
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	addrBucket := bucket.NestedReadBucket(addrBucketName)
+	if addrBucket == nil {
+		return nil, nil, managerError(ErrDatabase, fmt.Sprintf(
+			"scope %v: missing address bucket", scope), nil)
+	}
+	err = addrBucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+
+		row, err := deserializeAddressRow(v)
+		if err != nil {
+			problems = append(problems, s.problemf(
+				"address %x: %v", k, err))
+			return nil
+		}
+		s.addrs[string(k)] = row.account
+
+		acct, ok := s.accounts[row.account]
+		if !ok {
+			problems = append(problems, s.problemf(
+				"address %x belongs to unknown account %d", k,
+				row.account))
+			return nil
+		}
+		if row.addrType != adtChain {
+			return nil
+		}
+
+		chained, err := deserializeChainedAddress(row)
+		if err != nil {
+			problems = append(problems, s.problemf(
+				"address %x: %v", k, err))
+			return nil
+		}
+		switch {
+		case chained.branch != ExternalBranch &&
+			chained.branch != InternalBranch:
+
+			problems = append(problems, s.problemf(
+				"address %x has unknown branch %d", k,
+				chained.branch))
+
+		case chained.index >= acct.nextIndex[chained.branch]:
+			problems = append(problems, s.problemf(
+				"address %x has index %d/%d/%d beyond the "+
+					"next index %d", k, row.account,
+				chained.branch, chained.index,
+				acct.nextIndex[chained.branch]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, problems, nil
+}
+
+// checkIndexes checks the derived indexes of a scope against its primary
+// records.
+func (s *checkScope) checkIndexes(ns,
+	bucket walletdb.ReadBucket) ([]string, error) {
+
+	var problems []string
+
+	// Every account other than the imported account must be below the
+	// last account.
+	lastAccount, err := fetchLastAccount(ns, &s.scope)
+	if err == nil {
+		for account := range s.accounts {
+			if account != ImportedAddrAccount &&
+				account > lastAccount {
+
+				problems = append(problems, s.problemf(
+					"account %d is beyond the last "+
+						"account %d", account,
+					lastAccount))
+			}
+		}
+	} else {
+		problems = append(problems, s.problemf("%v", err))
+	}
+
+	// The account name index maps the name of each account to its
+	// number.
+	seen := make(map[uint32]bool)
+	err = forEachIndexEntry(bucket, acctNameIdxBucketName, func(k,
+		v []byte) error {
+
+		if len(v) != 4 || len(k) < 4 ||
+			uint32(len(k)-4) != binary.LittleEndian.Uint32(k) {
+
+			problems = append(problems, s.problemf(
+				"malformed account name index entry %x", k))
+			return nil
+		}
+		account := binary.LittleEndian.Uint32(v)
+		name := string(k[4:])
+
+		acct, ok := s.accounts[account]
+		switch {
+		case !ok:
+			problems = append(problems, s.problemf("account name "+
+				"index maps %q to unknown account %d", name,
+				account))
+		case acct.name != name:
+			problems = append(problems, s.problemf("account name "+
+				"index maps %q to account %d named %q", name,
+				account, acct.name))
+		default:
+			seen[account] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for account, acct := range s.accounts {
+		if !seen[account] {
+			problems = append(problems, s.problemf("account %d "+
+				"(%q) missing from the account name index",
+				account, acct.name))
+		}
+	}
+
+	// The account ID index maps the number of each account to its name.
+	seen = make(map[uint32]bool)
+	err = forEachIndexEntry(bucket, acctIDIdxBucketName, func(k,
+		v []byte) error {
+
+		if len(k) != 4 || len(v) < 4 ||
+			uint32(len(v)-4) != binary.LittleEndian.Uint32(v) {
+
+			problems = append(problems, s.problemf(
+				"malformed account ID index entry %x", k))
+			return nil
+		}
+		account := binary.LittleEndian.Uint32(k)
+		name := string(v[4:])
+
+		acct, ok := s.accounts[account]
+		switch {
+		case !ok:
+			problems = append(problems, s.problemf("account ID "+
+				"index holds unknown account %d", account))
+		case acct.name != name:
+			problems = append(problems, s.problemf("account ID "+
+				"index names account %d %q instead of %q",
+				account, name, acct.name))
+		default:
+			seen[account] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for account := range s.accounts {
+		if !seen[account] {
+			problems = append(problems, s.problemf("account %d "+
+				"missing from the account ID index", account))
+		}
+	}
+
+	// The address account index maps each address to its account, and
+	// holds a bucket per account listing its addresses.
+	seenAddrs := make(map[string]bool)
+	seenAcctAddrs := make(map[string]bool)
+	idxBucket := bucket.NestedReadBucket(addrAcctIdxBucketName)
+	if idxBucket == nil {
+		return nil, managerError(ErrDatabase, s.problemf(
+			"missing address account index"), nil)
+	}
+	err = idxBucket.ForEach(func(k, v []byte) error {
+		if v != nil {
+			if len(v) != 4 {
+				problems = append(problems, s.problemf(
+					"malformed address account index "+
+						"entry %x", k))
+				return nil
+			}
+			account := binary.LittleEndian.Uint32(v)
+			addrAccount, ok := s.addrs[string(k)]
+			switch {
+			case !ok:
+				problems = append(problems, s.problemf(
+					"address account index holds "+
+						"unknown address %x", k))
+			case addrAccount != account:
+				problems = append(problems, s.problemf(
+					"address account index maps "+
+						"address %x to account %d "+
+						"instead of %d", k, account,
+					addrAccount))
+			default:
+				seenAddrs[string(k)] = true
+			}
+			return nil
+		}
+
+		if len(k) != 4 {
+			problems = append(problems, s.problemf(
+				"malformed address account index bucket %x",
+				k))
+			return nil
+		}
+		account := binary.LittleEndian.Uint32(k)
+		acctBucket := idxBucket.NestedReadBucket(k)
+		return acctBucket.ForEach(func(addr, _ []byte) error {
+			addrAccount, ok := s.addrs[string(addr)]
+			switch {
+			case !ok:
+				problems = append(problems, s.problemf(
+					"address account index lists "+
+						"unknown address %x under "+
+						"account %d", addr, account))
+			case addrAccount != account:
+				problems = append(problems, s.problemf(
+					"address account index lists "+
+						"address %x under account %d "+
+						"instead of %d", addr, account,
+					addrAccount))
+			default:
+				seenAcctAddrs[string(addr)] = true
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for addr := range s.addrs {
+		if !seenAddrs[addr] || !seenAcctAddrs[addr] {
+			problems = append(problems, s.problemf("address %x "+
+				"missing from the address account index",
+				[]byte(addr)))
+		}
+	}
+
+	// The used addresses must be known.
+	err = forEachIndexEntry(bucket, usedAddrBucketName, func(k,
+		_ []byte) error {
+
+		if _, ok := s.addrs[string(k)]; !ok {
+			problems = append(problems, s.problemf("unknown "+
+				"address %x flagged as used", k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// repairIndexes rebuilds the derived indexes of a scope from its primary
+// records, and removes the used flags of unknown addresses.
+func (s *checkScope) repairIndexes(ns,
+	bucket walletdb.ReadWriteBucket) error {
+	for _, name := range [][]byte{
+		acctNameIdxBucketName, acctIDIdxBucketName,
+		addrAcctIdxBucketName,
+	} {
+		err := bucket.DeleteNestedBucket(name)
+		if err != nil && err != walletdb.ErrBucketNotFound {
+			return err
+		}
+		if _, err := bucket.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	for account, acct := range s.accounts {
+		err := putAccountNameIndex(ns, &s.scope, account, acct.name)
+		if err != nil {
+			return err
+		}
+		err = putAccountIDIndex(ns, &s.scope, account, acct.name)
+		if err != nil {
+			return err
+		}
+	}
+	for addr, account := range s.addrs {
+		err := putAddrAccountIndex(ns, &s.scope, account, []byte(addr))
+		if err != nil {
+			return err
+		}
+	}
+
+	usedBucket := bucket.NestedReadWriteBucket(usedAddrBucketName)
+	if usedBucket == nil {
+		return nil
+	}
+	var unknown [][]byte
+	err := usedBucket.ForEach(func(k, _ []byte) error {
+		if _, ok := s.addrs[string(k)]; !ok {
+			unknown = append(unknown, bytes.Clone(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range unknown {
+		if err := usedBucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// forEachIndexEntry calls fn for each key/value pair of the named bucket of a
+// scope, if it exists.
+func forEachIndexEntry(bucket walletdb.ReadBucket, name []byte,
+	fn func(k, v []byte) error) error {
+
+	idxBucket := bucket.NestedReadBucket(name)
+	if idxBucket == nil {
+		return nil
+	}
+	return idxBucket.ForEach(fn)
+}
+
+// forEachScopeBucket calls fn for each scope bucket of the namespace.
+func forEachScopeBucket(ns walletdb.ReadBucket,
+	fn func(scope KeyScope, key []byte) error) error {
+
+	scopeBucket := ns.NestedReadBucket(scopeBucketName)
+	if scopeBucket == nil {
+		str := "missing scope bucket"
+		return managerError(ErrDatabase, str, nil)
+	}
+
+	var keys [][]byte
+	err := scopeBucket.ForEach(func(k, _ []byte) error {
+		if len(k) == scopeKeySize {
+			keys = append(keys, bytes.Clone(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		scope := KeyScope{
+			Purpose: binary.LittleEndian.Uint32(k[:4]),
+			Coin:    binary.LittleEndian.Uint32(k[4:]),
+		}
+		if err := fn(scope, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckIndexes checks that the indexes of the address manager stored in the
+// given namespace agree with the account and address records they're derived
+// from, without opening the manager.  A description of each problem found is
+// returned.
+func CheckIndexes(ns walletdb.ReadBucket) ([]string, error) {
+	var problems []string
+	err := forEachScopeBucket(ns, func(scope KeyScope, key []byte) error {
+		bucket := ns.NestedReadBucket(scopeBucketName).
+			NestedReadBucket(key)
+
+		s, scopeProblems, err := loadCheckScope(scope, bucket)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, scopeProblems...)
+
+		scopeProblems, err = s.checkIndexes(ns, bucket)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, scopeProblems...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// RepairIndexes rebuilds the account name, account ID and address account
+// indexes of the address manager stored in the given namespace from its
+// account and address records.  Problems with the records themselves can't be
+// repaired, and are reported again by CheckIndexes.
+func RepairIndexes(ns walletdb.ReadWriteBucket) error {
+	return forEachScopeBucket(ns, func(scope KeyScope, key []byte) error {
+		bucket := ns.NestedReadWriteBucket(scopeBucketName).
+			NestedReadWriteBucket(key)
+
+		s, _, err := loadCheckScope(scope, bucket)
+		if err != nil {
+			return err
+		}
+		return s.repairIndexes(ns, bucket)
+	})
+}
@@ -0,0 +1,103 @@
+package waddrmgr
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckRepairIndexes ensures that inconsistencies between the account and
+// address records and their indexes are found, and that the indexes are
+// rebuilt from the records.
+func TestCheckRepairIndexes(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	scopedMgr, err := mgr.FetchScopedKeyManager(KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	var addrHash []byte
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		require.NoError(t, mgr.Unlock(ns, privPassphrase))
+
+		account, err := scopedMgr.NewAccount(ns, "test")
+		require.NoError(t, err)
+
+		addrs, err := scopedMgr.NextExternalAddresses(ns, account, 3)
+		require.NoError(t, err)
+		_, err = scopedMgr.NextInternalAddresses(ns, 0, 2)
+		require.NoError(t, err)
+
+		hash := sha256.Sum256(addrs[0].Address().ScriptAddress())
+		addrHash = hash[:]
+		return nil
+	})
+	require.NoError(t, err)
+
+	check := func() []string {
+		var problems []string
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			var err error
+			ns := tx.ReadBucket(waddrmgrNamespaceKey)
+			problems, err = CheckIndexes(ns)
+			return err
+		})
+		require.NoError(t, err)
+		return problems
+	}
+	require.Empty(t, check())
+
+	// Corrupt the indexes of the scope.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		scopeKey := scopeToBytes(&KeyScopeBIP0084)
+		bucket := ns.NestedReadWriteBucket(scopeBucketName).
+			NestedReadWriteBucket(scopeKey[:])
+
+		err := bucket.NestedReadWriteBucket(acctIDIdxBucketName).
+			Delete(uint32ToBytes(1))
+		require.NoError(t, err)
+
+		err = bucket.NestedReadWriteBucket(acctNameIdxBucketName).
+			Put(stringToBytes("other"), uint32ToBytes(1))
+		require.NoError(t, err)
+
+		err = bucket.NestedReadWriteBucket(addrAcctIdxBucketName).
+			Put(addrHash, uint32ToBytes(0))
+		require.NoError(t, err)
+
+		return bucket.NestedReadWriteBucket(usedAddrBucketName).
+			Put(make([]byte, 32), nullVal)
+	})
+	require.NoError(t, err)
+
+	problems := check()
+	require.Len(t, problems, 5)
+	require.Contains(t, problems[0], "account 1 missing from the account "+
+		"ID index")
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return RepairIndexes(tx.ReadWriteBucket(waddrmgrNamespaceKey))
+	})
+	require.NoError(t, err)
+	require.Empty(t, check())
+
+	// The wallet is still usable with the rebuilt indexes.
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		account, err := scopedMgr.LookupAccount(ns, "test")
+		require.NoError(t, err)
+		require.EqualValues(t, 1, account)
+
+		name, err := scopedMgr.AccountName(ns, 1)
+		require.NoError(t, err)
+		require.Equal(t, "test", name)
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -13,7 +13,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/walletdb"
 )
 
@@ -198,6 +201,7 @@ var (
 	// scopeBucket -> scope -> acctBucket
 	// scopeBucket -> scope -> addrBucket
 	// scopeBucket -> scope -> usedAddrBucket
+	// scopeBucket -> scope -> addrLabelBucket
 	// scopeBucket -> scope -> addrAcctIdxBucket
 	// scopeBucket -> scope -> acctNameIdxBucket
 	// scopeBucket -> scope -> acctIDIdxBucketName
@@ -261,6 +265,12 @@ var (
 	// addresses hash if the address has been used or not.
 	usedAddrBucketName = []byte("usedaddrs")
 
+	// addrLabelBucketName is the name of the bucket that maps an address
+	// hash to a user-defined label for that address. The bucket is only
+	// created once the first label is written, so older databases don't
+	// require a migration.
+	addrLabelBucketName = []byte("addrlabels")
+
 	// meta is used to store meta-data about the address manager
 	// e.g. last account number
 	metaBucketName = []byte("meta")
@@ -307,6 +317,8 @@ var (
 	birthdayName              = []byte("birthday")
 	birthdayBlockName         = []byte("birthdayblock")
 	birthdayBlockVerifiedName = []byte("birthdayblockverified")
+	rescanCheckpointName      = []byte("rescancheckpoint")
+	recoveryCheckpointName    = []byte("recoverycheckpoint")
 )
 
 // uint32ToBytes converts a 32 bit unsigned integer into a 4-byte slice in
@@ -1675,6 +1687,58 @@ func markAddressUsed(ns walletdb.ReadWriteBucket, scope *KeyScope,
 	return nil
 }
 
+// fetchAddrLabel returns the label stored for the provided address id. An
+// empty string is returned if the address has not been labelled.
+func fetchAddrLabel(ns walletdb.ReadBucket, scope *KeyScope,
+	addressID []byte) (string, error) {
+
+	scopedBucket, err := fetchReadScopeBucket(ns, scope)
+	if err != nil {
+		return "", err
+	}
+
+	bucket := scopedBucket.NestedReadBucket(addrLabelBucketName)
+	if bucket == nil {
+		return "", nil
+	}
+
+	addrHash := sha256.Sum256(addressID)
+	return string(bucket.Get(addrHash[:])), nil
+}
+
+// putAddrLabel stores the label for the provided address id. An empty label
+// removes any label previously stored for the address.
+func putAddrLabel(ns walletdb.ReadWriteBucket, scope *KeyScope,
+	addressID []byte, label string) error {
+
+	scopedBucket, err := fetchWriteScopeBucket(ns, scope)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := scopedBucket.CreateBucketIfNotExists(
+		addrLabelBucketName,
+	)
+	if err != nil {
+		str := "failed to create address label bucket"
+		return managerError(ErrDatabase, str, err)
+	}
+
+	addrHash := sha256.Sum256(addressID)
+	if label == "" {
+		err = bucket.Delete(addrHash[:])
+	} else {
+		err = bucket.Put(addrHash[:], []byte(label))
+	}
+	if err != nil {
+		str := fmt.Sprintf("failed to store label for address %x",
+			addressID)
+		return managerError(ErrDatabase, str, err)
+	}
+
+	return nil
+}
+
 // fetchAddress loads address information for the provided address id from the
 // database.  The returned value is one of the address rows for the specific
 // address type.  The caller should use type assertions to ascertain the type.
@@ -2294,6 +2358,83 @@ func deleteBlockHash(ns walletdb.ReadWriteBucket, height int32) error {
 	return nil
 }
 
+// FetchBlockHeight returns the height of the block with the given hash among
+// the recent blocks whose hashes are stored in the database.
+func FetchBlockHeight(ns walletdb.ReadBucket,
+	hash *chainhash.Hash) (int32, error) {
+
+	bucket := ns.NestedReadBucket(syncBucketName)
+	errStr := fmt.Sprintf("failed to fetch height of block %v", hash)
+
+	// Block hashes are keyed by their 4 byte height, unlike the other
+	// values of the bucket.
+	height := int32(-1)
+	err := bucket.ForEach(func(k, v []byte) error {
+		if len(k) == 4 && bytes.Equal(v, hash[:]) {
+			height = int32(binary.BigEndian.Uint32(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, managerError(ErrDatabase, errStr, err)
+	}
+	if height < 0 {
+		err := errors.New("block not found")
+		return 0, managerError(ErrBlockNotFound, errStr, err)
+	}
+
+	return height, nil
+}
+
+// RollbackSyncedTo marks the manager as synced to the block at the given
+// height, removing the hashes of the blocks after it.  The hash of the block
+// must still be stored, i.e. it must be one of the last MaxReorgDepth blocks,
+// and it can't be before the birthday block.  A zero timestamp is stored as
+// unknown.  The new synced to blockstamp is returned.
+func RollbackSyncedTo(ns walletdb.ReadWriteBucket, height int32,
+	timestamp time.Time) (*BlockStamp, error) {
+
+	errStr := fmt.Sprintf("failed to roll back to height %d", height)
+
+	syncedTo, err := fetchSyncedTo(ns)
+	if err != nil {
+		return nil, err
+	}
+	if height > syncedTo.Height {
+		err := fmt.Errorf("wallet is only synced to height %d",
+			syncedTo.Height)
+		return nil, managerError(ErrBlockNotFound, errStr, err)
+	}
+	birthdayBlock, err := FetchBirthdayBlock(ns)
+	if err == nil && height < birthdayBlock.Height {
+		err := fmt.Errorf("birthday block is at height %d",
+			birthdayBlock.Height)
+		return nil, managerError(ErrBlockNotFound, errStr, err)
+	}
+
+	hash, err := fetchBlockHash(ns, height)
+	if err != nil {
+		return nil, err
+	}
+
+	for h := height + 1; h <= syncedTo.Height; h++ {
+		if err := deleteBlockHash(ns, h); err != nil {
+			return nil, err
+		}
+	}
+
+	bs := &BlockStamp{
+		Height:    height,
+		Hash:      *hash,
+		Timestamp: timestamp,
+	}
+	if err := updateSyncedTo(ns, bs); err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
 // updateSyncedTo updates the value behind the syncedToName key to the given
 // block.
 func updateSyncedTo(ns walletdb.ReadWriteBucket, bs *BlockStamp) error {
@@ -2301,15 +2442,21 @@ func updateSyncedTo(ns walletdb.ReadWriteBucket, bs *BlockStamp) error {
 	//   <blockheight><blockhash><timestamp>
 	//
 	// 4 bytes block height + 32 bytes hash length + 4 byte timestamp length
+	//
+	// An unknown timestamp is left out.
 	var serializedStamp [40]byte
 	binary.LittleEndian.PutUint32(serializedStamp[0:4], uint32(bs.Height))
 	copy(serializedStamp[4:36], bs.Hash[0:32])
 	binary.LittleEndian.PutUint32(
 		serializedStamp[36:], uint32(bs.Timestamp.Unix()),
 	)
+	stamp := serializedStamp[:]
+	if bs.Timestamp.IsZero() {
+		stamp = stamp[:36]
+	}
 
 	bucket := ns.NestedReadWriteBucket(syncBucketName)
-	if err := bucket.Put(syncedToName, serializedStamp[:]); err != nil {
+	if err := bucket.Put(syncedToName, stamp); err != nil {
 		errStr := "failed to update synced to value"
 		return managerError(ErrDatabase, errStr, err)
 	}
@@ -2458,6 +2605,213 @@ func PutBirthdayBlock(ns walletdb.ReadWriteBucket, block BlockStamp) error {
 	return nil
 }
 
+// FetchRescanCheckpoint retrieves the last block processed by an unfinished
+// rescan from the database. The returned bool is false if no rescan is in
+// progress.
+//
+// The block is serialized as follows:
+//   [0:4]   block height
+//   [4:36]  block hash
+//   [36:44] block timestamp
+func FetchRescanCheckpoint(ns walletdb.ReadBucket) (BlockStamp, bool, error) {
+	var block BlockStamp
+
+	bucket := ns.NestedReadBucket(syncBucketName)
+	checkpoint := bucket.Get(rescanCheckpointName)
+	if checkpoint == nil {
+		return block, false, nil
+	}
+	if len(checkpoint) != 44 {
+		str := "malformed rescan checkpoint stored in database"
+		return block, false, managerError(ErrDatabase, str, nil)
+	}
+
+	block.Height = int32(binary.BigEndian.Uint32(checkpoint[:4]))
+	copy(block.Hash[:], checkpoint[4:36])
+	t := int64(binary.BigEndian.Uint64(checkpoint[36:]))
+	block.Timestamp = time.Unix(t, 0)
+
+	return block, true, nil
+}
+
+// PutRescanCheckpoint stores the last block processed by an unfinished rescan
+// to the database.
+//
+// The block is serialized as follows:
+//   [0:4]   block height
+//   [4:36]  block hash
+//   [36:44] block timestamp
+func PutRescanCheckpoint(ns walletdb.ReadWriteBucket, block BlockStamp) error {
+	var checkpoint [44]byte
+	binary.BigEndian.PutUint32(checkpoint[:4], uint32(block.Height))
+	copy(checkpoint[4:36], block.Hash[:])
+	binary.BigEndian.PutUint64(checkpoint[36:], uint64(block.Timestamp.Unix()))
+
+	bucket := ns.NestedReadWriteBucket(syncBucketName)
+	if err := bucket.Put(rescanCheckpointName, checkpoint[:]); err != nil {
+		str := "failed to store rescan checkpoint"
+		return managerError(ErrDatabase, str, err)
+	}
+
+	return nil
+}
+
+// DeleteRescanCheckpoint removes the rescan checkpoint from the database.
+func DeleteRescanCheckpoint(ns walletdb.ReadWriteBucket) error {
+	bucket := ns.NestedReadWriteBucket(syncBucketName)
+	if err := bucket.Delete(rescanCheckpointName); err != nil {
+		str := "failed to remove rescan checkpoint"
+		return managerError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// FetchRecoveryCheckpoint retrieves the progress of an unfinished wallet
+// recovery from the database. The returned bool is false if no recovery is in
+// progress.
+//
+// The checkpoint is serialized as follows:
+//   [0:4]   last block height
+//   [4:36]  last block hash
+//   [36:44] last block timestamp
+//   [44:48] number of key scopes
+//   for each key scope:
+//     [0:4]   purpose
+//     [4:8]   coin type
+//     [8:12]  next unfound external index
+//     [12:16] next unfound internal index
+//   [0:4]   number of watched outpoints
+//   for each watched outpoint:
+//     [0:32]  hash
+//     [32:36] index
+//     [36:38] address length
+//     [38:]   encoded address
+func FetchRecoveryCheckpoint(ns walletdb.ReadBucket,
+	chainParams *chaincfg.Params) (*RecoveryCheckpoint, bool, error) {
+
+	bucket := ns.NestedReadBucket(syncBucketName)
+	buf := bucket.Get(recoveryCheckpointName)
+	if buf == nil {
+		return nil, false, nil
+	}
+
+	str := "malformed recovery checkpoint stored in database"
+	if len(buf) < 48 {
+		return nil, false, managerError(ErrDatabase, str, nil)
+	}
+
+	checkpoint := &RecoveryCheckpoint{
+		Scopes:           make(map[KeyScope]ScopeRecoveryProgress),
+		WatchedOutPoints: make(map[wire.OutPoint]btcutil.Address),
+	}
+	checkpoint.Block.Height = int32(binary.BigEndian.Uint32(buf[:4]))
+	copy(checkpoint.Block.Hash[:], buf[4:36])
+	t := int64(binary.BigEndian.Uint64(buf[36:44]))
+	checkpoint.Block.Timestamp = time.Unix(t, 0)
+
+	numScopes := binary.BigEndian.Uint32(buf[44:48])
+	buf = buf[48:]
+	for i := uint32(0); i < numScopes; i++ {
+		if len(buf) < 16 {
+			return nil, false, managerError(ErrDatabase, str, nil)
+		}
+
+		scope := KeyScope{
+			Purpose: binary.BigEndian.Uint32(buf[:4]),
+			Coin:    binary.BigEndian.Uint32(buf[4:8]),
+		}
+		checkpoint.Scopes[scope] = ScopeRecoveryProgress{
+			ExternalNextUnfound: binary.BigEndian.Uint32(buf[8:12]),
+			InternalNextUnfound: binary.BigEndian.Uint32(buf[12:16]),
+		}
+		buf = buf[16:]
+	}
+
+	if len(buf) < 4 {
+		return nil, false, managerError(ErrDatabase, str, nil)
+	}
+	numOutPoints := binary.BigEndian.Uint32(buf[:4])
+	buf = buf[4:]
+	for i := uint32(0); i < numOutPoints; i++ {
+		if len(buf) < 38 {
+			return nil, false, managerError(ErrDatabase, str, nil)
+		}
+
+		var op wire.OutPoint
+		copy(op.Hash[:], buf[:32])
+		op.Index = binary.BigEndian.Uint32(buf[32:36])
+		addrLen := int(binary.BigEndian.Uint16(buf[36:38]))
+		buf = buf[38:]
+		if len(buf) < addrLen {
+			return nil, false, managerError(ErrDatabase, str, nil)
+		}
+
+		addr, err := btcutil.DecodeAddress(
+			string(buf[:addrLen]), chainParams,
+		)
+		if err != nil {
+			return nil, false, managerError(ErrDatabase, str, err)
+		}
+		checkpoint.WatchedOutPoints[op] = addr
+		buf = buf[addrLen:]
+	}
+
+	return checkpoint, true, nil
+}
+
+// PutRecoveryCheckpoint stores the progress of an unfinished wallet recovery
+// to the database. See FetchRecoveryCheckpoint for its serialization.
+func PutRecoveryCheckpoint(ns walletdb.ReadWriteBucket,
+	checkpoint *RecoveryCheckpoint) error {
+
+	buf := make([]byte, 48)
+	block := checkpoint.Block
+	binary.BigEndian.PutUint32(buf[:4], uint32(block.Height))
+	copy(buf[4:36], block.Hash[:])
+	binary.BigEndian.PutUint64(buf[36:44], uint64(block.Timestamp.Unix()))
+
+	binary.BigEndian.PutUint32(buf[44:48], uint32(len(checkpoint.Scopes)))
+	for scope, progress := range checkpoint.Scopes {
+		buf = binary.BigEndian.AppendUint32(buf, scope.Purpose)
+		buf = binary.BigEndian.AppendUint32(buf, scope.Coin)
+		buf = binary.BigEndian.AppendUint32(
+			buf, progress.ExternalNextUnfound,
+		)
+		buf = binary.BigEndian.AppendUint32(
+			buf, progress.InternalNextUnfound,
+		)
+	}
+
+	buf = binary.BigEndian.AppendUint32(
+		buf, uint32(len(checkpoint.WatchedOutPoints)),
+	)
+	for op, addr := range checkpoint.WatchedOutPoints {
+		encodedAddr := addr.EncodeAddress()
+		buf = append(buf, op.Hash[:]...)
+		buf = binary.BigEndian.AppendUint32(buf, op.Index)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(encodedAddr)))
+		buf = append(buf, encodedAddr...)
+	}
+
+	bucket := ns.NestedReadWriteBucket(syncBucketName)
+	if err := bucket.Put(recoveryCheckpointName, buf); err != nil {
+		str := "failed to store recovery checkpoint"
+		return managerError(ErrDatabase, str, err)
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCheckpoint removes the recovery checkpoint from the database.
+func DeleteRecoveryCheckpoint(ns walletdb.ReadWriteBucket) error {
+	bucket := ns.NestedReadWriteBucket(syncBucketName)
+	if err := bucket.Delete(recoveryCheckpointName); err != nil {
+		str := "failed to remove recovery checkpoint"
+		return managerError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // fetchBirthdayBlockVerification retrieves the bit that determines whether the
 // wallet has verified that its birthday block is correct.
 func fetchBirthdayBlockVerification(ns walletdb.ReadBucket) bool {
@@ -4,9 +4,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
 )
 
 // TestStoreMaxReorgDepth ensures that we can only store up to MaxReorgDepth
@@ -129,3 +133,147 @@ func TestStoreMaxReorgDepth(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestRecoveryCheckpoint ensures that the progress of a recovery is stored and
+// retrieved from the database until it is cleared.
+func TestRecoveryCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), mgr.ChainParams(),
+	)
+	require.NoError(t, err)
+
+	checkpoint := &RecoveryCheckpoint{
+		Block: BlockStamp{
+			Height:    1000,
+			Hash:      chainhash.Hash{1},
+			Timestamp: time.Unix(1600000000, 0),
+		},
+		Scopes: map[KeyScope]ScopeRecoveryProgress{
+			KeyScopeBIP0084: {
+				ExternalNextUnfound: 12,
+				InternalNextUnfound: 3,
+			},
+			KeyScopeBIP0086: {},
+		},
+		WatchedOutPoints: map[wire.OutPoint]btcutil.Address{
+			{Hash: chainhash.Hash{2}, Index: 1}: addr,
+		},
+	}
+
+	fetchCheckpoint := func() (*RecoveryCheckpoint, bool) {
+		var (
+			checkpoint *RecoveryCheckpoint
+			inProgress bool
+		)
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(waddrmgrNamespaceKey)
+
+			var err error
+			checkpoint, inProgress, err = mgr.RecoveryCheckpoint(ns)
+			return err
+		})
+		require.NoError(t, err)
+
+		return checkpoint, inProgress
+	}
+
+	_, inProgress := fetchCheckpoint()
+	require.False(t, inProgress)
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return mgr.SetRecoveryCheckpoint(ns, checkpoint)
+	})
+	require.NoError(t, err)
+
+	stored, inProgress := fetchCheckpoint()
+	require.True(t, inProgress)
+	require.Equal(t, checkpoint, stored)
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return mgr.SetRecoveryCheckpoint(ns, nil)
+	})
+	require.NoError(t, err)
+
+	_, inProgress = fetchCheckpoint()
+	require.False(t, inProgress)
+}
+
+// TestRollbackSyncedTo ensures that the manager can be marked as synced to one
+// of its recent blocks, and that the hashes of the later blocks are removed.
+func TestRollbackSyncedTo(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, _ := setupManager(t)
+	defer teardown()
+
+	blockHash := func(height int32) chainhash.Hash {
+		var hash chainhash.Hash
+		binary.BigEndian.PutUint32(hash[:], uint32(height))
+		return hash
+	}
+
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		for height := int32(100); height <= 110; height++ {
+			err := PutSyncedTo(ns, &BlockStamp{
+				Hash:      blockHash(height),
+				Height:    height,
+				Timestamp: time.Unix(int64(height), 0),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return PutBirthdayBlock(ns, BlockStamp{
+			Hash:   blockHash(102),
+			Height: 102,
+		})
+	})
+	require.NoError(t, err)
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		// Blocks before the birthday block, after the synced to block,
+		// or whose hash isn't known can't be rolled back to.
+		_, err := RollbackSyncedTo(ns, 101, time.Time{})
+		require.True(t, IsError(err, ErrBlockNotFound))
+		_, err = RollbackSyncedTo(ns, 111, time.Time{})
+		require.True(t, IsError(err, ErrBlockNotFound))
+		unknown := blockHash(111)
+		_, err = FetchBlockHeight(ns, &unknown)
+		require.True(t, IsError(err, ErrBlockNotFound))
+
+		hash := blockHash(105)
+		height, err := FetchBlockHeight(ns, &hash)
+		require.NoError(t, err)
+		require.EqualValues(t, 105, height)
+
+		bs, err := RollbackSyncedTo(ns, height, time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, hash, bs.Hash)
+
+		syncedTo, err := fetchSyncedTo(ns)
+		require.NoError(t, err)
+		require.Equal(t, *bs, *syncedTo)
+
+		_, err = fetchBlockHash(ns, 105)
+		require.NoError(t, err)
+		_, err = fetchBlockHash(ns, 106)
+		require.True(t, IsError(err, ErrBlockNotFound))
+
+		// The chain can be extended again from the synced to block.
+		return PutSyncedTo(ns, &BlockStamp{
+			Hash:   blockHash(106),
+			Height: 106,
+		})
+	})
+	require.NoError(t, err)
+}
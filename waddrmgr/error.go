@@ -143,6 +143,10 @@ const (
 	// ErrAccountNotCached is returned when we attempt to perform an
 	// operation that relies on an account begin cached but it isn't.
 	ErrAccountNotCached
+
+	// ErrInvalidLabel is returned when a label exceeds the maximum
+	// allowed length.
+	ErrInvalidLabel
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -170,6 +174,7 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrEmptyPassphrase:   "ErrEmptyPassphrase",
 	ErrScopeNotFound:     "ErrScopeNotFound",
 	ErrAccountNotCached:  "ErrAccountNotCached",
+	ErrInvalidLabel:      "ErrInvalidLabel",
 }
 
 // String returns the ErrorCode as a human-readable name.
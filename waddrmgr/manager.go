@@ -8,6 +8,7 @@ import (
 	"crypto/rand"
 	"crypto/sha512"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -39,6 +40,10 @@ const (
 	// not fit into that model.
 	ImportedAddrAccount = MaxAccountNum + 1 // 2^31 - 1
 
+	// MaxAddrLabelLen is the maximum length of a user-defined address
+	// label. It matches the limit wtxmgr places on transaction labels.
+	MaxAddrLabelLen = 500
+
 	// ImportedAddrAccountName is the name of the imported account.
 	ImportedAddrAccountName = "imported"
 
@@ -95,10 +100,31 @@ func isReservedAccountNum(acct uint32) bool {
 	return acct == ImportedAddrAccount
 }
 
-// ScryptOptions is used to hold the scrypt parameters needed when deriving new
-// passphrase keys.
-type ScryptOptions struct {
+// KDFOptions is used to hold the key derivation parameters needed when
+// deriving new passphrase keys.  The keys are derived with scrypt using N, R
+// and P, unless the Argon2id parameters are set.
+type KDFOptions struct {
 	N, R, P int
+
+	// Argon2id, if set, derives the passphrase keys with Argon2id using
+	// its parameters instead of scrypt.
+	Argon2id *Argon2idOptions
+}
+
+// ScryptOptions is the former name of KDFOptions, kept for compatibility.
+type ScryptOptions = KDFOptions
+
+// Argon2idOptions is used to hold the Argon2id parameters needed when deriving
+// new passphrase keys.
+type Argon2idOptions struct {
+	// Time is the number of passes over the memory.
+	Time uint32
+
+	// Memory is the amount of memory used, in KiB.
+	Memory uint32
+
+	// Threads is the number of threads used.
+	Threads uint8
 }
 
 // OpenCallbacks houses caller-provided callbacks that may be called when
@@ -124,6 +150,82 @@ var DefaultScryptOptions = ScryptOptions{
 	P: 1,
 }
 
+// DefaultArgon2idOptions are the default options used with Argon2id.
+var DefaultArgon2idOptions = KDFOptions{
+	Argon2id: &Argon2idOptions{
+		Time:    snacl.DefaultArgon2Time,
+		Memory:  snacl.DefaultArgon2Memory,
+		Threads: snacl.DefaultArgon2Threads,
+	},
+}
+
+// TuneArgon2idOptions returns Argon2id options using the given memory, in KiB,
+// and number of threads, with the number of passes chosen so that deriving a
+// passphrase key takes about the target duration on this host.  At least one
+// pass is used, however short the target, so the estimated derivation time of
+// the returned options is returned as well.
+func TuneArgon2idOptions(target time.Duration, memory uint32,
+	threads uint8) (*KDFOptions, time.Duration, error) {
+
+	// Each derivation has a fixed cost besides its passes, such as
+	// allocating the memory and freeing it afterwards, so time one and two
+	// passes and take the difference as the cost of a pass.  A first
+	// derivation warms up the runtime and isn't counted, as it would
+	// otherwise inflate the single pass.
+	if _, err := timeArgon2id(1, memory, threads); err != nil {
+		return nil, 0, err
+	}
+	one, err := timeArgon2id(1, memory, threads)
+	if err != nil {
+		return nil, 0, err
+	}
+	two, err := timeArgon2id(2, memory, threads)
+	if err != nil {
+		return nil, 0, err
+	}
+	perPass := two - one
+	if perPass <= 0 {
+		// The timings are too noisy to tell the passes apart from
+		// the fixed cost, so charge all of it to the passes.
+		perPass = two / 2
+	}
+	fixed := two - 2*perPass
+	if fixed < 0 {
+		fixed = 0
+	}
+
+	passes := int64(1)
+	if perPass > 0 && target > fixed+perPass {
+		passes = int64((target - fixed) / perPass)
+	}
+	if passes > math.MaxUint32 {
+		passes = math.MaxUint32
+	}
+
+	opts := &KDFOptions{
+		Argon2id: &Argon2idOptions{
+			Time:    uint32(passes),
+			Memory:  memory,
+			Threads: threads,
+		},
+	}
+	return opts, fixed + time.Duration(passes)*perPass, nil
+}
+
+// timeArgon2id returns how long deriving a passphrase key with the given
+// Argon2id parameters takes.
+func timeArgon2id(passes, memory uint32, threads uint8) (time.Duration,
+	error) {
+
+	passphrase := []byte("benchmark")
+	start := time.Now()
+	_, err := snacl.NewArgon2idSecretKey(&passphrase, passes, memory, threads)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
 // FastScryptOptions are the scrypt options that should be used for testing
 // purposes only where speed is more important than security.
 var FastScryptOptions = ScryptOptions{
@@ -240,6 +342,13 @@ type SecretKeyGenerator func(
 // defaultNewSecretKey returns a new secret key.  See newSecretKey.
 func defaultNewSecretKey(passphrase *[]byte,
 	config *ScryptOptions) (*snacl.SecretKey, error) {
+
+	if config.Argon2id != nil {
+		return snacl.NewArgon2idSecretKey(
+			passphrase, config.Argon2id.Time,
+			config.Argon2id.Memory, config.Argon2id.Threads,
+		)
+	}
 	return snacl.NewSecretKey(passphrase, config.N, config.R, config.P)
 }
 
@@ -748,6 +857,46 @@ func (m *Manager) MarkUsed(ns walletdb.ReadWriteBucket, address btcutil.Address)
 	return managerError(ErrAddressNotFound, str, nil)
 }
 
+// AddrLabel returns the user-defined label of the given address. An empty
+// string is returned if the address is known but has not been labelled.
+func (m *Manager) AddrLabel(ns walletdb.ReadBucket,
+	address btcutil.Address) (string, error) {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	for _, scopedMgr := range m.scopedManagers {
+		if _, err := scopedMgr.Address(ns, address); err != nil {
+			continue
+		}
+
+		return scopedMgr.AddrLabel(ns, address)
+	}
+
+	str := fmt.Sprintf("unable to find key for addr %v", address)
+	return "", managerError(ErrAddressNotFound, str, nil)
+}
+
+// SetAddrLabel sets the user-defined label of the given address. Passing an
+// empty label removes any existing label.
+func (m *Manager) SetAddrLabel(ns walletdb.ReadWriteBucket,
+	address btcutil.Address, label string) error {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	for _, scopedMgr := range m.scopedManagers {
+		if _, err := scopedMgr.Address(ns, address); err != nil {
+			continue
+		}
+
+		return scopedMgr.SetAddrLabel(ns, address, label)
+	}
+
+	str := fmt.Sprintf("unable to find key for addr %v", address)
+	return managerError(ErrAddressNotFound, str, nil)
+}
+
 // AddrAccount returns the account to which the given address belongs. We also
 // return the scoped manager that owns the addr+account combo.
 func (m *Manager) AddrAccount(ns walletdb.ReadBucket,
@@ -881,9 +1030,10 @@ func (m *Manager) ChainParams() *chaincfg.Params {
 // ChangePassphrase changes either the public or private passphrase to the
 // provided value depending on the private flag.  In order to change the
 // private password, the address manager must not be watching-only.  The new
-// passphrase keys are derived using the scrypt parameters in the options, so
-// changing the passphrase may be used to bump the computational difficulty
-// needed to brute force the passphrase.
+// passphrase keys are derived using the scrypt or Argon2id parameters in the
+// options, so changing the passphrase, even to the same value, may be used to
+// bump the computational difficulty needed to brute force the passphrase or to
+// upgrade the master keys of an existing wallet to Argon2id.
 func (m *Manager) ChangePassphrase(ns walletdb.ReadWriteBucket, oldPassphrase,
 	newPassphrase []byte, private bool, config *ScryptOptions) error {
 
@@ -1038,6 +1188,35 @@ func (m *Manager) ChangePassphrase(ns walletdb.ReadWriteBucket, oldPassphrase,
 	return nil
 }
 
+// PassphraseKDF returns the key derivation parameters of the public or private
+// passphrase depending on the private flag, as options which would derive keys
+// of the same cost.  Nil is returned for the private passphrase of a
+// watching-only address manager.
+func (m *Manager) PassphraseKDF(private bool) *KDFOptions {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	masterKey := m.masterKeyPub
+	if private {
+		if m.watchingOnly {
+			return nil
+		}
+		masterKey = m.masterKeyPriv
+	}
+
+	params := &masterKey.Parameters
+	if params.KDF == snacl.KDFArgon2id {
+		return &KDFOptions{
+			Argon2id: &Argon2idOptions{
+				Time:    params.Time,
+				Memory:  params.Memory,
+				Threads: params.Threads,
+			},
+		}
+	}
+	return &KDFOptions{N: params.N, R: params.R, P: params.P}
+}
+
 // ConvertToWatchingOnly converts the current address manager to a locked
 // watching-only address manager.
 //
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -1938,6 +1939,58 @@ func testSync(tc *testContext) bool {
 		return false
 	}
 
+	// A rescan checkpoint should be returned until it is cleared.
+	var (
+		checkpoint BlockStamp
+		inProgress bool
+	)
+	fetchCheckpoint := func() error {
+		return walletdb.View(tc.db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(waddrmgrNamespaceKey)
+
+			var err error
+			checkpoint, inProgress, err =
+				tc.rootManager.RescanCheckpoint(ns)
+			return err
+		})
+	}
+	err = walletdb.Update(tc.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return tc.rootManager.SetRescanCheckpoint(ns, &blockStamp)
+	})
+	if err == nil {
+		err = fetchCheckpoint()
+	}
+	if err != nil {
+		tc.t.Errorf("(%s) unexpected rescan checkpoint err: %v",
+			tc.caseName, err)
+		return false
+	}
+	if !inProgress || checkpoint != blockStamp {
+		tc.t.Errorf("(%s) RescanCheckpoint unexpected block stamp -- "+
+			"got %v (%v), want %v", tc.caseName, checkpoint,
+			inProgress, blockStamp)
+		return false
+	}
+
+	err = walletdb.Update(tc.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return tc.rootManager.SetRescanCheckpoint(ns, nil)
+	})
+	if err == nil {
+		err = fetchCheckpoint()
+	}
+	if err != nil {
+		tc.t.Errorf("(%s) unexpected rescan checkpoint err: %v",
+			tc.caseName, err)
+		return false
+	}
+	if inProgress {
+		tc.t.Errorf("(%s) RescanCheckpoint unexpected block stamp "+
+			"after clearing it: %v", tc.caseName, checkpoint)
+		return false
+	}
+
 	return true
 }
 
@@ -3284,3 +3337,174 @@ func TestManagedAddressValidation(t *testing.T) {
 	}
 
 }
+
+// TestFormatKeyPath tests that the derivation path of a key is formatted with
+// its hardened children marked.
+func TestFormatKeyPath(t *testing.T) {
+	t.Parallel()
+
+	path := DerivationPath{
+		Account: hdkeychain.HardenedKeyStart + 2,
+		Branch:  1,
+		Index:   7,
+	}
+	require.Equal(
+		t, "m/84'/0'/2'/1/7", FormatKeyPath(KeyScopeBIP0084, path),
+	)
+}
+
+// TestAddrLabel ensures that address labels can be set, updated and removed,
+// and that labels can't be set for addresses unknown to the manager.
+func TestAddrLabel(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	scopedMgr, err := mgr.FetchScopedKeyManager(KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	var addr ManagedAddress
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		addrs, err := scopedMgr.NextExternalAddresses(ns, 0, 1)
+		if err != nil {
+			return err
+		}
+		addr = addrs[0]
+		return nil
+	})
+	require.NoError(t, err)
+
+	fetchLabel := func() string {
+		var label string
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(waddrmgrNamespaceKey)
+			var err error
+			label, err = mgr.AddrLabel(ns, addr.Address())
+			return err
+		})
+		require.NoError(t, err)
+		return label
+	}
+	setLabel := func(a btcutil.Address, label string) error {
+		return walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			return mgr.SetAddrLabel(ns, a, label)
+		})
+	}
+
+	// An address that was never labelled has an empty label.
+	require.Empty(t, fetchLabel())
+
+	require.NoError(t, setLabel(addr.Address(), "deposit"))
+	require.Equal(t, "deposit", fetchLabel())
+
+	require.NoError(t, setLabel(addr.Address(), "withdrawal"))
+	require.Equal(t, "withdrawal", fetchLabel())
+
+	// Setting an empty label removes it.
+	require.NoError(t, setLabel(addr.Address(), ""))
+	require.Empty(t, fetchLabel())
+
+	// Labels over the limit are rejected.
+	err = setLabel(addr.Address(), strings.Repeat("a", MaxAddrLabelLen+1))
+	require.True(t, IsError(err, ErrInvalidLabel))
+
+	// Labels can't be set on addresses we don't know about.
+	unknown, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), &chaincfg.MainNetParams,
+	)
+	require.NoError(t, err)
+	err = setLabel(unknown, "unknown")
+	require.True(t, IsError(err, ErrAddressNotFound))
+}
+
+// TestChangePassphraseArgon2id ensures that the master keys of an existing
+// manager can be upgraded to Argon2id by changing its passphrases to the same
+// values, and that the manager can be reopened and unlocked afterwards.
+func TestChangePassphraseArgon2id(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	require.Equal(t, fastScrypt, mgr.PassphraseKDF(true))
+
+	// Parameters beyond the limits of snacl are rejected, leaving the
+	// current master key in place.
+	tooSlow := &KDFOptions{
+		Argon2id: &Argon2idOptions{
+			Time: snacl.MaxArgon2Time + 1, Memory: 64, Threads: 1,
+		},
+	}
+	tooLarge := &KDFOptions{N: snacl.MaxScryptN, R: 16, P: 1}
+	for _, config := range []*KDFOptions{tooSlow, tooLarge} {
+		err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			return mgr.ChangePassphrase(
+				ns, privPassphrase, privPassphrase, true, config,
+			)
+		})
+		require.True(t, IsError(err, ErrCrypto))
+		require.ErrorIs(t, err, snacl.ErrInvalidParams)
+	}
+	require.Equal(t, fastScrypt, mgr.PassphraseKDF(true))
+
+	config := &KDFOptions{
+		Argon2id: &Argon2idOptions{Time: 1, Memory: 64, Threads: 1},
+	}
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		err := mgr.ChangePassphrase(
+			ns, pubPassphrase, pubPassphrase, false, config,
+		)
+		if err != nil {
+			return err
+		}
+		return mgr.ChangePassphrase(
+			ns, privPassphrase, privPassphrase, true, config,
+		)
+	})
+	require.NoError(t, err)
+	require.Equal(t, config, mgr.PassphraseKDF(false))
+	require.Equal(t, config, mgr.PassphraseKDF(true))
+	mgr.Close()
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		mgr, err := Open(ns, pubPassphrase, &chaincfg.MainNetParams)
+		if err != nil {
+			return err
+		}
+		defer mgr.Close()
+
+		require.Equal(
+			t, uint32(64), mgr.masterKeyPriv.Parameters.Memory,
+		)
+		err = mgr.Unlock(ns, []byte("wrong"))
+		require.True(t, IsError(err, ErrWrongPassphrase))
+		return mgr.Unlock(ns, privPassphrase)
+	})
+	require.NoError(t, err)
+}
+
+// TestTuneArgon2idOptions ensures that the number of passes of the tuned
+// Argon2id options grows with the target derivation time.
+func TestTuneArgon2idOptions(t *testing.T) {
+	t.Parallel()
+
+	opts, estimate, err := TuneArgon2idOptions(0, 64, 1)
+	require.NoError(t, err)
+	require.Equal(t, &Argon2idOptions{Time: 1, Memory: 64, Threads: 1},
+		opts.Argon2id)
+	require.Positive(t, estimate)
+
+	opts, estimate, err = TuneArgon2idOptions(time.Hour, 64, 1)
+	require.NoError(t, err)
+	require.Greater(t, opts.Argon2id.Time, uint32(1000))
+	require.LessOrEqual(t, estimate, time.Hour)
+
+	_, _, err = TuneArgon2idOptions(time.Second, 64, 0)
+	require.ErrorIs(t, err, snacl.ErrInvalidParams)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/netparams"
 	"github.com/btcsuite/btcwallet/walletdb"
 	"github.com/btcsuite/btcwallet/walletdb/migration"
 )
@@ -314,6 +315,10 @@ func populateBirthdayBlock(ns walletdb.ReadWriteBucket) error {
 		genesisTimestamp =
 			chaincfg.TestNet3Params.GenesisBlock.Header.Timestamp
 
+	case *netparams.TestNet4Params.GenesisHash:
+		genesisTimestamp =
+			netparams.TestNet4Params.GenesisBlock.Header.Timestamp
+
 	case *chaincfg.RegressionNetParams.GenesisHash:
 		genesisTimestamp =
 			chaincfg.RegressionNetParams.GenesisBlock.Header.Timestamp
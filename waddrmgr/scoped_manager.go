@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -97,6 +98,28 @@ type DerivationPath struct {
 	MasterKeyFingerprint uint32
 }
 
+// FormatKeyPath returns the full BIP0032 derivation path of a key within a
+// key scope in the m/84'/0'/0'/0/1 notation, with hardened children marked by
+// an apostrophe.
+func FormatKeyPath(scope KeyScope, path DerivationPath) string {
+	indexes := []uint32{
+		scope.Purpose + hdkeychain.HardenedKeyStart,
+		scope.Coin + hdkeychain.HardenedKeyStart,
+		path.Account, path.Branch, path.Index,
+	}
+
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range indexes {
+		if index >= hdkeychain.HardenedKeyStart {
+			fmt.Fprintf(&b, "/%d'", index-hdkeychain.HardenedKeyStart)
+			continue
+		}
+		fmt.Fprintf(&b, "/%d", index)
+	}
+	return b.String()
+}
+
 // KeyScope represents a restricted key scope from the primary root key within
 // the HD chain. From the root manager (m/) we can create a nearly arbitrary
 // number of ScopedKeyManagers of key derivation path: m/purpose'/cointype'.
@@ -598,8 +621,12 @@ func (s *ScopedKeyManager) AccountProperties(ns walletdb.ReadBucket,
 		props.InternalKeyCount = acctInfo.nextInternalIndex
 		props.AccountPubKey = acctInfo.acctKeyPub
 		props.MasterKeyFingerprint = acctInfo.masterKeyFingerprint
+
+		// The decrypted private key is only loaded while the manager
+		// is unlocked, so whether the account holds one at all is
+		// told by its encrypted form.
 		props.IsWatchOnly = s.rootManager.WatchOnly() ||
-			acctInfo.acctKeyPriv == nil
+			len(acctInfo.acctKeyEncrypted) == 0
 		props.AddrSchema = acctInfo.addrSchema
 
 		// Export the account public key with the correct version
@@ -2368,6 +2395,66 @@ func (s *ScopedKeyManager) MarkUsed(ns walletdb.ReadWriteBucket,
 	return nil
 }
 
+// AddrLabel returns the user-defined label of the given address. An empty
+// string is returned if the address has not been labelled.
+func (s *ScopedKeyManager) AddrLabel(ns walletdb.ReadBucket,
+	address btcutil.Address) (string, error) {
+
+	// Labels are keyed by the same address id that is used to store the
+	// address itself, so pubkey addresses are converted first.
+	if pka, ok := address.(*btcutil.AddressPubKey); ok {
+		address = pka.AddressPubKeyHash()
+	}
+	addressID := address.ScriptAddress()
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if !s.existsAddress(ns, addressID) {
+		str := fmt.Sprintf("unable to find key for addr %v", address)
+		return "", managerError(ErrAddressNotFound, str, nil)
+	}
+
+	label, err := fetchAddrLabel(ns, &s.scope, addressID)
+	if err != nil {
+		return "", maybeConvertDbError(err)
+	}
+
+	return label, nil
+}
+
+// SetAddrLabel sets the user-defined label of the given address. Passing an
+// empty label removes any existing label.
+func (s *ScopedKeyManager) SetAddrLabel(ns walletdb.ReadWriteBucket,
+	address btcutil.Address, label string) error {
+
+	if len(label) > MaxAddrLabelLen {
+		str := fmt.Sprintf("label exceeds maximum length of %d",
+			MaxAddrLabelLen)
+		return managerError(ErrInvalidLabel, str, nil)
+	}
+
+	if pka, ok := address.(*btcutil.AddressPubKey); ok {
+		address = pka.AddressPubKeyHash()
+	}
+	addressID := address.ScriptAddress()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.existsAddress(ns, addressID) {
+		str := fmt.Sprintf("unable to find key for addr %v", address)
+		return managerError(ErrAddressNotFound, str, nil)
+	}
+
+	err := putAddrLabel(ns, &s.scope, addressID, label)
+	if err != nil {
+		return maybeConvertDbError(err)
+	}
+
+	return nil
+}
+
 // ChainParams returns the chain parameters for this address manager.
 func (s *ScopedKeyManager) ChainParams() *chaincfg.Params {
 	// NOTE: No need for mutex here since the net field does not change
@@ -2520,7 +2607,7 @@ func (s *ScopedKeyManager) cloneKeyWithVersion(key *hdkeychain.ExtendedKey) (
 			return nil, fmt.Errorf("unsupported scope %v", s.scope)
 		}
 
-	case wire.TestNet, wire.TestNet3,
+	case wire.TestNet, wire.TestNet3, netparams.TestNet4Wire,
 		netparams.SigNetWire(s.rootManager.ChainParams()):
 
 		switch s.scope {
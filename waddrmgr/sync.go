@@ -7,7 +7,9 @@ package waddrmgr
 import (
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/walletdb"
 )
 
@@ -20,6 +22,33 @@ type BlockStamp struct {
 	Timestamp time.Time
 }
 
+// RecoveryCheckpoint is the progress of a wallet recovery that has yet to
+// finish, from which the recovery can be resumed.
+type RecoveryCheckpoint struct {
+	// Block is the last block processed by the recovery.
+	Block BlockStamp
+
+	// Scopes holds the recovery progress of each key scope being
+	// recovered.
+	Scopes map[KeyScope]ScopeRecoveryProgress
+
+	// WatchedOutPoints is the set of outpoints watched for spends by the
+	// recovery, along with the address each of them pays to.
+	WatchedOutPoints map[wire.OutPoint]btcutil.Address
+}
+
+// ScopeRecoveryProgress is the recovery progress of the branches of the
+// default account of a key scope.
+type ScopeRecoveryProgress struct {
+	// ExternalNextUnfound is the child index following the highest one
+	// found on the external branch.
+	ExternalNextUnfound uint32
+
+	// InternalNextUnfound is the child index following the highest one
+	// found on the internal branch.
+	InternalNextUnfound uint32
+}
+
 // syncState houses the sync state of the manager.  It consists of the recently
 // seen blocks as height, as well as the start and current sync block stamps.
 type syncState struct {
@@ -133,3 +162,43 @@ func (m *Manager) SetBirthdayBlock(ns walletdb.ReadWriteBucket,
 	}
 	return putBirthdayBlockVerification(ns, verified)
 }
+
+// RescanCheckpoint returns the last block processed by a rescan that has yet
+// to finish, e.g. because the wallet was shut down, so that it can be resumed.
+// The returned bool is false if no rescan is in progress.
+func (m *Manager) RescanCheckpoint(ns walletdb.ReadBucket) (BlockStamp, bool,
+	error) {
+
+	return FetchRescanCheckpoint(ns)
+}
+
+// SetRescanCheckpoint records the last block processed by a rescan in
+// progress. A nil block stamp marks the rescan as finished.
+func (m *Manager) SetRescanCheckpoint(ns walletdb.ReadWriteBucket,
+	bs *BlockStamp) error {
+
+	if bs == nil {
+		return DeleteRescanCheckpoint(ns)
+	}
+	return PutRescanCheckpoint(ns, *bs)
+}
+
+// RecoveryCheckpoint returns the progress of a wallet recovery that has yet to
+// finish, e.g. because the wallet was shut down, so that it can be resumed.
+// The returned bool is false if no recovery is in progress.
+func (m *Manager) RecoveryCheckpoint(ns walletdb.ReadBucket) (
+	*RecoveryCheckpoint, bool, error) {
+
+	return FetchRecoveryCheckpoint(ns, m.chainParams)
+}
+
+// SetRecoveryCheckpoint records the progress of a wallet recovery. A nil
+// checkpoint marks the recovery as finished.
+func (m *Manager) SetRecoveryCheckpoint(ns walletdb.ReadWriteBucket,
+	checkpoint *RecoveryCheckpoint) error {
+
+	if checkpoint == nil {
+		return DeleteRecoveryCheckpoint(ns)
+	}
+	return PutRecoveryCheckpoint(ns, checkpoint)
+}
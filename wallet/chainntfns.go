@@ -190,6 +190,16 @@ func (w *Wallet) handleChainNotifications() {
 					})
 				}
 				notificationName = "filtered block connected"
+			case chain.TxEvicted:
+				err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+					return w.removeUnminedTx(tx, n.Hash, nil)
+				})
+				notificationName = "transaction evicted"
+			case chain.TxReplaced:
+				err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+					return w.removeUnminedTx(tx, n.Old, &n.New)
+				})
+				notificationName = "transaction replaced"
 
 			// The following require some database maintenance, but also
 			// need to be reported to the wallet's rescan goroutine.
@@ -313,6 +323,46 @@ func (w *Wallet) disconnectBlock(dbtx walletdb.ReadWriteTx, b wtxmgr.BlockMeta)
 	return nil
 }
 
+// removeUnminedTx handles a chain server notification that an unmined
+// transaction left the mempool without being mined, either because it was
+// evicted or, when replacedBy is set, replaced by a conflicting transaction.
+// The transaction, along with any unmined transaction spending its outputs, is
+// removed from the wallet.
+func (w *Wallet) removeUnminedTx(dbtx walletdb.ReadWriteTx,
+	hash chainhash.Hash, replacedBy *chainhash.Hash) error {
+
+	txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+	// The transaction may not be known to the wallet, or may have been
+	// mined since the notification was sent, in which case there's nothing
+	// to remove.
+	details, err := w.TxStore.TxDetails(txmgrNs, &hash)
+	if err != nil {
+		return err
+	}
+	if details == nil || details.Block.Height != -1 {
+		return nil
+	}
+
+	if replacedBy != nil {
+		log.Infof("Removing unmined transaction %v replaced by %v",
+			hash, replacedBy)
+	} else {
+		log.Infof("Removing unmined transaction %v evicted from the "+
+			"mempool", hash)
+	}
+
+	err = w.TxStore.RemoveUnminedTx(txmgrNs, &details.TxRecord)
+	if err != nil {
+		return err
+	}
+
+	// Notify interested clients of the removed transaction.
+	w.NtfnServer.notifyRemovedUnminedTransaction(dbtx, details, replacedBy)
+
+	return nil
+}
+
 func (w *Wallet) addRelevantTx(dbtx walletdb.ReadWriteTx, rec *wtxmgr.TxRecord,
 	block *wtxmgr.BlockMeta) error {
 
@@ -6,11 +6,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -308,3 +313,102 @@ func TestBirthdaySanityCheckHigherEstimate(t *testing.T) {
 			"%v vs %v", birthdayStore.syncedTo, birthdayBlock)
 	}
 }
+
+// TestRemoveUnminedTx tests that the unmined transactions evicted from or
+// replaced in the mempool are removed from the wallet, and that the clients
+// are notified of their removal.
+func TestRemoveUnminedTx(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	// addUnminedTx adds an unmined transaction paying to the wallet.
+	addUnminedTx := func(prevHash chainhash.Hash) *wire.MsgTx {
+		t.Helper()
+
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 0), nil, nil))
+		tx.AddTxOut(wire.NewTxOut(1e6, pkScript))
+
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+		require.NoError(t, err)
+		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			return w.addRelevantTx(dbtx, rec, nil)
+		})
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	// removeUnminedTx removes the transaction from the wallet and returns
+	// the notification sent for it.
+	removeUnminedTx := func(hash chainhash.Hash,
+		replacedBy *chainhash.Hash) *TransactionNotifications {
+
+		t.Helper()
+
+		ntfns := w.NtfnServer.TransactionNotifications()
+		defer ntfns.Done()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- walletdb.Update(w.db, func(
+				dbtx walletdb.ReadWriteTx) error {
+
+				return w.removeUnminedTx(dbtx, hash, replacedBy)
+			})
+		}()
+
+		var n *TransactionNotifications
+		select {
+		case n = <-ntfns.C:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected notification for %v", hash)
+		}
+		require.NoError(t, <-errChan)
+
+		return n
+	}
+
+	evicted := addUnminedTx(chainhash.Hash{1})
+	replaced := addUnminedTx(chainhash.Hash{2})
+	balance, err := w.CalculateBalance(0)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(2e6), balance)
+
+	evictedHash := evicted.TxHash()
+	n := removeUnminedTx(evictedHash, nil)
+	require.Equal(t, []*chainhash.Hash{&evictedHash}, n.EvictedTransactions)
+	require.Empty(t, n.ReplacedTransactions)
+	require.Len(t, n.UnminedTransactionHashes, 1)
+	require.Equal(t, []AccountBalance{{
+		Account:      0,
+		TotalBalance: 1e6,
+	}}, n.NewBalances)
+
+	replacedHash := replaced.TxHash()
+	replacement := chainhash.Hash{3}
+	n = removeUnminedTx(replacedHash, &replacement)
+	require.Empty(t, n.EvictedTransactions)
+	require.Equal(t, []ReplacedTransaction{{
+		Hash:       &replacedHash,
+		ReplacedBy: &replacement,
+	}}, n.ReplacedTransactions)
+	require.Empty(t, n.UnminedTransactionHashes)
+
+	balance, err = w.CalculateBalance(0)
+	require.NoError(t, err)
+	require.Zero(t, balance)
+
+	// Removing a transaction unknown to the wallet is a no-op.
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.removeUnminedTx(dbtx, evictedHash, nil)
+	})
+	require.NoError(t, err)
+}
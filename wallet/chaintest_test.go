@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain/chaintest"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWalletSyncInMemoryChain tests that a wallet synced to an in-memory
+// chain follows payments to it through the mempool, blocks and a
+// reorganization, and broadcasts its transactions to the chain.
+func TestWalletSyncInMemoryChain(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	testChain, err := chaintest.NewChain(&chaintest.Config{
+		ChainParams: params,
+	})
+	require.NoError(t, err)
+	_, err = testChain.GenerateBlocks(int(params.CoinbaseMaturity) + 1)
+	require.NoError(t, err)
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+	loader := NewLoader(
+		params, t.TempDir(), true, defaultDBTimeout, 0,
+		WithWalletSyncRetryInterval(10*time.Millisecond),
+	)
+	w, err := loader.CreateNewWallet(
+		[]byte("hello"), []byte("world"), seed, time.Now(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, w.Unlock([]byte("world"), nil))
+
+	client := testChain.NewClient()
+	require.NoError(t, client.Start())
+	w.Start()
+	w.SynchronizeRPC(client)
+	t.Cleanup(func() {
+		w.Stop()
+		w.WaitForShutdown()
+	})
+
+	require.Eventually(t, w.ChainSynced, 30*time.Second,
+		10*time.Millisecond)
+
+	requireBalance := func(confirms int32, amount btcutil.Amount) {
+		t.Helper()
+
+		require.Eventually(t, func() bool {
+			balance, err := w.CalculateBalance(confirms)
+			return err == nil && balance == amount
+		}, 30*time.Second, 10*time.Millisecond)
+	}
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	// The payment is found in the mempool and once it is mined.
+	pay, err := testChain.SendOutputs(wire.NewTxOut(1e8, pkScript))
+	require.NoError(t, err)
+	requireBalance(0, 1e8)
+	requireBalance(1, 0)
+
+	_, err = testChain.GenerateBlocks(1)
+	require.NoError(t, err)
+	requireBalance(1, 1e8)
+
+	// Once the block containing the payment is reorganized out of the
+	// chain, the payment is unconfirmed again.
+	_, err = testChain.Reorg(1, nil, nil)
+	require.NoError(t, err)
+	requireBalance(1, 0)
+	requireBalance(0, 1e8)
+
+	_, err = testChain.GenerateBlocks(1)
+	require.NoError(t, err)
+	requireBalance(1, 1e8)
+
+	// A transaction spending the payment passes the policy checks of the
+	// chain's mempool.
+	tx, err := w.SendOutputs(
+		[]*wire.TxOut{wire.NewTxOut(5e7, chaintest.AnyoneCanSpendPkScript)},
+		&waddrmgr.KeyScopeBIP0084, 0, 1, 2000, CoinSelectionLargest, "",
+	)
+	require.NoError(t, err)
+	require.Equal(t, pay.TxHash(), tx.TxIn[0].PreviousOutPoint.Hash)
+
+	mempool := testChain.Mempool()
+	require.Len(t, mempool, 1)
+	require.Equal(t, tx.TxHash(), mempool[0].TxHash())
+}
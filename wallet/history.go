@@ -5,8 +5,11 @@
 package wallet
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
@@ -94,8 +97,68 @@ func DropTransactionHistory(db walletdb.DB, keepLabels bool) error {
 	return nil
 }
 
+// RollbackTransactionHistory rolls the transaction history of the given
+// wallet database back to the block at the given height, or to the block with
+// the given hash if it's not nil, and marks the wallet as synced to it.  The
+// transactions mined in later blocks become unmined until the wallet rescans
+// them as it syncs again, which avoids a full rescan from the birthday when
+// only recent blocks need to be scanned again.  Transaction labels and locked
+// outputs are kept.
+//
+// The block must be one of the last waddrmgr.MaxReorgDepth blocks the wallet
+// synced to, and can't be before its birthday block.  The wallet must not be
+// open.  The block the wallet is now synced to is returned.
+func RollbackTransactionHistory(db walletdb.DB, chainParams *chaincfg.Params,
+	height int32, hash *chainhash.Hash) (*waddrmgr.BlockStamp, error) {
+
+	var syncedTo *waddrmgr.BlockStamp
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+		if addrmgrNs == nil || txmgrNs == nil {
+			return errors.New("missing wallet namespace")
+		}
+
+		var err error
+		if hash != nil {
+			height, err = waddrmgr.FetchBlockHeight(addrmgrNs, hash)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The wallet records the time of the block once it syncs
+		// again.
+		syncedTo, err = waddrmgr.RollbackSyncedTo(
+			addrmgrNs, height, time.Time{},
+		)
+		if err != nil {
+			return err
+		}
+
+		txStore, err := wtxmgr.Open(txmgrNs, chainParams)
+		if err != nil {
+			return err
+		}
+
+		// Rollback unconfirms the transactions at and beyond the passed
+		// height, so the transactions of the synced to block are kept
+		// by adding one.
+		return txStore.Rollback(txmgrNs, height+1)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back transaction "+
+			"history: %w", err)
+	}
+
+	log.Infof("Rolled back transaction history to block %v (height %d)",
+		syncedTo.Hash, syncedTo.Height)
+
+	return syncedTo, nil
+}
+
 // fetchAllLabels returns a map of hex-encoded txid to label.
-func fetchAllLabels(tx walletdb.ReadWriteTx) (map[chainhash.Hash]string,
+func fetchAllLabels(tx walletdb.ReadTx) (map[chainhash.Hash]string,
 	error) {
 
 	// Get our top level bucket, if it does not exist we just exit.
@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRollbackTransactionHistory ensures that rolling the transaction history
+// back to a recent block unconfirms the transactions of the later blocks and
+// resets the sync tip, while keeping transaction labels and locked outputs.
+func TestRollbackTransactionHistory(t *testing.T) {
+	t.Parallel()
+
+	pubPass := []byte("hello")
+	loader := NewLoader(
+		&chaincfg.TestNet3Params, t.TempDir(), true, defaultDBTimeout,
+		250,
+	)
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+	w, err := loader.CreateNewWallet(
+		pubPass, []byte("world"), seed, time.Now(),
+	)
+	require.NoError(t, err)
+	w.chainClient = &mockChainClient{}
+
+	blockHash := func(height int32) chainhash.Hash {
+		var hash chainhash.Hash
+		binary.BigEndian.PutUint32(hash[:], uint32(height))
+		return hash
+	}
+
+	// Sync the wallet to height 110, with a transaction paying to it mined
+	// at height 108.
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	msgTx := wire.NewMsgTx(2)
+	msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	msgTx.AddTxOut(wire.NewTxOut(1e6, pkScript))
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+	require.NoError(t, err)
+
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		for height := int32(100); height <= 110; height++ {
+			err := w.Manager.SetSyncedTo(
+				addrmgrNs, &waddrmgr.BlockStamp{
+					Hash:      blockHash(height),
+					Height:    height,
+					Timestamp: time.Unix(int64(height), 0),
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return w.addRelevantTx(tx, rec, &wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   blockHash(108),
+				Height: 108,
+			},
+			Time: time.Unix(108, 0),
+		})
+	})
+	require.NoError(t, err)
+
+	op := wire.OutPoint{Hash: rec.Hash}
+	require.NoError(t, w.LabelTransaction(rec.Hash, "label", false))
+	_, err = w.LeaseOutput(wtxmgr.LockID{1}, op, time.Hour)
+	require.NoError(t, err)
+
+	// The history of a loaded wallet can't be rolled back.
+	_, err = loader.RollbackTransactionHistory(pubPass, 105, nil)
+	require.ErrorIs(t, err, ErrLoaded)
+	require.NoError(t, loader.UnloadWallet())
+
+	// Blocks that aren't known can't be rolled back to.
+	_, err = loader.RollbackTransactionHistory(pubPass, 111, nil)
+	var managerErr waddrmgr.ManagerError
+	require.ErrorAs(t, err, &managerErr)
+	require.Equal(t, waddrmgr.ErrBlockNotFound, managerErr.ErrorCode)
+
+	syncedTo, err := loader.RollbackTransactionHistory(pubPass, 105, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 105, syncedTo.Height)
+	require.Equal(t, blockHash(105), syncedTo.Hash)
+
+	w, err = loader.OpenExistingWallet(pubPass, false)
+	require.NoError(t, err)
+	require.Equal(t, *syncedTo, w.Manager.SyncedTo())
+
+	details, err := UnstableAPI(w).TxDetails(&rec.Hash)
+	require.NoError(t, err)
+	require.EqualValues(t, -1, details.Block.Height)
+	require.Equal(t, "label", details.Label)
+
+	leased, err := w.ListLeasedOutputs()
+	require.NoError(t, err)
+	require.Len(t, leased, 1)
+	require.Equal(t, op, leased[0].Outpoint)
+	require.NoError(t, loader.UnloadWallet())
+
+	// The history can also be rolled back to a block by its hash.
+	hash := blockHash(103)
+	syncedTo, err = loader.RollbackTransactionHistory(pubPass, -1, &hash)
+	require.NoError(t, err)
+	require.EqualValues(t, 103, syncedTo.Height)
+}
@@ -0,0 +1,832 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// LabelType identifies the kind of object a BIP-0329 label record refers to.
+type LabelType string
+
+// These constants define the record types specified by BIP-0329.
+const (
+	LabelTypeTx     LabelType = "tx"
+	LabelTypeAddr   LabelType = "addr"
+	LabelTypePubKey LabelType = "pubkey"
+	LabelTypeInput  LabelType = "input"
+	LabelTypeOutput LabelType = "output"
+	LabelTypeXPub   LabelType = "xpub"
+)
+
+const (
+	// maxLabelRecordSize is the maximum size of a single JSON line that is
+	// accepted when importing labels.
+	maxLabelRecordSize = 1 << 20
+
+	// FrozenOutputLeaseDuration is the duration outputs are leased for
+	// when a label import marks them as unspendable. It is long enough to
+	// effectively freeze the output until it is explicitly released.
+	FrozenOutputLeaseDuration = 100 * 365 * 24 * time.Hour
+)
+
+var (
+	// FrozenOutputLockID is the lock ID used to lease outputs that were
+	// marked as unspendable by a label import. Outputs leased with this ID
+	// are released again when a later import marks them as spendable.
+	FrozenOutputLockID = wtxmgr.LockID(sha256.Sum256(
+		[]byte("btcwallet/bip329/frozen"),
+	))
+
+	// ErrInvalidLabelRecord is returned when a line of a label import can
+	// not be decoded as a BIP-0329 record.
+	ErrInvalidLabelRecord = errors.New("invalid BIP-0329 label record")
+)
+
+// LabelRecord is a single BIP-0329 label record. Records are serialized as
+// one JSON object per line.
+type LabelRecord struct {
+	// Type is the type of the object being labelled.
+	Type LabelType `json:"type"`
+
+	// Ref is the reference to the object, e.g. a txid for transactions, an
+	// encoded address for addresses or txid:vout for outputs.
+	Ref string `json:"ref"`
+
+	// Label is the user-defined label of the object.
+	Label string `json:"label,omitempty"`
+
+	// Origin is an optional key origin of the object in descriptor
+	// notation.
+	Origin string `json:"origin,omitempty"`
+
+	// Spendable is only used for output records and indicates whether the
+	// output may be used for coin selection.
+	Spendable *bool `json:"spendable,omitempty"`
+}
+
+// LabelConflict describes an imported record that disagrees with the state
+// already stored in the wallet.
+type LabelConflict struct {
+	// Imported is the record found in the import.
+	Imported LabelRecord
+
+	// Existing is the wallet's current state for the same reference.
+	Existing LabelRecord
+}
+
+// SkippedLabel describes an imported record that was not applied.
+type SkippedLabel struct {
+	// Record is the record found in the import.
+	Record LabelRecord
+
+	// Reason describes why the record was not applied.
+	Reason string
+}
+
+// LabelImportReport summarizes the result of a label import.
+type LabelImportReport struct {
+	// Imported is the number of records that changed wallet state.
+	Imported int
+
+	// Unchanged is the number of records that matched the wallet's
+	// current state.
+	Unchanged int
+
+	// Conflicts lists the records that disagree with the wallet's current
+	// state. Unless the import was asked to overwrite, these records have
+	// not been applied.
+	Conflicts []LabelConflict
+
+	// Skipped lists the records that could not be applied, e.g. because
+	// they reference objects unknown to the wallet.
+	Skipped []SkippedLabel
+}
+
+// ErrAddrLabelExists is returned when an address already has a label and an
+// attempt has been made to label it without setting overwrite to true.
+var ErrAddrLabelExists = errors.New("address already labelled")
+
+// AddressLabel returns the user-defined label of a wallet address. An empty
+// string is returned if the address has not been labelled.
+func (w *Wallet) AddressLabel(a btcutil.Address) (string, error) {
+	var label string
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		label, err = w.Manager.AddrLabel(addrmgrNs, a)
+		return err
+	})
+	return label, err
+}
+
+// LabelAddress sets the label of a wallet address. The call fails if the
+// address already has a different label and overwrite is not set. An empty
+// label removes the existing label.
+func (w *Wallet) LabelAddress(a btcutil.Address, label string,
+	overwrite bool) error {
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		existing, err := w.Manager.AddrLabel(addrmgrNs, a)
+		if err != nil {
+			return err
+		}
+		if existing != "" && existing != label && !overwrite {
+			return ErrAddrLabelExists
+		}
+
+		return w.Manager.SetAddrLabel(addrmgrNs, a, label)
+	})
+}
+
+// ExportLabels writes all transaction labels, address labels, account names
+// and unspendable outputs known to the wallet to w as BIP-0329 JSON lines.
+func (w *Wallet) ExportLabels(out io.Writer) error {
+	records, err := w.labelRecords()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// labelRecords gathers the wallet's label records in a deterministic order:
+// accounts, addresses, transactions and finally outputs.
+func (w *Wallet) labelRecords() ([]LabelRecord, error) {
+	var (
+		xpubs, addrs, txs, outputs []LabelRecord
+	)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+
+		for _, scopedMgr := range w.Manager.ActiveScopedKeyManagers() {
+			records, err := accountLabelRecords(addrmgrNs, scopedMgr)
+			if err != nil {
+				return err
+			}
+			xpubs = append(xpubs, records...)
+
+			records, err = addrLabelRecords(addrmgrNs, scopedMgr)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, records...)
+		}
+
+		labels, err := fetchAllLabels(tx)
+		if err != nil {
+			return err
+		}
+		for txid, label := range labels {
+			txs = append(txs, LabelRecord{
+				Type:  LabelTypeTx,
+				Ref:   txid.String(),
+				Label: label,
+			})
+		}
+
+		leased, err := w.TxStore.ListLockedOutputs(txmgrNs)
+		if err != nil {
+			return err
+		}
+		frozen := make(map[wire.OutPoint]struct{}, len(leased))
+		for _, output := range leased {
+			frozen[output.Outpoint] = struct{}{}
+		}
+		for _, op := range w.lockedOutpointsSnapshot() {
+			frozen[op] = struct{}{}
+		}
+		for op := range frozen {
+			spendable := false
+			outputs = append(outputs, LabelRecord{
+				Type:      LabelTypeOutput,
+				Ref:       op.String(),
+				Spendable: &spendable,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByRef := func(records []LabelRecord) {
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].Ref < records[j].Ref
+		})
+	}
+	sortByRef(txs)
+	sortByRef(outputs)
+
+	records := make(
+		[]LabelRecord, 0, len(xpubs)+len(addrs)+len(txs)+len(outputs),
+	)
+	records = append(records, xpubs...)
+	records = append(records, addrs...)
+	records = append(records, txs...)
+	records = append(records, outputs...)
+
+	return records, nil
+}
+
+// accountLabelRecords returns an xpub record labelled with the account name
+// for every derived account of the scoped manager.
+func accountLabelRecords(ns walletdb.ReadBucket,
+	scopedMgr *waddrmgr.ScopedKeyManager) ([]LabelRecord, error) {
+
+	var records []LabelRecord
+	err := scopedMgr.ForEachAccount(ns, func(account uint32) error {
+		if account == waddrmgr.ImportedAddrAccount {
+			return nil
+		}
+
+		props, err := scopedMgr.AccountProperties(ns, account)
+		if err != nil {
+			return err
+		}
+		if props.AccountPubKey == nil {
+			return nil
+		}
+
+		records = append(records, LabelRecord{
+			Type:   LabelTypeXPub,
+			Ref:    props.AccountPubKey.String(),
+			Label:  props.AccountName,
+			Origin: accountOrigin(props),
+		})
+		return nil
+	})
+	return records, err
+}
+
+// addrLabelRecords returns an addr record for every labelled address of the
+// scoped manager.
+func addrLabelRecords(ns walletdb.ReadBucket,
+	scopedMgr *waddrmgr.ScopedKeyManager) ([]LabelRecord, error) {
+
+	// The labels can't be looked up while iterating since the scoped
+	// manager holds its lock for the duration of the iteration.
+	var addrs []btcutil.Address
+	err := scopedMgr.ForEachActiveAddress(ns, func(a btcutil.Address) error {
+		addrs = append(addrs, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []LabelRecord
+	for _, addr := range addrs {
+		label, err := scopedMgr.AddrLabel(ns, addr)
+		if err != nil {
+			return nil, err
+		}
+		if label == "" {
+			continue
+		}
+
+		records = append(records, LabelRecord{
+			Type:  LabelTypeAddr,
+			Ref:   addr.EncodeAddress(),
+			Label: label,
+		})
+	}
+
+	return records, nil
+}
+
+// accountOrigin returns the key origin of an account in descriptor notation,
+// e.g. wpkh([d34db33f/84'/0'/0']). An empty string is returned if the master
+// key fingerprint of the account is unknown.
+func accountOrigin(props *waddrmgr.AccountProperties) string {
+	if props.MasterKeyFingerprint == 0 {
+		return ""
+	}
+
+	// The fingerprint is stored in the same little-endian form used by
+	// PSBT key derivation paths.
+	var fingerprint [4]byte
+	binary.LittleEndian.PutUint32(
+		fingerprint[:], props.MasterKeyFingerprint,
+	)
+
+	keyOrigin := fmt.Sprintf("[%x/%d'/%d'/%d']", fingerprint,
+		props.KeyScope.Purpose, props.KeyScope.Coin,
+		props.AccountNumber)
+
+	switch props.KeyScope {
+	case waddrmgr.KeyScopeBIP0044:
+		return "pkh(" + keyOrigin + ")"
+	case waddrmgr.KeyScopeBIP0049Plus:
+		return "sh(wpkh(" + keyOrigin + "))"
+	case waddrmgr.KeyScopeBIP0084:
+		return "wpkh(" + keyOrigin + ")"
+	case waddrmgr.KeyScopeBIP0086:
+		return "tr(" + keyOrigin + ")"
+	default:
+		return keyOrigin
+	}
+}
+
+// lockedOutpointsSnapshot returns a copy of the outpoints locked in memory.
+func (w *Wallet) lockedOutpointsSnapshot() []wire.OutPoint {
+	w.lockedOutpointsMtx.Lock()
+	defer w.lockedOutpointsMtx.Unlock()
+
+	ops := make([]wire.OutPoint, 0, len(w.lockedOutpoints))
+	for op := range w.lockedOutpoints {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// ReadLabelRecords decodes BIP-0329 JSON lines from r. Empty lines are
+// ignored. An error wrapping ErrInvalidLabelRecord is returned for the first
+// line that can not be decoded.
+func ReadLabelRecords(r io.Reader) ([]LabelRecord, error) {
+	var records []LabelRecord
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLabelRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record LabelRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v",
+				ErrInvalidLabelRecord, line, err)
+		}
+		if record.Type == "" || record.Ref == "" {
+			return nil, fmt.Errorf("%w: line %d: missing type or "+
+				"ref", ErrInvalidLabelRecord, line)
+		}
+
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// ImportLabels reads BIP-0329 JSON lines from r and applies them to the
+// wallet. Transaction and address labels are stored, xpub records rename the
+// matching account and output records lock or release outputs depending on
+// their spendable flag.
+//
+// Records that disagree with the wallet's current state are reported as
+// conflicts and are only applied if overwrite is set. Records that reference
+// objects unknown to the wallet, or types the wallet doesn't store, are
+// reported as skipped. The import is applied atomically: if the input can't
+// be decoded, no changes are made.
+func (w *Wallet) ImportLabels(r io.Reader,
+	overwrite bool) (*LabelImportReport, error) {
+
+	records, err := ReadLabelRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		report   LabelImportReport
+		renamed  []*waddrmgr.AccountProperties
+		released []wire.OutPoint
+	)
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		// Reset the results in case the transaction is retried.
+		report = LabelImportReport{}
+		renamed = nil
+		released = nil
+
+		imp := &labelImporter{
+			w:         w,
+			addrmgrNs: addrmgrNs,
+			txmgrNs:   txmgrNs,
+			overwrite: overwrite,
+			report:    &report,
+		}
+		if err := imp.loadAccounts(); err != nil {
+			return err
+		}
+		if err := imp.loadLeases(); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			var err error
+			switch record.Type {
+			case LabelTypeTx:
+				err = imp.importTxLabel(record)
+
+			case LabelTypeAddr:
+				err = imp.importAddrLabel(record)
+
+			case LabelTypeXPub:
+				err = imp.importAccountName(record)
+
+			case LabelTypeOutput:
+				err = imp.importOutput(record)
+
+			default:
+				imp.skip(record, "record type not supported")
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		renamed = imp.renamed
+		released = imp.released
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, props := range renamed {
+		w.NtfnServer.notifyAccountProperties(props)
+	}
+	for _, op := range released {
+		w.UnlockOutpoint(op)
+	}
+
+	return &report, nil
+}
+
+// importedAccount identifies an account targeted by an xpub label record.
+type importedAccount struct {
+	scopedMgr *waddrmgr.ScopedKeyManager
+	props     *waddrmgr.AccountProperties
+}
+
+// labelImporter holds the state of a single label import transaction.
+type labelImporter struct {
+	w         *Wallet
+	addrmgrNs walletdb.ReadWriteBucket
+	txmgrNs   walletdb.ReadWriteBucket
+	overwrite bool
+	report    *LabelImportReport
+
+	// accounts maps an encoded account xpub to its account.
+	accounts map[string]importedAccount
+
+	// leases maps each leased output to the ID it is locked with.
+	leases map[wire.OutPoint]wtxmgr.LockID
+
+	renamed  []*waddrmgr.AccountProperties
+	released []wire.OutPoint
+}
+
+// skip records that a label record was not applied.
+func (l *labelImporter) skip(record LabelRecord, reason string) {
+	l.report.Skipped = append(l.report.Skipped, SkippedLabel{
+		Record: record,
+		Reason: reason,
+	})
+}
+
+// conflict records that a label record disagrees with the wallet and returns
+// whether the record should be applied regardless.
+func (l *labelImporter) conflict(record, existing LabelRecord) bool {
+	l.report.Conflicts = append(l.report.Conflicts, LabelConflict{
+		Imported: record,
+		Existing: existing,
+	})
+	return l.overwrite
+}
+
+// loadAccounts indexes all derived accounts of the wallet by their xpub.
+func (l *labelImporter) loadAccounts() error {
+	l.accounts = make(map[string]importedAccount)
+	for _, scopedMgr := range l.w.Manager.ActiveScopedKeyManagers() {
+		scopedMgr := scopedMgr
+		err := scopedMgr.ForEachAccount(l.addrmgrNs,
+			func(account uint32) error {
+				if account == waddrmgr.ImportedAddrAccount {
+					return nil
+				}
+
+				props, err := scopedMgr.AccountProperties(
+					l.addrmgrNs, account,
+				)
+				if err != nil {
+					return err
+				}
+				if props.AccountPubKey == nil {
+					return nil
+				}
+
+				xpub := props.AccountPubKey.String()
+				l.accounts[xpub] = importedAccount{
+					scopedMgr: scopedMgr,
+					props:     props,
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadLeases indexes all leased outputs by their outpoint.
+func (l *labelImporter) loadLeases() error {
+	leased, err := l.w.TxStore.ListLockedOutputs(l.txmgrNs)
+	if err != nil {
+		return err
+	}
+
+	l.leases = make(map[wire.OutPoint]wtxmgr.LockID, len(leased))
+	for _, output := range leased {
+		l.leases[output.Outpoint] = output.LockID
+	}
+	return nil
+}
+
+// importTxLabel applies a tx label record.
+func (l *labelImporter) importTxLabel(record LabelRecord) error {
+	txid, err := chainhash.NewHashFromStr(record.Ref)
+	if err != nil {
+		l.skip(record, "invalid txid")
+		return nil
+	}
+	if record.Label == "" {
+		l.skip(record, "empty label")
+		return nil
+	}
+	if len(record.Label) > wtxmgr.TxLabelLimit {
+		l.skip(record, "label too long")
+		return nil
+	}
+
+	details, err := l.w.TxStore.TxDetails(l.txmgrNs, txid)
+	if err != nil {
+		return err
+	}
+	if details == nil {
+		l.skip(record, "transaction not known to wallet")
+		return nil
+	}
+
+	existing, err := wtxmgr.FetchTxLabel(l.txmgrNs, *txid)
+	switch {
+	case err == wtxmgr.ErrNoLabelBucket || err == wtxmgr.ErrTxLabelNotFound:
+
+	case err != nil:
+		return err
+
+	case existing == record.Label:
+		l.report.Unchanged++
+		return nil
+
+	default:
+		existingRecord := LabelRecord{
+			Type:  LabelTypeTx,
+			Ref:   record.Ref,
+			Label: existing,
+		}
+		if !l.conflict(record, existingRecord) {
+			return nil
+		}
+	}
+
+	err = l.w.TxStore.PutTxLabel(l.txmgrNs, *txid, record.Label)
+	if err != nil {
+		return err
+	}
+
+	l.report.Imported++
+	return nil
+}
+
+// importAddrLabel applies an addr label record.
+func (l *labelImporter) importAddrLabel(record LabelRecord) error {
+	addr, err := btcutil.DecodeAddress(record.Ref, l.w.chainParams)
+	if err != nil || !addr.IsForNet(l.w.chainParams) {
+		l.skip(record, "invalid address for network")
+		return nil
+	}
+	if len(record.Label) > waddrmgr.MaxAddrLabelLen {
+		l.skip(record, "label too long")
+		return nil
+	}
+
+	existing, err := l.w.Manager.AddrLabel(l.addrmgrNs, addr)
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound):
+		l.skip(record, "address not known to wallet")
+		return nil
+
+	case err != nil:
+		return err
+
+	case existing == record.Label:
+		l.report.Unchanged++
+		return nil
+
+	case existing != "":
+		existingRecord := LabelRecord{
+			Type:  LabelTypeAddr,
+			Ref:   record.Ref,
+			Label: existing,
+		}
+		if !l.conflict(record, existingRecord) {
+			return nil
+		}
+	}
+
+	err = l.w.Manager.SetAddrLabel(l.addrmgrNs, addr, record.Label)
+	if err != nil {
+		return err
+	}
+
+	l.report.Imported++
+	return nil
+}
+
+// importAccountName applies an xpub label record by renaming the account
+// with the matching extended public key.
+func (l *labelImporter) importAccountName(record LabelRecord) error {
+	acct, ok := l.accounts[record.Ref]
+	if !ok {
+		l.skip(record, "account not known to wallet")
+		return nil
+	}
+	if record.Label == "" {
+		l.skip(record, "empty label")
+		return nil
+	}
+
+	existing := acct.props.AccountName
+	if existing == record.Label {
+		l.report.Unchanged++
+		return nil
+	}
+
+	existingRecord := LabelRecord{
+		Type:   LabelTypeXPub,
+		Ref:    record.Ref,
+		Label:  existing,
+		Origin: accountOrigin(acct.props),
+	}
+	if !l.conflict(record, existingRecord) {
+		return nil
+	}
+
+	account := acct.props.AccountNumber
+	err := acct.scopedMgr.RenameAccount(l.addrmgrNs, account, record.Label)
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrDuplicateAccount),
+		waddrmgr.IsError(err, waddrmgr.ErrInvalidAccount):
+
+		l.skip(record, err.Error())
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	props, err := acct.scopedMgr.AccountProperties(l.addrmgrNs, account)
+	if err != nil {
+		return err
+	}
+	acct.props = props
+	l.accounts[record.Ref] = acct
+	l.renamed = append(l.renamed, props)
+
+	l.report.Imported++
+	return nil
+}
+
+// importOutput applies the spendable flag of an output record. Output labels
+// themselves aren't stored by the wallet.
+func (l *labelImporter) importOutput(record LabelRecord) error {
+	if record.Spendable == nil {
+		l.skip(record, "output labels not supported")
+		return nil
+	}
+
+	op, err := parseLabelOutPoint(record.Ref)
+	if err != nil {
+		l.skip(record, "invalid outpoint")
+		return nil
+	}
+
+	id, leased := l.leases[op]
+	spendable := *record.Spendable
+
+	switch {
+	// The output is already in the requested state.
+	case !spendable && leased:
+		l.report.Unchanged++
+		return nil
+
+	// The output isn't leased, but may still be locked in memory.
+	case spendable && !leased:
+		if !l.w.LockedOutpoint(op) {
+			l.report.Unchanged++
+			return nil
+		}
+		l.released = append(l.released, op)
+
+	// The output should be frozen.
+	case !spendable:
+		_, err := l.w.TxStore.LockOutput(
+			l.txmgrNs, FrozenOutputLockID, op,
+			FrozenOutputLeaseDuration,
+		)
+		if err == wtxmgr.ErrUnknownOutput {
+			l.skip(record, "output not known to wallet")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		l.leases[op] = FrozenOutputLockID
+
+	// The output should be released, but someone else holds the lease.
+	// Their lease is only released when overwriting.
+	case id != FrozenOutputLockID:
+		notSpendable := false
+		existingRecord := LabelRecord{
+			Type:      LabelTypeOutput,
+			Ref:       record.Ref,
+			Spendable: &notSpendable,
+		}
+		if !l.conflict(record, existingRecord) {
+			return nil
+		}
+		fallthrough
+
+	default:
+		err := l.w.TxStore.UnlockOutput(l.txmgrNs, id, op)
+		if err != nil {
+			return err
+		}
+		delete(l.leases, op)
+		l.released = append(l.released, op)
+	}
+
+	l.report.Imported++
+	return nil
+}
+
+// parseLabelOutPoint parses an outpoint in the txid:vout notation used by
+// BIP-0329 output and input records.
+func parseLabelOutPoint(ref string) (wire.OutPoint, error) {
+	parts := strings.Split(ref, ":")
+	if len(parts) != 2 {
+		return wire.OutPoint{}, fmt.Errorf("invalid outpoint %q", ref)
+	}
+
+	hash, err := chainhash.NewHashFromStr(parts[0])
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	return wire.OutPoint{Hash: *hash, Index: uint32(index)}, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLabelsExportImport tests that transaction labels, address labels,
+// account names and frozen outputs survive a BIP-0329 round trip and that
+// conflicting labels and leases are reported rather than overwritten unless
+// requested.
+func TestLabelsExportImport(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	incomingTx := &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(100000, pkScript)},
+	}
+	addUtxo(t, w, incomingTx)
+	txHash := incomingTx.TxHash()
+	op := wire.OutPoint{Hash: txHash, Index: 0}
+
+	require.NoError(t, w.LabelTransaction(txHash, "salary", false))
+	require.NoError(t, w.LabelAddress(addr, "payroll", false))
+	require.ErrorIs(
+		t, w.LabelAddress(addr, "other", false), ErrAddrLabelExists,
+	)
+	require.NoError(t, w.RenameAccount(
+		waddrmgr.KeyScopeBIP0084, 0, "savings",
+	))
+	w.LockOutpoint(op)
+
+	var export bytes.Buffer
+	require.NoError(t, w.ExportLabels(&export))
+
+	records, err := ReadLabelRecords(bytes.NewReader(export.Bytes()))
+	require.NoError(t, err)
+
+	found := make(map[LabelType][]LabelRecord)
+	for _, record := range records {
+		found[record.Type] = append(found[record.Type], record)
+	}
+	require.Len(t, found[LabelTypeTx], 1)
+	require.Equal(t, txHash.String(), found[LabelTypeTx][0].Ref)
+	require.Equal(t, "salary", found[LabelTypeTx][0].Label)
+	require.Len(t, found[LabelTypeAddr], 1)
+	require.Equal(t, addr.EncodeAddress(), found[LabelTypeAddr][0].Ref)
+	require.Equal(t, "payroll", found[LabelTypeAddr][0].Label)
+	require.Len(t, found[LabelTypeOutput], 1)
+	require.Equal(t, op.String(), found[LabelTypeOutput][0].Ref)
+	require.False(t, *found[LabelTypeOutput][0].Spendable)
+
+	var savings int
+	for _, record := range found[LabelTypeXPub] {
+		if record.Label == "savings" {
+			savings++
+		}
+	}
+	require.Equal(t, 1, savings)
+
+	// Re-importing the export into the same wallet changes nothing except
+	// persisting the in-memory lock as a lease.
+	report, err := w.ImportLabels(bytes.NewReader(export.Bytes()), false)
+	require.NoError(t, err)
+	require.Empty(t, report.Conflicts)
+	require.Empty(t, report.Skipped)
+	require.Equal(t, 1, report.Imported)
+
+	leased, err := w.ListLeasedOutputs()
+	require.NoError(t, err)
+	require.Len(t, leased, 1)
+	require.Equal(t, FrozenOutputLockID, leased[0].LockID)
+
+	// Change the labels so the export conflicts with the wallet.
+	require.NoError(t, w.LabelTransaction(txHash, "bonus", true))
+	require.NoError(t, w.LabelAddress(addr, "invoices", true))
+
+	report, err = w.ImportLabels(bytes.NewReader(export.Bytes()), false)
+	require.NoError(t, err)
+	require.Len(t, report.Conflicts, 2)
+	require.Zero(t, report.Imported)
+
+	label, err := w.AddressLabel(addr)
+	require.NoError(t, err)
+	require.Equal(t, "invoices", label)
+
+	// With overwrite set, the conflicting labels are applied.
+	report, err = w.ImportLabels(bytes.NewReader(export.Bytes()), true)
+	require.NoError(t, err)
+	require.Len(t, report.Conflicts, 2)
+	require.Equal(t, 2, report.Imported)
+
+	label, err = w.AddressLabel(addr)
+	require.NoError(t, err)
+	require.Equal(t, "payroll", label)
+
+	// Marking the output spendable releases the frozen lease, and records
+	// referencing unknown objects are skipped.
+	unknownTx := strings.Repeat("11", 32)
+	importData := `{"type":"output","ref":"` + op.String() +
+		`","spendable":true}
+{"type":"tx","ref":"` + unknownTx + `","label":"unknown"}
+{"type":"pubkey","ref":"02aa","label":"key"}
+`
+	report, err = w.ImportLabels(strings.NewReader(importData), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported)
+	require.Len(t, report.Skipped, 2)
+	require.False(t, w.LockedOutpoint(op))
+
+	leased, err = w.ListLeasedOutputs()
+	require.NoError(t, err)
+	require.Empty(t, leased)
+
+	// A lease held by someone else is a conflict, and is only released
+	// when overwriting.
+	otherID := wtxmgr.LockID{1}
+	_, err = w.LeaseOutput(otherID, op, time.Hour)
+	require.NoError(t, err)
+
+	releaseData := `{"type":"output","ref":"` + op.String() +
+		`","spendable":true}
+`
+	report, err = w.ImportLabels(strings.NewReader(releaseData), false)
+	require.NoError(t, err)
+	require.Len(t, report.Conflicts, 1)
+	require.Zero(t, report.Imported)
+
+	leased, err = w.ListLeasedOutputs()
+	require.NoError(t, err)
+	require.Len(t, leased, 1)
+	require.Equal(t, otherID, leased[0].LockID)
+
+	report, err = w.ImportLabels(strings.NewReader(releaseData), true)
+	require.NoError(t, err)
+	require.Len(t, report.Conflicts, 1)
+	require.Equal(t, 1, report.Imported)
+
+	leased, err = w.ListLeasedOutputs()
+	require.NoError(t, err)
+	require.Empty(t, leased)
+
+	// Malformed input is rejected without applying anything.
+	_, err = w.ImportLabels(strings.NewReader("{not json}\n"), false)
+	require.ErrorIs(t, err, ErrInvalidLabelRecord)
+
+}
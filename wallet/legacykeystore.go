@@ -0,0 +1,356 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// LegacyAddressImport describes the import of an address of a legacy keystore.
+type LegacyAddressImport struct {
+	// Address is the address in the legacy keystore.
+	Address btcutil.Address
+
+	// Script is whether the address pays to a script instead of a public
+	// key.
+	Script bool
+
+	// FirstBlock is the height of the first block the address could
+	// appear in, as recorded by the keystore.
+	FirstBlock int32
+
+	// Birthday is the block the address was imported with.  It's the
+	// genesis block when the block at FirstBlock couldn't be looked up,
+	// because the wallet isn't connected to a chain backend.
+	Birthday waddrmgr.BlockStamp
+
+	// Existed is whether the address was already in the wallet.
+	Existed bool
+
+	// Err is the reason the address couldn't be imported or didn't round
+	// trip, or nil if it's in the wallet with the keystore's key or
+	// script.
+	Err error
+}
+
+// LegacyKeystoreImport is the report of an import of a legacy keystore.
+type LegacyKeystoreImport struct {
+	// Scope is the key scope the addresses were imported into.
+	Scope waddrmgr.KeyScope
+
+	// Account is the account the addresses were imported into, which is
+	// always the imported account.
+	Account uint32
+
+	// Addresses describes the import of each active address of the
+	// keystore.
+	Addresses []LegacyAddressImport
+
+	// BirthdayBlock is the block the wallet's birthday was moved back to
+	// for the oldest imported address, or nil if the birthday was kept.
+	BirthdayBlock *waddrmgr.BlockStamp
+}
+
+// Failed returns the addresses that weren't imported or didn't round trip.
+func (r *LegacyKeystoreImport) Failed() []LegacyAddressImport {
+	var failed []LegacyAddressImport
+	for _, addr := range r.Addresses {
+		if addr.Err != nil {
+			failed = append(failed, addr)
+		}
+	}
+	return failed
+}
+
+// ImportLegacyKeystore imports the private keys and scripts of all active
+// addresses of an unlocked legacy keystore into the imported account of a key
+// scope of the wallet, which must be unlocked too.  The scope must use
+// pay-to-pubkey-hash addresses so the addresses of the keys are kept.  Addresses already in the
+// wallet are skipped, so the import may be retried.
+//
+// Every address is read back from the wallet to verify it has the keystore's
+// key or script, and those that fail are reported without stopping the
+// import.  The wallet's birthday is moved back to the first block of the
+// oldest address when needed.  When connected to a chain backend, the imported
+// addresses are either rescanned from there or watched from now on.
+func (w *Wallet) ImportLegacyKeystore(ks *keystore.Store,
+	scope waddrmgr.KeyScope, rescan bool) (*LegacyKeystoreImport, error) {
+
+	if ks.Net().Net != w.chainParams.Net {
+		return nil, fmt.Errorf("legacy keystore is for network %s, "+
+			"not %s", ks.Net().Name, w.chainParams.Name)
+	}
+	if ks.IsLocked() {
+		return nil, keystore.ErrLocked
+	}
+
+	manager, err := w.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		return nil, err
+	}
+	if manager.AddrSchema().ExternalAddrType != waddrmgr.PubKeyHash {
+		return nil, fmt.Errorf("key scope %v doesn't use "+
+			"pay-to-pubkey-hash addresses", scope)
+	}
+
+	// Look up the first blocks of the addresses before opening the
+	// database transaction, as it may query the chain backend.
+	addrs := ks.SortedActiveAddresses()
+	stamps := make(map[int32]waddrmgr.BlockStamp)
+	for _, addr := range addrs {
+		height := addr.FirstBlock()
+		if _, ok := stamps[height]; !ok {
+			stamps[height] = w.legacyBlockStamp(height)
+		}
+	}
+
+	report := &LegacyKeystoreImport{
+		Scope:   scope,
+		Account: waddrmgr.ImportedAddrAccount,
+	}
+	var (
+		imported []btcutil.Address
+		oldest   *waddrmgr.BlockStamp
+		props    *waddrmgr.AccountProperties
+	)
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		for _, addr := range addrs {
+			bs := stamps[addr.FirstBlock()]
+			result := LegacyAddressImport{
+				Address:    addr.Address(),
+				FirstBlock: addr.FirstBlock(),
+				Birthday:   bs,
+			}
+			_, result.Script = addr.(keystore.ScriptAddress)
+
+			err := importLegacyAddress(
+				addrmgrNs, manager, addr, &bs,
+			)
+			switch {
+			case waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress):
+				result.Existed = true
+				err = nil
+
+			case waddrmgr.IsError(err, waddrmgr.ErrDatabase):
+				return err
+			}
+			if err == nil {
+				err = verifyLegacyAddress(
+					addrmgrNs, manager, addr,
+				)
+			}
+			result.Err = err
+			report.Addresses = append(report.Addresses, result)
+
+			if err != nil {
+				log.Warnf("Failed to import legacy address %v: %v",
+					addr.Address(), err)
+				continue
+			}
+			imported = append(imported, addr.Address())
+			if oldest == nil || bs.Height < oldest.Height {
+				stamp := bs
+				oldest = &stamp
+			}
+		}
+
+		if oldest == nil {
+			return nil
+		}
+
+		var err error
+		props, err = manager.AccountProperties(
+			addrmgrNs, waddrmgr.ImportedAddrAccount,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Only move the birthday back, so that no earlier chain events
+		// are missed.  Like for other imports, the birthday block is
+		// marked unverified so it's checked when the wallet next syncs.
+		birthdayBlock, _, err := w.Manager.BirthdayBlock(addrmgrNs)
+		switch {
+		case waddrmgr.IsError(err, waddrmgr.ErrBirthdayBlockNotSet):
+			if !oldest.Timestamp.Before(w.Manager.Birthday()) {
+				return nil
+			}
+			report.BirthdayBlock = oldest
+			return w.Manager.SetBirthday(addrmgrNs, oldest.Timestamp)
+
+		case err != nil:
+			return err
+
+		case oldest.Height >= birthdayBlock.Height:
+			return nil
+		}
+
+		report.BirthdayBlock = oldest
+		err = w.Manager.SetBirthday(addrmgrNs, oldest.Timestamp)
+		if err != nil {
+			return err
+		}
+		return w.Manager.SetBirthdayBlock(addrmgrNs, *oldest, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Imported %d of %d addresses of legacy keystore into %v",
+		len(imported), len(addrs), scope)
+
+	if props != nil {
+		w.NtfnServer.notifyAccountProperties(props)
+	}
+
+	chainClient := w.ChainClient()
+	if chainClient == nil || len(imported) == 0 {
+		return report, nil
+	}
+	if rescan {
+		// Do not block on finishing the rescan, which is logged
+		// elsewhere.
+		_ = w.SubmitRescan(&RescanJob{
+			Addrs:      imported,
+			BlockStamp: *oldest,
+		})
+	} else {
+		err := chainClient.NotifyReceived(imported)
+		if err != nil {
+			return report, fmt.Errorf("unable to subscribe for "+
+				"address notifications: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// legacyBlockStamp returns the block at the height a legacy keystore address
+// was first seen at, or the genesis block if the height isn't known or the
+// block can't be looked up.
+func (w *Wallet) legacyBlockStamp(height int32) waddrmgr.BlockStamp {
+	genesis := waddrmgr.BlockStamp{
+		Hash:      *w.chainParams.GenesisHash,
+		Height:    0,
+		Timestamp: w.chainParams.GenesisBlock.Header.Timestamp,
+	}
+
+	chainClient := w.ChainClient()
+	if height <= 0 || chainClient == nil {
+		return genesis
+	}
+
+	hash, err := chainClient.GetBlockHash(int64(height))
+	if err != nil {
+		log.Warnf("Unable to look up block at height %d: %v", height,
+			err)
+		return genesis
+	}
+	header, err := chainClient.GetBlockHeader(hash)
+	if err != nil {
+		log.Warnf("Unable to look up block %v: %v", hash, err)
+		return genesis
+	}
+
+	return waddrmgr.BlockStamp{
+		Hash:      *hash,
+		Height:    height,
+		Timestamp: header.Timestamp,
+	}
+}
+
+// importLegacyAddress imports the private key or script of a legacy keystore
+// address into a scoped manager.
+func importLegacyAddress(ns walletdb.ReadWriteBucket,
+	manager *waddrmgr.ScopedKeyManager, addr keystore.WalletAddress,
+	bs *waddrmgr.BlockStamp) error {
+
+	switch addr := addr.(type) {
+	case keystore.PubKeyAddress:
+		privKey, err := addr.PrivKey()
+		if err != nil {
+			return err
+		}
+		wif, err := btcutil.NewWIF(
+			privKey, manager.ChainParams(), addr.Compressed(),
+		)
+		if err != nil {
+			return err
+		}
+		_, err = manager.ImportPrivateKey(ns, wif, bs)
+		return err
+
+	case keystore.ScriptAddress:
+		_, err := manager.ImportScript(ns, addr.Script(), bs)
+		return err
+
+	default:
+		return fmt.Errorf("unsupported legacy address type %T", addr)
+	}
+}
+
+// verifyLegacyAddress checks that a legacy keystore address is in the imported
+// account of a scoped manager, with the keystore's private key or script.
+func verifyLegacyAddress(ns walletdb.ReadBucket,
+	manager *waddrmgr.ScopedKeyManager, addr keystore.WalletAddress) error {
+
+	managedAddr, err := manager.Address(ns, addr.Address())
+	if err != nil {
+		return err
+	}
+	if managedAddr.Address().EncodeAddress() !=
+		addr.Address().EncodeAddress() {
+
+		return fmt.Errorf("address round trips to %v",
+			managedAddr.Address())
+	}
+	if managedAddr.InternalAccount() != waddrmgr.ImportedAddrAccount {
+		return fmt.Errorf("address is in account %d",
+			managedAddr.InternalAccount())
+	}
+
+	switch addr := addr.(type) {
+	case keystore.PubKeyAddress:
+		pubKeyAddr, ok := managedAddr.(waddrmgr.ManagedPubKeyAddress)
+		if !ok {
+			return errors.New("address isn't for a public key")
+		}
+		privKey, err := pubKeyAddr.PrivKey()
+		if err != nil {
+			return err
+		}
+		legacyPrivKey, err := addr.PrivKey()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(privKey.Serialize(), legacyPrivKey.Serialize()) {
+			return errors.New("private key doesn't match")
+		}
+
+	case keystore.ScriptAddress:
+		scriptAddr, ok := managedAddr.(waddrmgr.ManagedScriptAddress)
+		if !ok {
+			return errors.New("address isn't for a script")
+		}
+		script, err := scriptAddr.Script()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(script, addr.Script()) {
+			return errors.New("script doesn't match")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportLegacyKeystore ensures that the keys and scripts of a legacy
+// keystore are imported with their addresses kept, that the wallet's birthday
+// is moved back to the oldest address and that the import can be retried.
+func TestImportLegacyKeystore(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	blockTime := time.Unix(1300000000, 0)
+	w.chainClient = &mockChainClient{
+		getBlockHashFunc: func() (*chainhash.Hash, error) {
+			return &chainhash.Hash{1}, nil
+		},
+		getBlockHeader: &wire.BlockHeader{Timestamp: blockTime},
+	}
+
+	// Create a keystore with a chained address, an imported uncompressed
+	// key and an imported script, each first seen at a different height.
+	passphrase := []byte("legacy")
+	ks, err := keystore.New(
+		t.TempDir(), "legacy", passphrase, &chaincfg.TestNet3Params,
+		&keystore.BlockStamp{Hash: &chainhash.Hash{}, Height: 100},
+	)
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(passphrase))
+
+	_, err = ks.NextChainedAddress(
+		&keystore.BlockStamp{Hash: &chainhash.Hash{}, Height: 120},
+	)
+	require.NoError(t, err)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	wif, err := btcutil.NewWIF(privKey, &chaincfg.TestNet3Params, false)
+	require.NoError(t, err)
+	_, err = ks.ImportPrivateKey(
+		wif, &keystore.BlockStamp{Hash: &chainhash.Hash{}, Height: 50},
+	)
+	require.NoError(t, err)
+
+	pubKeyAddr, err := btcutil.NewAddressPubKey(
+		privKey.PubKey().SerializeCompressed(),
+		&chaincfg.TestNet3Params,
+	)
+	require.NoError(t, err)
+	script, err := txscript.MultiSigScript(
+		[]*btcutil.AddressPubKey{pubKeyAddr}, 1,
+	)
+	require.NoError(t, err)
+	_, err = ks.ImportScript(
+		script, &keystore.BlockStamp{Hash: &chainhash.Hash{}, Height: 75},
+	)
+	require.NoError(t, err)
+
+	legacyAddrs := ks.SortedActiveAddresses()
+
+	// Only a pay-to-pubkey-hash scope accepts the keys.
+	_, err = w.ImportLegacyKeystore(ks, waddrmgr.KeyScopeBIP0084, false)
+	require.Error(t, err)
+
+	report, err := w.ImportLegacyKeystore(
+		ks, waddrmgr.KeyScopeBIP0044, false,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, waddrmgr.ImportedAddrAccount, report.Account)
+	require.Empty(t, report.Failed())
+	require.Len(t, report.Addresses, len(legacyAddrs))
+
+	for i, result := range report.Addresses {
+		require.Equal(t, legacyAddrs[i].Address(), result.Address)
+		require.Equal(t, legacyAddrs[i].FirstBlock(), result.FirstBlock)
+		require.Equal(t, result.FirstBlock, result.Birthday.Height)
+		require.False(t, result.Existed)
+
+		addr, err := w.AddressInfo(result.Address)
+		require.NoError(t, err)
+		require.Equal(t, result.Address.EncodeAddress(),
+			addr.Address().EncodeAddress())
+		require.EqualValues(t, waddrmgr.ImportedAddrAccount,
+			addr.InternalAccount())
+	}
+
+	require.NotNil(t, report.BirthdayBlock)
+	require.EqualValues(t, 50, report.BirthdayBlock.Height)
+	require.Equal(t, blockTime, w.Manager.Birthday())
+
+	// Importing the keystore again finds all addresses in the wallet.
+	report, err = w.ImportLegacyKeystore(
+		ks, waddrmgr.KeyScopeBIP0044, false,
+	)
+	require.NoError(t, err)
+	require.Empty(t, report.Failed())
+	for _, result := range report.Addresses {
+		require.True(t, result.Existed)
+	}
+	require.Nil(t, report.BirthdayBlock)
+}
@@ -5,6 +5,7 @@
 package wallet
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/encdb"
 	"github.com/btcsuite/btcwallet/internal/prompt"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
@@ -40,17 +43,26 @@ var (
 	// ErrExists describes the error condition of attempting to create a new
 	// wallet when one exists already.
 	ErrExists = errors.New("wallet already exists")
+
+	// ErrInsecureDBPassphrase describes the error condition of encrypting
+	// the wallet database with the default public passphrase, which
+	// anyone could decrypt it with.
+	ErrInsecureDBPassphrase = errors.New("encrypting the wallet database " +
+		"requires a public passphrase other than the default")
 )
 
 // loaderConfig contains the configuration options for the loader.
 type loaderConfig struct {
 	walletSyncRetryInterval time.Duration
+	scanWorkers             int
+	encryptDB               bool
 }
 
 // defaultLoaderConfig returns the default configuration options for the loader.
 func defaultLoaderConfig() *loaderConfig {
 	return &loaderConfig{
 		walletSyncRetryInterval: defaultSyncRetryInterval,
+		scanWorkers:             DefaultScanWorkers,
 	}
 }
 
@@ -65,6 +77,30 @@ func WithWalletSyncRetryInterval(interval time.Duration) LoaderOption {
 	}
 }
 
+// WithScanWorkers specifies the number of requests made concurrently to the
+// chain backend when the wallet scans the chain during rescans and recovery.
+func WithScanWorkers(workers int) LoaderOption {
+	return func(c *loaderConfig) {
+		c.scanWorkers = workers
+	}
+}
+
+// WithEncryptedDB specifies that the contents of the wallet database should be
+// encrypted with the public passphrase.  New wallets are created encrypted, and
+// the database of an existing wallet is encrypted when it's opened.  Wallets
+// that are already encrypted are always decrypted when they're opened, whether
+// or not this option is set.
+//
+// Creating or encrypting a wallet with the default public passphrase fails
+// with ErrInsecureDBPassphrase.
+//
+// NOTE: This only applies to the databases created by the loader itself.
+func WithEncryptedDB() LoaderOption {
+	return func(c *loaderConfig) {
+		c.encryptDB = true
+	}
+}
+
 // Loader implements the creating of new and opening of existing wallets, while
 // providing a callback system for other subsystems to handle the loading of a
 // wallet.  This is primarily intended for use by the RPC servers, to enable
@@ -252,6 +288,11 @@ func (l *Loader) createNewWallet(pubPassphrase, privPassphrase []byte,
 		return nil, ErrExists
 	}
 
+	encryptDB := l.localDB && l.cfg.encryptDB
+	if encryptDB && isDefaultPubPassphrase(pubPassphrase) {
+		return nil, ErrInsecureDBPassphrase
+	}
+
 	if l.localDB {
 		dbPath := filepath.Join(l.dbDirPath, WalletDBName)
 
@@ -266,6 +307,15 @@ func (l *Loader) createNewWallet(pubPassphrase, privPassphrase []byte,
 		if err != nil {
 			return nil, err
 		}
+
+		if l.cfg.encryptDB {
+			db, err := encdb.Create(l.db, pubPassphrase)
+			if err != nil {
+				_ = l.db.Close()
+				return nil, err
+			}
+			l.db = db
+		}
 	}
 
 	// Initialize the newly created database for the wallet before opening.
@@ -295,6 +345,7 @@ func (l *Loader) createNewWallet(pubPassphrase, privPassphrase []byte,
 	if err != nil {
 		return nil, err
 	}
+	w.scanWorkers = l.cfg.scanWorkers
 	w.Start()
 
 	l.onLoaded(w)
@@ -330,13 +381,24 @@ func (l *Loader) OpenExistingWallet(pubPassphrase []byte,
 
 		// Open the database using the boltdb backend.
 		dbPath := filepath.Join(l.dbDirPath, WalletDBName)
-		l.db, err = walletdb.Open(
-			"bdb", dbPath, l.noFreelistSync, l.timeout,
-		)
+		var encrypted bool
+		l.db, encrypted, err = l.openDB(dbPath, pubPassphrase)
 		if err != nil {
 			log.Errorf("Failed to open database: %v", err)
 			return nil, err
 		}
+
+		// Back up the database before the wallet migrates it, so that
+		// the migrations can be rolled back.
+		backupPath := dbPath + MigrationBackupSuffix
+		err = l.backupBeforeMigrations(
+			backupPath, pubPassphrase, encrypted,
+		)
+		if err != nil {
+			log.Errorf("Failed to back up database: %v", err)
+			_ = l.db.Close()
+			return nil, err
+		}
 	}
 
 	var cbs *waddrmgr.OpenCallbacks
@@ -368,12 +430,171 @@ func (l *Loader) OpenExistingWallet(pubPassphrase []byte,
 
 		return nil, err
 	}
+	w.scanWorkers = l.cfg.scanWorkers
 	w.Start()
 
 	l.onLoaded(w)
 	return w, nil
 }
 
+// openDB opens the wallet database at the given path, decrypting it if it's
+// encrypted, or encrypting it first if the loader is configured to.  Whether
+// the returned database is encrypted is returned as well.
+func (l *Loader) openDB(dbPath string, pubPassphrase []byte) (walletdb.DB,
+	bool, error) {
+
+	db, encrypted, err := openDBFile(
+		dbPath, pubPassphrase, l.noFreelistSync, l.timeout,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	if encrypted && isDefaultPubPassphrase(pubPassphrase) {
+		log.Warnf("The wallet database is encrypted with the default " +
+			"public passphrase, which does not protect its contents")
+	}
+	if encrypted || !l.cfg.encryptDB {
+		return db, encrypted, nil
+	}
+	if isDefaultPubPassphrase(pubPassphrase) {
+		_ = db.Close()
+		return nil, false, ErrInsecureDBPassphrase
+	}
+
+	// Make sure the passphrase is right before encrypting the database
+	// with it.  An outdated address manager can't be opened before the
+	// wallet upgrades it, so the database is only encrypted the next time
+	// it's opened.
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		mgr, err := waddrmgr.Open(addrmgrNs, pubPassphrase, l.chainParams)
+		if err != nil {
+			return err
+		}
+		mgr.Close()
+		return nil
+	})
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrUpgrade):
+		log.Infof("Wallet database will be encrypted once upgraded")
+		return db, false, nil
+
+	case err != nil:
+		_ = db.Close()
+		return nil, false, err
+	}
+
+	log.Infof("Encrypting wallet database")
+
+	encPath := dbPath + ".encrypting"
+	err = encryptDB(db, encPath, pubPassphrase, l.noFreelistSync, l.timeout)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(encPath)
+		return nil, false, err
+	}
+
+	// Replacing the file unlinks the unencrypted database, although its
+	// contents may still be recoverable from the disk.
+	if err := os.Rename(encPath, dbPath); err != nil {
+		return nil, false, err
+	}
+
+	return l.openDB(dbPath, pubPassphrase)
+}
+
+// backupBeforeMigrations backs up the loaded database to the given path before
+// the wallet migrates it.  When the database is encrypted, or the loader
+// encrypts it, an unencrypted backup left by an earlier migration is removed,
+// and the backup is encrypted even when the database itself is only encrypted
+// once upgraded.
+func (l *Loader) backupBeforeMigrations(backupPath string, pubPassphrase []byte,
+	encrypted bool) error {
+
+	if !encrypted && !l.cfg.encryptDB {
+		return backupBeforeMigrations(l.db, backupPath, BackupDB)
+	}
+
+	if err := l.removeUnencryptedDB(backupPath); err != nil {
+		return err
+	}
+	if encrypted {
+		return backupBeforeMigrations(l.db, backupPath, BackupDB)
+	}
+
+	return backupBeforeMigrations(l.db, backupPath,
+		func(db walletdb.DB, backupPath string) error {
+			tmpPath := backupPath + ".tmp"
+			err := encryptDB(
+				db, tmpPath, pubPassphrase, l.noFreelistSync,
+				l.timeout,
+			)
+			if err != nil {
+				_ = os.Remove(tmpPath)
+				return err
+			}
+			return os.Rename(tmpPath, backupPath)
+		},
+	)
+}
+
+// removeUnencryptedDB removes the database at the given path if it exists and
+// isn't encrypted.
+func (l *Loader) removeUnencryptedDB(dbPath string) error {
+	exists, err := fileExists(dbPath)
+	if err != nil || !exists {
+		return err
+	}
+
+	db, err := walletdb.Open("bdb", dbPath, l.noFreelistSync, l.timeout)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encdb.IsEncrypted(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || encrypted {
+		return err
+	}
+
+	log.Warnf("Removing unencrypted wallet database backup %v", dbPath)
+	return os.Remove(dbPath)
+}
+
+// isDefaultPubPassphrase returns whether the public passphrase is empty or the
+// default one, which doesn't protect an encrypted database.
+func isDefaultPubPassphrase(pubPassphrase []byte) bool {
+	return len(pubPassphrase) == 0 ||
+		bytes.Equal(pubPassphrase, []byte(InsecurePubPassphrase))
+}
+
+// encryptDB writes an encrypted copy of the given wallet database to a new
+// database at the given path.
+func encryptDB(db walletdb.DB, encPath string, pubPassphrase []byte,
+	noFreelistSync bool, timeout time.Duration) error {
+
+	if err := os.RemoveAll(encPath); err != nil {
+		return err
+	}
+
+	dst, err := walletdb.Create("bdb", encPath, noFreelistSync, timeout)
+	if err != nil {
+		return err
+	}
+
+	encDB, err := encdb.Encrypt(dst, db, pubPassphrase)
+	if err != nil {
+		_ = dst.Close()
+		return err
+	}
+
+	// Closing the encrypted database closes the one it wraps.
+	return encDB.Close()
+}
+
 // WalletExists returns whether a file exists at the loader's database path.
 // This may return an error for unexpected I/O failures.
 func (l *Loader) WalletExists() (bool, error) {
@@ -385,6 +606,36 @@ func (l *Loader) WalletExists() (bool, error) {
 	return l.walletExists()
 }
 
+// RollbackTransactionHistory rolls the transaction history of the wallet back
+// to the block at the given height, or with the given hash if it's not nil, as
+// RollbackTransactionHistory does.  The public passphrase is only needed if the
+// database is encrypted.  This returns ErrLoaded if the wallet is loaded.
+func (l *Loader) RollbackTransactionHistory(pubPassphrase []byte, height int32,
+	hash *chainhash.Hash) (*waddrmgr.BlockStamp, error) {
+
+	defer l.mu.Unlock()
+	l.mu.Lock()
+
+	if l.wallet != nil {
+		return nil, ErrLoaded
+	}
+
+	db := l.db
+	if l.localDB {
+		var err error
+		dbPath := filepath.Join(l.dbDirPath, WalletDBName)
+		db, _, err = openDBFile(
+			dbPath, pubPassphrase, l.noFreelistSync, l.timeout,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+	}
+
+	return RollbackTransactionHistory(db, l.chainParams, height, hash)
+}
+
 // LoadedWallet returns the loaded wallet, if any, and a bool for whether the
 // wallet has been loaded or not.  If true, the wallet pointer should be safe to
 // dereference.
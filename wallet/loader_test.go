@@ -0,0 +1,213 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/encdb"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoaderEncryptedDB tests that the loader encrypts the database of an
+// existing wallet when it's configured to, and that encrypted wallets are
+// opened with their public passphrase.
+func TestLoaderEncryptedDB(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pubPass := []byte("hello")
+	privPass := []byte("world")
+
+	newLoader := func(opts ...LoaderOption) *Loader {
+		return NewLoader(
+			&chaincfg.TestNet3Params, dir, true, defaultDBTimeout,
+			250, opts...,
+		)
+	}
+	isEncrypted := func() bool {
+		db, err := walletdb.Open(
+			"bdb", filepath.Join(dir, WalletDBName), true,
+			defaultDBTimeout,
+		)
+		require.NoError(t, err)
+		defer db.Close()
+
+		encrypted, err := encdb.IsEncrypted(db)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+
+	loader := newLoader()
+	w, err := loader.CreateNewWallet(pubPass, privPass, seed, time.Now())
+	require.NoError(t, err)
+	props, err := w.AccountProperties(waddrmgr.KeyScopeBIP0084, 0)
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+	require.False(t, isEncrypted())
+
+	// The database isn't encrypted with a wrong passphrase.
+	loader = newLoader(WithEncryptedDB())
+	_, err = loader.OpenExistingWallet([]byte("wrong"), false)
+	require.Error(t, err)
+	require.False(t, isEncrypted())
+
+	w, err = loader.OpenExistingWallet(pubPass, false)
+	require.NoError(t, err)
+	encProps, err := w.AccountProperties(waddrmgr.KeyScopeBIP0084, 0)
+	require.NoError(t, err)
+	require.Equal(t, props, encProps)
+
+	// The encrypted database follows the changes of the public
+	// passphrase.
+	newPubPass := []byte("hello again")
+	require.NoError(t, w.ChangePublicPassphrase(pubPass, newPubPass))
+	require.NoError(t, loader.UnloadWallet())
+	require.True(t, isEncrypted())
+
+	// The encrypted wallet is opened without the option.
+	loader = newLoader()
+	_, err = loader.OpenExistingWallet(pubPass, false)
+	require.Error(t, err)
+
+	w, err = loader.OpenExistingWallet(newPubPass, false)
+	require.NoError(t, err)
+	encProps, err = w.AccountProperties(waddrmgr.KeyScopeBIP0084, 0)
+	require.NoError(t, err)
+	require.Equal(t, props, encProps)
+	require.NoError(t, loader.UnloadWallet())
+}
+
+// TestLoaderEncryptedDBDefaultPassphrase tests that the loader refuses to
+// encrypt a wallet database with the default public passphrase.
+func TestLoaderEncryptedDBDefaultPassphrase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pubPass := []byte(InsecurePubPassphrase)
+	privPass := []byte("world")
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+
+	loader := NewLoader(
+		&chaincfg.TestNet3Params, dir, true, defaultDBTimeout, 250,
+		WithEncryptedDB(),
+	)
+	_, err = loader.CreateNewWallet(pubPass, privPass, seed, time.Now())
+	require.ErrorIs(t, err, ErrInsecureDBPassphrase)
+	exists, err := loader.WalletExists()
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// An existing wallet isn't encrypted with it either.
+	loader = NewLoader(
+		&chaincfg.TestNet3Params, dir, true, defaultDBTimeout, 250,
+	)
+	_, err = loader.CreateNewWallet(pubPass, privPass, seed, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+
+	loader = NewLoader(
+		&chaincfg.TestNet3Params, dir, true, defaultDBTimeout, 250,
+		WithEncryptedDB(),
+	)
+	_, err = loader.OpenExistingWallet(pubPass, false)
+	require.ErrorIs(t, err, ErrInsecureDBPassphrase)
+}
+
+// TestLoaderEncryptedDBMigrationBackup tests that the backup written before
+// migrating a wallet whose database is encrypted once upgraded is encrypted,
+// and that unencrypted backups are removed once the database is encrypted.
+func TestLoaderEncryptedDBMigrationBackup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, WalletDBName)
+	backupPath := dbPath + MigrationBackupSuffix
+	pubPass := []byte("hello")
+	privPass := []byte("world")
+
+	newLoader := func(opts ...LoaderOption) *Loader {
+		return NewLoader(
+			&chaincfg.TestNet3Params, dir, true, defaultDBTimeout,
+			250, opts...,
+		)
+	}
+	isEncrypted := func(path string) bool {
+		db, err := walletdb.Open("bdb", path, true, defaultDBTimeout)
+		require.NoError(t, err)
+		defer db.Close()
+
+		encrypted, err := encdb.IsEncrypted(db)
+		require.NoError(t, err)
+		return encrypted
+	}
+	writePlaintextBackup := func() {
+		db, err := walletdb.Open("bdb", dbPath, true, defaultDBTimeout)
+		require.NoError(t, err)
+		defer db.Close()
+		require.NoError(t, BackupDB(db, backupPath))
+	}
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+	loader := newLoader()
+	_, err = loader.CreateNewWallet(pubPass, privPass, seed, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+
+	// Leave the unencrypted backup of an earlier migration, and make the
+	// address manager need an upgrade, so that the database is only
+	// encrypted the next time it's opened.
+	writePlaintextBackup()
+	db, err := walletdb.Open("bdb", dbPath, true, defaultDBTimeout)
+	require.NoError(t, err)
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return waddrmgr.NewMigrationManager(ns).SetVersion(ns, 7)
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	loader = newLoader(WithEncryptedDB())
+	_, err = loader.OpenExistingWallet(pubPass, false)
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+	require.False(t, isEncrypted(dbPath))
+
+	// The backup holds the database before the upgrade, encrypted.
+	require.True(t, isEncrypted(backupPath))
+	raw, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), string(waddrmgrNamespaceKey))
+
+	backup, encrypted, err := openDBFile(
+		backupPath, pubPass, true, defaultDBTimeout,
+	)
+	require.NoError(t, err)
+	require.True(t, encrypted)
+	statuses, err := MigrationStatuses(backup)
+	require.NoError(t, err)
+	require.NoError(t, backup.Close())
+	require.EqualValues(t, 7, statuses[1].Version)
+
+	// An unencrypted backup is removed once the database is encrypted,
+	// even without migrations to back up for.
+	writePlaintextBackup()
+	loader = newLoader(WithEncryptedDB())
+	_, err = loader.OpenExistingWallet(pubPass, false)
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+	require.True(t, isEncrypted(dbPath))
+
+	_, err = os.Stat(backupPath)
+	require.True(t, os.IsNotExist(err))
+}
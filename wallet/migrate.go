@@ -0,0 +1,295 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/btcsuite/btcwallet/encdb"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/walletdb/migration"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// MigrationBackupSuffix is appended to the path of a wallet database to name
+// the backup the loader writes before migrating it.
+const MigrationBackupSuffix = ".premigration"
+
+// errRollback is returned to roll back the transaction reading the migration
+// status of a database.
+var errRollback = errors.New("rollback")
+
+// MigrationStatus describes the version of a namespace of the wallet database
+// and the latest version the wallet migrates it to.
+type MigrationStatus struct {
+	// Name is the name of the service owning the namespace.
+	Name string
+
+	// Version is the current version of the namespace.
+	Version uint32
+
+	// LatestVersion is the version the namespace is migrated to.
+	LatestVersion uint32
+}
+
+// Pending returns whether the namespace has migrations to apply.
+func (s *MigrationStatus) Pending() bool {
+	return s.Version < s.LatestVersion
+}
+
+// Unsupported returns whether the namespace was written by a newer wallet,
+// which this wallet refuses to open.
+func (s *MigrationStatus) Unsupported() bool {
+	return s.Version > s.LatestVersion
+}
+
+// migrationManagers returns the migration managers of the namespaces of the
+// wallet database, in the order their migrations are applied.
+func migrationManagers(tx walletdb.ReadWriteTx) ([]migration.Manager, error) {
+	addrMgrBucket := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+	if addrMgrBucket == nil {
+		return nil, errors.New("missing address manager namespace")
+	}
+	txMgrBucket := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+	if txMgrBucket == nil {
+		return nil, errors.New("missing transaction manager namespace")
+	}
+
+	return []migration.Manager{
+		wtxmgr.NewMigrationManager(txMgrBucket),
+		waddrmgr.NewMigrationManager(addrMgrBucket),
+	}, nil
+}
+
+// MigrationStatuses returns the migration status of each namespace of the
+// wallet database.  The database is not modified.
+func MigrationStatuses(db walletdb.DB) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	// The migration managers require a read-write transaction, which is
+	// always rolled back.
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		mgrs, err := migrationManagers(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, mgr := range mgrs {
+			version, err := mgr.CurrentVersion(mgr.Namespace())
+			if err != nil {
+				return err
+			}
+			statuses = append(statuses, MigrationStatus{
+				Name:    mgr.Name(),
+				Version: version,
+				LatestVersion: migration.GetLatestVersion(
+					mgr.Versions(),
+				),
+			})
+		}
+
+		return errRollback
+	})
+	if err != errRollback {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// ApplyMigrations applies the pending migrations of every namespace of the
+// wallet database in a single transaction, as opening the wallet does.
+func ApplyMigrations(db walletdb.DB) error {
+	return walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		mgrs, err := migrationManagers(tx)
+		if err != nil {
+			return err
+		}
+
+		return migration.Upgrade(mgrs...)
+	})
+}
+
+// BackupDB writes a copy of the database to the given path, replacing any
+// file there once the copy is complete.  The copy of an encrypted database
+// remains encrypted.
+func BackupDB(db walletdb.DB, backupPath string) error {
+	tmpPath := backupPath + ".tmp"
+	f, err := os.OpenFile(
+		tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = db.Copy(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, backupPath)
+}
+
+// backupBeforeMigrations writes a backup of the database to the given path
+// with the backup function if it has migrations to apply.
+func backupBeforeMigrations(db walletdb.DB, backupPath string,
+	backup func(walletdb.DB, string) error) error {
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		return err
+	}
+
+	for i := range statuses {
+		if statuses[i].Pending() {
+			log.Infof("Backing up wallet database to %v before "+
+				"migrating it", backupPath)
+			return backup(db, backupPath)
+		}
+	}
+
+	return nil
+}
+
+// openDBFile opens the wallet database at the given path, decrypting it with
+// the public passphrase if it's encrypted.
+func openDBFile(dbPath string, pubPassphrase []byte, noFreelistSync bool,
+	timeout time.Duration) (walletdb.DB, bool, error) {
+
+	db, err := walletdb.Open("bdb", dbPath, noFreelistSync, timeout)
+	if err != nil {
+		return nil, false, err
+	}
+
+	encrypted, err := encdb.IsEncrypted(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, false, err
+	}
+	if !encrypted {
+		return db, false, nil
+	}
+
+	encDB, err := encdb.Open(db, pubPassphrase)
+	if err != nil {
+		_ = db.Close()
+		return nil, false, err
+	}
+	return encDB, true, nil
+}
+
+// DryRunMigrations applies the pending migrations of the wallet database at
+// the given path to a copy of it, and returns the migration status of the
+// migrated copy.  The public passphrase is only needed if the database is
+// encrypted.  The database itself is not modified, and the copy is removed.
+func DryRunMigrations(dbPath string, pubPassphrase []byte,
+	timeout time.Duration) ([]MigrationStatus, error) {
+
+	db, _, err := openDBFile(dbPath, pubPassphrase, true, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	copyPath := dbPath + ".dryrun"
+	err = BackupDB(db, copyPath)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(copyPath)
+
+	dbCopy, _, err := openDBFile(copyPath, pubPassphrase, true, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer dbCopy.Close()
+
+	if err := ApplyMigrations(dbCopy); err != nil {
+		return nil, err
+	}
+
+	return MigrationStatuses(dbCopy)
+}
+
+// RestoreBackup replaces the wallet database at the given path with a backup,
+// such as the one written before migrating it, rolling back every change made
+// since.  The database must not be in use.  The backup itself is kept.
+func RestoreBackup(dbPath, backupPath string, timeout time.Duration) error {
+	// Make sure the backup holds a wallet database before replacing the
+	// database with it.
+	backup, err := walletdb.Open("bdb", backupPath, true, timeout)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encdb.IsEncrypted(backup)
+	if err == nil && !encrypted {
+		err = walletdb.View(backup, func(tx walletdb.ReadTx) error {
+			if tx.ReadBucket(waddrmgrNamespaceKey) == nil {
+				return errors.New("backup is not a wallet " +
+					"database")
+			}
+			return nil
+		})
+	}
+	if closeErr := backup.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Opening the database fails within the timeout while another process
+	// has it open.
+	db, err := walletdb.Open("bdb", dbPath, true, timeout)
+	if err != nil {
+		return err
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	tmpPath := dbPath + ".restoring"
+	if err := copyFile(tmpPath, backupPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, dbPath)
+}
+
+// copyFile copies the file at src to a new file at dst.
+func copyFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMigrationBackupRollback ensures that pending migrations are reported,
+// can be tried on a copy of the database, and are rolled back by restoring
+// the backup the loader writes before applying them.
+func TestMigrationBackupRollback(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, WalletDBName)
+	backupPath := dbPath + MigrationBackupSuffix
+	pubPass := []byte("hello")
+
+	newLoader := func() *Loader {
+		return NewLoader(
+			&chaincfg.TestNet3Params, dir, true, defaultDBTimeout,
+			250,
+		)
+	}
+	statuses := func(path string) []MigrationStatus {
+		db, err := walletdb.Open("bdb", path, true, defaultDBTimeout)
+		require.NoError(t, err)
+		defer db.Close()
+
+		statuses, err := MigrationStatuses(db)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		return statuses
+	}
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	require.NoError(t, err)
+	loader := newLoader()
+	_, err = loader.CreateNewWallet(
+		pubPass, []byte("world"), seed, time.Now(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+
+	for _, s := range statuses(dbPath) {
+		require.False(t, s.Pending())
+		require.False(t, s.Unsupported())
+	}
+
+	// Make the transaction store migration pending again.
+	db, err := walletdb.Open("bdb", dbPath, true, defaultDBTimeout)
+	require.NoError(t, err)
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return wtxmgr.NewMigrationManager(ns).SetVersion(ns, 1)
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s := statuses(dbPath)
+	require.True(t, s[0].Pending())
+	require.EqualValues(t, 1, s[0].Version)
+	require.False(t, s[1].Pending())
+
+	// A dry run migrates a copy of the database, leaving it unchanged.
+	migrated, err := DryRunMigrations(dbPath, pubPass, defaultDBTimeout)
+	require.NoError(t, err)
+	for _, s := range migrated {
+		require.False(t, s.Pending())
+	}
+	require.True(t, statuses(dbPath)[0].Pending())
+	_, err = os.Stat(dbPath + ".dryrun")
+	require.True(t, os.IsNotExist(err))
+
+	// Opening the wallet backs up the database before migrating it.
+	loader = newLoader()
+	_, err = loader.OpenExistingWallet(pubPass, false)
+	require.NoError(t, err)
+	require.NoError(t, loader.UnloadWallet())
+	require.False(t, statuses(dbPath)[0].Pending())
+	require.True(t, statuses(backupPath)[0].Pending())
+
+	// Restoring the backup rolls the migration back.
+	require.NoError(t, RestoreBackup(dbPath, backupPath, defaultDBTimeout))
+	require.True(t, statuses(dbPath)[0].Pending())
+	_, err = os.Stat(backupPath)
+	require.NoError(t, err)
+}
@@ -232,6 +232,53 @@ func (s *NotificationServer) notifyUnminedTransaction(dbtx walletdb.ReadTx, deta
 	}
 }
 
+// notifyRemovedUnminedTransaction notifies the clients of an unmined
+// transaction that was removed from the wallet after it was evicted from the
+// mempool or, if replacedBy is set, replaced by a conflicting transaction. It
+// must be called after the removal so that the new balances are reported.
+func (s *NotificationServer) notifyRemovedUnminedTransaction(dbtx walletdb.ReadTx,
+	details *wtxmgr.TxDetails, replacedBy *chainhash.Hash) {
+
+	defer s.mu.Unlock()
+	s.mu.Lock()
+	clients := s.transactions
+	if len(clients) == 0 {
+		return
+	}
+
+	unminedHashes, err := s.wallet.TxStore.UnminedTxHashes(dbtx.ReadBucket(wtxmgrNamespaceKey))
+	if err != nil {
+		log.Errorf("Cannot fetch unmined transaction hashes: %v", err)
+		return
+	}
+	bals := make(map[uint32]btcutil.Amount)
+	relevantAccounts(s.wallet, bals, []TransactionSummary{
+		makeTxSummary(dbtx, s.wallet, details),
+	})
+	err = totalBalances(dbtx, s.wallet, bals)
+	if err != nil {
+		log.Errorf("Cannot determine balances for relevant accounts: %v", err)
+		return
+	}
+
+	hash := details.Hash
+	n := &TransactionNotifications{
+		UnminedTransactionHashes: unminedHashes,
+		NewBalances:              flattenBalanceMap(bals),
+	}
+	if replacedBy != nil {
+		n.ReplacedTransactions = []ReplacedTransaction{{
+			Hash:       &hash,
+			ReplacedBy: replacedBy,
+		}}
+	} else {
+		n.EvictedTransactions = []*chainhash.Hash{&hash}
+	}
+	for _, c := range clients {
+		c <- n
+	}
+}
+
 func (s *NotificationServer) notifyDetachedBlock(hash *chainhash.Hash) {
 	if s.currentTxNtfn == nil {
 		s.currentTxNtfn = &TransactionNotifications{}
@@ -330,9 +377,12 @@ func (s *NotificationServer) notifyAttachedBlock(dbtx walletdb.ReadTx, block *wt
 // blocks are sorted in the reverse order they were mined.  Attached blocks are
 // sorted in the order mined.
 //
-// All newly added unmined transactions are included.  Removed unmined
-// transactions are not explicitly included.  Instead, the hashes of all
-// transactions still unmined are included.
+// All newly added unmined transactions are included.  Unmined transactions
+// removed because they were evicted from the mempool or replaced by a
+// conflicting transaction are included as well, but those removed for other
+// reasons, e.g. conflicting with a mined transaction, are not explicitly
+// included.  Instead, the hashes of all transactions still unmined are
+// included.
 //
 // If any transactions were involved, each affected account's new total balance
 // is included.
@@ -344,9 +394,19 @@ type TransactionNotifications struct {
 	DetachedBlocks           []*chainhash.Hash
 	UnminedTransactions      []TransactionSummary
 	UnminedTransactionHashes []*chainhash.Hash
+	EvictedTransactions      []*chainhash.Hash
+	ReplacedTransactions     []ReplacedTransaction
 	NewBalances              []AccountBalance
 }
 
+// ReplacedTransaction describes an unmined transaction that was removed from
+// the wallet after a conflicting transaction, with hash ReplacedBy, replaced it
+// in the mempool.
+type ReplacedTransaction struct {
+	Hash       *chainhash.Hash
+	ReplacedBy *chainhash.Hash
+}
+
 // Block contains the properties and all relevant transactions of an attached
 // block.
 type Block struct {
@@ -175,6 +175,59 @@ func (rm *RecoveryManager) ResetBlockBatch() {
 	rm.blockBatch = rm.blockBatch[:0]
 }
 
+// Checkpoint returns the progress of the recovery through the given block, to
+// be persisted so that the recovery can be resumed from it with Restore.
+func (rm *RecoveryManager) Checkpoint(
+	block waddrmgr.BlockStamp) *waddrmgr.RecoveryCheckpoint {
+
+	checkpoint := &waddrmgr.RecoveryCheckpoint{
+		Block: block,
+		Scopes: make(
+			map[waddrmgr.KeyScope]waddrmgr.ScopeRecoveryProgress,
+			len(rm.state.scopes),
+		),
+		WatchedOutPoints: make(
+			map[wire.OutPoint]btcutil.Address,
+			len(rm.state.watchedOutPoints),
+		),
+	}
+	for keyScope, scopeState := range rm.state.scopes {
+		checkpoint.Scopes[keyScope] = waddrmgr.ScopeRecoveryProgress{
+			ExternalNextUnfound: scopeState.ExternalBranch.NextUnfound(),
+			InternalNextUnfound: scopeState.InternalBranch.NextUnfound(),
+		}
+	}
+	for outPoint, addr := range rm.state.watchedOutPoints {
+		checkpoint.WatchedOutPoints[outPoint] = addr
+	}
+
+	return checkpoint
+}
+
+// Restore resumes the recovery from the progress recorded by a checkpoint, by
+// reporting the indexes found on each branch and watching the outpoints found
+// before it was taken. It is meant to be called after Resurrect.
+func (rm *RecoveryManager) Restore(checkpoint *waddrmgr.RecoveryCheckpoint) {
+	for keyScope, progress := range checkpoint.Scopes {
+		scopeState := rm.state.StateForScope(keyScope)
+		if progress.ExternalNextUnfound > 0 {
+			scopeState.ExternalBranch.ReportFound(
+				progress.ExternalNextUnfound - 1,
+			)
+		}
+		if progress.InternalNextUnfound > 0 {
+			scopeState.InternalBranch.ReportFound(
+				progress.InternalNextUnfound - 1,
+			)
+		}
+	}
+
+	for outPoint, addr := range checkpoint.WatchedOutPoints {
+		outPoint := outPoint
+		rm.state.AddWatchedOutPoint(&outPoint, addr)
+	}
+}
+
 // State returns the current RecoveryState.
 func (rm *RecoveryManager) State() *RecoveryState {
 	return rm.state
@@ -4,7 +4,13 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/stretchr/testify/require"
 )
 
 // Harness holds the BranchRecoveryState being tested, the recovery window being
@@ -244,3 +250,61 @@ func assertHaveWant(t *testing.T, i int, msg string, have, want uint32) {
 			line, i, msg, have, want)
 	}
 }
+
+// TestRecoveryManagerCheckpoint asserts that a recovery restored from a
+// checkpoint resumes with the indexes found and outpoints watched when the
+// checkpoint was taken.
+func TestRecoveryManagerCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	const recoveryWindow = 10
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), &chaincfg.MainNetParams,
+	)
+	require.NoError(t, err)
+	outPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+
+	recoveryMgr := wallet.NewRecoveryManager(
+		recoveryWindow, 10, &chaincfg.MainNetParams,
+	)
+	state := recoveryMgr.State()
+	scopeState := state.StateForScope(waddrmgr.KeyScopeBIP0084)
+	scopeState.ExternalBranch.ReportFound(14)
+	scopeState.InternalBranch.ReportFound(3)
+	state.StateForScope(waddrmgr.KeyScopeBIP0086)
+	state.AddWatchedOutPoint(&outPoint, addr)
+
+	block := waddrmgr.BlockStamp{Height: 1000, Hash: chainhash.Hash{2}}
+	checkpoint := recoveryMgr.Checkpoint(block)
+	require.Equal(t, block, checkpoint.Block)
+	require.Equal(t, map[waddrmgr.KeyScope]waddrmgr.ScopeRecoveryProgress{
+		waddrmgr.KeyScopeBIP0084: {
+			ExternalNextUnfound: 15,
+			InternalNextUnfound: 4,
+		},
+		waddrmgr.KeyScopeBIP0086: {},
+	}, checkpoint.Scopes)
+
+	restoredMgr := wallet.NewRecoveryManager(
+		recoveryWindow, 10, &chaincfg.MainNetParams,
+	)
+	restoredMgr.Restore(checkpoint)
+
+	restored := restoredMgr.State()
+	scopeState = restored.StateForScope(waddrmgr.KeyScopeBIP0084)
+	require.EqualValues(t, 15, scopeState.ExternalBranch.NextUnfound())
+	require.EqualValues(t, 4, scopeState.InternalBranch.NextUnfound())
+	scopeState = restored.StateForScope(waddrmgr.KeyScopeBIP0086)
+	require.Zero(t, scopeState.ExternalBranch.NextUnfound())
+	require.Equal(t, map[wire.OutPoint]btcutil.Address{
+		outPoint: addr,
+	}, restored.WatchedOutPoints())
+
+	// The horizon of a restored branch extends past its last found index.
+	horizon, delta := restored.StateForScope(
+		waddrmgr.KeyScopeBIP0084,
+	).ExternalBranch.ExtendHorizon()
+	require.Zero(t, horizon)
+	require.EqualValues(t, 15+recoveryWindow, delta)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 )
 
@@ -274,13 +275,56 @@ out:
 // current best block in the main chain, and is considered an initial sync
 // rescan.
 func (w *Wallet) Rescan(addrs []btcutil.Address, unspent []wtxmgr.Credit) error {
-	return w.rescanWithTarget(addrs, unspent, nil)
+	return w.rescanWithTarget(addrs, unspent, nil, false)
+}
+
+// RescanFromHeight begins a rescan from the block at the given height. The
+// passed addresses are rescanned if any, otherwise all active addresses and
+// unspent outputs of the wallet are. The call blocks until the rescan
+// completes.
+func (w *Wallet) RescanFromHeight(height int32, addrs []btcutil.Address) error {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return err
+	}
+
+	hash, err := chainClient.GetBlockHash(int64(height))
+	if err != nil {
+		return err
+	}
+	header, err := chainClient.GetBlockHeader(hash)
+	if err != nil {
+		return err
+	}
+	startStamp := &waddrmgr.BlockStamp{
+		Hash:      *hash,
+		Height:    height,
+		Timestamp: header.Timestamp,
+	}
+
+	var unspent []wtxmgr.Credit
+	fullScan := len(addrs) == 0
+	if fullScan {
+		err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+			var err error
+			addrs, unspent, err = w.activeData(dbtx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.rescanWithTarget(addrs, unspent, startStamp, fullScan)
 }
 
 // rescanWithTarget performs a rescan starting at the optional startStamp. If
 // none is provided, the rescan will begin from the manager's sync tip.
+// fullScan must only be set if the addresses and unspent outputs are all
+// those of the wallet.
 func (w *Wallet) rescanWithTarget(addrs []btcutil.Address,
-	unspent []wtxmgr.Credit, startStamp *waddrmgr.BlockStamp) error {
+	unspent []wtxmgr.Credit, startStamp *waddrmgr.BlockStamp,
+	fullScan bool) error {
 
 	outpoints := make(map[wire.OutPoint]btcutil.Address, len(unspent))
 	for _, output := range unspent {
@@ -301,6 +345,13 @@ func (w *Wallet) rescanWithTarget(addrs []btcutil.Address,
 		*startStamp = w.Manager.SyncedTo()
 	}
 
+	// Scan the bulk of the blocks ourselves, so that the chain backend
+	// only has to rescan the most recent ones.
+	startStamp, err := w.scanChain(addrs, outpoints, startStamp, fullScan)
+	if err != nil {
+		return err
+	}
+
 	job := &RescanJob{
 		InitialSync: true,
 		Addrs:       addrs,
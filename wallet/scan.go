@@ -0,0 +1,421 @@
+package wallet
+
+import (
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultScanWorkers is the default number of requests made
+	// concurrently to the chain backend when scanning the chain.
+	DefaultScanWorkers = 4
+
+	// scanChunkSize is the number of blocks filtered by each FilterBlocks
+	// request made when scanning the chain.
+	scanChunkSize = 100
+
+	// minScanBlocks is the minimum number of blocks a rescan must span for
+	// the wallet to scan them itself, leaving only the remaining blocks to
+	// the rescan of the chain backend.
+	minScanBlocks = 1000
+)
+
+// chainScanner scans ranges of blocks for the transactions relevant to the
+// wallet. The headers of the blocks are prefetched in windows while the
+// previous window is being filtered, and the blocks of each window are
+// filtered by concurrent FilterBlocks requests. The results are still handed
+// to the caller in height order.
+type chainScanner struct {
+	chainClient chain.Interface
+
+	// workers is the maximum number of requests made concurrently to the
+	// chain backend.
+	workers int
+
+	// windowSize is the number of blocks in each prefetched window.
+	windowSize int
+
+	quit <-chan struct{}
+
+	// stopped, if set, reports whether the scan should end early, as if
+	// the quit channel was closed.
+	stopped func() bool
+}
+
+// newChainScanner returns a chainScanner making at most workers concurrent
+// requests to the chain backend and prefetching windowSize blocks at a time.
+func newChainScanner(chainClient chain.Interface, workers, windowSize int,
+	quit <-chan struct{}) *chainScanner {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &chainScanner{
+		chainClient: chainClient,
+		workers:     workers,
+		windowSize:  windowSize,
+		quit:        quit,
+	}
+}
+
+// quitting returns whether the scan should end early.
+func (s *chainScanner) quitting() bool {
+	select {
+	case <-s.quit:
+		return true
+	default:
+	}
+
+	return s.stopped != nil && s.stopped()
+}
+
+// blockWindow is a window of consecutive blocks prefetched by a chainScanner,
+// or the error encountered while fetching it.
+type blockWindow struct {
+	blocks []wtxmgr.BlockMeta
+	err    error
+}
+
+// prefetch fetches the blocks from start to end, inclusive, in windows of
+// windowSize blocks. At most one window is fetched ahead of the one being
+// consumed, which bounds the memory used. The returned channel is closed once
+// all the windows were sent, after an error, or once done is closed.
+func (s *chainScanner) prefetch(start, end int32,
+	done <-chan struct{}) <-chan blockWindow {
+
+	windows := make(chan blockWindow, 1)
+	go func() {
+		defer close(windows)
+
+		for height := start; height <= end; {
+			last := height + int32(s.windowSize) - 1
+			if last > end {
+				last = end
+			}
+
+			blocks, err := s.fetchBlocks(height, last)
+			select {
+			case windows <- blockWindow{blocks: blocks, err: err}:
+			case <-done:
+				return
+			case <-s.quit:
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			height = last + 1
+		}
+	}()
+
+	return windows
+}
+
+// fetchBlocks concurrently fetches the hashes and timestamps of the blocks
+// from start to end, inclusive.
+func (s *chainScanner) fetchBlocks(start, end int32) ([]wtxmgr.BlockMeta,
+	error) {
+
+	blocks := make([]wtxmgr.BlockMeta, end-start+1)
+
+	var eg errgroup.Group
+	eg.SetLimit(s.workers)
+	for i := range blocks {
+		i := i
+		eg.Go(func() error {
+			if s.quitting() {
+				return ErrWalletShuttingDown
+			}
+
+			height := start + int32(i)
+			hash, err := s.chainClient.GetBlockHash(int64(height))
+			if err != nil {
+				return err
+			}
+			header, err := s.chainClient.GetBlockHeader(hash)
+			if err != nil {
+				return err
+			}
+
+			blocks[i] = wtxmgr.BlockMeta{
+				Block: wtxmgr.Block{
+					Hash:   *hash,
+					Height: height,
+				},
+				Time: header.Timestamp,
+			}
+
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// filter returns the response for the first block of the request matching it,
+// or nil if none does, like FilterBlocks does. The blocks are split in chunks
+// filtered concurrently, and the chunks following one that matched are
+// skipped.
+func (s *chainScanner) filter(
+	req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+
+	numChunks := (len(req.Blocks) + scanChunkSize - 1) / scanChunkSize
+	if numChunks <= 1 {
+		return s.chainClient.FilterBlocks(req)
+	}
+
+	var (
+		resps      = make([]*chain.FilterBlocksResponse, numChunks)
+		firstMatch atomic.Int32
+		eg         errgroup.Group
+	)
+	firstMatch.Store(int32(numChunks))
+	eg.SetLimit(s.workers)
+	for i := 0; i < numChunks; i++ {
+		i := i
+		start := i * scanChunkSize
+		end := start + scanChunkSize
+		if end > len(req.Blocks) {
+			end = len(req.Blocks)
+		}
+
+		chunkReq := *req
+		chunkReq.Blocks = req.Blocks[start:end]
+
+		eg.Go(func() error {
+			// There's no need to filter the chunk if an earlier one
+			// already matched.
+			if int32(i) > firstMatch.Load() {
+				return nil
+			}
+
+			resp, err := s.chainClient.FilterBlocks(&chunkReq)
+			if err != nil || resp == nil {
+				return err
+			}
+
+			resp.BatchIndex += uint32(start)
+			resps[i] = resp
+
+			for {
+				first := firstMatch.Load()
+				if int32(i) >= first ||
+					firstMatch.CompareAndSwap(first, int32(i)) {
+
+					return nil
+				}
+			}
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, resp := range resps {
+		if resp != nil {
+			return resp, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// scan filters the blocks from start to end, inclusive, in height order. For
+// each round, newReq builds the request the remaining blocks of the current
+// window are filtered with, and process is handed the blocks filtered along
+// with the response for the last of them if it matched the request, or nil if
+// none did. The blocks following a match are filtered again in the next
+// round, with a request reflecting what process learned from the match.
+func (s *chainScanner) scan(start, end int32,
+	newReq func([]wtxmgr.BlockMeta) *chain.FilterBlocksRequest,
+	process func([]wtxmgr.BlockMeta, *chain.FilterBlocksResponse) error) error {
+
+	done := make(chan struct{})
+	defer close(done)
+
+	for window := range s.prefetch(start, end, done) {
+		if window.err != nil {
+			return window.err
+		}
+
+		blocks := window.blocks
+		for len(blocks) > 0 {
+			if s.quitting() {
+				return ErrWalletShuttingDown
+			}
+
+			resp, err := s.filter(newReq(blocks))
+			if err != nil {
+				return err
+			}
+
+			n := len(blocks)
+			if resp != nil {
+				n = int(resp.BatchIndex) + 1
+			}
+			if err := process(blocks[:n], resp); err != nil {
+				return err
+			}
+
+			blocks = blocks[n:]
+		}
+	}
+
+	// The windows stop being sent if we're shutting down.
+	if s.quitting() {
+		return ErrWalletShuttingDown
+	}
+
+	return nil
+}
+
+// scanChain scans the chain for the transactions relevant to the passed
+// addresses and outpoints, from the start block up to the current best block,
+// if this spans enough blocks to be worth it. The progress of the scan is
+// checkpointed in the database, so that a scan interrupted by a shutdown is
+// resumed once the wallet syncs again. Only a full scan, of all the active
+// addresses and unspent outputs of the wallet, advances the wallet's sync tip
+// over the scanned blocks. The block from which the chain backend should
+// rescan the remaining blocks is returned.
+func (w *Wallet) scanChain(addrs []btcutil.Address,
+	outpoints map[wire.OutPoint]btcutil.Address,
+	startStamp *waddrmgr.BlockStamp,
+	fullScan bool) (*waddrmgr.BlockStamp, error) {
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+
+	_, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if bestHeight-startStamp.Height < minScanBlocks {
+		return startStamp, nil
+	}
+
+	log.Infof("Scanning blocks %d-%d with %d workers", startStamp.Height,
+		bestHeight, w.scanWorkers)
+
+	// The addresses aren't derived from a scope here, so we index them by
+	// their position.
+	externalAddrs := make(map[waddrmgr.ScopedIndex]btcutil.Address, len(addrs))
+	for i, addr := range addrs {
+		externalAddrs[waddrmgr.ScopedIndex{Index: uint32(i)}] = addr
+	}
+	watchedOutPoints := make(map[wire.OutPoint]btcutil.Address, len(outpoints))
+	for op, addr := range outpoints {
+		watchedOutPoints[op] = addr
+	}
+
+	newReq := func(blocks []wtxmgr.BlockMeta) *chain.FilterBlocksRequest {
+		return &chain.FilterBlocksRequest{
+			Blocks:           blocks,
+			ExternalAddrs:    externalAddrs,
+			InternalAddrs:    map[waddrmgr.ScopedIndex]btcutil.Address{},
+			WatchedOutPoints: watchedOutPoints,
+		}
+	}
+
+	lastStamp := startStamp
+	process := func(blocks []wtxmgr.BlockMeta,
+		resp *chain.FilterBlocksResponse) error {
+
+		last := blocks[len(blocks)-1]
+		lastStamp = &waddrmgr.BlockStamp{
+			Hash:      last.Hash,
+			Height:    last.Height,
+			Timestamp: last.Time,
+		}
+
+		return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+			if resp != nil {
+				logFilterBlocksResp(resp.BlockMeta, resp)
+
+				for _, txn := range resp.RelevantTxns {
+					rec, err := wtxmgr.NewTxRecordFromMsgTx(
+						txn, resp.BlockMeta.Time,
+					)
+					if err != nil {
+						return err
+					}
+
+					err = w.addRelevantTx(
+						tx, rec, &resp.BlockMeta,
+					)
+					if err != nil {
+						return err
+					}
+				}
+
+				// The outputs found are watched for spends
+				// in the following blocks.
+				for op, addr := range resp.FoundOutPoints {
+					watchedOutPoints[op] = addr
+				}
+			}
+
+			// Blocks the wallet isn't synced to yet won't need to be
+			// rescanned by the chain backend, unless other addresses
+			// of the wallet weren't scanned for.
+			for _, block := range blocks {
+				if !fullScan ||
+					block.Height <= w.Manager.SyncedTo().Height {
+
+					continue
+				}
+
+				err := w.Manager.SetSyncedTo(
+					ns, &waddrmgr.BlockStamp{
+						Hash:      block.Hash,
+						Height:    block.Height,
+						Timestamp: block.Time,
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			return w.Manager.SetRescanCheckpoint(ns, lastStamp)
+		})
+	}
+
+	scanner := newChainScanner(
+		chainClient, w.scanWorkers, w.scanWorkers*scanChunkSize,
+		w.quitChan(),
+	)
+	err = scanner.scan(startStamp.Height, bestHeight, newReq, process)
+	if err != nil {
+		return nil, err
+	}
+
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetRescanCheckpoint(ns, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Scanned blocks %d-%d", startStamp.Height, lastStamp.Height)
+
+	return lastStamp, nil
+}
@@ -0,0 +1,318 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+var errScanTest = errors.New("filter failed")
+
+// scanTestChain is a chain client serving a chain of the given height, in
+// which the blocks matched by the match function contain relevant
+// transactions.
+type scanTestChain struct {
+	*mockChainClient
+
+	best   int32
+	failAt int32
+	match  func(*chain.FilterBlocksRequest,
+		wtxmgr.BlockMeta) *chain.FilterBlocksResponse
+
+	mu       sync.Mutex
+	inFlight int
+	maxCalls int
+}
+
+func scanTestHash(height int32) *chainhash.Hash {
+	var hash chainhash.Hash
+	binary.BigEndian.PutUint32(hash[:], uint32(height))
+	return &hash
+}
+
+func (c *scanTestChain) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return scanTestHash(c.best), c.best, nil
+}
+
+func (c *scanTestChain) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return scanTestHash(int32(height)), nil
+}
+
+func (c *scanTestChain) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	height := binary.BigEndian.Uint32(hash[:])
+	return &wire.BlockHeader{
+		Timestamp: time.Unix(int64(height)*600, 0),
+	}, nil
+}
+
+func (c *scanTestChain) FilterBlocks(
+	req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxCalls {
+		c.maxCalls = c.inFlight
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	// Leave the other workers some time to make their requests.
+	time.Sleep(time.Millisecond)
+
+	for i, block := range req.Blocks {
+		if block.Height == c.failAt {
+			return nil, errScanTest
+		}
+
+		if resp := c.match(req, block); resp != nil {
+			resp.BatchIndex = uint32(i)
+			resp.BlockMeta = block
+			return resp, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// TestChainScannerScan tests that the chain scanner filters blocks
+// concurrently, while still processing them in height order and filtering the
+// blocks following a match with what was learned from it.
+func TestChainScannerScan(t *testing.T) {
+	t.Parallel()
+
+	// The block at height 700 only matches once the one at height 300 was
+	// processed.
+	var learned bool
+	client := &scanTestChain{
+		mockChainClient: &mockChainClient{},
+		best:            2500,
+		failAt:          -1,
+		match: func(req *chain.FilterBlocksRequest,
+			block wtxmgr.BlockMeta) *chain.FilterBlocksResponse {
+
+			_, ok := req.WatchedOutPoints[wire.OutPoint{}]
+			switch {
+			case block.Height == 300, block.Height == 2301:
+				return &chain.FilterBlocksResponse{}
+			case block.Height == 700 && ok:
+				return &chain.FilterBlocksResponse{}
+			}
+			return nil
+		},
+	}
+
+	scanner := newChainScanner(client, 4, 1000, make(chan struct{}))
+
+	var (
+		next    int32 = 10
+		matched []int32
+	)
+	newReq := func(blocks []wtxmgr.BlockMeta) *chain.FilterBlocksRequest {
+		req := &chain.FilterBlocksRequest{Blocks: blocks}
+		if learned {
+			req.WatchedOutPoints = map[wire.OutPoint]btcutil.Address{
+				{}: nil,
+			}
+		}
+		return req
+	}
+	process := func(blocks []wtxmgr.BlockMeta,
+		resp *chain.FilterBlocksResponse) error {
+
+		for _, block := range blocks {
+			require.Equal(t, next, block.Height)
+			require.Equal(t, *scanTestHash(next), block.Hash)
+			next++
+		}
+
+		if resp != nil {
+			require.Equal(t, blocks[len(blocks)-1], resp.BlockMeta)
+			matched = append(matched, resp.BlockMeta.Height)
+			learned = true
+		}
+
+		return nil
+	}
+
+	require.NoError(t, scanner.scan(10, client.best, newReq, process))
+	require.Equal(t, client.best+1, next)
+	require.Equal(t, []int32{300, 700, 2301}, matched)
+	require.Greater(t, client.maxCalls, 1)
+	require.LessOrEqual(t, client.maxCalls, 4)
+}
+
+// TestScanChainResume tests that a rescan performed by the wallet itself adds
+// the relevant transactions it finds, and that it's resumed from its last
+// checkpoint after it was interrupted.
+func TestScanChainResume(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	// The wallet is paid at height 1500 and spends the payment at height
+	// 4000, which is only found if the payment was.
+	payment := wire.NewMsgTx(2)
+	payment.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	payment.AddTxOut(wire.NewTxOut(1e6, pkScript))
+	paymentOp := wire.OutPoint{Hash: payment.TxHash()}
+
+	spend := wire.NewMsgTx(2)
+	spend.AddTxIn(wire.NewTxIn(&paymentOp, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(9e5, nil))
+
+	client := &scanTestChain{
+		mockChainClient: &mockChainClient{},
+		best:            5000,
+		failAt:          2000,
+		match: func(req *chain.FilterBlocksRequest,
+			block wtxmgr.BlockMeta) *chain.FilterBlocksResponse {
+
+			_, watched := req.WatchedOutPoints[paymentOp]
+			switch {
+			case block.Height == 1500:
+				return &chain.FilterBlocksResponse{
+					FoundOutPoints: map[wire.OutPoint]btcutil.Address{
+						paymentOp: addr,
+					},
+					RelevantTxns: []*wire.MsgTx{payment},
+				}
+
+			case block.Height == 4000 && watched:
+				return &chain.FilterBlocksResponse{
+					RelevantTxns: []*wire.MsgTx{spend},
+				}
+			}
+
+			return nil
+		},
+	}
+	w.chainClient = client
+
+	// The wallet is synced further than the blocks rescanned.
+	syncedTo := waddrmgr.BlockStamp{
+		Hash:   *scanTestHash(client.best),
+		Height: client.best,
+	}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetSyncedTo(ns, &syncedTo)
+	})
+	require.NoError(t, err)
+
+	// The first rescan fails after it found the payment.
+	_, err = w.scanChain(
+		[]btcutil.Address{addr}, nil, &waddrmgr.BlockStamp{Height: 10},
+		false,
+	)
+	require.ErrorIs(t, err, errScanTest)
+
+	balance, err := w.CalculateBalance(0)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1e6), balance)
+
+	startStamp, err := w.rescanCheckpoint(client)
+	require.NoError(t, err)
+	require.NotNil(t, startStamp)
+	require.Less(t, startStamp.Height, client.failAt)
+	require.GreaterOrEqual(t, startStamp.Height, int32(1500))
+	require.Equal(t, *scanTestHash(startStamp.Height), startStamp.Hash)
+
+	// The resumed rescan still watches the payment, as an unspent output
+	// of the wallet, and finds it spent.
+	client.failAt = -1
+	unspent, err := w.ListUnspent(0, 1<<30, "")
+	require.NoError(t, err)
+	require.Len(t, unspent, 1)
+
+	lastStamp, err := w.scanChain(
+		[]btcutil.Address{addr},
+		map[wire.OutPoint]btcutil.Address{paymentOp: addr}, startStamp,
+		false,
+	)
+	require.NoError(t, err)
+	require.Equal(t, client.best, lastStamp.Height)
+
+	balance, err = w.CalculateBalance(0)
+	require.NoError(t, err)
+	require.Zero(t, balance)
+
+	startStamp, err = w.rescanCheckpoint(client)
+	require.NoError(t, err)
+	require.Nil(t, startStamp)
+	require.Equal(t, syncedTo, w.Manager.SyncedTo())
+}
+
+// TestScanChainSyncedTo tests that only a scan of all the addresses and
+// unspent outputs of the wallet advances its sync tip.
+func TestScanChainSyncedTo(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	client := &scanTestChain{
+		mockChainClient: &mockChainClient{},
+		best:            3000,
+		failAt:          -1,
+		match: func(*chain.FilterBlocksRequest,
+			wtxmgr.BlockMeta) *chain.FilterBlocksResponse {
+
+			return nil
+		},
+	}
+	w.chainClient = client
+
+	syncedTo := waddrmgr.BlockStamp{
+		Hash:   *scanTestHash(10),
+		Height: 10,
+	}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetSyncedTo(ns, &syncedTo)
+	})
+	require.NoError(t, err)
+
+	// A rescan of some of the addresses leaves the sync tip alone, since
+	// the other addresses still have to be scanned for.
+	lastStamp, err := w.scanChain(
+		[]btcutil.Address{addr}, nil, &syncedTo, false,
+	)
+	require.NoError(t, err)
+	require.Equal(t, client.best, lastStamp.Height)
+	require.Equal(t, syncedTo, w.Manager.SyncedTo())
+
+	lastStamp, err = w.scanChain(
+		[]btcutil.Address{addr}, nil, &syncedTo, true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, client.best, lastStamp.Height)
+	require.Equal(t, client.best, w.Manager.SyncedTo().Height)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/encdb"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
@@ -173,6 +174,10 @@ type Wallet struct {
 	// syncRetryInterval is the amount of time to wait between re-tries on
 	// errors during initial sync.
 	syncRetryInterval time.Duration
+
+	// scanWorkers is the number of requests made concurrently to the chain
+	// backend when the wallet scans the chain itself.
+	scanWorkers int
 }
 
 // Start starts the goroutines necessary to manage a wallet.
@@ -230,6 +235,8 @@ func (w *Wallet) SynchronizeRPC(chainClient chain.Interface) {
 		cc.SetStartTime(w.Manager.Birthday())
 	case *chain.BitcoindClient:
 		cc.SetBirthday(w.Manager.Birthday())
+	case *chain.FailoverClient:
+		cc.SetBirthday(w.Manager.Birthday())
 	}
 	w.chainClientLock.Unlock()
 
@@ -569,7 +576,60 @@ func (w *Wallet) syncWithChain(birthdayStamp *waddrmgr.BlockStamp) error {
 		return err
 	}
 
-	return w.rescanWithTarget(addrs, unspent, nil)
+	// If a previous rescan was interrupted, we'll resume it from the last
+	// block it processed rather than from our sync tip.
+	startStamp, err := w.rescanCheckpoint(chainClient)
+	if err != nil {
+		return err
+	}
+
+	return w.rescanWithTarget(addrs, unspent, startStamp, true)
+}
+
+// rescanCheckpoint returns the block from which an interrupted rescan should
+// be resumed, or nil if there's none to resume.
+func (w *Wallet) rescanCheckpoint(
+	chainClient chain.Interface) (*waddrmgr.BlockStamp, error) {
+
+	var (
+		checkpoint waddrmgr.BlockStamp
+		inProgress bool
+	)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+
+		var err error
+		checkpoint, inProgress, err = w.Manager.RescanCheckpoint(ns)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !inProgress || checkpoint.Height >= w.Manager.SyncedTo().Height {
+		return nil, nil
+	}
+
+	// The checkpointed block may have been reorganized out of the chain
+	// since, so we'll resume from the block at its height in the current
+	// chain.
+	hash, err := chainClient.GetBlockHash(int64(checkpoint.Height))
+	if err != nil {
+		return nil, err
+	}
+	header, err := chainClient.GetBlockHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Resuming rescan from block %v (height %d)", hash,
+		checkpoint.Height)
+
+	return &waddrmgr.BlockStamp{
+		Hash:      *hash,
+		Height:    checkpoint.Height,
+		Timestamp: header.Timestamp,
+	}, nil
 }
 
 // isDevEnv determines whether the wallet is currently under a local developer
@@ -690,11 +750,118 @@ func locateBirthdayBlock(chainClient chainConn,
 type recoverySyncer struct {
 	done chan struct{}
 	quit uint32 // atomic
+
+	// mtx guards the progress of the recovery below.
+	mtx         sync.Mutex
+	startTime   time.Time
+	startHeight int32
+	height      int32
+	bestHeight  int32
+	finished    bool
+}
+
+// RecoveryProgress describes the progress of the wallet recovery.
+type RecoveryProgress struct {
+	// Recovering is true once the wallet has started its recovery.
+	Recovering bool
+
+	// Finished is true once the recovery has processed all the blocks up
+	// to the best block known when it started.
+	Finished bool
+
+	// StartHeight is the height of the first block processed since the
+	// recovery was started or resumed.
+	StartHeight int32
+
+	// Height is the height of the last block processed.
+	Height int32
+
+	// BestHeight is the height of the block the recovery ends at.
+	BestHeight int32
+
+	// Progress is the fraction of the blocks processed, from 0 to 1.
+	Progress float64
+
+	// ETA is the estimated time left until the recovery finishes, or zero
+	// if it cannot be estimated yet.
+	ETA time.Duration
+}
+
+// setRange records the range of blocks the recovery is processing.
+func (s *recoverySyncer) setRange(startHeight, bestHeight int32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.startTime = time.Now()
+	s.startHeight = startHeight
+	s.height = startHeight - 1
+	s.bestHeight = bestHeight
+}
+
+// setHeight records the height of the last block processed by the recovery.
+func (s *recoverySyncer) setHeight(height int32) {
+	s.mtx.Lock()
+	s.height = height
+	s.mtx.Unlock()
+}
+
+// setFinished marks the recovery as finished.
+func (s *recoverySyncer) setFinished() {
+	s.mtx.Lock()
+	s.finished = true
+	s.mtx.Unlock()
+}
+
+// progress returns the progress of the recovery at the given time. The time
+// left is estimated from the rate at which the blocks were processed so far.
+func (s *recoverySyncer) progress(now time.Time) *RecoveryProgress {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	progress := &RecoveryProgress{
+		Recovering:  true,
+		Finished:    s.finished,
+		StartHeight: s.startHeight,
+		Height:      s.height,
+		BestHeight:  s.bestHeight,
+	}
+
+	total := s.bestHeight - s.startHeight + 1
+	processed := s.height - s.startHeight + 1
+	switch {
+	case s.finished || total <= 0:
+		progress.Progress = 1
+
+	case processed > 0:
+		progress.Progress = float64(processed) / float64(total)
+
+		elapsed := now.Sub(s.startTime)
+		progress.ETA = time.Duration(
+			float64(elapsed) * float64(total-processed) /
+				float64(processed),
+		)
+	}
+
+	return progress
+}
+
+// RecoveryProgress returns the progress of the wallet recovery. Recovering is
+// false if the wallet wasn't started in recovery mode, or if the recovery has
+// yet to start because the wallet is waiting for the chain backend to sync.
+func (w *Wallet) RecoveryProgress() *RecoveryProgress {
+	recoverySyncI := w.recovering.Load()
+	if recoverySyncI == nil {
+		return &RecoveryProgress{}
+	}
+
+	return recoverySyncI.(*recoverySyncer).progress(time.Now())
 }
 
 // recovery attempts to recover any unspent outputs that pay to any of our
 // addresses starting from our birthday, or the wallet's tip (if higher), which
-// would indicate resuming a recovery after a restart.
+// would indicate resuming a recovery after a restart. The progress of the
+// recovery is checkpointed after each batch of blocks, so that a recovery
+// interrupted by a shutdown resumes with the state it had.
 func (w *Wallet) recovery(chainClient chain.Interface,
 	birthdayBlock *waddrmgr.BlockStamp) error {
 
@@ -723,6 +890,10 @@ func (w *Wallet) recovery(chainClient chain.Interface,
 	for _, scopedMgr := range w.Manager.ActiveScopedKeyManagers() {
 		scopedMgrs[scopedMgr.Scope()] = scopedMgr
 	}
+	//
+	// If the recovery was interrupted, we'll also restore the state it had
+	// at its last checkpoint.
+	var checkpoint *waddrmgr.RecoveryCheckpoint
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txMgrNS := tx.ReadBucket(wtxmgrNamespaceKey)
 		credits, err := w.TxStore.UnspentOutputs(txMgrNS)
@@ -730,7 +901,21 @@ func (w *Wallet) recovery(chainClient chain.Interface,
 			return err
 		}
 		addrMgrNS := tx.ReadBucket(waddrmgrNamespaceKey)
-		return recoveryMgr.Resurrect(addrMgrNS, scopedMgrs, credits)
+		err = recoveryMgr.Resurrect(addrMgrNS, scopedMgrs, credits)
+		if err != nil {
+			return err
+		}
+
+		var inProgress bool
+		checkpoint, inProgress, err = w.Manager.RecoveryCheckpoint(
+			addrMgrNS,
+		)
+		if err != nil || !inProgress {
+			return err
+		}
+		recoveryMgr.Restore(checkpoint)
+
+		return nil
 	})
 	if err != nil {
 		return err
@@ -749,74 +934,113 @@ func (w *Wallet) recovery(chainClient chain.Interface,
 	// way to reflect the blocks we process and prevent rescanning them
 	// later on.
 	//
+	// The blocks are processed in batches of 2000, the next batch being
+	// fetched while the current one is filtered.
+	//
 	// NOTE: We purposefully don't update our best height since we assume
 	// that a wallet rescan will be performed from the wallet's tip, which
 	// will be of bestHeight after completing the recovery process.
-	var blocks []*waddrmgr.BlockStamp
+	//
+	// Recovery only ends early once its quit flag is set, which also stops
+	// the blocks of the next batch from being fetched.
+	scanner := newChainScanner(
+		chainClient, w.scanWorkers, recoveryBatchSize, nil,
+	)
+	scanner.stopped = func() bool {
+		return atomic.LoadUint32(&syncer.quit) == 1
+	}
+	done := make(chan struct{})
+	defer close(done)
+
 	startHeight := w.Manager.SyncedTo().Height + 1
-	for height := startHeight; height <= bestHeight; height++ {
-		if atomic.LoadUint32(&syncer.quit) == 1 {
-			return errors.New("recovery: forced shutdown")
+	if checkpoint != nil {
+		log.Infof("Resuming recovery from block %v (height %d)",
+			checkpoint.Block.Hash, checkpoint.Block.Height)
+
+		if checkpoint.Block.Height < startHeight-1 {
+			startHeight = checkpoint.Block.Height + 1
 		}
+	}
+	syncer.setRange(startHeight, bestHeight)
 
-		hash, err := chainClient.GetBlockHash(int64(height))
-		if err != nil {
-			return err
+	for window := range scanner.prefetch(startHeight, bestHeight, done) {
+		if atomic.LoadUint32(&syncer.quit) == 1 {
+			return errors.New("recovery: forced shutdown")
 		}
-		header, err := chainClient.GetBlockHeader(hash)
-		if err != nil {
-			return err
+		if window.err != nil {
+			return window.err
 		}
-		blocks = append(blocks, &waddrmgr.BlockStamp{
-			Hash:      *hash,
-			Height:    height,
-			Timestamp: header.Timestamp,
-		})
 
 		// It's possible for us to run into blocks before our birthday
 		// if our birthday is after our reorg safe height, so we'll make
 		// sure to not add those to the batch.
-		if height >= birthdayBlock.Height {
-			recoveryMgr.AddToBlockBatch(
-				hash, height, header.Timestamp,
-			)
+		for _, block := range window.blocks {
+			if block.Height >= birthdayBlock.Height {
+				recoveryMgr.AddToBlockBatch(
+					&block.Hash, block.Height, block.Time,
+				)
+			}
 		}
 
-		// We'll perform our recovery in batches of 2000 blocks.  It's
-		// possible for us to reach our best height without exceeding
-		// the recovery batch size, so we can proceed to commit our
-		// state to disk.
 		recoveryBatch := recoveryMgr.BlockBatch()
-		if len(recoveryBatch) == recoveryBatchSize || height == bestHeight {
-			err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
-				ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
-				for _, block := range blocks {
-					err := w.Manager.SetSyncedTo(ns, block)
-					if err != nil {
-						return err
-					}
+		err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			for _, block := range window.blocks {
+				err := w.Manager.SetSyncedTo(ns, &waddrmgr.BlockStamp{
+					Hash:      block.Hash,
+					Height:    block.Height,
+					Timestamp: block.Time,
+				})
+				if err != nil {
+					return err
 				}
-				return w.recoverScopedAddresses(
-					chainClient, tx, ns, recoveryBatch,
-					recoveryMgr.State(), scopedMgrs,
-				)
-			})
+			}
+			err := w.recoverScopedAddresses(
+				scanner, tx, ns, recoveryBatch,
+				recoveryMgr.State(), scopedMgrs,
+			)
 			if err != nil {
 				return err
 			}
 
-			if len(recoveryBatch) > 0 {
-				log.Infof("Recovered addresses from blocks "+
-					"%d-%d", recoveryBatch[0].Height,
-					recoveryBatch[len(recoveryBatch)-1].Height)
-			}
+			last := window.blocks[len(window.blocks)-1]
+			return w.Manager.SetRecoveryCheckpoint(
+				ns, recoveryMgr.Checkpoint(waddrmgr.BlockStamp{
+					Hash:      last.Hash,
+					Height:    last.Height,
+					Timestamp: last.Time,
+				}),
+			)
+		})
+		if err != nil {
+			return err
+		}
+		syncer.setHeight(window.blocks[len(window.blocks)-1].Height)
 
-			// Clear the batch of all processed blocks to reuse the
-			// same memory for future batches.
-			blocks = blocks[:0]
-			recoveryMgr.ResetBlockBatch()
+		if len(recoveryBatch) > 0 {
+			log.Infof("Recovered addresses from blocks "+
+				"%d-%d", recoveryBatch[0].Height,
+				recoveryBatch[len(recoveryBatch)-1].Height)
 		}
+
+		// Clear the batch of all processed blocks to reuse the same
+		// memory for future batches.
+		recoveryMgr.ResetBlockBatch()
+	}
+
+	// The batches stop being fetched if recovery was ended early.
+	if atomic.LoadUint32(&syncer.quit) == 1 {
+		return errors.New("recovery: forced shutdown")
+	}
+
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetRecoveryCheckpoint(ns, nil)
+	})
+	if err != nil {
+		return err
 	}
+	syncer.setFinished()
 
 	return nil
 }
@@ -833,9 +1057,10 @@ func (w *Wallet) recovery(chainClient chain.Interface,
 //  5. Trim the range of blocks up to and including the one reporting the addrs.
 //  6. Repeat from (1) if there are still more blocks in the range.
 //
-// TODO(conner): parallelize/pipeline/cache intermediate network requests
+// The blocks are filtered concurrently by the chain scanner, which still
+// reports the first of them containing addresses.
 func (w *Wallet) recoverScopedAddresses(
-	chainClient chain.Interface,
+	scanner *chainScanner,
 	tx walletdb.ReadWriteTx,
 	ns walletdb.ReadWriteBucket,
 	batch []wtxmgr.BlockMeta,
@@ -866,7 +1091,7 @@ expandHorizons:
 
 	// Initiate the filter blocks request using our chain backend. If an
 	// error occurs, we are unable to proceed with the recovery.
-	filterResp, err := chainClient.FilterBlocks(filterReq)
+	filterResp, err := scanner.filter(filterReq)
 	if err != nil {
 		return err
 	}
@@ -1365,6 +1590,7 @@ type (
 	changePassphraseRequest struct {
 		old, new []byte
 		private  bool
+		kdf      *waddrmgr.KDFOptions
 		err      chan error
 	}
 
@@ -1428,10 +1654,21 @@ out:
 		case req := <-w.changePassphrase:
 			err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
 				addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
-				return w.Manager.ChangePassphrase(
+				kdf := req.kdf
+				if kdf == nil {
+					kdf = &waddrmgr.DefaultScryptOptions
+				}
+				err := w.Manager.ChangePassphrase(
 					addrmgrNs, req.old, req.new, req.private,
-					&waddrmgr.DefaultScryptOptions,
+					kdf,
 				)
+				if err != nil || req.private {
+					return err
+				}
+
+				// The database may be encrypted with the
+				// public passphrase as well.
+				return encdb.ChangePassphrase(tx, req.old, req.new)
 			})
 			req.err <- err
 			continue
@@ -1447,6 +1684,13 @@ out:
 					return err
 				}
 
+				err = encdb.ChangePassphrase(
+					tx, req.publicOld, req.publicNew,
+				)
+				if err != nil {
+					return err
+				}
+
 				return w.Manager.ChangePassphrase(
 					addrmgrNs, req.privateOld, req.privateNew,
 					true, &waddrmgr.DefaultScryptOptions,
@@ -1562,11 +1806,23 @@ func (c heldUnlock) release() {
 // manager locking and unlocking.  The lock state will be the same as it was
 // before the password change.
 func (w *Wallet) ChangePrivatePassphrase(old, new []byte) error {
+	return w.ChangePrivatePassphraseKDF(old, new, nil)
+}
+
+// ChangePrivatePassphraseKDF changes the private passphrase like
+// ChangePrivatePassphrase, deriving the new passphrase key with the given
+// scrypt or Argon2id options instead of the default scrypt options.  Changing
+// the passphrase to the same value upgrades the key derivation of an existing
+// wallet.
+func (w *Wallet) ChangePrivatePassphraseKDF(old, new []byte,
+	kdf *waddrmgr.KDFOptions) error {
+
 	err := make(chan error, 1)
 	w.changePassphrase <- changePassphraseRequest{
 		old:     old,
 		new:     new,
 		private: true,
+		kdf:     kdf,
 		err:     err,
 	}
 	return <-err
@@ -1574,16 +1830,33 @@ func (w *Wallet) ChangePrivatePassphrase(old, new []byte) error {
 
 // ChangePublicPassphrase modifies the public passphrase of the wallet.
 func (w *Wallet) ChangePublicPassphrase(old, new []byte) error {
+	return w.ChangePublicPassphraseKDF(old, new, nil)
+}
+
+// ChangePublicPassphraseKDF modifies the public passphrase of the wallet,
+// deriving the new passphrase key with the given scrypt or Argon2id options
+// instead of the default scrypt options.
+func (w *Wallet) ChangePublicPassphraseKDF(old, new []byte,
+	kdf *waddrmgr.KDFOptions) error {
+
 	err := make(chan error, 1)
 	w.changePassphrase <- changePassphraseRequest{
 		old:     old,
 		new:     new,
 		private: false,
+		kdf:     kdf,
 		err:     err,
 	}
 	return <-err
 }
 
+// PassphraseKDFs returns the key derivation parameters currently in use for
+// the public and private passphrases.  The private parameters are nil for a
+// watching-only wallet.
+func (w *Wallet) PassphraseKDFs() (public, private *waddrmgr.KDFOptions) {
+	return w.Manager.PassphraseKDF(false), w.Manager.PassphraseKDF(true)
+}
+
 // ChangePassphrases modifies the public and private passphrase of the wallet
 // atomically.
 func (w *Wallet) ChangePassphrases(publicOld, publicNew, privateOld,
@@ -1890,6 +2163,66 @@ func (w *Wallet) AddressInfo(a btcutil.Address) (waddrmgr.ManagedAddress, error)
 	return managedAddress, err
 }
 
+// AddressDetails houses a managed wallet address along with the account and
+// usage information that can only be determined with access to the database.
+type AddressDetails struct {
+	// Address is the managed address itself.
+	Address waddrmgr.ManagedAddress
+
+	// KeyScope is the key scope of the manager the address belongs to.
+	KeyScope waddrmgr.KeyScope
+
+	// AccountName is the name of the account the address belongs to.
+	AccountName string
+
+	// WatchOnly is true if the wallet doesn't hold the private keys of
+	// the account the address belongs to.
+	WatchOnly bool
+
+	// Used is true if the address has been used in a transaction.
+	Used bool
+
+	// Label is the label attached to the address, if any.
+	Label string
+}
+
+// AddressDetails returns detailed information regarding a wallet address,
+// including its account, key scope, usage and label.
+func (w *Wallet) AddressDetails(a btcutil.Address) (*AddressDetails, error) {
+	var details *AddressDetails
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+
+		manager, account, err := w.Manager.AddrAccount(addrmgrNs, a)
+		if err != nil {
+			return err
+		}
+		ma, err := manager.Address(addrmgrNs, a)
+		if err != nil {
+			return err
+		}
+		props, err := manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		label, err := w.Manager.AddrLabel(addrmgrNs, a)
+		if err != nil {
+			return err
+		}
+
+		details = &AddressDetails{
+			Address:     ma,
+			KeyScope:    manager.Scope(),
+			AccountName: props.AccountName,
+			WatchOnly:   props.IsWatchOnly,
+			Used:        ma.Used(addrmgrNs),
+			Label:       label,
+		}
+		return nil
+	})
+	return details, err
+}
+
 // AccountNumber returns the account number for an account name under a
 // particular key scope.
 func (w *Wallet) AccountNumber(scope waddrmgr.KeyScope, accountName string) (uint32, error) {
@@ -2397,6 +2730,12 @@ type GetTransactionsResult struct {
 	UnminedTransactions []TransactionSummary
 }
 
+// blockHeightGetter is implemented by the chain clients that look up the
+// height of a block by its hash.
+type blockHeightGetter interface {
+	GetBlockHeight(*chainhash.Hash) (int32, error)
+}
+
 // GetTransactions returns transaction results between a starting and ending
 // block.  Blocks in the block range may be specified by either a height or a
 // hash.
@@ -2436,13 +2775,7 @@ func (w *Wallet) GetTransactions(startBlock, endBlock *BlockIdentifier,
 					return nil, err
 				}
 				start = startHeader.Height
-			case *chain.BitcoindClient:
-				var err error
-				start, err = client.GetBlockHeight(startBlock.hash)
-				if err != nil {
-					return nil, err
-				}
-			case *chain.NeutrinoClient:
+			case blockHeightGetter:
 				var err error
 				start, err = client.GetBlockHeight(startBlock.hash)
 				if err != nil {
@@ -2467,13 +2800,7 @@ func (w *Wallet) GetTransactions(startBlock, endBlock *BlockIdentifier,
 					return nil, err
 				}
 				end = endHeader.Height
-			case *chain.BitcoindClient:
-				var err error
-				start, err = client.GetBlockHeight(endBlock.hash)
-				if err != nil {
-					return nil, err
-				}
-			case *chain.NeutrinoClient:
+			case blockHeightGetter:
 				var err error
 				end, err = client.GetBlockHeight(endBlock.hash)
 				if err != nil {
@@ -3141,6 +3468,18 @@ func (w *Wallet) resendUnminedTxs() {
 		log.Debugf("Successfully rebroadcast unconfirmed transaction %v",
 			txHash)
 	}
+
+	// btcd only tells us about the unmined transactions that enter its
+	// mempool, so have it track the others as well, now that they've been
+	// rebroadcast, to find out about those that were evicted or replaced
+	// in the meantime.
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return
+	}
+	if client, ok := chainClient.(*chain.RPCClient); ok {
+		client.TrackUnminedTxs(txs)
+	}
 }
 
 // SortedActivePaymentAddresses returns a slice of all active payment
@@ -4048,21 +4387,16 @@ func OpenWithRetry(db walletdb.DB, pubPass []byte, cbs *waddrmgr.OpenCallbacks,
 	// to the address and transaction managers, as they are backed by the
 	// database.
 	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
-		addrMgrBucket := tx.ReadWriteBucket(waddrmgrNamespaceKey)
-		if addrMgrBucket == nil {
-			return errors.New("missing address manager namespace")
-		}
-		txMgrBucket := tx.ReadWriteBucket(wtxmgrNamespaceKey)
-		if txMgrBucket == nil {
-			return errors.New("missing transaction manager namespace")
-		}
-
-		addrMgrUpgrader := waddrmgr.NewMigrationManager(addrMgrBucket)
-		txMgrUpgrader := wtxmgr.NewMigrationManager(txMgrBucket)
-		err := migration.Upgrade(txMgrUpgrader, addrMgrUpgrader)
+		mgrs, err := migrationManagers(tx)
 		if err != nil {
 			return err
 		}
+		if err := migration.Upgrade(mgrs...); err != nil {
+			return err
+		}
+
+		addrMgrBucket := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txMgrBucket := tx.ReadWriteBucket(wtxmgrNamespaceKey)
 
 		addrMgr, err = waddrmgr.Open(addrMgrBucket, pubPass, params)
 		if err != nil {
@@ -4103,6 +4437,7 @@ func OpenWithRetry(db walletdb.DB, pubPass []byte, cbs *waddrmgr.OpenCallbacks,
 		chainParams:         params,
 		quit:                make(chan struct{}),
 		syncRetryInterval:   syncRetryInterval,
+		scanWorkers:         DefaultScanWorkers,
 	}
 
 	w.NtfnServer = newNotificationServer(w)
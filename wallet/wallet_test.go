@@ -382,6 +382,9 @@ func TestEndRecovery(t *testing.T) {
 
 	w, cleanup := testWallet(t)
 
+	// Fetch the blocks one at a time, so that each iteration is controlled.
+	w.scanWorkers = 1
+
 	blockHashCalled := make(chan struct{})
 
 	chainClient := &mockChainClient{
@@ -439,6 +442,7 @@ func TestEndRecovery(t *testing.T) {
 	// Try again.
 	w, cleanup = testWallet(t)
 	defer cleanup()
+	w.scanWorkers = 1
 
 	// We'll catch the error to make sure we're hitting our desired path. The
 	// WaitGroup isn't required for the test, but does show how it completes
@@ -485,3 +489,117 @@ func TestEndRecovery(t *testing.T) {
 		t.Fatal("wrong error")
 	}
 }
+
+// TestRecoveryProgress tests that the progress of a recovery and the time it
+// has left are estimated from the blocks it processed so far.
+func TestRecoveryProgress(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	require.Equal(t, &RecoveryProgress{}, w.RecoveryProgress())
+
+	syncer := &recoverySyncer{done: make(chan struct{})}
+	w.recovering.Store(syncer)
+	syncer.setRange(1001, 2000)
+	start := syncer.startTime
+
+	require.Equal(t, &RecoveryProgress{
+		Recovering:  true,
+		StartHeight: 1001,
+		Height:      1000,
+		BestHeight:  2000,
+	}, syncer.progress(start.Add(time.Minute)))
+
+	// A quarter of the blocks were processed in a minute, so the rest
+	// should be processed in three.
+	syncer.setHeight(1250)
+	require.Equal(t, &RecoveryProgress{
+		Recovering:  true,
+		StartHeight: 1001,
+		Height:      1250,
+		BestHeight:  2000,
+		Progress:    0.25,
+		ETA:         3 * time.Minute,
+	}, syncer.progress(start.Add(time.Minute)))
+
+	syncer.setHeight(2000)
+	syncer.setFinished()
+	progress := w.RecoveryProgress()
+	require.True(t, progress.Finished)
+	require.Equal(t, 1.0, progress.Progress)
+	require.Zero(t, progress.ETA)
+}
+
+// TestAddressDetails tests that the account, usage and label details of a
+// wallet address are returned.
+func TestAddressDetails(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	require.NoError(t, w.LabelAddress(addr, "deposit", false))
+
+	details, err := w.AddressDetails(addr)
+	require.NoError(t, err)
+	require.Equal(t, waddrmgr.KeyScopeBIP0084, details.KeyScope)
+	require.Equal(t, "default", details.AccountName)
+	require.False(t, details.WatchOnly)
+	require.False(t, details.Used)
+	require.Equal(t, "deposit", details.Label)
+	require.Equal(t, addr.String(), details.Address.Address().String())
+
+	// Marking the address as used must be reflected in the details.
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.MarkUsed(ns, addr)
+	})
+	require.NoError(t, err)
+
+	details, err = w.AddressDetails(addr)
+	require.NoError(t, err)
+	require.True(t, details.Used)
+
+	// An unknown address results in an address not found error.
+	unknown, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), w.ChainParams(),
+	)
+	require.NoError(t, err)
+	_, err = w.AddressDetails(unknown)
+	require.True(t, waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound))
+}
+
+// TestChangePrivatePassphraseKDF ensures that the private passphrase can be
+// changed with new key derivation parameters, which are then reported as in
+// use.
+func TestChangePrivatePassphraseKDF(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	public, private := w.PassphraseKDFs()
+	require.Equal(t, &waddrmgr.DefaultScryptOptions, public)
+	require.Equal(t, &waddrmgr.DefaultScryptOptions, private)
+
+	// Upgrade the key derivation without changing the passphrase.
+	kdf := &waddrmgr.KDFOptions{
+		Argon2id: &waddrmgr.Argon2idOptions{
+			Time: 1, Memory: 64, Threads: 1,
+		},
+	}
+	privPass := []byte("world")
+	require.NoError(t, w.ChangePrivatePassphraseKDF(privPass, privPass, kdf))
+
+	public, private = w.PassphraseKDFs()
+	require.Equal(t, &waddrmgr.DefaultScryptOptions, public)
+	require.Equal(t, kdf, private)
+
+	w.Lock()
+	require.Error(t, w.Unlock([]byte("wrong"), nil))
+	require.NoError(t, w.Unlock(privPass, nil))
+}
@@ -5,3 +5,6 @@ go test -coverprofile=cov.out && go tool cover -func=cov.out && rm -f cov.out
 echo "============================================================"
 (cd bdb && go test -coverprofile=cov.out && go tool cover -func=cov.out && \
   rm -f cov.out)
+echo "============================================================"
+(cd memdb && go test -coverprofile=cov.out && go tool cover -func=cov.out && \
+  rm -f cov.out)
@@ -0,0 +1,270 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"os"
+)
+
+// The layout of a bbolt database file, as written by bbolt 1.3.  All integers
+// are in the native byte order.
+//
+// The file is a sequence of pages.  The first two pages hold the meta data,
+// the third holds the list of free pages and the others the B+trees of the
+// buckets.  Each page starts with a header:
+//
+//	<id (8 bytes)><flags (2 bytes)><count (2 bytes)><overflow (4 bytes)>
+//
+// A page whose contents don't fit spans overflow more pages.
+const (
+	boltMagic   = 0xED0CDAED
+	boltVersion = 2
+
+	boltPageHeaderSize = 16
+
+	// boltElementSize is the size of both the leaf and the branch page
+	// elements:
+	//
+	//	leaf:   <flags (4 bytes)><pos (4 bytes)><ksize (4 bytes)><vsize (4 bytes)>
+	//	branch: <pos (4 bytes)><ksize (4 bytes)><pgid (8 bytes)>
+	//
+	// pos is the offset of the key, followed by the value, from the
+	// element itself.
+	boltElementSize = 16
+
+	// boltBucketSize is the size of the value of a nested bucket, which
+	// holds the ID of its root page and its sequence.
+	boltBucketSize = 16
+
+	// boltMaxElements is the most elements a page holds, as bbolt won't
+	// write a page with more.
+	boltMaxElements = 0xFFFF - 1
+
+	boltBranchPageFlag   = 0x01
+	boltLeafPageFlag     = 0x02
+	boltMetaPageFlag     = 0x04
+	boltFreelistPageFlag = 0x10
+
+	boltBucketLeafFlag = 0x01
+
+	// boltFreelistPage is the ID of the page holding the, always empty,
+	// list of free pages.  The pages of the buckets follow it.
+	boltFreelistPage = 2
+
+	// boltMetaChecksumOffset is the offset of the checksum in the meta
+	// data, which covers everything before it.
+	boltMetaChecksumOffset = 56
+)
+
+// boltItem is an element of a leaf or a branch page.
+type boltItem struct {
+	flags uint32
+	key   []byte
+	value []byte
+
+	// pgid is the ID of the child page of a branch element.
+	pgid uint64
+}
+
+// boltPage is a leaf or a branch page of the database file.
+type boltPage struct {
+	id       uint64
+	flags    uint16
+	overflow uint32
+	size     int
+	items    []boltItem
+}
+
+// boltWriter writes the contents of a database in the bbolt file format.  The
+// pages are laid out first, referring to the keys and values of the immutable
+// nodes, so that the file is then written in order without holding a copy of
+// the database.
+type boltWriter struct {
+	pageSize int
+	pages    []*boltPage
+	nextID   uint64
+}
+
+// writeBolt writes the buckets of the given root node to w as a bbolt database
+// file.
+func writeBolt(w io.Writer, root *node) error {
+	bw := &boltWriter{
+		pageSize: os.Getpagesize(),
+		nextID:   boltFreelistPage + 1,
+	}
+	rootID := bw.addBucket(root)
+
+	buf := make([]byte, bw.pageSize)
+	for id := uint64(0); id < 2; id++ {
+		clear(buf)
+		bw.putMeta(buf, id, rootID, root.sequence)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	clear(buf)
+	putBoltPageHeader(buf, boltFreelistPage, boltFreelistPageFlag, 0, 0)
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+
+	for _, p := range bw.pages {
+		buf = bw.putPage(buf, p)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addBucket lays out the pages of a bucket, after those of its nested buckets,
+// and returns the ID of its root page.
+func (bw *boltWriter) addBucket(n *node) uint64 {
+	items := make([]boltItem, 0, len(n.entries))
+	for _, e := range n.entries {
+		if e.bucket == nil {
+			items = append(items, boltItem{key: e.key, value: e.value})
+			continue
+		}
+
+		value := make([]byte, boltBucketSize)
+		binary.NativeEndian.PutUint64(value[:8], bw.addBucket(e.bucket))
+		binary.NativeEndian.PutUint64(value[8:], e.bucket.sequence)
+		items = append(items, boltItem{
+			flags: boltBucketLeafFlag,
+			key:   e.key,
+			value: value,
+		})
+	}
+
+	return bw.addNode(boltLeafPageFlag, items)
+}
+
+// addNode lays out the given items over as many pages as needed, with branch
+// pages above them when there's more than one, and returns the ID of the root
+// page.
+func (bw *boltWriter) addNode(flags uint16, items []boltItem) uint64 {
+	if len(items) == 0 {
+		return bw.addPage(flags, nil, boltPageHeaderSize).id
+	}
+
+	var branch []boltItem
+	for len(items) > 0 {
+		// Fill the page up to its size, with at least one item.
+		n, size := 0, boltPageHeaderSize
+		for n < len(items) && n < boltMaxElements {
+			itemSize := boltElementSize + len(items[n].key) +
+				len(items[n].value)
+			if n > 0 && size+itemSize > bw.pageSize {
+				break
+			}
+			size += itemSize
+			n++
+		}
+
+		p := bw.addPage(flags, items[:n], size)
+		branch = append(branch, boltItem{key: items[0].key, pgid: p.id})
+		items = items[n:]
+	}
+	if len(branch) == 1 {
+		return branch[0].pgid
+	}
+
+	return bw.addNode(boltBranchPageFlag, branch)
+}
+
+// addPage assigns the next page IDs to a page of the given size.
+func (bw *boltWriter) addPage(flags uint16, items []boltItem,
+	size int) *boltPage {
+
+	count := (size + bw.pageSize - 1) / bw.pageSize
+	p := &boltPage{
+		id:       bw.nextID,
+		flags:    flags,
+		overflow: uint32(count - 1),
+		size:     size,
+		items:    items,
+	}
+	bw.pages = append(bw.pages, p)
+	bw.nextID += uint64(count)
+
+	return p
+}
+
+// putMeta writes the meta page with the given ID to buf, which is the size of
+// a page.  The ID is used as the transaction ID, as bbolt does when creating
+// a database.
+func (bw *boltWriter) putMeta(buf []byte, id, rootID, sequence uint64) {
+	putBoltPageHeader(buf, id, boltMetaPageFlag, 0, 0)
+
+	m := buf[boltPageHeaderSize:]
+	binary.NativeEndian.PutUint32(m[0:4], boltMagic)
+	binary.NativeEndian.PutUint32(m[4:8], boltVersion)
+	binary.NativeEndian.PutUint32(m[8:12], uint32(bw.pageSize))
+	binary.NativeEndian.PutUint32(m[12:16], 0)
+	binary.NativeEndian.PutUint64(m[16:24], rootID)
+	binary.NativeEndian.PutUint64(m[24:32], sequence)
+	binary.NativeEndian.PutUint64(m[32:40], boltFreelistPage)
+	binary.NativeEndian.PutUint64(m[40:48], bw.nextID)
+	binary.NativeEndian.PutUint64(m[48:56], id)
+
+	h := fnv.New64a()
+	_, _ = h.Write(m[:boltMetaChecksumOffset])
+	binary.NativeEndian.PutUint64(
+		m[boltMetaChecksumOffset:boltMetaChecksumOffset+8], h.Sum64(),
+	)
+}
+
+// putPage writes a leaf or a branch page, including its overflow pages, to
+// buf, which is grown as needed, and returns it.
+func (bw *boltWriter) putPage(buf []byte, p *boltPage) []byte {
+	size := int(p.overflow+1) * bw.pageSize
+	if cap(buf) < size {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+	clear(buf)
+
+	putBoltPageHeader(buf, p.id, p.flags, uint16(len(p.items)), p.overflow)
+
+	elem := boltPageHeaderSize
+	data := boltPageHeaderSize + boltElementSize*len(p.items)
+	for _, item := range p.items {
+		e := buf[elem : elem+boltElementSize]
+		pos := uint32(data - elem)
+		if p.flags == boltLeafPageFlag {
+			binary.NativeEndian.PutUint32(e[0:4], item.flags)
+			binary.NativeEndian.PutUint32(e[4:8], pos)
+			binary.NativeEndian.PutUint32(e[8:12], uint32(len(item.key)))
+			binary.NativeEndian.PutUint32(
+				e[12:16], uint32(len(item.value)),
+			)
+		} else {
+			binary.NativeEndian.PutUint32(e[0:4], pos)
+			binary.NativeEndian.PutUint32(e[4:8], uint32(len(item.key)))
+			binary.NativeEndian.PutUint64(e[8:16], item.pgid)
+		}
+		elem += boltElementSize
+
+		data += copy(buf[data:], item.key)
+		data += copy(buf[data:], item.value)
+	}
+
+	return buf
+}
+
+// putBoltPageHeader writes a page header to the start of buf.
+func putBoltPageHeader(buf []byte, id uint64, flags, count uint16,
+	overflow uint32) {
+
+	binary.NativeEndian.PutUint64(buf[0:8], id)
+	binary.NativeEndian.PutUint16(buf[8:10], flags)
+	binary.NativeEndian.PutUint16(buf[10:12], count)
+	binary.NativeEndian.PutUint32(buf[12:16], overflow)
+}
@@ -0,0 +1,832 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	// maxKeySize and maxValueSize are the limits enforced by bbolt, which
+	// are enforced here as well so that any database can be copied to the
+	// bdb format.
+	maxKeySize   = 32768
+	maxValueSize = (1 << 31) - 2
+)
+
+// node holds the contents of a bucket: its sequence, and its key/value pairs
+// and nested buckets sorted by key.
+//
+// Nodes are copied on write. A node is only ever modified by the read-write
+// transaction that created it, and is immutable once that transaction is
+// committed, so the transactions that began earlier keep seeing the database as
+// it was.
+type node struct {
+	// txID is the ID of the read-write transaction that created the node.
+	txID uint64
+
+	entries  []entry
+	sequence uint64
+}
+
+// entry is a key/value pair or a nested bucket of a node.
+type entry struct {
+	key   []byte
+	value []byte
+
+	// bucket is the nested bucket stored under the key, if any, in which
+	// case value is nil.
+	bucket *node
+}
+
+// search returns the index of the entry with the given key, or the index at
+// which it should be inserted, along with whether the entry exists.
+func (n *node) search(key []byte) (int, bool) {
+	i := sort.Search(len(n.entries), func(i int) bool {
+		return bytes.Compare(n.entries[i].key, key) >= 0
+	})
+	return i, i < len(n.entries) && bytes.Equal(n.entries[i].key, key)
+}
+
+// insert inserts an entry at the given index.
+func (n *node) insert(i int, e entry) {
+	n.entries = append(n.entries, entry{})
+	copy(n.entries[i+1:], n.entries[i:])
+	n.entries[i] = e
+}
+
+// remove removes the entry at the given index.
+func (n *node) remove(i int) {
+	copy(n.entries[i:], n.entries[i+1:])
+	n.entries[len(n.entries)-1] = entry{}
+	n.entries = n.entries[:len(n.entries)-1]
+}
+
+// clone returns a copy of the node owned by the given read-write transaction.
+// The nested buckets are shared until they're modified.
+func (n *node) clone(txID uint64) *node {
+	entries := make([]entry, len(n.entries))
+	copy(entries, n.entries)
+
+	return &node{
+		txID:     txID,
+		entries:  entries,
+		sequence: n.sequence,
+	}
+}
+
+// cloneBytes returns a copy of the given byte slice which is never nil.
+func cloneBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+// transaction represents a database transaction.  It can either by read-only or
+// read-write and implements the walletdb Tx interfaces.  The transaction sees
+// the database from the root node it began with, which a read-write transaction
+// replaces as it modifies the database.
+type transaction struct {
+	db *db
+
+	// id is the ID of a read-write transaction, which owns the nodes it
+	// created.  It's zero for read-only transactions.
+	id uint64
+
+	root     *node
+	writable bool
+	closed   bool
+
+	onCommit []func()
+}
+
+// rootBucket returns the bucket holding the top level buckets.
+func (tx *transaction) rootBucket() *bucket {
+	return &bucket{tx: tx}
+}
+
+func (tx *transaction) ReadBucket(key []byte) walletdb.ReadBucket {
+	return tx.ReadWriteBucket(key)
+}
+
+// ForEachBucket will iterate through all top level buckets.
+func (tx *transaction) ForEachBucket(fn func(key []byte) error) error {
+	if tx.closed {
+		return walletdb.ErrTxClosed
+	}
+
+	for _, e := range tx.root.entries {
+		if err := fn(e.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *transaction) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	// Don't return a non-nil interface to a nil pointer.
+	b := tx.rootBucket().nestedBucket(key)
+	if b == nil {
+		return nil
+	}
+	return b
+}
+
+func (tx *transaction) CreateTopLevelBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	return tx.rootBucket().createBucket(key, true)
+}
+
+// DeleteTopLevelBucket deletes the top level bucket for a key.  This errors if
+// the bucket can not be found or the key keys a single value instead of a
+// bucket.
+//
+// This function is part of the walletdb.ReadWriteTx interface implementation.
+func (tx *transaction) DeleteTopLevelBucket(key []byte) error {
+	return tx.rootBucket().DeleteNestedBucket(key)
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets to the database.
+//
+// This function is part of the walletdb.ReadWriteTx interface implementation.
+func (tx *transaction) Commit() error {
+	switch {
+	case tx.closed:
+		return walletdb.ErrTxClosed
+	case !tx.writable:
+		return walletdb.ErrTxNotWritable
+	}
+
+	tx.db.mtx.Lock()
+	tx.db.root = tx.root
+	tx.db.mtx.Unlock()
+
+	tx.close()
+
+	for _, f := range tx.onCommit {
+		f()
+	}
+	return nil
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of
+// its sub-buckets.
+//
+// This function is part of the walletdb.ReadTx interface implementation.
+func (tx *transaction) Rollback() error {
+	if tx.closed {
+		return walletdb.ErrTxClosed
+	}
+
+	tx.close()
+	return nil
+}
+
+// close marks the transaction as closed and lets the next read-write
+// transaction begin.
+func (tx *transaction) close() {
+	tx.closed = true
+	tx.root = nil
+	if tx.writable {
+		tx.db.writeMtx.Unlock()
+	}
+}
+
+// OnCommit takes a function closure that will be executed when the transaction
+// successfully gets committed.
+//
+// This function is part of the walletdb.ReadWriteTx interface implementation.
+func (tx *transaction) OnCommit(f func()) {
+	tx.onCommit = append(tx.onCommit, f)
+}
+
+// bucket is an internal type used to represent a collection of key/value pairs
+// and implements the walletdb Bucket interfaces.  Since the nodes are replaced
+// as they're copied on write, the bucket is identified by its path from the
+// root of the transaction.
+type bucket struct {
+	tx   *transaction
+	path [][]byte
+}
+
+// Enforce bucket implements the walletdb Bucket interfaces.
+var _ walletdb.ReadWriteBucket = (*bucket)(nil)
+
+// node returns the node of the bucket as seen by its transaction, or nil if the
+// transaction is closed or the bucket doesn't exist anymore.
+func (b *bucket) node() *node {
+	if b.tx.closed {
+		return nil
+	}
+
+	n := b.tx.root
+	for _, key := range b.path {
+		i, ok := n.search(key)
+		if !ok || n.entries[i].bucket == nil {
+			return nil
+		}
+		n = n.entries[i].bucket
+	}
+	return n
+}
+
+// writableNode returns the node of the bucket owned by its read-write
+// transaction, copying the nodes on its path that the transaction doesn't own
+// yet.
+func (b *bucket) writableNode() (*node, error) {
+	tx := b.tx
+	switch {
+	case tx.closed:
+		return nil, walletdb.ErrTxClosed
+	case !tx.writable:
+		return nil, walletdb.ErrTxNotWritable
+	}
+
+	if tx.root.txID != tx.id {
+		tx.root = tx.root.clone(tx.id)
+	}
+
+	n := tx.root
+	for _, key := range b.path {
+		i, ok := n.search(key)
+		if !ok || n.entries[i].bucket == nil {
+			return nil, walletdb.ErrBucketNotFound
+		}
+
+		child := n.entries[i].bucket
+		if child.txID != tx.id {
+			child = child.clone(tx.id)
+			n.entries[i].bucket = child
+		}
+		n = child
+	}
+	return n, nil
+}
+
+// nestedBucket returns the nested bucket with the given key, or nil if it
+// doesn't exist.
+func (b *bucket) nestedBucket(key []byte) *bucket {
+	n := b.node()
+	if n == nil {
+		return nil
+	}
+
+	i, ok := n.search(key)
+	if !ok || n.entries[i].bucket == nil {
+		return nil
+	}
+
+	path := make([][]byte, len(b.path), len(b.path)+1)
+	copy(path, b.path)
+	return &bucket{
+		tx:   b.tx,
+		path: append(path, cloneBytes(key)),
+	}
+}
+
+// createBucket creates the nested bucket with the given key, or returns the
+// existing one if ifNotExists is set.
+func (b *bucket) createBucket(key []byte,
+	ifNotExists bool) (walletdb.ReadWriteBucket, error) {
+
+	n, err := b.writableNode()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(key) == 0:
+		return nil, walletdb.ErrBucketNameRequired
+	case len(key) > maxKeySize:
+		return nil, walletdb.ErrKeyTooLarge
+	}
+
+	i, ok := n.search(key)
+	switch {
+	case ok && n.entries[i].bucket == nil:
+		return nil, walletdb.ErrIncompatibleValue
+	case ok && !ifNotExists:
+		return nil, walletdb.ErrBucketExists
+	case !ok:
+		n.insert(i, entry{
+			key:    cloneBytes(key),
+			bucket: &node{txID: b.tx.id},
+		})
+	}
+
+	return b.nestedBucket(key), nil
+}
+
+// NestedReadWriteBucket retrieves a nested bucket with the given key.  Returns
+// nil if the bucket does not exist.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) NestedReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	// Don't return a non-nil interface to a nil pointer.
+	nested := b.nestedBucket(key)
+	if nested == nil {
+		return nil
+	}
+	return nested
+}
+
+func (b *bucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
+	return b.NestedReadWriteBucket(key)
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key value is otherwise
+// invalid.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (walletdb.ReadWriteBucket, error) {
+	return b.createBucket(key, false)
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.  Returns ErrBucketNameRequired if the
+// key is empty or ErrIncompatibleValue if the key value is otherwise invalid.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.ReadWriteBucket, error) {
+	return b.createBucket(key, true)
+}
+
+// DeleteNestedBucket removes a nested bucket with the given key.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction and
+// ErrBucketNotFound if the specified bucket does not exist.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) DeleteNestedBucket(key []byte) error {
+	n, err := b.writableNode()
+	if err != nil {
+		return err
+	}
+
+	// An empty key can't name a bucket.
+	if len(key) == 0 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	i, ok := n.search(key)
+	switch {
+	case !ok:
+		return walletdb.ErrBucketNotFound
+	case n.entries[i].bucket == nil:
+		return walletdb.ErrIncompatibleValue
+	}
+
+	n.remove(i)
+	return nil
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket.
+// This includes nested buckets, in which case the value is nil, but it does not
+// include the key/value pairs within those nested buckets.
+//
+// NOTE: The values returned by this function are only valid during a
+// transaction.  Attempting to access them after a transaction has ended will
+// likely result in an access violation.
+//
+// This function is part of the walletdb.ReadBucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	if b.tx.closed {
+		return walletdb.ErrTxClosed
+	}
+
+	n := b.node()
+	if n == nil {
+		return nil
+	}
+
+	for _, e := range n.entries {
+		if err := fn(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Put saves the specified key/value pair to the bucket.  Keys that do not
+// already exist are added and keys that already exist are overwritten.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	n, err := b.writableNode()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(key) == 0:
+		return walletdb.ErrKeyRequired
+	case len(key) > maxKeySize:
+		return walletdb.ErrKeyTooLarge
+	case len(value) > maxValueSize:
+		return walletdb.ErrValueTooLarge
+	}
+
+	i, ok := n.search(key)
+	switch {
+	case ok && n.entries[i].bucket != nil:
+		return walletdb.ErrIncompatibleValue
+	case ok:
+		n.entries[i].value = cloneBytes(value)
+	default:
+		n.insert(i, entry{
+			key:   cloneBytes(key),
+			value: cloneBytes(value),
+		})
+	}
+	return nil
+}
+
+// Get returns the value for the given key.  Returns nil if the key does
+// not exist in this bucket (or nested buckets).
+//
+// NOTE: The value returned by this function is only valid during a
+// transaction.  Attempting to access it after a transaction has ended
+// will likely result in an access violation.
+//
+// This function is part of the walletdb.ReadBucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	n := b.node()
+	if n == nil {
+		return nil
+	}
+
+	i, ok := n.search(key)
+	if !ok || n.entries[i].bucket != nil {
+		return nil
+	}
+	return n.entries[i].value
+}
+
+// Delete removes the specified key from the bucket.  Deleting a key that does
+// not exist does not return an error.  Returns ErrTxNotWritable if attempted
+// against a read-only transaction.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	n, err := b.writableNode()
+	if err != nil {
+		return err
+	}
+
+	i, ok := n.search(key)
+	switch {
+	case !ok:
+		return nil
+	case n.entries[i].bucket != nil:
+		return walletdb.ErrIncompatibleValue
+	}
+
+	n.remove(i)
+	return nil
+}
+
+func (b *bucket) ReadCursor() walletdb.ReadCursor {
+	return b.ReadWriteCursor()
+}
+
+// ReadWriteCursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	return &cursor{bucket: b, index: -1}
+}
+
+// Tx returns the bucket's transaction.
+//
+// This function is part of the walletdb.ReadWriteBucket interface implementation.
+func (b *bucket) Tx() walletdb.ReadWriteTx {
+	return b.tx
+}
+
+// NextSequence returns an autoincrementing integer for the bucket.
+func (b *bucket) NextSequence() (uint64, error) {
+	n, err := b.writableNode()
+	if err != nil {
+		return 0, err
+	}
+
+	n.sequence++
+	return n.sequence, nil
+}
+
+// SetSequence updates the sequence number for the bucket.
+func (b *bucket) SetSequence(v uint64) error {
+	n, err := b.writableNode()
+	if err != nil {
+		return err
+	}
+
+	n.sequence = v
+	return nil
+}
+
+// Sequence returns the current integer for the bucket without incrementing it.
+func (b *bucket) Sequence() uint64 {
+	n := b.node()
+	if n == nil {
+		return 0
+	}
+	return n.sequence
+}
+
+// cursor represents a cursor over key/value pairs and nested buckets of a
+// bucket.
+//
+// Note that open cursors are not tracked on bucket changes and any
+// modifications to the bucket, with the exception of cursor.Delete, invalidate
+// the cursor.  After invalidation, the cursor must be repositioned, or the keys
+// and values returned may be unpredictable.
+type cursor struct {
+	bucket *bucket
+
+	// index is the position of the cursor among the entries of the bucket.
+	index int
+}
+
+// position moves the cursor to the entry at the given index and returns it, or
+// nil if the index is out of range.
+func (c *cursor) position(i int) (key, value []byte) {
+	n := c.bucket.node()
+	if n == nil {
+		return nil, nil
+	}
+
+	switch {
+	case i < 0:
+		c.index = -1
+		return nil, nil
+	case i >= len(n.entries):
+		c.index = len(n.entries)
+		return nil, nil
+	}
+
+	c.index = i
+	return n.entries[i].key, n.entries[i].value
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor.
+//
+// This function is part of the walletdb.ReadWriteCursor interface implementation.
+func (c *cursor) Delete() error {
+	n, err := c.bucket.writableNode()
+	if err != nil {
+		return err
+	}
+
+	if c.index < 0 || c.index >= len(n.entries) {
+		return nil
+	}
+	if n.entries[c.index].bucket != nil {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	// Step back so that the next entry is the one following the deleted
+	// one.
+	n.remove(c.index)
+	c.index--
+	return nil
+}
+
+// First positions the cursor at the first key/value pair and returns the pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) First() (key, value []byte) {
+	return c.position(0)
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Last() (key, value []byte) {
+	n := c.bucket.node()
+	if n == nil {
+		return nil, nil
+	}
+	return c.position(len(n.entries) - 1)
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Next() (key, value []byte) {
+	return c.position(c.index + 1)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Prev() (key, value []byte) {
+	return c.position(c.index - 1)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not exist,
+// the cursor is moved to the next key after seek. Returns the new pair.
+//
+// This function is part of the walletdb.ReadCursor interface implementation.
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	n := c.bucket.node()
+	if n == nil {
+		return nil, nil
+	}
+
+	i, _ := n.search(seek)
+	return c.position(i)
+}
+
+// db represents a collection of namespaces which are kept in memory.  It
+// implements the walletdb.DB interface.
+type db struct {
+	// writeMtx is held by the read-write transaction in progress, if any,
+	// so that they run one at a time.
+	writeMtx sync.Mutex
+
+	// mtx protects the fields below.
+	mtx sync.Mutex
+
+	// root is the root node of the last committed transaction.
+	root *node
+
+	// lastTxID is the ID of the last read-write transaction.
+	lastTxID uint64
+
+	closed bool
+}
+
+// Enforce db implements the walletdb.DB interface.
+var _ walletdb.DB = (*db)(nil)
+
+// newDB returns a new empty database.
+func newDB() *db {
+	return &db{root: &node{}}
+}
+
+func (db *db) beginTx(writable bool) (*transaction, error) {
+	// Wait for the read-write transaction in progress to finish.
+	if writable {
+		db.writeMtx.Lock()
+	}
+
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	if db.closed {
+		if writable {
+			db.writeMtx.Unlock()
+		}
+		return nil, walletdb.ErrDbNotOpen
+	}
+
+	tx := &transaction{
+		db:       db,
+		root:     db.root,
+		writable: writable,
+	}
+	if writable {
+		db.lastTxID++
+		tx.id = db.lastTxID
+	}
+	return tx, nil
+}
+
+func (db *db) BeginReadTx() (walletdb.ReadTx, error) {
+	return db.beginTx(false)
+}
+
+func (db *db) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	return db.beginTx(true)
+}
+
+// Copy writes a copy of the database to the provided writer, in the format of
+// the bdb driver.  The pages of the bbolt file are written straight to the
+// writer, so the contents of the database never reach any other file.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	tx, err := db.beginTx(false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	return writeBolt(w, tx.root)
+}
+
+// Close releases the contents of the database, which can't be used anymore.
+// It waits for the read-write transaction in progress, if any, to finish.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Close() error {
+	db.writeMtx.Lock()
+	defer db.writeMtx.Unlock()
+
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	db.closed = true
+	db.root = nil
+	return nil
+}
+
+// Batch runs the function in a read-write transaction.  Since writes to memory
+// are cheap, there's nothing to gain from combining the transactions of
+// several callers.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Batch(f func(tx walletdb.ReadWriteTx) error) error {
+	return db.Update(f, func() {})
+}
+
+// View opens a database read transaction and executes the function f with the
+// transaction passed as a parameter. After f exits, the transaction is rolled
+// back. If f errors, its error is returned, not a rollback error (if any
+// occur). The passed reset function is called before the start of the
+// transaction and can be used to reset intermediate state. As callers may
+// expect retries of the f closure (depending on the database backend used), the
+// reset function will be called before each retry respectively.
+func (db *db) View(f func(tx walletdb.ReadTx) error, reset func()) error {
+	// We don't do any retries so we just initially call the reset function
+	// once.
+	reset()
+
+	tx, err := db.BeginReadTx()
+	if err != nil {
+		return err
+	}
+
+	// Make sure the transaction rolls back in the event of a panic.
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = f(tx)
+	rollbackErr := tx.Rollback()
+	if err != nil {
+		return err
+	}
+
+	if rollbackErr != nil {
+		return rollbackErr
+	}
+	return nil
+}
+
+// Update opens a database read/write transaction and executes the function f
+// with the transaction passed as a parameter. After f exits, if f did not
+// error, the transaction is committed. Otherwise, if f did error, the
+// transaction is rolled back. If the rollback fails, the original error
+// returned by f is still returned. If the commit fails, the commit error is
+// returned. As callers may expect retries of the f closure (depending on the
+// database backend used), the reset function will be called before each retry
+// respectively.
+func (db *db) Update(f func(tx walletdb.ReadWriteTx) error, reset func()) error {
+	// We don't do any retries so we just initially call the reset function
+	// once.
+	reset()
+
+	tx, err := db.BeginReadWriteTx()
+	if err != nil {
+		return err
+	}
+
+	// Make sure the transaction rolls back in the event of a panic.
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = f(tx)
+	if err != nil {
+		// Want to return the original error, not a rollback error if
+		// any occur.
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PrintStats returns all collected stats pretty printed into a string.
+func (db *db) PrintStats() string {
+	return "<no stats are collected by memdb backend>"
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package memdb implements an instance of walletdb that keeps the database in
+memory, for tests and ephemeral wallets that shouldn't touch the disk.
+
+# Usage
+
+This package is only a driver to the walletdb package and provides the database
+type of "memdb". The Create function takes no parameters and returns a new empty
+database, which is discarded once it is closed:
+
+	db, err := walletdb.Create("memdb")
+	if err != nil {
+		// Handle error
+	}
+
+Since an in-memory database can't be reopened, Open always returns
+walletdb.ErrDbDoesNotExist. The contents of a database can still be saved with
+Copy, which writes them in the format of the bdb driver.
+
+# Transactions
+
+Like with the bdb driver, any number of read-only transactions can run
+concurrently with at most one read-write transaction, and each transaction sees
+the database as it was when the transaction began. The buckets are copied on
+write, so a read-write transaction only copies the buckets it modifies, and
+rolling it back simply discards those copies.
+*/
+package memdb
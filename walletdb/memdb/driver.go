@@ -0,0 +1,59 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	dbType = "memdb"
+)
+
+// parseArgs parses the arguments from the walletdb Open/Create methods.
+func parseArgs(funcName string, args ...interface{}) error {
+	if len(args) != 0 {
+		return fmt.Errorf("invalid arguments to %s.%s -- expected no "+
+			"arguments", dbType, funcName)
+	}
+
+	return nil
+}
+
+// openDBDriver is the callback provided during driver registration that opens
+// an existing database for use. An in-memory database only lives as long as the
+// instance returned when creating it, so there's never one to open.
+func openDBDriver(args ...interface{}) (walletdb.DB, error) {
+	if err := parseArgs("Open", args...); err != nil {
+		return nil, err
+	}
+
+	return nil, walletdb.ErrDbDoesNotExist
+}
+
+// createDBDriver is the callback provided during driver registration that
+// creates, initializes, and opens a database for use.
+func createDBDriver(args ...interface{}) (walletdb.DB, error) {
+	if err := parseArgs("Create", args...); err != nil {
+		return nil, err
+	}
+
+	return newDB(), nil
+}
+
+func init() {
+	// Register the driver.
+	driver := walletdb.Driver{
+		DbType: dbType,
+		Create: createDBDriver,
+		Open:   openDBDriver,
+	}
+	if err := walletdb.RegisterDriver(driver); err != nil {
+		panic(fmt.Sprintf("Failed to regiser database driver '%s': %v",
+			dbType, err))
+	}
+}
@@ -0,0 +1,387 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package memdb_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/memdb"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// dbType is the database type name for this driver.
+	dbType = "memdb"
+)
+
+var (
+	bucketKey = []byte("bucket")
+	nestedKey = []byte("nested")
+)
+
+// TestCreateOpenFail ensures that errors related to creating and opening a
+// database are handled properly.
+func TestCreateOpenFail(t *testing.T) {
+	// Ensure that attempting to open a database returns the expected
+	// error.
+	wantErr := walletdb.ErrDbDoesNotExist
+	if _, err := walletdb.Open(dbType); err != wantErr {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to open or create a database with parameters
+	// returns the expected error.
+	for _, funcName := range []string{"Open", "Create"} {
+		wantErr = fmt.Errorf("invalid arguments to %s.%s -- expected "+
+			"no arguments", dbType, funcName)
+
+		var err error
+		if funcName == "Open" {
+			_, err = walletdb.Open(dbType, "db")
+		} else {
+			_, err = walletdb.Create(dbType, "db")
+		}
+		if err == nil || err.Error() != wantErr.Error() {
+			t.Errorf("%s: did not receive expected error - got %v, "+
+				"want %v", funcName, err, wantErr)
+			return
+		}
+	}
+
+	// Ensure operations against a closed database return the expected
+	// error.
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Errorf("Create: unexpected error: %v", err)
+		return
+	}
+	if err := db.Close(); err != nil {
+		t.Errorf("Close: unexpected error: %v", err)
+		return
+	}
+
+	wantErr = walletdb.ErrDbNotOpen
+	if _, err := db.BeginReadTx(); err != wantErr {
+		t.Errorf("BeginReadTx: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+	if _, err := db.BeginReadWriteTx(); err != wantErr {
+		t.Errorf("BeginReadWriteTx: did not receive expected error - "+
+			"got %v, want %v", err, wantErr)
+		return
+	}
+}
+
+// TestIsolation ensures that read transactions keep seeing the database as it
+// was when they began, and that rolled back changes to nested buckets are
+// discarded.
+func TestIsolation(t *testing.T) {
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		nested, err := b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		return nested.Put([]byte("key"), []byte("old"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	get := func(tx walletdb.ReadTx) []byte {
+		b := tx.ReadBucket(bucketKey).NestedReadBucket(nestedKey)
+		return b.Get([]byte("key"))
+	}
+
+	readTx, err := db.BeginReadTx()
+	if err != nil {
+		t.Fatalf("BeginReadTx: unexpected error: %v", err)
+	}
+	defer readTx.Rollback()
+
+	// Changes that are rolled back are never seen.
+	writeTx, err := db.BeginReadWriteTx()
+	if err != nil {
+		t.Fatalf("BeginReadWriteTx: unexpected error: %v", err)
+	}
+	nested := writeTx.ReadWriteBucket(bucketKey).
+		NestedReadWriteBucket(nestedKey)
+	if err := nested.Put([]byte("key"), []byte("new")); err != nil {
+		t.Fatalf("Put: unexpected error: %v", err)
+	}
+	if v := get(writeTx); !bytes.Equal(v, []byte("new")) {
+		t.Fatalf("Get: unexpected value in write tx: %q", v)
+	}
+	if v := get(readTx); !bytes.Equal(v, []byte("old")) {
+		t.Fatalf("Get: unexpected value in read tx: %q", v)
+	}
+	if err := writeTx.Rollback(); err != nil {
+		t.Fatalf("Rollback: unexpected error: %v", err)
+	}
+
+	// Committed changes are only seen by the transactions beginning
+	// afterwards.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		nested := tx.ReadWriteBucket(bucketKey).
+			NestedReadWriteBucket(nestedKey)
+		if v := get(tx); !bytes.Equal(v, []byte("old")) {
+			return fmt.Errorf("unexpected value after rollback: %q", v)
+		}
+		if err := nested.Put([]byte("key"), []byte("new")); err != nil {
+			return err
+		}
+		return tx.ReadWriteBucket(bucketKey).DeleteNestedBucket(
+			[]byte("missing"),
+		)
+	})
+	if err != walletdb.ErrBucketNotFound {
+		t.Fatalf("Update: did not receive expected error - got %v, "+
+			"want %v", err, walletdb.ErrBucketNotFound)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		nested := tx.ReadWriteBucket(bucketKey).
+			NestedReadWriteBucket(nestedKey)
+		return nested.Put([]byte("key"), []byte("new"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	if v := get(readTx); !bytes.Equal(v, []byte("old")) {
+		t.Fatalf("Get: unexpected value in read tx: %q", v)
+	}
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		if v := get(tx); !bytes.Equal(v, []byte("new")) {
+			return fmt.Errorf("unexpected value: %q", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: unexpected error: %v", err)
+	}
+}
+
+// TestCursorDelete ensures that deleting the current pair of a cursor moves it
+// to the pair following it.
+func TestCursorDelete(t *testing.T) {
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		for i := byte(0); i < 10; i++ {
+			if err := b.Put([]byte{i}, []byte{i}); err != nil {
+				return err
+			}
+		}
+
+		// Delete the even keys.
+		c := b.ReadWriteCursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if k[0]%2 != 0 {
+				continue
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+
+		var keys []byte
+		for k, _ := c.Last(); k != nil; k, _ = c.Prev() {
+			keys = append(keys, k[0])
+		}
+		if !bytes.Equal(keys, []byte{9, 7, 5, 3, 1}) {
+			return fmt.Errorf("unexpected keys: %v", keys)
+		}
+
+		k, _ := c.Seek([]byte{4})
+		if !bytes.Equal(k, []byte{5}) {
+			return fmt.Errorf("unexpected seek key: %v", k)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+}
+
+// TestCopy ensures that a copy of the database can be opened with the bdb
+// driver, that it holds the same contents, spread over as many pages as
+// needed, and that no temporary file is written on the way.
+func TestCopy(t *testing.T) {
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	// Enough keys for several levels of pages, and a value which
+	// overflows its page.
+	const numKeys = 20000
+	largeValue := bytes.Repeat([]byte{0xaa}, 3*os.Getpagesize())
+	manyKey := []byte("many")
+	emptyKey := []byte("empty")
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		b, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte("key"), []byte("value")); err != nil {
+			return err
+		}
+		if err := b.Put([]byte("large"), largeValue); err != nil {
+			return err
+		}
+		nested, err := b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		if _, err := nested.CreateBucket(emptyKey); err != nil {
+			return err
+		}
+		if err := nested.SetSequence(42); err != nil {
+			return err
+		}
+
+		many, err := tx.CreateTopLevelBucket(manyKey)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < numKeys; i++ {
+			key := []byte(fmt.Sprintf("key%08d", i))
+			if err := many.Put(key, bytes.Repeat(key, 4)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	// Any temporary file would be created in the empty directory.
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+	f, err := os.Create(dbPath)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	if err := db.Copy(f); err != nil {
+		t.Fatalf("Copy: unexpected error: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+
+	if entries, err := os.ReadDir(tempDir); err != nil {
+		t.Fatalf("ReadDir: unexpected error: %v", err)
+	} else if len(entries) != 0 {
+		t.Fatalf("Copy left %d temporary files", len(entries))
+	}
+
+	// The copy must pass the consistency checks of bbolt.
+	checkDB, err := bbolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	err = checkDB.View(func(tx *bbolt.Tx) error {
+		var checkErr error
+		for err := range tx.Check() {
+			if checkErr == nil {
+				checkErr = err
+			}
+		}
+		return checkErr
+	})
+	if err := checkDB.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("Check: unexpected error: %v", err)
+	}
+
+	boltDB, err := walletdb.Open("bdb", dbPath, true, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	defer boltDB.Close()
+
+	err = walletdb.Update(boltDB, func(tx walletdb.ReadWriteTx) error {
+		b := tx.ReadWriteBucket(bucketKey)
+		if b == nil {
+			return fmt.Errorf("bucket not copied")
+		}
+		if v := b.Get([]byte("key")); !bytes.Equal(v, []byte("value")) {
+			return fmt.Errorf("unexpected value: %q", v)
+		}
+		if v := b.Get([]byte("large")); !bytes.Equal(v, largeValue) {
+			return fmt.Errorf("large value not copied")
+		}
+		nested := b.NestedReadWriteBucket(nestedKey)
+		if nested == nil || nested.Sequence() != 42 {
+			return fmt.Errorf("nested bucket not copied")
+		}
+		if nested.NestedReadBucket(emptyKey) == nil {
+			return fmt.Errorf("empty bucket not copied")
+		}
+
+		many := tx.ReadWriteBucket(manyKey)
+		if many == nil {
+			return fmt.Errorf("bucket with many keys not copied")
+		}
+		n := 0
+		err := many.ForEach(func(k, v []byte) error {
+			key := []byte(fmt.Sprintf("key%08d", n))
+			if !bytes.Equal(k, key) ||
+				!bytes.Equal(v, bytes.Repeat(key, 4)) {
+
+				return fmt.Errorf("unexpected key %q", k)
+			}
+			n++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if n != numKeys {
+			return fmt.Errorf("copied %d keys, want %d", n, numKeys)
+		}
+
+		// The copy must remain writable.
+		return many.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
@@ -39,69 +38,18 @@ func networkDir(dataDir string, chainParams *chaincfg.Params) string {
 	return filepath.Join(dataDir, netname)
 }
 
-// convertLegacyKeystore converts all of the addresses in the passed legacy
-// key store to the new waddrmgr.Manager format.  Both the legacy keystore and
-// the new manager must be unlocked.
-func convertLegacyKeystore(legacyKeyStore *keystore.Store, w *wallet.Wallet) {
-	netParams := legacyKeyStore.Net()
-	blockStamp := waddrmgr.BlockStamp{
-		Height: 0,
-		Hash:   *netParams.GenesisHash,
-	}
-	for _, walletAddr := range legacyKeyStore.ActiveAddresses() {
-		switch addr := walletAddr.(type) {
-		case keystore.PubKeyAddress:
-			privKey, err := addr.PrivKey()
-			if err != nil {
-				fmt.Printf("WARN: Failed to obtain private key "+
-					"for address %v: %v\n", addr.Address(),
-					err)
-				continue
-			}
-
-			wif, err := btcutil.NewWIF(
-				privKey, netParams, addr.Compressed(),
-			)
-			if err != nil {
-				fmt.Printf("WARN: Failed to create wallet "+
-					"import format for address %v: %v\n",
-					addr.Address(), err)
-				continue
-			}
-
-			_, err = w.ImportPrivateKey(waddrmgr.KeyScopeBIP0044,
-				wif, &blockStamp, false)
-			if err != nil {
-				fmt.Printf("WARN: Failed to import private "+
-					"key for address %v: %v\n",
-					addr.Address(), err)
-				continue
-			}
-
-		case keystore.ScriptAddress:
-			_, err := w.ImportP2SHRedeemScript(addr.Script())
-			if err != nil {
-				fmt.Printf("WARN: Failed to import "+
-					"pay-to-script-hash script for "+
-					"address %v: %v\n", addr.Address(), err)
-				continue
-			}
-
-		default:
-			fmt.Printf("WARN: Skipping unrecognized legacy "+
-				"keystore type: %T\n", addr)
-			continue
-		}
-	}
-}
-
 // createWallet prompts the user for information needed to generate a new wallet
 // and generates the wallet accordingly.  The new wallet will reside at the
 // provided path.
 func createWallet(cfg *config) error {
 	dbDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
+	var loaderOpts []wallet.LoaderOption
+	if cfg.EncryptDB {
+		loaderOpts = append(loaderOpts, wallet.WithEncryptedDB())
+	}
 	loader := wallet.NewLoader(
 		activeNet.Params, dbDir, true, cfg.DBTimeout, 250,
+		loaderOpts...,
 	)
 
 	// When there is a legacy keystore, open it now to ensure any errors
@@ -159,9 +107,27 @@ func createWallet(cfg *config) error {
 				return
 			}
 
-			convertLegacyKeystore(legacyKeyStore, w)
+			report, err := w.ImportLegacyKeystore(
+				legacyKeyStore, waddrmgr.KeyScopeBIP0044, false,
+			)
+			if err != nil {
+				fmt.Printf("ERR: Failed to import old wallet "+
+					"keys: %v\n", err)
+				return
+			}
+			failed := report.Failed()
+			for _, addr := range failed {
+				fmt.Printf("WARN: Failed to import address "+
+					"%v: %v\n", addr.Address, addr.Err)
+			}
 
-			// Remove the legacy key store.
+			// Remove the legacy key store once all of its addresses
+			// are imported, or leave it for migratekeystore.
+			if len(failed) != 0 {
+				fmt.Printf("WARN: Keeping legacy wallet '%s'\n",
+					keystorePath)
+				return
+			}
 			err = os.Remove(keystorePath)
 			if err != nil {
 				fmt.Printf("WARN: Failed to remove legacy wallet "+
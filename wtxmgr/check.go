@@ -0,0 +1,542 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wtxmgr
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// checkStore houses the primary records of the store, which the derived
+// records are checked against and rebuilt from.  The records are read
+// directly, so that problems are reported instead of failing the store's own
+// lookups.
+type checkStore struct {
+	// txs maps the keys of the mined transaction records to their
+	// transactions.
+	txs map[string]*wire.MsgTx
+
+	// unmined maps the hashes of the unmined transactions to their
+	// transactions.
+	unmined map[chainhash.Hash]*wire.MsgTx
+
+	// debits maps the keys of the credits spent by mined debits to the
+	// keys of the debits.
+	debits map[string][]byte
+
+	problems []string
+}
+
+// problemf records a problem found in the store.
+func (s *checkStore) problemf(format string, args ...interface{}) {
+	s.problems = append(s.problems, fmt.Sprintf(format, args...))
+}
+
+// readCheckTx deserializes the transaction of a transaction record, and
+// checks that it matches the hash the record is keyed by.
+func readCheckTx(hash []byte, v []byte) (*wire.MsgTx, error) {
+	var txHash chainhash.Hash
+	copy(txHash[:], hash)
+
+	var rec TxRecord
+	if err := readRawTxRecord(&txHash, v, &rec); err != nil {
+		return nil, err
+	}
+	if recHash := rec.MsgTx.TxHash(); recHash != txHash {
+		return nil, fmt.Errorf("transaction hash is %v", recHash)
+	}
+	return &rec.MsgTx, nil
+}
+
+// rawOutPoint returns the outpoint of a transaction hash and output index read
+// from a key.
+func rawOutPoint(hash []byte, index uint32) *wire.OutPoint {
+	var h chainhash.Hash
+	copy(h[:], hash)
+	return wire.NewOutPoint(&h, index)
+}
+
+// creditUnspentKey returns the key of the unspent output of a credit key.
+func creditUnspentKey(k []byte) []byte {
+	op := rawOutPoint(k[:32], extractRawCreditIndex(k))
+	return canonicalOutPoint(&op.Hash, op.Index)
+}
+
+// loadCheckStore reads the transaction records and debits of the store.
+// Records which can't be deserialized are reported as problems and left out.
+func loadCheckStore(ns walletdb.ReadBucket) (*checkStore, error) {
+	s := &checkStore{
+		txs:     make(map[string]*wire.MsgTx),
+		unmined: make(map[chainhash.Hash]*wire.MsgTx),
+		debits:  make(map[string][]byte),
+	}
+
+	for _, name := range [][]byte{
+		bucketBlocks, bucketTxRecords, bucketCredits, bucketUnspent,
+		bucketDebits, bucketUnmined, bucketUnminedCredits,
+		bucketUnminedInputs,
+	} {
+		if ns.NestedReadBucket(name) == nil {
+			str := fmt.Sprintf("missing bucket %q", name)
+			return nil, storeError(ErrData, str, nil)
+		}
+	}
+
+	err := ns.NestedReadBucket(bucketTxRecords).ForEach(func(k,
+		v []byte) error {
+
+		if len(k) != 68 {
+			s.problemf("malformed transaction record key %x", k)
+			return nil
+		}
+		tx, err := readCheckTx(k[:32], v)
+		if err != nil {
+			s.problemf("transaction record %x: %v", k, err)
+			return nil
+		}
+		s.txs[string(k)] = tx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ns.NestedReadBucket(bucketUnmined).ForEach(func(k,
+		v []byte) error {
+
+		if len(k) != 32 {
+			s.problemf("malformed unmined transaction key %x", k)
+			return nil
+		}
+		tx, err := readCheckTx(k, v)
+		if err != nil {
+			s.problemf("unmined transaction %x: %v", k, err)
+			return nil
+		}
+		s.unmined[tx.TxHash()] = tx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ns.NestedReadBucket(bucketDebits).ForEach(func(k, v []byte) error {
+		if len(k) != 72 || len(v) != 80 {
+			s.problemf("malformed debit %x", k)
+			return nil
+		}
+		tx, ok := s.txs[string(k[:68])]
+		if !ok {
+			s.problemf("debit %x of unknown transaction", k)
+			return nil
+		}
+		index := byteOrder.Uint32(k[68:72])
+		if index >= uint32(len(tx.TxIn)) {
+			s.problemf("debit %x of unknown input", k)
+			return nil
+		}
+
+		ck := extractRawDebitCreditKey(v)
+		cv := existsRawCredit(ns, ck)
+		if cv == nil {
+			s.problemf("debit %x spends unknown credit %x", k, ck)
+			return nil
+		}
+		prevOut := tx.TxIn[index].PreviousOutPoint
+		if !bytes.Equal(prevOut.Hash[:], ck[:32]) ||
+			prevOut.Index != extractRawCreditIndex(ck) {
+
+			s.problemf("debit %x spends credit %x instead of %v", k,
+				ck, prevOut)
+			return nil
+		}
+		if len(cv) >= 9 && !bytes.Equal(cv[:8], v[:8]) {
+			s.problemf("debit %x amount doesn't match its credit", k)
+		}
+		if prev, ok := s.debits[string(ck)]; ok {
+			s.problemf("credit %x spent by debits %x and %x", ck,
+				prev, k)
+			return nil
+		}
+		s.debits[string(ck)] = append([]byte(nil), k...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// CheckStore checks that the records of the transaction store stored in the
+// given namespace agree with each other, without opening the store: the block
+// records list the mined transactions, the credits and debits match the
+// transactions and each other, the unspent outputs and mined balance match the
+// unspent credits, and the unmined credits and inputs match the unmined
+// transactions.  A description of each problem found is returned.
+func CheckStore(ns walletdb.ReadBucket) ([]string, error) {
+	s, err := loadCheckStore(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each mined transaction record must be listed by the record of its
+	// block, and the block records may only list known transactions.
+	listed := make(map[string]bool)
+	err = ns.NestedReadBucket(bucketBlocks).ForEach(func(k, v []byte) error {
+		var block blockRecord
+		if len(k) != 4 || readRawBlockRecord(k, v, &block) != nil ||
+			len(v) != 44+32*len(block.transactions) {
+
+			s.problemf("malformed block record %x", k)
+			return nil
+		}
+		for i := range block.transactions {
+			txKey := keyTxRecord(&block.transactions[i], &block.Block)
+			if _, ok := s.txs[string(txKey)]; !ok {
+				s.problemf("block %d lists unknown transaction "+
+					"%v", block.Height, block.transactions[i])
+				continue
+			}
+			listed[string(txKey)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for txKey := range s.txs {
+		if !listed[txKey] {
+			s.problemf("transaction %x missing from the record of "+
+				"block %d", txKey[:32],
+				byteOrder.Uint32([]byte(txKey[32:36])))
+		}
+	}
+
+	// Each credit must match the output of its transaction, and be either
+	// spent by a mined debit or unspent.  The mined balance is the sum of
+	// the unspent credits.
+	var balance btcutil.Amount
+	err = ns.NestedReadBucket(bucketCredits).ForEach(func(k, v []byte) error {
+		if len(k) != 72 || (len(v) != 9 && len(v) != 81) {
+			s.problemf("malformed credit %x", k)
+			return nil
+		}
+		index := extractRawCreditIndex(k)
+		op := rawOutPoint(k[:32], index)
+		amount, spent, _ := fetchRawCreditAmountSpent(v)
+
+		tx, ok := s.txs[string(extractRawCreditTxRecordKey(k))]
+		switch {
+		case !ok:
+			s.problemf("credit %v of unknown transaction", op)
+		case index >= uint32(len(tx.TxOut)):
+			s.problemf("credit %v of unknown output", op)
+		case btcutil.Amount(tx.TxOut[index].Value) != amount:
+			s.problemf("credit %v amount %d doesn't match its "+
+				"output", op, amount)
+		}
+
+		debit, debited := s.debits[string(k)]
+		inUnspent := bytes.Equal(
+			existsRawUnspent(ns, creditUnspentKey(k)), k,
+		)
+		switch {
+		case spent && !debited:
+			s.problemf("credit %v marked spent without a debit", op)
+		case spent && (len(v) != 81 || !bytes.Equal(v[9:], debit)):
+			s.problemf("credit %v doesn't refer to its debit", op)
+		case !spent && debited:
+			s.problemf("credit %v spent by debit %x isn't "+
+				"marked spent", op, debit)
+		}
+		switch {
+		case spent && inUnspent:
+			s.problemf("spent credit %v is unspent", op)
+		case !spent && !inUnspent:
+			s.problemf("unspent credit %v missing from the "+
+				"unspent outputs", op)
+		}
+		if !spent {
+			balance += amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ns.NestedReadBucket(bucketUnspent).ForEach(func(k, v []byte) error {
+		if len(k) != 36 || len(v) != 36 {
+			s.problemf("malformed unspent output %x", k)
+			return nil
+		}
+		credKey := existsRawUnspent(ns, k)
+		if credKey == nil || existsRawCredit(ns, credKey) == nil {
+			s.problemf("unspent output %v has no credit",
+				rawOutPoint(k[:32], byteOrder.Uint32(k[32:])))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if minedBalance, err := fetchMinedBalance(ns); err != nil {
+		s.problemf("malformed mined balance")
+	} else if minedBalance != balance {
+		s.problemf("mined balance %d doesn't match the unspent "+
+			"credits total %d", minedBalance, balance)
+	}
+
+	// The unmined credits must match the outputs of unmined transactions.
+	err = ns.NestedReadBucket(bucketUnminedCredits).ForEach(func(k,
+		v []byte) error {
+
+		if len(k) != 36 || len(v) != 9 {
+			s.problemf("malformed unmined credit %x", k)
+			return nil
+		}
+		var op wire.OutPoint
+		_ = readCanonicalOutPoint(k, &op)
+		amount, _ := fetchRawUnminedCreditAmount(v)
+
+		tx, ok := s.unmined[op.Hash]
+		switch {
+		case !ok:
+			s.problemf("unmined credit %v of unknown transaction",
+				op)
+		case op.Index >= uint32(len(tx.TxOut)):
+			s.problemf("unmined credit %v of unknown output", op)
+		case btcutil.Amount(tx.TxOut[op.Index].Value) != amount:
+			s.problemf("unmined credit %v amount doesn't match its "+
+				"output", op)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The unmined inputs index the spenders of the outpoints spent by
+	// unmined transactions.
+	spenders := unminedSpenders(s.unmined)
+	indexed := make(map[string]bool)
+	err = ns.NestedReadBucket(bucketUnminedInputs).ForEach(func(k,
+		v []byte) error {
+
+		var op wire.OutPoint
+		if len(k) != 36 || len(v)%32 != 0 ||
+			readCanonicalOutPoint(k, &op) != nil {
+
+			s.problemf("malformed unmined input %x", k)
+			return nil
+		}
+		for ; len(v) != 0; v = v[32:] {
+			if !bytes.Contains(spenders[string(k)], v[:32]) {
+				s.problemf("unmined input %v lists %x, which "+
+					"doesn't spend it", op, v[:32])
+				continue
+			}
+			indexed[string(k)+string(v[:32])] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for k, hashes := range spenders {
+		var op wire.OutPoint
+		_ = readCanonicalOutPoint([]byte(k), &op)
+		for ; len(hashes) != 0; hashes = hashes[32:] {
+			if !indexed[k+string(hashes[:32])] {
+				s.problemf("unmined transaction %x missing "+
+					"from the unmined inputs of %v",
+					hashes[:32], op)
+			}
+		}
+	}
+
+	sort.Strings(s.problems)
+	return s.problems, nil
+}
+
+// unminedSpenders returns the concatenated hashes of the unmined transactions
+// spending each outpoint, keyed by the canonical outpoint.
+func unminedSpenders(unmined map[chainhash.Hash]*wire.MsgTx) map[string][]byte {
+	hashes := make([]chainhash.Hash, 0, len(unmined))
+	for hash := range unmined {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+
+	spenders := make(map[string][]byte)
+	for _, hash := range hashes {
+		for _, in := range unmined[hash].TxIn {
+			prevOut := &in.PreviousOutPoint
+			k := string(canonicalOutPoint(&prevOut.Hash, prevOut.Index))
+			spenders[k] = append(spenders[k], hash[:]...)
+		}
+	}
+	return spenders
+}
+
+// recreateBucket empties a nested bucket of the namespace.
+func recreateBucket(ns walletdb.ReadWriteBucket, name []byte) error {
+	if err := ns.DeleteNestedBucket(name); err != nil {
+		return err
+	}
+	_, err := ns.CreateBucket(name)
+	return err
+}
+
+// RepairStore rebuilds the records of the transaction store stored in the
+// given namespace that are derived from other records: the spent flags of the
+// credits from the mined debits, the unspent outputs and mined balance from
+// the credits, the unmined inputs from the unmined transactions and the
+// transaction lists of the block records from the mined transaction records.
+// Problems with the other records can't be repaired, and are reported again
+// by CheckStore.
+func RepairStore(ns walletdb.ReadWriteBucket) error {
+	s, err := loadCheckStore(ns)
+	if err != nil {
+		return err
+	}
+
+	// Mark the credits spent by mined debits, and only those, as spent.
+	credits := ns.NestedReadWriteBucket(bucketCredits)
+	var keys, values [][]byte
+	err = credits.ForEach(func(k, v []byte) error {
+		if len(k) != 72 || len(v) < 9 {
+			return nil
+		}
+		cv := append([]byte(nil), v[:9]...)
+		if debit, ok := s.debits[string(k)]; ok {
+			cv[8] |= 1 << 0
+			cv = append(cv, debit...)
+		} else {
+			cv[8] &^= 1 << 0
+		}
+		if !bytes.Equal(cv, v) {
+			keys = append(keys, append([]byte(nil), k...))
+			values = append(values, cv)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := range keys {
+		if err := putRawCredit(ns, keys[i], values[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := recreateBucket(ns, bucketUnspent); err != nil {
+		return err
+	}
+	var balance btcutil.Amount
+	err = credits.ForEach(func(k, v []byte) error {
+		if len(k) != 72 {
+			return nil
+		}
+		amount, spent, err := fetchRawCreditAmountSpent(v)
+		if err != nil || spent {
+			return nil
+		}
+		balance += amount
+		unspentValue, _ := fetchRawCreditUnspentValue(k)
+		return putRawUnspent(ns, creditUnspentKey(k), unspentValue)
+	})
+	if err != nil {
+		return err
+	}
+	if err := putMinedBalance(ns, balance); err != nil {
+		return err
+	}
+
+	if err := recreateBucket(ns, bucketUnminedInputs); err != nil {
+		return err
+	}
+	for k, hashes := range unminedSpenders(s.unmined) {
+		if err := putRawUnminedInput(ns, []byte(k), hashes); err != nil {
+			return err
+		}
+	}
+
+	return repairBlockRecords(ns, s)
+}
+
+// repairBlockRecords rebuilds the transaction lists of the block records from
+// the mined transaction records, keeping the order of the transactions which
+// were already listed.  Records left without transactions are removed, while
+// missing records can't be recreated as the block times are unknown.
+func repairBlockRecords(ns walletdb.ReadWriteBucket, s *checkStore) error {
+	blockTxs := make(map[string][][]byte)
+	for txKey := range s.txs {
+		blockKey := txKey[32:68]
+		blockTxs[blockKey] = append(blockTxs[blockKey],
+			[]byte(txKey[:32]))
+	}
+
+	blocks := ns.NestedReadWriteBucket(bucketBlocks)
+	var keys, values [][]byte
+	err := blocks.ForEach(func(k, v []byte) error {
+		if len(k) != 4 || len(v) < 44 {
+			return nil
+		}
+		txs := blockTxs[string(k)+string(v[:32])]
+		want := make(map[string]bool, len(txs))
+		for _, hash := range txs {
+			want[string(hash)] = true
+		}
+
+		bv := append([]byte(nil), v[:44]...)
+		for hashes := v[44:]; len(hashes) >= 32; hashes = hashes[32:] {
+			if want[string(hashes[:32])] {
+				bv = append(bv, hashes[:32]...)
+				delete(want, string(hashes[:32]))
+			}
+		}
+		sort.Slice(txs, func(i, j int) bool {
+			return bytes.Compare(txs[i], txs[j]) < 0
+		})
+		for _, hash := range txs {
+			if want[string(hash)] {
+				bv = append(bv, hash...)
+			}
+		}
+		byteOrder.PutUint32(bv[40:44], uint32((len(bv)-44)/32))
+
+		if !bytes.Equal(bv, v) {
+			keys = append(keys, append([]byte(nil), k...))
+			values = append(values, bv)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, k := range keys {
+		if len(values[i]) == 44 {
+			err = blocks.Delete(k)
+		} else {
+			err = putRawBlockRecord(ns, k, values[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2024 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wtxmgr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// TestCheckRepairStore corrupts the derived records of a transaction store
+// and checks that CheckStore reports the problems and RepairStore fixes them.
+func TestCheckRepairStore(t *testing.T) {
+	t.Parallel()
+
+	s, db, teardown, err := testStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	b100 := BlockMeta{
+		Block: Block{Hash: chainhash.Hash{100}, Height: 100},
+		Time:  time.Unix(1e9, 0),
+	}
+	b101 := BlockMeta{
+		Block: Block{Hash: chainhash.Hash{101}, Height: 101},
+		Time:  time.Unix(1e9+600, 0),
+	}
+
+	// Fill the store with a mined coinbase paying two credits, a mined
+	// transaction spending one of them and an unmined transaction
+	// spending the output of the latter.
+	cb := newCoinBase(20e8, 10e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, b100.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spend := spendOutput(&cbRec.Hash, 0, 15e8)
+	spendRec, err := NewTxRecordFromMsgTx(spend, b101.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmined := spendOutput(&spendRec.Hash, 0, 14e8)
+	unminedRec, err := NewTxRecordFromMsgTx(unmined, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(namespaceKey)
+		if err := s.InsertTx(ns, cbRec, &b100); err != nil {
+			return err
+		}
+		for i := uint32(0); i < 2; i++ {
+			err := s.AddCredit(ns, cbRec, &b100, i, false)
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.InsertTx(ns, spendRec, &b101); err != nil {
+			return err
+		}
+		if err := s.AddCredit(ns, spendRec, &b101, 0, false); err != nil {
+			return err
+		}
+		if err := s.InsertTx(ns, unminedRec, nil); err != nil {
+			return err
+		}
+		return s.AddCredit(ns, unminedRec, nil, 0, false)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func() []string {
+		t.Helper()
+
+		var problems []string
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			var err error
+			problems, err = CheckStore(tx.ReadBucket(namespaceKey))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return problems
+	}
+
+	if problems := check(); len(problems) != 0 {
+		t.Fatalf("unexpected problems in a consistent store: %v",
+			problems)
+	}
+
+	var minedBalance int64
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		bal, err := fetchMinedBalance(tx.ReadBucket(namespaceKey))
+		minedBalance = int64(bal)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minedBalance != 25e8 {
+		t.Fatalf("mined balance is %d, want %d", minedBalance,
+			int64(25e8))
+	}
+
+	// Corrupt each kind of derived record: drop an unspent output, clear
+	// the spent flag of a credit, drop an unmined input and replace the
+	// transaction of a block record with an unknown one.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(namespaceKey)
+		err := deleteRawUnspent(ns, canonicalOutPoint(&cbRec.Hash, 1))
+		if err != nil {
+			return err
+		}
+		_, err = unspendRawCredit(ns, keyCredit(&cbRec.Hash, 0, &b100.Block))
+		if err != nil {
+			return err
+		}
+		err = deleteRawUnminedInput(
+			ns, canonicalOutPoint(&spendRec.Hash, 0),
+			unminedRec.Hash,
+		)
+		if err != nil {
+			return err
+		}
+		return putRawBlockRecord(
+			ns, keyBlockRecord(b101.Height),
+			valueBlockRecord(&b101, &chainhash.Hash{1}),
+		)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := check()
+	wantProblems := []string{
+		"block 101 lists unknown transaction",
+		"missing from the record of block 101",
+		"isn't marked spent",
+		"missing from the unspent outputs",
+		"mined balance",
+		"missing from the unmined inputs",
+	}
+	for _, want := range wantProblems {
+		found := false
+		for _, problem := range problems {
+			if strings.Contains(problem, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no problem containing %q reported in %v",
+				want, problems)
+		}
+	}
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		return RepairStore(tx.ReadWriteBucket(namespaceKey))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := check(); len(problems) != 0 {
+		t.Fatalf("unexpected problems after the repair: %v", problems)
+	}
+
+	// The repaired store must be usable again.
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(namespaceKey)
+		bal, err := fetchMinedBalance(ns)
+		if err != nil {
+			return err
+		}
+		if bal != 25e8 {
+			t.Errorf("mined balance is %v after the repair, want "+
+				"25 BTC", bal)
+		}
+
+		details, err := s.TxDetails(ns, &spendRec.Hash)
+		if err != nil {
+			return err
+		}
+		if details == nil || details.Block.Height != b101.Height {
+			t.Errorf("mined transaction not found after the repair")
+		}
+
+		spenders := fetchUnminedInputSpendTxHashes(
+			ns, canonicalOutPoint(&spendRec.Hash, 0),
+		)
+		if len(spenders) != 1 || spenders[0] != unminedRec.Hash {
+			t.Errorf("unmined input spenders are %v, want %v",
+				spenders, unminedRec.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}